@@ -0,0 +1,78 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/beacon"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/cache"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/grafana"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/metrics"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/validator"
+	"github.com/sirupsen/logrus"
+)
+
+// runDashboard writes a Grafana dashboard of every exported metric for the configured network and labels:
+// watcher dashboard [--config config.yaml] [--network NAME] [--output FILE]
+func runDashboard(args []string) int {
+	flags := flag.NewFlagSet("dashboard", flag.ContinueOnError)
+	path := flags.String("config", "config.yaml", "Path to configuration file (network and labels)")
+	network := flags.String("network", "", "Network name (default: the config's network)")
+	title := flags.String("title", "", "Dashboard title (default: Ethereum Validator Watcher (NETWORK))")
+	output := flags.String("output", "-", "Dashboard JSON file to write, - for stdout")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg, err := loadConfig(*path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		return 1
+	}
+	if *network == "" {
+		*network = cfg.Network
+	}
+
+	// The label variable starts with every configured label selected
+	seen := map[string]bool{"scope:watched": true}
+	labels := []string{"scope:watched"}
+	for _, key := range cfg.WatchedKeys {
+		for _, label := range validator.ExpandLabels(key.Labels) {
+			if !seen[label] {
+				seen[label] = true
+				labels = append(labels, label)
+			}
+		}
+	}
+	sort.Strings(labels[1:])
+
+	// The beacon and cache collectors export series for the configured endpoints and the caches;
+	// no request is sent
+	client := beacon.NewFailoverClient(cfg.BeaconEndpoints(), cfg.BeaconTimeout.ToDuration(), logrus.New())
+	catalog, err := metrics.Catalog(append(client.Collectors(), cache.NewCollector(client.Caches()...))...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to list metrics: %v\n", err)
+		return 1
+	}
+	data, err := grafana.GenerateDashboard(catalog, grafana.DashboardOptions{Title: *title, Network: *network, Labels: labels})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to generate dashboard: %v\n", err)
+		return 1
+	}
+	data = append(data, '\n')
+
+	if *output == "-" {
+		if _, err := os.Stdout.Write(data); err != nil {
+			return 1
+		}
+		return 0
+	}
+	if err := os.WriteFile(*output, data, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write %s: %v\n", *output, err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "Wrote %s: %d metrics for %s\n", *output, len(catalog), *network)
+	return 0
+}
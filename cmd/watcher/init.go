@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/beacon"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/bootstrap"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// initTimeout bounds probing the beacon node and resolving keys, a full validator set scan included
+const initTimeout = 5 * time.Minute
+
+// runInit writes a starter config from a beacon node and a key source:
+// watcher init --beacon-url URL --keys-from keymanager|file|withdrawal-address [...]
+func runInit(args []string) int {
+	flags := flag.NewFlagSet("init", flag.ContinueOnError)
+	beaconURL := flags.String("beacon-url", "http://localhost:5052", "Beacon node API URL")
+	keysFrom := flags.String("keys-from", "", "Key source: keymanager, file or withdrawal-address")
+	keymanagerURL := flags.String("keymanager-url", "http://localhost:7500", "Validator client keymanager API URL (keymanager)")
+	keymanagerToken := flags.String("keymanager-token", "", "Keymanager API token, or a file containing it (keymanager)")
+	keysFile := flags.String("keys-file", "", "Deposit data, JSON list or one key per line (file)")
+	address := flags.String("withdrawal-address", "", "Execution address the validators withdraw to (withdrawal-address)")
+	operator := flags.String("operator", "", "Operator name, labels every key operator:NAME")
+	network := flags.String("network", "", "Network name (default: detected from the beacon node)")
+	output := flags.String("output", "config.yaml", "Config file to write, - for stdout")
+	force := flags.Bool("force", false, "Overwrite an existing config file")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	logger := setupLogger("warn", "")
+	ctx, cancel := context.WithTimeout(context.Background(), initTimeout)
+	defer cancel()
+
+	if *output != "-" && !*force {
+		if _, err := os.Stat(*output); err == nil {
+			fmt.Fprintf(os.Stderr, "%s already exists (use --force to overwrite)\n", *output)
+			return 1
+		}
+	}
+
+	// Probe the beacon node: it must answer, and it names the network
+	client := beacon.NewClient(*beaconURL, 30*time.Second, logger)
+	node, err := client.DetectNode(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Beacon node at %s not reachable: %v\n", *beaconURL, err)
+		return 1
+	}
+	genesis, err := client.GetGenesis(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to get genesis from the beacon node: %v\n", err)
+		return 1
+	}
+	if *network == "" {
+		*network = bootstrap.NetworkName(genesis.GenesisValidatorsRoot)
+		if *network == "" {
+			fmt.Fprintf(os.Stderr, "Unknown network (genesis validators root %s), set --network\n", genesis.GenesisValidatorsRoot)
+			return 1
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Beacon node: %s %s on %s\n", node.Client, node.Version, *network)
+
+	var labels []string
+	if *operator != "" {
+		labels = append(labels, "operator:"+*operator)
+	}
+
+	var keys []string
+	var source string
+	switch *keysFrom {
+	case "keymanager":
+		token, err := readToken(*keymanagerToken)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to read keymanager token: %v\n", err)
+			return 1
+		}
+		keys, err = bootstrap.KeysFromKeymanager(ctx, &http.Client{Timeout: 30 * time.Second}, *keymanagerURL, token)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to list keymanager keys: %v\n", err)
+			return 1
+		}
+		source = "keymanager " + *keymanagerURL
+		labels = append(labels, "source:keymanager")
+	case "file":
+		if *keysFile == "" {
+			fmt.Fprintln(os.Stderr, "--keys-file is required with --keys-from file")
+			return 2
+		}
+		keys, err = bootstrap.KeysFromFile(*keysFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to read keys: %v\n", err)
+			return 1
+		}
+		source = *keysFile
+		labels = append(labels, "source:file")
+	case "withdrawal-address":
+		matches, err := bootstrap.WithdrawalAddressMatcher(*address)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "--withdrawal-address: %v\n", err)
+			return 2
+		}
+		fmt.Fprintln(os.Stderr, "Scanning the validator set...")
+		_, err = client.StreamAllValidators(ctx, "head", func(v models.Validator) {
+			if matches(v) {
+				keys = append(keys, v.Data.Pubkey)
+			}
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to scan the validator set: %v\n", err)
+			return 1
+		}
+		source = "withdrawal address " + strings.ToLower(*address)
+		labels = append(labels, "withdrawal:"+strings.ToLower(*address))
+	default:
+		fmt.Fprintln(os.Stderr, "--keys-from must be keymanager, file or withdrawal-address")
+		return 2
+	}
+	if len(keys) == 0 {
+		fmt.Fprintf(os.Stderr, "No keys found in %s\n", source)
+		return 1
+	}
+
+	opts := bootstrap.Options{
+		Network:   *network,
+		BeaconURL: *beaconURL,
+		Keys:      keys,
+		Labels:    labels,
+		Source:    source,
+		Node:      fmt.Sprintf("%s %s", node.Client, node.Version),
+	}
+
+	if *output == "-" {
+		if err := bootstrap.Render(os.Stdout, opts, time.Now()); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write config: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	file, err := os.Create(*output)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create %s: %v\n", *output, err)
+		return 1
+	}
+	if err := bootstrap.Render(file, opts, time.Now()); err != nil {
+		file.Close()
+		fmt.Fprintf(os.Stderr, "Failed to write config: %v\n", err)
+		return 1
+	}
+	if err := file.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write %s: %v\n", *output, err)
+		return 1
+	}
+
+	fmt.Fprintf(os.Stderr, "Wrote %s with %d keys; start with: eth-validator-watcher -config %s\n", *output, len(keys), *output)
+	return 0
+}
+
+// readToken returns a token given inline or as the path of a file holding it
+// (validator clients write their keymanager token to a file)
+func readToken(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	if data, err := os.ReadFile(value); err == nil {
+		return strings.TrimSpace(string(data)), nil
+	} else if !os.IsNotExist(err) {
+		return "", err
+	}
+	return value, nil
+}
@@ -9,13 +9,21 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io/fs"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/enriquemanuel/eth-validator-watcher/pkg/config"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/duties"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/lint"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/logging"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/tracing"
 	"github.com/enriquemanuel/eth-validator-watcher/pkg/watcher"
 	"github.com/sirupsen/logrus"
 )
@@ -23,7 +31,12 @@ import (
 var (
 	configPath  = flag.String("config", "config.yaml", "Path to configuration file")
 	logLevel    = flag.String("log-level", "info", "Log level (debug, info, warn, error)")
+	logFormat   = flag.String("log-format", "", "Log format (text, json), overriding the config's log.format")
 	showVersion = flag.Bool("version", false, "Show version information")
+	lintConfig  = flag.Bool("lint", false, "Check the watched keys for configurations correlated with slashing risk, then exit")
+	conformance = flag.String("check-attestations", "", "Decode captured attestation fixtures (file or directory) and report participation, then exit")
+	serveFile   = flag.String("serve-snapshot", "", "Serve the API and /metrics read-only from a snapshot_file export, without a beacon node")
+	serveAddr   = flag.String("listen", ":8000", "Address the snapshot is served on (with -serve-snapshot), secured by the config's http_server")
 )
 
 const (
@@ -31,6 +44,14 @@ const (
 )
 
 func main() {
+	// Subcommands come before the flags of the watcher itself
+	if len(os.Args) > 1 && os.Args[1] == "init" {
+		os.Exit(runInit(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "dashboard" {
+		os.Exit(runDashboard(os.Args[2:]))
+	}
+
 	flag.Parse()
 
 	if *showVersion {
@@ -38,8 +59,20 @@ func main() {
 		os.Exit(0)
 	}
 
+	if *conformance != "" {
+		os.Exit(runConformance(*conformance))
+	}
+
 	// Setup logger
-	logger := setupLogger(*logLevel)
+	logger := setupLogger(*logLevel, *logFormat)
+
+	if *lintConfig {
+		os.Exit(runLint(*configPath, logger))
+	}
+
+	if *serveFile != "" {
+		os.Exit(runServeSnapshot(*serveFile, *serveAddr, *configPath, logger))
+	}
 
 	logger.WithFields(logrus.Fields{
 		"version": version,
@@ -47,16 +80,43 @@ func main() {
 	}).Info("Starting Ethereum Validator Watcher")
 
 	// Load configuration
-	cfg, err := config.LoadConfig(*configPath)
+	cfg, err := loadConfig(*configPath)
 	if err != nil {
 		logger.WithError(err).Fatal("Failed to load configuration")
 	}
+	if cfg.Path == "" {
+		logger.Info("No config file - running in zero-config mode from the environment")
+	}
+
+	logFile, err := logging.Apply(logger, cfg.Log, *logFormat)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to set up logging")
+	}
+	if logFile != nil {
+		defer logFile.Close()
+	}
+
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing, version)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to set up tracing")
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			logger.WithError(err).Warn("Failed to flush traces")
+		}
+	}()
+	if cfg.Tracing.Endpoint != "" {
+		logger.WithField("endpoint", cfg.Tracing.Endpoint).Info("Exporting traces over OTLP")
+	}
 
 	logger.WithFields(logrus.Fields{
-		"network":       cfg.Network,
-		"beacon_url":    cfg.BeaconURL,
-		"metrics_port":  cfg.MetricsPort,
-		"watched_count": len(cfg.WatchedKeys),
+		"network":          cfg.Network,
+		"beacon_url":       cfg.BeaconEndpoints()[0],
+		"beacon_endpoints": len(cfg.BeaconEndpoints()),
+		"metrics_port":     cfg.MetricsPort,
+		"watched_count":    len(cfg.WatchedKeys),
 	}).Info("Configuration loaded")
 
 	// Create watcher
@@ -78,6 +138,17 @@ func main() {
 		cancel()
 	}()
 
+	// SIGHUP reloads watched keys from the config file without a restart
+	hupChan := make(chan os.Signal, 1)
+	signal.Notify(hupChan, syscall.SIGHUP)
+
+	go func() {
+		for range hupChan {
+			logger.Info("Received SIGHUP - reloading config at the next slot")
+			w.RequestReload()
+		}
+	}()
+
 	// Run watcher
 	if err := w.Run(ctx); err != nil && err != context.Canceled {
 		logger.WithError(err).Fatal("Validator watcher failed")
@@ -86,12 +157,23 @@ func main() {
 	logger.Info("Shutdown complete")
 }
 
-func setupLogger(level string) *logrus.Logger {
+// loadConfig reads the config file, or runs in zero-config mode from ETH_WATCHER_BEACON_URL and
+// WATCHED_PUBKEYS when there is no config file
+func loadConfig(path string) (*models.Config, error) {
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) && config.ZeroConfigAvailable() {
+		return config.FromEnv()
+	}
+	return config.LoadConfig(path)
+}
+
+func setupLogger(level, format string) *logrus.Logger {
 	logger := logrus.New()
-	logger.SetFormatter(&logrus.TextFormatter{
-		FullTimestamp: true,
-		DisableColors: false,
-	})
+	formatter, err := logging.NewFormatter(format)
+	if err != nil {
+		logger.WithError(err).Warn("Invalid log format, using text")
+		formatter, _ = logging.NewFormatter(logging.FormatText)
+	}
+	logger.SetFormatter(formatter)
 
 	logLevel, err := logrus.ParseLevel(level)
 	if err != nil {
@@ -102,3 +184,67 @@ func setupLogger(level string) *logrus.Logger {
 
 	return logger
 }
+
+// runLint reports the slashing-risk findings of the watched set
+// Returns a non-zero exit code if any finding is critical
+func runLint(path string, logger *logrus.Logger) int {
+	cfg, err := loadConfig(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		return 1
+	}
+	// Lint never touches counters, and a running watcher holds the state file lock
+	cfg.StateFile = ""
+
+	w, err := watcher.NewValidatorWatcher(cfg, logger)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create validator watcher: %v\n", err)
+		return 1
+	}
+
+	findings, err := w.Lint(context.Background())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Lint failed: %v\n", err)
+		return 1
+	}
+
+	if lint.Report(os.Stdout, findings) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// runConformance decodes attestation fixtures and prints the participation sets
+// Returns a non-zero exit code if any fixture with an expected set does not match
+func runConformance(path string) int {
+	fixtures, err := duties.LoadFixtures(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load fixtures: %v\n", err)
+		return 1
+	}
+
+	exitCode := 0
+	for _, fixture := range fixtures {
+		result, err := duties.RunFixture(fixture)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "FAIL %s: %v\n", fixture.Name, err)
+			exitCode = 1
+			continue
+		}
+
+		switch {
+		case !result.HasExpected:
+			fmt.Printf("INFO %s (fork=%s, slot=%d): %d validators attested: %v\n",
+				result.Name, result.Fork, result.Slot, len(result.Attested), result.Attested)
+		case result.Passed():
+			fmt.Printf("PASS %s (fork=%s, slot=%d): %d validators attested\n",
+				result.Name, result.Fork, result.Slot, len(result.Attested))
+		default:
+			fmt.Printf("FAIL %s (fork=%s, slot=%d): missing=%v unexpected=%v\n",
+				result.Name, result.Fork, result.Slot, result.Missing, result.Unexpected)
+			exitCode = 1
+		}
+	}
+
+	return exitCode
+}
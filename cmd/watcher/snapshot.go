@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/api"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/config"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/httpserver"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/snapshot"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/sirupsen/logrus"
+)
+
+// runServeSnapshot serves the JSON API and /metrics from an exported snapshot, read-only and
+// without a beacon node; SIGHUP reloads the file
+// The http_server settings of the config file, if any, secure it like the watcher's own server
+func runServeSnapshot(path, addr, configPath string, logger *logrus.Logger) int {
+	serverCfg, err := config.LoadHTTPServer(configPath)
+	if err != nil {
+		logger.WithError(err).Error("Failed to load the http_server settings")
+		return 1
+	}
+	apiServer := api.NewServer(nil, logger)
+	var gatherer atomic.Pointer[prometheus.Gatherer]
+
+	load := func() error {
+		s, err := snapshot.Read(path)
+		if err != nil {
+			return err
+		}
+		g, err := s.Gatherer()
+		if err != nil {
+			return err
+		}
+		apiServer.Restore(s.API)
+		gatherer.Store(&g)
+
+		logger.WithFields(logrus.Fields{
+			"network":    s.Network,
+			"epoch":      s.Epoch,
+			"created_at": s.CreatedAt.Format(time.RFC3339),
+			"validators": len(s.API.Validators),
+		}).Info("Loaded snapshot")
+		return nil
+	}
+	if err := load(); err != nil {
+		logger.WithError(err).Error("Failed to load snapshot")
+		return 1
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(prometheus.GathererFunc(func() ([]*dto.MetricFamily, error) {
+		return (*gatherer.Load()).Gather()
+	}), promhttp.HandlerOpts{}))
+	apiServer.Register(mux)
+	for _, probe := range []string{"/health", "/ready", "/livez", "/readyz", "/startupz"} {
+		mux.HandleFunc(probe, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("OK"))
+		})
+	}
+	if auth := serverCfg.BasicAuth; auth.Username != "" && auth.Password == "" {
+		logger.Error("http_server.basic_auth has a username but no password - basic auth requests are rejected")
+	}
+	server := &http.Server{
+		Addr:    addr,
+		Handler: httpserver.Wrap(mux, serverCfg),
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	hupChan := make(chan os.Signal, 1)
+	signal.Notify(hupChan, syscall.SIGHUP)
+	go func() {
+		for range hupChan {
+			if err := load(); err != nil {
+				logger.WithError(err).Warn("Failed to reload snapshot - serving the previous one")
+			}
+		}
+	}()
+
+	go func() {
+		<-ctx.Done()
+		server.Shutdown(context.Background())
+	}()
+
+	logger.WithFields(logrus.Fields{"address": addr, "snapshot": path}).Info("Serving snapshot read-only")
+	if err := httpserver.ListenAndServe(server, serverCfg); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.WithError(err).Error("Snapshot server failed")
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,169 @@
+package alert
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Severity is how urgently an alert needs attention
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+// severityRanks orders the severities from least to most urgent
+var severityRanks = map[Severity]int{
+	SeverityInfo:     0,
+	SeverityWarning:  1,
+	SeverityCritical: 2,
+}
+
+// ParseSeverity validates a configured severity name
+func ParseSeverity(name string) (Severity, error) {
+	severity := Severity(name)
+	if _, ok := severityRanks[severity]; !ok {
+		return "", fmt.Errorf("unknown severity %q (info, warning or critical)", name)
+	}
+	return severity, nil
+}
+
+// AtLeast reports whether the severity is as urgent as min
+func (s Severity) AtLeast(min Severity) bool {
+	return severityRanks[s] >= severityRanks[min]
+}
+
+// Alert is a notification for the on-call channels
+type Alert struct {
+	Severity Severity
+	Title    string
+	Text     string
+	Fields   map[string]string
+	Links    map[string]string // Explorer pages of the fields naming a validator, slot, epoch or block, by field key
+	Key      string            // Identifies the condition (e.g. slashing:42), so deduplicating channels page once for it
+	Channels []string          // Only these channels (notifier names, e.g. slack), every channel if empty
+}
+
+// RoutedTo reports whether the alert goes to a channel
+// The log always gets every alert, and groups forward it to their members
+func (a Alert) RoutedTo(channel string) bool {
+	if len(a.Channels) == 0 || channel == "log" || channel == "multi" {
+		return true
+	}
+	for _, c := range a.Channels {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// SortedFields returns the alert fields as key/value pairs ordered by key
+func (a Alert) SortedFields() [][2]string {
+	keys := make([]string, 0, len(a.Fields))
+	for key := range a.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fields := make([][2]string, len(keys))
+	for i, key := range keys {
+		fields[i] = [2]string{key, a.Fields[key]}
+	}
+	return fields
+}
+
+// Notifier delivers alerts to a channel
+type Notifier interface {
+	Name() string
+	Notify(ctx context.Context, alert Alert) error
+}
+
+// Multi sends every alert to all of its notifiers the alert is routed to
+type Multi []Notifier
+
+// Name returns the notifier name used in logs
+func (m Multi) Name() string {
+	return "multi"
+}
+
+// Notify delivers the alert to each notifier, returning the joined errors of those that failed
+func (m Multi) Notify(ctx context.Context, alert Alert) error {
+	var errs []error
+	for _, notifier := range m {
+		if !alert.RoutedTo(notifier.Name()) {
+			continue
+		}
+		if err := notifier.Notify(ctx, alert); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Linker attaches explorer links to the alerts it passes on
+type Linker struct {
+	Notifier
+	links func(fields map[string]string) map[string]string
+}
+
+// NewLinker wraps a notifier so its alerts carry the links built from their fields
+func NewLinker(next Notifier, links func(fields map[string]string) map[string]string) *Linker {
+	return &Linker{Notifier: next, links: links}
+}
+
+// Notify adds links to an alert without any, then delivers it
+func (l *Linker) Notify(ctx context.Context, alert Alert) error {
+	if alert.Links == nil {
+		alert.Links = l.links(alert.Fields)
+	}
+	return l.Notifier.Notify(ctx, alert)
+}
+
+// LogNotifier writes alerts to the log, for setups without a chat channel
+type LogNotifier struct {
+	logger *logrus.Logger
+}
+
+// NewLogNotifier creates a notifier that logs alerts
+func NewLogNotifier(logger *logrus.Logger) *LogNotifier {
+	return &LogNotifier{logger: logger}
+}
+
+// Name returns the notifier name used in logs
+func (l *LogNotifier) Name() string {
+	return "log"
+}
+
+// Notify logs the alert at a level matching its severity
+func (l *LogNotifier) Notify(ctx context.Context, alert Alert) error {
+	fields := logrus.Fields{"severity": alert.Severity}
+	for key, value := range alert.Fields {
+		fields[key] = value
+	}
+	for key, link := range alert.Links {
+		fields[key+"_url"] = link
+	}
+
+	entry := l.logger.WithFields(fields)
+	message := "🚨 " + alert.Title
+	if alert.Text != "" {
+		message += ": " + alert.Text
+	}
+
+	switch alert.Severity {
+	case SeverityCritical:
+		entry.Error(message)
+	case SeverityWarning:
+		entry.Warn(message)
+	default:
+		entry.Info(message)
+	}
+	return nil
+}
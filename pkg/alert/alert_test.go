@@ -0,0 +1,148 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSlackNotifier(t *testing.T) {
+	var got map[string]string
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	slack := NewSlackNotifier("xoxb-test", "#oncall", time.Second)
+	slack.url = server.URL
+
+	err := slack.Notify(context.Background(), Alert{
+		Severity: SeverityCritical,
+		Title:    "Canary missed attestation",
+		Fields:   map[string]string{"validator": "42", "label": "operator:a"},
+	})
+	if err != nil {
+		t.Fatalf("Notify failed: %v", err)
+	}
+
+	if auth != "Bearer xoxb-test" {
+		t.Errorf("Expected bearer token, got %q", auth)
+	}
+	if got["channel"] != "#oncall" {
+		t.Errorf("Expected channel #oncall, got %q", got["channel"])
+	}
+	expected := "*[CRITICAL] Canary missed attestation*\n• label: `operator:a`\n• validator: `42`"
+	if got["text"] != expected {
+		t.Errorf("Expected text %q, got %q", expected, got["text"])
+	}
+}
+
+func TestSlackNotifierAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok":false,"error":"channel_not_found"}`))
+	}))
+	defer server.Close()
+
+	slack := NewSlackNotifier("xoxb-test", "#missing", time.Second)
+	slack.url = server.URL
+
+	err := slack.Notify(context.Background(), Alert{Severity: SeverityWarning, Title: "test"})
+	if err == nil || !strings.Contains(err.Error(), "channel_not_found") {
+		t.Errorf("Expected channel_not_found error, got %v", err)
+	}
+}
+
+type failingNotifier struct{ calls int }
+
+func (f *failingNotifier) Name() string { return "failing" }
+
+func (f *failingNotifier) Notify(ctx context.Context, alert Alert) error {
+	f.calls++
+	return errors.New("unavailable")
+}
+
+func TestMultiNotifiesAll(t *testing.T) {
+	first, second := &failingNotifier{}, &failingNotifier{}
+
+	err := Multi{first, second}.Notify(context.Background(), Alert{Title: "test"})
+	if err == nil {
+		t.Error("Expected the notifier errors to be returned")
+	}
+	if first.calls != 1 || second.calls != 1 {
+		t.Errorf("Expected every notifier to be tried once, got %d and %d", first.calls, second.calls)
+	}
+}
+
+func TestMultiRoutesChannels(t *testing.T) {
+	routed, skipped := &failingNotifier{}, &countingNotifier{name: "slack"}
+	nested := &countingNotifier{name: "discord"}
+
+	Multi{routed, skipped, Multi{nested}}.Notify(context.Background(), Alert{Title: "test", Channels: []string{"failing", "discord"}})
+	if routed.calls != 1 || skipped.calls != 0 || nested.calls != 1 {
+		t.Errorf("Expected only the routed channels, got failing=%d slack=%d discord=%d", routed.calls, skipped.calls, nested.calls)
+	}
+}
+
+type countingNotifier struct {
+	name  string
+	calls int
+}
+
+func (c *countingNotifier) Name() string { return c.name }
+
+func (c *countingNotifier) Notify(ctx context.Context, alert Alert) error {
+	c.calls++
+	return nil
+}
+
+type recordingNotifier struct{ alerts []Alert }
+
+func (r *recordingNotifier) Name() string { return "recording" }
+
+func (r *recordingNotifier) Notify(ctx context.Context, alert Alert) error {
+	r.alerts = append(r.alerts, alert)
+	return nil
+}
+
+func TestLinkerAddsLinks(t *testing.T) {
+	next := &recordingNotifier{}
+	linker := NewLinker(next, func(fields map[string]string) map[string]string {
+		return map[string]string{"validator": "https://explorer.example/validator/" + fields["validator"]}
+	})
+
+	linker.Notify(context.Background(), Alert{Title: "test", Fields: map[string]string{"validator": "42"}})
+	if link := next.alerts[0].Links["validator"]; link != "https://explorer.example/validator/42" {
+		t.Errorf("Expected a validator link, got %q", link)
+	}
+	if linker.Name() != "recording" {
+		t.Errorf("Expected the wrapped notifier's name, got %s", linker.Name())
+	}
+	if text := formatSlack(next.alerts[0]); !strings.Contains(text, "<https://explorer.example/validator/42|42>") {
+		t.Errorf("Expected a Slack link, got %q", text)
+	}
+}
+
+func TestTrackedRecordsDeliveries(t *testing.T) {
+	failing := Track(&failingNotifier{})
+	failing.Notify(context.Background(), Alert{Title: "test"})
+	failing.Notify(context.Background(), Alert{Title: "test"})
+	status := failing.Status()
+	if status.Name != "failing" || status.ConsecutiveFailures != 2 || status.LastError != "unavailable" || status.LastFailure.IsZero() {
+		t.Errorf("Expected two recorded failures, got %+v", status)
+	}
+
+	counting := &countingNotifier{name: "slack"}
+	tracked := Track(counting)
+	Multi{tracked}.Notify(context.Background(), Alert{Title: "test", Channels: []string{"slack"}})
+	if status := tracked.Status(); counting.calls != 1 || status.LastSuccess.IsZero() || status.ConsecutiveFailures != 0 {
+		t.Errorf("Expected a routed, recorded delivery, got %d calls and %+v", counting.calls, status)
+	}
+}
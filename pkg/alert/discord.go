@@ -0,0 +1,170 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Discord embed limits; longer values are rejected by the API
+const (
+	discordMaxTitle      = 256
+	discordMaxFieldValue = 1024
+	discordMaxFields     = 25
+)
+
+// discordColors are the embed side colors per severity
+var discordColors = map[Severity]int{
+	SeverityInfo:     0x3498db,
+	SeverityWarning:  0xf1c40f,
+	SeverityCritical: 0xe74c3c,
+}
+
+// discordLeadFields are shown first, in this order; other fields follow sorted by key
+var discordLeadFields = []string{"validator", "pubkey", "label", "slot", "epoch"}
+
+// DiscordNotifier posts alerts as embeds to Discord webhooks, routed by severity
+type DiscordNotifier struct {
+	webhooks   map[Severity]string // Webhook per severity; severities without one are not posted
+	httpClient *http.Client
+}
+
+// NewDiscordNotifier creates a Discord notifier posting each severity to its webhook
+func NewDiscordNotifier(webhooks map[Severity]string, timeout time.Duration) *DiscordNotifier {
+	return &DiscordNotifier{
+		webhooks: webhooks,
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+// Name returns the notifier name used in logs
+func (d *DiscordNotifier) Name() string {
+	return "discord"
+}
+
+// discordEmbed is the part of a Discord message embed that alerts use
+type discordEmbed struct {
+	Title       string              `json:"title"`
+	Description string              `json:"description,omitempty"`
+	URL         string              `json:"url,omitempty"`
+	Color       int                 `json:"color"`
+	Fields      []discordEmbedField `json:"fields,omitempty"`
+	Timestamp   string              `json:"timestamp"`
+}
+
+// discordEmbedField is a name/value pair of an embed
+type discordEmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+// Notify posts the alert to its severity's webhook, if any
+func (d *DiscordNotifier) Notify(ctx context.Context, alert Alert) error {
+	webhook, ok := d.webhooks[alert.Severity]
+	if !ok {
+		return nil
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"embeds": []discordEmbed{d.embed(alert)},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal discord message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create discord request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		// The webhook URL embeds its token, so keep it out of the error
+		return fmt.Errorf("discord request failed: %w", unwrapURLError(err))
+	}
+	defer resp.Body.Close()
+
+	// Webhooks answer 204 without ?wait=true
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("discord returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
+
+// embed renders an alert as a Discord embed, with the title linking to the validator's explorer page
+func (d *DiscordNotifier) embed(alert Alert) discordEmbed {
+	embed := discordEmbed{
+		Title:       truncate(fmt.Sprintf("[%s] %s", strings.ToUpper(string(alert.Severity)), alert.Title), discordMaxTitle),
+		Description: alert.Text,
+		Color:       discordColors[alert.Severity],
+		Timestamp:   time.Now().UTC().Format(time.RFC3339),
+	}
+	embed.URL = alert.Links["validator"]
+
+	seen := make(map[string]bool, len(discordLeadFields))
+	var fields [][2]string
+	for _, key := range discordLeadFields {
+		if value, ok := alert.Fields[key]; ok {
+			fields = append(fields, [2]string{key, value})
+			seen[key] = true
+		}
+	}
+	for _, field := range alert.SortedFields() {
+		if !seen[field[0]] {
+			fields = append(fields, field)
+		}
+	}
+
+	for _, field := range fields {
+		if len(embed.Fields) == discordMaxFields {
+			break
+		}
+		embed.Fields = append(embed.Fields, discordEmbedField{
+			Name:   field[0],
+			Value:  truncate(discordFieldValue(field[1], alert.Links[field[0]]), discordMaxFieldValue),
+			Inline: len(field[1]) <= 32,
+		})
+	}
+	return embed
+}
+
+// discordFieldValue renders a field value, as a link to its explorer page if it has one
+func discordFieldValue(value, link string) string {
+	if value == "" {
+		return "-"
+	}
+	if link == "" {
+		return value
+	}
+	return fmt.Sprintf("[%s](%s)", value, link)
+}
+
+// truncate shortens s to at most max characters, marking the cut
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-3]) + "..."
+}
+
+// unwrapURLError drops the request URL from a client error
+func unwrapURLError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
@@ -0,0 +1,81 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDiscordNotifier(t *testing.T) {
+	var got struct {
+		Embeds []discordEmbed `json:"embeds"`
+	}
+	posts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posts++
+		json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	discord := NewDiscordNotifier(map[Severity]string{SeverityCritical: server.URL}, time.Second)
+
+	if err := discord.Notify(context.Background(), Alert{Severity: SeverityInfo, Title: "report"}); err != nil || posts != 0 {
+		t.Fatalf("Unrouted severity: posts = %d, err = %v", posts, err)
+	}
+
+	err := discord.Notify(context.Background(), Alert{
+		Severity: SeverityCritical,
+		Title:    "Canary missed attestation",
+		Text:     "Canary 42 missed",
+		Fields:   map[string]string{"network": "mainnet", "validator": "42", "pubkey": "0xabc", "label": "operator:a", "slot": "100"},
+		Links:    map[string]string{"validator": "https://beaconcha.in/validator/42", "slot": "https://beaconcha.in/slot/100"},
+	})
+	if err != nil {
+		t.Fatalf("Notify failed: %v", err)
+	}
+	if posts != 1 || len(got.Embeds) != 1 {
+		t.Fatalf("Expected one embed, got %d posts: %+v", posts, got)
+	}
+
+	embed := got.Embeds[0]
+	if embed.Title != "[CRITICAL] Canary missed attestation" || embed.URL != "https://beaconcha.in/validator/42" || embed.Color != discordColors[SeverityCritical] {
+		t.Errorf("Unexpected embed: %+v", embed)
+	}
+	var names []string
+	for _, field := range embed.Fields {
+		names = append(names, field.Name)
+	}
+	if strings.Join(names, ",") != "validator,pubkey,label,slot,network" {
+		t.Errorf("Expected lead fields first, got %v", names)
+	}
+	if embed.Fields[3].Value != "[100](https://beaconcha.in/slot/100)" {
+		t.Errorf("Expected slot link, got %q", embed.Fields[3].Value)
+	}
+}
+
+func TestDiscordNotifierError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message": "Unknown Webhook"}`, http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	discord := NewDiscordNotifier(map[Severity]string{SeverityWarning: server.URL + "/api/webhooks/1/secret-token"}, time.Second)
+	err := discord.Notify(context.Background(), Alert{Severity: SeverityWarning, Title: "test"})
+	if err == nil || !strings.Contains(err.Error(), "Unknown Webhook") {
+		t.Errorf("Expected Unknown Webhook error, got %v", err)
+	}
+}
+
+func TestSeverityAtLeast(t *testing.T) {
+	if !SeverityCritical.AtLeast(SeverityWarning) || SeverityInfo.AtLeast(SeverityWarning) || !SeverityWarning.AtLeast(SeverityWarning) {
+		t.Error("Unexpected severity ordering")
+	}
+	if _, err := ParseSeverity("page"); err == nil {
+		t.Error("ParseSeverity accepted an unknown severity")
+	}
+}
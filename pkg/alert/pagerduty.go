@@ -0,0 +1,130 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// pagerDutyEventsURL is the PagerDuty Events API v2 endpoint
+const pagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// pagerDutyMaxSummary is the longest summary the Events API accepts
+const pagerDutyMaxSummary = 1024
+
+// PagerDutyNotifier triggers PagerDuty incidents through the Events API v2
+// Alerts with the same Key share a dedup key, so a condition that persists across epochs pages once
+type PagerDutyNotifier struct {
+	routingKey  string
+	source      string
+	minSeverity Severity
+	url         string
+	httpClient  *http.Client
+}
+
+// NewPagerDutyNotifier creates a notifier for an Events API v2 integration, paging alerts from minSeverity up
+func NewPagerDutyNotifier(routingKey, source string, minSeverity Severity, timeout time.Duration) *PagerDutyNotifier {
+	return &PagerDutyNotifier{
+		routingKey:  routingKey,
+		source:      source,
+		minSeverity: minSeverity,
+		url:         pagerDutyEventsURL,
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+// Name returns the notifier name used in logs
+func (p *PagerDutyNotifier) Name() string {
+	return "pagerduty"
+}
+
+// pagerDutyEvent is an Events API v2 trigger
+type pagerDutyEvent struct {
+	RoutingKey  string           `json:"routing_key"`
+	EventAction string           `json:"event_action"`
+	DedupKey    string           `json:"dedup_key,omitempty"`
+	Payload     pagerDutyPayload `json:"payload"`
+	Links       []pagerDutyLink  `json:"links,omitempty"`
+}
+
+// pagerDutyLink is a link shown on the incident
+type pagerDutyLink struct {
+	Href string `json:"href"`
+	Text string `json:"text"`
+}
+
+// pagerDutyPayload describes the incident
+type pagerDutyPayload struct {
+	Summary       string            `json:"summary"`
+	Source        string            `json:"source"`
+	Severity      string            `json:"severity"`
+	CustomDetails map[string]string `json:"custom_details,omitempty"`
+}
+
+// pagerDutyLinks lists the explorer links of an alert in field order
+func pagerDutyLinks(alert Alert) []pagerDutyLink {
+	var links []pagerDutyLink
+	for _, field := range alert.SortedFields() {
+		if link := alert.Links[field[0]]; link != "" {
+			links = append(links, pagerDutyLink{Href: link, Text: fmt.Sprintf("%s %s", field[0], field[1])})
+		}
+	}
+	return links
+}
+
+// Notify triggers an incident for alerts from the minimum severity up
+func (p *PagerDutyNotifier) Notify(ctx context.Context, alert Alert) error {
+	if !alert.Severity.AtLeast(p.minSeverity) {
+		return nil
+	}
+
+	summary := alert.Title
+	if alert.Text != "" {
+		summary += ": " + alert.Text
+	}
+	dedupKey := alert.Key
+	if dedupKey == "" {
+		dedupKey = alert.Title
+	}
+
+	body, err := json.Marshal(pagerDutyEvent{
+		RoutingKey:  p.routingKey,
+		EventAction: "trigger",
+		DedupKey:    dedupKey,
+		Payload: pagerDutyPayload{
+			Summary:       truncate(summary, pagerDutyMaxSummary),
+			Source:        p.source,
+			Severity:      string(alert.Severity),
+			CustomDetails: alert.Fields,
+		},
+		Links: pagerDutyLinks(alert),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal pagerduty event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create pagerduty request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("pagerduty request failed: %w", unwrapURLError(err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("pagerduty returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPagerDutyNotifier(t *testing.T) {
+	var events []pagerDutyEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event pagerDutyEvent
+		json.NewDecoder(r.Body).Decode(&event)
+		events = append(events, event)
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"status":"success","dedup_key":"x"}`))
+	}))
+	defer server.Close()
+
+	pd := NewPagerDutyNotifier("routing-key", "eth-validator-watcher (mainnet)", SeverityCritical, time.Second)
+	pd.url = server.URL
+
+	if err := pd.Notify(context.Background(), Alert{Severity: SeverityWarning, Title: "relay"}); err != nil || len(events) != 0 {
+		t.Fatalf("Warning alert: %d events, err = %v", len(events), err)
+	}
+
+	err := pd.Notify(context.Background(), Alert{
+		Severity: SeverityCritical,
+		Title:    "Watched validator 42 slashed",
+		Text:     "proposer slashing",
+		Fields:   map[string]string{"validator": "42"},
+		Key:      "slashing:42",
+	})
+	if err != nil {
+		t.Fatalf("Notify failed: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("Expected one event, got %d", len(events))
+	}
+
+	event := events[0]
+	if event.RoutingKey != "routing-key" || event.EventAction != "trigger" || event.DedupKey != "slashing:42" {
+		t.Errorf("Unexpected event: %+v", event)
+	}
+	if event.Payload.Summary != "Watched validator 42 slashed: proposer slashing" || event.Payload.Severity != "critical" || event.Payload.CustomDetails["validator"] != "42" {
+		t.Errorf("Unexpected payload: %+v", event.Payload)
+	}
+}
+
+func TestPagerDutyNotifierError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"status":"invalid event","message":"Event object is invalid"}`, http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	pd := NewPagerDutyNotifier("bad", "watcher", SeverityCritical, time.Second)
+	pd.url = server.URL
+
+	err := pd.Notify(context.Background(), Alert{Severity: SeverityCritical, Title: "test"})
+	if err == nil || !strings.Contains(err.Error(), "invalid") {
+		t.Errorf("Expected invalid event error, got %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+package alert
+
+import (
+	"context"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/cron"
+	"github.com/sirupsen/logrus"
+)
+
+// Silence is a named maintenance window
+type Silence struct {
+	Name   string
+	Window cron.Window
+}
+
+// Silencer drops alerts while a maintenance window is open and forwards the rest
+type Silencer struct {
+	next     Notifier
+	silences []Silence
+	logger   *logrus.Logger
+	now      func() time.Time
+}
+
+// NewSilencer wraps a notifier with maintenance windows
+func NewSilencer(next Notifier, silences []Silence, logger *logrus.Logger) *Silencer {
+	return &Silencer{
+		next:     next,
+		silences: silences,
+		logger:   logger,
+		now:      time.Now,
+	}
+}
+
+// Name returns the wrapped notifier's name
+func (s *Silencer) Name() string {
+	return s.next.Name()
+}
+
+// Active returns the name of the open maintenance window, if any
+func (s *Silencer) Active() (string, bool) {
+	now := s.now()
+	for _, silence := range s.silences {
+		if silence.Window.Active(now) {
+			return silence.Name, true
+		}
+	}
+	return "", false
+}
+
+// Notify forwards the alert unless a maintenance window is open
+func (s *Silencer) Notify(ctx context.Context, alert Alert) error {
+	if name, ok := s.Active(); ok {
+		s.logger.WithFields(logrus.Fields{
+			"alert":   alert.Title,
+			"silence": name,
+		}).Debug("Alert silenced by maintenance window")
+		return nil
+	}
+	return s.next.Notify(ctx, alert)
+}
@@ -0,0 +1,36 @@
+package alert
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/cron"
+	"github.com/sirupsen/logrus"
+)
+
+func TestSilencer(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	schedule, err := cron.Parse("0 2 * * *", "") // Nightly 02:00-03:00 UTC
+	if err != nil {
+		t.Fatalf("Failed to parse schedule: %v", err)
+	}
+
+	next := &failingNotifier{}
+	silencer := NewSilencer(next, []Silence{{Name: "nightly", Window: cron.Window{Schedule: schedule, Duration: time.Hour}}}, logger)
+
+	silencer.now = func() time.Time { return time.Date(2025, 1, 1, 2, 30, 0, 0, time.UTC) }
+	if name, ok := silencer.Active(); !ok || name != "nightly" {
+		t.Errorf("Expected the nightly window to be open, got %q (%v)", name, ok)
+	}
+	if err := silencer.Notify(context.Background(), Alert{Title: "test"}); err != nil || next.calls != 0 {
+		t.Errorf("Expected the alert to be silenced, got err=%v calls=%d", err, next.calls)
+	}
+
+	silencer.now = func() time.Time { return time.Date(2025, 1, 1, 3, 0, 0, 0, time.UTC) }
+	if err := silencer.Notify(context.Background(), Alert{Title: "test"}); err == nil || next.calls != 1 {
+		t.Errorf("Expected the alert to be forwarded after the window, got err=%v calls=%d", err, next.calls)
+	}
+}
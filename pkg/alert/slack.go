@@ -0,0 +1,97 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// slackPostMessageURL is the Slack Web API method used to post alerts
+const slackPostMessageURL = "https://slack.com/api/chat.postMessage"
+
+// SlackNotifier posts alerts to a Slack channel with a bot token
+type SlackNotifier struct {
+	token      string
+	channel    string
+	url        string
+	httpClient *http.Client
+}
+
+// NewSlackNotifier creates a Slack notifier for the given bot token and channel
+func NewSlackNotifier(token, channel string, timeout time.Duration) *SlackNotifier {
+	return &SlackNotifier{
+		token:   token,
+		channel: channel,
+		url:     slackPostMessageURL,
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+// Name returns the notifier name used in logs
+func (s *SlackNotifier) Name() string {
+	return "slack"
+}
+
+// Notify posts the alert as a message to the channel
+func (s *SlackNotifier) Notify(ctx context.Context, alert Alert) error {
+	body, err := json.Marshal(map[string]string{
+		"channel": s.channel,
+		"text":    formatSlack(alert),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal slack message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create slack request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Authorization", "Bearer "+s.token)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("slack request failed: %w", unwrapURLError(err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("slack returned status %d", resp.StatusCode)
+	}
+
+	// The Web API reports errors in the body with a 200 status
+	var result struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode slack response: %w", err)
+	}
+	if !result.OK {
+		return fmt.Errorf("slack rejected message: %s", result.Error)
+	}
+	return nil
+}
+
+// formatSlack renders an alert as Slack mrkdwn
+func formatSlack(alert Alert) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*[%s] %s*", strings.ToUpper(string(alert.Severity)), alert.Title)
+	if alert.Text != "" {
+		b.WriteString("\n" + alert.Text)
+	}
+	for _, field := range alert.SortedFields() {
+		if link := alert.Links[field[0]]; link != "" {
+			fmt.Fprintf(&b, "\n• %s: <%s|%s>", field[0], link, field[1])
+			continue
+		}
+		fmt.Fprintf(&b, "\n• %s: `%s`", field[0], field[1])
+	}
+	return b.String()
+}
@@ -0,0 +1,109 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// telegramAPIURL is the Telegram Bot API base URL
+const telegramAPIURL = "https://api.telegram.org"
+
+// telegramMaxMessage is the longest message text the Bot API accepts
+const telegramMaxMessage = 4096
+
+// TelegramNotifier sends alerts to a Telegram chat through a bot
+type TelegramNotifier struct {
+	token       string
+	chatID      string
+	minSeverity Severity
+	url         string
+	httpClient  *http.Client
+}
+
+// NewTelegramNotifier creates a Telegram notifier for a bot token and chat, sending alerts from minSeverity up
+func NewTelegramNotifier(token, chatID string, minSeverity Severity, timeout time.Duration) *TelegramNotifier {
+	return &TelegramNotifier{
+		token:       token,
+		chatID:      chatID,
+		minSeverity: minSeverity,
+		url:         telegramAPIURL,
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+// Name returns the notifier name used in logs
+func (t *TelegramNotifier) Name() string {
+	return "telegram"
+}
+
+// Notify sends the alert as a message to the chat
+func (t *TelegramNotifier) Notify(ctx context.Context, alert Alert) error {
+	if !alert.Severity.AtLeast(t.minSeverity) {
+		return nil
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"chat_id":                  t.chatID,
+		"text":                     formatTelegram(alert),
+		"parse_mode":               "HTML",
+		"disable_web_page_preview": true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal telegram message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url+"/bot"+t.token+"/sendMessage", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create telegram request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		// The request URL carries the bot token
+		return fmt.Errorf("telegram request failed: %w", unwrapURLError(err))
+	}
+	defer resp.Body.Close()
+
+	// The Bot API reports errors in the body, with a matching status
+	var result struct {
+		OK          bool   `json:"ok"`
+		Description string `json:"description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("telegram returned status %d", resp.StatusCode)
+	}
+	if !result.OK {
+		return fmt.Errorf("telegram rejected message: %s", result.Description)
+	}
+	return nil
+}
+
+// formatTelegram renders an alert as Telegram HTML
+// Fields come last, so truncation drops them before the title and text
+func formatTelegram(alert Alert) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "<b>[%s] %s</b>", strings.ToUpper(string(alert.Severity)), html.EscapeString(alert.Title))
+	if alert.Text != "" {
+		b.WriteString("\n" + html.EscapeString(alert.Text))
+	}
+	for _, field := range alert.SortedFields() {
+		line := fmt.Sprintf("\n• %s: <code>%s</code>", html.EscapeString(field[0]), html.EscapeString(field[1]))
+		if link := alert.Links[field[0]]; link != "" {
+			line = fmt.Sprintf("\n• %s: <a href=\"%s\">%s</a>", html.EscapeString(field[0]), html.EscapeString(link), html.EscapeString(field[1]))
+		}
+		if len([]rune(b.String()+line)) > telegramMaxMessage {
+			break
+		}
+		b.WriteString(line)
+	}
+	return b.String()
+}
@@ -0,0 +1,70 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTelegramNotifier(t *testing.T) {
+	var got map[string]interface{}
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"ok":true,"result":{}}`))
+	}))
+	defer server.Close()
+
+	telegram := NewTelegramNotifier("123:abc", "-1001", SeverityWarning, time.Second)
+	telegram.url = server.URL
+
+	if err := telegram.Notify(context.Background(), Alert{Severity: SeverityInfo, Title: "report"}); err != nil || got != nil {
+		t.Errorf("Expected info alerts to be skipped, got %v, %v", got, err)
+	}
+
+	err := telegram.Notify(context.Background(), Alert{
+		Severity: SeverityCritical,
+		Title:    "Validator 42 <offline>",
+		Fields:   map[string]string{"label": "operator:a&b"},
+	})
+	if err != nil {
+		t.Fatalf("Notify failed: %v", err)
+	}
+
+	if path != "/bot123:abc/sendMessage" {
+		t.Errorf("Expected the bot's sendMessage method, got %s", path)
+	}
+	if got["chat_id"] != "-1001" || got["parse_mode"] != "HTML" {
+		t.Errorf("Unexpected message: %v", got)
+	}
+	expected := "<b>[CRITICAL] Validator 42 &lt;offline&gt;</b>\n• label: <code>operator:a&amp;b</code>"
+	if got["text"] != expected {
+		t.Errorf("Expected text %q, got %q", expected, got["text"])
+	}
+}
+
+func TestTelegramNotifierAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"ok":false,"error_code":400,"description":"Bad Request: chat not found"}`))
+	}))
+	defer server.Close()
+
+	telegram := NewTelegramNotifier("123:abc", "-1", SeverityInfo, time.Second)
+	telegram.url = server.URL
+
+	err := telegram.Notify(context.Background(), Alert{Severity: SeverityWarning, Title: "test"})
+	if err == nil || !strings.Contains(err.Error(), "chat not found") {
+		t.Errorf("Expected chat not found error, got %v", err)
+	}
+
+	telegram.url = "http://127.0.0.1:1"
+	if err := telegram.Notify(context.Background(), Alert{Severity: SeverityWarning, Title: "test"}); err == nil || strings.Contains(err.Error(), "123:abc") {
+		t.Errorf("Expected a transport error without the token, got %v", err)
+	}
+}
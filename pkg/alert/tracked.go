@@ -0,0 +1,55 @@
+package alert
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ChannelStatus is the outcome of the latest deliveries to an alert channel
+type ChannelStatus struct {
+	Name                string
+	LastSuccess         time.Time // Zero until an alert was delivered
+	LastFailure         time.Time // Zero until a delivery failed
+	LastError           string
+	ConsecutiveFailures uint64
+}
+
+// Tracked records the delivery outcomes of a notifier, for the health report
+type Tracked struct {
+	Notifier
+
+	mu     sync.Mutex
+	status ChannelStatus
+}
+
+// Track wraps a notifier so its deliveries are recorded; routing still sees the notifier's name
+func Track(next Notifier) *Tracked {
+	return &Tracked{Notifier: next, status: ChannelStatus{Name: next.Name()}}
+}
+
+// Notify delivers the alert and records the outcome
+func (t *Tracked) Notify(ctx context.Context, alert Alert) error {
+	err := t.Notifier.Notify(ctx, alert)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if err != nil {
+		t.status.LastFailure = time.Now()
+		t.status.LastError = err.Error()
+		t.status.ConsecutiveFailures++
+	} else {
+		t.status.LastSuccess = time.Now()
+		t.status.ConsecutiveFailures = 0
+	}
+	return err
+}
+
+// Status returns the channel's latest delivery outcomes
+func (t *Tracked) Status() ChannelStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.status
+}
@@ -0,0 +1,79 @@
+package anonymize
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"strconv"
+	"strings"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// Prefix marks pseudonymous pubkeys in outputs
+const Prefix = "anon-"
+
+// pseudonymBytes is the length of the keyed hash kept in a pseudonym (64 bits,
+// so collisions stay unlikely in watched sets of millions of keys)
+const pseudonymBytes = 8
+
+// IndexBase is the smallest pseudonymous validator index; pseudonyms lie in [2^52, 2^53), far above
+// any real index and still exact as JSON numbers
+const IndexBase = 1 << 52
+
+// Anonymizer replaces pubkeys and validator indices with stable pseudonyms
+// Pseudonyms are keyed hashes: without the salt, nobody can hash the public
+// validator set to map them back to pubkeys
+type Anonymizer struct {
+	key []byte
+}
+
+// New creates an anonymizer keyed with salt; the same salt always gives the same pseudonyms
+func New(salt string) *Anonymizer {
+	return &Anonymizer{key: []byte(salt)}
+}
+
+// Pubkey returns the pseudonym of a pubkey
+// A nil anonymizer returns the pubkey unchanged, so callers don't need to check if privacy mode is on
+func (a *Anonymizer) Pubkey(pubkey string) string {
+	if a == nil || pubkey == "" {
+		return pubkey
+	}
+
+	return Prefix + hex.EncodeToString(a.sum("", strings.ToLower(strings.TrimPrefix(pubkey, "0x"))))
+}
+
+// Index returns the pseudonym of a validator index, a number from IndexBase up
+// Indices map to the validators' pubkeys on chain, so outputs that carry them need pseudonyms too;
+// a nil anonymizer returns the index unchanged
+func (a *Anonymizer) Index(index models.ValidatorIndex) models.ValidatorIndex {
+	if a == nil {
+		return index
+	}
+
+	sum := binary.BigEndian.Uint64(a.sum("index:", strconv.FormatUint(uint64(index), 10)))
+	return models.ValidatorIndex(IndexBase | sum&(IndexBase-1))
+}
+
+// Credentials returns the pseudonym of withdrawal credentials, which identify a validator as much as
+// its pubkey; a nil anonymizer returns them unchanged
+func (a *Anonymizer) Credentials(credentials string) string {
+	if a == nil || credentials == "" {
+		return credentials
+	}
+
+	return Prefix + hex.EncodeToString(a.sum("credentials:", strings.ToLower(strings.TrimPrefix(credentials, "0x"))))
+}
+
+// sum returns the truncated keyed hash of a domain-separated value
+func (a *Anonymizer) sum(domain, value string) []byte {
+	mac := hmac.New(sha256.New, a.key)
+	mac.Write([]byte(domain + value))
+	return mac.Sum(nil)[:pseudonymBytes]
+}
+
+// Enabled reports whether pubkeys are anonymized
+func (a *Anonymizer) Enabled() bool {
+	return a != nil
+}
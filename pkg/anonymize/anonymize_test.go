@@ -0,0 +1,77 @@
+package anonymize
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPubkeyPseudonyms(t *testing.T) {
+	pubkey := "0x8f3c5e1a"
+	a := New("secret")
+
+	pseudonym := a.Pubkey(pubkey)
+	if !strings.HasPrefix(pseudonym, Prefix) || len(pseudonym) != len(Prefix)+16 {
+		t.Fatalf("Unexpected pseudonym %q", pseudonym)
+	}
+	if strings.Contains(pseudonym, "8f3c5e1a") {
+		t.Error("Pseudonym must not contain the pubkey")
+	}
+
+	// Stable across instances and pubkey spellings
+	if again := New("secret").Pubkey("0x8F3C5E1A"); again != pseudonym {
+		t.Errorf("Expected stable pseudonym %q, got %q", pseudonym, again)
+	}
+	if other := New("other").Pubkey(pubkey); other == pseudonym {
+		t.Error("Expected a different salt to give a different pseudonym")
+	}
+}
+
+func TestNilAnonymizer(t *testing.T) {
+	var a *Anonymizer
+	if a.Enabled() {
+		t.Error("Expected nil anonymizer to be disabled")
+	}
+	if got := a.Pubkey("0xabc"); got != "0xabc" {
+		t.Errorf("Expected pubkey unchanged, got %q", got)
+	}
+}
+
+func TestIndexPseudonyms(t *testing.T) {
+	a := New("secret")
+
+	pseudonym := a.Index(42)
+	if pseudonym < IndexBase || pseudonym >= 2*IndexBase {
+		t.Fatalf("Expected a pseudonym in [2^52, 2^53), got %d", pseudonym)
+	}
+	if again := New("secret").Index(42); again != pseudonym {
+		t.Errorf("Expected stable pseudonym %d, got %d", pseudonym, again)
+	}
+	if a.Index(43) == pseudonym || New("other").Index(42) == pseudonym {
+		t.Error("Expected other indices and salts to give other pseudonyms")
+	}
+
+	var disabled *Anonymizer
+	if got := disabled.Index(42); got != 42 {
+		t.Errorf("Expected index unchanged, got %d", got)
+	}
+}
+
+func TestCredentialsPseudonyms(t *testing.T) {
+	credentials := "0x010000000000000000000000d8da6bf26964af9d7eed9e03e53415d37aa96045"
+	a := New("secret")
+
+	pseudonym := a.Credentials(credentials)
+	if !strings.HasPrefix(pseudonym, Prefix) || strings.Contains(pseudonym, "d8da6bf2") {
+		t.Fatalf("Unexpected pseudonym %q", pseudonym)
+	}
+	if pseudonym == a.Pubkey(credentials) {
+		t.Error("Expected credentials and pubkeys to get distinct pseudonyms")
+	}
+	if again := New("secret").Credentials(strings.ToUpper(credentials[2:])); again != pseudonym {
+		t.Errorf("Expected stable pseudonym %q, got %q", pseudonym, again)
+	}
+	var disabled *Anonymizer
+	if got := disabled.Credentials(credentials); got != credentials {
+		t.Errorf("Expected credentials unchanged, got %q", got)
+	}
+}
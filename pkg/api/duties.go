@@ -0,0 +1,236 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// ProposalDuty is an upcoming block proposal of a watched validator
+type ProposalDuty struct {
+	Slot           models.Slot           `json:"slot"`
+	ValidatorIndex models.ValidatorIndex `json:"validator_index"`
+	Pubkey         string                `json:"pubkey"`
+	Labels         []string              `json:"labels"`
+	ExplorerURL    string                `json:"explorer_url,omitempty"` // Slot page on the block explorer
+}
+
+// Liveness is what the beacon node's liveness endpoint last said about a validator
+type Liveness struct {
+	CheckedEpoch  models.Epoch  `json:"checked_epoch"`   // Last epoch liveness was checked for
+	Live          bool          `json:"live"`            // Whether the validator was live in that epoch
+	LastLiveEpoch *models.Epoch `json:"last_live_epoch"` // Last epoch it was seen live since startup, null if never
+}
+
+// livenessTracker keeps the last liveness check and the last epoch each validator was seen live
+type livenessTracker struct {
+	checked  bool
+	epoch    models.Epoch
+	live     map[models.ValidatorIndex]bool
+	lastLive map[models.ValidatorIndex]models.Epoch
+}
+
+// of returns a validator's liveness, nil until liveness was checked
+func (t *livenessTracker) of(index models.ValidatorIndex) *Liveness {
+	if !t.checked {
+		return nil
+	}
+
+	liveness := &Liveness{CheckedEpoch: t.epoch, Live: t.live[index]}
+	if epoch, ok := t.lastLive[index]; ok {
+		liveness.LastLiveEpoch = &epoch
+	}
+	return liveness
+}
+
+// UpdateLiveness records the liveness of the watched validators in an epoch
+func (s *Server) UpdateLiveness(epoch models.Epoch, live map[models.ValidatorIndex]bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.liveness.lastLive == nil {
+		s.liveness.lastLive = make(map[models.ValidatorIndex]models.Epoch)
+	}
+	if s.indices != nil {
+		mapped := make(map[models.ValidatorIndex]bool, len(live))
+		for index, isLive := range live {
+			mapped[s.indices(index)] = isLive
+		}
+		live = mapped
+	}
+	s.liveness.checked = true
+	s.liveness.epoch = epoch
+	s.liveness.live = live
+	for index, isLive := range live {
+		if isLive && epoch >= s.liveness.lastLive[index] {
+			s.liveness.lastLive[index] = epoch
+		}
+	}
+}
+
+// UpdateProposals replaces the upcoming proposals of watched validators
+func (s *Server) UpdateProposals(proposals []ProposalDuty) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sorted := make([]ProposalDuty, len(proposals))
+	copy(sorted, proposals)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Slot < sorted[j].Slot })
+	for i := range sorted {
+		if s.pubkeys != nil {
+			sorted[i].Pubkey = s.pubkeys(sorted[i].Pubkey)
+		}
+		sorted[i].ExplorerURL = s.explorer.Slot(sorted[i].Slot)
+		sorted[i].ValidatorIndex = s.index(sorted[i].ValidatorIndex)
+	}
+
+	s.proposals = sorted
+}
+
+// handleProposals returns the upcoming block proposals of watched validators, earliest first
+// Optional query parameter: label (repeat or comma-separate to require several)
+func (s *Server) handleProposals(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	labels := splitValues(r.URL.Query()["label"])
+
+	s.mu.RLock()
+	proposals := make([]ProposalDuty, 0, len(s.proposals))
+	for _, proposal := range s.proposals {
+		if hasLabels(proposal.Labels, labels) {
+			proposals = append(proposals, proposal)
+		}
+	}
+	s.mu.RUnlock()
+
+	writeJSON(w, http.StatusOK, response{Data: proposals})
+}
+
+// hasLabels reports whether have contains every wanted label
+func hasLabels(have, want []string) bool {
+	for _, label := range want {
+		found := false
+		for _, h := range have {
+			if h == label {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// SyncParticipationSlots is how many recent slots of sync committee participation are kept
+const SyncParticipationSlots = 64
+
+// SyncCommitteeMember is a watched validator in the current sync committee
+type SyncCommitteeMember struct {
+	ValidatorIndex models.ValidatorIndex `json:"validator_index"`
+	Pubkey         string                `json:"pubkey"`
+	Labels         []string              `json:"labels"`
+	Positions      []int                 `json:"positions"`               // Committee positions (a validator may hold several)
+	Participation  []SyncParticipation   `json:"participation,omitempty"` // Recent blocks, with ?detail=true
+	ExplorerURL    string                `json:"explorer_url,omitempty"`  // Validator page on the block explorer
+}
+
+// SyncParticipation is whether a member signed at all of its positions in a block's sync aggregate
+type SyncParticipation struct {
+	Slot   models.Slot `json:"slot"` // Slot of the block carrying the aggregate (it signs the parent block root)
+	Signed bool        `json:"signed"`
+}
+
+// SyncCommittee is the current sync committee period and its watched members
+type SyncCommittee struct {
+	Period     uint64                `json:"period"`
+	StartEpoch models.Epoch          `json:"start_epoch"`
+	EndEpoch   models.Epoch          `json:"end_epoch"`
+	Members    []SyncCommitteeMember `json:"members"`
+}
+
+// syncSlot is the participation of the watched members in one block's sync aggregate
+type syncSlot struct {
+	slot   models.Slot
+	signed map[models.ValidatorIndex]bool
+}
+
+// UpdateSyncCommittee replaces the current sync committee; participation of a previous period is dropped
+func (s *Server) UpdateSyncCommittee(committee SyncCommittee) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	members := make([]SyncCommitteeMember, len(committee.Members))
+	copy(members, committee.Members)
+	for i := range members {
+		if s.pubkeys != nil {
+			members[i].Pubkey = s.pubkeys(members[i].Pubkey)
+		}
+		members[i].ExplorerURL = s.explorer.Validator(members[i].ValidatorIndex)
+		members[i].ValidatorIndex = s.index(members[i].ValidatorIndex)
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].ValidatorIndex < members[j].ValidatorIndex })
+	committee.Members = members
+
+	if s.syncCommittee == nil || s.syncCommittee.Period != committee.Period {
+		s.syncSlots = nil
+	}
+	s.syncCommittee = &committee
+}
+
+// AddSyncParticipation records the watched members' participation in a block's sync aggregate
+func (s *Server) AddSyncParticipation(slot models.Slot, signed map[models.ValidatorIndex]bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.indices != nil {
+		mapped := make(map[models.ValidatorIndex]bool, len(signed))
+		for index, ok := range signed {
+			mapped[s.indices(index)] = ok
+		}
+		signed = mapped
+	}
+	s.syncSlots = append(s.syncSlots, syncSlot{slot: slot, signed: signed})
+	if len(s.syncSlots) > SyncParticipationSlots {
+		s.syncSlots = s.syncSlots[len(s.syncSlots)-SyncParticipationSlots:]
+	}
+}
+
+// handleSyncCommittee returns the watched members of the current sync committee and its period
+// With ?detail=true each member lists its participation in recent blocks
+func (s *Server) handleSyncCommittee(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	detail := r.URL.Query().Get("detail") == "true"
+
+	s.mu.RLock()
+	if s.syncCommittee == nil {
+		s.mu.RUnlock()
+		writeError(w, http.StatusServiceUnavailable, "sync committee not available")
+		return
+	}
+	committee := *s.syncCommittee
+	committee.Members = make([]SyncCommitteeMember, len(s.syncCommittee.Members))
+	copy(committee.Members, s.syncCommittee.Members)
+	if detail {
+		for i := range committee.Members {
+			member := &committee.Members[i]
+			member.Participation = []SyncParticipation{}
+			for _, slot := range s.syncSlots {
+				if signed, ok := slot.signed[member.ValidatorIndex]; ok {
+					member.Participation = append(member.Participation, SyncParticipation{Slot: slot.slot, Signed: signed})
+				}
+			}
+		}
+	}
+	s.mu.RUnlock()
+
+	writeJSON(w, http.StatusOK, response{Data: committee})
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+func TestProposalsEndpoint(t *testing.T) {
+	server := newTestServer()
+	server.SetPubkeyMapper(func(pubkey string) string { return "anon-" + pubkey[2:] })
+	server.UpdateProposals([]ProposalDuty{
+		{Slot: 420, ValidatorIndex: 2, Pubkey: "0xbb", Labels: []string{"operator:b"}},
+		{Slot: 410, ValidatorIndex: 1, Pubkey: "0xaa", Labels: []string{"operator:a"}},
+	})
+	mux := http.NewServeMux()
+	server.Register(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/duties/proposals", nil))
+
+	var body struct {
+		Data []ProposalDuty `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(body.Data) != 2 || body.Data[0].Slot != 410 || body.Data[0].Pubkey != "anon-aa" {
+		t.Errorf("Expected proposals sorted by slot with mapped pubkeys, got %+v", body.Data)
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/duties/proposals?label=operator:b", nil))
+	body.Data = nil
+	json.Unmarshal(rec.Body.Bytes(), &body)
+	if len(body.Data) != 1 || body.Data[0].ValidatorIndex != 2 {
+		t.Errorf("Expected only operator:b's proposal, got %+v", body.Data)
+	}
+}
+
+func TestSyncCommitteeEndpoint(t *testing.T) {
+	server := newTestServer()
+	mux := http.NewServeMux()
+	server.Register(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/duties/sync_committee", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 before the committee is known, got %d", rec.Code)
+	}
+
+	server.UpdateSyncCommittee(SyncCommittee{Period: 3, StartEpoch: 768, EndEpoch: 1023, Members: []SyncCommitteeMember{
+		{ValidatorIndex: 9, Positions: []int{4}},
+		{ValidatorIndex: 5, Positions: []int{1, 300}},
+	}})
+	server.AddSyncParticipation(24600, map[models.ValidatorIndex]bool{5: true, 9: false})
+	server.AddSyncParticipation(24601, map[models.ValidatorIndex]bool{5: false, 9: true})
+
+	var body struct {
+		Data SyncCommittee `json:"data"`
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/duties/sync_committee", nil))
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if body.Data.Period != 3 || len(body.Data.Members) != 2 || body.Data.Members[0].ValidatorIndex != 5 {
+		t.Fatalf("Unexpected committee: %+v", body.Data)
+	}
+	if body.Data.Members[0].Participation != nil {
+		t.Errorf("Participation listed without detail: %+v", body.Data.Members[0].Participation)
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/duties/sync_committee?detail=true", nil))
+	body.Data = SyncCommittee{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	participation := body.Data.Members[0].Participation
+	if len(participation) != 2 || !participation[0].Signed || participation[1].Signed || participation[1].Slot != 24601 {
+		t.Errorf("Unexpected participation of validator 5: %+v", participation)
+	}
+
+	// A new period drops the previous period's participation
+	server.UpdateSyncCommittee(SyncCommittee{Period: 4, StartEpoch: 1024, EndEpoch: 1279})
+	if len(server.syncSlots) != 0 {
+		t.Errorf("Expected participation to be reset for a new period, got %d slots", len(server.syncSlots))
+	}
+}
@@ -0,0 +1,192 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// networkLabel is the network-wide aggregate, the same on every shard of a network
+const networkLabel = "scope:all-network"
+
+// Shard is a watcher instance whose label summaries are combined in the federated view
+type Shard struct {
+	Name      string         `json:"name"`
+	URL       string         `json:"url,omitempty"`   // Peer API, empty for this instance
+	UpdatedAt time.Time      `json:"updated_at"`      // When its summaries were last fetched
+	Error     string         `json:"error,omitempty"` // Last fetch error; the previous summaries are still combined
+	Labels    int            `json:"labels"`          // Labels it contributed
+	Summaries []LabelSummary `json:"-"`
+}
+
+// Federation is the combined view of this instance and its peers
+type Federation struct {
+	Shards []Shard        `json:"shards"`
+	Labels []LabelSummary `json:"labels"`
+}
+
+// federation is the federated view state: this instance's shard name and the peers' last summaries
+type federation struct {
+	name  string
+	peers []Shard
+}
+
+// EnableFederation serves the combined view of this instance (as the named shard) and its peers
+func (s *Server) EnableFederation(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.federation = &federation{name: name}
+}
+
+// UpdateFederation replaces the peers' last fetched summaries
+func (s *Server) UpdateFederation(peers []Shard) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.federation != nil {
+		s.federation.peers = peers
+	}
+}
+
+// localSummaries returns this instance's label summaries sorted by label; the caller holds s.mu
+func (s *Server) localSummaries() []LabelSummary {
+	summaries := make([]LabelSummary, 0, len(s.metricsByLabel))
+	for _, m := range s.metricsByLabel {
+		summaries = append(summaries, NewLabelSummary(m))
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Label < summaries[j].Label })
+	return summaries
+}
+
+// handleSummaries returns the summary of every label, which peers federate
+func (s *Server) handleSummaries(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	s.mu.RLock()
+	summaries := s.localSummaries()
+	s.mu.RUnlock()
+
+	writeJSON(w, http.StatusOK, response{Data: summaries})
+}
+
+// handleFederation returns the label summaries combined across this instance and its peers
+// Optional query parameter: label (repeatable) to only return those labels
+func (s *Server) handleFederation(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	s.mu.RLock()
+	if s.federation == nil {
+		s.mu.RUnlock()
+		writeError(w, http.StatusServiceUnavailable, "federation not configured")
+		return
+	}
+	local := Shard{Name: s.federation.name, UpdatedAt: time.Now(), Summaries: s.localSummaries()}
+	shards := append([]Shard{local}, s.federation.peers...)
+	s.mu.RUnlock()
+
+	sets := make([][]LabelSummary, len(shards))
+	for i := range shards {
+		shards[i].Labels = len(shards[i].Summaries)
+		sets[i] = shards[i].Summaries
+	}
+	labels := MergeLabelSummaries(sets...)
+
+	if wanted := splitValues(r.URL.Query()["label"]); len(wanted) > 0 {
+		filtered := make([]LabelSummary, 0, len(wanted))
+		for _, summary := range labels {
+			if hasLabels(wanted, []string{summary.Label}) {
+				filtered = append(filtered, summary)
+			}
+		}
+		labels = filtered
+	}
+
+	writeJSON(w, http.StatusOK, response{Data: Federation{Shards: shards, Labels: labels}})
+}
+
+// MergeLabelSummaries combines the label summaries of several shards, sorted by label
+// Counts and totals are summed, maxima kept and rates recomputed from the totals.
+// The network-wide aggregate is the same on every shard, so the first one is kept as is
+func MergeLabelSummaries(sets ...[]LabelSummary) []LabelSummary {
+	merged := make(map[string]*LabelSummary)
+	for _, set := range sets {
+		for _, summary := range set {
+			m, ok := merged[summary.Label]
+			if !ok {
+				copied := summary
+				copied.StatusCounts = make(map[models.ValidatorStatus]int, len(summary.StatusCounts))
+				for status, count := range summary.StatusCounts {
+					copied.StatusCounts[status] = count
+				}
+				merged[summary.Label] = &copied
+				continue
+			}
+			if summary.Label == networkLabel {
+				continue
+			}
+			mergeLabelSummary(m, summary)
+		}
+	}
+
+	result := make([]LabelSummary, 0, len(merged))
+	for _, m := range merged {
+		if m.AttestationDuties > 0 {
+			m.AttestationDutiesRate = float64(m.AttestationDutiesSuccess) / float64(m.AttestationDuties)
+		}
+		if m.IdealConsensusRewards > 0 {
+			m.ConsensusRewardsRate = float64(m.ConsensusRewards) / float64(m.IdealConsensusRewards)
+		}
+		if m.InclusionDelayCount > 0 {
+			m.InclusionDelayAvg = float64(m.InclusionDelaySum) / float64(m.InclusionDelayCount)
+		}
+		result = append(result, *m)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Label < result[j].Label })
+	return result
+}
+
+// mergeLabelSummary adds one shard's summary of a label into the combined one
+func mergeLabelSummary(m *LabelSummary, s LabelSummary) {
+	m.Validators += s.Validators
+	m.Stake += s.Stake
+	for status, count := range s.StatusCounts {
+		m.StatusCounts[status] += count
+	}
+	m.Slashed += s.Slashed
+	m.Balance += s.Balance
+	m.EffectiveBalance += s.EffectiveBalance
+	m.FundedValidators += s.FundedValidators
+	m.AttestationDuties += s.AttestationDuties
+	m.AttestationDutiesSuccess += s.AttestationDutiesSuccess
+	m.MissedAttestations += s.MissedAttestations
+	m.SuboptimalSourceVotes += s.SuboptimalSourceVotes
+	m.SuboptimalTargetVotes += s.SuboptimalTargetVotes
+	m.SuboptimalHeadVotes += s.SuboptimalHeadVotes
+	if s.MaxConsecutiveMissed > m.MaxConsecutiveMissed {
+		m.MaxConsecutiveMissed = s.MaxConsecutiveMissed
+	}
+	m.ProposedBlocks += s.ProposedBlocks
+	m.MissedBlocks += s.MissedBlocks
+	m.ProposedBlocksFinalized += s.ProposedBlocksFinalized
+	m.MissedBlocksFinalized += s.MissedBlocksFinalized
+	m.FutureBlockProposals += s.FutureBlockProposals
+	m.IdealConsensusRewards += s.IdealConsensusRewards
+	m.ConsensusRewards += s.ConsensusRewards
+	m.ExpectedAggregations += s.ExpectedAggregations
+	m.CommitteeAggregatesIncluded += s.CommitteeAggregatesIncluded
+	m.CommitteeAggregatesMissed += s.CommitteeAggregatesMissed
+	m.InclusionDelaySum += s.InclusionDelaySum
+	m.InclusionDelayCount += s.InclusionDelayCount
+	if s.MaxInclusionDelay > m.MaxInclusionDelay {
+		m.MaxInclusionDelay = s.MaxInclusionDelay
+	}
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMergeLabelSummaries(t *testing.T) {
+	a := []LabelSummary{
+		{Label: "operator:a", Validators: 2, AttestationDuties: 10, AttestationDutiesSuccess: 9, MaxConsecutiveMissed: 1, InclusionDelaySum: 4, InclusionDelayCount: 4},
+		{Label: "scope:all-network", Validators: 1000},
+	}
+	b := []LabelSummary{
+		{Label: "operator:a", Validators: 3, AttestationDuties: 10, AttestationDutiesSuccess: 7, MaxConsecutiveMissed: 3, InclusionDelaySum: 8, InclusionDelayCount: 2},
+		{Label: "operator:b", Validators: 1},
+		{Label: "scope:all-network", Validators: 1000},
+	}
+
+	merged := MergeLabelSummaries(a, b)
+	if len(merged) != 3 || merged[0].Label != "operator:a" || merged[1].Label != "operator:b" {
+		t.Fatalf("Unexpected labels: %+v", merged)
+	}
+	got := merged[0]
+	if got.Validators != 5 || got.AttestationDutiesRate != 0.8 || got.MaxConsecutiveMissed != 3 || got.InclusionDelayAvg != 2 {
+		t.Errorf("Unexpected merged summary: %+v", got)
+	}
+	if merged[2].Validators != 1000 {
+		t.Errorf("Network aggregate was summed across shards: %d validators", merged[2].Validators)
+	}
+}
+
+func TestFederationEndpoint(t *testing.T) {
+	server := newTestServer()
+	mux := http.NewServeMux()
+	server.Register(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/federation/labels", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Expected 503 without federation, got %d", rec.Code)
+	}
+
+	server.EnableFederation("shard-1")
+	server.UpdateFederation([]Shard{{
+		Name:      "shard-2",
+		Summaries: []LabelSummary{{Label: "operator:a", Validators: 3, AttestationDuties: 10, AttestationDutiesSuccess: 7}},
+	}})
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/federation/labels?label=operator:a", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+
+	var body struct {
+		Data Federation `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(body.Data.Shards) != 2 || body.Data.Shards[0].Name != "shard-1" || body.Data.Shards[0].Labels != 2 || body.Data.Shards[1].Labels != 1 {
+		t.Errorf("Unexpected shards: %+v", body.Data.Shards)
+	}
+	if len(body.Data.Labels) != 1 || body.Data.Labels[0].Validators != 5 || body.Data.Labels[0].AttestationDutiesRate != 0.8 {
+		t.Errorf("Unexpected labels: %+v", body.Data.Labels)
+	}
+}
+
+func TestSummariesEndpoint(t *testing.T) {
+	server := newTestServer()
+	mux := http.NewServeMux()
+	server.Register(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/summaries", nil))
+
+	var body struct {
+		Data []LabelSummary `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(body.Data) != 2 || body.Data[0].Label != "operator:a" || body.Data[0].Validators != 2 {
+		t.Errorf("Unexpected summaries: %+v", body.Data)
+	}
+}
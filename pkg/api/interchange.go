@@ -0,0 +1,48 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/interchange"
+)
+
+// SetInterchange sets the signing history served by the EIP-3076 export endpoint
+func (s *Server) SetInterchange(history *interchange.History, genesisValidatorsRoot string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.signingHistory = history
+	s.genesisValidatorsRoot = genesisValidatorsRoot
+}
+
+// handleInterchange exports the signing history observed on chain in EIP-3076 interchange format
+// Optional query parameter: pubkey (repeat or comma-separate for several)
+// The document is returned as is (not wrapped in data), so it can be saved and compared directly
+func (s *Server) handleInterchange(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	s.mu.RLock()
+	history, root, anonymized := s.signingHistory, s.genesisValidatorsRoot, s.pubkeys != nil
+	s.mu.RUnlock()
+
+	// The export is only useful with real pubkeys, which privacy mode never publishes
+	if anonymized {
+		writeError(w, http.StatusForbidden, "interchange export is disabled in privacy mode")
+		return
+	}
+	if history == nil {
+		writeError(w, http.StatusServiceUnavailable, "signing history not available")
+		return
+	}
+
+	pubkeys := splitValues(r.URL.Query()["pubkey"])
+	for i, pubkey := range pubkeys {
+		pubkeys[i] = strings.ToLower(pubkey)
+	}
+
+	writeJSON(w, http.StatusOK, history.Export(root, pubkeys))
+}
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/metrics"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// LabelSummary is the aggregate of the watched validators carrying a label
+// Counters cover the current epoch, like the validator listing
+type LabelSummary struct {
+	Label                       string                         `json:"label"`
+	Validators                  int                            `json:"validators"`
+	Stake                       float64                        `json:"stake"` // In full validator units (32 ETH on mainnet)
+	StatusCounts                map[models.ValidatorStatus]int `json:"status_counts"`
+	Slashed                     int                            `json:"slashed"`
+	Balance                     models.Gwei                    `json:"balance"` // Summed over the validators not yet withdrawn
+	EffectiveBalance            models.Gwei                    `json:"effective_balance"`
+	FundedValidators            int                            `json:"funded_validators"` // Validators not yet withdrawn, behind the balance averages
+	AttestationDuties           uint64                         `json:"attestation_duties"`
+	AttestationDutiesSuccess    uint64                         `json:"attestation_duties_success"`
+	AttestationDutiesRate       float64                        `json:"attestation_duties_rate"`
+	MissedAttestations          uint64                         `json:"missed_attestations"` // Liveness misses
+	SuboptimalSourceVotes       uint64                         `json:"suboptimal_source_votes"`
+	SuboptimalTargetVotes       uint64                         `json:"suboptimal_target_votes"`
+	SuboptimalHeadVotes         uint64                         `json:"suboptimal_head_votes"`
+	MaxConsecutiveMissed        uint64                         `json:"max_consecutive_missed"`
+	ProposedBlocks              uint64                         `json:"proposed_blocks"`
+	MissedBlocks                uint64                         `json:"missed_blocks"`
+	ProposedBlocksFinalized     uint64                         `json:"proposed_blocks_finalized"`
+	MissedBlocksFinalized       uint64                         `json:"missed_blocks_finalized"`
+	FutureBlockProposals        uint64                         `json:"future_block_proposals"`
+	IdealConsensusRewards       models.Gwei                    `json:"ideal_consensus_rewards"`
+	ConsensusRewards            models.SignedGwei              `json:"consensus_rewards"`
+	ConsensusRewardsRate        float64                        `json:"consensus_rewards_rate"`
+	ExpectedAggregations        float64                        `json:"expected_aggregations"`
+	CommitteeAggregatesIncluded uint64                         `json:"committee_aggregates_included"`
+	CommitteeAggregatesMissed   uint64                         `json:"committee_aggregates_missed"`
+	InclusionDelayAvg           float64                        `json:"inclusion_delay_avg"`
+	InclusionDelaySum           uint64                         `json:"inclusion_delay_sum"` // Sum and count behind the average, so shards can be combined
+	InclusionDelayCount         uint64                         `json:"inclusion_delay_count"`
+	MaxInclusionDelay           uint64                         `json:"max_inclusion_delay"`
+}
+
+// NewLabelSummary copies the served fields of a label's metrics
+func NewLabelSummary(m *metrics.MetricsByLabel) LabelSummary {
+	return LabelSummary{
+		Label:                       m.Label,
+		Validators:                  m.ValidatorCount,
+		Stake:                       m.StakeCount,
+		StatusCounts:                m.StatusCounts,
+		Slashed:                     m.SlashedCount,
+		Balance:                     m.Balance,
+		EffectiveBalance:            m.EffectiveBalance,
+		FundedValidators:            m.FundedCount,
+		AttestationDuties:           m.AttestationDuties,
+		AttestationDutiesSuccess:    m.AttestationDutiesSuccess,
+		AttestationDutiesRate:       m.AttestationDutiesRate,
+		MissedAttestations:          m.MissedAttestations,
+		SuboptimalSourceVotes:       m.SuboptimalSourceVotes,
+		SuboptimalTargetVotes:       m.SuboptimalTargetVotes,
+		SuboptimalHeadVotes:         m.SuboptimalHeadVotes,
+		MaxConsecutiveMissed:        m.MaxConsecutiveMissed,
+		ProposedBlocks:              m.ProposedBlocks,
+		MissedBlocks:                m.MissedBlocks,
+		ProposedBlocksFinalized:     m.ProposedBlocksFinalized,
+		MissedBlocksFinalized:       m.MissedBlocksFinalized,
+		FutureBlockProposals:        m.FutureBlockProposals,
+		IdealConsensusRewards:       m.IdealConsensusRewards,
+		ConsensusRewards:            m.ConsensusRewards,
+		ConsensusRewardsRate:        m.ConsensusRewardsRate,
+		ExpectedAggregations:        m.ExpectedAggregations,
+		CommitteeAggregatesIncluded: m.CommitteeAggregatesIncluded,
+		CommitteeAggregatesMissed:   m.CommitteeAggregatesMissed,
+		InclusionDelayAvg:           m.InclusionDelayAvg,
+		InclusionDelaySum:           m.InclusionDelaySum,
+		InclusionDelayCount:         m.InclusionDelayCount,
+		MaxInclusionDelay:           m.MaxInclusionDelay,
+	}
+}
+
+// handleLabels returns the aggregated labels, sorted
+func (s *Server) handleLabels(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	s.mu.RLock()
+	labels := make([]string, 0, len(s.metricsByLabel))
+	for label := range s.metricsByLabel {
+		labels = append(labels, label)
+	}
+	s.mu.RUnlock()
+
+	sort.Strings(labels)
+	writeJSON(w, http.StatusOK, response{Data: labels})
+}
+
+// handleLabelSummary returns the aggregate of one label at /api/v1/labels/{label}/summary,
+// and hands /api/v1/labels/{label}/trend to handleLabelTrend
+func (s *Server) handleLabelSummary(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/api/v1/labels/")
+	if path == "" {
+		s.handleLabels(w, r)
+		return
+	}
+	if label, ok := strings.CutSuffix(path, "/trend"); ok && label != "" {
+		s.handleLabelTrend(w, r, label)
+		return
+	}
+	label, ok := strings.CutSuffix(path, "/summary")
+	if !ok || label == "" {
+		writeError(w, http.StatusNotFound, "not found: "+r.URL.Path)
+		return
+	}
+
+	s.mu.RLock()
+	m, ok := s.metricsByLabel[label]
+	var summary LabelSummary
+	if ok {
+		summary = NewLabelSummary(m)
+	}
+	s.mu.RUnlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "label not found: "+label)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, response{Data: summary})
+}
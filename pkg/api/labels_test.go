@@ -0,0 +1,108 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/store"
+)
+
+// fakeTrend serves a fixed trend for operator:a
+type fakeTrend map[string][]store.TrendPoint
+
+func (f fakeTrend) Trend(label, resolution string) ([]store.TrendPoint, error) {
+	if label != "operator:a" {
+		return []store.TrendPoint{}, nil
+	}
+	return f[resolution], nil
+}
+
+func TestLabelSummaryEndpoint(t *testing.T) {
+	server := newTestServer()
+	mux := http.NewServeMux()
+	server.Register(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/labels/operator:a/summary", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+
+	var body struct {
+		Data LabelSummary `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if body.Data.Label != "operator:a" || body.Data.Validators != 2 || body.Data.AttestationDutiesRate != 0.9 {
+		t.Errorf("Unexpected summary: %+v", body.Data)
+	}
+
+	for path, want := range map[string]int{
+		"/api/v1/labels/operator:missing/summary": http.StatusNotFound,
+		"/api/v1/labels/operator:a":               http.StatusNotFound,
+	} {
+		rec = httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != want {
+			t.Errorf("%s: expected %d, got %d", path, want, rec.Code)
+		}
+	}
+}
+
+func TestLabelTrendEndpoint(t *testing.T) {
+	server := newTestServer()
+	mux := http.NewServeMux()
+	server.Register(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/labels/operator:a/trend", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Expected 503 without a state file, got %d", rec.Code)
+	}
+
+	server.SetTrend(fakeTrend{
+		store.ResolutionEpoch: {{FirstEpoch: 7, LastEpoch: 7, Epochs: 1}},
+		store.ResolutionDay:   {{FirstEpoch: 0, LastEpoch: 224, Epochs: 225}},
+	})
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/labels/operator:a/trend?resolution=day", nil))
+
+	var body struct {
+		Data []store.TrendPoint `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(body.Data) != 1 || body.Data[0].Epochs != 225 {
+		t.Errorf("Unexpected daily trend: %+v", body.Data)
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/labels/operator:a/trend?resolution=hour", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unknown resolution, got %d", rec.Code)
+	}
+}
+
+func TestLabelsEndpoint(t *testing.T) {
+	server := newTestServer()
+	mux := http.NewServeMux()
+	server.Register(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/labels", nil))
+
+	var body struct {
+		Data []string `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(body.Data) != 2 || body.Data[0] != "operator:a" || body.Data[1] != "scope:all-network" {
+		t.Errorf("Expected sorted labels, got %v", body.Data)
+	}
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/membership"
+)
+
+// DefaultMembershipLimit is the page size of the membership feed
+const DefaultMembershipLimit = 1000
+
+// MaxMembershipLimit caps the changes returned at once
+const MaxMembershipLimit = 10000
+
+// SetMembership sets the feed served by the membership changes endpoint
+func (s *Server) SetMembership(feed *membership.Feed) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.membership = feed
+}
+
+// handleMembership returns the membership changes after a sequence number, oldest first
+// Optional query parameters: since (last seq already consumed), label and limit
+func (s *Server) handleMembership(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	query := r.URL.Query()
+	var since uint64
+	if value := query.Get("since"); value != "" {
+		parsed, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "since must be a non-negative integer")
+			return
+		}
+		since = parsed
+	}
+	limit, err := positiveInt(query.Get("limit"), DefaultMembershipLimit)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "limit: "+err.Error())
+		return
+	}
+	limit = min(limit, MaxMembershipLimit)
+
+	s.mu.RLock()
+	feed := s.membership
+	s.mu.RUnlock()
+
+	if feed == nil {
+		writeError(w, http.StatusServiceUnavailable, "membership feed not available")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, response{Data: feed.Since(since, query.Get("label"), limit)})
+}
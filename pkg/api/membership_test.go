@@ -0,0 +1,50 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/membership"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+func TestMembershipEndpoint(t *testing.T) {
+	server := newTestServer()
+	mux := http.NewServeMux()
+	server.Register(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/membership/changes", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Expected 503 without a feed, got %d", rec.Code)
+	}
+
+	feed := membership.NewFeed(membership.DefaultRetention)
+	feed.Observe(5, []membership.Member{
+		{Validator: models.Validator{Index: 1}, Labels: []string{"operator:a"}},
+		{Validator: models.Validator{Index: 2}, Labels: []string{"operator:b"}},
+	}, time.Now())
+	server.SetMembership(feed)
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/membership/changes?since=0&label=operator:b", nil))
+
+	var body struct {
+		Data []membership.Change `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(body.Data) != 1 || body.Data[0].ValidatorIndex != 2 || body.Data[0].Kind != membership.KindAdded {
+		t.Errorf("Unexpected changes: %+v", body.Data)
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/membership/changes?since=x", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid since, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/queues"
+)
+
+// QueuePosition is a watched validator's place in the activation or exit queue
+type QueuePosition struct {
+	queues.Position
+	Labels []string `json:"labels"`
+}
+
+// Queues is the network's queue estimates and the watched validators waiting in a queue
+type Queues struct {
+	Epoch      models.Epoch    `json:"epoch"`
+	Network    *queues.Network `json:"network"` // null unless the full validator set is loaded
+	Validators []QueuePosition `json:"validators"`
+}
+
+// UpdateQueues replaces the queue positions of watched validators
+func (s *Server) UpdateQueues(q Queues) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if q.Validators == nil {
+		q.Validators = []QueuePosition{}
+	}
+	if s.indices != nil {
+		positions := make([]QueuePosition, len(q.Validators))
+		for i, p := range q.Validators {
+			p.ValidatorIndex = s.indices(p.ValidatorIndex)
+			positions[i] = p
+		}
+		q.Validators = positions
+	}
+	s.queues = &q
+}
+
+// queuePosition returns a validator's queue position, nil if it isn't queued; the caller holds the lock
+func (s *Server) queuePosition(index models.ValidatorIndex) *queues.Position {
+	if s.queues == nil {
+		return nil
+	}
+	for _, p := range s.queues.Validators {
+		if p.ValidatorIndex == index {
+			position := p.Position
+			return &position
+		}
+	}
+	return nil
+}
+
+// handleQueues returns the activation and exit queue estimates
+// Optional query parameter: label (repeat or comma-separate to require several)
+func (s *Server) handleQueues(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	labels := splitValues(r.URL.Query()["label"])
+
+	s.mu.RLock()
+	snapshot := s.queues
+	s.mu.RUnlock()
+
+	if snapshot == nil {
+		writeError(w, http.StatusServiceUnavailable, "queues not available yet")
+		return
+	}
+
+	result := *snapshot
+	result.Validators = make([]QueuePosition, 0, len(snapshot.Validators))
+	for _, p := range snapshot.Validators {
+		if hasLabels(p.Labels, labels) {
+			result.Validators = append(result.Validators, p)
+		}
+	}
+	writeJSON(w, http.StatusOK, response{Data: result})
+}
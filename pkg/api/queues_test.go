@@ -0,0 +1,52 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/queues"
+)
+
+func TestQueuesEndpoint(t *testing.T) {
+	server := newTestServer()
+	mux := http.NewServeMux()
+	server.Register(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/queues", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 before the queues are estimated, got %d", rec.Code)
+	}
+
+	server.UpdateQueues(Queues{
+		Epoch:   100,
+		Network: &queues.Network{ActivationEpochs: 12, ExitEpochs: 5},
+		Validators: []QueuePosition{
+			{Position: queues.Position{ValidatorIndex: 1, Queue: queues.QueueActivation, ETAEpochs: 9}, Labels: []string{"operator:a"}},
+			{Position: queues.Position{ValidatorIndex: 2, Queue: queues.QueueExit, ETAEpochs: 260, Scheduled: true}, Labels: []string{"operator:b"}},
+		},
+	})
+
+	var body struct {
+		Data Queues `json:"data"`
+	}
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/queues?label=operator:b", nil))
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if body.Data.Network == nil || body.Data.Network.ExitEpochs != 5 {
+		t.Errorf("Expected the network estimates, got %+v", body.Data.Network)
+	}
+	if len(body.Data.Validators) != 1 || body.Data.Validators[0].ValidatorIndex != 2 {
+		t.Errorf("Expected only operator:b's validator, got %+v", body.Data.Validators)
+	}
+	if position := server.queuePosition(1); position == nil || position.ETAEpochs != 9 {
+		t.Errorf("Expected validator 1's position, got %+v", position)
+	}
+	if position := server.queuePosition(3); position != nil {
+		t.Errorf("Expected no position for an unqueued validator, got %+v", position)
+	}
+}
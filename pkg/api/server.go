@@ -0,0 +1,237 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/explorer"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/heatmap"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/interchange"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/membership"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/metrics"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/validator"
+	"github.com/sirupsen/logrus"
+)
+
+// Server serves the watcher's JSON API alongside /metrics
+// It reads from snapshots pushed by the watcher after each metrics update
+type Server struct {
+	mu                    sync.RWMutex
+	metricsByLabel        map[string]*metrics.MetricsByLabel
+	validators            []ValidatorSummary
+	details               map[models.ValidatorIndex]ValidatorDetails
+	proposals             []ProposalDuty // Upcoming proposals of watched validators, earliest first
+	queues                *Queues        // Activation and exit queue positions, nil until estimated
+	liveness              livenessTracker
+	syncCommittee         *SyncCommittee // Current sync committee, nil until known
+	syncSlots             []syncSlot     // Recent sync aggregate participation, oldest first
+	federation            *federation    // Peers' summaries, nil unless federation is configured
+	membership            *membership.Feed
+	scorecardWeights      map[string]float64
+	heatmap               *heatmap.Tracker
+	trend                 TrendSource // Per-label trend, nil unless the state file is set
+	signingHistory        *interchange.History
+	genesisValidatorsRoot string
+	pubkeys               func(string) string                               // Maps listed pubkeys (anonymization), nil to keep them
+	indices               func(models.ValidatorIndex) models.ValidatorIndex // Maps listed validator indices, nil to keep them
+	explorer              *explorer.Explorer                                // Links listed validators and proposals, nil for none
+	logger                *logrus.Logger
+}
+
+// response wraps API payloads the same way the beacon API does
+type response struct {
+	Data interface{} `json:"data"`
+	Meta *PageMeta   `json:"meta,omitempty"`
+}
+
+// errorResponse is returned for failed requests
+type errorResponse struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// NewServer creates a new API server
+func NewServer(scorecardWeights map[string]float64, logger *logrus.Logger) *Server {
+	return &Server{
+		metricsByLabel:   make(map[string]*metrics.MetricsByLabel),
+		scorecardWeights: scorecardWeights,
+		logger:           logger,
+	}
+}
+
+// UpdateMetrics replaces the per-label metrics snapshot served by the API
+func (s *Server) UpdateMetrics(metricsByLabel map[string]*metrics.MetricsByLabel) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.metricsByLabel = metricsByLabel
+}
+
+// UpdateValidators replaces the watched validators snapshot served by the listing endpoint
+func (s *Server) UpdateValidators(watched []*validator.WatchedValidator) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	validators := make([]ValidatorSummary, len(watched))
+	details := make(map[models.ValidatorIndex]ValidatorDetails, len(watched))
+	for i, v := range watched {
+		detail := NewValidatorDetails(v)
+		if s.pubkeys != nil {
+			detail.Pubkey = s.pubkeys(detail.Pubkey)
+		}
+		detail.ExplorerURL = s.explorer.Validator(v.Index)
+		detail.Index = s.index(v.Index)
+		validators[i] = detail.ValidatorSummary
+		details[detail.Index] = detail
+	}
+
+	s.validators = validators
+	s.details = details
+}
+
+// SetPubkeyMapper sets a function applied to the pubkeys of subsequently listed validators
+func (s *Server) SetPubkeyMapper(fn func(string) string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pubkeys = fn
+}
+
+// SetIndexMapper sets a function applied to the validator indices of everything subsequently
+// recorded; the API then only knows the mapped indices, which validator lookups take too
+func (s *Server) SetIndexMapper(fn func(models.ValidatorIndex) models.ValidatorIndex) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.indices = fn
+}
+
+// index maps a recorded validator index; the caller holds the lock
+func (s *Server) index(index models.ValidatorIndex) models.ValidatorIndex {
+	if s.indices == nil {
+		return index
+	}
+	return s.indices(index)
+}
+
+// SetExplorer sets the explorer linked from subsequently listed validators and proposals
+func (s *Server) SetExplorer(e *explorer.Explorer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.explorer = e
+}
+
+// SetHeatmap sets the tracker served by the heatmap endpoint
+func (s *Server) SetHeatmap(tracker *heatmap.Tracker) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.heatmap = tracker
+}
+
+// Register adds the API routes to a mux
+func (s *Server) Register(mux *http.ServeMux) {
+	for _, endpoint := range s.endpoints() {
+		if endpoint.pattern == "" {
+			continue
+		}
+		mux.HandleFunc(endpoint.pattern, versioned(endpoint))
+	}
+}
+
+// handleHeatmap returns per-validator attestation outcome bitmaps for recent epochs
+// Optional query parameters: epochs (window length) and label (only validators carrying it)
+func (s *Server) handleHeatmap(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	epochs := 0
+	if value := r.URL.Query().Get("epochs"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			writeError(w, http.StatusBadRequest, "epochs must be a positive integer")
+			return
+		}
+		epochs = parsed
+	}
+
+	s.mu.RLock()
+	tracker, indices := s.heatmap, s.indices
+	s.mu.RUnlock()
+
+	if tracker == nil {
+		writeError(w, http.StatusServiceUnavailable, "heatmap not available")
+		return
+	}
+
+	snapshot := tracker.Snapshot(epochs, r.URL.Query().Get("label"))
+	if indices != nil {
+		for i := range snapshot.Validators {
+			snapshot.Validators[i].Index = indices(snapshot.Validators[i].Index)
+		}
+	}
+	writeJSON(w, http.StatusOK, response{Data: snapshot})
+}
+
+// handleScorecards returns the composite scorecard of every label
+func (s *Server) handleScorecards(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	s.mu.RLock()
+	cards := metrics.ComputeScorecards(s.metricsByLabel, s.scorecardWeights)
+	s.mu.RUnlock()
+
+	writeJSON(w, http.StatusOK, response{Data: cards})
+}
+
+// handleScorecard returns the composite scorecard of a single label
+func (s *Server) handleScorecard(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	label := strings.TrimPrefix(r.URL.Path, "/api/v1/scorecards/")
+	if label == "" {
+		s.handleScorecards(w, r)
+		return
+	}
+
+	s.mu.RLock()
+	m, ok := s.metricsByLabel[label]
+	network := s.metricsByLabel["scope:all-network"]
+	var card *metrics.Scorecard
+	if ok {
+		card = metrics.ComputeScorecard(m, network, s.scorecardWeights)
+	}
+	s.mu.RUnlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "label not found: "+label)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, response{Data: card})
+}
+
+// writeJSON writes a JSON response with the given status code
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes a JSON error response
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, errorResponse{Code: status, Message: message})
+}
@@ -0,0 +1,148 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/heatmap"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/interchange"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/metrics"
+	"github.com/sirupsen/logrus"
+)
+
+func newTestServer() *Server {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	server := NewServer(metrics.DefaultScorecardWeights(), logger)
+	server.UpdateMetrics(map[string]*metrics.MetricsByLabel{
+		"operator:a": {
+			Label:                    "operator:a",
+			ValidatorCount:           2,
+			AttestationDuties:        10,
+			AttestationDutiesSuccess: 9,
+			AttestationDutiesRate:    0.9,
+		},
+		"scope:all-network": {
+			Label:          "scope:all-network",
+			ValidatorCount: 1000,
+		},
+	})
+	return server
+}
+
+func TestScorecardsEndpoint(t *testing.T) {
+	server := newTestServer()
+	mux := http.NewServeMux()
+	server.Register(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/scorecards", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+
+	var body struct {
+		Data []metrics.Scorecard `json:"data"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	// The network scope is a baseline, not a scored label
+	if len(body.Data) != 1 {
+		t.Fatalf("Expected 1 scorecard, got %d", len(body.Data))
+	}
+	if body.Data[0].Label != "operator:a" {
+		t.Errorf("Expected label operator:a, got %s", body.Data[0].Label)
+	}
+	if body.Data[0].Score == nil || *body.Data[0].Score != 90 {
+		t.Errorf("Expected score 90, got %v", body.Data[0].Score)
+	}
+}
+
+func TestScorecardByLabel(t *testing.T) {
+	server := newTestServer()
+	mux := http.NewServeMux()
+	server.Register(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/scorecards/operator:a", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/scorecards/operator:missing", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for unknown label, got %d", rec.Code)
+	}
+}
+
+func TestHeatmapEndpoint(t *testing.T) {
+	server := newTestServer()
+	tracker := heatmap.New(4)
+	tracker.Record(7, []string{"operator:a"}, 100, true)
+	tracker.Record(8, []string{"operator:b"}, 100, false)
+	server.SetHeatmap(tracker)
+
+	mux := http.NewServeMux()
+	server.Register(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/heatmap?epochs=1&label=operator:a", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+
+	var body struct {
+		Data heatmap.Heatmap `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if body.Data.StartEpoch != 100 || len(body.Data.Validators) != 1 || body.Data.Validators[0].Missed != "0x01" {
+		t.Errorf("Unexpected heatmap: %+v", body.Data)
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/heatmap?epochs=abc", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid epochs, got %d", rec.Code)
+	}
+}
+
+func TestInterchangeEndpoint(t *testing.T) {
+	server := newTestServer()
+	history := interchange.NewHistory()
+	history.RecordAttestation("0xaa", 10, 11)
+	history.RecordAttestation("0xbb", 10, 11)
+	server.SetInterchange(history, "0x4b36")
+
+	mux := http.NewServeMux()
+	server.Register(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/interchange?pubkey=0xAA", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+
+	var doc interchange.Interchange
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if doc.Metadata.GenesisValidatorsRoot != "0x4b36" || len(doc.Data) != 1 || doc.Data[0].Pubkey != "0xaa" {
+		t.Errorf("Unexpected interchange document: %+v", doc)
+	}
+
+	server.SetPubkeyMapper(func(pubkey string) string { return "anon" })
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/interchange", nil))
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 in privacy mode, got %d", rec.Code)
+	}
+}
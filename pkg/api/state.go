@@ -0,0 +1,119 @@
+package api
+
+import (
+	"sort"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/metrics"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// State is the data the API serves from the watcher's snapshots, exported so a read-only
+// replica can serve it without a beacon node
+// Heatmap, membership, federation, trend and signing history are not part of it
+type State struct {
+	Labels           map[string]*metrics.MetricsByLabel     `json:"labels"`
+	Validators       []ValidatorDetails                     `json:"validators"`
+	Proposals        []ProposalDuty                         `json:"proposals"`
+	Queues           *Queues                                `json:"queues,omitempty"`
+	LivenessEpoch    *models.Epoch                          `json:"liveness_epoch,omitempty"` // null until liveness was checked
+	Live             map[models.ValidatorIndex]bool         `json:"live,omitempty"`
+	LastLive         map[models.ValidatorIndex]models.Epoch `json:"last_live,omitempty"`
+	SyncCommittee    *SyncCommittee                         `json:"sync_committee,omitempty"` // Members carry their recent participation
+	ScorecardWeights map[string]float64                     `json:"scorecard_weights"`
+}
+
+// State exports what the API currently serves
+func (s *Server) State() State {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	state := State{
+		Labels:           s.metricsByLabel,
+		Validators:       make([]ValidatorDetails, 0, len(s.details)),
+		Proposals:        s.proposals,
+		Queues:           s.queues,
+		ScorecardWeights: s.scorecardWeights,
+	}
+	for _, summary := range s.validators {
+		if details, ok := s.details[summary.Index]; ok {
+			state.Validators = append(state.Validators, details)
+		}
+	}
+	if s.liveness.checked {
+		epoch := s.liveness.epoch
+		state.LivenessEpoch = &epoch
+		state.Live = s.liveness.live
+		state.LastLive = make(map[models.ValidatorIndex]models.Epoch, len(s.liveness.lastLive)) // Updated in place
+		for index, epoch := range s.liveness.lastLive {
+			state.LastLive[index] = epoch
+		}
+	}
+	if s.syncCommittee != nil {
+		committee := *s.syncCommittee
+		committee.Members = make([]SyncCommitteeMember, len(s.syncCommittee.Members))
+		copy(committee.Members, s.syncCommittee.Members)
+		for i := range committee.Members {
+			member := &committee.Members[i]
+			member.Participation = []SyncParticipation{}
+			for _, slot := range s.syncSlots {
+				if signed, ok := slot.signed[member.ValidatorIndex]; ok {
+					member.Participation = append(member.Participation, SyncParticipation{Slot: slot.slot, Signed: signed})
+				}
+			}
+		}
+		state.SyncCommittee = &committee
+	}
+	return state
+}
+
+// Restore replaces what the API serves with an exported state
+// Pubkeys and indices are served as exported; the mappers only apply to live updates
+func (s *Server) Restore(state State) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.metricsByLabel = state.Labels
+	if s.metricsByLabel == nil {
+		s.metricsByLabel = make(map[string]*metrics.MetricsByLabel)
+	}
+	if state.ScorecardWeights != nil {
+		s.scorecardWeights = state.ScorecardWeights
+	}
+
+	s.validators = make([]ValidatorSummary, len(state.Validators))
+	s.details = make(map[models.ValidatorIndex]ValidatorDetails, len(state.Validators))
+	for i, details := range state.Validators {
+		details.Liveness, details.UpcomingProposals, details.Queue = nil, nil, nil
+		s.validators[i] = details.ValidatorSummary
+		s.details[details.Index] = details
+	}
+	s.proposals = state.Proposals
+	s.queues = state.Queues
+
+	s.liveness = livenessTracker{}
+	if state.LivenessEpoch != nil {
+		s.liveness = livenessTracker{checked: true, epoch: *state.LivenessEpoch, live: state.Live, lastLive: state.LastLive}
+	}
+
+	s.syncCommittee, s.syncSlots = nil, nil
+	if state.SyncCommittee != nil {
+		committee := *state.SyncCommittee
+		committee.Members = make([]SyncCommitteeMember, len(state.SyncCommittee.Members))
+		bySlot := make(map[models.Slot]map[models.ValidatorIndex]bool)
+		for i, member := range state.SyncCommittee.Members {
+			for _, p := range member.Participation {
+				if bySlot[p.Slot] == nil {
+					bySlot[p.Slot] = make(map[models.ValidatorIndex]bool)
+				}
+				bySlot[p.Slot][member.ValidatorIndex] = p.Signed
+			}
+			member.Participation = nil
+			committee.Members[i] = member
+		}
+		for slot, signed := range bySlot {
+			s.syncSlots = append(s.syncSlots, syncSlot{slot: slot, signed: signed})
+		}
+		sort.Slice(s.syncSlots, func(i, j int) bool { return s.syncSlots[i].slot < s.syncSlots[j].slot })
+		s.syncCommittee = &committee
+	}
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/store"
+)
+
+// TrendSource reads the per-label trend, implemented by the state store
+type TrendSource interface {
+	Trend(label, resolution string) ([]store.TrendPoint, error)
+}
+
+// SetTrend sets the source served by the label trend endpoint
+func (s *Server) SetTrend(source TrendSource) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.trend = source
+}
+
+// handleLabelTrend returns a label's trend at /api/v1/labels/{label}/trend, oldest first
+// Optional query parameter: resolution (epoch, the default, or day)
+func (s *Server) handleLabelTrend(w http.ResponseWriter, r *http.Request, label string) {
+	resolution := r.URL.Query().Get("resolution")
+	switch resolution {
+	case "":
+		resolution = store.ResolutionEpoch
+	case store.ResolutionEpoch, store.ResolutionDay:
+	default:
+		writeError(w, http.StatusBadRequest, "resolution must be epoch or day")
+		return
+	}
+
+	s.mu.RLock()
+	source := s.trend
+	s.mu.RUnlock()
+
+	if source == nil {
+		writeError(w, http.StatusServiceUnavailable, "trend not available (state_file is not set)")
+		return
+	}
+
+	points, err := source.Trend(label, resolution)
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to read trend")
+		writeError(w, http.StatusInternalServerError, "failed to read trend")
+		return
+	}
+	writeJSON(w, http.StatusOK, response{Data: points})
+}
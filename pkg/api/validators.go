@@ -0,0 +1,373 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/queues"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/validator"
+)
+
+const (
+	// DefaultPerPage is the page size of validator listings when none is requested
+	DefaultPerPage = 100
+	// MaxPerPage caps the page size so a single request can't serialize the whole watched set
+	MaxPerPage = 1000
+)
+
+// Sort keys of validator listings
+const (
+	SortIndex             = "index"
+	SortMisses            = "misses"
+	SortConsecutiveMisses = "consecutive_misses"
+	SortPerformance       = "performance"
+	SortBalance           = "balance"
+)
+
+// ValidatorSummary is a watched validator as listed by the API
+type ValidatorSummary struct {
+	Index                   models.ValidatorIndex  `json:"index"`
+	Pubkey                  string                 `json:"pubkey"`
+	Status                  models.ValidatorStatus `json:"status"`
+	Labels                  []string               `json:"labels"`
+	EffectiveBalance        models.Gwei            `json:"effective_balance"`
+	AttestationDuties       uint64                 `json:"attestation_duties"`
+	MissedAttestationDuties uint64                 `json:"missed_attestation_duties"`
+	MissedAttestations      uint64                 `json:"missed_attestations"` // Liveness misses
+	ConsecutiveMissed       uint64                 `json:"consecutive_missed"`
+	ProposedBlocks          uint64                 `json:"proposed_blocks"`
+	MissedBlocks            uint64                 `json:"missed_blocks"`
+	Performance             *float64               `json:"performance"`            // Actual / ideal consensus rewards, null before rewards are known
+	ExplorerURL             string                 `json:"explorer_url,omitempty"` // Validator page on the block explorer
+}
+
+// NewValidatorSummary copies the listed fields of a watched validator
+func NewValidatorSummary(v *validator.WatchedValidator) ValidatorSummary {
+	summary := ValidatorSummary{
+		Index:                   v.Index,
+		Pubkey:                  v.Data.Pubkey,
+		Status:                  v.Status,
+		Labels:                  v.Labels,
+		EffectiveBalance:        v.Data.EffectiveBalance,
+		AttestationDuties:       v.AttestationDuties,
+		MissedAttestationDuties: v.AttestationDuties - v.AttestationDutiesSuccess,
+		MissedAttestations:      v.MissedAttestations,
+		ConsecutiveMissed:       v.ConsecutiveMissedAttest,
+		ProposedBlocks:          v.ProposedBlocks,
+		MissedBlocks:            v.MissedBlocks,
+	}
+	if v.IdealConsensusRewards > 0 {
+		performance := float64(v.ConsensusRewards) / float64(v.IdealConsensusRewards)
+		summary.Performance = &performance
+	}
+	return summary
+}
+
+// ValidatorDetails is a watched validator with all of its counters, as returned by /api/v1/validators/{index}
+type ValidatorDetails struct {
+	ValidatorSummary
+	Balance                     models.Gwei       `json:"balance"`
+	WithdrawalCredentials       string            `json:"withdrawal_credentials"`
+	Slashed                     bool              `json:"slashed"`
+	ActivationEpoch             models.Epoch      `json:"activation_epoch"`
+	ExitEpoch                   models.Epoch      `json:"exit_epoch"`
+	SuboptimalSourceVotes       uint64            `json:"suboptimal_source_votes"`
+	SuboptimalTargetVotes       uint64            `json:"suboptimal_target_votes"`
+	SuboptimalHeadVotes         uint64            `json:"suboptimal_head_votes"`
+	IdealConsensusRewards       models.Gwei       `json:"ideal_consensus_rewards"`
+	ConsensusRewards            models.SignedGwei `json:"consensus_rewards"`
+	ProposedBlocksFinalized     uint64            `json:"proposed_blocks_finalized"`
+	MissedBlocksFinalized       uint64            `json:"missed_blocks_finalized"`
+	FutureBlockProposals        uint64            `json:"future_block_proposals"`
+	ExpectedAggregations        float64           `json:"expected_aggregations"`
+	CommitteeAggregatesIncluded uint64            `json:"committee_aggregates_included"`
+	CommitteeAggregatesMissed   uint64            `json:"committee_aggregates_missed"`
+	InclusionDelayAvg           *float64          `json:"inclusion_delay_avg"` // null until an attestation was included
+	MaxInclusionDelay           uint64            `json:"max_inclusion_delay"`
+	Liveness                    *Liveness         `json:"liveness"`           // null until liveness was checked
+	UpcomingProposals           []models.Slot     `json:"upcoming_proposals"` // Scheduled slots after the last processed one
+	Queue                       *queues.Position  `json:"queue"`              // null unless waiting for activation or to become withdrawable
+}
+
+// NewValidatorDetails copies every counter of a watched validator
+func NewValidatorDetails(v *validator.WatchedValidator) ValidatorDetails {
+	details := ValidatorDetails{
+		ValidatorSummary:            NewValidatorSummary(v),
+		Balance:                     v.Balance,
+		WithdrawalCredentials:       v.Data.WithdrawalCredentials,
+		Slashed:                     v.Data.Slashed,
+		ActivationEpoch:             v.Data.ActivationEpoch,
+		ExitEpoch:                   v.Data.ExitEpoch,
+		SuboptimalSourceVotes:       v.SuboptimalSourceVotes,
+		SuboptimalTargetVotes:       v.SuboptimalTargetVotes,
+		SuboptimalHeadVotes:         v.SuboptimalHeadVotes,
+		IdealConsensusRewards:       v.IdealConsensusRewards,
+		ConsensusRewards:            v.ConsensusRewards,
+		ProposedBlocksFinalized:     v.ProposedBlocksFinalized,
+		MissedBlocksFinalized:       v.MissedBlocksFinalized,
+		FutureBlockProposals:        v.FutureBlockProposals,
+		ExpectedAggregations:        v.ExpectedAggregations,
+		CommitteeAggregatesIncluded: v.CommitteeAggregatesIncluded,
+		CommitteeAggregatesMissed:   v.CommitteeAggregatesMissed,
+		MaxInclusionDelay:           v.MaxInclusionDelay,
+		UpcomingProposals:           []models.Slot{},
+	}
+	if v.InclusionDelayCount > 0 {
+		avg := float64(v.InclusionDelaySum) / float64(v.InclusionDelayCount)
+		details.InclusionDelayAvg = &avg
+	}
+	return details
+}
+
+// misses is the total of missed duties used to sort by misses
+func (v *ValidatorSummary) misses() uint64 {
+	return v.MissedAttestationDuties + v.MissedAttestations + v.MissedBlocks
+}
+
+// ValidatorQuery selects a page of the validator listing
+type ValidatorQuery struct {
+	Statuses             []string // Exact statuses or their prefix (e.g. "active" matches active_ongoing)
+	Labels               []string // Validators must carry all of them
+	MinConsecutiveMissed uint64
+	Sort                 string
+	Desc                 bool
+	Page                 int // 1-based
+	PerPage              int
+}
+
+// PageMeta describes the page returned by a listing
+type PageMeta struct {
+	Total   int `json:"total"`
+	Page    int `json:"page"`
+	PerPage int `json:"per_page"`
+	Pages   int `json:"pages"`
+}
+
+// ParseValidatorQuery parses listing query parameters:
+// status, label (repeatable, comma-separated), min_consecutive_missed, sort, order, page and per_page
+func ParseValidatorQuery(values url.Values) (ValidatorQuery, error) {
+	query := ValidatorQuery{
+		Statuses: splitValues(values["status"]),
+		Labels:   splitValues(values["label"]),
+		Sort:     SortIndex,
+		Page:     1,
+		PerPage:  DefaultPerPage,
+	}
+
+	if value := values.Get("min_consecutive_missed"); value != "" {
+		parsed, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return query, fmt.Errorf("min_consecutive_missed must be a non-negative integer")
+		}
+		query.MinConsecutiveMissed = parsed
+	}
+
+	if value := values.Get("sort"); value != "" {
+		switch value {
+		case SortIndex, SortMisses, SortConsecutiveMisses, SortPerformance, SortBalance:
+			query.Sort = value
+		default:
+			return query, fmt.Errorf("unknown sort %q (index, misses, consecutive_misses, performance, balance)", value)
+		}
+	}
+
+	// Worst first by default: most misses, lowest performance
+	query.Desc = query.Sort == SortMisses || query.Sort == SortConsecutiveMisses
+	switch values.Get("order") {
+	case "":
+	case "asc":
+		query.Desc = false
+	case "desc":
+		query.Desc = true
+	default:
+		return query, fmt.Errorf("order must be asc or desc")
+	}
+
+	var err error
+	if query.Page, err = positiveInt(values.Get("page"), 1); err != nil {
+		return query, fmt.Errorf("page must be a positive integer")
+	}
+	if query.PerPage, err = positiveInt(values.Get("per_page"), DefaultPerPage); err != nil {
+		return query, fmt.Errorf("per_page must be a positive integer")
+	}
+	if query.PerPage > MaxPerPage {
+		query.PerPage = MaxPerPage
+	}
+
+	return query, nil
+}
+
+// Apply filters and sorts the validators and returns the requested page
+// The input slice is not modified
+func (q ValidatorQuery) Apply(validators []ValidatorSummary) ([]ValidatorSummary, PageMeta) {
+	matched := make([]ValidatorSummary, 0, len(validators))
+	for _, v := range validators {
+		if q.matches(&v) {
+			matched = append(matched, v)
+		}
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		a, b := &matched[i], &matched[j]
+		if q.Desc {
+			a, b = b, a
+		}
+		if less, equal := q.compare(a, b); !equal {
+			return less
+		}
+		return matched[i].Index < matched[j].Index
+	})
+
+	meta := PageMeta{
+		Total:   len(matched),
+		Page:    q.Page,
+		PerPage: q.PerPage,
+		Pages:   (len(matched) + q.PerPage - 1) / q.PerPage,
+	}
+
+	// Pages past the last are empty; checking before multiplying keeps a huge page from overflowing
+	if q.Page-1 >= meta.Pages {
+		return []ValidatorSummary{}, meta
+	}
+	start := (q.Page - 1) * q.PerPage
+	end := start + q.PerPage
+	if end > len(matched) {
+		end = len(matched)
+	}
+	return matched[start:end], meta
+}
+
+// matches reports whether a validator passes the query filters
+func (q ValidatorQuery) matches(v *ValidatorSummary) bool {
+	if v.ConsecutiveMissed < q.MinConsecutiveMissed {
+		return false
+	}
+
+	if len(q.Statuses) > 0 {
+		found := false
+		for _, status := range q.Statuses {
+			if string(v.Status) == status || strings.HasPrefix(string(v.Status), status+"_") {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return hasLabels(v.Labels, q.Labels)
+}
+
+// compare orders a before b by the sort key; equal values fall back to the index
+// Validators without a performance value sort after those with one in ascending order
+func (q ValidatorQuery) compare(a, b *ValidatorSummary) (less, equal bool) {
+	switch q.Sort {
+	case SortMisses:
+		return a.misses() < b.misses(), a.misses() == b.misses()
+	case SortConsecutiveMisses:
+		return a.ConsecutiveMissed < b.ConsecutiveMissed, a.ConsecutiveMissed == b.ConsecutiveMissed
+	case SortBalance:
+		return a.EffectiveBalance < b.EffectiveBalance, a.EffectiveBalance == b.EffectiveBalance
+	case SortPerformance:
+		switch {
+		case a.Performance == nil || b.Performance == nil:
+			return b.Performance == nil && a.Performance != nil, a.Performance == nil && b.Performance == nil
+		default:
+			return *a.Performance < *b.Performance, *a.Performance == *b.Performance
+		}
+	default:
+		return a.Index < b.Index, a.Index == b.Index
+	}
+}
+
+// handleValidators returns a filtered, sorted page of the watched validators
+func (s *Server) handleValidators(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	query, err := ParseValidatorQuery(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.RLock()
+	validators := s.validators
+	s.mu.RUnlock()
+
+	page, meta := query.Apply(validators)
+	writeJSON(w, http.StatusOK, response{Data: page, Meta: &meta})
+}
+
+// handleValidator returns one watched validator by index with its counters, liveness and
+// upcoming proposals
+func (s *Server) handleValidator(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	value := strings.TrimPrefix(r.URL.Path, "/api/v1/validators/")
+	if value == "" {
+		s.handleValidators(w, r)
+		return
+	}
+	index, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "validator index must be a non-negative integer")
+		return
+	}
+
+	s.mu.RLock()
+	details, ok := s.details[models.ValidatorIndex(index)]
+	if ok {
+		details.Liveness = s.liveness.of(details.Index)
+		details.Queue = s.queuePosition(details.Index)
+		for _, proposal := range s.proposals {
+			if proposal.ValidatorIndex == details.Index {
+				details.UpcomingProposals = append(details.UpcomingProposals, proposal.Slot)
+			}
+		}
+	}
+	s.mu.RUnlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("validator %d is not watched", index))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, response{Data: details})
+}
+
+// splitValues flattens repeated and comma-separated query values
+func splitValues(values []string) []string {
+	var result []string
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				result = append(result, part)
+			}
+		}
+	}
+	return result
+}
+
+// positiveInt parses a positive integer, returning fallback for an empty value
+func positiveInt(value string, fallback int) (int, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed <= 0 {
+		return 0, fmt.Errorf("invalid positive integer %q", value)
+	}
+	return parsed, nil
+}
@@ -0,0 +1,206 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/explorer"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/validator"
+)
+
+func testValidators() []ValidatorSummary {
+	performance := func(p float64) *float64 { return &p }
+	return []ValidatorSummary{
+		{Index: 1, Status: models.StatusActiveOngoing, Labels: []string{"operator:a"}, MissedAttestationDuties: 2, ConsecutiveMissed: 2, Performance: performance(0.5)},
+		{Index: 2, Status: models.StatusActiveOngoing, Labels: []string{"operator:b"}, Performance: performance(1)},
+		{Index: 3, Status: models.StatusActiveExiting, Labels: []string{"operator:a"}, MissedBlocks: 1, ConsecutiveMissed: 0},
+		{Index: 4, Status: models.StatusExitedUnslashed, Labels: []string{"operator:a"}, MissedAttestations: 5, ConsecutiveMissed: 5, Performance: performance(0.9)},
+	}
+}
+
+func indices(validators []ValidatorSummary) []models.ValidatorIndex {
+	result := make([]models.ValidatorIndex, len(validators))
+	for i, v := range validators {
+		result[i] = v.Index
+	}
+	return result
+}
+
+func TestValidatorQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected []models.ValidatorIndex
+		total    int
+	}{
+		{"default order", "", []models.ValidatorIndex{1, 2, 3, 4}, 4},
+		{"status prefix", "status=active", []models.ValidatorIndex{1, 2, 3}, 3},
+		{"label and status", "label=operator:a&status=active_ongoing,exited_unslashed", []models.ValidatorIndex{1, 4}, 2},
+		{"min consecutive misses", "min_consecutive_missed=2", []models.ValidatorIndex{1, 4}, 2},
+		{"misses worst first", "sort=misses", []models.ValidatorIndex{4, 1, 3, 2}, 4},
+		{"performance worst first, unknown last", "sort=performance", []models.ValidatorIndex{1, 4, 2, 3}, 4},
+		{"descending performance", "sort=performance&order=desc", []models.ValidatorIndex{3, 2, 4, 1}, 4},
+		{"second page", "per_page=3&page=2", []models.ValidatorIndex{4}, 4},
+		{"past the last page", "per_page=3&page=3", []models.ValidatorIndex{}, 4},
+		{"page overflowing the offset", "per_page=1000&page=9223372036854775807", []models.ValidatorIndex{}, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, _ := url.ParseQuery(tt.query)
+			query, err := ParseValidatorQuery(values)
+			if err != nil {
+				t.Fatalf("Failed to parse query: %v", err)
+			}
+
+			page, meta := query.Apply(testValidators())
+			got := indices(page)
+			if len(got) != len(tt.expected) {
+				t.Fatalf("Expected %v, got %v", tt.expected, got)
+			}
+			for i := range got {
+				if got[i] != tt.expected[i] {
+					t.Fatalf("Expected %v, got %v", tt.expected, got)
+				}
+			}
+			if meta.Total != tt.total {
+				t.Errorf("Expected total %d, got %d", tt.total, meta.Total)
+			}
+		})
+	}
+}
+
+func TestValidatorQueryErrors(t *testing.T) {
+	for _, query := range []string{"sort=name", "order=up", "page=0", "per_page=-1", "min_consecutive_missed=x"} {
+		values, _ := url.ParseQuery(query)
+		if _, err := ParseValidatorQuery(values); err == nil {
+			t.Errorf("Expected an error for %q", query)
+		}
+	}
+
+	values, _ := url.ParseQuery("per_page=100000")
+	query, err := ParseValidatorQuery(values)
+	if err != nil || query.PerPage != MaxPerPage {
+		t.Errorf("Expected per_page to be capped at %d, got %d (%v)", MaxPerPage, query.PerPage, err)
+	}
+}
+
+func TestValidatorsEndpoint(t *testing.T) {
+	server := newTestServer()
+	server.validators = testValidators()
+	mux := http.NewServeMux()
+	server.Register(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/validators?label=operator:a&per_page=2", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+
+	var body struct {
+		Data []ValidatorSummary `json:"data"`
+		Meta PageMeta           `json:"meta"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(body.Data) != 2 || body.Meta.Total != 3 || body.Meta.Pages != 2 {
+		t.Errorf("Unexpected page: %d validators, meta %+v", len(body.Data), body.Meta)
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/validators?sort=name", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for unknown sort, got %d", rec.Code)
+	}
+}
+
+func TestUpdateValidatorsMapsPubkeys(t *testing.T) {
+	server := newTestServer()
+	server.SetPubkeyMapper(func(pubkey string) string { return "anon-" + pubkey[2:] })
+
+	v := &validator.WatchedValidator{}
+	v.Index = 9
+	v.Data.Pubkey = "0xabc"
+	server.UpdateValidators([]*validator.WatchedValidator{v})
+
+	if server.validators[0].Pubkey != "anon-abc" {
+		t.Errorf("Expected mapped pubkey, got %q", server.validators[0].Pubkey)
+	}
+}
+
+func TestUpdateLinksExplorer(t *testing.T) {
+	server := newTestServer()
+	server.SetExplorer(explorer.New("https://explorer.devnet.example", explorer.DefaultPaths))
+
+	v := &validator.WatchedValidator{}
+	v.Index = 9
+	server.UpdateValidators([]*validator.WatchedValidator{v})
+	server.UpdateProposals([]ProposalDuty{{Slot: 400, ValidatorIndex: 9}})
+	server.UpdateSyncCommittee(SyncCommittee{Members: []SyncCommitteeMember{{ValidatorIndex: 9}}})
+
+	if url := server.details[9].ExplorerURL; url != "https://explorer.devnet.example/validator/9" {
+		t.Errorf("Expected a validator link, got %q", url)
+	}
+	if url := server.proposals[0].ExplorerURL; url != "https://explorer.devnet.example/slot/400" {
+		t.Errorf("Expected a slot link, got %q", url)
+	}
+	if url := server.syncCommittee.Members[0].ExplorerURL; url != "https://explorer.devnet.example/validator/9" {
+		t.Errorf("Expected a sync committee member link, got %q", url)
+	}
+}
+
+func TestValidatorEndpoint(t *testing.T) {
+	server := newTestServer()
+	v := &validator.WatchedValidator{InclusionDelaySum: 3, InclusionDelayCount: 2, SuboptimalHeadVotes: 1}
+	v.Index = 7
+	v.Status = models.StatusActiveOngoing
+	server.UpdateValidators([]*validator.WatchedValidator{v})
+	server.UpdateLiveness(10, map[models.ValidatorIndex]bool{7: true})
+	server.UpdateLiveness(11, map[models.ValidatorIndex]bool{7: false})
+	server.UpdateProposals([]ProposalDuty{{Slot: 400, ValidatorIndex: 7}, {Slot: 390, ValidatorIndex: 8}})
+
+	mux := http.NewServeMux()
+	server.Register(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/validators/7", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+
+	var body struct {
+		Data ValidatorDetails `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	details := body.Data
+	if details.Index != 7 || details.SuboptimalHeadVotes != 1 {
+		t.Errorf("Unexpected details: %+v", details)
+	}
+	if details.InclusionDelayAvg == nil || *details.InclusionDelayAvg != 1.5 {
+		t.Errorf("Expected inclusion delay avg 1.5, got %v", details.InclusionDelayAvg)
+	}
+	if l := details.Liveness; l == nil || l.CheckedEpoch != 11 || l.Live || l.LastLiveEpoch == nil || *l.LastLiveEpoch != 10 {
+		t.Errorf("Expected not live in epoch 11, last live in 10, got %+v", l)
+	}
+	if len(details.UpcomingProposals) != 1 || details.UpcomingProposals[0] != 400 {
+		t.Errorf("Expected upcoming proposal at slot 400, got %v", details.UpcomingProposals)
+	}
+
+	for path, want := range map[string]int{
+		"/api/v1/validators/8":   http.StatusNotFound,
+		"/api/v1/validators/abc": http.StatusBadRequest,
+	} {
+		rec = httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != want {
+			t.Errorf("%s: expected %d, got %d", path, want, rec.Code)
+		}
+	}
+}
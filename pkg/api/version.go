@@ -0,0 +1,223 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// Version is the API's major version; breaking changes get a new /api/v{N} path prefix
+const Version = "1"
+
+// VersionHeader carries the API version of every response
+// Clients may send it to pin the version they were written against; other versions get a 406
+const VersionHeader = "X-API-Version"
+
+// Deprecation marks an endpoint that is going away
+// Responses carry the Deprecation (RFC 9745), Sunset (RFC 8594) and successor Link headers
+type Deprecation struct {
+	Since     time.Time `json:"since"`
+	Sunset    time.Time `json:"sunset,omitempty"`    // When the endpoint is removed, zero if not yet decided
+	Successor string    `json:"successor,omitempty"` // Path of the replacement endpoint
+	Note      string    `json:"note,omitempty"`
+}
+
+// Parameter documents an endpoint parameter in the schema
+type Parameter struct {
+	Name        string `json:"name"`
+	In          string `json:"in"` // path or query
+	Description string `json:"description"`
+}
+
+// Endpoint is a route of the API and its schema entry
+type Endpoint struct {
+	Method      string       `json:"method"`
+	Path        string       `json:"path"`
+	Description string       `json:"description"`
+	Parameters  []Parameter  `json:"parameters,omitempty"`
+	Deprecated  *Deprecation `json:"deprecated,omitempty"`
+
+	pattern string // ServeMux pattern the endpoint is registered under, empty if another endpoint's pattern covers it
+	handler http.HandlerFunc
+}
+
+// Schema is the machine-readable description of the API served by /api/v1/schema
+type Schema struct {
+	Version   string     `json:"version"`
+	Endpoints []Endpoint `json:"endpoints"`
+}
+
+// endpoints returns every route of the API
+func (s *Server) endpoints() []Endpoint {
+	labelParam := Parameter{Name: "label", In: "query", Description: "Only items carrying the label (repeat or comma-separate to require several)"}
+
+	endpoints := []Endpoint{
+		{
+			Path:        "/api/v1/scorecards",
+			Description: "Composite 0-100 scorecard of every label",
+			pattern:     "/api/v1/scorecards",
+			handler:     s.handleScorecards,
+		},
+		{
+			Path:        "/api/v1/scorecards/{label}",
+			Description: "Composite 0-100 scorecard of one label",
+			Parameters:  []Parameter{{Name: "label", In: "path", Description: "Label, e.g. operator:foo"}},
+			pattern:     "/api/v1/scorecards/",
+			handler:     s.handleScorecard,
+		},
+		{
+			Path:        "/api/v1/heatmap",
+			Description: "Per-validator attestation duty and miss bitmaps of recent epochs",
+			Parameters: []Parameter{
+				{Name: "epochs", In: "query", Description: "Window length in epochs"},
+				{Name: "label", In: "query", Description: "Only validators carrying the label"},
+			},
+			pattern: "/api/v1/heatmap",
+			handler: s.handleHeatmap,
+		},
+		{
+			Path:        "/api/v1/validators",
+			Description: "Filtered, sorted page of the watched validators",
+			Parameters: []Parameter{
+				{Name: "status", In: "query", Description: "Exact status or prefix (e.g. active)"},
+				labelParam,
+				{Name: "min_consecutive_missed", In: "query", Description: "Minimum consecutive missed attestations"},
+				{Name: "sort", In: "query", Description: "index, misses, consecutive_misses, performance or balance"},
+				{Name: "order", In: "query", Description: "asc or desc"},
+				{Name: "page", In: "query", Description: "1-based page number"},
+				{Name: "per_page", In: "query", Description: "Page size (max 1000)"},
+			},
+			pattern: "/api/v1/validators",
+			handler: s.handleValidators,
+		},
+		{
+			Path:        "/api/v1/validators/{index}",
+			Description: "One watched validator with all counters, liveness and upcoming proposals",
+			Parameters:  []Parameter{{Name: "index", In: "path", Description: "Validator index"}},
+			pattern:     "/api/v1/validators/",
+			handler:     s.handleValidator,
+		},
+		{
+			Path:        "/api/v1/labels",
+			Description: "Aggregated labels",
+			pattern:     "/api/v1/labels",
+			handler:     s.handleLabels,
+		},
+		{
+			Path:        "/api/v1/labels/{label}/summary",
+			Description: "Aggregate of the watched validators carrying a label",
+			Parameters:  []Parameter{{Name: "label", In: "path", Description: "Label, e.g. operator:foo"}},
+			pattern:     "/api/v1/labels/",
+			handler:     s.handleLabelSummary,
+		},
+		{
+			Path:        "/api/v1/labels/{label}/trend",
+			Description: "What a label gained per epoch, or per UTC day once compacted, from the state file; oldest first",
+			Parameters: []Parameter{
+				{Name: "label", In: "path", Description: "Label, e.g. operator:foo"},
+				{Name: "resolution", In: "query", Description: "epoch (default) or day"},
+			},
+			handler: s.handleLabelSummary, // Served under the summary's pattern
+		},
+		{
+			Path:        "/api/v1/duties/proposals",
+			Description: "Upcoming block proposals of watched validators, earliest first",
+			Parameters:  []Parameter{labelParam},
+			pattern:     "/api/v1/duties/proposals",
+			handler:     s.handleProposals,
+		},
+		{
+			Path:        "/api/v1/duties/sync_committee",
+			Description: "Watched validators in the current sync committee and the period boundaries",
+			Parameters:  []Parameter{{Name: "detail", In: "query", Description: "true to list each member's participation in recent blocks"}},
+			pattern:     "/api/v1/duties/sync_committee",
+			handler:     s.handleSyncCommittee,
+		},
+		{
+			Path:        "/api/v1/queues",
+			Description: "Activation and exit queue estimates, with the positions and ETAs of queued watched validators",
+			Parameters:  []Parameter{labelParam},
+			pattern:     "/api/v1/queues",
+			handler:     s.handleQueues,
+		},
+		{
+			Path:        "/api/v1/membership/changes",
+			Description: "Append-only feed of label membership changes (added, removed, activated, exited, slashed, withdrawn), oldest first",
+			Parameters: []Parameter{
+				{Name: "since", In: "query", Description: "Only changes after this seq"},
+				{Name: "label", In: "query", Description: "Only changes of this label"},
+				{Name: "limit", In: "query", Description: "Maximum changes returned (default 1000, max 10000)"},
+			},
+			pattern: "/api/v1/membership/changes",
+			handler: s.handleMembership,
+		},
+		{
+			Path:        "/api/v1/summaries",
+			Description: "Summary of every label, as federated by peer instances",
+			pattern:     "/api/v1/summaries",
+			handler:     s.handleSummaries,
+		},
+		{
+			Path:        "/api/v1/federation/labels",
+			Description: "Label summaries combined across this instance and its federation peers, with each shard's status",
+			Parameters:  []Parameter{{Name: "label", In: "query", Description: "Only these labels (repeat or comma-separate)"}},
+			pattern:     "/api/v1/federation/labels",
+			handler:     s.handleFederation,
+		},
+		{
+			Path:        "/api/v1/interchange",
+			Description: "Observed signing history in EIP-3076 interchange format",
+			Parameters:  []Parameter{{Name: "pubkey", In: "query", Description: "Only these validators (repeatable)"}},
+			pattern:     "/api/v1/interchange",
+			handler:     s.handleInterchange,
+		},
+		{
+			Path:        "/api/v1/schema",
+			Description: "This schema",
+			pattern:     "/api/v1/schema",
+			handler:     s.handleSchema,
+		},
+	}
+
+	for i := range endpoints {
+		endpoints[i].Method = http.MethodGet
+	}
+	return endpoints
+}
+
+// versioned wraps an endpoint's handler with version negotiation and deprecation headers
+func versioned(endpoint Endpoint) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(VersionHeader, Version)
+
+		if requested := r.Header.Get(VersionHeader); requested != "" && requested != Version {
+			writeError(w, http.StatusNotAcceptable, fmt.Sprintf("unsupported API version %q (supported: %s)", requested, Version))
+			return
+		}
+
+		if d := endpoint.Deprecated; d != nil {
+			w.Header().Set("Deprecation", fmt.Sprintf("@%d", d.Since.Unix()))
+			if !d.Sunset.IsZero() {
+				w.Header().Set("Sunset", d.Sunset.UTC().Format(http.TimeFormat))
+			}
+			if d.Successor != "" {
+				w.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", d.Successor))
+			}
+		}
+
+		endpoint.handler(w, r)
+	}
+}
+
+// handleSchema returns the machine-readable description of every endpoint
+func (s *Server) handleSchema(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	endpoints := s.endpoints()
+	sort.Slice(endpoints, func(i, j int) bool { return endpoints[i].Path < endpoints[j].Path })
+	writeJSON(w, http.StatusOK, response{Data: Schema{Version: Version, Endpoints: endpoints}})
+}
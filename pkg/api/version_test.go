@@ -0,0 +1,85 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestVersionNegotiation(t *testing.T) {
+	server := newTestServer()
+	mux := http.NewServeMux()
+	server.Register(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/scorecards", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get(VersionHeader) != Version {
+		t.Fatalf("Expected 200 with version header %s, got %d %q", Version, rec.Code, rec.Header().Get(VersionHeader))
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/scorecards", nil)
+	req.Header.Set(VersionHeader, Version)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected 200 for the supported version, got %d", rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/scorecards", nil)
+	req.Header.Set(VersionHeader, "2")
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotAcceptable {
+		t.Errorf("Expected 406 for an unsupported version, got %d", rec.Code)
+	}
+}
+
+func TestDeprecationHeaders(t *testing.T) {
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	handler := versioned(Endpoint{
+		Deprecated: &Deprecation{Since: since, Sunset: sunset, Successor: "/api/v2/things"},
+		handler:    func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) },
+	})
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/api/v1/things", nil))
+
+	if got := rec.Header().Get("Deprecation"); got != "@1767225600" {
+		t.Errorf("Deprecation = %q, want @1767225600", got)
+	}
+	if got := rec.Header().Get("Sunset"); got != "Wed, 01 Jul 2026 00:00:00 GMT" {
+		t.Errorf("Sunset = %q", got)
+	}
+	if got := rec.Header().Get("Link"); got != `</api/v2/things>; rel="successor-version"` {
+		t.Errorf("Link = %q", got)
+	}
+}
+
+func TestSchemaEndpoint(t *testing.T) {
+	server := newTestServer()
+	mux := http.NewServeMux()
+	server.Register(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/schema", nil))
+
+	var body struct {
+		Data Schema `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if body.Data.Version != Version || len(body.Data.Endpoints) != len(server.endpoints()) {
+		t.Fatalf("Unexpected schema: version %q, %d endpoints", body.Data.Version, len(body.Data.Endpoints))
+	}
+
+	// Every entry documents its method and purpose
+	for _, endpoint := range body.Data.Endpoints {
+		if endpoint.Method != http.MethodGet || endpoint.Description == "" {
+			t.Errorf("Incomplete schema entry: %+v", endpoint)
+		}
+	}
+}
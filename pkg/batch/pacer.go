@@ -0,0 +1,95 @@
+package batch
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Pacer runs batched requests with bounded concurrency and a delay between batch starts
+// so large fleets don't trip beacon node rate limits
+type Pacer struct {
+	Concurrency int           // Batches in flight at once (values below 1 mean 1)
+	Delay       time.Duration // Wait between starting consecutive batches
+}
+
+// Run calls fn for batches 0..n-1 and returns the first error
+// progress, if set, is called with the number of completed batches after each one finishes
+// Remaining batches are not started once a batch fails or ctx is cancelled
+func (p Pacer) Run(ctx context.Context, n int, fn func(ctx context.Context, batch int) error, progress func(done int)) error {
+	concurrency := p.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		done     int
+	)
+	sem := make(chan struct{}, concurrency)
+
+	fail := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+	}
+
+	for i := 0; i < n; i++ {
+		if i > 0 && p.Delay > 0 {
+			select {
+			case <-ctx.Done():
+			case <-time.After(p.Delay):
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+		case sem <- struct{}{}:
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(batch int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := fn(ctx, batch); err != nil {
+				fail(err)
+				return
+			}
+
+			mu.Lock()
+			done++
+			completed := done
+			mu.Unlock()
+			if progress != nil {
+				progress(completed)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+// Count returns the number of batches of size needed for n items
+func Count(n, size int) int {
+	if size < 1 {
+		size = 1
+	}
+	return (n + size - 1) / size
+}
@@ -0,0 +1,74 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRunBoundsConcurrency(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, peak := 0, 0
+	var progress []int
+
+	err := Pacer{Concurrency: 2}.Run(context.Background(), 6, func(ctx context.Context, batch int) error {
+		mu.Lock()
+		inFlight++
+		if inFlight > peak {
+			peak = inFlight
+		}
+		mu.Unlock()
+
+		time.Sleep(5 * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		return nil
+	}, func(done int) {
+		mu.Lock()
+		progress = append(progress, done)
+		mu.Unlock()
+	})
+
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if peak > 2 {
+		t.Errorf("Expected at most 2 batches in flight, got %d", peak)
+	}
+	if len(progress) != 6 {
+		t.Errorf("Expected progress after each of 6 batches, got %v", progress)
+	}
+}
+
+func TestRunDelayAndError(t *testing.T) {
+	start := time.Now()
+	ran := 0
+
+	err := Pacer{Concurrency: 1, Delay: 10 * time.Millisecond}.Run(context.Background(), 5, func(ctx context.Context, batch int) error {
+		ran++
+		if batch == 2 {
+			return errors.New("rate limited")
+		}
+		return nil
+	}, nil)
+
+	if err == nil || err.Error() != "rate limited" {
+		t.Fatalf("Expected the batch error, got %v", err)
+	}
+	if ran != 3 {
+		t.Errorf("Expected batches after the failure not to start, ran %d", ran)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("Expected delays between batch starts, took %v", elapsed)
+	}
+}
+
+func TestCount(t *testing.T) {
+	if Count(0, 100) != 0 || Count(100, 100) != 1 || Count(101, 100) != 2 {
+		t.Error("Unexpected batch counts")
+	}
+}
@@ -0,0 +1,121 @@
+package beacon
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// jwtHeader is the encoded header of the HS256 tokens sent with jwt_secret_file
+var jwtHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// authenticator adds the configured credentials to every request to the beacon nodes
+type authenticator struct {
+	headers   map[string]string
+	bearer    string
+	username  string
+	password  string
+	jwtSecret []byte
+	now       func() time.Time
+}
+
+// ValidateAuth checks that at most one Authorization scheme is configured
+func ValidateAuth(auth models.BeaconAuth) error {
+	schemes := 0
+	if auth.BearerToken != "" {
+		schemes++
+	}
+	if auth.BasicAuth.Username != "" {
+		schemes++
+	}
+	if auth.JWTSecretFile != "" {
+		schemes++
+	}
+	if schemes > 1 {
+		return fmt.Errorf("only one of bearer_token, basic_auth and jwt_secret_file can be set")
+	}
+	if auth.BasicAuth.Password != "" && auth.BasicAuth.Username == "" {
+		return fmt.Errorf("basic_auth.password needs a username")
+	}
+	return nil
+}
+
+// newAuthenticator expands the environment variables of the credentials and reads the JWT secret
+// Returns nil when nothing is configured
+func newAuthenticator(auth models.BeaconAuth) (*authenticator, error) {
+	if len(auth.Headers) == 0 && auth.BearerToken == "" && auth.BasicAuth.Username == "" && auth.JWTSecretFile == "" {
+		return nil, nil
+	}
+
+	a := &authenticator{
+		headers:  make(map[string]string, len(auth.Headers)),
+		bearer:   os.ExpandEnv(auth.BearerToken),
+		username: os.ExpandEnv(auth.BasicAuth.Username),
+		password: os.ExpandEnv(auth.BasicAuth.Password),
+		now:      time.Now,
+	}
+	for name, value := range auth.Headers {
+		a.headers[name] = os.ExpandEnv(value)
+	}
+	if auth.JWTSecretFile != "" {
+		secret, err := readJWTSecret(auth.JWTSecretFile)
+		if err != nil {
+			return nil, err
+		}
+		a.jwtSecret = secret
+	}
+	return a, nil
+}
+
+// readJWTSecret reads a hex-encoded secret, as generated for the Engine API (openssl rand -hex 32)
+func readJWTSecret(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWT secret: %w", err)
+	}
+	secret, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(string(data)), "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("JWT secret is not hex: %w", err)
+	}
+	if len(secret) < 32 {
+		return nil, fmt.Errorf("JWT secret must be at least 32 bytes, got %d", len(secret))
+	}
+	return secret, nil
+}
+
+// apply sets the credentials on a request
+func (a *authenticator) apply(req *http.Request) {
+	if a == nil {
+		return
+	}
+	for name, value := range a.headers {
+		req.Header.Set(name, value)
+	}
+	switch {
+	case a.bearer != "":
+		req.Header.Set("Authorization", "Bearer "+a.bearer)
+	case a.username != "":
+		req.SetBasicAuth(a.username, a.password)
+	case a.jwtSecret != nil:
+		req.Header.Set("Authorization", "Bearer "+a.token())
+	}
+}
+
+// token returns an HS256 JWT issued now; the receiver checks the iat claim is recent
+func (a *authenticator) token() string {
+	claims, _ := json.Marshal(map[string]int64{"iat": a.now().Unix()})
+	unsigned := jwtHeader + "." + base64.RawURLEncoding.EncodeToString(claims)
+
+	mac := hmac.New(sha256.New, a.jwtSecret)
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,149 @@
+package beacon
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+func TestSetAuthHeadersAndBearerToken(t *testing.T) {
+	t.Setenv("PROVIDER_KEY", "key-123")
+
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"genesis_time":"1606824023"}}`))
+	}))
+	defer server.Close()
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	client := NewClient(server.URL, 10*time.Second, logger)
+	err := client.SetAuth(models.BeaconAuth{
+		Headers:     map[string]string{"X-API-Key": "${PROVIDER_KEY}"},
+		BearerToken: "token-1",
+	})
+	if err != nil {
+		t.Fatalf("SetAuth: %v", err)
+	}
+
+	if _, err := client.GetGenesis(context.Background()); err != nil {
+		t.Fatalf("GetGenesis: %v", err)
+	}
+	if got.Get("X-API-Key") != "key-123" {
+		t.Errorf("X-API-Key = %q, want the expanded environment variable", got.Get("X-API-Key"))
+	}
+	if got.Get("Authorization") != "Bearer token-1" {
+		t.Errorf("Authorization = %q, want Bearer token-1", got.Get("Authorization"))
+	}
+}
+
+func TestSetEndpointAuth(t *testing.T) {
+	var primary, secondary http.Header
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		primary = r.Header.Clone()
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		secondary = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"genesis_time":"1606824023"}}`))
+	}))
+	defer server.Close()
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	client := NewFailoverClient([]string{failing.URL, server.URL}, 10*time.Second, logger)
+	if err := client.SetEndpointAuth(0, models.BeaconAuth{BearerToken: "provider-a"}); err != nil {
+		t.Fatalf("SetEndpointAuth: %v", err)
+	}
+	if err := client.SetEndpointAuth(1, models.BeaconAuth{Headers: map[string]string{"X-API-Key": "provider-b"}}); err != nil {
+		t.Fatalf("SetEndpointAuth: %v", err)
+	}
+
+	// The request failing over to the second node carries that node's credentials only
+	if _, err := client.GetGenesis(context.Background()); err != nil {
+		t.Fatalf("GetGenesis: %v", err)
+	}
+	if primary.Get("Authorization") != "Bearer provider-a" || primary.Get("X-API-Key") != "" {
+		t.Errorf("First node got Authorization %q and X-API-Key %q", primary.Get("Authorization"), primary.Get("X-API-Key"))
+	}
+	if secondary.Get("X-API-Key") != "provider-b" || secondary.Get("Authorization") != "" {
+		t.Errorf("Second node got Authorization %q and X-API-Key %q", secondary.Get("Authorization"), secondary.Get("X-API-Key"))
+	}
+
+	if err := client.SetEndpointAuth(2, models.BeaconAuth{}); err == nil {
+		t.Error("Expected an error for an endpoint that doesn't exist")
+	}
+}
+
+func TestAuthenticatorJWT(t *testing.T) {
+	secret := strings.Repeat("ab", 32)
+	path := filepath.Join(t.TempDir(), "jwt.hex")
+	if err := os.WriteFile(path, []byte("0x"+secret+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := newAuthenticator(models.BeaconAuth{JWTSecretFile: path})
+	if err != nil {
+		t.Fatalf("newAuthenticator: %v", err)
+	}
+	a.now = func() time.Time { return time.Unix(1700000000, 0) }
+
+	req := httptest.NewRequest(http.MethodGet, "/eth/v1/node/version", nil)
+	a.apply(req)
+	token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		t.Fatalf("Authorization = %q, want a bearer token", req.Header.Get("Authorization"))
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Fatalf("token has %d parts, want 3", len(parts))
+	}
+	key := make([]byte, 32)
+	for i := range key {
+		key[i] = 0xab
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if base64.RawURLEncoding.EncodeToString(mac.Sum(nil)) != parts[2] {
+		t.Error("token signature does not verify with the secret")
+	}
+	claims, _ := base64.RawURLEncoding.DecodeString(parts[1])
+	var decoded map[string]int64
+	if err := json.Unmarshal(claims, &decoded); err != nil || decoded["iat"] != 1700000000 {
+		t.Errorf("claims = %s, want iat 1700000000", claims)
+	}
+}
+
+func TestAuthenticatorRejectsShortJWTSecret(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jwt.hex")
+	os.WriteFile(path, []byte("abcd"), 0o600)
+	if _, err := newAuthenticator(models.BeaconAuth{JWTSecretFile: path}); err == nil {
+		t.Error("accepted a 2-byte JWT secret")
+	}
+}
+
+func TestValidateAuth(t *testing.T) {
+	if err := ValidateAuth(models.BeaconAuth{BearerToken: "t", BasicAuth: models.BasicAuth{Username: "u"}}); err == nil {
+		t.Error("accepted both a bearer token and basic auth")
+	}
+	if err := ValidateAuth(models.BeaconAuth{Headers: map[string]string{"X-API-Key": "k"}, BearerToken: "t"}); err != nil {
+		t.Errorf("rejected headers with a bearer token: %v", err)
+	}
+}
@@ -4,113 +4,304 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/cache"
 	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/sharedcache"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/tracing"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
 )
 
 const (
-	maxRetries      = 3
-	retryDelay      = 2 * time.Second
+	maxRetries      = 3               // Attempts of the default retry policy
+	retryDelay      = 2 * time.Second // Base delay of the default retry policy, and between event stream reconnects
 	contentTypeJSON = "application/json"
+
+	// Committee shuffling is fixed an epoch in advance, so epoch committees can be cached
+	committeeCacheSize = 4
+	committeeCacheTTL  = 30 * time.Minute
+
+	// Ids per GET request when the node doesn't accept POSTed validator ids (keeps URLs short)
+	validatorsQueryChunk = 50
 )
 
+// StatusError is returned when the beacon node answers with an HTTP error status
+type StatusError struct {
+	StatusCode int
+	msg        string
+}
+
+// Error implements error
+func (e *StatusError) Error() string {
+	return e.msg
+}
+
+// IsNotFound reports whether err is a 404 response, e.g. for a slot without a block
+func IsNotFound(err error) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound
+}
+
 // Client represents a Beacon Chain API client
+// With several endpoints, requests fail over to the next node on errors, 5xx responses and timeouts
 type Client struct {
-	baseURL    string
+	endpoints  []*endpoint
+	activeMu   sync.RWMutex
+	active     *endpoint
 	httpClient *http.Client
-	logger     *logrus.Logger
+	// Event streams stay open indefinitely, so they use a client without a request timeout
+	streamClient *http.Client
+	logger       *logrus.Logger
+
+	slotsPerEpoch uint64
+	committees    *cache.Cache[models.Epoch, []models.Committee]
+	responses     *cache.Cache[string, *cachedResponse] // Responses of getCached, by path
+	epochClock    func() models.Epoch                   // Current epoch, nil until the clock is known
+	shared        *sharedcache.Cache
+	quirks        *quirks
+	limiter       *limiter
+	defaultPolicy retryPolicy
+	policies      map[string]retryPolicy // By request category, nil until SetRetryPolicies
+
+	requestMetrics *requestMetrics
 }
 
 // NewClient creates a new Beacon Chain API client
 func NewClient(baseURL string, timeout time.Duration, logger *logrus.Logger) *Client {
+	return NewFailoverClient([]string{baseURL}, timeout, logger)
+}
+
+// NewFailoverClient creates a client that spreads requests over several beacon nodes,
+// preferring the fastest healthy one
+func NewFailoverClient(baseURLs []string, timeout time.Duration, logger *logrus.Logger) *Client {
+	endpoints := make([]*endpoint, len(baseURLs))
+	for i, baseURL := range baseURLs {
+		endpoints[i] = newEndpoint(baseURL)
+	}
+
 	return &Client{
-		baseURL: strings.TrimSuffix(baseURL, "/"),
-		httpClient: &http.Client{
-			Timeout: timeout,
-		},
-		logger: logger,
+		endpoints: endpoints,
+		// Attempts are bounded by the timeout of their category's retry policy
+		httpClient:    &http.Client{},
+		streamClient:  &http.Client{},
+		defaultPolicy: defaultPolicy(timeout),
+		logger:        logger,
+		committees:    cache.New[models.Epoch, []models.Committee]("committees", committeeCacheSize, committeeCacheTTL),
+		responses:     cache.New[string, *cachedResponse]("beacon_responses", responseCacheSize, 0),
+		quirks:        newQuirks(),
+		limiter:       newLimiter(models.BeaconRateLimit{}),
+
+		requestMetrics: newRequestMetrics(),
+	}
+}
+
+// SetSlotsPerEpoch enables per-epoch committee caching for slot committee lookups
+func (c *Client) SetSlotsPerEpoch(slotsPerEpoch uint64) {
+	c.slotsPerEpoch = slotsPerEpoch
+}
+
+// SetRateLimit paces subsequent requests to the beacon nodes: requests per second with a burst,
+// and at most a number of requests in flight (zero values leave either unbounded)
+func (c *Client) SetRateLimit(limits models.BeaconRateLimit) {
+	c.limiter = newLimiter(limits)
+}
+
+// SetAuth adds headers and credentials to every subsequent request to every beacon node
+func (c *Client) SetAuth(auth models.BeaconAuth) error {
+	for i := range c.endpoints {
+		if err := c.SetEndpointAuth(i, auth); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetEndpointAuth adds headers and credentials to every subsequent request to the i-th beacon node,
+// for failover endpoints run by different providers
+func (c *Client) SetEndpointAuth(i int, auth models.BeaconAuth) error {
+	if i < 0 || i >= len(c.endpoints) {
+		return fmt.Errorf("no beacon endpoint %d", i)
+	}
+	a, err := newAuthenticator(auth)
+	if err != nil {
+		return err
+	}
+	c.endpoints[i].auth = a
+	return nil
+}
+
+// SetSharedCache makes epoch committee lookups try the cache shared by the watcher replicas
+// before asking the beacon node
+func (c *Client) SetSharedCache(shared *sharedcache.Cache) {
+	c.shared = shared
+}
+
+// Collectors returns the collectors of the endpoints, nodes and requests of the client
+func (c *Client) Collectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		NewCollector(c),
+		c.requestMetrics.duration,
+		c.requestMetrics.errors,
+		c.requestMetrics.revalidations,
 	}
 }
 
-// doRequest performs an HTTP request with retry logic
+// Caches returns the client's caches for metrics collection
+func (c *Client) Caches() []cache.Source {
+	return []cache.Source{c.committees, c.responses}
+}
+
+// doRequest performs an HTTP request with failover and retry logic
+// Each attempt tries every endpoint in preference order; 4xx responses are returned as is
 func (c *Client) doRequest(ctx context.Context, method, path string, body interface{}, result interface{}) error {
+	var jsonData []byte
+	if body != nil {
+		var err error
+		if jsonData, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("failed to marshal request body: %w", err)
+		}
+	}
+
+	r, err := c.exchange(ctx, method, path, jsonData, "")
+	if err != nil {
+		return err
+	}
+	if result != nil {
+		if err := c.decode(path, r.body, result); err != nil {
+			return fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+	}
+	return nil
+}
+
+// reply is a successful response
+type reply struct {
+	body        []byte
+	etag        string
+	notModified bool // 304 to a conditional request: body is empty, the cached one is current
+}
+
+// exchange sends a request with failover and retry logic, conditional on an ETag if set
+func (c *Client) exchange(ctx context.Context, method, path string, jsonData []byte, etag string) (r reply, err error) {
+	ctx, span := startCall(ctx, method, path)
+	defer func() { tracing.End(span, err) }()
+
 	var lastErr error
+	policy := c.policy(path)
 
-	for attempt := 0; attempt < maxRetries; attempt++ {
+	for attempt := 0; attempt < policy.maxAttempts; attempt++ {
 		if attempt > 0 {
 			select {
 			case <-ctx.Done():
-				return ctx.Err()
-			case <-time.After(retryDelay * time.Duration(attempt)):
+				return reply{}, ctx.Err()
+			case <-time.After(policy.wait(attempt)):
 			}
-			c.logger.Debugf("Retrying request to %s (attempt %d/%d)", path, attempt+1, maxRetries)
+			c.logger.Debugf("Retrying request to %s (attempt %d/%d)", path, attempt+1, policy.maxAttempts)
 		}
 
-		var reqBody io.Reader
-		if body != nil {
-			jsonData, err := json.Marshal(body)
-			if err != nil {
-				return fmt.Errorf("failed to marshal request body: %w", err)
+		for _, ep := range c.orderedEndpoints() {
+			r, err := c.send(ctx, ep, policy.timeout, method, path, jsonData, etag)
+			if err == nil {
+				return r, nil
 			}
-			reqBody = bytes.NewBuffer(jsonData)
-		}
 
-		url := c.baseURL + path
-		c.logger.Debugf("Making request: %s %s", method, url)
-		req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
-		if err != nil {
-			lastErr = fmt.Errorf("failed to create request: %w", err)
-			continue
-		}
+			lastErr = err
+			var statusErr *StatusError
+			if errors.As(err, &statusErr) && statusErr.StatusCode < 500 {
+				return reply{}, err
+			}
+			// A cancelled caller says nothing about the node's health
+			if ctx.Err() != nil {
+				return reply{}, err
+			}
 
-		if body != nil {
-			req.Header.Set("Content-Type", contentTypeJSON)
+			ep.recordFailure(time.Now())
+			if len(c.endpoints) > 1 {
+				c.logger.WithError(err).WithField("endpoint", ep.name).Debug("Beacon endpoint failed - trying next endpoint")
+			}
 		}
-		req.Header.Set("Accept", contentTypeJSON)
+	}
 
-		resp, err := c.httpClient.Do(req)
-		if err != nil {
-			lastErr = fmt.Errorf("request failed: %w", err)
-			continue
-		}
+	return reply{}, fmt.Errorf("request failed after %d attempts: %w", policy.maxAttempts, lastErr)
+}
 
-		defer resp.Body.Close()
-		respBody, err := io.ReadAll(resp.Body)
-		if err != nil {
-			lastErr = fmt.Errorf("failed to read response: %w", err)
-			continue
-		}
+// send performs a single request against one endpoint, within a timeout (0 for none)
+func (c *Client) send(ctx context.Context, ep *endpoint, timeout time.Duration, method, path string, jsonData []byte, etag string) (r reply, err error) {
+	ctx, span := startAttempt(ctx, ep, method)
+	status := 0
+	defer func() { endAttempt(span, status, err) }()
 
-		if resp.StatusCode >= 400 {
-			// Provide helpful error messages
-			if resp.StatusCode == 404 {
-				lastErr = fmt.Errorf("endpoint not found (HTTP 404): %s - this beacon node may not support this API endpoint. Response: %s", url, string(respBody))
-			} else {
-				lastErr = fmt.Errorf("HTTP %d: %s - URL: %s", resp.StatusCode, string(respBody), url)
-			}
-			// Retry on 5xx errors
-			if resp.StatusCode >= 500 {
-				continue
-			}
-			return lastErr
-		}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
 
-		if result != nil {
-			if err := json.Unmarshal(respBody, result); err != nil {
-				return fmt.Errorf("failed to unmarshal response: %w", err)
-			}
-		}
+	var reqBody io.Reader
+	if jsonData != nil {
+		reqBody = bytes.NewReader(jsonData)
+	}
 
-		return nil
+	url := ep.url + path
+	c.logger.Debugf("Making request: %s %s", method, redactURL(url))
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+	if err != nil {
+		return reply{}, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if jsonData != nil {
+		req.Header.Set("Content-Type", contentTypeJSON)
+	}
+	req.Header.Set("Accept", contentTypeJSON)
+	ep.auth.apply(req)
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
 	}
 
-	return fmt.Errorf("request failed after %d attempts: %w", maxRetries, lastErr)
+	release, err := c.limiter.acquire(ctx)
+	if err != nil {
+		return reply{}, err
+	}
+	defer release()
+
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.requestMetrics.observe(ctx, ep.name, time.Since(start), 0, err)
+		return reply{}, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	status = resp.StatusCode
+
+	respBody, err := io.ReadAll(resp.Body)
+	c.requestMetrics.observe(ctx, ep.name, time.Since(start), resp.StatusCode, err)
+	if err != nil {
+		return reply{}, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode >= 400 {
+		// Provide helpful error messages
+		url = redactURL(url)
+		if resp.StatusCode == 404 {
+			return reply{}, &StatusError{resp.StatusCode, fmt.Sprintf("endpoint not found (HTTP 404): %s - this beacon node may not support this API endpoint. Response: %s", url, string(respBody))}
+		}
+		return reply{}, &StatusError{resp.StatusCode, fmt.Sprintf("HTTP %d: %s - URL: %s", resp.StatusCode, string(respBody), url)}
+	}
+
+	ep.recordSuccess(time.Since(start))
+	c.setActive(ep)
+	if resp.StatusCode == http.StatusNotModified {
+		return reply{etag: etag, notModified: true}, nil
+	}
+	return reply{body: respBody, etag: resp.Header.Get("ETag")}, nil
 }
 
 // GetGenesis retrieves the genesis configuration
@@ -119,7 +310,7 @@ func (c *Client) GetGenesis(ctx context.Context) (*models.Genesis, error) {
 		Data models.Genesis `json:"data"`
 	}
 
-	if err := c.doRequest(ctx, http.MethodGet, "/eth/v1/beacon/genesis", nil, &response); err != nil {
+	if err := c.getCached(ctx, "/eth/v1/beacon/genesis", &response); err != nil {
 		return nil, fmt.Errorf("failed to get genesis: %w", err)
 	}
 
@@ -132,20 +323,33 @@ func (c *Client) GetSpec(ctx context.Context) (*models.Spec, error) {
 		Data models.Spec `json:"data"`
 	}
 
-	if err := c.doRequest(ctx, http.MethodGet, "/eth/v1/config/spec", nil, &response); err != nil {
+	if err := c.getCached(ctx, "/eth/v1/config/spec", &response); err != nil {
 		return nil, fmt.Errorf("failed to get spec: %w", err)
 	}
 
 	return &response.Data, nil
 }
 
-// GetHeader retrieves a block header by state ID
-func (c *Client) GetHeader(ctx context.Context, stateID string) (*models.BeaconHeader, error) {
+// GetNodeSyncing retrieves the beacon node's sync status
+func (c *Client) GetNodeSyncing(ctx context.Context) (*models.SyncStatus, error) {
+	var response struct {
+		Data models.SyncStatus `json:"data"`
+	}
+
+	if err := c.doRequest(ctx, http.MethodGet, "/eth/v1/node/syncing", nil, &response); err != nil {
+		return nil, fmt.Errorf("failed to get sync status: %w", err)
+	}
+
+	return &response.Data, nil
+}
+
+// GetHeader retrieves a block header by block ID
+func (c *Client) GetHeader(ctx context.Context, blockID string) (*models.BeaconHeader, error) {
 	var response struct {
 		Data models.BeaconHeader `json:"data"`
 	}
 
-	path := fmt.Sprintf("/eth/v1/beacon/headers/%s", stateID)
+	path := fmt.Sprintf("/eth/v1/beacon/headers/%s", blockID)
 	if err := c.doRequest(ctx, http.MethodGet, path, nil, &response); err != nil {
 		return nil, fmt.Errorf("failed to get header: %w", err)
 	}
@@ -153,6 +357,20 @@ func (c *Client) GetHeader(ctx context.Context, stateID string) (*models.BeaconH
 	return &response.Data, nil
 }
 
+// GetFinalityCheckpoints retrieves the justified and finalized checkpoints of a state
+func (c *Client) GetFinalityCheckpoints(ctx context.Context, stateID string) (*models.FinalityCheckpoints, error) {
+	var response struct {
+		Data models.FinalityCheckpoints `json:"data"`
+	}
+
+	path := fmt.Sprintf("/eth/v1/beacon/states/%s/finality_checkpoints", stateID)
+	if err := c.doRequest(ctx, http.MethodGet, path, nil, &response); err != nil {
+		return nil, fmt.Errorf("failed to get finality checkpoints: %w", err)
+	}
+
+	return &response.Data, nil
+}
+
 // GetValidators retrieves validators by indices (uses POST for large sets)
 func (c *Client) GetValidators(ctx context.Context, stateID string, indices []models.ValidatorIndex) ([]models.Validator, error) {
 	// Convert indices to strings for the request
@@ -161,63 +379,131 @@ func (c *Client) GetValidators(ctx context.Context, stateID string, indices []mo
 		indicesStr[i] = fmt.Sprintf("%d", idx)
 	}
 
-	requestBody := map[string]interface{}{
-		"ids": indicesStr,
-	}
-
-	var response models.ValidatorsResponse
-	path := fmt.Sprintf("/eth/v1/beacon/states/%s/validators", stateID)
-
-	if err := c.doRequest(ctx, http.MethodPost, path, requestBody, &response); err != nil {
+	validators, err := c.fetchValidators(ctx, stateID, indicesStr)
+	if err != nil {
 		return nil, fmt.Errorf("failed to get validators: %w", err)
 	}
 
-	return response.Data, nil
+	return validators, nil
 }
 
 // GetValidatorsByPubkeys retrieves validators by public keys (uses POST)
 func (c *Client) GetValidatorsByPubkeys(ctx context.Context, stateID string, pubkeys []string) ([]models.Validator, error) {
-	requestBody := map[string]interface{}{
-		"ids": pubkeys,
+	c.logger.WithField("count", len(pubkeys)).Debug("Fetching validators by pubkeys")
+	validators, err := c.fetchValidators(ctx, stateID, pubkeys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get validators by pubkeys: %w", err)
 	}
 
-	var response models.ValidatorsResponse
+	c.logger.Infof("Loaded %d validators by pubkeys", len(validators))
+	return validators, nil
+}
+
+// fetchValidators POSTs validator ids, falling back to chunked GET queries on nodes without POST support
+func (c *Client) fetchValidators(ctx context.Context, stateID string, ids []string) ([]models.Validator, error) {
 	path := fmt.Sprintf("/eth/v1/beacon/states/%s/validators", stateID)
 
-	c.logger.WithField("count", len(pubkeys)).Debug("Fetching validators by pubkeys")
-	if err := c.doRequest(ctx, http.MethodPost, path, requestBody, &response); err != nil {
-		return nil, fmt.Errorf("failed to get validators by pubkeys: %w", err)
+	if !c.hasQuirk(QuirkNoValidatorsPOST) {
+		var response models.ValidatorsResponse
+		err := c.doRequest(ctx, http.MethodPost, path, map[string]interface{}{"ids": ids}, &response)
+		if err == nil {
+			return response.Data, nil
+		}
+
+		var statusErr *StatusError
+		if !errors.As(err, &statusErr) || (statusErr.StatusCode != http.StatusNotFound && statusErr.StatusCode != http.StatusMethodNotAllowed) {
+			return nil, err
+		}
 	}
 
-	c.logger.Infof("Loaded %d validators by pubkeys", len(response.Data))
-	return response.Data, nil
+	validators := make([]models.Validator, 0, len(ids))
+	for start := 0; start < len(ids); start += validatorsQueryChunk {
+		end := start + validatorsQueryChunk
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		var response models.ValidatorsResponse
+		query := path + "?id=" + strings.Join(ids[start:end], ",")
+		if err := c.doRequest(ctx, http.MethodGet, query, nil, &response); err != nil {
+			return nil, err
+		}
+		c.recordQuirk(QuirkNoValidatorsPOST, path)
+		validators = append(validators, response.Data...)
+	}
+
+	return validators, nil
 }
 
 // GetAllValidators retrieves all validators (for loading the full 2M+ validator set)
+// Prefer StreamAllValidators, which doesn't need the whole set as a slice
 func (c *Client) GetAllValidators(ctx context.Context, stateID string) ([]models.Validator, error) {
-	var response models.ValidatorsResponse
-	path := fmt.Sprintf("/eth/v1/beacon/states/%s/validators", stateID)
+	var validators []models.Validator
+	if _, err := c.StreamAllValidators(ctx, stateID, func(v models.Validator) {
+		validators = append(validators, v)
+	}); err != nil {
+		return nil, err
+	}
+	return validators, nil
+}
 
-	if err := c.doRequest(ctx, http.MethodGet, path, nil, &response); err != nil {
-		return nil, fmt.Errorf("failed to get all validators: %w", err)
+// GetProposerDuties retrieves proposer duties for an epoch
+func (c *Client) GetProposerDuties(ctx context.Context, epoch models.Epoch) ([]models.ProposerDuty, error) {
+	var response models.ProposerDutiesResponse
+	path := fmt.Sprintf("/eth/v1/validator/duties/proposer/%d", epoch)
+
+	// Duties of a later epoch depend on blocks of the current one, so they are only cached once
+	// their epoch started
+	var err error
+	if c.epochClock != nil && epoch > c.epochClock() {
+		err = c.doRequest(ctx, http.MethodGet, path, nil, &response)
+	} else {
+		err = c.getCached(ctx, path, &response)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get proposer duties: %w", err)
 	}
 
-	c.logger.Infof("Loaded %d validators from beacon node", len(response.Data))
 	return response.Data, nil
 }
 
-// GetProposerDuties retrieves proposer duties for an epoch
-func (c *Client) GetProposerDuties(ctx context.Context, epoch models.Epoch) ([]models.ProposerDuty, error) {
+// RefetchProposerDuties retrieves proposer duties for an epoch like GetProposerDuties, but
+// revalidates a response cached during the current epoch, so duties reassigned by a reorg are seen
+func (c *Client) RefetchProposerDuties(ctx context.Context, epoch models.Epoch) ([]models.ProposerDuty, error) {
 	var response models.ProposerDutiesResponse
 	path := fmt.Sprintf("/eth/v1/validator/duties/proposer/%d", epoch)
 
-	if err := c.doRequest(ctx, http.MethodGet, path, nil, &response); err != nil {
+	var err error
+	if c.epochClock != nil && epoch > c.epochClock() {
+		err = c.doRequest(ctx, http.MethodGet, path, nil, &response)
+	} else {
+		err = c.getRevalidated(ctx, path, &response)
+	}
+	if err != nil {
 		return nil, fmt.Errorf("failed to get proposer duties: %w", err)
 	}
 
 	return response.Data, nil
 }
 
+// GetAttesterDuties retrieves attester duties for an epoch for the given validators
+func (c *Client) GetAttesterDuties(ctx context.Context, epoch models.Epoch, indices []models.ValidatorIndex) ([]models.AttesterDuty, error) {
+	// Convert indices to strings for the request
+	indicesStr := make([]string, len(indices))
+	for i, idx := range indices {
+		indicesStr[i] = fmt.Sprintf("%d", idx)
+	}
+
+	var response models.AttesterDutiesResponse
+	path := fmt.Sprintf("/eth/v1/validator/duties/attester/%d", epoch)
+
+	if err := c.doRequest(ctx, http.MethodPost, path, indicesStr, &response); err != nil {
+		return nil, fmt.Errorf("failed to get attester duties: %w", err)
+	}
+
+	return response.Data, nil
+}
+
 // GetBlock retrieves a block by block ID
 func (c *Client) GetBlock(ctx context.Context, blockID string) (*models.Block, error) {
 	var response models.BlockResponse
@@ -230,6 +516,34 @@ func (c *Client) GetBlock(ctx context.Context, blockID string) (*models.Block, e
 	return &response.Data, nil
 }
 
+// GetBlockRewards retrieves the consensus layer rewards of a block's proposer
+func (c *Client) GetBlockRewards(ctx context.Context, blockID string) (*models.BlockRewards, error) {
+	var response struct {
+		Data models.BlockRewards `json:"data"`
+	}
+
+	path := fmt.Sprintf("/eth/v1/beacon/rewards/blocks/%s", blockID)
+	if err := c.doRequest(ctx, http.MethodGet, path, nil, &response); err != nil {
+		return nil, fmt.Errorf("failed to get block rewards: %w", err)
+	}
+
+	return &response.Data, nil
+}
+
+// GetSyncCommittee retrieves the sync committee of the period containing an epoch
+func (c *Client) GetSyncCommittee(ctx context.Context, stateID string, epoch models.Epoch) (*models.SyncCommittee, error) {
+	var response struct {
+		Data models.SyncCommittee `json:"data"`
+	}
+
+	path := fmt.Sprintf("/eth/v1/beacon/states/%s/sync_committees?epoch=%d", stateID, epoch)
+	if err := c.doRequest(ctx, http.MethodGet, path, nil, &response); err != nil {
+		return nil, fmt.Errorf("failed to get sync committee: %w", err)
+	}
+
+	return &response.Data, nil
+}
+
 // GetAttestations retrieves attestations for a slot
 func (c *Client) GetAttestations(ctx context.Context, slot models.Slot) ([]models.Attestation, error) {
 	var response models.AttestationsResponse
@@ -243,7 +557,67 @@ func (c *Client) GetAttestations(ctx context.Context, slot models.Slot) ([]model
 }
 
 // GetCommittees retrieves committees for a slot
+// Head slot lookups are served from a per-epoch cache once slots per epoch is known
 func (c *Client) GetCommittees(ctx context.Context, stateID string, epoch *models.Epoch, slot *models.Slot) ([]models.Committee, error) {
+	if stateID == "head" && epoch == nil && slot != nil && c.slotsPerEpoch > 0 {
+		return c.getSlotCommittees(ctx, *slot)
+	}
+
+	return c.fetchCommittees(ctx, stateID, epoch, slot)
+}
+
+// getSlotCommittees returns a slot's committees from the epoch committee cache
+func (c *Client) getSlotCommittees(ctx context.Context, slot models.Slot) ([]models.Committee, error) {
+	epoch := models.Epoch(uint64(slot) / c.slotsPerEpoch)
+
+	epochCommittees, ok := c.committees.Get(epoch)
+	if !ok {
+		var err error
+		epochCommittees, err = c.sharedEpochCommittees(ctx, epoch)
+		if err != nil {
+			return nil, err
+		}
+		c.committees.Set(epoch, epochCommittees)
+	}
+
+	committees := make([]models.Committee, 0, len(epochCommittees)/int(c.slotsPerEpoch)+1)
+	for _, committee := range epochCommittees {
+		if committee.Slot == slot {
+			committees = append(committees, committee)
+		}
+	}
+	return committees, nil
+}
+
+// sharedEpochCommittees returns an epoch's committees from the shared cache if another replica
+// already fetched them, otherwise fetches and shares them
+// Shared cache errors only cost a beacon request
+func (c *Client) sharedEpochCommittees(ctx context.Context, epoch models.Epoch) ([]models.Committee, error) {
+	key := fmt.Sprintf("committees:%d", epoch)
+	if c.shared != nil {
+		var committees []models.Committee
+		if ok, err := c.shared.Get(ctx, key, &committees); err != nil {
+			c.logger.WithError(err).Debug("Failed to read committees from the shared cache")
+		} else if ok {
+			return committees, nil
+		}
+	}
+
+	committees, err := c.fetchCommittees(ctx, "head", &epoch, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.shared != nil {
+		if err := c.shared.Set(ctx, key, committees, committeeCacheTTL); err != nil {
+			c.logger.WithError(err).Debug("Failed to share committees")
+		}
+	}
+	return committees, nil
+}
+
+// fetchCommittees retrieves committees from the beacon node
+func (c *Client) fetchCommittees(ctx context.Context, stateID string, epoch *models.Epoch, slot *models.Slot) ([]models.Committee, error) {
 	var response models.CommitteesResponse
 	path := fmt.Sprintf("/eth/v1/beacon/states/%s/committees", stateID)
 
@@ -259,7 +633,14 @@ func (c *Client) GetCommittees(ctx context.Context, stateID string, epoch *model
 		path += "?" + strings.Join(params, "&")
 	}
 
-	if err := c.doRequest(ctx, http.MethodGet, path, nil, &response); err != nil {
+	// Committees of a whole epoch don't change once it is known
+	var err error
+	if epoch != nil && slot == nil {
+		err = c.getCached(ctx, path, &response)
+	} else {
+		err = c.doRequest(ctx, http.MethodGet, path, nil, &response)
+	}
+	if err != nil {
 		return nil, fmt.Errorf("failed to get committees: %w", err)
 	}
 
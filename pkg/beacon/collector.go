@@ -0,0 +1,144 @@
+package beacon
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// requestMetrics records the latency and errors of every request the client sends
+type requestMetrics struct {
+	duration      *prometheus.HistogramVec
+	errors        *prometheus.CounterVec
+	revalidations *prometheus.CounterVec
+}
+
+func newRequestMetrics() *requestMetrics {
+	return &requestMetrics{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "eth_beacon_request_duration_seconds",
+			Help:    "Latency of beacon API requests that got a response, per endpoint",
+			Buckets: []float64{0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+			// Also exported as a native histogram to scrapers that negotiate it, for the tail of slow nodes
+			NativeHistogramBucketFactor:     1.1,
+			NativeHistogramMaxBucketNumber:  100,
+			NativeHistogramMinResetDuration: time.Hour,
+		}, []string{"endpoint"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "eth_beacon_request_errors_total",
+			Help: "Total failed beacon API requests per endpoint, by HTTP status code, timeout or network error",
+		}, []string{"endpoint", "code"}),
+		revalidations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "eth_beacon_cache_revalidations_total",
+			Help: "Total cached beacon responses revalidated with their ETag, by result (not_modified or modified)",
+		}, []string{"result"}),
+	}
+}
+
+// observe records one request to an endpoint: its HTTP status, or the error if no response came back
+// Requests cancelled by the caller say nothing about the endpoint and aren't recorded
+func (m *requestMetrics) observe(ctx context.Context, endpoint string, elapsed time.Duration, status int, err error) {
+	switch {
+	case err != nil && ctx.Err() == context.Canceled:
+		return
+	case err != nil:
+		code := "network"
+		var netErr net.Error
+		if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+			code = "timeout"
+		}
+		m.errors.WithLabelValues(endpoint, code).Inc()
+		return
+	}
+
+	m.duration.WithLabelValues(endpoint).Observe(elapsed.Seconds())
+	if status >= 400 {
+		m.errors.WithLabelValues(endpoint, strconv.Itoa(status)).Inc()
+	}
+}
+
+// Collector exports beacon endpoint health, the detected nodes, and the response quirks tolerated from them
+type Collector struct {
+	client *Client
+
+	nodeInfo *prometheus.Desc
+	quirks   *prometheus.Desc
+	active   *prometheus.Desc
+	healthy  *prometheus.Desc
+	latency  *prometheus.Desc
+	requests *prometheus.Desc
+	failures *prometheus.Desc
+	inFlight *prometheus.Desc
+	waited   *prometheus.Desc
+}
+
+// NewCollector creates a collector for the client
+func NewCollector(client *Client) *Collector {
+	return &Collector{
+		client: client,
+		nodeInfo: prometheus.NewDesc("eth_beacon_node_info",
+			"Beacon node client and version behind each endpoint (always 1)", []string{"endpoint", "client", "version"}, nil),
+		quirks: prometheus.NewDesc("eth_beacon_quirks_total",
+			"Total beacon node responses that needed a client quirk handled", []string{"client", "quirk"}, nil),
+		active: prometheus.NewDesc("eth_beacon_endpoint_active",
+			"1 for the endpoint that served the latest request, 0 otherwise", []string{"endpoint"}, nil),
+		healthy: prometheus.NewDesc("eth_beacon_endpoint_healthy",
+			"0 while an endpoint is cooling down after a failure, 1 otherwise", []string{"endpoint"}, nil),
+		latency: prometheus.NewDesc("eth_beacon_endpoint_latency_seconds",
+			"Moving average latency of successful requests per endpoint", []string{"endpoint"}, nil),
+		requests: prometheus.NewDesc("eth_beacon_endpoint_requests_total",
+			"Total requests sent per endpoint", []string{"endpoint"}, nil),
+		failures: prometheus.NewDesc("eth_beacon_endpoint_failures_total",
+			"Total failed requests (errors, timeouts, 5xx) per endpoint", []string{"endpoint"}, nil),
+		inFlight: prometheus.NewDesc("eth_beacon_requests_in_flight",
+			"Beacon API requests sent and not yet fully read", nil, nil),
+		waited: prometheus.NewDesc("eth_beacon_rate_limit_wait_seconds_total",
+			"Total time requests waited for the beacon_rate_limit concurrency bound or request rate", nil, nil),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.nodeInfo
+	ch <- c.quirks
+	ch <- c.active
+	ch <- c.healthy
+	ch <- c.latency
+	ch <- c.requests
+	ch <- c.failures
+	ch <- c.inFlight
+	ch <- c.waited
+}
+
+// Collect implements prometheus.Collector
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	for _, status := range c.client.Endpoints() {
+		ch <- prometheus.MustNewConstMetric(c.nodeInfo, prometheus.GaugeValue, 1, status.Name, status.Node.Client, status.Node.Version)
+		ch <- prometheus.MustNewConstMetric(c.active, prometheus.GaugeValue, boolToFloat(status.Active), status.Name)
+		ch <- prometheus.MustNewConstMetric(c.healthy, prometheus.GaugeValue, boolToFloat(status.Healthy), status.Name)
+		ch <- prometheus.MustNewConstMetric(c.latency, prometheus.GaugeValue, status.Latency.Seconds(), status.Name)
+		ch <- prometheus.MustNewConstMetric(c.requests, prometheus.CounterValue, float64(status.Requests), status.Name)
+		ch <- prometheus.MustNewConstMetric(c.failures, prometheus.CounterValue, float64(status.Failures), status.Name)
+	}
+
+	for client, byQuirk := range c.client.QuirkCounts() {
+		for quirk, count := range byQuirk {
+			ch <- prometheus.MustNewConstMetric(c.quirks, prometheus.CounterValue, float64(count), client, quirk)
+		}
+	}
+
+	limiter := c.client.limiter
+	ch <- prometheus.MustNewConstMetric(c.inFlight, prometheus.GaugeValue, float64(limiter.inFlight.Load()))
+	ch <- prometheus.MustNewConstMetric(c.waited, prometheus.CounterValue, time.Duration(limiter.waitNanos.Load()).Seconds())
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
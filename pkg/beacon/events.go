@@ -0,0 +1,152 @@
+package beacon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Event stream topics
+const (
+	TopicHead                = "head"
+	TopicBlock               = "block"
+	TopicFinalizedCheckpoint = "finalized_checkpoint"
+	TopicChainReorg          = "chain_reorg"
+)
+
+// maxEventSize bounds a single server-sent event line
+const maxEventSize = 1 << 20
+
+// Event is one server-sent event from /eth/v1/events
+type Event struct {
+	Topic string
+	Data  json.RawMessage
+}
+
+// Decode unmarshals the event payload, accepting numbers sent unquoted
+func (e Event) Decode(v interface{}) error {
+	err := json.Unmarshal(e.Data, v)
+	if err == nil {
+		return nil
+	}
+	if normalized, changed, normErr := quoteNumbers(e.Data); normErr == nil && changed {
+		if json.Unmarshal(normalized, v) == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// SubscribeEvents streams beacon node events until ctx is cancelled
+// The first connection is made before returning so callers can fall back when the node
+// has no event stream; later disconnects reconnect to the preferred endpoint
+func (c *Client) SubscribeEvents(ctx context.Context, topics []string) (<-chan Event, error) {
+	path := "/eth/v1/events?topics=" + strings.Join(topics, ",")
+
+	resp, ep, err := c.openEventStream(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan Event, 64)
+	go func() {
+		defer close(events)
+
+		for {
+			c.logger.WithField("endpoint", ep.name).Info("Subscribed to beacon node events")
+			err := readEvents(ctx, resp, events)
+			resp.Body.Close()
+			if ctx.Err() != nil {
+				return
+			}
+			c.logger.WithError(err).WithField("endpoint", ep.name).Warn("Beacon event stream disconnected - reconnecting")
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(retryDelay):
+				}
+
+				resp, ep, err = c.openEventStream(ctx, path)
+				if err == nil {
+					break
+				}
+				c.logger.WithError(err).Debug("Failed to reconnect to beacon event stream")
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// openEventStream connects to the first endpoint that accepts the subscription
+func (c *Client) openEventStream(ctx context.Context, path string) (*http.Response, *endpoint, error) {
+	var lastErr error
+
+	for _, ep := range c.orderedEndpoints() {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, ep.url+path, nil)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Accept", "text/event-stream")
+		ep.auth.apply(req)
+
+		resp, err := c.streamClient.Do(req)
+		if err != nil {
+			lastErr = fmt.Errorf("event stream request failed: %w", err)
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			lastErr = &StatusError{resp.StatusCode, fmt.Sprintf("event stream HTTP %d - URL: %s", resp.StatusCode, redactURL(ep.url+path))}
+			continue
+		}
+		return resp, ep, nil
+	}
+
+	return nil, nil, lastErr
+}
+
+// readEvents parses server-sent events until the stream ends
+func readEvents(ctx context.Context, resp *http.Response, events chan<- Event) error {
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxEventSize)
+
+	var topic string
+	var data strings.Builder
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case line == "":
+			// A blank line dispatches the event
+			if topic != "" && data.Len() > 0 {
+				select {
+				case events <- Event{Topic: topic, Data: json.RawMessage(data.String())}:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			topic = ""
+			data.Reset()
+		case strings.HasPrefix(line, "event:"):
+			topic = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimSpace(strings.TrimPrefix(line, "data:")))
+		}
+		// Comments (":") and other fields such as id/retry are ignored
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return fmt.Errorf("event stream closed")
+}
@@ -0,0 +1,206 @@
+package beacon
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// Failed endpoints are tried last until this long after their latest failure
+	failoverCooldown = 30 * time.Second
+	// Another endpoint only takes over from a healthy active one if it is clearly faster
+	switchLatencyRatio = 0.8
+	// Weight of the newest sample in the latency moving average
+	latencyEWMAWeight = 0.2
+)
+
+// endpoint is one beacon node the client can send requests to
+type endpoint struct {
+	url  string         // Base URL without trailing slash
+	name string         // URL without credentials, used in logs and metrics
+	auth *authenticator // Credentials added to every request, nil without any
+
+	mu           sync.Mutex
+	latency      time.Duration // Moving average of successful request latency, 0 until measured
+	latencyTotal time.Duration // Summed latency of successful requests
+	failures     int           // Consecutive failures
+	lastFailure  time.Time
+	requests     uint64
+	failureTotal uint64
+	node         NodeInfo
+}
+
+// EndpointStatus is a snapshot of an endpoint's health
+type EndpointStatus struct {
+	Name         string
+	Active       bool
+	Healthy      bool
+	Latency      time.Duration
+	LatencyTotal time.Duration // Summed latency of successful requests
+	Requests     uint64
+	Failures     uint64
+	Node         NodeInfo
+}
+
+func newEndpoint(rawURL string) *endpoint {
+	base := strings.TrimSuffix(rawURL, "/")
+	return &endpoint{
+		url:  base,
+		name: redactURL(base),
+		node: NodeInfo{Client: ClientUnknown},
+	}
+}
+
+// redactURL drops credentials from a URL
+func redactURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.User == nil {
+		return rawURL
+	}
+	parsed.User = nil
+	return parsed.String()
+}
+
+// recordSuccess folds a successful request's latency into the endpoint's score
+func (e *endpoint) recordSuccess(latency time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.requests++
+	e.failures = 0
+	e.latencyTotal += latency
+	if e.latency == 0 {
+		e.latency = latency
+	} else {
+		e.latency = time.Duration(latencyEWMAWeight*float64(latency) + (1-latencyEWMAWeight)*float64(e.latency))
+	}
+}
+
+// recordFailure marks the endpoint as failing
+func (e *endpoint) recordFailure(now time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.requests++
+	e.failureTotal++
+	e.failures++
+	e.lastFailure = now
+}
+
+// healthy returns false while the endpoint is cooling down after a failure
+func (e *endpoint) healthy(now time.Time) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.failures == 0 || now.Sub(e.lastFailure) >= failoverCooldown
+}
+
+func (e *endpoint) averageLatency() time.Duration {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.latency
+}
+
+func (e *endpoint) setNode(node NodeInfo) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.node = node
+}
+
+func (e *endpoint) nodeInfo() NodeInfo {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.node
+}
+
+// orderedEndpoints returns endpoints in the order requests should try them:
+// healthy before failing, fastest first, keeping the active endpoint unless another is clearly faster
+// Unmeasured endpoints sort first so each gets probed once
+func (c *Client) orderedEndpoints() []*endpoint {
+	if len(c.endpoints) == 1 {
+		return c.endpoints
+	}
+
+	now := time.Now()
+	healthy := make(map[*endpoint]bool, len(c.endpoints))
+	for _, ep := range c.endpoints {
+		healthy[ep] = ep.healthy(now)
+	}
+
+	ordered := make([]*endpoint, len(c.endpoints))
+	copy(ordered, c.endpoints)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if healthy[ordered[i]] != healthy[ordered[j]] {
+			return healthy[ordered[i]]
+		}
+		return ordered[i].averageLatency() < ordered[j].averageLatency()
+	})
+
+	active := c.activeEndpoint()
+	if active == nil || !healthy[active] || ordered[0] == active {
+		return ordered
+	}
+	best := ordered[0].averageLatency()
+	if best == 0 || float64(best) < switchLatencyRatio*float64(active.averageLatency()) {
+		return ordered
+	}
+
+	// Keep the active endpoint in front to avoid flapping between similar nodes
+	result := []*endpoint{active}
+	for _, ep := range ordered {
+		if ep != active {
+			result = append(result, ep)
+		}
+	}
+	return result
+}
+
+// activeEndpoint returns the endpoint that served the latest successful request
+func (c *Client) activeEndpoint() *endpoint {
+	c.activeMu.RLock()
+	defer c.activeMu.RUnlock()
+
+	return c.active
+}
+
+// setActive records the endpoint that served a request, logging switches
+func (c *Client) setActive(ep *endpoint) {
+	c.activeMu.Lock()
+	previous := c.active
+	c.active = ep
+	c.activeMu.Unlock()
+
+	if previous != nil && previous != ep {
+		c.logger.WithField("from", previous.name).WithField("to", ep.name).Info("Switched active beacon endpoint")
+	}
+}
+
+// Endpoints returns the health of every configured endpoint
+func (c *Client) Endpoints() []EndpointStatus {
+	now := time.Now()
+	active := c.activeEndpoint()
+
+	statuses := make([]EndpointStatus, 0, len(c.endpoints))
+	for _, ep := range c.endpoints {
+		healthy := ep.healthy(now)
+		ep.mu.Lock()
+		statuses = append(statuses, EndpointStatus{
+			Name:         ep.name,
+			Active:       ep == active,
+			Healthy:      healthy,
+			Latency:      ep.latency,
+			LatencyTotal: ep.latencyTotal,
+			Requests:     ep.requests,
+			Failures:     ep.failureTotal,
+			Node:         ep.node,
+		})
+		ep.mu.Unlock()
+	}
+	return statuses
+}
@@ -0,0 +1,116 @@
+package beacon
+
+import (
+	"context"
+	"io"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// limiter paces the requests sent to the beacon nodes: a token bucket of requests per second and a
+// bound on the requests in flight, shared by every endpoint so failover doesn't multiply the load
+// Event streams are long-lived and aren't limited
+type limiter struct {
+	mu     sync.Mutex
+	rate   float64 // Tokens per second, 0 for no rate limit
+	burst  float64
+	tokens float64
+	last   time.Time
+
+	slots chan struct{} // Buffered to the concurrency bound, nil for unbounded
+
+	inFlight  atomic.Int64
+	waitNanos atomic.Int64 // Total time requests waited for a slot or a token
+}
+
+// newLimiter creates a limiter; zero values leave the rate or the concurrency unbounded
+// The burst defaults to one second of requests
+func newLimiter(limits models.BeaconRateLimit) *limiter {
+	l := &limiter{rate: limits.RequestsPerSec, last: time.Now()}
+	if l.rate > 0 {
+		l.burst = float64(limits.Burst)
+		if l.burst <= 0 {
+			l.burst = math.Max(1, math.Ceil(l.rate))
+		}
+		l.tokens = l.burst
+	}
+	if limits.MaxConcurrent > 0 {
+		l.slots = make(chan struct{}, limits.MaxConcurrent)
+	}
+	return l
+}
+
+// acquire waits for a concurrency slot and then a token; release frees the slot once the response is read
+func (l *limiter) acquire(ctx context.Context) (release func(), err error) {
+	start := time.Now()
+	defer func() {
+		l.waitNanos.Add(int64(time.Since(start)))
+	}()
+
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	var once sync.Once
+	release = func() {
+		once.Do(func() {
+			l.inFlight.Add(-1)
+			if l.slots != nil {
+				<-l.slots
+			}
+		})
+	}
+	l.inFlight.Add(1)
+
+	if delay := l.reserve(time.Now()); delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			release()
+			return nil, ctx.Err()
+		}
+	}
+	return release, nil
+}
+
+// reserve takes a token and returns how long to wait until it is available
+// A cancelled wait doesn't give its token back, erring on the side of fewer requests
+func (l *limiter) reserve(now time.Time) time.Duration {
+	if l.rate <= 0 {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// releasingBody frees a streamed request's slot when its body is closed
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+}
+
+// Close implements io.Closer
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.release()
+	return err
+}
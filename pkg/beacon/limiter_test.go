@@ -0,0 +1,110 @@
+package beacon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+func TestLimiterReserve(t *testing.T) {
+	l := newLimiter(models.BeaconRateLimit{RequestsPerSec: 10, Burst: 2})
+	now := l.last
+
+	// The burst goes out at once, then requests are spaced by 1/rate
+	if d := l.reserve(now); d != 0 {
+		t.Errorf("Expected the first request to go out at once, waited %v", d)
+	}
+	if d := l.reserve(now); d != 0 {
+		t.Errorf("Expected the second request to go out at once, waited %v", d)
+	}
+	if d := l.reserve(now); d != 100*time.Millisecond {
+		t.Errorf("Expected the third request to wait 100ms, waited %v", d)
+	}
+	if d := l.reserve(now); d != 200*time.Millisecond {
+		t.Errorf("Expected the fourth request to wait 200ms, waited %v", d)
+	}
+
+	// Tokens refill with time, up to the burst
+	if d := l.reserve(now.Add(time.Second)); d != 0 {
+		t.Errorf("Expected a refilled token after a second, waited %v", d)
+	}
+}
+
+func TestLimiterDefaultBurst(t *testing.T) {
+	l := newLimiter(models.BeaconRateLimit{RequestsPerSec: 2.5})
+	if l.burst != 3 {
+		t.Errorf("Expected a burst of one second of requests, got %v", l.burst)
+	}
+	if l := newLimiter(models.BeaconRateLimit{}); l.reserve(time.Now()) != 0 || l.slots != nil {
+		t.Error("Expected no limits by default")
+	}
+}
+
+func TestLimiterCancelledWait(t *testing.T) {
+	l := newLimiter(models.BeaconRateLimit{MaxConcurrent: 1})
+	release, err := l.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to acquire: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := l.acquire(ctx); err == nil {
+		t.Error("Expected the second request to wait for the slot until cancelled")
+	}
+
+	release()
+	release() // Releasing twice frees one slot
+	if got := l.inFlight.Load(); got != 0 {
+		t.Errorf("Expected no request in flight, got %d", got)
+	}
+	if _, err := l.acquire(context.Background()); err != nil {
+		t.Errorf("Expected the slot to be free after release: %v", err)
+	}
+}
+
+func TestClientMaxConcurrent(t *testing.T) {
+	var current, peak atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := current.Add(1)
+		defer current.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": models.Genesis{GenesisTime: 1}})
+	}))
+	defer server.Close()
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	client := NewClient(server.URL, 10*time.Second, logger)
+	client.SetRateLimit(models.BeaconRateLimit{MaxConcurrent: 2})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.GetGenesis(context.Background()); err != nil {
+				t.Errorf("GetGenesis failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := peak.Load(); got > 2 {
+		t.Errorf("Expected at most 2 requests in flight, saw %d", got)
+	}
+}
@@ -0,0 +1,233 @@
+package beacon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Consensus clients recognized from /eth/v1/node/version
+const (
+	ClientLighthouse = "lighthouse"
+	ClientPrysm      = "prysm"
+	ClientTeku       = "teku"
+	ClientNimbus     = "nimbus"
+	ClientLodestar   = "lodestar"
+	ClientGrandine   = "grandine"
+	ClientUnknown    = "unknown"
+)
+
+var knownClients = []string{ClientLighthouse, ClientPrysm, ClientTeku, ClientNimbus, ClientLodestar, ClientGrandine}
+
+// Response quirks the client tolerates
+// Each is learned the first time the node shows it and then handled directly on later requests
+const (
+	// QuirkUnquotedNumbers: numeric fields encoded as JSON numbers instead of decimal strings
+	QuirkUnquotedNumbers = "unquoted_numbers"
+	// QuirkNoValidatorsPOST: POST /states/{id}/validators is unsupported, so ids are sent as GET query parameters
+	QuirkNoValidatorsPOST = "no_validators_post"
+)
+
+// NodeInfo identifies the beacon node implementation
+type NodeInfo struct {
+	Client  string // One of the Client* constants
+	Version string // Version as reported, e.g. v5.1.3-3058b96
+	Raw     string // Full version string
+}
+
+// NodeVersionResponse is the /eth/v1/node/version response
+type NodeVersionResponse struct {
+	Data struct {
+		Version string `json:"version"`
+	} `json:"data"`
+}
+
+// ParseNodeVersion extracts the client name and version from a node version string
+// such as "Lighthouse/v5.1.3-3058b96/x86_64-linux" or "teku/v24.1.0/linux-x86_64/-eclipseadoptium-openjdk64bitservervm-java-21"
+func ParseNodeVersion(raw string) NodeInfo {
+	info := NodeInfo{Client: ClientUnknown, Raw: raw}
+
+	parts := strings.Split(raw, "/")
+	name := strings.ToLower(strings.TrimSpace(parts[0]))
+	for _, client := range knownClients {
+		if strings.HasPrefix(name, client) {
+			info.Client = client
+			break
+		}
+	}
+	if len(parts) > 1 {
+		info.Version = parts[1]
+	}
+
+	return info
+}
+
+// quirks tracks the response quirks seen from the beacon nodes
+// Quirks are learned from any node and tolerated for all of them
+type quirks struct {
+	mu      sync.RWMutex
+	learned map[string]bool
+	counts  map[string]map[string]uint64 // client -> quirk -> responses that needed it handled
+}
+
+func newQuirks() *quirks {
+	return &quirks{
+		learned: make(map[string]bool),
+		counts:  make(map[string]map[string]uint64),
+	}
+}
+
+// DetectNode queries the version of every endpoint and returns the active node
+// Endpoints that don't answer keep ClientUnknown; an error is returned only if none answered
+func (c *Client) DetectNode(ctx context.Context) (NodeInfo, error) {
+	var lastErr error
+	detected := false
+
+	timeout := c.policy("/eth/v1/node/version").timeout
+	for _, ep := range c.endpoints {
+		r, err := c.send(ctx, ep, timeout, http.MethodGet, "/eth/v1/node/version", nil, "")
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		var response NodeVersionResponse
+		if err := c.decode("/eth/v1/node/version", r.body, &response); err != nil {
+			lastErr = err
+			continue
+		}
+		ep.setNode(ParseNodeVersion(response.Data.Version))
+		detected = true
+	}
+
+	if !detected {
+		return NodeInfo{}, fmt.Errorf("failed to get node version: %w", lastErr)
+	}
+	return c.Node(), nil
+}
+
+// Node returns the active endpoint's node, with Client set to ClientUnknown before detection
+func (c *Client) Node() NodeInfo {
+	ep := c.activeEndpoint()
+	if ep == nil {
+		ep = c.endpoints[0]
+	}
+	return ep.nodeInfo()
+}
+
+// QuirkCounts returns how many responses from each client needed each quirk handled
+func (c *Client) QuirkCounts() map[string]map[string]uint64 {
+	c.quirks.mu.RLock()
+	defer c.quirks.mu.RUnlock()
+
+	counts := make(map[string]map[string]uint64, len(c.quirks.counts))
+	for client, byQuirk := range c.quirks.counts {
+		counts[client] = make(map[string]uint64, len(byQuirk))
+		for quirk, count := range byQuirk {
+			counts[client][quirk] = count
+		}
+	}
+	return counts
+}
+
+// hasQuirk returns true once the node has shown the quirk
+func (c *Client) hasQuirk(quirk string) bool {
+	c.quirks.mu.RLock()
+	defer c.quirks.mu.RUnlock()
+
+	return c.quirks.learned[quirk]
+}
+
+// recordQuirk counts a handled quirk against the active node, logging the first time it's seen
+func (c *Client) recordQuirk(quirk, path string) {
+	node := c.Node()
+
+	c.quirks.mu.Lock()
+	first := !c.quirks.learned[quirk]
+	c.quirks.learned[quirk] = true
+	if c.quirks.counts[node.Client] == nil {
+		c.quirks.counts[node.Client] = make(map[string]uint64)
+	}
+	c.quirks.counts[node.Client][quirk]++
+	c.quirks.mu.Unlock()
+
+	if first {
+		c.logger.WithFields(logrus.Fields{
+			"quirk":  quirk,
+			"client": node.Client,
+			"path":   path,
+		}).Info("Beacon node response quirk detected - tolerating it from now on")
+	}
+}
+
+// decode unmarshals a response, accepting numbers the node sent unquoted
+// Well-formed responses take the plain decode; once the node is known to send bare
+// numbers, responses are normalized up front instead of failing first
+func (c *Client) decode(path string, data []byte, result interface{}) error {
+	if !c.hasQuirk(QuirkUnquotedNumbers) {
+		err := json.Unmarshal(data, result)
+		if err == nil {
+			return nil
+		}
+
+		normalized, changed, normErr := quoteNumbers(data)
+		if normErr != nil || !changed || json.Unmarshal(normalized, result) != nil {
+			return err
+		}
+		c.recordQuirk(QuirkUnquotedNumbers, path)
+		return nil
+	}
+
+	normalized, changed, err := quoteNumbers(data)
+	if err != nil {
+		return err
+	}
+	if changed {
+		c.recordQuirk(QuirkUnquotedNumbers, path)
+	}
+	return json.Unmarshal(normalized, result)
+}
+
+// quoteNumbers rewrites every JSON number as a decimal string
+// All numeric model fields are decoded from strings, as the beacon API specifies
+func quoteNumbers(data []byte) ([]byte, bool, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, false, err
+	}
+
+	changed := false
+	var walk func(v interface{}) interface{}
+	walk = func(v interface{}) interface{} {
+		switch typed := v.(type) {
+		case json.Number:
+			changed = true
+			return typed.String()
+		case map[string]interface{}:
+			for key, item := range typed {
+				typed[key] = walk(item)
+			}
+		case []interface{}:
+			for i, item := range typed {
+				typed[i] = walk(item)
+			}
+		}
+		return v
+	}
+	value = walk(value)
+
+	if !changed {
+		return data, false, nil
+	}
+	normalized, err := json.Marshal(value)
+	return normalized, true, err
+}
@@ -0,0 +1,85 @@
+package beacon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// Responses kept for revalidation: the spec, genesis and a few epochs of duties and committees
+const responseCacheSize = 64
+
+// cachedResponse is a response body kept with its ETag
+type cachedResponse struct {
+	body  []byte
+	etag  string
+	epoch models.Epoch // Epoch the body was last fetched or revalidated in
+}
+
+// SetEpochClock lets responses that rarely change within an epoch be served from the cache until
+// the epoch ends, instead of revalidating them on every request
+func (c *Client) SetEpochClock(currentEpoch func() models.Epoch) {
+	c.epochClock = currentEpoch
+}
+
+// getCached GETs a path whose response rarely changes within an epoch (spec, genesis, duties and
+// committees of an epoch): a response fetched during the current epoch is served as is, an older
+// one is revalidated with its ETag, so an unchanged response costs a 304 instead of the whole body
+// Without an epoch clock every request is revalidated
+func (c *Client) getCached(ctx context.Context, path string, result interface{}) error {
+	return c.fetchCached(ctx, path, result, false)
+}
+
+// getRevalidated GETs a cached path like getCached, but revalidates the response even if it was
+// fetched during the current epoch, for callers that know it may have changed, e.g. after a reorg
+func (c *Client) getRevalidated(ctx context.Context, path string, result interface{}) error {
+	return c.fetchCached(ctx, path, result, true)
+}
+
+// fetchCached implements getCached and getRevalidated
+func (c *Client) fetchCached(ctx context.Context, path string, result interface{}, revalidate bool) error {
+	var epoch models.Epoch
+	if c.epochClock != nil {
+		epoch = c.epochClock()
+	}
+
+	cached, ok := c.responses.Get(path)
+	if ok && !revalidate && c.epochClock != nil && cached.epoch == epoch {
+		return c.decodeResponse(path, cached.body, result)
+	}
+
+	var etag string
+	if ok {
+		etag = cached.etag
+	}
+	r, err := c.exchange(ctx, http.MethodGet, path, nil, etag)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case r.notModified && !ok:
+		return errors.New("unexpected HTTP 304 to an unconditional request")
+	case r.notModified:
+		c.requestMetrics.revalidations.WithLabelValues("not_modified").Inc()
+		r.body = cached.body
+	case etag != "":
+		c.requestMetrics.revalidations.WithLabelValues("modified").Inc()
+	}
+
+	if r.etag != "" || c.epochClock != nil {
+		c.responses.Set(path, &cachedResponse{body: r.body, etag: r.etag, epoch: epoch})
+	}
+	return c.decodeResponse(path, r.body, result)
+}
+
+// decodeResponse decodes a response body like doRequest does
+func (c *Client) decodeResponse(path string, body []byte, result interface{}) error {
+	if err := c.decode(path, body, result); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package beacon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sirupsen/logrus"
+)
+
+func TestGetCachedRevalidatesWithETag(t *testing.T) {
+	var requests, notModified int
+	genesisTime := uint64(1606824023)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		etag := fmt.Sprintf(`"genesis-%d"`, genesisTime)
+		if r.Header.Get("If-None-Match") == etag {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": models.Genesis{GenesisTime: genesisTime}})
+	}))
+	defer server.Close()
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	client := NewClient(server.URL, 10*time.Second, logger)
+	ctx := context.Background()
+
+	// Without a clock, every request after the first is conditional
+	for i := 0; i < 2; i++ {
+		genesis, err := client.GetGenesis(ctx)
+		if err != nil {
+			t.Fatalf("GetGenesis failed: %v", err)
+		}
+		if genesis.GenesisTime != genesisTime {
+			t.Errorf("Expected genesis time %d, got %d", genesisTime, genesis.GenesisTime)
+		}
+	}
+	if requests != 2 || notModified != 1 {
+		t.Errorf("Expected a fetch and a 304, got %d requests and %d 304s", requests, notModified)
+	}
+
+	// With a clock, the current epoch's response is served without a request
+	epoch := models.Epoch(10)
+	client.SetEpochClock(func() models.Epoch { return epoch })
+	client.GetGenesis(ctx) // Revalidated, then fresh for epoch 10
+	client.GetGenesis(ctx)
+	if requests != 3 {
+		t.Errorf("Expected one request during the epoch, got %d in total", requests)
+	}
+
+	// The next epoch revalidates, and a changed response replaces the cached one
+	epoch++
+	genesisTime++
+	genesis, err := client.GetGenesis(ctx)
+	if err != nil {
+		t.Fatalf("GetGenesis failed: %v", err)
+	}
+	if genesis.GenesisTime != genesisTime || requests != 4 || notModified != 2 {
+		t.Errorf("Expected the changed response, got %d after %d requests and %d 304s", genesis.GenesisTime, requests, notModified)
+	}
+	if got := testutil.ToFloat64(client.requestMetrics.revalidations.WithLabelValues("not_modified")); got != 2 {
+		t.Errorf("Expected 2 not modified revalidations, got %v", got)
+	}
+	if got := testutil.ToFloat64(client.requestMetrics.revalidations.WithLabelValues("modified")); got != 1 {
+		t.Errorf("Expected 1 modified revalidation, got %v", got)
+	}
+}
+
+func TestProposerDutiesOfLaterEpochsNotCached(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": []models.ProposerDuty{}})
+	}))
+	defer server.Close()
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	client := NewClient(server.URL, 10*time.Second, logger)
+	client.SetEpochClock(func() models.Epoch { return 10 })
+	ctx := context.Background()
+
+	// The next epoch's duties can still change with the current epoch's blocks
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetProposerDuties(ctx, 11); err != nil {
+			t.Fatalf("GetProposerDuties failed: %v", err)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("Expected every request for a later epoch sent, got %d", requests)
+	}
+
+	// The current epoch's are served from the cache
+	client.GetProposerDuties(ctx, 10)
+	client.GetProposerDuties(ctx, 10)
+	if requests != 3 {
+		t.Errorf("Expected one request for the current epoch, got %d in total", requests-2)
+	}
+}
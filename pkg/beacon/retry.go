@@ -0,0 +1,155 @@
+package beacon
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// Request categories with their own timeout and retry policy
+const (
+	CategoryDefault    = "default"    // Genesis, spec, node version and anything not below
+	CategoryValidators = "validators" // Validator lookups and the full validator set
+	CategoryBlocks     = "blocks"     // Blocks, headers and block attestations
+	CategoryDuties     = "duties"     // Proposer and attester duties, liveness, committees
+	CategoryRewards    = "rewards"    // Attestation and block rewards
+	CategoryState      = "state"      // Other state queries: finality, pending queues
+)
+
+// Retry backoff strategies
+const (
+	BackoffLinear      = "linear"      // delay, 2*delay, 3*delay, ...
+	BackoffExponential = "exponential" // delay, 2*delay, 4*delay, ... up to max_delay_ms
+)
+
+// categories lists the categories a policy can be configured for
+var categories = []string{CategoryDefault, CategoryValidators, CategoryBlocks, CategoryDuties, CategoryRewards, CategoryState}
+
+// categoryOf returns the category of a request path
+func categoryOf(path string) string {
+	r := route(path)
+	switch {
+	case strings.HasPrefix(r, "/eth/v1/beacon/states/") && strings.Contains(r, "/validator"):
+		return CategoryValidators
+	case strings.Contains(r, "/committees") || strings.HasPrefix(r, "/eth/v1/validator/"):
+		return CategoryDuties
+	case strings.HasPrefix(r, "/eth/v1/beacon/states/"):
+		return CategoryState
+	case strings.HasPrefix(r, "/eth/v1/beacon/rewards/"):
+		return CategoryRewards
+	case strings.Contains(r, "/beacon/blocks/") || strings.HasPrefix(r, "/eth/v1/beacon/headers"):
+		return CategoryBlocks
+	default:
+		return CategoryDefault
+	}
+}
+
+// retryPolicy is how long a request attempt may take and how failed ones are retried
+type retryPolicy struct {
+	timeout     time.Duration // Per attempt, 0 for none
+	maxAttempts int
+	backoff     string
+	delay       time.Duration
+	maxDelay    time.Duration // Cap of exponential delays, 0 for none
+	jitter      bool
+}
+
+// defaultPolicy is the policy of every category unless configured: three attempts, 2s and 4s apart
+func defaultPolicy(timeout time.Duration) retryPolicy {
+	return retryPolicy{timeout: timeout, maxAttempts: maxRetries, backoff: BackoffLinear, delay: retryDelay}
+}
+
+// merge returns the policy with the configured fields of cfg replacing its own
+func (p retryPolicy) merge(cfg models.BeaconRetryPolicy) retryPolicy {
+	if cfg.Timeout > 0 {
+		p.timeout = cfg.Timeout.ToDuration()
+	}
+	if cfg.MaxAttempts > 0 {
+		p.maxAttempts = cfg.MaxAttempts
+	}
+	if cfg.Backoff != "" {
+		p.backoff = cfg.Backoff
+	}
+	if cfg.DelayMs > 0 {
+		p.delay = time.Duration(cfg.DelayMs) * time.Millisecond
+	}
+	if cfg.MaxDelayMs > 0 {
+		p.maxDelay = time.Duration(cfg.MaxDelayMs) * time.Millisecond
+	}
+	if cfg.Jitter != nil {
+		p.jitter = *cfg.Jitter
+	}
+	return p
+}
+
+// wait returns the delay before a retry (attempt counts from 1 for the first retry)
+// Jitter picks a delay between half and all of it, so clients retrying together spread out
+func (p retryPolicy) wait(attempt int) time.Duration {
+	d := p.delay * time.Duration(attempt)
+	if p.backoff == BackoffExponential {
+		d = p.delay << min(attempt-1, 30)
+	}
+	if p.maxDelay > 0 && d > p.maxDelay {
+		d = p.maxDelay
+	}
+	if p.jitter && d > 1 {
+		d = d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+	}
+	return d
+}
+
+// ValidateRetry checks the retry policies of the beacon requests
+func ValidateRetry(cfg models.BeaconRetry) error {
+	if err := validatePolicy(cfg.Default); err != nil {
+		return fmt.Errorf("default: %w", err)
+	}
+	for name, policy := range cfg.Categories {
+		known := false
+		for _, category := range categories {
+			known = known || name == category
+		}
+		if !known {
+			return fmt.Errorf("unknown category %q (one of %s)", name, strings.Join(categories, ", "))
+		}
+		if err := validatePolicy(policy); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// validatePolicy checks a single policy
+func validatePolicy(p models.BeaconRetryPolicy) error {
+	switch p.Backoff {
+	case "", BackoffLinear, BackoffExponential:
+	default:
+		return fmt.Errorf("unknown backoff %q (linear or exponential)", p.Backoff)
+	}
+	if p.Timeout < 0 || p.MaxAttempts < 0 || p.DelayMs < 0 || p.MaxDelayMs < 0 {
+		return fmt.Errorf("timeout_sec, max_attempts, delay_ms and max_delay_ms must not be negative")
+	}
+	return nil
+}
+
+// SetRetryPolicies sets the timeout and retries of subsequent requests by category; unset fields
+// of a category fall back to the default policy, and those of the default to the client's timeout,
+// three attempts and a linear 2s backoff
+func (c *Client) SetRetryPolicies(cfg models.BeaconRetry) {
+	base := c.defaultPolicy.merge(cfg.Default)
+	policies := make(map[string]retryPolicy, len(categories))
+	for _, category := range categories {
+		policies[category] = base.merge(cfg.Categories[category])
+	}
+	c.policies = policies
+}
+
+// policy returns the retry policy of a request path
+func (c *Client) policy(path string) retryPolicy {
+	if p, ok := c.policies[categoryOf(path)]; ok {
+		return p
+	}
+	return c.defaultPolicy
+}
@@ -0,0 +1,109 @@
+package beacon
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+func TestCategoryOf(t *testing.T) {
+	tests := map[string]string{
+		"/eth/v1/beacon/states/head/validators":           CategoryValidators,
+		"/eth/v1/beacon/states/123/validators?id=1,2":     CategoryValidators,
+		"/eth/v2/beacon/blocks/9876543":                   CategoryBlocks,
+		"/eth/v1/beacon/blocks/9876543/attestations":      CategoryBlocks,
+		"/eth/v1/beacon/headers/head":                     CategoryBlocks,
+		"/eth/v1/validator/duties/proposer/300":           CategoryDuties,
+		"/eth/v1/validator/liveness/300":                  CategoryDuties,
+		"/eth/v1/beacon/states/head/committees?epoch=300": CategoryDuties,
+		"/eth/v1/beacon/rewards/attestations/300":         CategoryRewards,
+		"/eth/v1/beacon/states/head/finality_checkpoints": CategoryState,
+		"/eth/v1/beacon/states/head/pending_deposits":     CategoryState,
+		"/eth/v1/config/spec":                             CategoryDefault,
+		"/eth/v1/node/version":                            CategoryDefault,
+	}
+	for path, want := range tests {
+		if got := categoryOf(path); got != want {
+			t.Errorf("categoryOf(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestRetryPolicyWait(t *testing.T) {
+	linear := defaultPolicy(time.Minute)
+	if linear.wait(1) != 2*time.Second || linear.wait(2) != 4*time.Second {
+		t.Errorf("linear waits = %v, %v, want 2s, 4s", linear.wait(1), linear.wait(2))
+	}
+
+	exponential := retryPolicy{backoff: BackoffExponential, delay: 100 * time.Millisecond, maxDelay: 500 * time.Millisecond}
+	for attempt, want := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 3: 400 * time.Millisecond, 4: 500 * time.Millisecond} {
+		if got := exponential.wait(attempt); got != want {
+			t.Errorf("exponential wait(%d) = %v, want %v", attempt, got, want)
+		}
+	}
+
+	exponential.jitter = true
+	for i := 0; i < 100; i++ {
+		if got := exponential.wait(3); got < 200*time.Millisecond || got > 400*time.Millisecond {
+			t.Fatalf("jittered wait = %v, want within [200ms, 400ms]", got)
+		}
+	}
+}
+
+func TestSetRetryPoliciesInheritsDefault(t *testing.T) {
+	client := NewClient("http://localhost:5052", 90*time.Second, logrus.New())
+	client.SetRetryPolicies(models.BeaconRetry{
+		Default: models.BeaconRetryPolicy{MaxAttempts: 5},
+		Categories: map[string]models.BeaconRetryPolicy{
+			CategoryValidators: {Timeout: models.Duration(5 * time.Minute)},
+			CategoryBlocks:     {Timeout: models.Duration(5 * time.Second), MaxAttempts: 1},
+		},
+	})
+
+	validators := client.policy("/eth/v1/beacon/states/head/validators")
+	if validators.timeout != 5*time.Minute || validators.maxAttempts != 5 {
+		t.Errorf("validators policy = %+v, want a 5m timeout and the default's 5 attempts", validators)
+	}
+	blocks := client.policy("/eth/v2/beacon/blocks/1")
+	if blocks.timeout != 5*time.Second || blocks.maxAttempts != 1 {
+		t.Errorf("blocks policy = %+v, want a 5s timeout and 1 attempt", blocks)
+	}
+	spec := client.policy("/eth/v1/config/spec")
+	if spec.timeout != 90*time.Second || spec.delay != retryDelay {
+		t.Errorf("default policy = %+v, want the client timeout and 2s delay", spec)
+	}
+}
+
+func TestRetryPolicyAttemptsAndTimeout(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		time.Sleep(50 * time.Millisecond)
+		w.Write([]byte(`{"data":{}}`))
+	}))
+	defer server.Close()
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	client := NewClient(server.URL, 10*time.Second, logger)
+	client.SetRetryPolicies(models.BeaconRetry{Categories: map[string]models.BeaconRetryPolicy{
+		CategoryBlocks: {MaxAttempts: 2, DelayMs: 1},
+	}})
+	// A timeout shorter than the response, set directly since timeout_sec has second granularity
+	p := client.policies[CategoryBlocks]
+	p.timeout = 10 * time.Millisecond
+	client.policies[CategoryBlocks] = p
+
+	if _, err := client.GetBlock(context.Background(), "1"); err == nil {
+		t.Fatal("GetBlock succeeded past its timeout")
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("requests = %d, want max_attempts 2", got)
+	}
+}
@@ -0,0 +1,206 @@
+package beacon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/tracing"
+)
+
+// StreamAllValidators decodes the full validator set one validator at a time, calling fn for each
+// Only one validator is held in memory at a time instead of the whole 2M+ validator response.
+// Opening the request fails over and retries like any other; a failure mid-stream is returned
+// as is, since fn has already seen part of the set
+func (c *Client) StreamAllValidators(ctx context.Context, stateID string, fn func(models.Validator)) (int, error) {
+	path := fmt.Sprintf("/eth/v1/beacon/states/%s/validators", stateID)
+
+	resp, err := c.openStream(ctx, path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get all validators: %w", err)
+	}
+	defer resp.Body.Close()
+
+	count, err := decodeDataArray(resp.Body, func(raw json.RawMessage) error {
+		var v models.Validator
+		if err := c.decode(path, raw, &v); err != nil {
+			return err
+		}
+		fn(v)
+		return nil
+	})
+	if err != nil {
+		return count, fmt.Errorf("failed to decode all validators after %d: %w", count, err)
+	}
+
+	c.logger.Infof("Loaded %d validators from beacon node", count)
+	return count, nil
+}
+
+// openStream GETs a path for streamed decoding, with the failover and retries of doRequest
+// The caller closes the response body
+func (c *Client) openStream(ctx context.Context, path string) (resp *http.Response, err error) {
+	ctx, span := startCall(ctx, http.MethodGet, path)
+	defer func() { tracing.End(span, err) }()
+
+	var lastErr error
+	policy := c.policy(path)
+
+	for attempt := 0; attempt < policy.maxAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(policy.wait(attempt)):
+			}
+			c.logger.Debugf("Retrying request to %s (attempt %d/%d)", path, attempt+1, policy.maxAttempts)
+		}
+
+		for _, ep := range c.orderedEndpoints() {
+			resp, err := c.get(ctx, ep, policy.timeout, path)
+			if err == nil {
+				return resp, nil
+			}
+
+			lastErr = err
+			var statusErr *StatusError
+			if errors.As(err, &statusErr) && statusErr.StatusCode < 500 {
+				return nil, err
+			}
+			if ctx.Err() != nil {
+				return nil, err
+			}
+
+			ep.recordFailure(time.Now())
+			if len(c.endpoints) > 1 {
+				c.logger.WithError(err).WithField("endpoint", ep.name).Debug("Beacon endpoint failed - trying next endpoint")
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("request failed after %d attempts: %w", policy.maxAttempts, lastErr)
+}
+
+// get opens a GET request against one endpoint, leaving the body unread
+// Its span ends at the response headers, like the request duration metrics; the timeout (0 for none)
+// covers reading the body too
+func (c *Client) get(ctx context.Context, ep *endpoint, timeout time.Duration, path string) (resp *http.Response, err error) {
+	ctx, span := startAttempt(ctx, ep, http.MethodGet)
+	status := 0
+	defer func() { endAttempt(span, status, err) }()
+
+	cancel := context.CancelFunc(func() {})
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	}
+	defer func() {
+		if err != nil {
+			cancel()
+		}
+	}()
+
+	url := ep.url + path
+	c.logger.Debugf("Making streamed request: GET %s", redactURL(url))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", contentTypeJSON)
+	ep.auth.apply(req)
+
+	// The slot is held until the caller has read and closed the body
+	release, err := c.limiter.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	resp, err = c.httpClient.Do(req)
+	if err != nil {
+		release()
+		c.requestMetrics.observe(ctx, ep.name, time.Since(start), 0, err)
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	status = resp.StatusCode
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: func() {
+		release()
+		cancel()
+	}}
+	// Time to the response headers: the body is decoded as it streams in
+	c.requestMetrics.observe(ctx, ep.name, time.Since(start), resp.StatusCode, nil)
+
+	if resp.StatusCode >= 400 {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, &StatusError{resp.StatusCode, fmt.Sprintf("HTTP %d: %s - URL: %s", resp.StatusCode, string(body), redactURL(url))}
+	}
+
+	ep.recordSuccess(time.Since(start))
+	c.setActive(ep)
+	return resp, nil
+}
+
+// decodeDataArray walks a {"data": [...]} response token by token, passing each array element to fn
+// Other top-level fields are skipped
+func decodeDataArray(r io.Reader, fn func(json.RawMessage) error) (int, error) {
+	decoder := json.NewDecoder(r)
+	if err := expectDelim(decoder, '{'); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	found := false
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return count, err
+		}
+		if key, _ := token.(string); key != "data" {
+			var skip json.RawMessage
+			if err := decoder.Decode(&skip); err != nil {
+				return count, err
+			}
+			continue
+		}
+
+		found = true
+		if err := expectDelim(decoder, '['); err != nil {
+			return count, err
+		}
+		for decoder.More() {
+			var raw json.RawMessage
+			if err := decoder.Decode(&raw); err != nil {
+				return count, err
+			}
+			if err := fn(raw); err != nil {
+				return count, err
+			}
+			count++
+		}
+		if err := expectDelim(decoder, ']'); err != nil {
+			return count, err
+		}
+	}
+
+	if !found {
+		return 0, errors.New("response has no data field")
+	}
+	return count, expectDelim(decoder, '}')
+}
+
+// expectDelim reads the next token and checks it is the delimiter
+func expectDelim(decoder *json.Decoder, delim json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if token != delim {
+		return fmt.Errorf("expected %q, got %v", delim, token)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package beacon
+
+import (
+	"context"
+	"strings"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/tracing"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// route returns a request path with its slot, epoch, index, root and pubkey segments replaced by
+// {id}, so spans of the same endpoint share a name
+func route(path string) string {
+	path, _, _ = strings.Cut(path, "?")
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		if isPathID(s) {
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// isPathID reports whether a path segment is a number or a 0x-prefixed hex value
+func isPathID(segment string) bool {
+	if strings.HasPrefix(segment, "0x") {
+		return true
+	}
+	if segment == "" {
+		return false
+	}
+	for _, r := range segment {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// startCall starts the span of a beacon API call, across its retries and endpoints
+// Only the route is recorded, so validator indices and pubkeys in the path don't reach the traces
+func startCall(ctx context.Context, method, path string) (context.Context, trace.Span) {
+	r := route(path)
+	return tracing.Start(ctx, "beacon "+r,
+		tracing.AttrRoute.String(r),
+		semconv.HTTPRequestMethodKey.String(method),
+		semconv.URLPath(r),
+	)
+}
+
+// startAttempt starts the span of one request of a call to an endpoint
+func startAttempt(ctx context.Context, ep *endpoint, method string) (context.Context, trace.Span) {
+	return tracing.Start(ctx, method+" "+ep.name, tracing.AttrEndpoint.String(ep.name))
+}
+
+// endAttempt ends an attempt's span with the response status, 0 if there was no response
+func endAttempt(span trace.Span, status int, err error) {
+	if status != 0 {
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+	}
+	tracing.End(span, err)
+}
@@ -0,0 +1,18 @@
+package beacon
+
+import "testing"
+
+func TestRoute(t *testing.T) {
+	tests := map[string]string{
+		"/eth/v2/beacon/blocks/9876543":                           "/eth/v2/beacon/blocks/{id}",
+		"/eth/v1/beacon/states/head/validators":                   "/eth/v1/beacon/states/head/validators",
+		"/eth/v1/beacon/states/0xabc123/committees?epoch=12":      "/eth/v1/beacon/states/{id}/committees",
+		"/eth/v1/validator/duties/proposer/300":                   "/eth/v1/validator/duties/proposer/{id}",
+		"/eth/v1/beacon/states/finalized/validators/0x8f1e2d3c4b": "/eth/v1/beacon/states/finalized/validators/{id}",
+	}
+	for path, want := range tests {
+		if got := route(path); got != want {
+			t.Errorf("route(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/config"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"gopkg.in/yaml.v3"
+)
+
+// networksByGenesisRoot names the public networks by their genesis validators root
+var networksByGenesisRoot = map[string]string{
+	"0x4b363db94e286120d76eb905340fdd4e54bfe9f06bf33ff6cf5ad27f511bfe95": "mainnet",
+	"0x9143aa7c615a7f7115e2b6aac319c03529df8242ae705fba9df39b79c59fa8b1": "holesky",
+	"0xd8ea171f3c94aea21ebc42a1ed61052acf3f9209c00e4efbaaddac09ed9b8078": "sepolia",
+	"0x212f13fc4df078b6cb7db228f1c8307566dcecf900867401a92023d7ba99cb5f": "hoodi",
+	"0xf5dcb5564e829aab27264b9becd5dfaa017085611224cb3036f573368dbb9d47": "gnosis",
+}
+
+// NetworkName returns the public network with a genesis validators root, empty if unknown
+func NetworkName(genesisValidatorsRoot string) string {
+	return networksByGenesisRoot[strings.ToLower(genesisValidatorsRoot)]
+}
+
+// normalizePubkey lowercases a BLS public key and adds the 0x prefix, rejecting anything else
+func normalizePubkey(value string) (string, error) {
+	pubkey := strings.ToLower(strings.TrimSpace(value))
+	if !strings.HasPrefix(pubkey, "0x") {
+		pubkey = "0x" + pubkey
+	}
+	if len(pubkey) != 98 {
+		return "", fmt.Errorf("%q is not a BLS public key", value)
+	}
+	for _, c := range pubkey[2:] {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			return "", fmt.Errorf("%q is not a BLS public key", value)
+		}
+	}
+	return pubkey, nil
+}
+
+// keystoresResponse is the keymanager API's list of local keystores
+type keystoresResponse struct {
+	Data []struct {
+		ValidatingPubkey string `json:"validating_pubkey"`
+	} `json:"data"`
+}
+
+// KeysFromKeymanager lists the keys a validator client signs with through its keymanager API
+// (GET /eth/v1/keystores), authenticated with the API token
+func KeysFromKeymanager(ctx context.Context, client *http.Client, url, token string) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(url, "/")+"/eth/v1/keystores", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var keystores keystoresResponse
+	if err := json.NewDecoder(resp.Body).Decode(&keystores); err != nil {
+		return nil, fmt.Errorf("failed to decode keystores: %w", err)
+	}
+
+	pubkeys := make([]string, 0, len(keystores.Data))
+	for _, keystore := range keystores.Data {
+		pubkey, err := normalizePubkey(keystore.ValidatingPubkey)
+		if err != nil {
+			return nil, err
+		}
+		pubkeys = append(pubkeys, pubkey)
+	}
+	return pubkeys, nil
+}
+
+// KeysFromFile reads public keys from a deposit data file (a JSON list of objects with a pubkey),
+// a JSON list of keys, or plain text with one key per line and # comments
+func KeysFromFile(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseKeys(data)
+}
+
+// parseKeys decodes the key file formats accepted by KeysFromFile
+func parseKeys(data []byte) ([]string, error) {
+	var values []string
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("[")) {
+		var deposits []struct {
+			Pubkey string `json:"pubkey"`
+		}
+		if err := json.Unmarshal(trimmed, &deposits); err == nil {
+			for _, deposit := range deposits {
+				values = append(values, deposit.Pubkey)
+			}
+		} else if err := json.Unmarshal(trimmed, &values); err != nil {
+			return nil, fmt.Errorf("failed to parse key list: %w", err)
+		}
+	} else {
+		for _, line := range strings.Split(string(data), "\n") {
+			if line, _, _ = strings.Cut(line, "#"); strings.TrimSpace(line) != "" {
+				values = append(values, line)
+			}
+		}
+	}
+
+	pubkeys := make([]string, 0, len(values))
+	for _, value := range values {
+		pubkey, err := normalizePubkey(value)
+		if err != nil {
+			return nil, err
+		}
+		pubkeys = append(pubkeys, pubkey)
+	}
+	return pubkeys, nil
+}
+
+// WithdrawalAddressMatcher returns a filter of the validators withdrawing to an execution address
+// (0x01 or 0x02 withdrawal credentials)
+func WithdrawalAddressMatcher(value string) (func(models.Validator) bool, error) {
+	address := strings.TrimPrefix(strings.ToLower(value), "0x")
+	if len(address) != 40 || strings.Trim(address, "0123456789abcdef") != "" {
+		return nil, fmt.Errorf("%q is not an execution address", value)
+	}
+
+	suffix := strings.Repeat("0", 22) + address
+	return func(v models.Validator) bool {
+		credentials := strings.ToLower(v.Data.WithdrawalCredentials)
+		return (strings.HasPrefix(credentials, "0x01") || strings.HasPrefix(credentials, "0x02")) &&
+			strings.HasSuffix(credentials, suffix)
+	}, nil
+}
+
+// Options describe the starter config
+type Options struct {
+	Network   string
+	BeaconURL string
+	Keys      []string // Public keys, duplicates are dropped
+	Labels    []string // Given to every key
+	Source    string   // Where the keys came from, noted in the header
+	Node      string   // Beacon node client and version, noted in the header
+}
+
+// starterConfig is the part of the config the init command writes; everything else keeps its default
+type starterConfig struct {
+	Network     string              `yaml:"network"`
+	BeaconURL   string              `yaml:"beacon_url"`
+	MetricsPort int                 `yaml:"metrics_port"`
+	WatchedKeys []models.WatchedKey `yaml:"watched_keys"`
+}
+
+// Render writes a ready-to-run config file, validated like any loaded config
+func Render(w io.Writer, opts Options, now time.Time) error {
+	seen := make(map[string]bool, len(opts.Keys))
+	var pubkeys []string
+	for _, pubkey := range opts.Keys {
+		if !seen[pubkey] {
+			seen[pubkey] = true
+			pubkeys = append(pubkeys, pubkey)
+		}
+	}
+	sort.Strings(pubkeys)
+
+	cfg := config.DefaultConfig()
+	cfg.Network = opts.Network
+	cfg.BeaconURL = opts.BeaconURL
+	for _, pubkey := range pubkeys {
+		cfg.WatchedKeys = append(cfg.WatchedKeys, models.WatchedKey{PublicKey: pubkey, Labels: opts.Labels})
+	}
+	if err := config.ValidateConfig(cfg); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+
+	body, err := yaml.Marshal(starterConfig{
+		Network:     cfg.Network,
+		BeaconURL:   cfg.BeaconURL,
+		MetricsPort: cfg.MetricsPort,
+		WatchedKeys: cfg.WatchedKeys,
+	})
+	if err != nil {
+		return err
+	}
+
+	header := fmt.Sprintf("# Generated by eth-validator-watcher init on %s\n", now.UTC().Format(time.RFC3339))
+	if opts.Node != "" {
+		header += fmt.Sprintf("# Beacon node: %s\n", opts.Node)
+	}
+	header += fmt.Sprintf("# %d keys from %s\n", len(pubkeys), opts.Source)
+	header += "# See config.example.yaml for every other option\n\n"
+
+	_, err = io.WriteString(w, header+string(body))
+	return err
+}
@@ -0,0 +1,106 @@
+package bootstrap
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/config"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"gopkg.in/yaml.v3"
+)
+
+var testKey = "0x" + strings.Repeat("ab", 48)
+
+func TestParseKeys(t *testing.T) {
+	formats := map[string]string{
+		"deposit data": `[{"pubkey": "` + strings.Repeat("ab", 48) + `", "amount": 32000000000}]`,
+		"json list":    `["` + strings.ToUpper(testKey[2:]) + `"]`,
+		"text":         "# operator keys\n" + testKey + "  # first\n\n",
+	}
+	for name, data := range formats {
+		keys, err := parseKeys([]byte(data))
+		if err != nil || len(keys) != 1 || keys[0] != testKey {
+			t.Errorf("%s: parseKeys() = %v, %v, want [%s]", name, keys, err, testKey)
+		}
+	}
+
+	if _, err := parseKeys([]byte("0x1234\n")); err == nil {
+		t.Error("parseKeys() accepted a short key")
+	}
+}
+
+func TestKeysFromKeymanager(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/eth/v1/keystores" || r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"data": [{"validating_pubkey": "` + testKey + `", "derivation_path": "m/12381/3600/0/0/0", "readonly": false}]}`))
+	}))
+	defer server.Close()
+
+	keys, err := KeysFromKeymanager(context.Background(), server.Client(), server.URL+"/", "secret")
+	if err != nil || len(keys) != 1 || keys[0] != testKey {
+		t.Errorf("KeysFromKeymanager() = %v, %v, want [%s]", keys, err, testKey)
+	}
+	if _, err := KeysFromKeymanager(context.Background(), server.Client(), server.URL, "wrong"); err == nil {
+		t.Error("KeysFromKeymanager() ignored an unauthorized response")
+	}
+}
+
+func TestWithdrawalAddressMatcher(t *testing.T) {
+	matches, err := WithdrawalAddressMatcher("0x00000000219AB540356cBB839Cbe05303d7705Fa")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var v models.Validator
+	for credentials, want := range map[string]bool{
+		"0x01000000000000000000000000000000219ab540356cbb839cbe05303d7705fa": true,
+		"0x02000000000000000000000000000000219ab540356cbb839cbe05303d7705fa": true,
+		"0x00000000000000000000000000000000219ab540356cbb839cbe05303d7705fa": false,
+		"0x010000000000000000000000000000000000000000000000000000000000dead": false,
+	} {
+		v.Data.WithdrawalCredentials = credentials
+		if got := matches(v); got != want {
+			t.Errorf("matches(%s) = %v, want %v", credentials, got, want)
+		}
+	}
+
+	if _, err := WithdrawalAddressMatcher("0x1234"); err == nil {
+		t.Error("WithdrawalAddressMatcher() accepted a short address")
+	}
+}
+
+func TestRender(t *testing.T) {
+	var out strings.Builder
+	err := Render(&out, Options{
+		Network:   "holesky",
+		BeaconURL: "http://beacon:5052",
+		Keys:      []string{testKey, testKey},
+		Labels:    []string{"operator:acme", "source:file"},
+		Source:    "keys.txt",
+		Node:      "lighthouse v5.1.3",
+	}, time.Unix(0, 0))
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if !strings.HasPrefix(out.String(), "# Generated by eth-validator-watcher init on 1970-01-01T00:00:00Z\n") {
+		t.Errorf("Missing header:\n%s", out.String())
+	}
+
+	cfg := config.DefaultConfig()
+	if err := yaml.Unmarshal([]byte(out.String()), cfg); err != nil {
+		t.Fatalf("Rendered config does not parse: %v", err)
+	}
+	if err := config.ValidateConfig(cfg); err != nil {
+		t.Errorf("Rendered config is invalid: %v", err)
+	}
+	if cfg.Network != "holesky" || len(cfg.WatchedKeys) != 1 || len(cfg.WatchedKeys[0].Labels) != 2 {
+		t.Errorf("Unexpected config: network %s, keys %+v", cfg.Network, cfg.WatchedKeys)
+	}
+}
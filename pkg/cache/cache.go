@@ -0,0 +1,160 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Stats is a snapshot of a cache's counters
+type Stats struct {
+	Hits        uint64
+	Misses      uint64
+	Evictions   uint64 // Entries dropped to stay within the size limit
+	Expirations uint64 // Entries dropped because their TTL elapsed
+	Size        int
+}
+
+// Source is anything that exposes cache statistics (used by the collector)
+type Source interface {
+	Name() string
+	Stats() Stats
+}
+
+// entry is a cached value with its expiry
+type entry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time
+}
+
+// Cache is a thread-safe LRU cache with a per-entry TTL
+// A maxEntries of 0 means unbounded, a ttl of 0 means entries never expire
+type Cache[K comparable, V any] struct {
+	name       string
+	maxEntries int
+	ttl        time.Duration
+
+	mu    sync.Mutex
+	items map[K]*list.Element
+	order *list.List // Front is most recently used
+	stats Stats
+	now   func() time.Time
+}
+
+// New creates a new cache
+func New[K comparable, V any](name string, maxEntries int, ttl time.Duration) *Cache[K, V] {
+	return &Cache[K, V]{
+		name:       name,
+		maxEntries: maxEntries,
+		ttl:        ttl,
+		items:      make(map[K]*list.Element),
+		order:      list.New(),
+		now:        time.Now,
+	}
+}
+
+// Name returns the cache name used in metrics
+func (c *Cache[K, V]) Name() string {
+	return c.name
+}
+
+// Get returns the value for a key if present and not expired
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var zero V
+	elem, ok := c.items[key]
+	if !ok {
+		c.stats.Misses++
+		return zero, false
+	}
+
+	e := elem.Value.(*entry[K, V])
+	if c.expired(e) {
+		c.remove(elem)
+		c.stats.Expirations++
+		c.stats.Misses++
+		return zero, false
+	}
+
+	c.order.MoveToFront(elem)
+	c.stats.Hits++
+	return e.value, true
+}
+
+// Set stores a value, evicting the least recently used entry if the cache is full
+func (c *Cache[K, V]) Set(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expiresAt time.Time
+	if c.ttl > 0 {
+		expiresAt = c.now().Add(c.ttl)
+	}
+
+	if elem, ok := c.items[key]; ok {
+		e := elem.Value.(*entry[K, V])
+		e.value = value
+		e.expiresAt = expiresAt
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&entry[K, V]{key: key, value: value, expiresAt: expiresAt})
+
+	if c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		c.remove(c.order.Back())
+		c.stats.Evictions++
+	}
+}
+
+// Delete removes a key
+func (c *Cache[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		c.remove(elem)
+	}
+}
+
+// Purge removes all entries (counters are kept)
+func (c *Cache[K, V]) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.items = make(map[K]*list.Element)
+	c.order.Init()
+}
+
+// Len returns the number of entries, including expired ones not yet removed
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+// Stats returns a snapshot of the cache counters
+func (c *Cache[K, V]) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Size = c.order.Len()
+	return stats
+}
+
+// expired reports whether an entry's TTL has elapsed
+func (c *Cache[K, V]) expired(e *entry[K, V]) bool {
+	return !e.expiresAt.IsZero() && !c.now().Before(e.expiresAt)
+}
+
+// remove deletes an element (caller must hold the lock)
+func (c *Cache[K, V]) remove(elem *list.Element) {
+	e := elem.Value.(*entry[K, V])
+	delete(c.items, e.key)
+	c.order.Remove(elem)
+}
@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCacheLRUEviction(t *testing.T) {
+	c := New[int, string]("test", 2, 0)
+	c.Set(1, "a")
+	c.Set(2, "b")
+
+	// Touch 1 so 2 becomes the least recently used
+	if v, ok := c.Get(1); !ok || v != "a" {
+		t.Fatalf("Expected hit for key 1, got %q, %v", v, ok)
+	}
+	c.Set(3, "c")
+
+	if _, ok := c.Get(2); ok {
+		t.Error("Expected key 2 to be evicted")
+	}
+	if _, ok := c.Get(1); !ok {
+		t.Error("Expected key 1 to survive eviction")
+	}
+
+	stats := c.Stats()
+	if stats.Evictions != 1 || stats.Hits != 2 || stats.Misses != 1 || stats.Size != 2 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestCacheTTL(t *testing.T) {
+	now := time.Unix(1000, 0)
+	c := New[string, int]("test", 0, time.Minute)
+	c.now = func() time.Time { return now }
+
+	c.Set("k", 1)
+	if _, ok := c.Get("k"); !ok {
+		t.Fatal("Expected hit before TTL")
+	}
+
+	now = now.Add(time.Minute)
+	if _, ok := c.Get("k"); ok {
+		t.Error("Expected miss after TTL")
+	}
+
+	stats := c.Stats()
+	if stats.Expirations != 1 || stats.Size != 0 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestCacheOverwrite(t *testing.T) {
+	c := New[int, int]("test", 1, 0)
+	c.Set(1, 1)
+	c.Set(1, 2)
+
+	if v, _ := c.Get(1); v != 2 {
+		t.Errorf("Expected overwritten value 2, got %d", v)
+	}
+	if c.Stats().Evictions != 0 {
+		t.Error("Overwriting an existing key must not evict")
+	}
+
+	c.Purge()
+	if c.Len() != 0 {
+		t.Errorf("Expected empty cache after purge, got %d", c.Len())
+	}
+}
+
+func TestCollector(t *testing.T) {
+	c := New[int, int]("committees", 10, 0)
+	c.Set(1, 1)
+	c.Get(1)
+	c.Get(2)
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(NewCollector(c))
+
+	expected := `
+# HELP eth_cache_hits_total Total cache hits
+# TYPE eth_cache_hits_total counter
+eth_cache_hits_total{cache="committees"} 1
+# HELP eth_cache_misses_total Total cache misses
+# TYPE eth_cache_misses_total counter
+eth_cache_misses_total{cache="committees"} 1
+`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(expected),
+		"eth_cache_hits_total", "eth_cache_misses_total"); err != nil {
+		t.Error(err)
+	}
+}
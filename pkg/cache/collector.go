@@ -0,0 +1,65 @@
+package cache
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Collector exports hit/miss/eviction counters of registered caches to Prometheus
+type Collector struct {
+	mu      sync.RWMutex
+	sources []Source
+
+	hits      *prometheus.Desc
+	misses    *prometheus.Desc
+	evictions *prometheus.Desc
+	entries   *prometheus.Desc
+}
+
+// NewCollector creates a collector for the given caches
+func NewCollector(sources ...Source) *Collector {
+	return &Collector{
+		sources: sources,
+		hits: prometheus.NewDesc("eth_cache_hits_total",
+			"Total cache hits", []string{"cache"}, nil),
+		misses: prometheus.NewDesc("eth_cache_misses_total",
+			"Total cache misses", []string{"cache"}, nil),
+		evictions: prometheus.NewDesc("eth_cache_evictions_total",
+			"Total cache entries dropped, by reason (size or expired)", []string{"cache", "reason"}, nil),
+		entries: prometheus.NewDesc("eth_cache_entries",
+			"Current number of cache entries", []string{"cache"}, nil),
+	}
+}
+
+// Add registers more caches with the collector
+func (c *Collector) Add(sources ...Source) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sources = append(c.sources, sources...)
+}
+
+// Describe implements prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.hits
+	ch <- c.misses
+	ch <- c.evictions
+	ch <- c.entries
+}
+
+// Collect implements prometheus.Collector
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, source := range c.sources {
+		stats := source.Stats()
+		name := source.Name()
+		ch <- prometheus.MustNewConstMetric(c.hits, prometheus.CounterValue, float64(stats.Hits), name)
+		ch <- prometheus.MustNewConstMetric(c.misses, prometheus.CounterValue, float64(stats.Misses), name)
+		ch <- prometheus.MustNewConstMetric(c.evictions, prometheus.CounterValue, float64(stats.Evictions), name, "size")
+		ch <- prometheus.MustNewConstMetric(c.evictions, prometheus.CounterValue, float64(stats.Expirations), name, "expired")
+		ch <- prometheus.MustNewConstMetric(c.entries, prometheus.GaugeValue, float64(stats.Size), name)
+	}
+}
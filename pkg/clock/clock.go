@@ -37,6 +37,11 @@ func NewBeaconClock(genesis *models.Genesis, spec *models.Spec, logger *logrus.L
 	}
 }
 
+// SetSlotLag sets how long after a slot's end it is considered complete
+func (c *BeaconClock) SetSlotLag(seconds uint64) {
+	c.slotLagSeconds = seconds
+}
+
 // EnableReplayMode enables replay mode with start and end timestamps
 func (c *BeaconClock) EnableReplayMode(startTS, endTS *uint64) {
 	c.replayMode = true
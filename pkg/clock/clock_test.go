@@ -214,3 +214,22 @@ func TestBeaconClockTimeToSlot(t *testing.T) {
 		t.Errorf("Expected slot 0 before genesis, got %d", slot)
 	}
 }
+
+func TestBeaconClockSlotLag(t *testing.T) {
+	genesis := &models.Genesis{GenesisTime: 1606824023}
+	spec := &models.Spec{SecondsPerSlot: 12, SlotsPerEpoch: 32}
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	clock := NewBeaconClock(genesis, spec, logger)
+
+	// Slot 10 ends at the start of slot 11, plus the lag
+	if got, want := clock.SlotEndTime(10), clock.SlotStartTime(11).Add(DefaultSlotLagSeconds*time.Second); !got.Equal(want) {
+		t.Errorf("Expected slot 10 to end at %v with the default lag, got %v", want, got)
+	}
+
+	clock.SetSlotLag(0)
+	if got, want := clock.SlotEndTime(10), clock.SlotStartTime(11); !got.Equal(want) {
+		t.Errorf("Expected slot 10 to end at %v without lag, got %v", want, got)
+	}
+}
@@ -1,11 +1,35 @@
 package config
 
 import (
+	"encoding/hex"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/alert"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/beacon"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/clock"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/cron"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/events"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/explorer"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/export"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/federation"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/grafana"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/health"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/heatmap"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/httpserver"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/interchange"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/logging"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/metrics"
 	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/onchain"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/proposer"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/ratings"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/relay"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/rules"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/tracing"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/validator"
 	"gopkg.in/yaml.v3"
 )
 
@@ -17,6 +41,56 @@ func DefaultConfig() *models.Config {
 		BeaconTimeout: models.Duration(90 * time.Second),
 		MetricsPort:   8000,
 		WatchedKeys:   []models.WatchedKey{},
+		Log: models.Logging{
+			MaxSizeMB:  100,
+			MaxBackups: 5,
+		},
+		LogSampling: models.LogSampling{
+			MaxExamples: 5,
+		},
+		ProposalLookahead: models.ProposalLookahead{
+			MinConsecutiveMissed: 1,
+		},
+		Tracing: models.Tracing{
+			SampleRatio: 1,
+		},
+		SlotLagSeconds:       clock.DefaultSlotLagSeconds,
+		LivenessSlot:         16,
+		RewardsSlot:          17,
+		ConfigReloadSlot:     15,
+		StaleDataAfter:       models.Duration(20 * time.Minute),
+		PriceRefresh:         models.Duration(10 * time.Minute),
+		HeatmapEpochs:        heatmap.DefaultEpochs,
+		SigningHistoryEpochs: interchange.DefaultRetentionEpochs,
+		Startup: models.Startup{
+			BatchSize:            100,
+			MaxConcurrentBatches: 1,
+		},
+		StateTrends: models.StateTrends{
+			CompactAfterDays: 7,
+			RetentionDays:    365,
+		},
+		OnchainRegistry: models.OnchainRegistry{
+			Refresh: models.Duration(time.Hour),
+		},
+		ExternalRatings: models.ExternalRatings{
+			Refresh: models.Duration(24 * time.Hour),
+		},
+		WatchedKeysRefreshEpochs: 10,
+		Web3Signer: models.Web3Signer{
+			RefreshEpochs: 10,
+		},
+		SharedCache: models.SharedCache{
+			MaxFullSetAgeEpochs: 10,
+		},
+		Federation: models.Federation{
+			Refresh: models.Duration(30 * time.Second),
+		},
+		Degradation: models.Degradation{
+			MaxLatencyMs:  3000,
+			MaxErrorRate:  0.2,
+			RecoverEpochs: 3,
+		},
 	}
 }
 
@@ -43,42 +117,565 @@ func LoadConfig(path string) (*models.Config, error) {
 
 	// Apply environment variable overrides
 	applyEnvOverrides(cfg)
+	cfg.Path = path
 
 	return cfg, nil
 }
 
+// LoadHTTPServer loads only the http_server settings of a config file, with their environment
+// overrides, for the read-only snapshot server that needs no other setting; without the file only
+// the environment applies
+func LoadHTTPServer(path string) (models.HTTPServer, error) {
+	var file struct {
+		HTTPServer models.HTTPServer `yaml:"http_server"`
+	}
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return models.HTTPServer{}, fmt.Errorf("failed to read config file: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return models.HTTPServer{}, fmt.Errorf("failed to parse config: %w", err)
+	}
+	if err := httpserver.Validate(file.HTTPServer); err != nil {
+		return models.HTTPServer{}, fmt.Errorf("invalid config: http_server: %w", err)
+	}
+
+	cfg := &models.Config{HTTPServer: file.HTTPServer}
+	applyEnvOverrides(cfg)
+	return cfg.HTTPServer, nil
+}
+
 // ValidateConfig validates the configuration
 func ValidateConfig(cfg *models.Config) error {
 	if cfg.Network == "" {
 		return fmt.Errorf("network is required")
 	}
-	if cfg.BeaconURL == "" {
+	if cfg.BeaconURL == "" && len(cfg.BeaconURLs) == 0 {
 		return fmt.Errorf("beacon_url is required")
 	}
+	for i, url := range cfg.BeaconURLs {
+		if url == "" {
+			return fmt.Errorf("beacon_urls[%d] must not be empty", i)
+		}
+	}
+	if err := validateBeaconNodes(cfg); err != nil {
+		return err
+	}
 	if cfg.MetricsPort <= 0 || cfg.MetricsPort > 65535 {
 		return fmt.Errorf("metrics_port must be between 1 and 65535")
 	}
+	if cfg.GRPCHealthPort < 0 || cfg.GRPCHealthPort > 65535 {
+		return fmt.Errorf("grpc_health_port must be between 0 and 65535")
+	}
+	if failOn := cfg.Health.FailOn; failOn != "" && failOn != string(health.StatusWarn) && failOn != string(health.StatusFail) {
+		return fmt.Errorf("health.fail_on must be warn or fail")
+	}
+	if _, err := logging.NewFormatter(cfg.Log.Format); err != nil {
+		return fmt.Errorf("log.format: %w", err)
+	}
+	if cfg.Log.MaxSizeMB < 0 || cfg.Log.MaxBackups < 0 {
+		return fmt.Errorf("log.max_size_mb and log.max_backups must not be negative")
+	}
+	if err := httpserver.Validate(cfg.HTTPServer); err != nil {
+		return fmt.Errorf("http_server: %w", err)
+	}
+	if err := tracing.Validate(cfg.Tracing); err != nil {
+		return fmt.Errorf("tracing: %w", err)
+	}
+	if cfg.ProposalLookahead.Slots < 0 {
+		return fmt.Errorf("proposal_lookahead.slots must not be negative")
+	}
+	if cfg.ProposalLookahead.Slots > 0 && cfg.ProposalLookahead.MinConsecutiveMissed == 0 {
+		return fmt.Errorf("proposal_lookahead.min_consecutive_missed must be at least 1")
+	}
+	if cfg.LogSampling.MaxExamples < 0 {
+		return fmt.Errorf("log_sampling.max_examples must not be negative")
+	}
+	if cfg.SlotLagSeconds < 0 {
+		return fmt.Errorf("slot_lag_seconds must not be negative")
+	}
+	if cfg.LivenessSlot < 0 || cfg.RewardsSlot < 0 || cfg.ConfigReloadSlot < 0 {
+		return fmt.Errorf("liveness_slot, rewards_slot and config_reload_slot must not be negative")
+	}
+	if err := beacon.ValidateRetry(cfg.BeaconRetry); err != nil {
+		return fmt.Errorf("beacon_retry: %w", err)
+	}
+	if err := beacon.ValidateAuth(cfg.BeaconAuth); err != nil {
+		return fmt.Errorf("beacon_auth: %w", err)
+	}
+	if cfg.BeaconRateLimit.RequestsPerSec < 0 || cfg.BeaconRateLimit.Burst < 0 || cfg.BeaconRateLimit.MaxConcurrent < 0 {
+		return fmt.Errorf("beacon_rate_limit values must not be negative")
+	}
+	if cfg.Startup.BatchSize <= 0 {
+		return fmt.Errorf("startup.batch_size must be positive")
+	}
+	if cfg.Startup.MaxConcurrentBatches <= 0 {
+		return fmt.Errorf("startup.max_concurrent_batches must be positive")
+	}
+	if cfg.Startup.BatchDelayMs < 0 {
+		return fmt.Errorf("startup.batch_delay_ms must not be negative")
+	}
+	if err := validateOnchainRegistry(cfg.OnchainRegistry); err != nil {
+		return fmt.Errorf("onchain_registry: %w", err)
+	}
+	if err := validateDepositTracking(cfg.DepositTracking); err != nil {
+		return fmt.Errorf("deposit_tracking: %w", err)
+	}
+	if cfg.Privacy.AnonymizePubkeys && cfg.Privacy.Salt == "" {
+		return fmt.Errorf("privacy.salt is required when privacy.anonymize_pubkeys is enabled")
+	}
+	if cfg.HeatmapEpochs <= 0 {
+		return fmt.Errorf("heatmap_epochs must be positive")
+	}
+	if cfg.SigningHistoryEpochs <= 0 {
+		return fmt.Errorf("signing_history_epochs must be positive")
+	}
+	if cfg.PriceRefresh <= 0 {
+		return fmt.Errorf("price_refresh_interval_sec must be positive")
+	}
+	if cfg.EpochStageTimeout < 0 {
+		return fmt.Errorf("epoch_stage_timeout_sec must not be negative")
+	}
+	if cfg.NetworkSample < 0 {
+		return fmt.Errorf("network_sample must not be negative")
+	}
+	if cfg.NetworkSample > 0 && !cfg.ShouldLoadAllValidators() {
+		return fmt.Errorf("network_sample needs load_all_validators")
+	}
+	if _, err := metrics.ResolveScorecardWeights(cfg.Scorecard.Weights); err != nil {
+		return fmt.Errorf("scorecard: %w", err)
+	}
+	if cfg.Report.Schedule != "" {
+		if _, err := cron.Parse(cfg.Report.Schedule, cfg.Report.TimeZone); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+	}
+	for i, silence := range cfg.Silences {
+		if silence.Name == "" {
+			return fmt.Errorf("silences[%d]: name is required", i)
+		}
+		if _, err := cron.Parse(silence.Schedule, silence.TimeZone); err != nil {
+			return fmt.Errorf("silences[%d]: %w", i, err)
+		}
+		if silence.Duration <= 0 {
+			return fmt.Errorf("silences[%d]: duration_sec must be positive", i)
+		}
+	}
+
+	if err := validateMEVRelays(cfg.MEVRelays); err != nil {
+		return fmt.Errorf("mev_relays: %w", err)
+	}
+	if cfg.SharedCache.RedisURL != "" {
+		if !strings.HasPrefix(cfg.SharedCache.RedisURL, "redis://") && !strings.HasPrefix(cfg.SharedCache.RedisURL, "rediss://") {
+			return fmt.Errorf("shared_cache.redis_url must be a redis:// or rediss:// URL")
+		}
+		if cfg.SharedCache.MaxFullSetAgeEpochs <= 0 {
+			return fmt.Errorf("shared_cache.max_full_set_age_epochs must be positive")
+		}
+	}
+	if err := validateFeeRecipients(cfg.FeeRecipients); err != nil {
+		return fmt.Errorf("fee_recipients: %w", err)
+	}
+	if err := proposer.ValidateGraffitiClients(cfg.GraffitiClients); err != nil {
+		return fmt.Errorf("graffiti_clients%w", err)
+	}
+	if cfg.PagerDuty.MinSeverity != "" {
+		if _, err := alert.ParseSeverity(cfg.PagerDuty.MinSeverity); err != nil {
+			return fmt.Errorf("pagerduty.min_severity: %w", err)
+		}
+	}
+	if cfg.StateTrends.CompactAfterDays < 1 {
+		return fmt.Errorf("state_trends.compact_after_days must be at least 1")
+	}
+	if r := cfg.StateTrends.RetentionDays; r < 0 || (r > 0 && r < cfg.StateTrends.CompactAfterDays) {
+		return fmt.Errorf("state_trends.retention_days must be 0 or at least compact_after_days")
+	}
+	if p := cfg.CriticalAlerts.LabelOfflinePercent; p < 0 || p >= 100 {
+		return fmt.Errorf("critical_alerts.label_offline_percent must be in [0, 100)")
+	}
+	ruleNames := make(map[string]bool, len(cfg.Rules))
+	for _, rule := range cfg.Rules {
+		if _, err := rules.FromConfig(rule); err != nil {
+			return fmt.Errorf("rules: %w", err)
+		}
+		if ruleNames[rule.Name] {
+			return fmt.Errorf("rules: duplicate rule name %s", rule.Name)
+		}
+		ruleNames[rule.Name] = true
+	}
+	if cfg.Telegram.BotToken != "" && cfg.Telegram.ChatID == "" {
+		return fmt.Errorf("telegram.chat_id is required with a bot_token")
+	}
+	if cfg.Telegram.MinSeverity != "" {
+		if _, err := alert.ParseSeverity(cfg.Telegram.MinSeverity); err != nil {
+			return fmt.Errorf("telegram.min_severity: %w", err)
+		}
+	}
+	if _, err := events.NewEncoder(cfg.EventsFormat, ""); err != nil {
+		return fmt.Errorf("events_format: %w", err)
+	}
+	if err := validateExplorer(cfg.Explorer); err != nil {
+		return fmt.Errorf("explorer: %w", err)
+	}
+	if err := validateDiscord(cfg.Discord); err != nil {
+		return fmt.Errorf("discord: %w", err)
+	}
+	if d := cfg.Degradation; d.IsEnabled() {
+		if d.MaxLatencyMs <= 0 || d.RecoverEpochs <= 0 {
+			return fmt.Errorf("degradation.max_latency_ms and degradation.recover_epochs must be positive")
+		}
+		if d.MaxErrorRate <= 0 || d.MaxErrorRate > 1 {
+			return fmt.Errorf("degradation.max_error_rate must be in (0, 1]")
+		}
+	}
+	if g := cfg.GrafanaAnnotations; g.URL != "" {
+		if !strings.HasPrefix(g.URL, "http://") && !strings.HasPrefix(g.URL, "https://") {
+			return fmt.Errorf("grafana_annotations.url must be an http(s) URL")
+		}
+		if g.APIKey == "" {
+			return fmt.Errorf("grafana_annotations.api_key is required with a url")
+		}
+		if err := grafana.ValidateEvents(g.Events); err != nil {
+			return fmt.Errorf("grafana_annotations.events: %w", err)
+		}
+	}
+	if i := cfg.Influx; i.URL != "" && !strings.HasPrefix(i.URL, "http://") && !strings.HasPrefix(i.URL, "https://") {
+		return fmt.Errorf("influx.url must be an http(s) URL")
+	}
+	if e := cfg.Export; e.Dir != "" {
+		switch e.Format {
+		case "", export.FormatCSV:
+		case "parquet":
+			return fmt.Errorf("export.format: parquet is not supported, export csv and convert the files")
+		default:
+			return fmt.Errorf("export.format must be csv")
+		}
+		if e.RetentionDays < 0 {
+			return fmt.Errorf("export.retention_days must not be negative")
+		}
+	}
+	if err := validateExternalRatings(cfg.ExternalRatings); err != nil {
+		return fmt.Errorf("external_ratings: %w", err)
+	}
+	if err := validateFederation(cfg.Federation); err != nil {
+		return fmt.Errorf("federation: %w", err)
+	}
+	for i, class := range cfg.AggregateLabelClasses {
+		if class == "" || strings.Contains(class, ":") {
+			return fmt.Errorf("aggregate_label_classes[%d]: must be a label prefix without ':' (e.g. operator)", i)
+		}
+	}
 
-	// Validate watched keys
-	for i, key := range cfg.WatchedKeys {
+	if cfg.WatchedKeysURL != "" {
+		if !strings.HasPrefix(cfg.WatchedKeysURL, "http://") && !strings.HasPrefix(cfg.WatchedKeysURL, "https://") {
+			return fmt.Errorf("watched_keys_url must be an http(s) URL")
+		}
+		if cfg.WatchedKeysRefreshEpochs <= 0 {
+			return fmt.Errorf("watched_keys_refresh_epochs must be positive")
+		}
+	}
+
+	if s := cfg.Web3Signer; s.URL != "" {
+		if !strings.HasPrefix(s.URL, "http://") && !strings.HasPrefix(s.URL, "https://") {
+			return fmt.Errorf("web3signer.url must be an http(s) URL")
+		}
+		if s.RefreshEpochs <= 0 {
+			return fmt.Errorf("web3signer.refresh_epochs must be positive")
+		}
+		for _, label := range s.Labels {
+			if validator.IsCohortLabel(label) {
+				return fmt.Errorf("web3signer.labels: %s labels are reserved for cohorts", validator.CohortPrefix)
+			}
+		}
+	}
+	if err := validateCohorts(cfg.Cohorts); err != nil {
+		return fmt.Errorf("cohorts: %w", err)
+	}
+
+	if _, err := validator.ParseResetPolicy(cfg.Counters.Reset); err != nil {
+		return fmt.Errorf("counters.reset: %w", err)
+	}
+	for _, n := range cfg.Counters.WindowEpochs {
+		if n <= 0 || n > metrics.MaxWindowEpochs {
+			return fmt.Errorf("counters.window_epochs must be between 1 and %d", metrics.MaxWindowEpochs)
+		}
+	}
+
+	return validateWatchedKeys(cfg.WatchedKeys, "watched_keys")
+}
+
+// validateCohorts checks that cohorts have unique names and valid members
+func validateCohorts(cohorts []models.Cohort) error {
+	names := make(map[string]bool, len(cohorts))
+	for i, cohort := range cohorts {
+		if cohort.Name == "" || strings.Contains(cohort.Name, ":") {
+			return fmt.Errorf("[%d]: name is required and can't contain ':'", i)
+		}
+		if names[cohort.Name] {
+			return fmt.Errorf("duplicate cohort %s", cohort.Name)
+		}
+		names[cohort.Name] = true
+		if len(cohort.PublicKeys) == 0 && len(cohort.Indices) == 0 {
+			return fmt.Errorf("%s: public_keys or indices are required", cohort.Name)
+		}
+		for j, pubkey := range cohort.PublicKeys {
+			if len(pubkey) != 98 || pubkey[:2] != "0x" {
+				return fmt.Errorf("%s: public_keys[%d] must be a valid BLS public key (0x...)", cohort.Name, j)
+			}
+		}
+	}
+	return nil
+}
+
+// validateWatchedKeys checks that every key is a BLS public key
+func validateWatchedKeys(keys []models.WatchedKey, source string) error {
+	for i, key := range keys {
 		if key.PublicKey == "" {
-			return fmt.Errorf("watched_keys[%d]: public_key is required", i)
+			return fmt.Errorf("%s[%d]: public_key is required", source, i)
 		}
 		if len(key.PublicKey) != 98 || key.PublicKey[:2] != "0x" {
-			return fmt.Errorf("watched_keys[%d]: public_key must be a valid BLS public key (0x...)", i)
+			return fmt.Errorf("%s[%d]: public_key must be a valid BLS public key (0x...)", source, i)
+		}
+		for _, label := range key.Labels {
+			if validator.IsCohortLabel(label) {
+				return fmt.Errorf("%s[%d]: %s labels are reserved for cohorts", source, i, validator.CohortPrefix)
+			}
+			if err := validator.ValidateLabel(label); err != nil {
+				return fmt.Errorf("%s[%d]: %w", source, i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateBeaconNodes checks the per-node endpoints, which replace beacon_urls and beacon_auth
+func validateBeaconNodes(cfg *models.Config) error {
+	if len(cfg.BeaconNodes) == 0 {
+		return nil
+	}
+	if len(cfg.BeaconURLs) > 0 {
+		return fmt.Errorf("beacon_nodes and beacon_urls can't both be set")
+	}
+	auth := cfg.BeaconAuth
+	if len(auth.Headers) > 0 || auth.BearerToken != "" || auth.BasicAuth.Username != "" || auth.JWTSecretFile != "" {
+		return fmt.Errorf("beacon_auth can't be set with beacon_nodes - set each node's auth instead")
+	}
+	for i, node := range cfg.BeaconNodes {
+		if node.URL == "" {
+			return fmt.Errorf("beacon_nodes[%d].url must not be empty", i)
+		}
+		if err := beacon.ValidateAuth(node.Auth); err != nil {
+			return fmt.Errorf("beacon_nodes[%d].auth: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// validateOnchainRegistry checks registry contracts when an execution endpoint is configured
+func validateOnchainRegistry(registry models.OnchainRegistry) error {
+	if registry.ExecutionURL == "" {
+		return nil
+	}
+	if len(registry.Contracts) == 0 {
+		return fmt.Errorf("at least one contract is required")
+	}
+	if registry.Refresh <= 0 {
+		return fmt.Errorf("refresh_interval_sec must be positive")
+	}
+
+	for i, contract := range registry.Contracts {
+		if !isHex(contract.Address, 20) {
+			return fmt.Errorf("contracts[%d]: address must be a 20-byte hex address", i)
+		}
+		if !isHex(contract.Selector, 4) {
+			return fmt.Errorf("contracts[%d]: selector must be a 4-byte hex selector", i)
+		}
+		switch contract.Returns {
+		case onchain.ReturnString, onchain.ReturnUint256, onchain.ReturnAddress, onchain.ReturnBytes32:
+		default:
+			return fmt.Errorf("contracts[%d]: returns must be string, uint256, address or bytes32", i)
+		}
+		if contract.Label == "" {
+			return fmt.Errorf("contracts[%d]: label is required", i)
 		}
 	}
+	return nil
+}
 
+// validateDepositTracking checks the execution endpoint and the deposit contract override
+func validateDepositTracking(tracking models.DepositTracking) error {
+	if tracking.ExecutionURL != "" && !strings.HasPrefix(tracking.ExecutionURL, "http://") && !strings.HasPrefix(tracking.ExecutionURL, "https://") {
+		return fmt.Errorf("execution_url must be an http(s) URL")
+	}
+	if tracking.ContractAddress != "" && !isHex(tracking.ContractAddress, 20) {
+		return fmt.Errorf("contract_address must be a 20-byte hex address")
+	}
 	return nil
 }
 
+// validateMEVRelays checks that every relay has an http(s) URL and a unique name
+func validateMEVRelays(relays []models.MEVRelay) error {
+	names := make(map[string]bool, len(relays))
+	for i, r := range relays {
+		if !strings.HasPrefix(r.URL, "http://") && !strings.HasPrefix(r.URL, "https://") {
+			return fmt.Errorf("[%d]: url must be an http(s) URL", i)
+		}
+		name := relay.Name(r)
+		if names[name] {
+			return fmt.Errorf("[%d]: duplicate relay name %q", i, name)
+		}
+		names[name] = true
+	}
+	return nil
+}
+
+// validateExternalRatings checks the ratings provider when one is configured
+func validateExternalRatings(cfg models.ExternalRatings) error {
+	if cfg.Provider == "" {
+		return nil
+	}
+	if !ratings.IsProvider(cfg.Provider) {
+		return fmt.Errorf("provider must be %s or %s", ratings.ProviderRated, ratings.ProviderBeaconchain)
+	}
+	if cfg.URL != "" && !strings.HasPrefix(cfg.URL, "http://") && !strings.HasPrefix(cfg.URL, "https://") {
+		return fmt.Errorf("url must be an http(s) URL")
+	}
+	if cfg.Provider == ratings.ProviderRated && cfg.APIKey == "" {
+		return fmt.Errorf("api_key is required by %s", ratings.ProviderRated)
+	}
+	if cfg.Refresh <= 0 {
+		return fmt.Errorf("refresh_interval_sec must be positive")
+	}
+	return nil
+}
+
+// validateExplorer checks that explorers are http(s) URLs and every page path has the {id} placeholder
+func validateExplorer(cfg models.Explorer) error {
+	urls := map[string]string{"url": cfg.URL}
+	for network, url := range cfg.Networks {
+		if url == "" {
+			return fmt.Errorf("networks.%s: url is required", network)
+		}
+		urls["networks."+network] = url
+	}
+	for key, url := range urls {
+		if url != "" && !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+			return fmt.Errorf("%s must be an http(s) URL", key)
+		}
+	}
+
+	paths := map[string]string{
+		"validator_path": cfg.ValidatorPath,
+		"slot_path":      cfg.SlotPath,
+		"epoch_path":     cfg.EpochPath,
+		"block_path":     cfg.BlockPath,
+	}
+	for key, path := range paths {
+		if path == "" {
+			continue
+		}
+		if err := explorer.ValidatePath(path); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// validateDiscord checks the severities and that every webhook is an http(s) URL
+func validateDiscord(discord models.Discord) error {
+	if discord.MinSeverity != "" {
+		if _, err := alert.ParseSeverity(discord.MinSeverity); err != nil {
+			return fmt.Errorf("min_severity: %w", err)
+		}
+	}
+	urls := map[string]string{"webhook_url": discord.WebhookURL}
+	for severity, url := range discord.SeverityWebhooks {
+		if _, err := alert.ParseSeverity(severity); err != nil {
+			return fmt.Errorf("severity_webhook_urls: %w", err)
+		}
+		urls["severity_webhook_urls."+severity] = url
+	}
+	if discord.ExplorerURL != "" {
+		urls["explorer_url"] = discord.ExplorerURL
+	}
+	for key, url := range urls {
+		if url != "" && !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+			return fmt.Errorf("%s must be an http(s) URL", key)
+		}
+	}
+	return nil
+}
+
+// validateFederation checks that every peer has an http(s) URL and a unique name, distinct from this instance's
+func validateFederation(fed models.Federation) error {
+	if len(fed.Peers) == 0 {
+		return nil
+	}
+	if fed.Refresh <= 0 {
+		return fmt.Errorf("refresh_interval_sec must be positive")
+	}
+
+	local := fed.Name
+	if local == "" {
+		local = federation.DefaultName
+	}
+	names := map[string]bool{local: true}
+	for i, peer := range fed.Peers {
+		if !strings.HasPrefix(peer.URL, "http://") && !strings.HasPrefix(peer.URL, "https://") {
+			return fmt.Errorf("peers[%d]: url must be an http(s) URL", i)
+		}
+		name := federation.Name(peer)
+		if names[name] {
+			return fmt.Errorf("peers[%d]: duplicate shard name %q", i, name)
+		}
+		names[name] = true
+	}
+	return nil
+}
+
+// validateFeeRecipients checks that every expected fee recipient is an execution address
+func validateFeeRecipients(recipients models.FeeRecipients) error {
+	for i, address := range recipients.Default {
+		if !isHex(address, 20) {
+			return fmt.Errorf("default[%d]: %q is not a 0x-prefixed 20-byte address", i, address)
+		}
+	}
+	for label, addresses := range recipients.ByLabel {
+		if len(addresses) == 0 {
+			return fmt.Errorf("by_label[%s]: at least one address is required", label)
+		}
+		for i, address := range addresses {
+			if !isHex(address, 20) {
+				return fmt.Errorf("by_label[%s][%d]: %q is not a 0x-prefixed 20-byte address", label, i, address)
+			}
+		}
+	}
+	return nil
+}
+
+// isHex returns true for a 0x-prefixed hex string of exactly size bytes
+func isHex(value string, size int) bool {
+	if !strings.HasPrefix(value, "0x") || len(value) != 2+2*size {
+		return false
+	}
+	_, err := hex.DecodeString(value[2:])
+	return err == nil
+}
+
 // applyEnvOverrides applies environment variable overrides
 func applyEnvOverrides(cfg *models.Config) {
 	if network := os.Getenv("ETH_WATCHER_NETWORK"); network != "" {
 		cfg.Network = network
 	}
+	// A comma-separated list replaces the failover endpoints too, beacon_nodes included
 	if beaconURL := os.Getenv("ETH_WATCHER_BEACON_URL"); beaconURL != "" {
-		cfg.BeaconURL = beaconURL
+		urls := strings.Split(beaconURL, ",")
+		cfg.BeaconURL = urls[0]
+		cfg.BeaconURLs = nil
+		cfg.BeaconNodes = nil
+		if len(urls) > 1 {
+			cfg.BeaconURLs = urls
+		}
 	}
 	if slackToken := os.Getenv("ETH_WATCHER_SLACK_TOKEN"); slackToken != "" {
 		cfg.SlackToken = slackToken
@@ -86,6 +683,36 @@ func applyEnvOverrides(cfg *models.Config) {
 	if slackChannel := os.Getenv("ETH_WATCHER_SLACK_CHANNEL"); slackChannel != "" {
 		cfg.SlackChannel = slackChannel
 	}
+	if routingKey := os.Getenv("ETH_WATCHER_PAGERDUTY_ROUTING_KEY"); routingKey != "" {
+		cfg.PagerDuty.RoutingKey = routingKey
+	}
+	if botToken := os.Getenv("ETH_WATCHER_TELEGRAM_BOT_TOKEN"); botToken != "" {
+		cfg.Telegram.BotToken = botToken
+	}
+	if webhook := os.Getenv("ETH_WATCHER_DISCORD_WEBHOOK_URL"); webhook != "" {
+		cfg.Discord.WebhookURL = webhook
+	}
+	if salt := os.Getenv("ETH_WATCHER_PRIVACY_SALT"); salt != "" {
+		cfg.Privacy.Salt = salt
+	}
+	if password := os.Getenv("ETH_WATCHER_HTTP_PASSWORD"); password != "" {
+		cfg.HTTPServer.BasicAuth.Password = password
+	}
+	if token := os.Getenv("ETH_WATCHER_HTTP_BEARER_TOKEN"); token != "" {
+		cfg.HTTPServer.BearerToken = token
+	}
+	if redisURL := os.Getenv("ETH_WATCHER_REDIS_URL"); redisURL != "" {
+		cfg.SharedCache.RedisURL = redisURL
+	}
+	if apiKey := os.Getenv("ETH_WATCHER_GRAFANA_API_KEY"); apiKey != "" {
+		cfg.GrafanaAnnotations.APIKey = apiKey
+	}
+	if token := os.Getenv("ETH_WATCHER_INFLUX_TOKEN"); token != "" {
+		cfg.Influx.Token = token
+	}
+	if apiKey := os.Getenv("ETH_WATCHER_RATINGS_API_KEY"); apiKey != "" {
+		cfg.ExternalRatings.APIKey = apiKey
+	}
 }
 
 // SaveConfig saves configuration to a YAML file
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"sort"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// LabelChange describes a watched key whose labels changed
+type LabelChange struct {
+	PublicKey string   `json:"public_key"`
+	OldLabels []string `json:"old_labels"`
+	NewLabels []string `json:"new_labels"`
+}
+
+// WatchlistDiff is the structured difference between two watched key lists
+type WatchlistDiff struct {
+	Added     []models.WatchedKey `json:"added,omitempty"`
+	Removed   []models.WatchedKey `json:"removed,omitempty"`
+	Relabeled []LabelChange       `json:"relabeled,omitempty"`
+}
+
+// IsEmpty returns true if the watched key lists are equivalent
+func (d *WatchlistDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Relabeled) == 0
+}
+
+// MapPubkeys returns a copy of the diff with every public key passed through fn
+func (d *WatchlistDiff) MapPubkeys(fn func(string) string) *WatchlistDiff {
+	mapKeys := func(keys []models.WatchedKey) []models.WatchedKey {
+		mapped := make([]models.WatchedKey, len(keys))
+		for i, wk := range keys {
+			mapped[i] = models.WatchedKey{PublicKey: fn(wk.PublicKey), Labels: wk.Labels}
+		}
+		return mapped
+	}
+
+	mapped := &WatchlistDiff{
+		Added:     mapKeys(d.Added),
+		Removed:   mapKeys(d.Removed),
+		Relabeled: make([]LabelChange, len(d.Relabeled)),
+	}
+	for i, change := range d.Relabeled {
+		change.PublicKey = fn(change.PublicKey)
+		mapped.Relabeled[i] = change
+	}
+	return mapped
+}
+
+// DiffWatchedKeys computes which keys were added, removed, or relabeled between two lists
+// Results are sorted by public key so diffs are stable across runs
+func DiffWatchedKeys(oldKeys, newKeys []models.WatchedKey) *WatchlistDiff {
+	oldMap := make(map[string]models.WatchedKey, len(oldKeys))
+	for _, wk := range oldKeys {
+		oldMap[wk.PublicKey] = wk
+	}
+	newMap := make(map[string]models.WatchedKey, len(newKeys))
+	for _, wk := range newKeys {
+		newMap[wk.PublicKey] = wk
+	}
+
+	diff := &WatchlistDiff{}
+
+	for pubkey, newKey := range newMap {
+		oldKey, ok := oldMap[pubkey]
+		if !ok {
+			diff.Added = append(diff.Added, newKey)
+			continue
+		}
+		if !sameLabels(oldKey.Labels, newKey.Labels) {
+			diff.Relabeled = append(diff.Relabeled, LabelChange{
+				PublicKey: pubkey,
+				OldLabels: oldKey.Labels,
+				NewLabels: newKey.Labels,
+			})
+		}
+	}
+
+	for pubkey, oldKey := range oldMap {
+		if _, ok := newMap[pubkey]; !ok {
+			diff.Removed = append(diff.Removed, oldKey)
+		}
+	}
+
+	sort.Slice(diff.Added, func(i, j int) bool { return diff.Added[i].PublicKey < diff.Added[j].PublicKey })
+	sort.Slice(diff.Removed, func(i, j int) bool { return diff.Removed[i].PublicKey < diff.Removed[j].PublicKey })
+	sort.Slice(diff.Relabeled, func(i, j int) bool { return diff.Relabeled[i].PublicKey < diff.Relabeled[j].PublicKey })
+
+	return diff
+}
+
+// sameLabels compares label sets ignoring order
+func sameLabels(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	counts := make(map[string]int, len(a))
+	for _, label := range a {
+		counts[label]++
+	}
+	for _, label := range b {
+		counts[label]--
+		if counts[label] < 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+func TestDiffWatchedKeys(t *testing.T) {
+	oldKeys := []models.WatchedKey{
+		{PublicKey: "0xaaa", Labels: []string{"operator:a"}},
+		{PublicKey: "0xbbb", Labels: []string{"operator:a", "region:us"}},
+		{PublicKey: "0xccc", Labels: []string{"operator:b"}},
+	}
+	newKeys := []models.WatchedKey{
+		{PublicKey: "0xbbb", Labels: []string{"region:us", "operator:a"}}, // Reordered only
+		{PublicKey: "0xccc", Labels: []string{"operator:c"}},
+		{PublicKey: "0xddd", Labels: []string{"operator:a"}},
+	}
+
+	diff := DiffWatchedKeys(oldKeys, newKeys)
+
+	if len(diff.Added) != 1 || diff.Added[0].PublicKey != "0xddd" {
+		t.Errorf("Expected 0xddd added, got %v", diff.Added)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0].PublicKey != "0xaaa" {
+		t.Errorf("Expected 0xaaa removed, got %v", diff.Removed)
+	}
+	if len(diff.Relabeled) != 1 || diff.Relabeled[0].PublicKey != "0xccc" {
+		t.Fatalf("Expected 0xccc relabeled, got %v", diff.Relabeled)
+	}
+	if diff.Relabeled[0].NewLabels[0] != "operator:c" {
+		t.Errorf("Expected new label operator:c, got %v", diff.Relabeled[0].NewLabels)
+	}
+	if diff.IsEmpty() {
+		t.Error("Expected diff not to be empty")
+	}
+}
+
+func TestDiffWatchedKeysUnchanged(t *testing.T) {
+	keys := []models.WatchedKey{
+		{PublicKey: "0xaaa", Labels: []string{"operator:a"}},
+	}
+
+	diff := DiffWatchedKeys(keys, keys)
+	if !diff.IsEmpty() {
+		t.Errorf("Expected empty diff, got %+v", diff)
+	}
+}
+
+func TestWatchlistDiffMapPubkeys(t *testing.T) {
+	diff := DiffWatchedKeys(
+		[]models.WatchedKey{{PublicKey: "0xaaa"}, {PublicKey: "0xbbb", Labels: []string{"operator:a"}}},
+		[]models.WatchedKey{{PublicKey: "0xbbb", Labels: []string{"operator:b"}}, {PublicKey: "0xccc"}},
+	)
+
+	mapped := diff.MapPubkeys(func(pubkey string) string { return "anon-" + pubkey[2:] })
+	if mapped.Added[0].PublicKey != "anon-ccc" || mapped.Removed[0].PublicKey != "anon-aaa" || mapped.Relabeled[0].PublicKey != "anon-bbb" {
+		t.Errorf("Expected mapped public keys, got %+v", mapped)
+	}
+	if diff.Added[0].PublicKey != "0xccc" {
+		t.Error("Expected the original diff to be unchanged")
+	}
+}
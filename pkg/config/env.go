@@ -0,0 +1,67 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// Zero-config mode environment variables
+const (
+	EnvBeaconURL      = "ETH_WATCHER_BEACON_URL"
+	EnvWatchedPubkeys = "WATCHED_PUBKEYS"
+	EnvWatchedLabels  = "WATCHED_LABELS"
+	EnvMetricsPort    = "ETH_WATCHER_METRICS_PORT"
+)
+
+// zeroConfigMetricsPort matches the port the container image exposes
+const zeroConfigMetricsPort = 8080
+
+// ZeroConfigAvailable reports whether the environment has enough to run without a config file
+func ZeroConfigAvailable() bool {
+	return os.Getenv(EnvBeaconURL) != "" && os.Getenv(EnvWatchedPubkeys) != ""
+}
+
+// FromEnv builds the configuration from environment variables alone (zero-config mode)
+// WATCHED_PUBKEYS is a comma or whitespace separated list of keys, WATCHED_LABELS optional labels
+// given to all of them; everything else takes the defaults and the usual ETH_WATCHER_* overrides
+func FromEnv() (*models.Config, error) {
+	cfg := DefaultConfig()
+	cfg.MetricsPort = zeroConfigMetricsPort
+	applyEnvOverrides(cfg)
+
+	if port := os.Getenv(EnvMetricsPort); port != "" {
+		p, err := strconv.Atoi(port)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %q is not a port number", EnvMetricsPort, port)
+		}
+		cfg.MetricsPort = p
+	}
+
+	labels := splitList(os.Getenv(EnvWatchedLabels))
+	for _, pubkey := range splitList(os.Getenv(EnvWatchedPubkeys)) {
+		pubkey = strings.ToLower(pubkey)
+		if !strings.HasPrefix(pubkey, "0x") {
+			pubkey = "0x" + pubkey
+		}
+		cfg.WatchedKeys = append(cfg.WatchedKeys, models.WatchedKey{PublicKey: pubkey, Labels: labels})
+	}
+	if err := validateWatchedKeys(cfg.WatchedKeys, EnvWatchedPubkeys); err != nil {
+		return nil, err
+	}
+
+	if err := ValidateConfig(cfg); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	return cfg, nil
+}
+
+// splitList splits a comma or whitespace separated list, dropping empty items
+func splitList(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n' || r == '\r'
+	})
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFromEnv(t *testing.T) {
+	key1 := "0x" + strings.Repeat("a", 96)
+	key2 := strings.Repeat("B", 96)
+	t.Setenv(EnvBeaconURL, "http://beacon:5052")
+	t.Setenv(EnvWatchedPubkeys, key1+", "+key2+"\n")
+	t.Setenv(EnvWatchedLabels, "operator:acme")
+	t.Setenv("ETH_WATCHER_NETWORK", "holesky")
+
+	if !ZeroConfigAvailable() {
+		t.Fatal("ZeroConfigAvailable() = false")
+	}
+	cfg, err := FromEnv()
+	if err != nil {
+		t.Fatalf("FromEnv() error = %v", err)
+	}
+
+	if cfg.BeaconURL != "http://beacon:5052" || cfg.Network != "holesky" || cfg.MetricsPort != zeroConfigMetricsPort {
+		t.Errorf("Unexpected config: beacon_url=%s network=%s metrics_port=%d", cfg.BeaconURL, cfg.Network, cfg.MetricsPort)
+	}
+	if len(cfg.WatchedKeys) != 2 || cfg.WatchedKeys[1].PublicKey != "0x"+strings.Repeat("b", 96) {
+		t.Fatalf("Unexpected watched keys: %+v", cfg.WatchedKeys)
+	}
+	if labels := cfg.WatchedKeys[0].Labels; len(labels) != 1 || labels[0] != "operator:acme" {
+		t.Errorf("Unexpected labels: %v", labels)
+	}
+}
+
+func TestFromEnvInvalidKey(t *testing.T) {
+	t.Setenv(EnvBeaconURL, "http://beacon:5052")
+	t.Setenv(EnvWatchedPubkeys, "0x1234")
+
+	if _, err := FromEnv(); err == nil {
+		t.Error("FromEnv() accepted an invalid public key")
+	}
+}
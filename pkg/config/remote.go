@@ -0,0 +1,83 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"gopkg.in/yaml.v3"
+)
+
+// maxRemoteKeysSize caps the downloaded key list (about 40k keys with a few labels each)
+const maxRemoteKeysSize = 16 << 20
+
+// remoteKeyList is the document form of a remote key list, matching the config file layout
+type remoteKeyList struct {
+	WatchedKeys []models.WatchedKey `yaml:"watched_keys"`
+}
+
+// FetchWatchedKeys downloads a watched key list over HTTP(S)
+// The body is JSON or YAML, either a list of keys or a document with a watched_keys list.
+// Header values may reference environment variables (e.g. "Bearer ${KEYS_TOKEN}").
+func FetchWatchedKeys(ctx context.Context, client *http.Client, url string, headers map[string]string) ([]models.WatchedKey, error) {
+	data, err := fetchKeyList(ctx, client, url, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := parseKeyList(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateWatchedKeys(keys, "watched_keys_url"); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// fetchKeyList downloads a key list document, expanding environment variables in header values
+func fetchKeyList(ctx context.Context, client *http.Client, url string, headers map[string]string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	for name, value := range headers {
+		req.Header.Set(name, os.ExpandEnv(value))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteKeysSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if len(data) > maxRemoteKeysSize {
+		return nil, fmt.Errorf("key list exceeds %d bytes", maxRemoteKeysSize)
+	}
+	return data, nil
+}
+
+// parseKeyList decodes a key list; YAML is a superset of JSON, so one decoder handles both
+func parseKeyList(data []byte) ([]models.WatchedKey, error) {
+	var keys []models.WatchedKey
+	if err := yaml.Unmarshal(data, &keys); err == nil {
+		return keys, nil
+	}
+
+	var list remoteKeyList
+	if err := yaml.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse key list: %w", err)
+	}
+	return list.WatchedKeys, nil
+}
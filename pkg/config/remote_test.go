@@ -0,0 +1,65 @@
+package config
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testPubkey = "0x" + strings.Repeat("ab", 48)
+
+func TestFetchWatchedKeys(t *testing.T) {
+	t.Setenv("TEST_KEYS_TOKEN", "secret")
+
+	bodies := map[string]string{
+		"/keys.json": `[{"public_key": "` + testPubkey + `", "labels": ["operator:a"]}]`,
+		"/keys.yaml": "watched_keys:\n  - public_key: \"" + testPubkey + "\"\n    labels: [operator:a]\n",
+	}
+
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		body, ok := bodies[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	client := &http.Client{Timeout: time.Second}
+	headers := map[string]string{"Authorization": "Bearer ${TEST_KEYS_TOKEN}"}
+
+	for path := range bodies {
+		keys, err := FetchWatchedKeys(context.Background(), client, server.URL+path, headers)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", path, err)
+		}
+		if len(keys) != 1 || keys[0].PublicKey != testPubkey || len(keys[0].Labels) != 1 || keys[0].Labels[0] != "operator:a" {
+			t.Errorf("%s: unexpected keys %+v", path, keys)
+		}
+		if auth != "Bearer secret" {
+			t.Errorf("%s: expected expanded auth header, got %q", path, auth)
+		}
+	}
+
+	if _, err := FetchWatchedKeys(context.Background(), client, server.URL+"/missing", nil); err == nil {
+		t.Error("Expected an error for a non-200 response")
+	}
+}
+
+func TestFetchWatchedKeysInvalidKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"public_key": "0x1234"}]`))
+	}))
+	defer server.Close()
+
+	_, err := FetchWatchedKeys(context.Background(), &http.Client{Timeout: time.Second}, server.URL, nil)
+	if err == nil || !strings.Contains(err.Error(), "watched_keys_url[0]") {
+		t.Errorf("Expected the invalid key to be reported, got %v", err)
+	}
+}
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// web3SignerKeysPath lists the BLS keys a Web3Signer instance signs with
+const web3SignerKeysPath = "/api/v1/eth2/publicKeys"
+
+// FetchWeb3SignerKeys lists the keys loaded in a Web3Signer instance as watched keys with the given labels
+func FetchWeb3SignerKeys(ctx context.Context, client *http.Client, baseURL string, headers map[string]string, labels []string) ([]models.WatchedKey, error) {
+	data, err := fetchKeyList(ctx, client, strings.TrimRight(baseURL, "/")+web3SignerKeysPath, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	var pubkeys []string
+	if err := json.Unmarshal(data, &pubkeys); err != nil {
+		return nil, fmt.Errorf("failed to parse Web3Signer key list: %w", err)
+	}
+
+	keys := make([]models.WatchedKey, len(pubkeys))
+	for i, pubkey := range pubkeys {
+		keys[i] = models.WatchedKey{PublicKey: strings.ToLower(pubkey), Labels: labels}
+	}
+	if err := validateWatchedKeys(keys, "web3signer"); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFetchWeb3SignerKeys(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != web3SignerKeysPath {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`["` + strings.ToUpper(testPubkey[:4]) + testPubkey[4:] + `"]`))
+	}))
+	defer server.Close()
+
+	client := &http.Client{Timeout: time.Second}
+	keys, err := FetchWeb3SignerKeys(context.Background(), client, server.URL+"/", nil, []string{"signer:a"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(keys) != 1 || keys[0].PublicKey != testPubkey || len(keys[0].Labels) != 1 || keys[0].Labels[0] != "signer:a" {
+		t.Errorf("Unexpected keys %+v", keys)
+	}
+
+	if _, err := FetchWeb3SignerKeys(context.Background(), client, server.URL+"/missing", nil, nil); err == nil {
+		t.Error("Expected an error for a non-200 response")
+	}
+}
@@ -0,0 +1,202 @@
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// searchLimit bounds the search for the next matching time, so impossible
+// expressions like "0 0 31 2 *" end instead of looping forever
+const searchLimit = 5 * 366 * 24 * time.Hour
+
+// macros are the supported shorthand expressions
+var macros = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+}
+
+// field is the allowed range of a cron field
+type field struct {
+	name     string
+	min, max int
+}
+
+var fields = []field{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7}, // 0 and 7 are both Sunday
+}
+
+// Schedule is a parsed five-field cron expression evaluated in a time zone
+type Schedule struct {
+	minute, hour, dom, month, dow uint64 // Bitsets of matching values
+	domAny, dowAny                bool   // Field was "*", for the day-of-month/day-of-week OR rule
+	location                      *time.Location
+}
+
+// Parse parses a cron expression (minute hour day-of-month month day-of-week) or one of
+// @hourly, @daily, @weekly, @monthly, evaluated in an IANA time zone (UTC if empty)
+func Parse(expr, timezone string) (*Schedule, error) {
+	location := time.UTC
+	if timezone != "" {
+		loc, err := time.LoadLocation(timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time zone %q: %w", timezone, err)
+		}
+		location = loc
+	}
+
+	expr = strings.TrimSpace(expr)
+	if macro, ok := macros[expr]; ok {
+		expr = macro
+	}
+
+	parts := strings.Fields(expr)
+	if len(parts) != len(fields) {
+		return nil, fmt.Errorf("invalid cron expression %q: expected %d fields, got %d", expr, len(fields), len(parts))
+	}
+
+	bits := make([]uint64, len(fields))
+	for i, part := range parts {
+		set, err := parseField(part, fields[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+		}
+		bits[i] = set
+	}
+
+	// Sunday may be written as 7
+	if bits[4]&(1<<7) != 0 {
+		bits[4] |= 1
+	}
+
+	return &Schedule{
+		minute:   bits[0],
+		hour:     bits[1],
+		dom:      bits[2],
+		month:    bits[3],
+		dow:      bits[4],
+		domAny:   parts[2] == "*",
+		dowAny:   parts[4] == "*",
+		location: location,
+	}, nil
+}
+
+// parseField parses a comma-separated list of values, ranges and steps (e.g. "1-5", "*/15", "0,30")
+func parseField(part string, f field) (uint64, error) {
+	var set uint64
+	for _, item := range strings.Split(part, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(item, "/")
+
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q in %s", stepPart, f.name)
+			}
+			step = n
+		}
+
+		var lo, hi int
+		switch {
+		case rangePart == "*":
+			lo, hi = f.min, f.max
+		case strings.Contains(rangePart, "-"):
+			loPart, hiPart, _ := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = parseValue(loPart, f); err != nil {
+				return 0, err
+			}
+			if hi, err = parseValue(hiPart, f); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("invalid range %q in %s", rangePart, f.name)
+			}
+		default:
+			value, err := parseValue(rangePart, f)
+			if err != nil {
+				return 0, err
+			}
+			lo, hi = value, value
+			if hasStep {
+				hi = f.max
+			}
+		}
+
+		for value := lo; value <= hi; value += step {
+			set |= 1 << value
+		}
+	}
+	return set, nil
+}
+
+// parseValue parses a single number within the field's range
+func parseValue(s string, f field) (int, error) {
+	value, err := strconv.Atoi(s)
+	if err != nil || value < f.min || value > f.max {
+		return 0, fmt.Errorf("invalid %s %q (must be %d-%d)", f.name, s, f.min, f.max)
+	}
+	return value, nil
+}
+
+// Location returns the time zone the schedule is evaluated in
+func (s *Schedule) Location() *time.Location {
+	return s.location
+}
+
+// Next returns the first matching time strictly after t, or the zero time if there is none
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.In(s.location).Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(searchLimit)
+
+	for t.Before(limit) {
+		year, month, day := t.Date()
+		switch {
+		case s.month&(1<<uint(month)) == 0:
+			t = time.Date(year, month+1, 1, 0, 0, 0, 0, s.location)
+		case !s.dayMatches(t):
+			t = time.Date(year, month, day+1, 0, 0, 0, 0, s.location)
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			next := time.Date(year, month, day, t.Hour()+1, 0, 0, 0, s.location)
+			if !next.After(t) {
+				// Repeated hour at the end of daylight saving time
+				next = t.Add(time.Duration(60-t.Minute()) * time.Minute)
+			}
+			t = next
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// dayMatches applies the cron rule that a restricted day of month and day of week match either way
+func (s *Schedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domAny || s.dowAny {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// Window is a recurring period that opens at every schedule time and lasts Duration
+type Window struct {
+	Schedule *Schedule
+	Duration time.Duration
+}
+
+// Active reports whether t falls inside an occurrence of the window
+func (w Window) Active(t time.Time) bool {
+	start := w.Schedule.Next(t.Add(-w.Duration))
+	return !start.IsZero() && !start.After(t)
+}
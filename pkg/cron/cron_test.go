@@ -0,0 +1,86 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseErrors(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "5-1 * * * *", "*/0 * * * *", "a * * * *"} {
+		if _, err := Parse(expr, ""); err == nil {
+			t.Errorf("Expected %q to be rejected", expr)
+		}
+	}
+	if _, err := Parse("0 9 * * *", "Mars/Olympus_Mons"); err == nil {
+		t.Error("Expected unknown time zone to be rejected")
+	}
+}
+
+func TestNext(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("Time zone data unavailable: %v", err)
+	}
+
+	tests := []struct {
+		expr     string
+		timezone string
+		after    time.Time
+		expected time.Time
+	}{
+		{"*/15 * * * *", "", time.Date(2025, 1, 1, 10, 7, 30, 0, time.UTC), time.Date(2025, 1, 1, 10, 15, 0, 0, time.UTC)},
+		{"@daily", "", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)},
+		// Weekdays at 09:00 Berlin time: Friday evening -> Monday morning
+		{"0 9 * * 1-5", "Europe/Berlin", time.Date(2025, 1, 3, 18, 0, 0, 0, time.UTC), time.Date(2025, 1, 6, 9, 0, 0, 0, berlin)},
+		// Sunday written as 7
+		{"30 2 * * 7", "", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 5, 2, 30, 0, 0, time.UTC)},
+		// Day of month and day of week both restricted: either matches
+		{"0 0 15 * 1", "", time.Date(2025, 1, 7, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 13, 0, 0, 0, 0, time.UTC)},
+		// 09:00 Berlin is 08:00 UTC in winter and 07:00 UTC in summer
+		{"0 9 * * *", "Europe/Berlin", time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC), time.Date(2025, 7, 2, 7, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		s, err := Parse(tt.expr, tt.timezone)
+		if err != nil {
+			t.Fatalf("Failed to parse %q: %v", tt.expr, err)
+		}
+		if next := s.Next(tt.after); !next.Equal(tt.expected) {
+			t.Errorf("%q after %v: expected %v, got %v", tt.expr, tt.after, tt.expected, next)
+		}
+	}
+}
+
+func TestNextImpossible(t *testing.T) {
+	s, err := Parse("0 0 31 2 *", "")
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	if next := s.Next(time.Now()); !next.IsZero() {
+		t.Errorf("Expected no match for February 31st, got %v", next)
+	}
+}
+
+func TestWindowActive(t *testing.T) {
+	s, err := Parse("0 22 * * 6", "") // Saturdays 22:00
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	window := Window{Schedule: s, Duration: 2 * time.Hour}
+
+	saturday := time.Date(2025, 1, 4, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		at     time.Time
+		active bool
+	}{
+		{saturday.Add(21*time.Hour + 59*time.Minute), false},
+		{saturday.Add(22 * time.Hour), true},
+		{saturday.Add(23*time.Hour + 59*time.Minute), true},
+		{saturday.Add(24 * time.Hour), false},
+	}
+	for _, tt := range tests {
+		if active := window.Active(tt.at); active != tt.active {
+			t.Errorf("At %v: expected active=%v, got %v", tt.at, tt.active, active)
+		}
+	}
+}
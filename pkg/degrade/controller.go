@@ -0,0 +1,147 @@
+package degrade
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Level is how much optional work is shed to spare an overloaded beacon node
+type Level int
+
+const (
+	// LevelNormal runs all work
+	LevelNormal Level = iota
+	// LevelReduced runs optional work at stretched intervals
+	LevelReduced
+	// LevelMinimal sheds optional work; only duty tracking runs
+	LevelMinimal
+)
+
+// Stretch is the factor optional work intervals are lengthened by at LevelReduced
+const Stretch = 4
+
+// String returns the level name used in logs
+func (l Level) String() string {
+	switch l {
+	case LevelNormal:
+		return "normal"
+	case LevelReduced:
+		return "reduced"
+	case LevelMinimal:
+		return "minimal"
+	default:
+		return fmt.Sprintf("level-%d", int(l))
+	}
+}
+
+// Sample is the beacon node's cumulative request counters
+type Sample struct {
+	Requests uint64        // Requests attempted, including failures
+	Failures uint64        // Requests that failed with a transport error, timeout or 5xx
+	Latency  time.Duration // Total latency of the successful requests
+}
+
+// Thresholds decide when the beacon node counts as overloaded
+type Thresholds struct {
+	MaxLatency     time.Duration // Average latency of successful requests
+	MaxErrorRate   float64       // Share of failed requests
+	RecoverSamples int           // Consecutive healthy samples before stepping a level back
+	MinRequests    uint64        // Samples with fewer requests carry no signal
+}
+
+// Controller steps the degradation level up on every overloaded sample and back down
+// once the node stayed healthy for RecoverSamples samples
+type Controller struct {
+	thresholds Thresholds
+
+	mu      sync.RWMutex
+	level   Level
+	prev    Sample
+	healthy int
+}
+
+// NewController creates a controller at LevelNormal
+func NewController(thresholds Thresholds) *Controller {
+	return &Controller{thresholds: thresholds}
+}
+
+// Level returns the current level
+func (c *Controller) Level() Level {
+	if c == nil {
+		return LevelNormal
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.level
+}
+
+// Observe folds the requests since the previous sample into the level
+// It returns the new level, whether it changed and what the sample measured
+func (c *Controller) Observe(total Sample) (Level, bool, string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delta := Sample{
+		Requests: total.Requests - c.prev.Requests,
+		Failures: total.Failures - c.prev.Failures,
+		Latency:  total.Latency - c.prev.Latency,
+	}
+	c.prev = total
+	if delta.Requests == 0 || delta.Requests < c.thresholds.MinRequests {
+		return c.level, false, "too few requests"
+	}
+
+	errorRate := float64(delta.Failures) / float64(delta.Requests)
+	var latency time.Duration
+	if successes := delta.Requests - delta.Failures; successes > 0 {
+		latency = delta.Latency / time.Duration(successes)
+	}
+	measured := fmt.Sprintf("%d requests, %.1f%% failed, %s average latency", delta.Requests, errorRate*100, latency.Round(time.Millisecond))
+
+	overloaded := errorRate >= c.thresholds.MaxErrorRate || (c.thresholds.MaxLatency > 0 && latency >= c.thresholds.MaxLatency)
+	if overloaded {
+		c.healthy = 0
+		if c.level < LevelMinimal {
+			c.level++
+			return c.level, true, measured
+		}
+		return c.level, false, measured
+	}
+
+	c.healthy++
+	if c.level > LevelNormal && c.healthy >= c.thresholds.RecoverSamples {
+		c.level--
+		c.healthy = 0
+		return c.level, true, measured
+	}
+	return c.level, false, measured
+}
+
+// Due reports whether optional work that normally runs every epoch runs in an epoch:
+// always at LevelNormal, every Stretch epochs at LevelReduced and never at LevelMinimal
+func (c *Controller) Due(epoch uint64) bool {
+	switch c.Level() {
+	case LevelNormal:
+		return true
+	case LevelReduced:
+		return epoch%Stretch == 0
+	default:
+		return false
+	}
+}
+
+// DueAfter reports whether optional work with a time interval runs when it last ran age ago:
+// at its interval at LevelNormal, at Stretch times it at LevelReduced and never at LevelMinimal
+func (c *Controller) DueAfter(age, interval time.Duration) bool {
+	switch c.Level() {
+	case LevelNormal:
+		return true
+	case LevelReduced:
+		return age >= Stretch*interval
+	default:
+		return false
+	}
+}
@@ -0,0 +1,39 @@
+package degrade
+
+import (
+	"testing"
+	"time"
+)
+
+func TestControllerSteps(t *testing.T) {
+	c := NewController(Thresholds{MaxLatency: time.Second, MaxErrorRate: 0.2, RecoverSamples: 2, MinRequests: 10})
+	total := Sample{}
+	observe := func(requests, failures uint64, latency time.Duration) (Level, bool) {
+		total.Requests += requests
+		total.Failures += failures
+		total.Latency += latency * time.Duration(requests-failures)
+		level, changed, _ := c.Observe(total)
+		return level, changed
+	}
+
+	if level, changed := observe(100, 0, 100*time.Millisecond); level != LevelNormal || changed {
+		t.Errorf("Healthy sample: level %s, changed %v", level, changed)
+	}
+	if level, changed := observe(100, 0, 2*time.Second); level != LevelReduced || !changed {
+		t.Errorf("Slow sample: level %s, changed %v", level, changed)
+	}
+	if level, _ := observe(100, 50, 100*time.Millisecond); level != LevelMinimal {
+		t.Errorf("Failing sample: level %s, want minimal", level)
+	}
+	if level, changed := observe(5, 5, 0); level != LevelMinimal || changed {
+		t.Errorf("Sample below MinRequests changed the level to %s", level)
+	}
+
+	observe(100, 0, 100*time.Millisecond)
+	if level, changed := observe(100, 0, 100*time.Millisecond); level != LevelReduced || !changed {
+		t.Errorf("After RecoverSamples healthy samples: level %s, changed %v", level, changed)
+	}
+	if c.Due(5) || !c.Due(8) || c.DueAfter(time.Minute, time.Minute) || !c.DueAfter(4*time.Minute, time.Minute) {
+		t.Error("Unexpected reduced schedule")
+	}
+}
@@ -0,0 +1,147 @@
+package duties
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// TargetAggregatorsPerCommittee is the spec constant for expected aggregators per committee
+const TargetAggregatorsPerCommittee = 16
+
+// AggregatorProbability returns the probability that a committee member is selected as aggregator
+// Selection itself depends on the validator's slot signature, which the beacon API does not expose
+func AggregatorProbability(committeeLength uint64) float64 {
+	modulo := committeeLength / TargetAggregatorsPerCommittee
+	if modulo < 1 {
+		modulo = 1
+	}
+	return 1.0 / float64(modulo)
+}
+
+// IncludedCommittees returns the committee indices an on-chain attestation aggregates
+// Post-Electra attestations carry committee_bits, pre-Electra ones use data.index
+func IncludedCommittees(attestation models.Attestation) ([]uint64, error) {
+	if attestation.CommitteeBits == "" || attestation.CommitteeBits == "0x" {
+		return []uint64{attestation.Data.Index}, nil
+	}
+
+	bits, err := DecodeBitVector(attestation.CommitteeBits, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode committee bits: %w", err)
+	}
+
+	committees := make([]uint64, 0, len(bits))
+	for index := range bits {
+		committees = append(committees, uint64(index))
+	}
+	sort.Slice(committees, func(i, j int) bool { return committees[i] < committees[j] })
+	return committees, nil
+}
+
+// committeeKey identifies a committee by slot and index
+type committeeKey struct {
+	slot  models.Slot
+	index uint64
+}
+
+// AggregationOutcome is the final aggregation result for one watched validator's committee duty
+type AggregationOutcome struct {
+	ValidatorIndex       models.ValidatorIndex
+	Slot                 models.Slot
+	CommitteeIndex       uint64
+	SelectionProbability float64
+	AggregateIncluded    bool
+}
+
+// AggregationTracker follows watched validators' committees until their aggregate is seen on chain
+// or the inclusion window expires
+type AggregationTracker struct {
+	mu       sync.Mutex
+	pending  map[committeeKey][]models.AttesterDuty
+	included map[committeeKey]bool
+}
+
+// NewAggregationTracker creates a new aggregation tracker
+func NewAggregationTracker() *AggregationTracker {
+	return &AggregationTracker{
+		pending:  make(map[committeeKey][]models.AttesterDuty),
+		included: make(map[committeeKey]bool),
+	}
+}
+
+// AddDuties registers attester duties of watched validators
+func (t *AggregationTracker) AddDuties(duties []models.AttesterDuty) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, duty := range duties {
+		key := committeeKey{slot: duty.Slot, index: duty.CommitteeIndex}
+		// Ignore duties that were already registered (e.g. duties refetched for the same epoch)
+		duplicate := false
+		for _, existing := range t.pending[key] {
+			if existing.ValidatorIndex == duty.ValidatorIndex {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			t.pending[key] = append(t.pending[key], duty)
+		}
+	}
+}
+
+// ObserveBlock marks the committees aggregated by a block's attestations as included
+func (t *AggregationTracker) ObserveBlock(attestations []models.Attestation) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, attestation := range attestations {
+		committees, err := IncludedCommittees(attestation)
+		if err != nil {
+			return err
+		}
+		for _, index := range committees {
+			key := committeeKey{slot: attestation.Data.Slot, index: index}
+			if _, ok := t.pending[key]; ok {
+				t.included[key] = true
+			}
+		}
+	}
+	return nil
+}
+
+// Expire finalizes committees whose slot is before beforeSlot and returns their outcomes
+func (t *AggregationTracker) Expire(beforeSlot models.Slot) []AggregationOutcome {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var outcomes []AggregationOutcome
+	for key, duties := range t.pending {
+		if key.slot >= beforeSlot {
+			continue
+		}
+		for _, duty := range duties {
+			outcomes = append(outcomes, AggregationOutcome{
+				ValidatorIndex:       duty.ValidatorIndex,
+				Slot:                 duty.Slot,
+				CommitteeIndex:       duty.CommitteeIndex,
+				SelectionProbability: AggregatorProbability(duty.CommitteeLength),
+				AggregateIncluded:    t.included[key],
+			})
+		}
+		delete(t.pending, key)
+		delete(t.included, key)
+	}
+	return outcomes
+}
+
+// Pending returns the number of committees still awaiting an aggregate
+func (t *AggregationTracker) Pending() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return len(t.pending)
+}
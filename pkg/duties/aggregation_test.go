@@ -0,0 +1,90 @@
+package duties
+
+import (
+	"testing"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+func TestAggregatorProbability(t *testing.T) {
+	tests := []struct {
+		length   uint64
+		expected float64
+	}{
+		{length: 0, expected: 1},
+		{length: 10, expected: 1},   // Small committees: everyone aggregates
+		{length: 32, expected: 0.5}, // modulo = 2
+		{length: 512, expected: 1.0 / 32},
+	}
+
+	for _, tt := range tests {
+		if got := AggregatorProbability(tt.length); got != tt.expected {
+			t.Errorf("AggregatorProbability(%d) = %v, want %v", tt.length, got, tt.expected)
+		}
+	}
+}
+
+func TestIncludedCommittees(t *testing.T) {
+	// Pre-Electra: committee taken from data.index
+	committees, err := IncludedCommittees(models.Attestation{Data: models.AttestationData{Index: 7}})
+	if err != nil {
+		t.Fatalf("IncludedCommittees failed: %v", err)
+	}
+	if len(committees) != 1 || committees[0] != 7 {
+		t.Errorf("Expected [7], got %v", committees)
+	}
+
+	// Electra: committee bits 0 and 2 set
+	committees, err = IncludedCommittees(models.Attestation{CommitteeBits: "0x0500000000000000"})
+	if err != nil {
+		t.Fatalf("IncludedCommittees failed: %v", err)
+	}
+	if len(committees) != 2 || committees[0] != 0 || committees[1] != 2 {
+		t.Errorf("Expected [0 2], got %v", committees)
+	}
+}
+
+func TestAggregationTracker(t *testing.T) {
+	tracker := NewAggregationTracker()
+	tracker.AddDuties([]models.AttesterDuty{
+		{ValidatorIndex: 10, Slot: 100, CommitteeIndex: 0, CommitteeLength: 64},
+		{ValidatorIndex: 20, Slot: 100, CommitteeIndex: 1, CommitteeLength: 64},
+		{ValidatorIndex: 30, Slot: 140, CommitteeIndex: 0, CommitteeLength: 64},
+	})
+	// Re-registering the same duty must not double count
+	tracker.AddDuties([]models.AttesterDuty{{ValidatorIndex: 10, Slot: 100, CommitteeIndex: 0, CommitteeLength: 64}})
+
+	// Aggregate for committee 0 at slot 100 is included two blocks later
+	err := tracker.ObserveBlock([]models.Attestation{
+		{Data: models.AttestationData{Slot: 100, Index: 0}},
+	})
+	if err != nil {
+		t.Fatalf("ObserveBlock failed: %v", err)
+	}
+
+	outcomes := tracker.Expire(132)
+	if len(outcomes) != 2 {
+		t.Fatalf("Expected 2 outcomes, got %d", len(outcomes))
+	}
+	for _, outcome := range outcomes {
+		switch outcome.ValidatorIndex {
+		case 10:
+			if !outcome.AggregateIncluded {
+				t.Error("Expected validator 10's committee aggregate to be included")
+			}
+			if outcome.SelectionProbability != 0.25 {
+				t.Errorf("Expected selection probability 0.25, got %v", outcome.SelectionProbability)
+			}
+		case 20:
+			if outcome.AggregateIncluded {
+				t.Error("Expected validator 20's committee aggregate to be missing")
+			}
+		default:
+			t.Errorf("Unexpected outcome for validator %d", outcome.ValidatorIndex)
+		}
+	}
+
+	if tracker.Pending() != 1 {
+		t.Errorf("Expected 1 pending committee, got %d", tracker.Pending())
+	}
+}
@@ -43,6 +43,11 @@ func DecodeBitVector(bitVectorHex string, size int) (map[int]bool, error) {
 // ProcessAttestations processes attestations for a slot and returns validator indices that attested
 // Post-Electra format: attestations can span multiple committees using committee_bits
 func ProcessAttestations(attestations []models.Attestation, committees []models.Committee) (map[models.ValidatorIndex]bool, error) {
+	return processAttestations(attestations, committees, parseMembers)
+}
+
+// processAttestations decodes attestations using resolve to map committee positions to validator indices
+func processAttestations(attestations []models.Attestation, committees []models.Committee, resolve func(models.Committee) []models.ValidatorIndex) (map[models.ValidatorIndex]bool, error) {
 	attested := make(map[models.ValidatorIndex]bool)
 
 	// Build committee index map (committees are indexed 0..63 per slot)
@@ -68,12 +73,10 @@ func ProcessAttestations(attestations []models.Attestation, committees []models.
 			}
 
 			// Mark validators as attested
+			members := resolve(committee)
 			for pos, isSet := range bits {
-				if isSet && pos < len(committee.Validators) {
-					// Parse validator index from string
-					var validatorIndex models.ValidatorIndex
-					fmt.Sscanf(committee.Validators[pos], "%d", &validatorIndex)
-					attested[validatorIndex] = true
+				if isSet && pos < len(members) {
+					attested[members[pos]] = true
 				}
 			}
 		} else {
@@ -113,15 +116,13 @@ func ProcessAttestations(attestations []models.Attestation, committees []models.
 			committeeOffset := 0
 			for _, committee := range activeCommittees {
 				// For each validator in this committee
-				for i := 0; i < len(committee.Validators); i++ {
+				members := resolve(committee)
+				for i := 0; i < len(members); i++ {
 					bitPosition := committeeOffset + i
 
 					// Check if this validator attested
 					if aggregationBits[bitPosition] {
-						// Parse validator index from string
-						var validatorIndex models.ValidatorIndex
-						fmt.Sscanf(committee.Validators[i], "%d", &validatorIndex)
-						attested[validatorIndex] = true
+						attested[members[i]] = true
 					}
 				}
 
@@ -134,6 +135,29 @@ func ProcessAttestations(attestations []models.Attestation, committees []models.
 	return attested, nil
 }
 
+// closestIdealReward returns the ideal reward of the largest listed effective balance not above
+// the validator's, or of the smallest listed one; listed balances depend on the network's preset
+func closestIdealReward(ideals []models.IdealReward, effectiveBalance models.Gwei) models.IdealReward {
+	var below, lowest *models.IdealReward
+	for i := range ideals {
+		ideal := &ideals[i]
+		if ideal.EffectiveBalance <= effectiveBalance && (below == nil || ideal.EffectiveBalance > below.EffectiveBalance) {
+			below = ideal
+		}
+		if lowest == nil || ideal.EffectiveBalance < lowest.EffectiveBalance {
+			lowest = ideal
+		}
+	}
+	switch {
+	case below != nil:
+		return *below
+	case lowest != nil:
+		return *lowest
+	default:
+		return models.IdealReward{}
+	}
+}
+
 // ProcessRewards processes reward data and updates validator metrics
 func ProcessRewards(rewards *models.RewardsResponse, validators map[models.ValidatorIndex]models.Gwei) (map[models.ValidatorIndex]RewardData, error) {
 	result := make(map[models.ValidatorIndex]RewardData)
@@ -160,15 +184,7 @@ func ProcessRewards(rewards *models.RewardsResponse, validators map[models.Valid
 		// Find matching ideal reward using validator's actual effective balance
 		ideal, ok := idealByBalance[effectiveBalance]
 		if !ok {
-			// If exact match not found, use 32 ETH as fallback
-			ideal, ok = idealByBalance[32_000_000_000]
-			if !ok {
-				// Last resort: pick any available ideal reward
-				for _, idealReward := range idealByBalance {
-					ideal = idealReward
-					break
-				}
-			}
+			ideal = closestIdealReward(rewards.Data.IdealRewards, effectiveBalance)
 		}
 
 		data := RewardData{
@@ -227,3 +227,21 @@ func TestProcessRewards(t *testing.T) {
 		t.Error("Expected validator 200 to not have suboptimal target")
 	}
 }
+
+func TestClosestIdealReward(t *testing.T) {
+	ideals := []models.IdealReward{
+		{EffectiveBalance: 1_000_000_000, Head: 1},
+		{EffectiveBalance: 32_000_000_000, Head: 32},
+		{EffectiveBalance: 16_000_000_000, Head: 16},
+	}
+
+	for balance, want := range map[models.Gwei]models.Gwei{
+		20_000_000_000: 16, // Between listed balances
+		64_000_000_000: 32, // Above every listed balance
+		500_000_000:    1,  // Below every listed balance
+	} {
+		if got := closestIdealReward(ideals, balance); got.Head != want {
+			t.Errorf("closestIdealReward(%d) head = %d, want %d", balance, got.Head, want)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package duties
+
+import (
+	"fmt"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/cache"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// DefaultCommitteeCacheSize holds two epochs of mainnet committees (64 per slot)
+const DefaultCommitteeCacheSize = 2 * 32 * 64
+
+// CommitteeResolver parses committee members once and caches them by slot and committee index
+type CommitteeResolver struct {
+	cache *cache.Cache[committeeKey, []models.ValidatorIndex]
+}
+
+// NewCommitteeResolver creates a committee resolver holding up to maxEntries committees
+func NewCommitteeResolver(maxEntries int) *CommitteeResolver {
+	return &CommitteeResolver{
+		cache: cache.New[committeeKey, []models.ValidatorIndex]("committee_members", maxEntries, 0),
+	}
+}
+
+// Members returns the validator indices of a committee in committee order
+func (r *CommitteeResolver) Members(committee models.Committee) []models.ValidatorIndex {
+	key := committeeKey{slot: committee.Slot, index: committee.Index}
+	if members, ok := r.cache.Get(key); ok {
+		return members
+	}
+
+	members := parseMembers(committee)
+	r.cache.Set(key, members)
+	return members
+}
+
+// ProcessAttestations is ProcessAttestations using cached committee members
+func (r *CommitteeResolver) ProcessAttestations(attestations []models.Attestation, committees []models.Committee) (map[models.ValidatorIndex]bool, error) {
+	return processAttestations(attestations, committees, r.Members)
+}
+
+// Cache returns the resolver's cache for metrics collection
+func (r *CommitteeResolver) Cache() cache.Source {
+	return r.cache
+}
+
+// parseMembers parses a committee's validator indices
+func parseMembers(committee models.Committee) []models.ValidatorIndex {
+	members := make([]models.ValidatorIndex, len(committee.Validators))
+	for i, validatorStr := range committee.Validators {
+		fmt.Sscanf(validatorStr, "%d", &members[i])
+	}
+	return members
+}
@@ -0,0 +1,43 @@
+package duties
+
+import (
+	"testing"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+func TestCommitteeResolver(t *testing.T) {
+	resolver := NewCommitteeResolver(DefaultCommitteeCacheSize)
+	committees := []models.Committee{
+		{Index: 0, Slot: 100, Validators: []string{"10", "20", "30", "40"}},
+	}
+	attestations := []models.Attestation{
+		{AggregationBits: "0x05", Data: models.AttestationData{Index: 0, Slot: 100}},
+	}
+
+	members := resolver.Members(committees[0])
+	if len(members) != 4 || members[2] != 30 {
+		t.Fatalf("Unexpected members: %v", members)
+	}
+
+	attested, err := resolver.ProcessAttestations(attestations, committees)
+	if err != nil {
+		t.Fatalf("ProcessAttestations failed: %v", err)
+	}
+
+	// Must match the uncached decoder
+	expected, _ := ProcessAttestations(attestations, committees)
+	if len(attested) != len(expected) {
+		t.Fatalf("Expected %d attested, got %d", len(expected), len(attested))
+	}
+	for idx := range expected {
+		if !attested[idx] {
+			t.Errorf("Expected validator %d to have attested", idx)
+		}
+	}
+
+	stats := resolver.Cache().Stats()
+	if stats.Hits != 1 || stats.Misses != 1 {
+		t.Errorf("Expected 1 hit and 1 miss, got %+v", stats)
+	}
+}
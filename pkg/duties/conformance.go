@@ -0,0 +1,152 @@
+package duties
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// Fixture is a captured block's attestations together with the committees of the
+// attesting slot and, optionally, the participation set known to be correct.
+// Attestations and committees use the beacon API JSON encoding, so the "data" arrays of
+// /eth/v1/beacon/blocks/{slot+1}/attestations and /eth/v1/beacon/states/head/committees?slot={slot}
+// can be pasted in directly.
+type Fixture struct {
+	Name         string               `json:"name"`
+	Fork         string               `json:"fork"`
+	Slot         models.Slot          `json:"slot,string"`
+	Attestations []models.Attestation `json:"attestations"`
+	Committees   []models.Committee   `json:"committees"`
+	Expected     []string             `json:"expected_attested,omitempty"`
+}
+
+// ConformanceResult is the outcome of decoding a fixture
+type ConformanceResult struct {
+	Name        string
+	Fork        string
+	Slot        models.Slot
+	HasExpected bool
+	Attested    []models.ValidatorIndex // Decoded participation set (sorted)
+	Missing     []models.ValidatorIndex // Expected to attest but not decoded
+	Unexpected  []models.ValidatorIndex // Decoded but not expected
+}
+
+// Passed returns true if the decoded set matches the expected set exactly
+// Fixtures without an expected set always pass (they only report what was decoded)
+func (r *ConformanceResult) Passed() bool {
+	return len(r.Missing) == 0 && len(r.Unexpected) == 0
+}
+
+// LoadFixture reads a fixture from a JSON file
+func LoadFixture(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture: %w", err)
+	}
+
+	var fixture Fixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("failed to parse fixture %s: %w", path, err)
+	}
+
+	if fixture.Name == "" {
+		fixture.Name = filepath.Base(path)
+	}
+
+	return &fixture, nil
+}
+
+// LoadFixtures reads a single fixture file or every *.json fixture in a directory
+func LoadFixtures(path string) ([]*Fixture, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat fixture path: %w", err)
+	}
+
+	paths := []string{path}
+	if info.IsDir() {
+		paths, err = filepath.Glob(filepath.Join(path, "*.json"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list fixtures: %w", err)
+		}
+		sort.Strings(paths)
+	}
+
+	fixtures := make([]*Fixture, 0, len(paths))
+	for _, p := range paths {
+		fixture, err := LoadFixture(p)
+		if err != nil {
+			return nil, err
+		}
+		fixtures = append(fixtures, fixture)
+	}
+
+	return fixtures, nil
+}
+
+// RunFixture decodes a fixture the same way the watcher processes a slot and compares
+// the result against the expected participation set
+func RunFixture(fixture *Fixture) (*ConformanceResult, error) {
+	// Only attestations for the fixture slot count, as in processAttestations
+	filtered := make([]models.Attestation, 0, len(fixture.Attestations))
+	for _, att := range fixture.Attestations {
+		if att.Data.Slot == fixture.Slot {
+			filtered = append(filtered, att)
+		}
+	}
+
+	attested, err := ProcessAttestations(filtered, fixture.Committees)
+	if err != nil {
+		return nil, fmt.Errorf("fixture %s: %w", fixture.Name, err)
+	}
+
+	result := &ConformanceResult{
+		Name:        fixture.Name,
+		Fork:        fixture.Fork,
+		Slot:        fixture.Slot,
+		HasExpected: fixture.Expected != nil,
+	}
+
+	for idx := range attested {
+		result.Attested = append(result.Attested, idx)
+	}
+	sortIndices(result.Attested)
+
+	if !result.HasExpected {
+		return result, nil
+	}
+
+	expected := make(map[models.ValidatorIndex]bool, len(fixture.Expected))
+	for _, s := range fixture.Expected {
+		idx, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("fixture %s: invalid expected validator index %q", fixture.Name, s)
+		}
+		expected[models.ValidatorIndex(idx)] = true
+	}
+
+	for idx := range expected {
+		if !attested[idx] {
+			result.Missing = append(result.Missing, idx)
+		}
+	}
+	for idx := range attested {
+		if !expected[idx] {
+			result.Unexpected = append(result.Unexpected, idx)
+		}
+	}
+	sortIndices(result.Missing)
+	sortIndices(result.Unexpected)
+
+	return result, nil
+}
+
+// sortIndices sorts validator indices in ascending order
+func sortIndices(indices []models.ValidatorIndex) {
+	sort.Slice(indices, func(i, j int) bool { return indices[i] < indices[j] })
+}
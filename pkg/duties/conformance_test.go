@@ -0,0 +1,97 @@
+package duties
+
+import (
+	"testing"
+)
+
+func TestConformanceFixtures(t *testing.T) {
+	fixtures, err := LoadFixtures("testdata/conformance")
+	if err != nil {
+		t.Fatalf("LoadFixtures failed: %v", err)
+	}
+
+	if len(fixtures) == 0 {
+		t.Fatal("Expected at least one conformance fixture")
+	}
+
+	forks := make(map[string]bool)
+	for _, fixture := range fixtures {
+		forks[fixture.Fork] = true
+
+		t.Run(fixture.Name, func(t *testing.T) {
+			result, err := RunFixture(fixture)
+			if err != nil {
+				t.Fatalf("RunFixture failed: %v", err)
+			}
+
+			if !result.HasExpected {
+				t.Fatal("Bundled fixtures must declare an expected participation set")
+			}
+			if !result.Passed() {
+				t.Errorf("Participation mismatch: missing=%v unexpected=%v", result.Missing, result.Unexpected)
+			}
+			if len(result.Attested) == 0 {
+				t.Error("Expected a non-empty participation set")
+			}
+		})
+	}
+
+	// The corpus must cover both attestation formats
+	if !forks["electra"] {
+		t.Error("Expected an Electra fixture in the corpus")
+	}
+	if !forks["deneb"] {
+		t.Error("Expected a pre-Electra fixture in the corpus")
+	}
+}
+
+func TestRunFixtureReportsMismatch(t *testing.T) {
+	fixtures, err := LoadFixtures("testdata/conformance/pre_electra_basic.json")
+	if err != nil {
+		t.Fatalf("LoadFixtures failed: %v", err)
+	}
+
+	fixture := fixtures[0]
+	// Drop one expected participant and add one that never attested
+	fixture.Expected = append(fixture.Expected[1:], "1")
+
+	result, err := RunFixture(fixture)
+	if err != nil {
+		t.Fatalf("RunFixture failed: %v", err)
+	}
+
+	if result.Passed() {
+		t.Fatal("Expected fixture with altered expectations to fail")
+	}
+	if len(result.Missing) != 1 || result.Missing[0] != 1 {
+		t.Errorf("Expected validator 1 to be reported missing, got %v", result.Missing)
+	}
+	if len(result.Unexpected) != 1 {
+		t.Errorf("Expected 1 unexpected validator, got %v", result.Unexpected)
+	}
+}
+
+func TestRunFixtureWithoutExpected(t *testing.T) {
+	fixtures, err := LoadFixtures("testdata/conformance/electra_byte_boundaries.json")
+	if err != nil {
+		t.Fatalf("LoadFixtures failed: %v", err)
+	}
+
+	fixture := fixtures[0]
+	fixture.Expected = nil
+
+	result, err := RunFixture(fixture)
+	if err != nil {
+		t.Fatalf("RunFixture failed: %v", err)
+	}
+
+	if result.HasExpected {
+		t.Error("Expected HasExpected to be false")
+	}
+	if !result.Passed() {
+		t.Error("Fixtures without expectations should pass")
+	}
+	if len(result.Attested) == 0 {
+		t.Error("Expected decoded participation set to be reported")
+	}
+}
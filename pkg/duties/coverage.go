@@ -0,0 +1,95 @@
+package duties
+
+import (
+	"sync"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// Coverage is how many of an epoch's expected attestation duties were evaluated
+// Every active validator has exactly one attestation duty per epoch
+type Coverage struct {
+	Epoch     models.Epoch
+	Expected  int // Active watched validators
+	Evaluated int // Duties whose outcome was recorded
+	Slots     int // Slots whose duties were evaluated
+}
+
+// Percent returns the evaluated share of the expected duties, 100 when none were expected
+func (c Coverage) Percent() float64 {
+	if c.Expected == 0 {
+		return 100
+	}
+	return float64(c.Evaluated) * 100 / float64(c.Expected)
+}
+
+// Missing returns the expected duties that were never evaluated
+func (c Coverage) Missing() int {
+	if c.Evaluated >= c.Expected {
+		return 0
+	}
+	return c.Expected - c.Evaluated
+}
+
+// CoverageTracker counts the attestation duties evaluated per epoch, so that gaps in the
+// watcher's own data (failed committee fetches, downtime) can be told apart from validator misses
+type CoverageTracker struct {
+	mu      sync.Mutex
+	started bool
+	next    models.Epoch // First epoch not yet completed
+	epochs  map[models.Epoch]*Coverage
+}
+
+// NewCoverageTracker creates a new coverage tracker
+func NewCoverageTracker() *CoverageTracker {
+	return &CoverageTracker{epochs: make(map[models.Epoch]*Coverage)}
+}
+
+// Record adds the duties evaluated for one slot of an epoch
+// Tracking starts at the first slot of an epoch, so a partially observed first epoch is not audited
+func (t *CoverageTracker) Record(epoch models.Epoch, firstSlot bool, evaluated int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.started {
+		if !firstSlot {
+			return
+		}
+		t.started = true
+		t.next = epoch
+	}
+	if epoch < t.next {
+		return
+	}
+
+	coverage, ok := t.epochs[epoch]
+	if !ok {
+		coverage = &Coverage{Epoch: epoch}
+		t.epochs[epoch] = coverage
+	}
+	coverage.Evaluated += evaluated
+	coverage.Slots++
+}
+
+// Complete returns the coverage of every tracked epoch before an epoch, oldest first
+// Epochs without a single evaluated slot are included with nothing evaluated
+func (t *CoverageTracker) Complete(before models.Epoch, expected func(models.Epoch) int) []Coverage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.started {
+		return nil
+	}
+
+	var completed []Coverage
+	for ; t.next < before; t.next++ {
+		coverage := Coverage{Epoch: t.next}
+		if recorded, ok := t.epochs[t.next]; ok {
+			coverage = *recorded
+			delete(t.epochs, t.next)
+		}
+		coverage.Expected = expected(t.next)
+		completed = append(completed, coverage)
+	}
+	return completed
+}
@@ -0,0 +1,42 @@
+package duties
+
+import (
+	"testing"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+func TestCoverageTracker(t *testing.T) {
+	tracker := NewCoverageTracker()
+	expected := func(models.Epoch) int { return 4 }
+
+	// Started mid-epoch: epoch 9 is not audited
+	tracker.Record(9, false, 1)
+	tracker.Record(10, true, 2)
+	tracker.Record(10, false, 2)
+	tracker.Record(11, true, 3)
+	if got := tracker.Complete(10, expected); got != nil {
+		t.Errorf("Complete(10) = %+v, want nothing before tracking started", got)
+	}
+
+	// Epoch 12 was never processed (downtime)
+	got := tracker.Complete(13, expected)
+	if len(got) != 3 {
+		t.Fatalf("Complete(13) = %+v, want epochs 10, 11 and 12", got)
+	}
+	if got[0].Percent() != 100 || got[0].Slots != 2 {
+		t.Errorf("Epoch 10 = %+v, want full coverage over 2 slots", got[0])
+	}
+	if got[1].Percent() != 75 || got[1].Missing() != 1 {
+		t.Errorf("Epoch 11 = %+v, want 75%% with 1 missing", got[1])
+	}
+	if got[2].Epoch != 12 || got[2].Evaluated != 0 || got[2].Missing() != 4 {
+		t.Errorf("Epoch 12 = %+v, want nothing evaluated", got[2])
+	}
+
+	// Late records of a completed epoch are dropped
+	tracker.Record(11, false, 1)
+	if got := tracker.Complete(13, expected); got != nil {
+		t.Errorf("Complete(13) again = %+v, want nothing", got)
+	}
+}
@@ -0,0 +1,75 @@
+package duties
+
+import (
+	"sync"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// InclusionWindow is how many slots after its slot an attestation is looked for in blocks
+const InclusionWindow = 32
+
+// Inclusion is the first on-chain inclusion of a validator's attestation
+type Inclusion struct {
+	ValidatorIndex  models.ValidatorIndex
+	AttestationSlot models.Slot
+	InclusionSlot   models.Slot
+}
+
+// Delay returns the slots between the attestation and the block that included it (1 is optimal)
+func (i Inclusion) Delay() uint64 {
+	return uint64(i.InclusionSlot - i.AttestationSlot)
+}
+
+// InclusionTracker remembers which attestations were already included, so that an attestation
+// aggregated into several blocks only counts at its first inclusion
+type InclusionTracker struct {
+	mu   sync.Mutex
+	seen map[models.Slot]map[models.ValidatorIndex]bool
+}
+
+// NewInclusionTracker creates a new inclusion tracker
+func NewInclusionTracker() *InclusionTracker {
+	return &InclusionTracker{
+		seen: make(map[models.Slot]map[models.ValidatorIndex]bool),
+	}
+}
+
+// Observe records the attesters of attestationSlot included in the block at inclusionSlot,
+// returning those included for the first time
+func (t *InclusionTracker) Observe(attestationSlot, inclusionSlot models.Slot, attesters []models.ValidatorIndex) []Inclusion {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	seen, ok := t.seen[attestationSlot]
+	if !ok {
+		seen = make(map[models.ValidatorIndex]bool)
+		t.seen[attestationSlot] = seen
+	}
+
+	var inclusions []Inclusion
+	for _, index := range attesters {
+		if seen[index] {
+			continue
+		}
+		seen[index] = true
+		inclusions = append(inclusions, Inclusion{
+			ValidatorIndex:  index,
+			AttestationSlot: attestationSlot,
+			InclusionSlot:   inclusionSlot,
+		})
+	}
+	return inclusions
+}
+
+// Prune forgets the attestations of slots before beforeSlot
+func (t *InclusionTracker) Prune(beforeSlot models.Slot) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for slot := range t.seen {
+		if slot < beforeSlot {
+			delete(t.seen, slot)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package duties
+
+import (
+	"testing"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+func TestInclusionTrackerFirstInclusionOnly(t *testing.T) {
+	tracker := NewInclusionTracker()
+
+	first := tracker.Observe(100, 101, []models.ValidatorIndex{1, 2})
+	if len(first) != 2 || first[0].Delay() != 1 {
+		t.Fatalf("Expected two inclusions with delay 1, got %+v", first)
+	}
+
+	// The same attestation aggregated into a later block doesn't count again
+	later := tracker.Observe(100, 103, []models.ValidatorIndex{2, 3})
+	if len(later) != 1 || later[0].ValidatorIndex != 3 || later[0].Delay() != 3 {
+		t.Errorf("Expected only validator 3 with delay 3, got %+v", later)
+	}
+
+	tracker.Prune(101)
+	if again := tracker.Observe(100, 104, []models.ValidatorIndex{1}); len(again) != 1 {
+		t.Errorf("Expected pruned slot to be forgotten, got %+v", again)
+	}
+}
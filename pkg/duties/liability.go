@@ -0,0 +1,126 @@
+package duties
+
+import (
+	"sync"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// Liability is what a validator owes the chain in an epoch
+// It follows from the activation and exit epochs rather than the status string, which is only as
+// fresh as the last validator load and would flip a managed exit in and out of the duty set
+type Liability string
+
+const (
+	LiabilityPending Liability = "pending" // Not active yet: no duties
+	LiabilityActive  Liability = "active"  // Attests every epoch, may propose and join sync committees
+	LiabilityExiting Liability = "exiting" // Exit scheduled: owes every duty until the exit epoch
+	LiabilitySlashed Liability = "slashed" // Still assigned attestations until the exit epoch, can't propose
+	LiabilityExited  Liability = "exited"  // Past the exit epoch: no duties
+)
+
+// FarFutureEpoch is the exit epoch of validators that haven't initiated an exit
+const FarFutureEpoch = models.Epoch(^uint64(0))
+
+// Liabilities lists every state, in lifecycle order
+var Liabilities = []Liability{LiabilityPending, LiabilityActive, LiabilityExiting, LiabilitySlashed, LiabilityExited}
+
+// LiabilityAt returns a validator's liability in an epoch
+func LiabilityAt(v *models.Validator, epoch models.Epoch) Liability {
+	switch {
+	case epoch < v.Data.ActivationEpoch:
+		return LiabilityPending
+	case epoch >= v.Data.ExitEpoch:
+		return LiabilityExited
+	case v.Data.Slashed:
+		return LiabilitySlashed
+	case v.Data.ExitEpoch != FarFutureEpoch:
+		return LiabilityExiting
+	default:
+		return LiabilityActive
+	}
+}
+
+// Attests reports whether the state owes an attestation every epoch
+func (l Liability) Attests() bool {
+	return l == LiabilityActive || l == LiabilityExiting || l == LiabilitySlashed
+}
+
+// Proposes reports whether the state may be selected to propose
+func (l Liability) Proposes() bool {
+	return l == LiabilityActive || l == LiabilityExiting
+}
+
+// allowedTransitions is the lifecycle: a validator only moves forward through it
+var allowedTransitions = map[Liability][]Liability{
+	LiabilityPending: {LiabilityActive, LiabilityExiting, LiabilitySlashed, LiabilityExited},
+	LiabilityActive:  {LiabilityExiting, LiabilitySlashed, LiabilityExited},
+	LiabilityExiting: {LiabilitySlashed, LiabilityExited},
+	LiabilitySlashed: {LiabilityExited},
+}
+
+// Transition is a validator moving between liability states
+type Transition struct {
+	ValidatorIndex models.ValidatorIndex
+	Epoch          models.Epoch
+	From           Liability
+	To             Liability
+}
+
+// LiabilityTracker keeps each validator's liability and only lets it move forward, so a stale or
+// inconsistent validator load can't put an exited validator back on duty
+type LiabilityTracker struct {
+	mu     sync.Mutex
+	states map[models.ValidatorIndex]Liability
+}
+
+// NewLiabilityTracker creates an empty tracker
+func NewLiabilityTracker() *LiabilityTracker {
+	return &LiabilityTracker{states: make(map[models.ValidatorIndex]Liability)}
+}
+
+// Observe records a validator's liability in an epoch and returns the state it is held in, with the
+// transition if it moved; a move backwards is ignored
+func (t *LiabilityTracker) Observe(v *models.Validator, epoch models.Epoch) (Liability, *Transition) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	next := LiabilityAt(v, epoch)
+	prev, ok := t.states[v.Index]
+	switch {
+	case !ok:
+		t.states[v.Index] = next
+		return next, nil
+	case prev == next:
+		return prev, nil
+	}
+
+	for _, allowed := range allowedTransitions[prev] {
+		if allowed == next {
+			t.states[v.Index] = next
+			return next, &Transition{ValidatorIndex: v.Index, Epoch: epoch, From: prev, To: next}
+		}
+	}
+	return prev, nil
+}
+
+// Of returns the last observed liability of a validator
+func (t *LiabilityTracker) Of(index models.ValidatorIndex) (Liability, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	l, ok := t.states[index]
+	return l, ok
+}
+
+// Retain forgets validators that are no longer watched
+func (t *LiabilityTracker) Retain(watched func(models.ValidatorIndex) bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for index := range t.states {
+		if !watched(index) {
+			delete(t.states, index)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package duties
+
+import (
+	"testing"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+func testValidator(activation, exit models.Epoch, slashed bool) *models.Validator {
+	v := &models.Validator{Index: 7}
+	v.Data.ActivationEpoch = activation
+	v.Data.ExitEpoch = exit
+	v.Data.Slashed = slashed
+	return v
+}
+
+func TestLiabilityAt(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		v     *models.Validator
+		epoch models.Epoch
+		want  Liability
+	}{
+		{"pending", testValidator(100, FarFutureEpoch, false), 99, LiabilityPending},
+		{"active", testValidator(100, FarFutureEpoch, false), 100, LiabilityActive},
+		{"exiting", testValidator(100, 200, false), 199, LiabilityExiting},
+		{"exited at the exit epoch", testValidator(100, 200, false), 200, LiabilityExited},
+		{"slashed", testValidator(100, 300, true), 250, LiabilitySlashed},
+	} {
+		if got := LiabilityAt(tc.v, tc.epoch); got != tc.want {
+			t.Errorf("%s: LiabilityAt() = %s, want %s", tc.name, got, tc.want)
+		}
+	}
+
+	if !LiabilityExiting.Attests() || LiabilityExited.Attests() || LiabilitySlashed.Proposes() {
+		t.Error("Unexpected duties per state")
+	}
+}
+
+func TestLiabilityTracker(t *testing.T) {
+	tracker := NewLiabilityTracker()
+
+	if state, transition := tracker.Observe(testValidator(100, FarFutureEpoch, false), 150); state != LiabilityActive || transition != nil {
+		t.Fatalf("First Observe() = %s, %v", state, transition)
+	}
+
+	// The exit is initiated, then the exit epoch passes without a validator reload
+	exiting := testValidator(100, 200, false)
+	if state, transition := tracker.Observe(exiting, 160); state != LiabilityExiting || transition == nil || transition.From != LiabilityActive {
+		t.Errorf("Observe() after the exit was initiated = %s, %+v", state, transition)
+	}
+	if state, transition := tracker.Observe(exiting, 200); state != LiabilityExited || transition == nil {
+		t.Errorf("Observe() at the exit epoch = %s, %+v", state, transition)
+	}
+
+	// A stale load still showing the validator active doesn't put it back on duty
+	if state, transition := tracker.Observe(testValidator(100, FarFutureEpoch, false), 201); state != LiabilityExited || transition != nil {
+		t.Errorf("Observe() of a stale load = %s, %+v", state, transition)
+	}
+
+	tracker.Retain(func(models.ValidatorIndex) bool { return false })
+	if _, ok := tracker.Of(7); ok {
+		t.Error("Retain() kept an unwatched validator")
+	}
+}
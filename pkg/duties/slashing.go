@@ -0,0 +1,79 @@
+package duties
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// Slashing kinds
+const (
+	SlashingProposer = "proposer"
+	SlashingAttester = "attester"
+)
+
+// Slashing is one validator slashed by an operation included in a block
+type Slashing struct {
+	Kind           string
+	ValidatorIndex models.ValidatorIndex
+	OffenceSlot    models.Slot // Slot of the conflicting headers or attestations
+	Roots          [2]string   // Conflicting body roots (proposer) or attested block roots (attester)
+}
+
+// ProcessSlashings returns the validators slashed by a block's proposer and attester slashings
+// An attester slashing only slashes the validators present in both attestations
+func ProcessSlashings(block *models.Block) []Slashing {
+	var slashings []Slashing
+	body := block.Message.Body
+
+	for _, ps := range body.ProposerSlashings {
+		header1, header2 := ps.SignedHeader1.Message, ps.SignedHeader2.Message
+		slashings = append(slashings, Slashing{
+			Kind:           SlashingProposer,
+			ValidatorIndex: header1.ProposerIndex,
+			OffenceSlot:    header1.Slot,
+			Roots:          [2]string{header1.BodyRoot, header2.BodyRoot},
+		})
+	}
+
+	for _, as := range body.AttesterSlashings {
+		second := make(map[models.ValidatorIndex]bool, len(as.Attestation2.AttestingIndices))
+		for _, index := range parseIndices(as.Attestation2.AttestingIndices) {
+			second[index] = true
+		}
+
+		slashed := make(map[models.ValidatorIndex]bool)
+		for _, index := range parseIndices(as.Attestation1.AttestingIndices) {
+			if second[index] {
+				slashed[index] = true
+			}
+		}
+
+		indices := make([]models.ValidatorIndex, 0, len(slashed))
+		for index := range slashed {
+			indices = append(indices, index)
+		}
+		sort.Slice(indices, func(i, j int) bool { return indices[i] < indices[j] })
+
+		for _, index := range indices {
+			slashings = append(slashings, Slashing{
+				Kind:           SlashingAttester,
+				ValidatorIndex: index,
+				OffenceSlot:    as.Attestation1.Data.Slot,
+				Roots:          [2]string{as.Attestation1.Data.BeaconBlockRoot, as.Attestation2.Data.BeaconBlockRoot},
+			})
+		}
+	}
+
+	return slashings
+}
+
+// parseIndices parses decimal validator indices
+func parseIndices(values []string) []models.ValidatorIndex {
+	indices := make([]models.ValidatorIndex, len(values))
+	for i, value := range values {
+		fmt.Sscanf(value, "%d", &indices[i])
+	}
+	return indices
+}
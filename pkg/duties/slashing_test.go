@@ -0,0 +1,53 @@
+package duties
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+func TestProcessSlashings(t *testing.T) {
+	raw := `{
+		"message": {
+			"slot": "1000",
+			"proposer_index": "7",
+			"body": {
+				"proposer_slashings": [{
+					"signed_header_1": {"message": {"slot": "990", "proposer_index": "42", "body_root": "0xaa"}},
+					"signed_header_2": {"message": {"slot": "990", "proposer_index": "42", "body_root": "0xbb"}}
+				}],
+				"attester_slashings": [{
+					"attestation_1": {"attesting_indices": ["5", "3", "9"], "data": {"slot": "980", "beacon_block_root": "0xcc"}},
+					"attestation_2": {"attesting_indices": ["3", "4", "5"], "data": {"slot": "980", "beacon_block_root": "0xdd"}}
+				}]
+			}
+		}
+	}`
+
+	var block models.Block
+	if err := json.Unmarshal([]byte(raw), &block); err != nil {
+		t.Fatalf("Failed to decode block: %v", err)
+	}
+
+	slashings := ProcessSlashings(&block)
+	if len(slashings) != 3 {
+		t.Fatalf("Expected 3 slashed validators, got %d: %+v", len(slashings), slashings)
+	}
+
+	proposer := slashings[0]
+	if proposer.Kind != SlashingProposer || proposer.ValidatorIndex != 42 || proposer.OffenceSlot != 990 {
+		t.Errorf("Unexpected proposer slashing: %+v", proposer)
+	}
+	if proposer.Roots != [2]string{"0xaa", "0xbb"} {
+		t.Errorf("Expected conflicting body roots, got %v", proposer.Roots)
+	}
+
+	// Only validators in both attestations are slashed
+	for i, expected := range []models.ValidatorIndex{3, 5} {
+		s := slashings[1+i]
+		if s.Kind != SlashingAttester || s.ValidatorIndex != expected || s.OffenceSlot != 980 {
+			t.Errorf("Expected attester slashing of %d, got %+v", expected, s)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package duties
+
+import (
+	"encoding/hex"
+	"math/bits"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// specTestsDir holds the consensus spec tests fetched by scripts/fetch-spec-tests.sh;
+// ETH_SPEC_TESTS_DIR points elsewhere
+func specTestsDir() string {
+	if dir := os.Getenv("ETH_SPEC_TESTS_DIR"); dir != "" {
+		return dir
+	}
+	return "testdata/spec-tests"
+}
+
+// specVectorSize matches the bit size in a vector name such as bitvec_513_random_0
+var specVectorSize = regexp.MustCompile(`^bit(?:vec|list)_(\d+)_`)
+
+// specVector is a valid ssz_generic bitfield vector
+type specVector struct {
+	name  string
+	size  int // Vector length or list limit
+	bytes []byte
+	value string
+}
+
+// loadSpecVectors reads the valid vectors of an ssz_generic bitfield handler, skipping the test
+// when the spec tests weren't fetched
+func loadSpecVectors(t *testing.T, handler string) []specVector {
+	t.Helper()
+
+	root := filepath.Join(specTestsDir(), "tests", "general", "phase0", "ssz_generic", handler, "valid")
+	entries, err := os.ReadDir(root)
+	if os.IsNotExist(err) {
+		t.Skipf("No spec tests in %s; run make spec-tests", specTestsDir())
+	}
+	if err != nil {
+		t.Fatalf("Failed to list %s: %v", root, err)
+	}
+
+	var vectors []specVector
+	for _, entry := range entries {
+		m := specVectorSize.FindStringSubmatch(entry.Name())
+		if m == nil {
+			continue
+		}
+		size, _ := strconv.Atoi(m[1])
+
+		raw, err := os.ReadFile(filepath.Join(root, entry.Name(), "value.yaml"))
+		if err != nil {
+			t.Fatalf("%s: %v", entry.Name(), err)
+		}
+		var value string
+		if err := yaml.Unmarshal(raw, &value); err != nil {
+			t.Fatalf("%s: failed to parse value: %v", entry.Name(), err)
+		}
+		decoded, err := hex.DecodeString(strings.TrimPrefix(value, "0x"))
+		if err != nil {
+			t.Fatalf("%s: invalid hex value: %v", entry.Name(), err)
+		}
+		vectors = append(vectors, specVector{name: entry.Name(), size: size, bytes: decoded, value: value})
+	}
+	if len(vectors) == 0 {
+		t.Fatalf("No %s vectors in %s", handler, root)
+	}
+	return vectors
+}
+
+// popCount counts the set bits of a serialized bitfield
+func popCount(b []byte) int {
+	n := 0
+	for _, v := range b {
+		n += bits.OnesCount8(v)
+	}
+	return n
+}
+
+// committee_bits are an SSZ Bitvector: bit i is bit i%8 of byte i/8
+func TestSpecVectorsBitvector(t *testing.T) {
+	for _, v := range loadSpecVectors(t, "bitvector") {
+		t.Run(v.name, func(t *testing.T) {
+			if len(v.bytes) != (v.size+7)/8 {
+				t.Fatalf("Expected %d bytes for %d bits, got %d", (v.size+7)/8, v.size, len(v.bytes))
+			}
+			decoded, err := DecodeBitVector(v.value, v.size)
+			if err != nil {
+				t.Fatalf("DecodeBitVector failed: %v", err)
+			}
+			if len(decoded) != popCount(v.bytes) {
+				t.Errorf("Expected %d set bits, got %d", popCount(v.bytes), len(decoded))
+			}
+			for pos := range decoded {
+				if pos >= v.size || v.bytes[pos/8]&(1<<(pos%8)) == 0 {
+					t.Errorf("Bit %d decoded as set", pos)
+				}
+			}
+		})
+	}
+}
+
+// aggregation_bits are an SSZ Bitlist: the highest set bit delimits the list and isn't part of it,
+// and the watcher decodes them with the committee size as the list length
+func TestSpecVectorsBitlist(t *testing.T) {
+	for _, v := range loadSpecVectors(t, "bitlist") {
+		t.Run(v.name, func(t *testing.T) {
+			last := v.bytes[len(v.bytes)-1]
+			if last == 0 {
+				t.Fatal("Valid bitlist without a delimiter bit")
+			}
+			length := (len(v.bytes)-1)*8 + bits.Len8(last) - 1
+			if length > v.size {
+				t.Fatalf("Bitlist of %d bits over its limit %d", length, v.size)
+			}
+
+			decoded, err := DecodeBitVector(v.value, length)
+			if err != nil {
+				t.Fatalf("DecodeBitVector failed: %v", err)
+			}
+			if decoded[length] {
+				t.Error("Delimiter bit decoded as a participant")
+			}
+			if len(decoded) != popCount(v.bytes)-1 {
+				t.Errorf("Expected %d participants, got %d", popCount(v.bytes)-1, len(decoded))
+			}
+			for pos := range decoded {
+				if pos >= length || v.bytes[pos/8]&(1<<(pos%8)) == 0 {
+					t.Errorf("Bit %d decoded as set", pos)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package duties
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// SyncPeriod returns the sync committee period of an epoch and its first and last epochs
+func SyncPeriod(epoch models.Epoch, epochsPerPeriod uint64) (period uint64, start, end models.Epoch) {
+	period = uint64(epoch) / epochsPerPeriod
+	start = models.Epoch(period * epochsPerPeriod)
+	return period, start, start + models.Epoch(epochsPerPeriod) - 1
+}
+
+// SyncCommitteeMembership is a period's sync committee as far as the watched validators are concerned
+type SyncCommitteeMembership struct {
+	Period     uint64
+	StartEpoch models.Epoch
+	EndEpoch   models.Epoch                    // Last epoch of the period
+	Size       int                             // Committee positions
+	Positions  map[models.ValidatorIndex][]int // Watched members and their committee positions
+}
+
+// NewSyncCommitteeMembership finds the watched validators in a sync committee
+func NewSyncCommitteeMembership(epoch models.Epoch, epochsPerPeriod uint64, committee *models.SyncCommittee, watched func(models.ValidatorIndex) bool) (*SyncCommitteeMembership, error) {
+	period, start, end := SyncPeriod(epoch, epochsPerPeriod)
+	membership := &SyncCommitteeMembership{
+		Period:     period,
+		StartEpoch: start,
+		EndEpoch:   end,
+		Size:       len(committee.Validators),
+		Positions:  make(map[models.ValidatorIndex][]int),
+	}
+
+	for position, value := range committee.Validators {
+		index, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sync committee member %q: %w", value, err)
+		}
+		if watched(models.ValidatorIndex(index)) {
+			membership.Positions[models.ValidatorIndex(index)] = append(membership.Positions[models.ValidatorIndex(index)], position)
+		}
+	}
+	return membership, nil
+}
+
+// Covers reports whether the membership is the committee of an epoch's period
+func (m *SyncCommitteeMembership) Covers(epoch models.Epoch) bool {
+	return epoch >= m.StartEpoch && epoch <= m.EndEpoch
+}
+
+// CountByLabel counts the watched members carrying each of labels, reporting 0 for labels without one
+func (m *SyncCommitteeMembership) CountByLabel(labels []string, labelsOf func(models.ValidatorIndex) []string) map[string]int {
+	counts := make(map[string]int, len(labels))
+	for _, label := range labels {
+		counts[label] = 0
+	}
+	for index := range m.Positions {
+		for _, label := range labelsOf(index) {
+			if _, ok := counts[label]; ok {
+				counts[label]++
+			}
+		}
+	}
+	return counts
+}
+
+// Participation decodes a sync aggregate's bitvector and reports, for every watched member,
+// whether it signed at all of its positions
+func (m *SyncCommitteeMembership) Participation(bits string) (map[models.ValidatorIndex]bool, error) {
+	raw, err := hex.DecodeString(strings.TrimPrefix(bits, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid sync committee bits: %w", err)
+	}
+	if len(raw)*8 < m.Size {
+		return nil, fmt.Errorf("sync committee bits cover %d positions, committee has %d", len(raw)*8, m.Size)
+	}
+
+	participation := make(map[models.ValidatorIndex]bool, len(m.Positions))
+	for index, positions := range m.Positions {
+		signed := true
+		for _, position := range positions {
+			if raw[position/8]&(1<<(position%8)) == 0 {
+				signed = false
+				break
+			}
+		}
+		participation[index] = signed
+	}
+	return participation, nil
+}
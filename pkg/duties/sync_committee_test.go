@@ -0,0 +1,64 @@
+package duties
+
+import (
+	"testing"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+func TestSyncPeriod(t *testing.T) {
+	period, start, end := SyncPeriod(520, 256)
+	if period != 2 || start != 512 || end != 767 {
+		t.Errorf("SyncPeriod(520, 256) = %d, %d, %d, want 2, 512, 767", period, start, end)
+	}
+}
+
+func TestSyncCommitteeMembership(t *testing.T) {
+	committee := &models.SyncCommittee{Validators: []string{"5", "9", "7", "5", "1", "2", "3", "4", "6", "8"}}
+	watched := func(index models.ValidatorIndex) bool { return index == 5 || index == 7 }
+
+	membership, err := NewSyncCommitteeMembership(300, 256, committee, watched)
+	if err != nil {
+		t.Fatalf("NewSyncCommitteeMembership() error = %v", err)
+	}
+	if membership.Period != 1 || membership.Size != 10 || !membership.Covers(511) || membership.Covers(512) {
+		t.Errorf("Unexpected period: %+v", membership)
+	}
+	if got := membership.Positions[5]; len(got) != 2 || got[0] != 0 || got[1] != 3 {
+		t.Errorf("Positions of 5 = %v, want [0 3]", got)
+	}
+
+	// Bits 0, 2 set (5 misses position 3, 7 signed at position 2), bit 9 set in the second byte
+	participation, err := membership.Participation("0x0502")
+	if err != nil {
+		t.Fatalf("Participation() error = %v", err)
+	}
+	if participation[5] || !participation[7] {
+		t.Errorf("Participation() = %v, want 5 missed and 7 signed", participation)
+	}
+
+	if _, err := membership.Participation("0x05"); err == nil {
+		t.Error("Participation() accepted bits shorter than the committee")
+	}
+}
+
+func TestSyncCommitteeCountByLabel(t *testing.T) {
+	membership := &SyncCommitteeMembership{Positions: map[models.ValidatorIndex][]int{5: {0, 3}, 7: {2}}}
+	labelsOf := func(index models.ValidatorIndex) []string {
+		if index == 5 {
+			return []string{"scope:watched", "operator:a"}
+		}
+		return []string{"scope:watched", "operator:b"}
+	}
+
+	counts := membership.CountByLabel([]string{"scope:watched", "operator:a", "operator:c"}, labelsOf)
+	if counts["scope:watched"] != 2 || counts["operator:a"] != 1 {
+		t.Errorf("CountByLabel() = %v, want 2 watched and 1 operator:a", counts)
+	}
+	if count, ok := counts["operator:c"]; !ok || count != 0 {
+		t.Errorf("CountByLabel() = %v, want operator:c reported as 0", counts)
+	}
+	if _, ok := counts["operator:b"]; ok {
+		t.Errorf("CountByLabel() = %v, want only the requested labels", counts)
+	}
+}
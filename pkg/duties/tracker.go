@@ -0,0 +1,122 @@
+package duties
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// Outcome is the settled result of a validator's attestation duty in an epoch
+type Outcome string
+
+const (
+	OutcomeIncluded Outcome = "included" // An attestation was seen included in a block
+	OutcomeLive     Outcome = "live"     // Not seen included, but the node saw the validator attest (e.g. included late)
+	OutcomeMissed   Outcome = "missed"   // Neither included nor live
+)
+
+// Performed reports whether the outcome counts as a performed duty
+func (o Outcome) Performed() bool {
+	return o != OutcomeMissed
+}
+
+// DutyResult is the one authoritative outcome of a validator's attestation duty in an epoch
+type DutyResult struct {
+	ValidatorIndex models.ValidatorIndex
+	Slot           models.Slot // Duty slot, 0 if the duty was only known from liveness
+	Outcome        Outcome
+}
+
+// dutyRecord is what was observed of a validator's duty before the epoch settles
+type dutyRecord struct {
+	slot     models.Slot
+	included bool
+}
+
+// DutyTracker reconciles block-included attestations with the liveness endpoint, so every
+// validator gets exactly one performed or missed attestation duty per epoch whichever sources saw it
+// Blocks are seen slot by slot and liveness once the epoch is over: the epoch settles on liveness
+type DutyTracker struct {
+	mu     sync.Mutex
+	epochs map[models.Epoch]map[models.ValidatorIndex]*dutyRecord
+}
+
+// NewDutyTracker creates an empty tracker
+func NewDutyTracker() *DutyTracker {
+	return &DutyTracker{epochs: make(map[models.Epoch]map[models.ValidatorIndex]*dutyRecord)}
+}
+
+// record returns the record of a validator's duty in an epoch; the caller holds the lock
+func (t *DutyTracker) record(epoch models.Epoch, index models.ValidatorIndex) *dutyRecord {
+	records, ok := t.epochs[epoch]
+	if !ok {
+		records = make(map[models.ValidatorIndex]*dutyRecord)
+		t.epochs[epoch] = records
+	}
+	r, ok := records[index]
+	if !ok {
+		r = &dutyRecord{}
+		records[index] = r
+	}
+	return r
+}
+
+// Assign records a validator's attestation duty at a slot
+func (t *DutyTracker) Assign(epoch models.Epoch, index models.ValidatorIndex, slot models.Slot) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.record(epoch, index).slot = slot
+}
+
+// Include records that the validator's attestation of an epoch was included in a block, in time or late
+func (t *DutyTracker) Include(epoch models.Epoch, index models.ValidatorIndex) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.record(epoch, index).included = true
+}
+
+// Settle returns the outcome of every duty of an epoch and forgets it with any older epoch
+// Duties seen in blocks are settled by inclusion first and liveness second; validators only known
+// from liveness (slots the watcher didn't process) are settled by liveness alone
+// Without liveness (nil), inclusion decides
+func (t *DutyTracker) Settle(epoch models.Epoch, liveness map[models.ValidatorIndex]bool) []DutyResult {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	records := t.epochs[epoch]
+	for e := range t.epochs {
+		if e <= epoch {
+			delete(t.epochs, e)
+		}
+	}
+
+	results := make([]DutyResult, 0, max(len(records), len(liveness)))
+	for index, r := range records {
+		outcome := OutcomeMissed
+		switch {
+		case r.included:
+			outcome = OutcomeIncluded
+		case liveness[index]:
+			outcome = OutcomeLive
+		}
+		results = append(results, DutyResult{ValidatorIndex: index, Slot: r.slot, Outcome: outcome})
+	}
+	for index, live := range liveness {
+		if _, ok := records[index]; ok {
+			continue
+		}
+		outcome := OutcomeMissed
+		if live {
+			outcome = OutcomeLive
+		}
+		results = append(results, DutyResult{ValidatorIndex: index, Outcome: outcome})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].ValidatorIndex < results[j].ValidatorIndex
+	})
+	return results
+}
@@ -0,0 +1,56 @@
+package duties
+
+import (
+	"testing"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+func TestDutyTracker(t *testing.T) {
+	tracker := NewDutyTracker()
+
+	tracker.Assign(10, 1, 320) // Included in the next slot
+	tracker.Include(10, 1)
+	tracker.Assign(10, 2, 321) // Included late
+	tracker.Include(10, 2)
+	tracker.Assign(10, 3, 322) // Not seen included, live
+	tracker.Assign(10, 4, 323) // Missed
+	tracker.Assign(9, 5, 300)  // Never settled
+
+	liveness := map[models.ValidatorIndex]bool{1: true, 3: true, 4: false, 6: false, 7: true}
+	results := tracker.Settle(10, liveness)
+
+	expected := []DutyResult{
+		{ValidatorIndex: 1, Slot: 320, Outcome: OutcomeIncluded},
+		{ValidatorIndex: 2, Slot: 321, Outcome: OutcomeIncluded},
+		{ValidatorIndex: 3, Slot: 322, Outcome: OutcomeLive},
+		{ValidatorIndex: 4, Slot: 323, Outcome: OutcomeMissed},
+		{ValidatorIndex: 6, Outcome: OutcomeMissed}, // Only known from liveness
+		{ValidatorIndex: 7, Outcome: OutcomeLive},
+	}
+	if len(results) != len(expected) {
+		t.Fatalf("Settle(10) = %+v, want %+v", results, expected)
+	}
+	for i := range expected {
+		if results[i] != expected[i] {
+			t.Errorf("Result %d = %+v, want %+v", i, results[i], expected[i])
+		}
+	}
+
+	// Settled and older epochs are forgotten, so a duty is never counted twice
+	if results := tracker.Settle(10, liveness); len(results) != 5 || results[0].Slot != 0 {
+		t.Errorf("Settle(10) again = %+v, want liveness only", results)
+	}
+	if len(tracker.epochs) != 0 {
+		t.Errorf("Expected no tracked epochs, got %d", len(tracker.epochs))
+	}
+
+	// Without liveness, inclusion decides
+	tracker.Assign(11, 1, 352)
+	tracker.Assign(11, 2, 353)
+	tracker.Include(11, 2)
+	results = tracker.Settle(11, nil)
+	if len(results) != 2 || results[0].Outcome != OutcomeMissed || !results[1].Outcome.Performed() {
+		t.Errorf("Settle(11) = %+v, want 1 missed and 2 included", results)
+	}
+}
@@ -0,0 +1,18 @@
+package duties
+
+import "github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+
+// Withdrawal kinds
+const (
+	WithdrawalPartial = "partial" // Balance above the maximum effective balance, swept while the validator stays active
+	WithdrawalFull    = "full"    // The whole balance of a withdrawable validator: its exit is complete
+)
+
+// WithdrawalKind classifies a validator's withdrawal in an epoch
+// Once the withdrawable epoch is reached, the sweep withdraws the whole balance
+func WithdrawalKind(v *models.Validator, epoch models.Epoch) string {
+	if epoch >= v.Data.WithdrawableEpoch {
+		return WithdrawalFull
+	}
+	return WithdrawalPartial
+}
@@ -0,0 +1,24 @@
+package duties
+
+import (
+	"testing"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+func TestWithdrawalKind(t *testing.T) {
+	v := &models.Validator{}
+	v.Data.WithdrawableEpoch = FarFutureEpoch
+	if kind := WithdrawalKind(v, 100); kind != WithdrawalPartial {
+		t.Errorf("Expected a partial withdrawal of an active validator, got %s", kind)
+	}
+
+	v.Data.ExitEpoch = 90
+	v.Data.WithdrawableEpoch = 346
+	if kind := WithdrawalKind(v, 345); kind != WithdrawalPartial {
+		t.Errorf("Expected a partial withdrawal before the withdrawable epoch, got %s", kind)
+	}
+	if kind := WithdrawalKind(v, 346); kind != WithdrawalFull {
+		t.Errorf("Expected a full withdrawal from the withdrawable epoch, got %s", kind)
+	}
+}
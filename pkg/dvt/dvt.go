@@ -0,0 +1,121 @@
+package dvt
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// Label prefixes attached to keys derived from distributed validator setups
+const (
+	LabelCluster     = "cluster:"      // Cluster the key's shares are split across
+	LabelSSVOperator = "ssv_operator:" // Configured SSV operator running a share of the key
+	LabelDVT         = "dvt:"          // Distributed validator technology (obol, ssv)
+)
+
+// DefaultSSVAPIURL is the public SSV network API
+const DefaultSSVAPIURL = "https://api.ssv.network/api/v4"
+
+// Resolve derives watched keys from the configured Obol lock files and SSV operators
+func Resolve(ctx context.Context, cfg models.DVT, network string, logger *logrus.Logger) ([]models.WatchedKey, error) {
+	var keys []models.WatchedKey
+
+	for _, path := range cfg.ObolLockFiles {
+		lock, err := LoadObolLock(path)
+		if err != nil {
+			return nil, err
+		}
+		clusterKeys := lock.WatchedKeys()
+		logger.WithFields(logrus.Fields{
+			"file":       path,
+			"cluster":    lock.ClusterName(),
+			"validators": len(clusterKeys),
+		}).Info("Loaded Obol cluster lock")
+		keys = Merge(keys, clusterKeys)
+	}
+
+	if len(cfg.SSVOperatorIDs) > 0 {
+		apiURL := cfg.SSVAPIURL
+		if apiURL == "" {
+			apiURL = DefaultSSVAPIURL
+		}
+		client := NewSSVClient(apiURL, network, 30*time.Second)
+		for _, id := range cfg.SSVOperatorIDs {
+			operatorKeys, err := client.OperatorKeys(ctx, id)
+			if err != nil {
+				return nil, fmt.Errorf("ssv operator %d: %w", id, err)
+			}
+			logger.WithFields(logrus.Fields{
+				"operator":   id,
+				"validators": len(operatorKeys),
+			}).Info("Loaded SSV operator validators")
+			keys = Merge(keys, operatorKeys)
+		}
+	}
+
+	return keys, nil
+}
+
+// Merge adds extra keys to base, appending labels to keys present in both
+// Labels already on a key keep their position so the configured primary label wins
+func Merge(base, extra []models.WatchedKey) []models.WatchedKey {
+	merged := make([]models.WatchedKey, 0, len(base)+len(extra))
+	positions := make(map[string]int, len(base)+len(extra))
+
+	add := func(wk models.WatchedKey) {
+		pubkey := normalizePubkey(wk.PublicKey)
+		i, ok := positions[pubkey]
+		if !ok {
+			positions[pubkey] = len(merged)
+			merged = append(merged, models.WatchedKey{
+				PublicKey: pubkey,
+				Labels:    appendMissing(nil, wk.Labels),
+			})
+			return
+		}
+		merged[i].Labels = appendMissing(merged[i].Labels, wk.Labels)
+	}
+
+	for _, wk := range base {
+		add(wk)
+	}
+	for _, wk := range extra {
+		add(wk)
+	}
+	return merged
+}
+
+// appendMissing appends the labels not already in dst
+func appendMissing(dst, labels []string) []string {
+	for _, label := range labels {
+		found := false
+		for _, existing := range dst {
+			if existing == label {
+				found = true
+				break
+			}
+		}
+		if !found {
+			dst = append(dst, label)
+		}
+	}
+	return dst
+}
+
+// normalizePubkey lowercases a pubkey and ensures the 0x prefix
+func normalizePubkey(pubkey string) string {
+	pubkey = strings.ToLower(strings.TrimSpace(pubkey))
+	if !strings.HasPrefix(pubkey, "0x") {
+		pubkey = "0x" + pubkey
+	}
+	return pubkey
+}
+
+// sanitizeLabelValue turns free-form names into label-friendly values
+func sanitizeLabelValue(value string) string {
+	return strings.Join(strings.Fields(strings.ToLower(value)), "-")
+}
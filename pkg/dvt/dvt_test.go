@@ -0,0 +1,89 @@
+package dvt
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+func TestLoadObolLock(t *testing.T) {
+	lock, err := LoadObolLock("testdata/cluster-lock.json")
+	if err != nil {
+		t.Fatalf("Failed to load cluster lock: %v", err)
+	}
+
+	if lock.ClusterName() != "lido-simple-dvt-7" {
+		t.Errorf("Expected sanitized cluster name, got %q", lock.ClusterName())
+	}
+
+	keys := lock.WatchedKeys()
+	if len(keys) != 2 {
+		t.Fatalf("Expected 2 keys, got %d", len(keys))
+	}
+	if keys[1].PublicKey != "0x"+strings.Repeat("b", 96) {
+		t.Errorf("Expected lowercased pubkey, got %s", keys[1].PublicKey)
+	}
+	if len(keys[0].Labels) != 2 || keys[0].Labels[0] != "cluster:lido-simple-dvt-7" || keys[0].Labels[1] != "dvt:obol" {
+		t.Errorf("Unexpected labels: %v", keys[0].Labels)
+	}
+
+	lock.Definition.Name = ""
+	if lock.ClusterName() != "0xc0ffee00" {
+		t.Errorf("Expected lock hash prefix for unnamed cluster, got %q", lock.ClusterName())
+	}
+}
+
+func TestSSVOperatorKeys(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/mainnet/validators/in_operator/42" {
+			http.NotFound(w, r)
+			return
+		}
+		// Two pages of one validator each, returned without the 0x prefix like the SSV API
+		page := r.URL.Query().Get("page")
+		key := strings.Repeat(map[string]string{"1": "c", "2": "d"}[page], 96)
+		fmt.Fprintf(w, `{"validators":[{"public_key":%q,"operators":[42,7,19,3]}],"pagination":{"page":%s,"pages":2}}`, key, page)
+	}))
+	defer server.Close()
+
+	keys, err := NewSSVClient(server.URL+"/", "mainnet", 0).OperatorKeys(context.Background(), 42)
+	if err != nil {
+		t.Fatalf("OperatorKeys failed: %v", err)
+	}
+	if len(keys) != 2 {
+		t.Fatalf("Expected keys from both pages, got %d", len(keys))
+	}
+	if keys[0].PublicKey != "0x"+strings.Repeat("c", 96) {
+		t.Errorf("Expected 0x-prefixed pubkey, got %s", keys[0].PublicKey)
+	}
+
+	expected := []string{"ssv_operator:42", "dvt:ssv", "cluster:ssv-3-7-19-42"}
+	if strings.Join(keys[0].Labels, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected labels %v, got %v", expected, keys[0].Labels)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	key := "0x" + strings.Repeat("a", 96)
+	base := []models.WatchedKey{{PublicKey: key, Labels: []string{"operator:me"}}}
+	extra := []models.WatchedKey{
+		{PublicKey: strings.ToUpper(key[2:]), Labels: []string{"cluster:one", "operator:me"}},
+		{PublicKey: "0x" + strings.Repeat("b", 96), Labels: []string{"cluster:one"}},
+	}
+
+	merged := Merge(base, extra)
+	if len(merged) != 2 {
+		t.Fatalf("Expected 2 keys, got %d", len(merged))
+	}
+	if strings.Join(merged[0].Labels, ",") != "operator:me,cluster:one" {
+		t.Errorf("Expected configured label first and no duplicates, got %v", merged[0].Labels)
+	}
+	if len(base[0].Labels) != 1 {
+		t.Error("Merge must not modify the base keys")
+	}
+}
@@ -0,0 +1,64 @@
+package dvt
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// ObolLock is the subset of an Obol cluster-lock.json the watcher needs
+type ObolLock struct {
+	Definition struct {
+		Name string `json:"name"`
+	} `json:"cluster_definition"`
+	Validators []struct {
+		PublicKey string `json:"distributed_public_key"`
+	} `json:"distributed_validators"`
+	LockHash string `json:"lock_hash"`
+}
+
+// LoadObolLock reads an Obol cluster lock file
+func LoadObolLock(path string) (*ObolLock, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cluster lock: %w", err)
+	}
+
+	var lock ObolLock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("failed to parse cluster lock %s: %w", path, err)
+	}
+	if len(lock.Validators) == 0 {
+		return nil, fmt.Errorf("cluster lock %s has no distributed validators", path)
+	}
+
+	return &lock, nil
+}
+
+// ClusterName returns the cluster's label value: its name, or the lock hash prefix if unnamed
+func (l *ObolLock) ClusterName() string {
+	if name := sanitizeLabelValue(l.Definition.Name); name != "" {
+		return name
+	}
+	hash := normalizePubkey(l.LockHash)
+	if len(hash) > 10 {
+		hash = hash[:10]
+	}
+	return hash
+}
+
+// WatchedKeys returns the cluster's distributed validator keys labelled with the cluster
+func (l *ObolLock) WatchedKeys() []models.WatchedKey {
+	labels := []string{LabelCluster + l.ClusterName(), LabelDVT + "obol"}
+
+	keys := make([]models.WatchedKey, 0, len(l.Validators))
+	for _, v := range l.Validators {
+		keys = append(keys, models.WatchedKey{
+			PublicKey: normalizePubkey(v.PublicKey),
+			Labels:    labels,
+		})
+	}
+	return keys
+}
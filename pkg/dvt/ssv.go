@@ -0,0 +1,116 @@
+package dvt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// ssvPageSize is the number of validators requested per SSV API page
+const ssvPageSize = 100
+
+// SSVClient looks up the validators run by SSV operators
+type SSVClient struct {
+	baseURL string
+	network string
+	client  *http.Client
+}
+
+// ssvValidatorsResponse is one page of /validators/in_operator
+type ssvValidatorsResponse struct {
+	Validators []struct {
+		PublicKey string   `json:"public_key"`
+		Operators []uint64 `json:"operators"`
+	} `json:"validators"`
+	Pagination struct {
+		Page  int `json:"page"`
+		Pages int `json:"pages"`
+	} `json:"pagination"`
+}
+
+// NewSSVClient creates a new SSV API client
+func NewSSVClient(baseURL, network string, timeout time.Duration) *SSVClient {
+	return &SSVClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		network: network,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+// OperatorKeys returns the validators run by an operator
+// Keys are labelled with the operator and with the operator set (the SSV cluster) they belong to
+func (c *SSVClient) OperatorKeys(ctx context.Context, operatorID uint64) ([]models.WatchedKey, error) {
+	var keys []models.WatchedKey
+
+	for page := 1; ; page++ {
+		resp, err := c.fetchPage(ctx, operatorID, page)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range resp.Validators {
+			labels := []string{LabelSSVOperator + strconv.FormatUint(operatorID, 10), LabelDVT + "ssv"}
+			if cluster := ssvClusterName(v.Operators); cluster != "" {
+				labels = append(labels, LabelCluster+cluster)
+			}
+			keys = append(keys, models.WatchedKey{
+				PublicKey: normalizePubkey(v.PublicKey),
+				Labels:    labels,
+			})
+		}
+
+		if page >= resp.Pagination.Pages {
+			return keys, nil
+		}
+	}
+}
+
+// fetchPage fetches one page of an operator's validators
+func (c *SSVClient) fetchPage(ctx context.Context, operatorID uint64, page int) (*ssvValidatorsResponse, error) {
+	url := fmt.Sprintf("%s/%s/validators/in_operator/%d?page=%d&perPage=%d", c.baseURL, c.network, operatorID, page, ssvPageSize)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var result ssvValidatorsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &result, nil
+}
+
+// ssvClusterName identifies an SSV cluster by its sorted operator IDs
+func ssvClusterName(operators []uint64) string {
+	if len(operators) == 0 {
+		return ""
+	}
+
+	ids := make([]uint64, len(operators))
+	copy(ids, operators)
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.FormatUint(id, 10)
+	}
+	return "ssv-" + strings.Join(parts, "-")
+}
@@ -0,0 +1,211 @@
+package events
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Event encoding formats
+const (
+	FormatJSON        = "json"
+	FormatCloudEvents = "cloudevents"
+	FormatProtobuf    = "protobuf"
+)
+
+// CloudEventsTypePrefix prefixes the event type in CloudEvents envelopes
+const CloudEventsTypePrefix = "com.github.enriquemanuel.eth-validator-watcher."
+
+// Encoder serializes events for a sink
+type Encoder interface {
+	// Marshal encodes one event as a standalone message, e.g. a webhook body or a Kafka record value
+	Marshal(event Event) ([]byte, error)
+	// Delimit frames a message for a stream of events, e.g. a file
+	Delimit(message []byte) []byte
+	// ContentType is the media type of a message
+	ContentType() string
+}
+
+// NewEncoder returns the encoder of a format; source identifies this watcher in CloudEvents envelopes
+func NewEncoder(format, source string) (Encoder, error) {
+	switch format {
+	case "", FormatJSON:
+		return JSONEncoder{}, nil
+	case FormatCloudEvents:
+		return CloudEventsEncoder{Source: source}, nil
+	case FormatProtobuf:
+		return ProtobufEncoder{}, nil
+	default:
+		return nil, fmt.Errorf("unknown event format %q (json, cloudevents or protobuf)", format)
+	}
+}
+
+// JSONEncoder encodes events as JSON objects, one per line in a stream
+type JSONEncoder struct{}
+
+// Marshal encodes the event as JSON
+func (JSONEncoder) Marshal(event Event) ([]byte, error) {
+	return json.Marshal(event)
+}
+
+// Delimit terminates the message with a newline (JSON lines)
+func (JSONEncoder) Delimit(message []byte) []byte {
+	return append(message, '\n')
+}
+
+// ContentType returns the JSON media type
+func (JSONEncoder) ContentType() string {
+	return "application/json"
+}
+
+// CloudEventsEncoder wraps events in a CloudEvents 1.0 envelope (JSON structured mode)
+type CloudEventsEncoder struct {
+	Source string // URI reference of the producing watcher, e.g. /eth-validator-watcher/mainnet
+}
+
+// cloudEvent is a CloudEvents 1.0 envelope
+type cloudEvent struct {
+	SpecVersion     string `json:"specversion"`
+	ID              string `json:"id"`
+	Source          string `json:"source"`
+	Type            string `json:"type"`
+	Subject         string `json:"subject,omitempty"`
+	Time            string `json:"time"`
+	DataContentType string `json:"datacontenttype"`
+	Data            Event  `json:"data"`
+}
+
+// Marshal encodes the event in its envelope
+// The id ends with a hash of the whole event, its time and data included, so a consumer can drop an
+// event delivered twice while distinct events of a slot, or one emitted again after a reorg or a
+// restart, keep distinct ids
+func (c CloudEventsEncoder) Marshal(event Event) ([]byte, error) {
+	content, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(content)
+
+	envelope := cloudEvent{
+		SpecVersion:     "1.0",
+		ID:              fmt.Sprintf("%s-%d-%d-%d-%s", event.Type, event.Epoch, event.Slot, event.ValidatorIndex, hex.EncodeToString(sum[:8])),
+		Source:          c.Source,
+		Type:            CloudEventsTypePrefix + string(event.Type),
+		Time:            event.Time.UTC().Format(time.RFC3339Nano),
+		DataContentType: "application/json",
+		Data:            event,
+	}
+	if event.Pubkey != "" {
+		envelope.Subject = fmt.Sprintf("validator/%d", event.ValidatorIndex)
+	}
+	return json.Marshal(envelope)
+}
+
+// Delimit terminates the message with a newline (JSON lines)
+func (CloudEventsEncoder) Delimit(message []byte) []byte {
+	return append(message, '\n')
+}
+
+// ContentType returns the CloudEvents structured mode media type
+func (CloudEventsEncoder) ContentType() string {
+	return "application/cloudevents+json"
+}
+
+// ProtobufEncoder encodes events as the Event message of event.proto, length-delimited in a stream
+type ProtobufEncoder struct{}
+
+// Event message field numbers, see event.proto
+const (
+	protoFieldType protowire.Number = iota + 1
+	protoFieldTime
+	protoFieldSlot
+	protoFieldEpoch
+	protoFieldValidatorIndex
+	protoFieldPubkey
+	protoFieldLabel
+	protoFieldData
+)
+
+// Marshal encodes the event in the protobuf wire format
+func (ProtobufEncoder) Marshal(event Event) ([]byte, error) {
+	timestamp, err := proto.Marshal(timestamppb.New(event.Time))
+	if err != nil {
+		return nil, err
+	}
+
+	var b []byte
+	b = protowire.AppendTag(b, protoFieldType, protowire.BytesType)
+	b = protowire.AppendString(b, string(event.Type))
+	b = protowire.AppendTag(b, protoFieldTime, protowire.BytesType)
+	b = protowire.AppendBytes(b, timestamp)
+	b = appendVarintField(b, protoFieldSlot, uint64(event.Slot))
+	b = appendVarintField(b, protoFieldEpoch, uint64(event.Epoch))
+	b = appendVarintField(b, protoFieldValidatorIndex, uint64(event.ValidatorIndex))
+	b = appendStringField(b, protoFieldPubkey, event.Pubkey)
+	b = appendStringField(b, protoFieldLabel, event.Label)
+
+	if len(event.Data) > 0 {
+		data, err := protoStruct(event.Data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode event data: %w", err)
+		}
+		b = protowire.AppendTag(b, protoFieldData, protowire.BytesType)
+		b = protowire.AppendBytes(b, data)
+	}
+	return b, nil
+}
+
+// Delimit prefixes the message with its varint length
+func (ProtobufEncoder) Delimit(message []byte) []byte {
+	framed := protowire.AppendVarint(make([]byte, 0, len(message)+protowire.SizeVarint(uint64(len(message)))), uint64(len(message)))
+	return append(framed, message...)
+}
+
+// ContentType returns the protobuf media type
+func (ProtobufEncoder) ContentType() string {
+	return "application/x-protobuf"
+}
+
+// appendVarintField appends a non-zero varint field (proto3 omits default values)
+func appendVarintField(b []byte, number protowire.Number, value uint64) []byte {
+	if value == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, number, protowire.VarintType)
+	return protowire.AppendVarint(b, value)
+}
+
+// appendStringField appends a non-empty string field
+func appendStringField(b []byte, number protowire.Number, value string) []byte {
+	if value == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, number, protowire.BytesType)
+	return protowire.AppendString(b, value)
+}
+
+// protoStruct encodes event data as a google.protobuf.Struct
+// The data goes through JSON first, so values keep the form they have in the JSON formats
+func protoStruct(data map[string]interface{}) ([]byte, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	var generic map[string]interface{}
+	if err := json.Unmarshal(raw, &generic); err != nil {
+		return nil, err
+	}
+
+	value, err := structpb.NewStruct(generic)
+	if err != nil {
+		return nil, err
+	}
+	return proto.Marshal(value)
+}
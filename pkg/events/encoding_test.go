@@ -0,0 +1,143 @@
+package events
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+var testEvent = Event{
+	Type:           TypeMissedAttestation,
+	Time:           time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+	Slot:           320,
+	Epoch:          10,
+	ValidatorIndex: 42,
+	Pubkey:         "0xabc",
+	Label:          "operator:a",
+	Data:           map[string]interface{}{"consecutive_missed": uint64(3)},
+}
+
+func TestCloudEventsEncoder(t *testing.T) {
+	encoder, err := NewEncoder(FormatCloudEvents, "/eth-validator-watcher/mainnet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	message, err := encoder.Marshal(testEvent)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	var envelope map[string]interface{}
+	if err := json.Unmarshal(message, &envelope); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	want := map[string]interface{}{
+		"specversion": "1.0",
+		"source":      "/eth-validator-watcher/mainnet",
+		"type":        CloudEventsTypePrefix + "missed_attestation",
+		"subject":     "validator/42",
+		"time":        "2025-01-02T03:04:05Z",
+	}
+	for key, value := range want {
+		if envelope[key] != value {
+			t.Errorf("%s = %v, want %v", key, envelope[key], value)
+		}
+	}
+	id, _ := envelope["id"].(string)
+	if !strings.HasPrefix(id, "missed_attestation-10-320-42-") {
+		t.Errorf("id = %q, want the event type, epoch, slot and validator with a content hash", id)
+	}
+
+	// An event differing only in its data or time gets another id; the same event keeps it
+	again, _ := encoder.Marshal(testEvent)
+	other := testEvent
+	other.Data = map[string]interface{}{"consecutive_missed": uint64(4)}
+	changed, _ := encoder.Marshal(other)
+	if idOf(t, again) != id || idOf(t, changed) == id {
+		t.Errorf("Expected the id to follow the event content, got %q, %q and %q", id, idOf(t, again), idOf(t, changed))
+	}
+
+	if data, ok := envelope["data"].(map[string]interface{}); !ok || data["label"] != "operator:a" {
+		t.Errorf("data = %v, want the event", envelope["data"])
+	}
+	if framed := encoder.Delimit(message); framed[len(framed)-1] != '\n' {
+		t.Error("Delimit() did not terminate the line")
+	}
+}
+
+// idOf returns the id of a CloudEvents envelope
+func idOf(t *testing.T, message []byte) string {
+	t.Helper()
+	var envelope struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(message, &envelope); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	return envelope.ID
+}
+
+func TestProtobufEncoder(t *testing.T) {
+	encoder, err := NewEncoder(FormatProtobuf, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	message, err := encoder.Marshal(testEvent)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	strings := make(map[protowire.Number]string)
+	varints := make(map[protowire.Number]uint64)
+	var data structpb.Struct
+	for b := message; len(b) > 0; {
+		number, kind, n := protowire.ConsumeTag(b)
+		b = b[n:]
+		switch kind {
+		case protowire.VarintType:
+			value, n := protowire.ConsumeVarint(b)
+			varints[number] = value
+			b = b[n:]
+		case protowire.BytesType:
+			value, n := protowire.ConsumeBytes(b)
+			if number == protoFieldData {
+				if err := proto.Unmarshal(value, &data); err != nil {
+					t.Fatalf("Invalid data struct: %v", err)
+				}
+			}
+			strings[number] = string(value)
+			b = b[n:]
+		default:
+			t.Fatalf("Unexpected wire type %d", kind)
+		}
+		if n < 0 {
+			t.Fatal("Truncated message")
+		}
+	}
+
+	if strings[protoFieldType] != "missed_attestation" || strings[protoFieldLabel] != "operator:a" {
+		t.Errorf("Unexpected string fields: %v", strings)
+	}
+	if varints[protoFieldSlot] != 320 || varints[protoFieldEpoch] != 10 || varints[protoFieldValidatorIndex] != 42 {
+		t.Errorf("Unexpected varint fields: %v", varints)
+	}
+	if got := data.Fields["consecutive_missed"].GetNumberValue(); got != 3 {
+		t.Errorf("consecutive_missed = %v, want 3", got)
+	}
+
+	framed := encoder.Delimit(message)
+	if length, n := protowire.ConsumeVarint(framed); int(length) != len(message) || len(framed) != n+len(message) {
+		t.Errorf("Delimit() prefix = %d, want %d", length, len(message))
+	}
+}
+
+func TestNewEncoderUnknownFormat(t *testing.T) {
+	if _, err := NewEncoder("avro", ""); err == nil {
+		t.Error("NewEncoder() accepted an unknown format")
+	}
+}
@@ -0,0 +1,247 @@
+package events
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultBufferSize is the number of events that can be queued before new events are dropped
+	DefaultBufferSize = 10000
+)
+
+// Type identifies the kind of event
+type Type string
+
+const (
+	TypeMissedAttestation  Type = "missed_attestation"
+	TypeValidatorNotLive   Type = "validator_not_live"
+	TypeMissedBlock        Type = "missed_block"
+	TypeBlockProposed      Type = "block_proposed"
+	TypeWatchlistChanged   Type = "watchlist_changed"
+	TypeChainReorg         Type = "chain_reorg"
+	TypeSlashing           Type = "slashing"
+	TypeWrongFeeRecipient  Type = "wrong_fee_recipient"
+	TypeStatusChanged      Type = "status_changed"
+	TypeCredentialsChanged Type = "credentials_changed"
+)
+
+// Event represents a single validator-level occurrence with full detail
+type Event struct {
+	Type           Type                   `json:"type"`
+	Time           time.Time              `json:"time"`
+	Slot           models.Slot            `json:"slot"`
+	Epoch          models.Epoch           `json:"epoch"`
+	ValidatorIndex models.ValidatorIndex  `json:"validator_index"`
+	Pubkey         string                 `json:"pubkey,omitempty"`
+	Label          string                 `json:"label,omitempty"`
+	Data           map[string]interface{} `json:"data,omitempty"`
+}
+
+// Sink receives events from a stream
+type Sink interface {
+	Write(event Event) error
+	Close() error
+}
+
+// Stream dispatches events asynchronously to its sinks
+// Emit never blocks: when the buffer is full, events are dropped and counted
+type Stream struct {
+	mu       sync.RWMutex
+	sinks    []Sink
+	internal []Sink // Consumers inside the watcher, which get the events unmapped
+	queue    chan Event
+	done     chan struct{}
+	dropped  uint64
+	logger   *logrus.Logger
+	closed   bool
+	pubkeys  func(string) string                               // Maps pubkeys before delivery (anonymization), nil to keep them
+	indices  func(models.ValidatorIndex) models.ValidatorIndex // Maps validator indices before delivery, nil to keep them
+}
+
+// NewStream creates a new event stream and starts its dispatcher
+func NewStream(bufferSize int, logger *logrus.Logger) *Stream {
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+
+	s := &Stream{
+		queue:  make(chan Event, bufferSize),
+		done:   make(chan struct{}),
+		logger: logger,
+	}
+
+	go s.dispatch()
+	return s
+}
+
+// AddSink registers a sink that will receive all subsequent events
+func (s *Stream) AddSink(sink Sink) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sinks = append(s.sinks, sink)
+}
+
+// AddInternalSink registers a sink that acts on events inside the watcher; it gets the real
+// pubkeys and validator indices, as no event it receives leaves the watcher
+func (s *Stream) AddInternalSink(sink Sink) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.internal = append(s.internal, sink)
+}
+
+// SetPubkeyMapper sets a function applied to the pubkey of every subsequent event
+func (s *Stream) SetPubkeyMapper(fn func(string) string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pubkeys = fn
+}
+
+// SetIndexMapper sets a function applied to the validator index of every subsequent validator event
+func (s *Stream) SetIndexMapper(fn func(models.ValidatorIndex) models.ValidatorIndex) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.indices = fn
+}
+
+// Emit queues an event for delivery to all sinks
+func (s *Stream) Emit(event Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed || len(s.sinks)+len(s.internal) == 0 {
+		return
+	}
+
+	select {
+	case s.queue <- event:
+	default:
+		s.dropped++
+		// Warn on the first drop and then periodically to avoid adding to the log pressure
+		if s.dropped == 1 || s.dropped%1000 == 0 {
+			s.logger.WithField("dropped", s.dropped).Warn("Event stream buffer full, dropping events")
+		}
+	}
+}
+
+// Dropped returns the number of events dropped due to backpressure
+func (s *Stream) Dropped() uint64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.dropped
+}
+
+// Close flushes queued events and closes all sinks
+func (s *Stream) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.queue)
+	s.mu.Unlock()
+
+	<-s.done
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var firstErr error
+	for _, sink := range append(s.internal, s.sinks...) {
+		if err := sink.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// dispatch delivers queued events to sinks until the stream is closed
+func (s *Stream) dispatch() {
+	defer close(s.done)
+
+	for event := range s.queue {
+		s.mu.RLock()
+		sinks, internal, pubkeys, indices := s.sinks, s.internal, s.pubkeys, s.indices
+		s.mu.RUnlock()
+
+		for _, sink := range internal {
+			if err := sink.Write(event); err != nil {
+				s.logger.WithError(err).Debug("Failed to write event to sink")
+			}
+		}
+
+		// Only validator events carry a pubkey; the index of the others is unset rather than validator 0
+		if indices != nil && event.Pubkey != "" {
+			event.ValidatorIndex = indices(event.ValidatorIndex)
+		}
+		if pubkeys != nil {
+			event.Pubkey = pubkeys(event.Pubkey)
+		}
+		for _, sink := range sinks {
+			if err := sink.Write(event); err != nil {
+				s.logger.WithError(err).Debug("Failed to write event to sink")
+			}
+		}
+	}
+}
+
+// FileSink appends encoded events to a file
+type FileSink struct {
+	mu      sync.Mutex
+	file    *os.File
+	encoder Encoder
+}
+
+// NewFileSink opens (or creates) a file for appending events, JSON lines if encoder is nil
+func NewFileSink(path string, encoder Encoder) (*FileSink, error) {
+	if encoder == nil {
+		encoder = JSONEncoder{}
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open events file: %w", err)
+	}
+
+	return &FileSink{
+		file:    file,
+		encoder: encoder,
+	}, nil
+}
+
+// Write appends an event to the file
+func (f *FileSink) Write(event Event) error {
+	message, err := f.encoder.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	_, err = f.file.Write(f.encoder.Delimit(message))
+	return err
+}
+
+// Close closes the underlying file
+func (f *FileSink) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.file.Close()
+}
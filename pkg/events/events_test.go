@@ -0,0 +1,170 @@
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// memorySink collects events in memory for tests
+type memorySink struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (m *memorySink) Write(event Event) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = append(m.events, event)
+	return nil
+}
+
+func (m *memorySink) Close() error { return nil }
+
+func TestStreamDeliversEvents(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	stream := NewStream(10, logger)
+	sink := &memorySink{}
+	stream.AddSink(sink)
+
+	stream.Emit(Event{Type: TypeMissedAttestation, ValidatorIndex: 100})
+	stream.Emit(Event{Type: TypeMissedBlock, ValidatorIndex: 200})
+
+	if err := stream.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	if len(sink.events) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(sink.events))
+	}
+	if sink.events[0].ValidatorIndex != 100 {
+		t.Errorf("Expected first event for validator 100, got %d", sink.events[0].ValidatorIndex)
+	}
+	if sink.events[0].Time.IsZero() {
+		t.Error("Expected event time to be set")
+	}
+}
+
+func TestStreamPubkeyMapper(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	stream := NewStream(10, logger)
+	sink := &memorySink{}
+	stream.AddSink(sink)
+	stream.SetPubkeyMapper(func(pubkey string) string { return "anon-" + pubkey[2:4] })
+
+	stream.Emit(Event{Type: TypeMissedBlock, Pubkey: "0xab12"})
+	stream.Close()
+
+	if len(sink.events) != 1 || sink.events[0].Pubkey != "anon-ab" {
+		t.Errorf("Expected mapped pubkey, got %+v", sink.events)
+	}
+}
+
+func TestStreamIndexMapperSkipsInternalSinks(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	stream := NewStream(10, logger)
+	external, internal := &memorySink{}, &memorySink{}
+	stream.AddSink(external)
+	stream.AddInternalSink(internal)
+	stream.SetIndexMapper(func(index models.ValidatorIndex) models.ValidatorIndex { return index + 1000 })
+
+	stream.Emit(Event{Type: TypeMissedBlock, ValidatorIndex: 7, Pubkey: "0xab12"})
+	stream.Emit(Event{Type: TypeWatchlistChanged})
+	stream.Close()
+
+	if len(external.events) != 2 || external.events[0].ValidatorIndex != 1007 {
+		t.Fatalf("Expected the validator event's index mapped, got %+v", external.events)
+	}
+	if external.events[1].ValidatorIndex != 0 {
+		t.Errorf("Expected events without a validator left alone, got %d", external.events[1].ValidatorIndex)
+	}
+	if len(internal.events) != 2 || internal.events[0].ValidatorIndex != 7 {
+		t.Errorf("Expected internal sinks to get the real index, got %+v", internal.events)
+	}
+}
+
+func TestStreamEmitAfterClose(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	stream := NewStream(10, logger)
+	stream.AddSink(&memorySink{})
+	stream.Close()
+
+	// Must not panic
+	stream.Emit(Event{Type: TypeMissedAttestation})
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+
+	sink, err := NewFileSink(path, nil)
+	if err != nil {
+		t.Fatalf("NewFileSink failed: %v", err)
+	}
+
+	sink.Write(Event{Type: TypeValidatorNotLive, ValidatorIndex: 42, Epoch: 7})
+	sink.Write(Event{Type: TypeMissedBlock, ValidatorIndex: 43, Slot: 224})
+	sink.Close()
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open events file: %v", err)
+	}
+	defer file.Close()
+
+	var decoded []Event
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("Failed to decode event line: %v", err)
+		}
+		decoded = append(decoded, e)
+	}
+
+	if len(decoded) != 2 {
+		t.Fatalf("Expected 2 lines, got %d", len(decoded))
+	}
+	if decoded[0].ValidatorIndex != 42 || decoded[0].Epoch != 7 {
+		t.Errorf("Unexpected first event: %+v", decoded[0])
+	}
+}
+
+func TestSampler(t *testing.T) {
+	sampler := NewSampler(2)
+	sampler.Add("a")
+	sampler.Add("b")
+	sampler.Add("c")
+
+	if sampler.Count() != 3 {
+		t.Errorf("Expected count 3, got %d", sampler.Count())
+	}
+	if len(sampler.Examples()) != 2 {
+		t.Errorf("Expected 2 examples, got %d", len(sampler.Examples()))
+	}
+	if sampler.Omitted() != 1 {
+		t.Errorf("Expected 1 omitted, got %d", sampler.Omitted())
+	}
+
+	fields := logrus.Fields{}
+	sampler.AddFields(fields, "examples")
+	if fields["examples"] != "a; b" {
+		t.Errorf("Expected examples 'a; b', got %v", fields["examples"])
+	}
+	if fields["more"] != "+1 more" {
+		t.Errorf("Expected '+1 more', got %v", fields["more"])
+	}
+}
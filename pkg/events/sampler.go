@@ -0,0 +1,60 @@
+package events
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultMaxExamples is the default number of examples kept per sampled log line
+const DefaultMaxExamples = 5
+
+// Sampler aggregates repeated log details into N examples plus a total count
+// Full details are expected to go to the event stream instead of the log
+type Sampler struct {
+	maxExamples int
+	examples    []string
+	count       int
+}
+
+// NewSampler creates a sampler keeping at most maxExamples examples
+func NewSampler(maxExamples int) *Sampler {
+	if maxExamples < 0 {
+		maxExamples = 0
+	}
+	return &Sampler{maxExamples: maxExamples}
+}
+
+// Add records an occurrence, keeping its detail only while under the example limit
+func (s *Sampler) Add(example string) {
+	s.count++
+	if len(s.examples) < s.maxExamples {
+		s.examples = append(s.examples, example)
+	}
+}
+
+// Count returns the total number of occurrences recorded
+func (s *Sampler) Count() int {
+	return s.count
+}
+
+// Examples returns the sampled examples
+func (s *Sampler) Examples() []string {
+	return s.examples
+}
+
+// Omitted returns how many occurrences were counted but not kept as examples
+func (s *Sampler) Omitted() int {
+	return s.count - len(s.examples)
+}
+
+// AddFields adds the sampled examples and omitted count to log fields under the given key
+func (s *Sampler) AddFields(fields logrus.Fields, key string) {
+	if len(s.examples) > 0 {
+		fields[key] = strings.Join(s.examples, "; ")
+	}
+	if omitted := s.Omitted(); omitted > 0 {
+		fields["more"] = fmt.Sprintf("+%d more", omitted)
+	}
+}
@@ -0,0 +1,182 @@
+// Package explorer builds block explorer links to validators, slots, epochs and blocks
+package explorer
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// Placeholder is replaced by the validator index, slot, epoch or block root in explorer paths
+const Placeholder = "{id}"
+
+// NetworkPlaceholder is replaced by the network name in explorer URLs shared by several networks
+const NetworkPlaceholder = "{network}"
+
+// Paths are the pages of an explorer, each containing the placeholder
+type Paths struct {
+	Validator string
+	Slot      string
+	Epoch     string
+	Block     string // Linked by block root
+}
+
+// DefaultPaths are the pages of beaconcha.in and the explorers built on it
+var DefaultPaths = Paths{
+	Validator: "/validator/" + Placeholder,
+	Slot:      "/slot/" + Placeholder,
+	Epoch:     "/epoch/" + Placeholder,
+	Block:     "/slot/" + Placeholder,
+}
+
+// defaultURLs are the explorers of the public networks
+var defaultURLs = map[string]string{
+	"mainnet": "https://beaconcha.in",
+	"holesky": "https://holesky.beaconcha.in",
+	"hoodi":   "https://hoodi.beaconcha.in",
+	"sepolia": "https://sepolia.beaconcha.in",
+	"gnosis":  "https://gnosischa.in",
+}
+
+// DefaultURL returns the explorer of a public network, empty for others
+func DefaultURL(network string) string {
+	return defaultURLs[network]
+}
+
+// Explorer links to the pages of one block explorer
+// A nil Explorer links nothing, so callers don't need to check whether one is configured
+type Explorer struct {
+	base  string
+	paths Paths
+}
+
+// New creates an explorer from its base URL, nil if empty; empty paths take the default
+func New(baseURL string, paths Paths) *Explorer {
+	if baseURL == "" {
+		return nil
+	}
+	if paths.Validator == "" {
+		paths.Validator = DefaultPaths.Validator
+	}
+	if paths.Slot == "" {
+		paths.Slot = DefaultPaths.Slot
+	}
+	if paths.Epoch == "" {
+		paths.Epoch = DefaultPaths.Epoch
+	}
+	if paths.Block == "" {
+		paths.Block = DefaultPaths.Block
+	}
+	return &Explorer{base: strings.TrimRight(baseURL, "/"), paths: paths}
+}
+
+// ValidatePath checks that a configured path can be linked
+func ValidatePath(path string) error {
+	if !strings.HasPrefix(path, "/") {
+		return fmt.Errorf("%q must start with /", path)
+	}
+	if !strings.Contains(path, Placeholder) {
+		return fmt.Errorf("%q must contain %s", path, Placeholder)
+	}
+	return nil
+}
+
+// Validator returns the page of a validator
+func (e *Explorer) Validator(index models.ValidatorIndex) string {
+	if e == nil {
+		return ""
+	}
+	return e.link(e.paths.Validator, strconv.FormatUint(uint64(index), 10))
+}
+
+// Slot returns the page of a slot
+func (e *Explorer) Slot(slot models.Slot) string {
+	if e == nil {
+		return ""
+	}
+	return e.link(e.paths.Slot, strconv.FormatUint(uint64(slot), 10))
+}
+
+// Epoch returns the page of an epoch
+func (e *Explorer) Epoch(epoch models.Epoch) string {
+	if e == nil {
+		return ""
+	}
+	return e.link(e.paths.Epoch, strconv.FormatUint(uint64(epoch), 10))
+}
+
+// Block returns the page of a block by its root
+func (e *Explorer) Block(root string) string {
+	if e == nil || root == "" {
+		return ""
+	}
+	return e.link(e.paths.Block, root)
+}
+
+func (e *Explorer) link(path, id string) string {
+	return e.base + strings.Replace(path, Placeholder, url.PathEscape(id), 1)
+}
+
+// FieldLinks returns the pages of the alert or log fields naming a validator, slot, epoch or block, by field key
+func (e *Explorer) FieldLinks(fields map[string]string) map[string]string {
+	if e == nil {
+		return nil
+	}
+
+	links := make(map[string]string)
+	for key, value := range fields {
+		number, err := strconv.ParseUint(value, 10, 64)
+		isNumber := err == nil
+		switch {
+		case (key == "validator" || key == "validator_index") && isNumber:
+			links[key] = e.Validator(models.ValidatorIndex(number))
+		case (key == "slot" || strings.HasSuffix(key, "_slot")) && isNumber:
+			links[key] = e.Slot(models.Slot(number))
+		case key == "epoch" && isNumber:
+			links[key] = e.Epoch(models.Epoch(number))
+		case key == "block_root" && strings.HasPrefix(value, "0x"):
+			links[key] = e.Block(value)
+		}
+	}
+	if len(links) == 0 {
+		return nil
+	}
+	return links
+}
+
+// LinkSuffix is appended to a field's key for the field holding its explorer link
+const LinkSuffix = "_url"
+
+// LogHook adds explorer links to the warnings and errors naming a validator, slot, epoch or block,
+// as <field>_url, so a log line leads straight to the page to look at
+type LogHook struct {
+	explorer *Explorer
+}
+
+// NewLogHook creates a hook linking to an explorer's pages
+func NewLogHook(e *Explorer) *LogHook {
+	return &LogHook{explorer: e}
+}
+
+// Levels returns the levels linked; routine info and debug lines are left as they are
+func (h *LogHook) Levels() []logrus.Level {
+	return []logrus.Level{logrus.PanicLevel, logrus.FatalLevel, logrus.ErrorLevel, logrus.WarnLevel}
+}
+
+// Fire adds the links of the entry's fields, keeping fields already set
+func (h *LogHook) Fire(entry *logrus.Entry) error {
+	fields := make(map[string]string, len(entry.Data))
+	for key, value := range entry.Data {
+		fields[key] = fmt.Sprint(value)
+	}
+	for key, link := range h.explorer.FieldLinks(fields) {
+		if _, ok := entry.Data[key+LinkSuffix]; !ok {
+			entry.Data[key+LinkSuffix] = link
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package explorer
+
+import (
+	"testing"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+func TestLinks(t *testing.T) {
+	if e := New("", DefaultPaths); e != nil || e.Validator(1) != "" || e.FieldLinks(map[string]string{"validator": "1"}) != nil {
+		t.Error("Expected no links without an explorer URL")
+	}
+
+	e := New(DefaultURL("mainnet")+"/", Paths{Validator: "/v/{id}/overview"})
+	if got := e.Validator(42); got != "https://beaconcha.in/v/42/overview" {
+		t.Errorf("Validator() = %s", got)
+	}
+	if got := e.Slot(100); got != "https://beaconcha.in/slot/100" {
+		t.Errorf("Slot() = %s, expected the default path", got)
+	}
+
+	links := e.FieldLinks(map[string]string{
+		"validator":    "42",
+		"offence_slot": "99",
+		"epoch":        "3",
+		"block_root":   "0xabc",
+		"label":        "operator:a",
+		"slot":         "-",
+	})
+	expected := map[string]string{
+		"validator":    "https://beaconcha.in/v/42/overview",
+		"offence_slot": "https://beaconcha.in/slot/99",
+		"epoch":        "https://beaconcha.in/epoch/3",
+		"block_root":   "https://beaconcha.in/slot/0xabc",
+	}
+	if len(links) != len(expected) {
+		t.Errorf("FieldLinks() = %v", links)
+	}
+	for key, url := range expected {
+		if links[key] != url {
+			t.Errorf("FieldLinks()[%s] = %s, want %s", key, links[key], url)
+		}
+	}
+}
+
+func TestValidatePath(t *testing.T) {
+	for path, valid := range map[string]bool{
+		"/validator/{id}": true,
+		"validator/{id}":  false,
+		"/validator/":     false,
+	} {
+		if err := ValidatePath(path); (err == nil) != valid {
+			t.Errorf("ValidatePath(%q) = %v", path, err)
+		}
+	}
+}
+
+func TestLogHook(t *testing.T) {
+	hook := NewLogHook(New(DefaultURL("hoodi"), DefaultPaths))
+	entry := logrus.WithFields(logrus.Fields{
+		"validator_index": models.ValidatorIndex(7),
+		"slot":            models.Slot(64),
+		"epoch_url":       "kept",
+		"epoch":           2,
+	})
+	if err := hook.Fire(entry); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := map[string]string{
+		"validator_index_url": "https://hoodi.beaconcha.in/validator/7",
+		"slot_url":            "https://hoodi.beaconcha.in/slot/64",
+		"epoch_url":           "kept",
+	}
+	for key, url := range expected {
+		if entry.Data[key] != url {
+			t.Errorf("Data[%s] = %v, want %s", key, entry.Data[key], url)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+// Package export appends one row per watched validator and epoch to daily CSV files, for offline
+// analysis without a time series database
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// FormatCSV is the only export format; Parquet needs a dependency the watcher doesn't carry, and
+// the CSV files convert with e.g. duckdb's COPY ... TO 'file.parquet'
+const FormatCSV = "csv"
+
+// header names the columns of every file
+// Columns settle on different schedules, so each group names what it covers: attestation duties and
+// liveness misses belong to epoch, rewards to rewards_epoch and blocks to the processed slots from
+// blocks_from_slot to blocks_to_slot
+var header = []string{
+	"time", "epoch", "validator_index", "pubkey", "label", "status",
+	"balance_gwei", "effective_balance_gwei",
+	"attestation_duties", "missed_attestation_duties", "missed_attestations",
+	"suboptimal_source_votes", "suboptimal_target_votes", "suboptimal_head_votes", "inclusion_delay_avg",
+	"ideal_consensus_rewards_gwei", "consensus_rewards_gwei",
+	"proposed_blocks", "missed_blocks",
+	"rewards_epoch", "blocks_from_slot", "blocks_to_slot",
+}
+
+// Counters are a validator's cumulative duty counters; rows hold what changed since the previous one
+type Counters struct {
+	AttestationDuties        uint64
+	AttestationDutiesSuccess uint64
+	MissedAttestations       uint64 // Liveness misses
+	SuboptimalSourceVotes    uint64
+	SuboptimalTargetVotes    uint64
+	SuboptimalHeadVotes      uint64
+	InclusionDelaySum        uint64
+	InclusionDelayCount      uint64
+	ProposedBlocks           uint64
+	MissedBlocks             uint64
+}
+
+// sub returns the counters gained since prev; a counter that went down was zeroed meanwhile,
+// e.g. for a validator that stopped being watched and came back, so all of it is new
+func (c Counters) sub(prev Counters) Counters {
+	gained := func(cur, prev uint64) uint64 {
+		if cur < prev {
+			return cur
+		}
+		return cur - prev
+	}
+	return Counters{
+		AttestationDuties:        gained(c.AttestationDuties, prev.AttestationDuties),
+		AttestationDutiesSuccess: gained(c.AttestationDutiesSuccess, prev.AttestationDutiesSuccess),
+		MissedAttestations:       gained(c.MissedAttestations, prev.MissedAttestations),
+		SuboptimalSourceVotes:    gained(c.SuboptimalSourceVotes, prev.SuboptimalSourceVotes),
+		SuboptimalTargetVotes:    gained(c.SuboptimalTargetVotes, prev.SuboptimalTargetVotes),
+		SuboptimalHeadVotes:      gained(c.SuboptimalHeadVotes, prev.SuboptimalHeadVotes),
+		InclusionDelaySum:        gained(c.InclusionDelaySum, prev.InclusionDelaySum),
+		InclusionDelayCount:      gained(c.InclusionDelayCount, prev.InclusionDelayCount),
+		ProposedBlocks:           gained(c.ProposedBlocks, prev.ProposedBlocks),
+		MissedBlocks:             gained(c.MissedBlocks, prev.MissedBlocks),
+	}
+}
+
+// Row is a watched validator once an epoch settled, with its cumulative counters and the rewards
+// of a single epoch, which are written as they are
+type Row struct {
+	Epoch                 models.Epoch // Epoch of the attestation duties
+	RewardsEpoch          models.Epoch // Epoch of the rewards, which are fetched on their own schedule
+	BlocksFrom            models.Slot  // First processed slot of the blocks
+	BlocksTo              models.Slot  // Last processed slot of the blocks
+	Index                 models.ValidatorIndex
+	Pubkey                string
+	Label                 string
+	Status                models.ValidatorStatus
+	Balance               models.Gwei
+	EffectiveBalance      models.Gwei
+	IdealConsensusRewards models.Gwei
+	ConsensusRewards      models.SignedGwei
+	Counters              Counters
+}
+
+// record formats a row with the counters gained since the previous one
+func (r Row) record(at time.Time, gained Counters) []string {
+	delay := ""
+	if gained.InclusionDelayCount > 0 {
+		delay = strconv.FormatFloat(float64(gained.InclusionDelaySum)/float64(gained.InclusionDelayCount), 'f', 3, 64)
+	}
+	u := func(v uint64) string { return strconv.FormatUint(v, 10) }
+	return []string{
+		at.UTC().Format(time.RFC3339), u(uint64(r.Epoch)), u(uint64(r.Index)), r.Pubkey, r.Label, string(r.Status),
+		u(uint64(r.Balance)), u(uint64(r.EffectiveBalance)),
+		u(gained.AttestationDuties), u(gained.AttestationDuties - gained.AttestationDutiesSuccess), u(gained.MissedAttestations),
+		u(gained.SuboptimalSourceVotes), u(gained.SuboptimalTargetVotes), u(gained.SuboptimalHeadVotes), delay,
+		u(uint64(r.IdealConsensusRewards)), strconv.FormatInt(int64(r.ConsensusRewards), 10),
+		u(gained.ProposedBlocks), u(gained.MissedBlocks),
+		u(uint64(r.RewardsEpoch)), u(uint64(r.BlocksFrom)), u(uint64(r.BlocksTo)),
+	}
+}
+
+// Exporter appends epoch rows to a CSV file per UTC day, <dir>/<network>-validators-<date>.csv,
+// deleting the files older than the retention
+type Exporter struct {
+	dir           string
+	prefix        string
+	retentionDays int // 0 keeps every file
+
+	mu       sync.Mutex
+	previous map[models.ValidatorIndex]Counters // Counters of each validator's last row since the last rebase
+}
+
+// New creates an exporter writing into dir, which is created if missing
+func New(dir, network string, retentionDays int) (*Exporter, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create export directory: %w", err)
+	}
+	return &Exporter{
+		dir:           dir,
+		prefix:        network + "-validators-",
+		retentionDays: retentionDays,
+		previous:      make(map[models.ValidatorIndex]Counters),
+	}, nil
+}
+
+// Rebase forgets the counters of the previous rows, for when the watcher zeroes its counters:
+// the next rows hold everything counted since
+func (e *Exporter) Rebase() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.previous = make(map[models.ValidatorIndex]Counters)
+}
+
+// Write appends the rows of an epoch that started at the given time to that day's file
+func (e *Exporter) Write(at time.Time, rows []Row) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	day := at.UTC().Format(time.DateOnly)
+	path := filepath.Join(e.dir, e.prefix+day+".csv")
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open export file: %w", err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat export file: %w", err)
+	}
+
+	buffered := bufio.NewWriter(file)
+	out := csv.NewWriter(buffered)
+	if info.Size() == 0 {
+		out.Write(header)
+	}
+	for _, row := range rows {
+		out.Write(row.record(at, row.Counters.sub(e.previous[row.Index])))
+		e.previous[row.Index] = row.Counters
+	}
+	out.Flush()
+	if err := out.Error(); err != nil {
+		return fmt.Errorf("failed to write export file: %w", err)
+	}
+	if err := buffered.Flush(); err != nil {
+		return fmt.Errorf("failed to write export file: %w", err)
+	}
+
+	return e.prune(day)
+}
+
+// prune deletes the daily files older than the retention, counting back from day
+func (e *Exporter) prune(day string) error {
+	if e.retentionDays <= 0 {
+		return nil
+	}
+	entries, err := os.ReadDir(e.dir)
+	if err != nil {
+		return fmt.Errorf("failed to list export directory: %w", err)
+	}
+
+	var days []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if d, ok := strings.CutPrefix(name, e.prefix); ok && strings.HasSuffix(d, ".csv") {
+			days = append(days, strings.TrimSuffix(d, ".csv"))
+		}
+	}
+	sort.Strings(days)
+
+	today, err := time.Parse(time.DateOnly, day)
+	if err != nil {
+		return err
+	}
+	oldest := today.AddDate(0, 0, 1-e.retentionDays).Format(time.DateOnly)
+	for _, d := range days {
+		if d >= oldest {
+			break
+		}
+		if err := os.Remove(filepath.Join(e.dir, e.prefix+d+".csv")); err != nil {
+			return fmt.Errorf("failed to delete old export file: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package export
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// readRows reads a CSV file, header included
+func readRows(t *testing.T, path string) [][]string {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open %s: %v", path, err)
+	}
+	defer file.Close()
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", path, err)
+	}
+	return records
+}
+
+func TestWriteEpochDeltas(t *testing.T) {
+	dir := t.TempDir()
+	e, err := New(dir, "hoodi", 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	day := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	row := Row{Epoch: 10, RewardsEpoch: 9, BlocksFrom: 368, BlocksTo: 399, Index: 7, Pubkey: "0xabc", Label: "operator:a", Status: "active_ongoing", Balance: 32_000_000_000}
+	row.IdealConsensusRewards, row.ConsensusRewards = 10, 10
+	row.Counters = Counters{AttestationDuties: 1, AttestationDutiesSuccess: 1, InclusionDelaySum: 1, InclusionDelayCount: 1}
+	if err := e.Write(day, []Row{row}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	row.Epoch = 11
+	row.IdealConsensusRewards, row.ConsensusRewards = 20, -5
+	row.Counters = Counters{AttestationDuties: 2, AttestationDutiesSuccess: 1, InclusionDelaySum: 1, InclusionDelayCount: 1}
+	if err := e.Write(day.Add(6*time.Minute), []Row{row}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// After a rebase the counters start from zero again
+	e.Rebase()
+	row.Epoch = 12
+	row.IdealConsensusRewards, row.ConsensusRewards = 0, 0
+	row.Counters = Counters{AttestationDuties: 1, AttestationDutiesSuccess: 1, InclusionDelaySum: 2, InclusionDelayCount: 1}
+	if err := e.Write(day.Add(12*time.Minute), []Row{row}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	records := readRows(t, filepath.Join(dir, "hoodi-validators-2025-03-01.csv"))
+	if len(records) != 4 || records[0][0] != "time" {
+		t.Fatalf("Expected a header and 3 rows, got %v", records)
+	}
+	column := func(name string) int {
+		for i, h := range header {
+			if h == name {
+				return i
+			}
+		}
+		t.Fatalf("No column %s", name)
+		return -1
+	}
+	expected := []map[string]string{
+		{"epoch": "10", "rewards_epoch": "9", "blocks_from_slot": "368", "blocks_to_slot": "399", "attestation_duties": "1", "missed_attestation_duties": "0", "inclusion_delay_avg": "1.000", "consensus_rewards_gwei": "10"},
+		{"epoch": "11", "attestation_duties": "1", "missed_attestation_duties": "1", "inclusion_delay_avg": "", "consensus_rewards_gwei": "-5"},
+		{"epoch": "12", "attestation_duties": "1", "missed_attestation_duties": "0", "inclusion_delay_avg": "2.000", "consensus_rewards_gwei": "0"},
+	}
+	for i, want := range expected {
+		for name, value := range want {
+			if got := records[i+1][column(name)]; got != value {
+				t.Errorf("Row %d %s = %q, want %q", i, name, got, value)
+			}
+		}
+	}
+}
+
+func TestWriteUnderDailyResets(t *testing.T) {
+	dir := t.TempDir()
+	e, err := New(dir, "hoodi", 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	day := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// Counters accumulate over the day while rewards are the last epoch's; a lower ideal reward
+	// than the epoch before is not a reset, and a counter zeroed alone doesn't resend the others
+	writes := []struct {
+		counters      Counters
+		ideal, actual int64
+	}{
+		{Counters{AttestationDuties: 1, AttestationDutiesSuccess: 1, ProposedBlocks: 1}, 30, 30},
+		{Counters{AttestationDuties: 2, AttestationDutiesSuccess: 2, ProposedBlocks: 1}, 20, 18},
+		{Counters{AttestationDuties: 3, AttestationDutiesSuccess: 2, ProposedBlocks: 0}, 25, -4},
+	}
+	for i, write := range writes {
+		row := Row{Epoch: models.Epoch(10 + i), Index: 7, IdealConsensusRewards: models.Gwei(write.ideal), ConsensusRewards: models.SignedGwei(write.actual), Counters: write.counters}
+		if err := e.Write(day.Add(time.Duration(i)*6*time.Minute), []Row{row}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	records := readRows(t, filepath.Join(dir, "hoodi-validators-2025-03-01.csv"))
+	if len(records) != 4 {
+		t.Fatalf("Expected a header and 3 rows, got %v", records)
+	}
+	column := func(name string) int {
+		for i, h := range header {
+			if h == name {
+				return i
+			}
+		}
+		t.Fatalf("No column %s", name)
+		return -1
+	}
+	expected := []map[string]string{
+		{"attestation_duties": "1", "missed_attestation_duties": "0", "proposed_blocks": "1", "ideal_consensus_rewards_gwei": "30", "consensus_rewards_gwei": "30"},
+		{"attestation_duties": "1", "missed_attestation_duties": "0", "proposed_blocks": "0", "ideal_consensus_rewards_gwei": "20", "consensus_rewards_gwei": "18"},
+		{"attestation_duties": "1", "missed_attestation_duties": "1", "proposed_blocks": "0", "ideal_consensus_rewards_gwei": "25", "consensus_rewards_gwei": "-4"},
+	}
+	for i, want := range expected {
+		for name, value := range want {
+			if got := records[i+1][column(name)]; got != value {
+				t.Errorf("Row %d %s = %q, want %q", i, name, got, value)
+			}
+		}
+	}
+}
+
+func TestWriteRotatesDaily(t *testing.T) {
+	dir := t.TempDir()
+	e, err := New(dir, "mainnet", 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	for day := 0; day < 4; day++ {
+		rows := []Row{{Epoch: models.Epoch(day * 225), Index: 1}, {Epoch: models.Epoch(day * 225), Index: 2}}
+		if err := e.Write(start.AddDate(0, 0, day), rows); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Name() != "mainnet-validators-2025-03-03.csv" || entries[1].Name() != "mainnet-validators-2025-03-04.csv" {
+		t.Fatalf("Expected the last 2 days kept, got %v", entries)
+	}
+	if records := readRows(t, filepath.Join(dir, entries[1].Name())); len(records) != 3 {
+		t.Errorf("Expected each file to start with its own header, got %v", records)
+	}
+}
@@ -0,0 +1,123 @@
+package federation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/api"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/refresh"
+	"github.com/sirupsen/logrus"
+)
+
+// summariesPath is the peer API endpoint returning the summary of every label
+const summariesPath = "/api/v1/summaries"
+
+// DefaultName is this instance's shard name when none is configured
+const DefaultName = "local"
+
+// peer is a federated watcher and its last fetched label summaries
+type peer struct {
+	name      string
+	url       string // Redacted, as shown in the federated view
+	summaries *refresh.Refresher[[]api.LabelSummary]
+}
+
+// Client pulls the label summaries of peer watchers in the background
+type Client struct {
+	peers  []peer
+	client *http.Client
+}
+
+// NewClient creates a client for the configured peers, refetching each of them every interval
+func NewClient(peers []models.FederationPeer, interval, timeout time.Duration, logger *logrus.Logger) *Client {
+	c := &Client{client: &http.Client{Timeout: timeout}}
+	for _, p := range peers {
+		base := strings.TrimRight(p.URL, "/")
+		c.peers = append(c.peers, peer{
+			name: Name(p),
+			url:  redactURL(base),
+			summaries: refresh.New("federation_"+Name(p), interval, func(ctx context.Context) ([]api.LabelSummary, error) {
+				return c.fetch(ctx, base)
+			}, logger),
+		})
+	}
+	return c
+}
+
+// Name returns the peer's configured name, or its host if none is set
+func Name(p models.FederationPeer) string {
+	if p.Name != "" {
+		return p.Name
+	}
+	if u, err := url.Parse(p.URL); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return p.URL
+}
+
+// redactURL drops credentials from a URL
+func redactURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.User == nil {
+		return rawURL
+	}
+	parsed.User = nil
+	return parsed.String()
+}
+
+// Start fetches every peer until ctx is cancelled
+func (c *Client) Start(ctx context.Context) {
+	for _, p := range c.peers {
+		p.summaries.Start(ctx)
+	}
+}
+
+// Shards returns every peer's last fetched summaries and fetch status
+// A peer that fails keeps contributing its last good summaries, with the error reported
+func (c *Client) Shards() []api.Shard {
+	shards := make([]api.Shard, 0, len(c.peers))
+	for _, p := range c.peers {
+		shard := api.Shard{Name: p.name, URL: p.url}
+		shard.Summaries, shard.UpdatedAt, _ = p.summaries.Value()
+		if err := p.summaries.Err(); err != nil {
+			shard.Error = err.Error()
+		}
+		shards = append(shards, shard)
+	}
+	return shards
+}
+
+// fetch gets a peer's label summaries
+func (c *Client) fetch(ctx context.Context, base string) ([]api.LabelSummary, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, base+summariesPath, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set(api.VersionHeader, api.Version)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var body struct {
+		Data []api.LabelSummary `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode summaries: %w", err)
+	}
+	return body.Data, nil
+}
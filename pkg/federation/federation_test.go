@@ -0,0 +1,51 @@
+package federation
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/api"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+func TestShards(t *testing.T) {
+	peer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != summariesPath || r.Header.Get(api.VersionHeader) != api.Version {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"data":[{"label":"operator:a","validators":3}]}`))
+	}))
+	defer peer.Close()
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "down", http.StatusBadGateway)
+	}))
+	defer broken.Close()
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	brokenURL := strings.Replace(broken.URL, "http://", "http://user:secret@", 1)
+	client := NewClient([]models.FederationPeer{
+		{Name: "shard-2", URL: peer.URL + "/"},
+		{URL: brokenURL},
+	}, time.Minute, time.Second, logger)
+	for _, p := range client.peers {
+		p.summaries.Refresh(context.Background())
+	}
+
+	shards := client.Shards()
+	if len(shards) != 2 {
+		t.Fatalf("Expected 2 shards, got %d", len(shards))
+	}
+	if got := shards[0]; got.Name != "shard-2" || got.Error != "" || len(got.Summaries) != 1 || got.Summaries[0].Validators != 3 {
+		t.Errorf("Unexpected shard: %+v", got)
+	}
+	if got := shards[1]; got.Error == "" || got.Summaries != nil || strings.Contains(got.URL, "secret") || strings.Contains(got.Name, "secret") {
+		t.Errorf("Unexpected failed shard: %+v", got)
+	}
+}
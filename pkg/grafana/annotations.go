@@ -0,0 +1,304 @@
+// Package grafana publishes watcher events as Grafana annotations, so dashboards show proposals,
+// misses and the watcher's own state changes on their time series, and generates a dashboard of
+// the exported metrics
+package grafana
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/events"
+	"github.com/sirupsen/logrus"
+)
+
+// annotationsPath is the Grafana HTTP API endpoint creating annotations
+const annotationsPath = "/api/annotations"
+
+// queueSize is the number of annotations waiting to be posted before new ones are dropped
+const queueSize = 256
+
+// Tag is added to every annotation, so dashboards can query the watcher's annotations alone
+const Tag = "eth-validator-watcher"
+
+// TypeDegradation annotates the watcher shedding or restoring optional work; it isn't an event
+// stream type since it concerns the watcher rather than a validator
+const TypeDegradation = "degradation"
+
+// DefaultEvents are annotated when no event types are configured: missed attestations and liveness
+// are left out since a bad epoch would add one annotation per validator
+var DefaultEvents = []string{
+	string(events.TypeBlockProposed),
+	string(events.TypeMissedBlock),
+	string(events.TypeSlashing),
+	string(events.TypeChainReorg),
+	string(events.TypeWrongFeeRecipient),
+	string(events.TypeWatchlistChanged),
+	string(events.TypeStatusChanged),
+	string(events.TypeCredentialsChanged),
+	TypeDegradation,
+}
+
+// knownEvents is every type that can be annotated
+var knownEvents = map[string]bool{
+	string(events.TypeMissedAttestation):  true,
+	string(events.TypeValidatorNotLive):   true,
+	string(events.TypeMissedBlock):        true,
+	string(events.TypeBlockProposed):      true,
+	string(events.TypeWatchlistChanged):   true,
+	string(events.TypeChainReorg):         true,
+	string(events.TypeSlashing):           true,
+	string(events.TypeWrongFeeRecipient):  true,
+	string(events.TypeStatusChanged):      true,
+	string(events.TypeCredentialsChanged): true,
+	TypeDegradation:                       true,
+}
+
+// ValidateEvents checks that every configured type can be annotated
+func ValidateEvents(types []string) error {
+	for _, t := range types {
+		if !knownEvents[t] {
+			return fmt.Errorf("unknown event type %q", t)
+		}
+	}
+	return nil
+}
+
+// Annotation is a point in time marked on dashboards
+type Annotation struct {
+	Time time.Time
+	Text string
+	Tags []string
+}
+
+// annotationRequest is the body of POST /api/annotations
+type annotationRequest struct {
+	DashboardUID string   `json:"dashboardUID,omitempty"`
+	Time         int64    `json:"time"` // Milliseconds since the epoch
+	Tags         []string `json:"tags"`
+	Text         string   `json:"text"`
+}
+
+// Publisher posts annotations to Grafana in the background
+// Annotate never blocks: when Grafana is slow or down, annotations are dropped rather than delaying
+// slot processing or the other event sinks
+type Publisher struct {
+	url        string
+	apiKey     string
+	dashboards []string // Empty for organization annotations, shown on dashboards querying by tag
+	tags       []string // Added to every annotation
+	events     map[string]bool
+	httpClient *http.Client
+	logger     *logrus.Logger
+
+	mu      sync.Mutex
+	queue   chan Annotation
+	done    chan struct{}
+	closed  bool
+	dropped uint64
+}
+
+// NewPublisher creates a publisher for a Grafana instance and starts posting
+func NewPublisher(baseURL, apiKey string, dashboards, tags, eventTypes []string, timeout time.Duration, logger *logrus.Logger) *Publisher {
+	if len(eventTypes) == 0 {
+		eventTypes = DefaultEvents
+	}
+	enabled := make(map[string]bool, len(eventTypes))
+	for _, t := range eventTypes {
+		enabled[t] = true
+	}
+
+	p := &Publisher{
+		url:        strings.TrimRight(baseURL, "/") + annotationsPath,
+		apiKey:     apiKey,
+		dashboards: dashboards,
+		tags:       append([]string{Tag}, tags...),
+		events:     enabled,
+		httpClient: &http.Client{Timeout: timeout},
+		logger:     logger,
+		queue:      make(chan Annotation, queueSize),
+		done:       make(chan struct{}),
+	}
+	go p.run()
+	return p
+}
+
+// Enabled reports whether a type is annotated; nil-safe so callers can skip an unconfigured publisher
+func (p *Publisher) Enabled(eventType string) bool {
+	return p != nil && p.events[eventType]
+}
+
+// Annotate queues an annotation, with the publisher's tags added
+func (p *Publisher) Annotate(a Annotation) {
+	if p == nil {
+		return
+	}
+	if a.Time.IsZero() {
+		a.Time = time.Now()
+	}
+	a.Tags = append(append([]string{}, p.tags...), a.Tags...)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return
+	}
+	select {
+	case p.queue <- a:
+	default:
+		p.dropped++
+		if p.dropped == 1 || p.dropped%100 == 0 {
+			p.logger.WithField("dropped", p.dropped).Warn("Grafana annotation queue full, dropping annotations")
+		}
+	}
+}
+
+// Write annotates an event of an enabled type, so the publisher can be added to an event stream
+func (p *Publisher) Write(event events.Event) error {
+	if !p.Enabled(string(event.Type)) {
+		return nil
+	}
+
+	tags := []string{string(event.Type)}
+	if event.Label != "" {
+		tags = append(tags, event.Label)
+	}
+	p.Annotate(Annotation{Time: event.Time, Text: EventText(event), Tags: tags})
+	return nil
+}
+
+// Close posts the queued annotations and stops the publisher
+func (p *Publisher) Close() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	close(p.queue)
+	p.mu.Unlock()
+
+	<-p.done
+	return nil
+}
+
+// run posts queued annotations until the publisher is closed
+func (p *Publisher) run() {
+	defer close(p.done)
+
+	for a := range p.queue {
+		ctx, cancel := context.WithTimeout(context.Background(), p.httpClient.Timeout)
+		if err := p.post(ctx, a); err != nil {
+			p.logger.WithError(err).Debug("Failed to post Grafana annotation")
+		}
+		cancel()
+	}
+}
+
+// post creates the annotation on every configured dashboard, or once for the organization
+func (p *Publisher) post(ctx context.Context, a Annotation) error {
+	dashboards := p.dashboards
+	if len(dashboards) == 0 {
+		dashboards = []string{""}
+	}
+
+	for _, uid := range dashboards {
+		body, err := json.Marshal(annotationRequest{
+			DashboardUID: uid,
+			Time:         a.Time.UnixMilli(),
+			Tags:         a.Tags,
+			Text:         a.Text,
+		})
+		if err != nil {
+			return err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if p.apiKey != "" {
+			req.Header.Set("Authorization", "Bearer "+p.apiKey)
+		}
+
+		resp, err := p.httpClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("failed to post annotation: %w", err)
+		}
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("grafana returned %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+		}
+	}
+	return nil
+}
+
+// eventTitles are the annotation headlines of event types
+var eventTitles = map[events.Type]string{
+	events.TypeMissedAttestation:  "Missed attestation",
+	events.TypeValidatorNotLive:   "Validator not live",
+	events.TypeMissedBlock:        "Missed block",
+	events.TypeBlockProposed:      "Block proposed",
+	events.TypeWatchlistChanged:   "Watchlist changed",
+	events.TypeChainReorg:         "Chain reorg",
+	events.TypeSlashing:           "Validator slashed",
+	events.TypeWrongFeeRecipient:  "Wrong fee recipient",
+	events.TypeStatusChanged:      "Validator status changed",
+	events.TypeCredentialsChanged: "Withdrawal credentials changed",
+}
+
+// EventText describes an event in one line: its headline, validator, slot or epoch, label and the
+// scalar fields of its data; lists are summarized by their length
+func EventText(event events.Event) string {
+	title, ok := eventTitles[event.Type]
+	if !ok {
+		title = string(event.Type)
+	}
+	parts := []string{title}
+
+	if event.ValidatorIndex != 0 || event.Pubkey != "" {
+		parts = append(parts, fmt.Sprintf("validator %d", event.ValidatorIndex))
+	}
+	switch {
+	case event.Slot != 0:
+		parts = append(parts, fmt.Sprintf("slot %d", event.Slot))
+	case event.Epoch != 0:
+		parts = append(parts, fmt.Sprintf("epoch %d", event.Epoch))
+	}
+	if event.Label != "" {
+		parts = append(parts, fmt.Sprintf("(%s)", event.Label))
+	}
+
+	keys := make([]string, 0, len(event.Data))
+	for key := range event.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var fields []string
+	for _, key := range keys {
+		value := reflect.ValueOf(event.Data[key])
+		switch value.Kind() {
+		case reflect.Slice, reflect.Array, reflect.Map:
+			fields = append(fields, fmt.Sprintf("%s=%d", key, value.Len()))
+		case reflect.Invalid, reflect.Struct, reflect.Pointer:
+		default:
+			fields = append(fields, fmt.Sprintf("%s=%v", key, value.Interface()))
+		}
+	}
+	if len(fields) > 0 {
+		parts = append(parts, "- "+strings.Join(fields, ", "))
+	}
+	return strings.Join(parts, " ")
+}
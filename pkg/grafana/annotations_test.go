@@ -0,0 +1,97 @@
+package grafana
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/events"
+	"github.com/sirupsen/logrus"
+)
+
+func TestPublisher(t *testing.T) {
+	var mu sync.Mutex
+	var received []annotationRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != annotationsPath || r.Header.Get("Authorization") != "Bearer key" {
+			t.Errorf("Unexpected request %s with %q", r.URL.Path, r.Header.Get("Authorization"))
+		}
+		var req annotationRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Invalid body: %v", err)
+		}
+		mu.Lock()
+		received = append(received, req)
+		mu.Unlock()
+		w.Write([]byte(`{"id":1,"message":"Annotation added"}`))
+	}))
+	defer server.Close()
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	p := NewPublisher(server.URL+"/", "key", []string{"a", "b"}, []string{"mainnet"}, nil, time.Second, logger)
+
+	at := time.UnixMilli(1700000000000)
+	p.Write(events.Event{Type: events.TypeBlockProposed, Time: at, Slot: 1344, ValidatorIndex: 7, Label: "operator:a"})
+	p.Write(events.Event{Type: events.TypeMissedAttestation, Time: at, Slot: 1344, ValidatorIndex: 7})
+	p.Close()
+	p.Annotate(Annotation{Text: "after close"})
+
+	if len(received) != 2 {
+		t.Fatalf("Expected the proposal on both dashboards, got %+v", received)
+	}
+	got := received[0]
+	if got.DashboardUID != "a" || received[1].DashboardUID != "b" || got.Time != at.UnixMilli() {
+		t.Errorf("Unexpected annotation: %+v", got)
+	}
+	if got.Text != "Block proposed validator 7 slot 1344 (operator:a)" {
+		t.Errorf("Unexpected text %q", got.Text)
+	}
+	expectedTags := []string{Tag, "mainnet", "block_proposed", "operator:a"}
+	if len(got.Tags) != len(expectedTags) {
+		t.Fatalf("Expected tags %v, got %v", expectedTags, got.Tags)
+	}
+	for i, tag := range expectedTags {
+		if got.Tags[i] != tag {
+			t.Errorf("Expected tags %v, got %v", expectedTags, got.Tags)
+		}
+	}
+}
+
+func TestEventText(t *testing.T) {
+	tests := []struct {
+		event    events.Event
+		expected string
+	}{
+		{
+			event:    events.Event{Type: events.TypeChainReorg, Slot: 100, Data: map[string]interface{}{"depth": 2, "source": "block_roots"}},
+			expected: "Chain reorg slot 100 - depth=2, source=block_roots",
+		},
+		{
+			event:    events.Event{Type: events.TypeWatchlistChanged, Data: map[string]interface{}{"source": "config_reload", "added": []string{"a", "b"}}},
+			expected: "Watchlist changed - added=2, source=config_reload",
+		},
+		{
+			event:    events.Event{Type: events.TypeValidatorNotLive, Epoch: 42, ValidatorIndex: 3},
+			expected: "Validator not live validator 3 epoch 42",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := EventText(tt.event); got != tt.expected {
+			t.Errorf("EventText(%s) = %q, want %q", tt.event.Type, got, tt.expected)
+		}
+	}
+}
+
+func TestValidateEvents(t *testing.T) {
+	if err := ValidateEvents(DefaultEvents); err != nil {
+		t.Errorf("Default events rejected: %v", err)
+	}
+	if err := ValidateEvents([]string{"missed_attestation", "reload"}); err == nil {
+		t.Error("Expected an unknown event type to be rejected")
+	}
+}
@@ -0,0 +1,469 @@
+package grafana
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/metrics"
+)
+
+// Scopes of the dashboard queries
+const (
+	scopeWatched = "scope:watched"
+	scopeNetwork = "scope:all-network"
+)
+
+// dashboardSchemaVersion is the Grafana dashboard schema the generated JSON follows
+const dashboardSchemaVersion = 39
+
+// maxUIDLength is the longest dashboard UID Grafana accepts
+const maxUIDLength = 40
+
+// comparable matches the per-scope metrics that make sense side by side for the watched validators
+// and the whole network: rates and averages rather than counts
+var comparable = regexp.MustCompile(`_rate($|_)|_apr$|_percent$|inclusion_delay`)
+
+// DashboardOptions parameterizes a generated dashboard
+type DashboardOptions struct {
+	Title   string
+	Network string   // Default of the network variable
+	Labels  []string // Default selection of the label variable, scope:watched if empty
+}
+
+// Dashboard is the subset of Grafana's dashboard model the generator fills in
+type Dashboard struct {
+	UID           string      `json:"uid"`
+	Title         string      `json:"title"`
+	Tags          []string    `json:"tags"`
+	Timezone      string      `json:"timezone"`
+	Editable      bool        `json:"editable"`
+	GraphTooltip  int         `json:"graphTooltip"`
+	Refresh       string      `json:"refresh"`
+	SchemaVersion int         `json:"schemaVersion"`
+	Time          timeRange   `json:"time"`
+	Annotations   annotations `json:"annotations"`
+	Templating    templating  `json:"templating"`
+	Panels        []panel     `json:"panels"`
+}
+
+type timeRange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type annotations struct {
+	List []annotationQuery `json:"list"`
+}
+
+// annotationQuery shows the watcher's organization annotations (see Publisher) for the selected network
+type annotationQuery struct {
+	Name       string     `json:"name"`
+	Datasource datasource `json:"datasource"`
+	Enable     bool       `json:"enable"`
+	IconColor  string     `json:"iconColor"`
+	Type       string     `json:"type"`
+	MatchAny   bool       `json:"matchAny"`
+	Tags       []string   `json:"tags"`
+	Target     struct {
+		Type     string   `json:"type"`
+		Tags     []string `json:"tags"`
+		MatchAny bool     `json:"matchAny"`
+		Limit    int      `json:"limit"`
+	} `json:"target"`
+}
+
+type templating struct {
+	List []variable `json:"list"`
+}
+
+type variable struct {
+	Name       string      `json:"name"`
+	Label      string      `json:"label,omitempty"`
+	Type       string      `json:"type"`
+	Query      string      `json:"query"`
+	Datasource *datasource `json:"datasource,omitempty"`
+	Definition string      `json:"definition,omitempty"`
+	Regex      string      `json:"regex,omitempty"`
+	Refresh    int         `json:"refresh,omitempty"`
+	Multi      bool        `json:"multi"`
+	IncludeAll bool        `json:"includeAll"`
+	Sort       int         `json:"sort,omitempty"`
+	Current    current     `json:"current"`
+}
+
+type current struct {
+	Text  interface{} `json:"text"`
+	Value interface{} `json:"value"`
+}
+
+type datasource struct {
+	Type string `json:"type"`
+	UID  string `json:"uid"`
+}
+
+type gridPos struct {
+	H int `json:"h"`
+	W int `json:"w"`
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+type panel struct {
+	ID          int          `json:"id"`
+	Type        string       `json:"type"`
+	Title       string       `json:"title"`
+	Description string       `json:"description,omitempty"`
+	GridPos     gridPos      `json:"gridPos"`
+	Datasource  *datasource  `json:"datasource,omitempty"`
+	Targets     []target     `json:"targets,omitempty"`
+	FieldConfig *fieldConfig `json:"fieldConfig,omitempty"`
+	Collapsed   *bool        `json:"collapsed,omitempty"`
+	Panels      []panel      `json:"panels,omitempty"` // Panels of a collapsed row
+}
+
+type target struct {
+	RefID        string      `json:"refId"`
+	Datasource   *datasource `json:"datasource"`
+	Expr         string      `json:"expr"`
+	LegendFormat string      `json:"legendFormat,omitempty"`
+}
+
+type fieldConfig struct {
+	Defaults struct {
+		Unit string `json:"unit,omitempty"`
+	} `json:"defaults"`
+	Overrides []interface{} `json:"overrides"`
+}
+
+// prometheusDatasource is the datasource variable every query goes through
+var prometheusDatasource = &datasource{Type: "prometheus", UID: "${datasource}"}
+
+// layout places panels left to right, wrapping at the dashboard's 24 columns
+type layout struct {
+	id, x, y, rowHeight int
+}
+
+// next returns the position of a panel of the given size
+func (l *layout) next(w, h int) gridPos {
+	if l.x+w > 24 {
+		l.x, l.y, l.rowHeight = 0, l.y+l.rowHeight, 0
+	}
+	pos := gridPos{H: h, W: w, X: l.x, Y: l.y}
+	l.x += w
+	if h > l.rowHeight {
+		l.rowHeight = h
+	}
+	return pos
+}
+
+// row returns the position of a full-width row header
+func (l *layout) row() gridPos {
+	if l.x > 0 {
+		l.x, l.y, l.rowHeight = 0, l.y+l.rowHeight, 0
+	}
+	pos := gridPos{H: 1, W: 24, X: 0, Y: l.y}
+	l.y++
+	return pos
+}
+
+// nextID returns the next panel ID
+func (l *layout) nextID() int {
+	l.id++
+	return l.id
+}
+
+// GenerateDashboard builds a dashboard with a panel for every metric of the catalog: network-wide
+// metrics, the watched validators compared to the network, the selected labels and the watcher's own
+func GenerateDashboard(catalog []metrics.Info, opts DashboardOptions) ([]byte, error) {
+	if opts.Network == "" {
+		return nil, fmt.Errorf("network is required")
+	}
+	if opts.Title == "" {
+		opts.Title = "Ethereum Validator Watcher (" + opts.Network + ")"
+	}
+	labels := opts.Labels
+	if len(labels) == 0 {
+		labels = []string{scopeWatched}
+	}
+
+	var network, compared, perLabel, other []metrics.Info
+	for _, info := range catalog {
+		switch {
+		case info.HasLabel("scope"):
+			if comparable.MatchString(info.Name) {
+				compared = append(compared, info)
+			}
+			perLabel = append(perLabel, info)
+		case len(info.Labels) == 1 && info.HasLabel("network"):
+			network = append(network, info)
+		default:
+			other = append(other, info)
+		}
+	}
+
+	l := &layout{}
+	var panels []panel
+	panels = append(panels, rowPanel(l, "🖧 Network"))
+	for _, info := range network {
+		panels = append(panels, statPanel(l, info))
+	}
+	panels = append(panels, rowPanel(l, "🔎 Watched validators compared to the network"))
+	for _, info := range compared {
+		panels = append(panels, comparisonPanel(l, info))
+	}
+
+	// Collapsed rows lay out their panels below the header as if expanded
+	header := l.row()
+	var labelPanels []panel
+	for _, info := range perLabel {
+		labelPanels = append(labelPanels, seriesPanel(l, info, `scope=~"${label:regex}"`))
+	}
+	panels = append(panels, collapsedRow(l, header, "🏷️ Per label", labelPanels))
+
+	header = l.row()
+	var otherPanels []panel
+	for _, info := range other {
+		otherPanels = append(otherPanels, seriesPanel(l, info, ""))
+	}
+	panels = append(panels, collapsedRow(l, header, "⚙️ Watcher, queues and relays", otherPanels))
+
+	dashboard := Dashboard{
+		UID:           dashboardUID(opts.Network),
+		Title:         opts.Title,
+		Tags:          []string{Tag, opts.Network},
+		Timezone:      "browser",
+		Editable:      true,
+		GraphTooltip:  1,
+		Refresh:       "1m",
+		SchemaVersion: dashboardSchemaVersion,
+		Time:          timeRange{From: "now-24h", To: "now"},
+		Annotations:   annotations{List: []annotationQuery{watcherAnnotations()}},
+		Templating:    templating{List: variables(opts.Network, labels)},
+		Panels:        panels,
+	}
+	return json.MarshalIndent(dashboard, "", "  ")
+}
+
+// dashboardUID derives a stable UID from the network, so regenerating replaces the imported dashboard
+func dashboardUID(network string) string {
+	uid := "eth-validator-watcher-" + strings.ToLower(network)
+	if len(uid) > maxUIDLength {
+		uid = uid[:maxUIDLength]
+	}
+	return uid
+}
+
+// variables returns the datasource, network and label variables
+func variables(network string, labels []string) []variable {
+	return []variable{
+		{
+			Name:    "datasource",
+			Label:   "Data source",
+			Type:    "datasource",
+			Query:   "prometheus",
+			Current: current{Text: "default", Value: "default"},
+		},
+		{
+			Name:       "network",
+			Type:       "query",
+			Query:      "label_values(eth_slot, network)",
+			Definition: "label_values(eth_slot, network)",
+			Datasource: prometheusDatasource,
+			Refresh:    1,
+			Current:    current{Text: network, Value: network},
+		},
+		{
+			Name:       "label",
+			Type:       "query",
+			Query:      `label_values(eth_validator_status_count{network="$network"}, scope)`,
+			Definition: `label_values(eth_validator_status_count{network="$network"}, scope)`,
+			Datasource: prometheusDatasource,
+			Regex:      "/^(?!" + regexp.QuoteMeta(scopeNetwork) + "$).*/",
+			Refresh:    2,
+			Multi:      true,
+			IncludeAll: true,
+			Sort:       1,
+			Current:    current{Text: labels, Value: labels},
+		},
+	}
+}
+
+// watcherAnnotations queries the annotations the watcher posts for the selected network
+func watcherAnnotations() annotationQuery {
+	q := annotationQuery{
+		Name:       "Watcher events",
+		Datasource: datasource{Type: "grafana", UID: "-- Grafana --"},
+		Enable:     true,
+		IconColor:  "orange",
+		Type:       "tags",
+		Tags:       []string{Tag, "$network"},
+	}
+	q.Target.Type = "tags"
+	q.Target.Tags = q.Tags
+	q.Target.Limit = 100
+	return q
+}
+
+// rowPanel returns an expanded row header
+func rowPanel(l *layout, title string) panel {
+	collapsed := false
+	return panel{ID: l.nextID(), Type: "row", Title: title, GridPos: l.row(), Collapsed: &collapsed, Panels: []panel{}}
+}
+
+// collapsedRow returns a collapsed row header holding panels, which are laid out below it; the
+// following panels start right under the header
+func collapsedRow(l *layout, header gridPos, title string, panels []panel) panel {
+	collapsed := true
+	if panels == nil {
+		panels = []panel{}
+	}
+	l.x, l.y, l.rowHeight = 0, header.Y+1, 0
+	return panel{ID: l.nextID(), Type: "row", Title: title, GridPos: header, Collapsed: &collapsed, Panels: panels}
+}
+
+// statPanel shows the latest value of a network-wide gauge, or a counter's increase over the time range
+func statPanel(l *layout, info metrics.Info) panel {
+	expr := query(info, "", nil)
+	if info.Type == metrics.TypeCounter {
+		expr = fmt.Sprintf(`sum(increase(%s{network="$network"}[$__range]))`, info.Name)
+	}
+	return panel{
+		ID:          l.nextID(),
+		Type:        "stat",
+		Title:       title(info.Name),
+		Description: info.Help,
+		GridPos:     l.next(4, 4),
+		Datasource:  prometheusDatasource,
+		Targets:     []target{{RefID: "A", Datasource: prometheusDatasource, Expr: expr}},
+		FieldConfig: unit(info.Name),
+	}
+}
+
+// comparisonPanel plots a per-scope metric of the watched validators against the whole network
+func comparisonPanel(l *layout, info metrics.Info) panel {
+	by := groupBy(info, "scope")
+	p := panel{
+		ID:          l.nextID(),
+		Type:        "timeseries",
+		Title:       title(info.Name),
+		Description: info.Help,
+		GridPos:     l.next(8, 8),
+		Datasource:  prometheusDatasource,
+		FieldConfig: unit(info.Name),
+	}
+	for i, scope := range []string{scopeWatched, scopeNetwork} {
+		p.Targets = append(p.Targets, target{
+			RefID:        string(rune('A' + i)),
+			Datasource:   prometheusDatasource,
+			Expr:         query(info, fmt.Sprintf(`scope="%s"`, scope), by),
+			LegendFormat: legend(scope, by),
+		})
+	}
+	return p
+}
+
+// seriesPanel plots a metric by all its labels but the network, optionally filtered
+func seriesPanel(l *layout, info metrics.Info, filter string) panel {
+	by := groupBy(info)
+	return panel{
+		ID:          l.nextID(),
+		Type:        "timeseries",
+		Title:       title(info.Name),
+		Description: info.Help,
+		GridPos:     l.next(12, 8),
+		Datasource:  prometheusDatasource,
+		Targets: []target{{
+			RefID:        "A",
+			Datasource:   prometheusDatasource,
+			Expr:         query(info, filter, by),
+			LegendFormat: legend("", by),
+		}},
+		FieldConfig: unit(info.Name),
+	}
+}
+
+// query returns the expression of a metric on the selected network: the value of gauges, the
+// increase of counters per interval, and the 95th percentile of histograms
+func query(info metrics.Info, filter string, by []string) string {
+	selector := `network="$network"`
+	if filter != "" {
+		selector += ", " + filter
+	}
+	grouping := ""
+	if len(by) > 0 {
+		grouping = " by (" + strings.Join(by, ", ") + ")"
+	}
+
+	switch info.Type {
+	case metrics.TypeCounter:
+		return fmt.Sprintf(`sum%s (increase(%s{%s}[$__rate_interval]))`, grouping, info.Name, selector)
+	case metrics.TypeHistogram:
+		return fmt.Sprintf(`histogram_quantile(0.95, sum by (%s) (rate(%s_bucket{%s}[$__rate_interval])))`,
+			strings.Join(append([]string{"le"}, by...), ", "), info.Name, selector)
+	case metrics.TypeGauge:
+		if strings.HasSuffix(info.Name, "_timestamp_seconds") {
+			// Grafana reads timestamps in milliseconds
+			return fmt.Sprintf(`max%s (%s{%s}) * 1000`, grouping, info.Name, selector)
+		}
+	}
+	return fmt.Sprintf(`max%s (%s{%s})`, grouping, info.Name, selector)
+}
+
+// groupBy returns the labels a metric is plotted by: all but the network and the excluded ones
+func groupBy(info metrics.Info, exclude ...string) []string {
+	var by []string
+	for _, label := range info.Labels {
+		excluded := label == "network"
+		for _, e := range exclude {
+			excluded = excluded || label == e
+		}
+		if !excluded {
+			by = append(by, label)
+		}
+	}
+	return by
+}
+
+// legend names a series by its prefix and grouping labels
+func legend(prefix string, by []string) string {
+	parts := make([]string, 0, len(by)+1)
+	if prefix != "" {
+		parts = append(parts, prefix)
+	}
+	for _, label := range by {
+		parts = append(parts, "{{"+label+"}}")
+	}
+	return strings.Join(parts, " ")
+}
+
+// title turns a metric name into a panel title: eth_missed_attestations -> Missed attestations
+func title(name string) string {
+	words := strings.Fields(strings.ReplaceAll(strings.TrimPrefix(name, "eth_"), "_", " "))
+	if len(words) == 0 {
+		return name
+	}
+	words[0] = strings.ToUpper(words[0][:1]) + words[0][1:]
+	return strings.Join(words, " ")
+}
+
+// unit picks the Grafana unit of a metric from its name, nil when there is none to set
+func unit(name string) *fieldConfig {
+	var u string
+	switch {
+	case strings.HasSuffix(name, "_timestamp_seconds"):
+		u = "dateTimeFromNow"
+	case strings.HasSuffix(name, "_seconds"):
+		u = "s"
+	case strings.HasSuffix(name, "_percent"), strings.HasSuffix(name, "_apr"):
+		u = "percent"
+	case strings.HasSuffix(name, "_dollars"):
+		u = "currencyUSD"
+	default:
+		return nil
+	}
+	fc := &fieldConfig{Overrides: []interface{}{}}
+	fc.Defaults.Unit = u
+	return fc
+}
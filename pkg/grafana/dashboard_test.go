@@ -0,0 +1,103 @@
+package grafana
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/metrics"
+)
+
+func TestGenerateDashboard(t *testing.T) {
+	catalog, err := metrics.Catalog()
+	if err != nil {
+		t.Fatalf("Catalog() error = %v", err)
+	}
+
+	data, err := GenerateDashboard(catalog, DashboardOptions{Network: "Hoodi", Labels: []string{"operator:a"}})
+	if err != nil {
+		t.Fatalf("GenerateDashboard() error = %v", err)
+	}
+	var dashboard Dashboard
+	if err := json.Unmarshal(data, &dashboard); err != nil {
+		t.Fatalf("Generated dashboard is not valid JSON: %v", err)
+	}
+
+	if dashboard.UID != "eth-validator-watcher-hoodi" || dashboard.Title != "Ethereum Validator Watcher (Hoodi)" {
+		t.Errorf("Unexpected UID %q or title %q", dashboard.UID, dashboard.Title)
+	}
+	vars := make(map[string]variable)
+	for _, v := range dashboard.Templating.List {
+		vars[v.Name] = v
+	}
+	if vars["network"].Current.Value != "Hoodi" {
+		t.Errorf("Expected the network variable to default to Hoodi, got %v", vars["network"].Current.Value)
+	}
+	if labels, ok := vars["label"].Current.Value.([]interface{}); !ok || len(labels) != 1 || labels[0] != "operator:a" {
+		t.Errorf("Expected the label variable to default to operator:a, got %v", vars["label"].Current.Value)
+	}
+
+	// Every metric is plotted, and panel IDs are unique across collapsed rows
+	var exprs []string
+	ids := make(map[int]bool)
+	var walk func([]panel)
+	walk = func(panels []panel) {
+		for _, p := range panels {
+			if ids[p.ID] {
+				t.Errorf("Duplicate panel ID %d", p.ID)
+			}
+			ids[p.ID] = true
+			for _, target := range p.Targets {
+				exprs = append(exprs, target.Expr)
+			}
+			walk(p.Panels)
+		}
+	}
+	walk(dashboard.Panels)
+	all := strings.Join(exprs, "\n")
+	for _, info := range catalog {
+		series := info.Name
+		if info.Type == metrics.TypeHistogram {
+			series += "_bucket"
+		}
+		if !strings.Contains(all, series+"{") {
+			t.Errorf("Metric %s has no panel", info.Name)
+		}
+	}
+
+	if _, err := GenerateDashboard(catalog, DashboardOptions{}); err == nil {
+		t.Error("Expected a dashboard without network to be rejected")
+	}
+}
+
+func TestDashboardQuery(t *testing.T) {
+	tests := []struct {
+		info     metrics.Info
+		filter   string
+		expected string
+	}{
+		{
+			info:     metrics.Info{Name: "eth_missed_attestations", Type: metrics.TypeGauge, Labels: []string{"scope", "network"}},
+			filter:   `scope="scope:watched"`,
+			expected: `max by (scope) (eth_missed_attestations{network="$network", scope="scope:watched"})`,
+		},
+		{
+			info:     metrics.Info{Name: "eth_reorg_events_total", Type: metrics.TypeCounter, Labels: []string{"depth", "network"}},
+			expected: `sum by (depth) (increase(eth_reorg_events_total{network="$network"}[$__rate_interval]))`,
+		},
+		{
+			info:     metrics.Info{Name: "eth_request_seconds", Type: metrics.TypeHistogram, Labels: []string{"network"}},
+			expected: `histogram_quantile(0.95, sum by (le) (rate(eth_request_seconds_bucket{network="$network"}[$__rate_interval])))`,
+		},
+		{
+			info:     metrics.Info{Name: "eth_counters_reset_timestamp_seconds", Type: metrics.TypeGauge, Labels: []string{"network"}},
+			expected: `max (eth_counters_reset_timestamp_seconds{network="$network"}) * 1000`,
+		},
+	}
+
+	for _, tt := range tests {
+		if got := query(tt.info, tt.filter, groupBy(tt.info)); got != tt.expected {
+			t.Errorf("query(%s) = %s, want %s", tt.info.Name, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package health
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// grpcUpdateInterval is how often the gRPC serving status is refreshed from the checks
+const grpcUpdateInterval = 5 * time.Second
+
+// ServeGRPC serves the standard gRPC health checking protocol (grpc.health.v1.Health) until ctx is cancelled
+// The overall service ("") follows the readiness probe, and every check is also a service of its own name
+func ServeGRPC(ctx context.Context, addr string, registry *Registry, logger *logrus.Logger) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	logger.WithField("address", addr).Info("Starting gRPC health server")
+	return serveGRPC(ctx, listener, registry)
+}
+
+// serveGRPC serves the health service on a listener
+func serveGRPC(ctx context.Context, listener net.Listener, registry *Registry) error {
+	server := grpc.NewServer()
+	healthServer := grpchealth.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	update := func() {
+		_, ready := registry.Run(ProbeReady)
+		healthServer.SetServingStatus("", servingStatus(ready))
+		for _, name := range registry.Names() {
+			if result, ok := registry.RunCheck(name); ok {
+				healthServer.SetServingStatus(name, servingStatus(result.Healthy))
+			}
+		}
+	}
+	update()
+
+	go func() {
+		ticker := time.NewTicker(grpcUpdateInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				healthServer.Shutdown()
+				server.GracefulStop()
+				return
+			case <-ticker.C:
+				update()
+			}
+		}
+	}()
+
+	return server.Serve(listener)
+}
+
+// servingStatus converts a check outcome to the gRPC health status
+func servingStatus(healthy bool) healthpb.HealthCheckResponse_ServingStatus {
+	if healthy {
+		return healthpb.HealthCheckResponse_SERVING
+	}
+	return healthpb.HealthCheckResponse_NOT_SERVING
+}
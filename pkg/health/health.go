@@ -0,0 +1,163 @@
+package health
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Probe is a Kubernetes-style probe that checks contribute to
+type Probe string
+
+const (
+	ProbeLive    Probe = "livez"    // Failing means the process should be restarted
+	ProbeReady   Probe = "readyz"   // Failing means the watcher's data shouldn't be relied on
+	ProbeStartup Probe = "startupz" // Failing means the watcher is still initializing
+)
+
+// probes lists every probe served over HTTP
+var probes = []Probe{ProbeLive, ProbeReady, ProbeStartup}
+
+// Check is a subsystem health check; Run returns nil when the subsystem is healthy
+type Check struct {
+	Name   string
+	Probes []Probe
+	Run    func() error
+}
+
+// Result is the outcome of one check
+type Result struct {
+	Name    string
+	Healthy bool
+	Err     error
+}
+
+// Registry holds the subsystem checks behind the probe endpoints and the components of the JSON report
+type Registry struct {
+	mu         sync.RWMutex
+	checks     []Check
+	components []Component // Parts of the JSON report
+}
+
+// New creates an empty registry
+func New() *Registry {
+	return &Registry{}
+}
+
+// Add registers a check
+func (r *Registry) Add(check Check) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.checks = append(r.checks, check)
+}
+
+// Names returns the names of all checks in registration order
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, len(r.checks))
+	for i, check := range r.checks {
+		names[i] = check.Name
+	}
+	return names
+}
+
+// Run runs the checks of a probe; the probe passes if all of them do
+func (r *Registry) Run(probe Probe) (results []Result, healthy bool) {
+	healthy = true
+	for _, check := range r.probeChecks(probe) {
+		result := runCheck(check)
+		healthy = healthy && result.Healthy
+		results = append(results, result)
+	}
+	return results, healthy
+}
+
+// RunCheck runs a single check by name
+func (r *Registry) RunCheck(name string) (Result, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, check := range r.checks {
+		if check.Name == name {
+			return runCheck(check), true
+		}
+	}
+	return Result{}, false
+}
+
+// probeChecks returns the checks that contribute to a probe
+func (r *Registry) probeChecks(probe Probe) []Check {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var checks []Check
+	for _, check := range r.checks {
+		for _, p := range check.Probes {
+			if p == probe {
+				checks = append(checks, check)
+				break
+			}
+		}
+	}
+	return checks
+}
+
+// runCheck runs a check
+func runCheck(check Check) Result {
+	err := check.Run()
+	return Result{Name: check.Name, Healthy: err == nil, Err: err}
+}
+
+// Register adds /livez, /readyz and /startupz, plus /<probe>/<check> for single checks
+// Responses follow the Kubernetes API server: "ok", or per-check lines with ?verbose or on failure
+func (r *Registry) Register(mux *http.ServeMux) {
+	for _, probe := range probes {
+		probe := probe
+		mux.HandleFunc("/"+string(probe), func(w http.ResponseWriter, req *http.Request) {
+			results, healthy := r.Run(probe)
+			_, verbose := req.URL.Query()["verbose"]
+			writeResults(w, string(probe), results, healthy, verbose)
+		})
+		mux.HandleFunc("/"+string(probe)+"/", func(w http.ResponseWriter, req *http.Request) {
+			name := strings.TrimPrefix(req.URL.Path, "/"+string(probe)+"/")
+			result, ok := r.RunCheck(name)
+			if !ok {
+				http.NotFound(w, req)
+				return
+			}
+			_, verbose := req.URL.Query()["verbose"]
+			writeResults(w, string(probe), []Result{result}, result.Healthy, verbose)
+		})
+	}
+}
+
+// writeResults writes a probe response
+func writeResults(w http.ResponseWriter, probe string, results []Result, healthy, verbose bool) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if !healthy {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	if healthy && !verbose {
+		fmt.Fprint(w, "ok")
+		return
+	}
+
+	for _, result := range results {
+		if result.Healthy {
+			fmt.Fprintf(w, "[+]%s ok\n", result.Name)
+		} else {
+			fmt.Fprintf(w, "[-]%s failed: %v\n", result.Name, result.Err)
+		}
+	}
+	if healthy {
+		fmt.Fprintf(w, "%s check passed\n", probe)
+	} else {
+		fmt.Fprintf(w, "%s check failed\n", probe)
+	}
+}
@@ -0,0 +1,99 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func newTestRegistry(beaconErr *error) *Registry {
+	r := New()
+	r.Add(Check{Name: "initialized", Probes: []Probe{ProbeStartup, ProbeReady}, Run: func() error { return nil }})
+	r.Add(Check{Name: "beacon", Probes: []Probe{ProbeReady}, Run: func() error { return *beaconErr }})
+	return r
+}
+
+func TestProbeEndpoints(t *testing.T) {
+	var beaconErr error
+	mux := http.NewServeMux()
+	newTestRegistry(&beaconErr).Register(mux)
+
+	get := func(path string) (int, string) {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec.Code, rec.Body.String()
+	}
+
+	if code, body := get("/readyz"); code != http.StatusOK || body != "ok" {
+		t.Errorf("Expected 200 ok, got %d %q", code, body)
+	}
+	if code, body := get("/readyz?verbose"); code != http.StatusOK || !strings.Contains(body, "[+]beacon ok") {
+		t.Errorf("Expected verbose per-check output, got %d %q", code, body)
+	}
+	// A probe without checks passes
+	if code, _ := get("/livez"); code != http.StatusOK {
+		t.Errorf("Expected livez to pass, got %d", code)
+	}
+
+	beaconErr = errors.New("no healthy beacon endpoint")
+	code, body := get("/readyz")
+	if code != http.StatusServiceUnavailable || !strings.Contains(body, "[-]beacon failed: no healthy beacon endpoint") {
+		t.Errorf("Expected 503 with the failing check, got %d %q", code, body)
+	}
+	if code, _ := get("/startupz"); code != http.StatusOK {
+		t.Errorf("Expected startupz to ignore the beacon check, got %d", code)
+	}
+	if code, _ := get("/readyz/beacon"); code != http.StatusServiceUnavailable {
+		t.Errorf("Expected the single check to fail, got %d", code)
+	}
+	if code, _ := get("/readyz/unknown"); code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown check, got %d", code)
+	}
+}
+
+func TestGRPCHealth(t *testing.T) {
+	beaconErr := errors.New("down")
+	registry := newTestRegistry(&beaconErr)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go serveGRPC(ctx, listener, registry)
+
+	conn, err := grpc.Dial(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	defer conn.Close()
+	client := healthpb.NewHealthClient(conn)
+
+	checkCtx, checkCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer checkCancel()
+
+	expected := map[string]healthpb.HealthCheckResponse_ServingStatus{
+		"":            healthpb.HealthCheckResponse_NOT_SERVING,
+		"beacon":      healthpb.HealthCheckResponse_NOT_SERVING,
+		"initialized": healthpb.HealthCheckResponse_SERVING,
+	}
+	for service, status := range expected {
+		resp, err := client.Check(checkCtx, &healthpb.HealthCheckRequest{Service: service})
+		if err != nil {
+			t.Fatalf("Check(%q) failed: %v", service, err)
+		}
+		if resp.Status != status {
+			t.Errorf("Check(%q): expected %s, got %s", service, status, resp.Status)
+		}
+	}
+}
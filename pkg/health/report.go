@@ -0,0 +1,128 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// Status is a component's health in the JSON report
+type Status string
+
+const (
+	StatusPass Status = "pass"
+	StatusWarn Status = "warn"
+	StatusFail Status = "fail"
+)
+
+// severity orders statuses from pass to fail
+func (s Status) severity() int {
+	switch s {
+	case StatusPass:
+		return 0
+	case StatusWarn:
+		return 1
+	}
+	return 2
+}
+
+// ComponentResult is the state of one component in the report
+type ComponentResult struct {
+	Name    string                 `json:"name"`
+	Status  Status                 `json:"status"`
+	Message string                 `json:"message,omitempty"`
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+// Component is a part of the watcher reported with pass, warn or fail
+type Component struct {
+	Name  string
+	Check func() ComponentResult // The result's name is filled in
+}
+
+// Report is the structured health report of every component
+type Report struct {
+	Status     Status            `json:"status"` // Worst status of the components the criteria include
+	Healthy    bool              `json:"healthy"`
+	Time       time.Time         `json:"time"`
+	Components []ComponentResult `json:"components"`
+}
+
+// Criteria decide when a report fails a probe
+type Criteria struct {
+	FailOn     Status   // Lowest status failing the probe, fail if empty
+	Components []string // Components that count, every component if empty
+}
+
+// includes reports whether a component counts
+func (c Criteria) includes(name string) bool {
+	if len(c.Components) == 0 {
+		return true
+	}
+	for _, component := range c.Components {
+		if component == name {
+			return true
+		}
+	}
+	return false
+}
+
+// AddComponent registers a component of the JSON report
+func (r *Registry) AddComponent(component Component) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.components = append(r.components, component)
+}
+
+// ComponentNames returns the names of all components in registration order
+func (r *Registry) ComponentNames() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, len(r.components))
+	for i, component := range r.components {
+		names[i] = component.Name
+	}
+	return names
+}
+
+// Report checks every component; the components outside the criteria are reported without counting
+func (r *Registry) Report(criteria Criteria) Report {
+	r.mu.RLock()
+	components := append([]Component(nil), r.components...)
+	r.mu.RUnlock()
+
+	failOn := criteria.FailOn
+	if failOn == "" {
+		failOn = StatusFail
+	}
+
+	report := Report{Status: StatusPass, Time: time.Now().UTC(), Components: make([]ComponentResult, 0, len(components))}
+	for _, component := range components {
+		result := component.Check()
+		result.Name = component.Name
+		if result.Status == "" {
+			result.Status = StatusPass
+		}
+		report.Components = append(report.Components, result)
+		if criteria.includes(component.Name) && result.Status.severity() > report.Status.severity() {
+			report.Status = result.Status
+		}
+	}
+	report.Healthy = report.Status.severity() < failOn.severity()
+	return report
+}
+
+// RegisterReport serves the JSON report at a path: 200 while healthy by the criteria, 503 otherwise
+func (r *Registry) RegisterReport(mux *http.ServeMux, path string, criteria Criteria) {
+	mux.HandleFunc(path, func(w http.ResponseWriter, req *http.Request) {
+		report := r.Report(criteria)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		if !report.Healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(report)
+	})
+}
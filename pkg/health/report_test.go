@@ -0,0 +1,55 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestReport(t *testing.T) {
+	syncStatus := StatusPass
+	r := New()
+	r.AddComponent(Component{Name: "slot_age", Check: func() ComponentResult { return ComponentResult{} }})
+	r.AddComponent(Component{Name: "beacon_sync", Check: func() ComponentResult {
+		return ComponentResult{Status: syncStatus, Details: map[string]interface{}{"sync_distance": 3}}
+	}})
+
+	mux := http.NewServeMux()
+	r.RegisterReport(mux, "/health", Criteria{Components: []string{"slot_age"}})
+	r.RegisterReport(mux, "/ready", Criteria{})
+	r.RegisterReport(mux, "/strict", Criteria{FailOn: StatusWarn})
+	get := func(path string) (int, Report) {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		var report Report
+		if err := json.NewDecoder(rec.Body).Decode(&report); err != nil {
+			t.Fatalf("Invalid report from %s: %v", path, err)
+		}
+		return rec.Code, report
+	}
+
+	code, report := get("/ready")
+	if code != http.StatusOK || report.Status != StatusPass || len(report.Components) != 2 || report.Components[0].Name != "slot_age" {
+		t.Errorf("Expected a passing report of both components, got %d %+v", code, report)
+	}
+
+	// A warning only fails the probes that fail on warnings
+	syncStatus = StatusWarn
+	if code, report := get("/ready"); code != http.StatusOK || report.Status != StatusWarn || !report.Healthy {
+		t.Errorf("Expected a healthy warning, got %d %+v", code, report)
+	}
+	if code, _ := get("/strict"); code != http.StatusServiceUnavailable {
+		t.Errorf("Expected fail_on warn to fail, got %d", code)
+	}
+
+	// Components outside the criteria are reported but don't count
+	syncStatus = StatusFail
+	if code, _ := get("/ready"); code != http.StatusServiceUnavailable {
+		t.Errorf("Expected a failing component to fail readiness, got %d", code)
+	}
+	code, report = get("/health")
+	if code != http.StatusOK || report.Status != StatusPass || report.Components[1].Status != StatusFail {
+		t.Errorf("Expected liveness to ignore beacon_sync, got %d %+v", code, report)
+	}
+}
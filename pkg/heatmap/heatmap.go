@@ -0,0 +1,187 @@
+package heatmap
+
+import (
+	"encoding/hex"
+	"sort"
+	"sync"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// DefaultEpochs is one day of epochs
+const DefaultEpochs = 225
+
+// Tracker keeps per-validator attestation outcomes for a rolling window of epochs
+// Outcomes live in ring-buffer bitsets, two bits per validator per epoch
+type Tracker struct {
+	mu      sync.RWMutex
+	size    int
+	columns []models.Epoch // Epoch held by each ring position
+	filled  []bool         // Whether the ring position holds any epoch yet
+	rows    map[models.ValidatorIndex]*row
+	latest  models.Epoch
+	started bool
+}
+
+// row is one validator's outcome bitsets, indexed by ring position
+type row struct {
+	labels []string
+	duties []byte // Bit set when the validator had an attestation duty
+	missed []byte // Bit set when that duty was missed
+}
+
+// Heatmap is a window of outcomes, bit i of each bitmap being epoch StartEpoch+i
+// Bitmaps are hex-encoded little-endian bitfields, like SSZ bitvectors
+type Heatmap struct {
+	StartEpoch models.Epoch `json:"start_epoch,string"`
+	EndEpoch   models.Epoch `json:"end_epoch,string"`
+	Epochs     int          `json:"epochs"`
+	Validators []Row        `json:"validators"`
+}
+
+// Row is one validator's outcomes in a Heatmap
+type Row struct {
+	Index  models.ValidatorIndex `json:"index,string"`
+	Labels []string              `json:"labels,omitempty"`
+	Duties string                `json:"duties"`
+	Missed string                `json:"missed"`
+}
+
+// New creates a tracker holding the most recent epochs
+func New(epochs int) *Tracker {
+	if epochs <= 0 {
+		epochs = DefaultEpochs
+	}
+	return &Tracker{
+		size:    epochs,
+		columns: make([]models.Epoch, epochs),
+		filled:  make([]bool, epochs),
+		rows:    make(map[models.ValidatorIndex]*row),
+	}
+}
+
+// Record stores a validator's attestation outcome for an epoch
+// Outcomes older than the window are ignored
+func (t *Tracker) Record(index models.ValidatorIndex, labels []string, epoch models.Epoch, missed bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.started && epoch+models.Epoch(t.size) <= t.latest {
+		return
+	}
+	if !t.started || epoch > t.latest {
+		t.latest = epoch
+		t.started = true
+	}
+
+	pos := int(epoch % models.Epoch(t.size))
+	if !t.filled[pos] || t.columns[pos] != epoch {
+		t.resetColumn(pos, epoch)
+	}
+
+	r, ok := t.rows[index]
+	if !ok {
+		bytes := (t.size + 7) / 8
+		r = &row{duties: make([]byte, bytes), missed: make([]byte, bytes)}
+		t.rows[index] = r
+	}
+	r.labels = labels
+
+	setBit(r.duties, pos, true)
+	setBit(r.missed, pos, missed)
+}
+
+// resetColumn clears a ring position before it's reused for a newer epoch
+func (t *Tracker) resetColumn(pos int, epoch models.Epoch) {
+	for _, r := range t.rows {
+		setBit(r.duties, pos, false)
+		setBit(r.missed, pos, false)
+	}
+	t.columns[pos] = epoch
+	t.filled[pos] = true
+}
+
+// Snapshot returns the last epochs of outcomes (all kept epochs if epochs is out of range)
+// Only validators carrying label are included, or all validators if label is empty
+func (t *Tracker) Snapshot(epochs int, label string) Heatmap {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if epochs <= 0 || epochs > t.size {
+		epochs = t.size
+	}
+
+	heatmap := Heatmap{Epochs: epochs, Validators: []Row{}}
+	if !t.started {
+		return heatmap
+	}
+
+	heatmap.EndEpoch = t.latest
+	if t.latest+1 >= models.Epoch(epochs) {
+		heatmap.StartEpoch = t.latest + 1 - models.Epoch(epochs)
+	}
+	heatmap.Epochs = int(heatmap.EndEpoch-heatmap.StartEpoch) + 1
+
+	for index, r := range t.rows {
+		if label != "" && !hasLabel(r.labels, label) {
+			continue
+		}
+
+		duties := make([]byte, (heatmap.Epochs+7)/8)
+		missed := make([]byte, len(duties))
+		for i := 0; i < heatmap.Epochs; i++ {
+			epoch := heatmap.StartEpoch + models.Epoch(i)
+			pos := int(epoch % models.Epoch(t.size))
+			if !t.filled[pos] || t.columns[pos] != epoch {
+				continue
+			}
+			setBit(duties, i, getBit(r.duties, pos))
+			setBit(missed, i, getBit(r.missed, pos))
+		}
+
+		heatmap.Validators = append(heatmap.Validators, Row{
+			Index:  index,
+			Labels: r.labels,
+			Duties: "0x" + hex.EncodeToString(duties),
+			Missed: "0x" + hex.EncodeToString(missed),
+		})
+	}
+
+	sort.Slice(heatmap.Validators, func(i, j int) bool {
+		return heatmap.Validators[i].Index < heatmap.Validators[j].Index
+	})
+	return heatmap
+}
+
+// Retain drops validators no longer watched
+func (t *Tracker) Retain(keep func(models.ValidatorIndex) bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for index := range t.rows {
+		if !keep(index) {
+			delete(t.rows, index)
+		}
+	}
+}
+
+func hasLabel(labels []string, label string) bool {
+	for _, l := range labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
+func setBit(bits []byte, i int, value bool) {
+	if value {
+		bits[i/8] |= 1 << (i % 8)
+	} else {
+		bits[i/8] &^= 1 << (i % 8)
+	}
+}
+
+func getBit(bits []byte, i int) bool {
+	return bits[i/8]&(1<<(i%8)) != 0
+}
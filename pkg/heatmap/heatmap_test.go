@@ -0,0 +1,64 @@
+package heatmap
+
+import (
+	"testing"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+func TestSnapshotBitmaps(t *testing.T) {
+	tracker := New(8)
+	labels := []string{"operator:a"}
+
+	// Validator 1 attests in epochs 10-12 and misses 11; validator 2 only has a duty in 12
+	tracker.Record(1, labels, 10, false)
+	tracker.Record(1, labels, 11, true)
+	tracker.Record(1, labels, 12, false)
+	tracker.Record(2, []string{"operator:b"}, 12, false)
+
+	heatmap := tracker.Snapshot(3, "")
+	if heatmap.StartEpoch != 10 || heatmap.EndEpoch != 12 || heatmap.Epochs != 3 {
+		t.Fatalf("Unexpected window: %+v", heatmap)
+	}
+	if len(heatmap.Validators) != 2 {
+		t.Fatalf("Expected 2 validators, got %d", len(heatmap.Validators))
+	}
+
+	v1 := heatmap.Validators[0]
+	if v1.Index != 1 || v1.Duties != "0x07" || v1.Missed != "0x02" {
+		t.Errorf("Unexpected row for validator 1: %+v", v1)
+	}
+	v2 := heatmap.Validators[1]
+	if v2.Duties != "0x04" || v2.Missed != "0x00" {
+		t.Errorf("Unexpected row for validator 2: %+v", v2)
+	}
+
+	filtered := tracker.Snapshot(0, "operator:b")
+	if len(filtered.Validators) != 1 || filtered.Validators[0].Index != 2 {
+		t.Errorf("Expected only validator 2 for operator:b, got %+v", filtered.Validators)
+	}
+}
+
+func TestRingReuse(t *testing.T) {
+	tracker := New(4)
+
+	tracker.Record(1, nil, 1, true)
+	// Epoch 5 reuses epoch 1's ring position, which must not leak the old miss
+	tracker.Record(1, nil, 5, false)
+	// Too old for the window
+	tracker.Record(1, nil, 1, true)
+
+	heatmap := tracker.Snapshot(0, "")
+	if heatmap.StartEpoch != 2 || heatmap.EndEpoch != 5 {
+		t.Fatalf("Unexpected window: %d-%d", heatmap.StartEpoch, heatmap.EndEpoch)
+	}
+	row := heatmap.Validators[0]
+	if row.Duties != "0x08" || row.Missed != "0x00" {
+		t.Errorf("Expected only the epoch 5 duty, got %+v", row)
+	}
+
+	tracker.Retain(func(models.ValidatorIndex) bool { return false })
+	if len(tracker.Snapshot(0, "").Validators) != 0 {
+		t.Error("Expected Retain to drop the validator")
+	}
+}
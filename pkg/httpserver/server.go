@@ -0,0 +1,93 @@
+package httpserver
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// realm is announced to clients that must authenticate
+const realm = "eth-validator-watcher"
+
+// Validate checks the TLS and authentication settings of the HTTP server
+func Validate(cfg models.HTTPServer) error {
+	if (cfg.TLSCertFile == "") != (cfg.TLSKeyFile == "") {
+		return fmt.Errorf("tls_cert_file and tls_key_file must be set together")
+	}
+	if cfg.BasicAuth.Password != "" && cfg.BasicAuth.Username == "" {
+		return fmt.Errorf("basic_auth.password needs a username")
+	}
+	for i, path := range cfg.PublicPaths {
+		if !strings.HasPrefix(path, "/") {
+			return fmt.Errorf("public_paths[%d] must start with /", i)
+		}
+	}
+	return nil
+}
+
+// Wrap requires the configured basic auth credentials or bearer token on every path but the public
+// ones; without credentials configured the handler is returned as is
+// A username without a password (e.g. ETH_WATCHER_HTTP_PASSWORD unset) rejects every basic auth request
+func Wrap(handler http.Handler, cfg models.HTTPServer) http.Handler {
+	if cfg.BasicAuth.Username == "" && cfg.BearerToken == "" {
+		return handler
+	}
+
+	public := make(map[string]bool, len(cfg.PublicPaths))
+	for _, path := range cfg.PublicPaths {
+		public[path] = true
+	}
+	challenge := `Bearer realm="` + realm + `"`
+	if cfg.BasicAuth.Username != "" {
+		challenge = `Basic realm="` + realm + `", charset="UTF-8"`
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if public[r.URL.Path] || authorized(r, cfg) {
+			handler.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set("WWW-Authenticate", challenge)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	})
+}
+
+// authorized reports whether a request carries the basic auth credentials or the bearer token
+func authorized(r *http.Request, cfg models.HTTPServer) bool {
+	if cfg.BasicAuth.Username != "" && cfg.BasicAuth.Password != "" {
+		if user, password, ok := r.BasicAuth(); ok &&
+			equal(user, cfg.BasicAuth.Username) && equal(password, cfg.BasicAuth.Password) {
+			return true
+		}
+	}
+	if cfg.BearerToken != "" {
+		scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+		if ok && strings.EqualFold(scheme, "Bearer") && equal(token, cfg.BearerToken) {
+			return true
+		}
+	}
+	return false
+}
+
+// equal compares secrets in constant time, hashing first so their lengths don't leak either
+func equal(given, expected string) bool {
+	g := sha256.Sum256([]byte(given))
+	e := sha256.Sum256([]byte(expected))
+	return subtle.ConstantTimeCompare(g[:], e[:]) == 1
+}
+
+// ListenAndServe serves over TLS when a certificate is configured, plain HTTP otherwise
+func ListenAndServe(server *http.Server, cfg models.HTTPServer) error {
+	if cfg.TLSCertFile == "" {
+		return server.ListenAndServe()
+	}
+	if server.TLSConfig == nil {
+		server.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	return server.ListenAndServeTLS(cfg.TLSCertFile, cfg.TLSKeyFile)
+}
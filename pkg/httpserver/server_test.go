@@ -0,0 +1,91 @@
+package httpserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+func serve(handler http.Handler, path string, setAuth func(*http.Request)) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if setAuth != nil {
+		setAuth(req)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestWrap(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	cfg := models.HTTPServer{
+		BasicAuth:   models.BasicAuth{Username: "prometheus", Password: "s3cret"},
+		BearerToken: "token-1",
+		PublicPaths: []string{"/livez"},
+	}
+	handler := Wrap(ok, cfg)
+
+	tests := []struct {
+		name    string
+		path    string
+		setAuth func(*http.Request)
+		want    int
+	}{
+		{"no credentials", "/metrics", nil, http.StatusUnauthorized},
+		{"basic auth", "/metrics", func(r *http.Request) { r.SetBasicAuth("prometheus", "s3cret") }, http.StatusOK},
+		{"wrong password", "/metrics", func(r *http.Request) { r.SetBasicAuth("prometheus", "guess") }, http.StatusUnauthorized},
+		{"bearer token", "/api/v1/validators", func(r *http.Request) { r.Header.Set("Authorization", "Bearer token-1") }, http.StatusOK},
+		{"wrong token", "/api/v1/validators", func(r *http.Request) { r.Header.Set("Authorization", "Bearer token-2") }, http.StatusUnauthorized},
+		{"public path", "/livez", nil, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(handler, tt.path, tt.setAuth)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if rec.Code == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+				t.Error("401 without a WWW-Authenticate challenge")
+			}
+		})
+	}
+}
+
+func TestWrapWithoutCredentials(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	if rec := serve(Wrap(ok, models.HTTPServer{}), "/metrics", nil); rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200 without configured credentials", rec.Code)
+	}
+}
+
+func TestWrapRejectsUsernameWithoutPassword(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	handler := Wrap(ok, models.HTTPServer{BasicAuth: models.BasicAuth{Username: "prometheus"}})
+	rec := serve(handler, "/metrics", func(r *http.Request) { r.SetBasicAuth("prometheus", "") })
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want 401 with an empty password", rec.Code)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     models.HTTPServer
+		wantErr bool
+	}{
+		{"empty", models.HTTPServer{}, false},
+		{"tls", models.HTTPServer{TLSCertFile: "cert.pem", TLSKeyFile: "key.pem"}, false},
+		{"cert without key", models.HTTPServer{TLSCertFile: "cert.pem"}, true},
+		{"password without username", models.HTTPServer{BasicAuth: models.BasicAuth{Password: "x"}}, true},
+		{"relative public path", models.HTTPServer{PublicPaths: []string{"livez"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Validate(tt.cfg); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
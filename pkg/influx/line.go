@@ -0,0 +1,93 @@
+// Package influx writes epoch-level per-validator and per-label measurements in the InfluxDB line
+// protocol, for long-term history that would be too many series for Prometheus
+package influx
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Point is one line of the line protocol: a measurement's tags and fields at a time
+// Field values are float64, int64, uint64, int, bool or string
+type Point struct {
+	Measurement string
+	Tags        map[string]string
+	Fields      map[string]interface{}
+	Time        time.Time
+}
+
+// Escapers of the line protocol's measurement, tag keys and values, and string field values
+var (
+	measurementEscaper = strings.NewReplacer(",", `\,`, " ", `\ `, "\n", `\n`)
+	tagEscaper         = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `, "\n", `\n`)
+	stringEscaper      = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+)
+
+// Line encodes the point, with sorted tags and fields and a nanosecond timestamp
+// Empty tag values are left out, since the line protocol has no empty tags
+func (p Point) Line() (string, error) {
+	if p.Measurement == "" {
+		return "", fmt.Errorf("point without measurement")
+	}
+	if len(p.Fields) == 0 {
+		return "", fmt.Errorf("point %s without fields", p.Measurement)
+	}
+
+	var b strings.Builder
+	b.WriteString(measurementEscaper.Replace(p.Measurement))
+	for _, key := range sortedKeys(p.Tags) {
+		if p.Tags[key] == "" {
+			continue
+		}
+		b.WriteByte(',')
+		b.WriteString(tagEscaper.Replace(key))
+		b.WriteByte('=')
+		b.WriteString(tagEscaper.Replace(p.Tags[key]))
+	}
+
+	for i, key := range sortedKeys(p.Fields) {
+		if i == 0 {
+			b.WriteByte(' ')
+		} else {
+			b.WriteByte(',')
+		}
+		b.WriteString(tagEscaper.Replace(key))
+		b.WriteByte('=')
+		switch v := p.Fields[key].(type) {
+		case float64:
+			b.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+		case int64:
+			b.WriteString(strconv.FormatInt(v, 10) + "i")
+		case int:
+			b.WriteString(strconv.Itoa(v) + "i")
+		case uint64:
+			// Unsigned integers aren't supported by InfluxDB 1.x; counters never reach 2^63
+			b.WriteString(strconv.FormatUint(v, 10) + "i")
+		case bool:
+			b.WriteString(strconv.FormatBool(v))
+		case string:
+			b.WriteString(`"` + stringEscaper.Replace(v) + `"`)
+		default:
+			return "", fmt.Errorf("field %s of %s has unsupported type %T", key, p.Measurement, v)
+		}
+	}
+
+	if !p.Time.IsZero() {
+		b.WriteByte(' ')
+		b.WriteString(strconv.FormatInt(p.Time.UnixNano(), 10))
+	}
+	return b.String(), nil
+}
+
+// sortedKeys returns a map's keys in order, so lines are stable
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
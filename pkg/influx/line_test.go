@@ -0,0 +1,37 @@
+package influx
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPointLine(t *testing.T) {
+	p := Point{
+		Measurement: "eth_validator",
+		Tags:        map[string]string{"network": "mainnet", "label": "operator:acme corp,eu", "empty": ""},
+		Fields: map[string]interface{}{
+			"balance_gwei":      uint64(32000000000),
+			"rate":              0.975,
+			"validators":        3,
+			"consensus_rewards": int64(-12),
+			"status":            `active "ongoing"`,
+			"slashed":           false,
+		},
+		Time: time.Unix(1700000000, 0),
+	}
+	line, err := p.Line()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `eth_validator,label=operator:acme\ corp\,eu,network=mainnet balance_gwei=32000000000i,consensus_rewards=-12i,rate=0.975,slashed=false,status="active \"ongoing\"",validators=3i 1700000000000000000`
+	if line != expected {
+		t.Errorf("Expected\n%s\ngot\n%s", expected, line)
+	}
+
+	if _, err := (Point{Measurement: "m"}).Line(); err == nil {
+		t.Error("Expected an error for a point without fields")
+	}
+	if _, err := (Point{Measurement: "m", Fields: map[string]interface{}{"x": []int{1}}}).Line(); err == nil {
+		t.Error("Expected an error for an unsupported field type")
+	}
+}
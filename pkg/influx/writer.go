@@ -0,0 +1,141 @@
+package influx
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// queueSize is the number of batches waiting to be written before new ones are dropped
+const queueSize = 16
+
+// maxLinesPerRequest bounds a write request, within the 5000-10000 lines InfluxDB recommends
+const maxLinesPerRequest = 5000
+
+// Writer posts batches of points to a line protocol endpoint in the background
+// Write never blocks: when the endpoint is slow or down, batches are dropped rather than delaying
+// slot processing
+type Writer struct {
+	url        string
+	token      string
+	httpClient *http.Client
+	logger     *logrus.Logger
+
+	mu      sync.Mutex
+	queue   chan []string
+	done    chan struct{}
+	closed  bool
+	dropped uint64
+}
+
+// NewWriter creates a writer for a line protocol write URL and starts posting, e.g.
+// http://influxdb:8086/api/v2/write?org=acme&bucket=validators or http://influxdb:8086/write?db=validators
+// The token is sent as "Authorization: Token <token>"; basic auth can be part of the URL
+func NewWriter(url, token string, timeout time.Duration, logger *logrus.Logger) *Writer {
+	w := &Writer{
+		url:        url,
+		token:      token,
+		httpClient: &http.Client{Timeout: timeout},
+		logger:     logger,
+		queue:      make(chan []string, queueSize),
+		done:       make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// Write encodes points and queues them; points that can't be encoded are skipped and logged
+func (w *Writer) Write(points []Point) {
+	if w == nil || len(points) == 0 {
+		return
+	}
+
+	lines := make([]string, 0, len(points))
+	for _, p := range points {
+		line, err := p.Line()
+		if err != nil {
+			w.logger.WithError(err).Debug("Skipping line protocol point")
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return
+	}
+	select {
+	case w.queue <- lines:
+	default:
+		w.dropped++
+		if w.dropped == 1 || w.dropped%10 == 0 {
+			w.logger.WithField("dropped", w.dropped).Warn("Line protocol write queue full, dropping measurements")
+		}
+	}
+}
+
+// Close writes the queued batches and stops the writer
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.queue)
+	w.mu.Unlock()
+
+	<-w.done
+	return nil
+}
+
+// run posts queued batches until the writer is closed
+func (w *Writer) run() {
+	defer close(w.done)
+
+	for lines := range w.queue {
+		for start := 0; start < len(lines); start += maxLinesPerRequest {
+			end := min(start+maxLinesPerRequest, len(lines))
+			ctx, cancel := context.WithTimeout(context.Background(), w.httpClient.Timeout)
+			err := w.post(ctx, lines[start:end])
+			cancel()
+			if err != nil {
+				w.logger.WithError(err).Warn("Failed to write line protocol measurements")
+				break
+			}
+		}
+	}
+}
+
+// post writes lines in one request
+func (w *Writer) post(ctx context.Context, lines []string) error {
+	body := strings.Join(lines, "\n") + "\n"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewBufferString(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if w.token != "" {
+		req.Header.Set("Authorization", "Token "+w.token)
+	}
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to write measurements: %w", err)
+	}
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("endpoint returned %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package influx
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestWriter(t *testing.T) {
+	var mu sync.Mutex
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("bucket") != "validators" || r.Header.Get("Authorization") != "Token secret" {
+			t.Errorf("Unexpected request %s with %q", r.URL, r.Header.Get("Authorization"))
+		}
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(body))
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	w := NewWriter(server.URL+"/api/v2/write?org=acme&bucket=validators", "secret", time.Second, logger)
+
+	at := time.Unix(1700000000, 0)
+	w.Write([]Point{
+		{Measurement: "eth_label", Tags: map[string]string{"label": "scope:watched"}, Fields: map[string]interface{}{"validators": 2}, Time: at},
+		{Measurement: "invalid"},
+		{Measurement: "eth_label", Tags: map[string]string{"label": "operator:a"}, Fields: map[string]interface{}{"validators": 1}, Time: at},
+	})
+	w.Close()
+	w.Write([]Point{{Measurement: "after_close", Fields: map[string]interface{}{"x": 1}}})
+
+	if len(bodies) != 1 {
+		t.Fatalf("Expected one request, got %d", len(bodies))
+	}
+	lines := strings.Split(strings.TrimSuffix(bodies[0], "\n"), "\n")
+	if len(lines) != 2 || lines[0] != "eth_label,label=scope:watched validators=2i 1700000000000000000" {
+		t.Errorf("Unexpected body %q", bodies[0])
+	}
+}
@@ -0,0 +1,168 @@
+package interchange
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// FormatVersion is the EIP-3076 interchange format version produced by Export
+const FormatVersion = "5"
+
+// DefaultRetentionEpochs keeps about a day of history
+const DefaultRetentionEpochs = 225
+
+// Interchange is an EIP-3076 slashing protection interchange document
+type Interchange struct {
+	Metadata Metadata `json:"metadata"`
+	Data     []Record `json:"data"`
+}
+
+// Metadata identifies the format and chain of an interchange document
+type Metadata struct {
+	InterchangeFormatVersion string `json:"interchange_format_version"`
+	GenesisValidatorsRoot    string `json:"genesis_validators_root"`
+}
+
+// Record is the signing history of one validator
+// Signing roots aren't known from beacon data, so they are omitted (they are optional in EIP-3076)
+type Record struct {
+	Pubkey             string              `json:"pubkey"`
+	SignedBlocks       []SignedBlock       `json:"signed_blocks"`
+	SignedAttestations []SignedAttestation `json:"signed_attestations"`
+}
+
+// SignedBlock is a block proposal seen on chain
+type SignedBlock struct {
+	Slot models.Slot `json:"slot,string"`
+}
+
+// SignedAttestation is the source/target vote of an attestation seen on chain
+type SignedAttestation struct {
+	SourceEpoch models.Epoch `json:"source_epoch,string"`
+	TargetEpoch models.Epoch `json:"target_epoch,string"`
+}
+
+// History records the signing history of watched validators as observed on chain
+type History struct {
+	mu           sync.RWMutex
+	blocks       map[string]map[models.Slot]struct{}
+	attestations map[string]map[SignedAttestation]struct{}
+}
+
+// NewHistory creates an empty history
+func NewHistory() *History {
+	return &History{
+		blocks:       make(map[string]map[models.Slot]struct{}),
+		attestations: make(map[string]map[SignedAttestation]struct{}),
+	}
+}
+
+// RecordBlock records a block proposed by a validator
+func (h *History) RecordBlock(pubkey string, slot models.Slot) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.blocks[pubkey] == nil {
+		h.blocks[pubkey] = make(map[models.Slot]struct{})
+	}
+	h.blocks[pubkey][slot] = struct{}{}
+}
+
+// RecordAttestation records an attestation vote of a validator
+// A validator's attestation is usually included several times; duplicates are kept once
+func (h *History) RecordAttestation(pubkey string, source, target models.Epoch) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.attestations[pubkey] == nil {
+		h.attestations[pubkey] = make(map[SignedAttestation]struct{})
+	}
+	h.attestations[pubkey][SignedAttestation{SourceEpoch: source, TargetEpoch: target}] = struct{}{}
+}
+
+// Prune drops attestations targeting epochs before oldest and blocks of slots before it
+func (h *History) Prune(oldest models.Epoch, slotsPerEpoch uint64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	oldestSlot := models.Slot(uint64(oldest) * slotsPerEpoch)
+	for pubkey, slots := range h.blocks {
+		for slot := range slots {
+			if slot < oldestSlot {
+				delete(slots, slot)
+			}
+		}
+		if len(slots) == 0 {
+			delete(h.blocks, pubkey)
+		}
+	}
+	for pubkey, votes := range h.attestations {
+		for vote := range votes {
+			if vote.TargetEpoch < oldest {
+				delete(votes, vote)
+			}
+		}
+		if len(votes) == 0 {
+			delete(h.attestations, pubkey)
+		}
+	}
+}
+
+// Export builds the interchange document of the given pubkeys, or of every recorded validator if none are given
+// Records and their entries are sorted, so exports of the same history are identical
+func (h *History) Export(genesisValidatorsRoot string, pubkeys []string) *Interchange {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if len(pubkeys) == 0 {
+		seen := make(map[string]bool)
+		for pubkey := range h.blocks {
+			seen[pubkey] = true
+		}
+		for pubkey := range h.attestations {
+			seen[pubkey] = true
+		}
+		for pubkey := range seen {
+			pubkeys = append(pubkeys, pubkey)
+		}
+	} else {
+		pubkeys = append([]string(nil), pubkeys...)
+	}
+	sort.Strings(pubkeys)
+
+	doc := &Interchange{
+		Metadata: Metadata{
+			InterchangeFormatVersion: FormatVersion,
+			GenesisValidatorsRoot:    genesisValidatorsRoot,
+		},
+		Data: make([]Record, 0, len(pubkeys)),
+	}
+
+	for _, pubkey := range pubkeys {
+		record := Record{
+			Pubkey:             pubkey,
+			SignedBlocks:       make([]SignedBlock, 0, len(h.blocks[pubkey])),
+			SignedAttestations: make([]SignedAttestation, 0, len(h.attestations[pubkey])),
+		}
+		for slot := range h.blocks[pubkey] {
+			record.SignedBlocks = append(record.SignedBlocks, SignedBlock{Slot: slot})
+		}
+		for vote := range h.attestations[pubkey] {
+			record.SignedAttestations = append(record.SignedAttestations, vote)
+		}
+
+		sort.Slice(record.SignedBlocks, func(i, j int) bool { return record.SignedBlocks[i].Slot < record.SignedBlocks[j].Slot })
+		sort.Slice(record.SignedAttestations, func(i, j int) bool {
+			a, b := record.SignedAttestations[i], record.SignedAttestations[j]
+			if a.TargetEpoch != b.TargetEpoch {
+				return a.TargetEpoch < b.TargetEpoch
+			}
+			return a.SourceEpoch < b.SourceEpoch
+		})
+
+		doc.Data = append(doc.Data, record)
+	}
+	return doc
+}
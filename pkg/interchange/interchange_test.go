@@ -0,0 +1,65 @@
+package interchange
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestHistoryExport(t *testing.T) {
+	h := NewHistory()
+	h.RecordAttestation("0xbb", 10, 11)
+	h.RecordAttestation("0xbb", 9, 10)
+	h.RecordAttestation("0xbb", 10, 11) // Included twice
+	h.RecordBlock("0xbb", 360)
+	h.RecordAttestation("0xaa", 10, 11)
+
+	doc := h.Export("0x4b36", nil)
+	if doc.Metadata.InterchangeFormatVersion != FormatVersion || doc.Metadata.GenesisValidatorsRoot != "0x4b36" {
+		t.Errorf("Unexpected metadata %+v", doc.Metadata)
+	}
+	if len(doc.Data) != 2 || doc.Data[0].Pubkey != "0xaa" || doc.Data[1].Pubkey != "0xbb" {
+		t.Fatalf("Expected records sorted by pubkey, got %+v", doc.Data)
+	}
+
+	data, err := json.Marshal(doc.Data[1])
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	expected := `{"pubkey":"0xbb","signed_blocks":[{"slot":"360"}],"signed_attestations":[{"source_epoch":"9","target_epoch":"10"},{"source_epoch":"10","target_epoch":"11"}]}`
+	if string(data) != expected {
+		t.Errorf("Expected %s, got %s", expected, data)
+	}
+
+	// Requested pubkeys without history still get an (empty) record
+	doc = h.Export("0x4b36", []string{"0xcc"})
+	if len(doc.Data) != 1 || len(doc.Data[0].SignedAttestations) != 0 {
+		t.Errorf("Expected one empty record, got %+v", doc.Data)
+	}
+	if data, _ := json.Marshal(doc.Data[0]); !strings.Contains(string(data), `"signed_blocks":[]`) {
+		t.Errorf("Expected empty lists rather than null, got %s", data)
+	}
+}
+
+func TestHistoryPrune(t *testing.T) {
+	h := NewHistory()
+	h.RecordBlock("0xaa", 31)
+	h.RecordBlock("0xaa", 32)
+	h.RecordAttestation("0xaa", 0, 0)
+	h.RecordAttestation("0xaa", 0, 1)
+	h.RecordAttestation("0xbb", 0, 0)
+
+	h.Prune(1, 32)
+
+	doc := h.Export("", nil)
+	if len(doc.Data) != 1 {
+		t.Fatalf("Expected validators without recent history to be dropped, got %+v", doc.Data)
+	}
+	record := doc.Data[0]
+	if len(record.SignedBlocks) != 1 || record.SignedBlocks[0].Slot != 32 {
+		t.Errorf("Expected only the block of epoch 1 to remain, got %+v", record.SignedBlocks)
+	}
+	if len(record.SignedAttestations) != 1 || record.SignedAttestations[0].TargetEpoch != 1 {
+		t.Errorf("Expected only the attestation targeting epoch 1 to remain, got %+v", record.SignedAttestations)
+	}
+}
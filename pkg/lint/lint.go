@@ -0,0 +1,217 @@
+// Package lint inspects the watched set for configurations correlated with slashing risk
+package lint
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/alert"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// OfflineLabel marks a key our inventory says isn't running anywhere (decommissioned, migrating, kept as backup)
+// Such a key attesting on-chain means someone else is signing with it, so starting it would double sign
+const OfflineLabel = "offline"
+
+// operatorClass is the label class naming who runs a key
+const operatorClass = "operator"
+
+// Check names
+const (
+	CheckMultipleOperators = "multiple_operators"
+	CheckDuplicateEntry    = "duplicate_entry"
+	CheckOfflineActive     = "offline_active"
+	CheckOfflineLive       = "offline_live"
+)
+
+// Finding is a slashing-risk configuration of one key
+type Finding struct {
+	Severity alert.Severity
+	Check    string
+	Pubkey   string
+	Message  string
+}
+
+// Source is a list of watched keys and where it came from (config file, watched_keys_url, DVT clusters)
+type Source struct {
+	Name string
+	Keys []models.WatchedKey
+}
+
+// CheckKeys looks for keys run by several operators and keys listed more than once in a source
+// A key under two operator labels may be loaded into two validator clients
+func CheckKeys(sources []Source) []Finding {
+	var findings []Finding
+	operators := make(map[string][]string)
+	var order []string
+
+	for _, source := range sources {
+		entries := make(map[string]int)
+		for _, wk := range source.Keys {
+			pubkey := normalize(wk.PublicKey)
+			if _, ok := operators[pubkey]; !ok {
+				operators[pubkey] = nil
+				order = append(order, pubkey)
+			}
+			for _, label := range wk.Labels {
+				// The operator of a hierarchical label is its top level
+				label, _, _ = strings.Cut(label, "/")
+				if strings.HasPrefix(label, operatorClass+":") && !contains(operators[pubkey], label) {
+					operators[pubkey] = append(operators[pubkey], label)
+				}
+			}
+			entries[pubkey]++
+		}
+
+		for pubkey, count := range entries {
+			if count > 1 {
+				findings = append(findings, Finding{
+					Severity: alert.SeverityWarning,
+					Check:    CheckDuplicateEntry,
+					Pubkey:   pubkey,
+					Message:  fmt.Sprintf("listed %d times in %s", count, source.Name),
+				})
+			}
+		}
+	}
+
+	for _, pubkey := range order {
+		if labels := operators[pubkey]; len(labels) > 1 {
+			sort.Strings(labels)
+			findings = append(findings, Finding{
+				Severity: alert.SeverityCritical,
+				Check:    CheckMultipleOperators,
+				Pubkey:   pubkey,
+				Message:  fmt.Sprintf("listed under several operators: %s", strings.Join(labels, ", ")),
+			})
+		}
+	}
+
+	Sort(findings)
+	return findings
+}
+
+// Offline returns the keys labelled offline
+func Offline(keys []models.WatchedKey) []models.WatchedKey {
+	var offline []models.WatchedKey
+	for _, wk := range keys {
+		if contains(wk.Labels, OfflineLabel) {
+			offline = append(offline, wk)
+		}
+	}
+	return offline
+}
+
+// Indices returns the validator indices of the keys among the on-chain records
+func Indices(keys []models.WatchedKey, validators []models.Validator) []models.ValidatorIndex {
+	pubkeys := make(map[string]bool, len(keys))
+	for _, wk := range keys {
+		pubkeys[normalize(wk.PublicKey)] = true
+	}
+
+	var indices []models.ValidatorIndex
+	for _, v := range validators {
+		if pubkeys[normalize(v.Data.Pubkey)] {
+			indices = append(indices, v.Index)
+		}
+	}
+	return indices
+}
+
+// CheckOffline compares the keys labelled offline against their on-chain records
+// An active key is a warning; one the liveness endpoint saw attesting (live) is critical
+func CheckOffline(keys []models.WatchedKey, validators []models.Validator, live map[models.ValidatorIndex]bool) []Finding {
+	byPubkey := make(map[string]models.Validator, len(validators))
+	for _, v := range validators {
+		byPubkey[normalize(v.Data.Pubkey)] = v
+	}
+
+	var findings []Finding
+	for _, wk := range Offline(keys) {
+		pubkey := normalize(wk.PublicKey)
+		v, ok := byPubkey[pubkey]
+		if !ok {
+			continue
+		}
+
+		switch {
+		case live[v.Index]:
+			findings = append(findings, Finding{
+				Severity: alert.SeverityCritical,
+				Check:    CheckOfflineLive,
+				Pubkey:   pubkey,
+				Message:  fmt.Sprintf("marked offline but validator %d is attesting on-chain", v.Index),
+			})
+		case strings.HasPrefix(string(v.Status), "active"):
+			findings = append(findings, Finding{
+				Severity: alert.SeverityWarning,
+				Check:    CheckOfflineActive,
+				Pubkey:   pubkey,
+				Message:  fmt.Sprintf("marked offline but validator %d is %s", v.Index, v.Status),
+			})
+		}
+	}
+
+	Sort(findings)
+	return findings
+}
+
+// Sort orders findings by severity (critical first), check and pubkey
+func Sort(findings []Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if rank(a.Severity) != rank(b.Severity) {
+			return rank(a.Severity) < rank(b.Severity)
+		}
+		if a.Check != b.Check {
+			return a.Check < b.Check
+		}
+		return a.Pubkey < b.Pubkey
+	})
+}
+
+// Report prints one line per finding and returns the number of critical findings
+func Report(w io.Writer, findings []Finding) int {
+	critical := 0
+	for _, f := range findings {
+		if f.Severity == alert.SeverityCritical {
+			critical++
+		}
+		fmt.Fprintf(w, "%-8s %-18s %s: %s\n", strings.ToUpper(string(f.Severity)), f.Check, f.Pubkey, f.Message)
+	}
+	fmt.Fprintf(w, "%d findings, %d critical\n", len(findings), critical)
+	return critical
+}
+
+// rank orders severities, most severe first
+func rank(severity alert.Severity) int {
+	switch severity {
+	case alert.SeverityCritical:
+		return 0
+	case alert.SeverityWarning:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// normalize lowercases a pubkey and adds the 0x prefix
+func normalize(pubkey string) string {
+	pubkey = strings.ToLower(strings.TrimSpace(pubkey))
+	if !strings.HasPrefix(pubkey, "0x") {
+		pubkey = "0x" + pubkey
+	}
+	return pubkey
+}
+
+// contains reports whether labels contains label
+func contains(labels []string, label string) bool {
+	for _, l := range labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package lint
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/alert"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+func TestCheckKeys(t *testing.T) {
+	sources := []Source{
+		{Name: "config", Keys: []models.WatchedKey{
+			{PublicKey: "0xAA", Labels: []string{"operator:acme"}},
+			{PublicKey: "0xbb", Labels: []string{"operator:acme"}},
+			{PublicKey: "bb", Labels: []string{"region:eu"}},
+		}},
+		{Name: "watched_keys_url", Keys: []models.WatchedKey{
+			{PublicKey: "0xaa", Labels: []string{"operator:other"}},
+			{PublicKey: "0xbb", Labels: []string{"operator:acme"}},
+		}},
+	}
+
+	findings := CheckKeys(sources)
+	if len(findings) != 2 {
+		t.Fatalf("CheckKeys() = %+v, want 2 findings", findings)
+	}
+	if f := findings[0]; f.Check != CheckMultipleOperators || f.Pubkey != "0xaa" || f.Severity != alert.SeverityCritical {
+		t.Errorf("findings[0] = %+v, want critical multiple_operators for 0xaa", f)
+	}
+	if f := findings[1]; f.Check != CheckDuplicateEntry || f.Pubkey != "0xbb" || !strings.Contains(f.Message, "config") {
+		t.Errorf("findings[1] = %+v, want duplicate_entry for 0xbb in config", f)
+	}
+}
+
+func TestCheckOffline(t *testing.T) {
+	keys := []models.WatchedKey{
+		{PublicKey: "0xaa", Labels: []string{OfflineLabel}},
+		{PublicKey: "0xbb", Labels: []string{OfflineLabel}},
+		{PublicKey: "0xcc", Labels: []string{OfflineLabel}},
+		{PublicKey: "0xdd", Labels: []string{"operator:acme"}},
+	}
+	validators := make([]models.Validator, 4)
+	for i, status := range []models.ValidatorStatus{models.StatusActiveOngoing, models.StatusActiveOngoing, models.StatusExitedUnslashed, models.StatusActiveOngoing} {
+		validators[i].Index = models.ValidatorIndex(i + 1)
+		validators[i].Status = status
+	}
+	validators[0].Data.Pubkey = "0xaa"
+	validators[1].Data.Pubkey = "0xbb"
+	validators[2].Data.Pubkey = "0xcc"
+	validators[3].Data.Pubkey = "0xdd"
+
+	findings := CheckOffline(keys, validators, map[models.ValidatorIndex]bool{1: true, 4: true})
+	if len(findings) != 2 {
+		t.Fatalf("CheckOffline() = %+v, want 2 findings", findings)
+	}
+	if findings[0].Check != CheckOfflineLive || findings[0].Pubkey != "0xaa" {
+		t.Errorf("findings[0] = %+v, want offline_live for 0xaa", findings[0])
+	}
+	if findings[1].Check != CheckOfflineActive || findings[1].Pubkey != "0xbb" {
+		t.Errorf("findings[1] = %+v, want offline_active for 0xbb", findings[1])
+	}
+
+	var out bytes.Buffer
+	if critical := Report(&out, findings); critical != 1 {
+		t.Errorf("Report() critical = %d, want 1", critical)
+	}
+}
@@ -0,0 +1,65 @@
+package logging
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// Log output formats
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// JSON field names, the same for every line so log pipelines can index them
+const (
+	FieldTime  = "time"
+	FieldLevel = "level"
+	FieldMsg   = "msg"
+)
+
+// NewFormatter returns the formatter of a format
+func NewFormatter(format string) (logrus.Formatter, error) {
+	switch format {
+	case "", FormatText:
+		return &logrus.TextFormatter{FullTimestamp: true}, nil
+	case FormatJSON:
+		return &logrus.JSONFormatter{
+			TimestampFormat: time.RFC3339Nano,
+			FieldMap: logrus.FieldMap{
+				logrus.FieldKeyTime:  FieldTime,
+				logrus.FieldKeyLevel: FieldLevel,
+				logrus.FieldKeyMsg:   FieldMsg,
+			},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown log format %q (text or json)", format)
+	}
+}
+
+// Apply sets the logger's format and output from the config; a non-empty format (the --log-format
+// flag) overrides the configured one
+// Returns the log file to close on shutdown, nil when logging to stderr
+func Apply(logger *logrus.Logger, cfg models.Logging, format string) (*RotatingFile, error) {
+	if format == "" {
+		format = cfg.Format
+	}
+	formatter, err := NewFormatter(format)
+	if err != nil {
+		return nil, err
+	}
+	logger.SetFormatter(formatter)
+
+	if cfg.File == "" {
+		return nil, nil
+	}
+	file, err := OpenRotatingFile(cfg.File, cfg.MaxSizeMB, cfg.MaxBackups)
+	if err != nil {
+		return nil, err
+	}
+	logger.SetOutput(file)
+	return file, nil
+}
@@ -0,0 +1,90 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+func TestRotatingFileRotatesAndKeepsBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "watcher.log")
+	r, err := OpenRotatingFile(path, 0, 2)
+	if err != nil {
+		t.Fatalf("OpenRotatingFile: %v", err)
+	}
+	defer r.Close()
+	r.maxBytes = 10
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if _, err := r.Write([]byte(line)); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+
+	want := map[string]string{
+		path:        "fourth\n",
+		path + ".1": "third\n",
+		path + ".2": "second\n",
+	}
+	for file, content := range want {
+		got, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("ReadFile(%s): %v", file, err)
+		}
+		if string(got) != content {
+			t.Errorf("%s = %q, want %q", filepath.Base(file), got, content)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("backup past max_backups kept: %v", err)
+	}
+}
+
+func TestRotatingFileAppendsToExistingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "watcher.log")
+	if err := os.WriteFile(path, []byte("before\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	r, err := OpenRotatingFile(path, 1, 1)
+	if err != nil {
+		t.Fatalf("OpenRotatingFile: %v", err)
+	}
+	r.Write([]byte("after\n"))
+	r.Close()
+
+	got, _ := os.ReadFile(path)
+	if string(got) != "before\nafter\n" {
+		t.Errorf("file = %q, want both lines", got)
+	}
+}
+
+func TestApplyJSONFormat(t *testing.T) {
+	logger := logrus.New()
+	if _, err := Apply(logger, models.Logging{Format: FormatText}, FormatJSON); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	var buf bytes.Buffer
+	logger.SetOutput(&buf)
+	logger.WithField("slot", 42).Info("processed")
+
+	var line map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("log line is not JSON: %v (%s)", err, buf.String())
+	}
+	for _, key := range []string{FieldTime, FieldLevel, FieldMsg, "slot"} {
+		if _, ok := line[key]; !ok {
+			t.Errorf("log line has no %q field: %s", key, buf.String())
+		}
+	}
+}
+
+func TestApplyRejectsUnknownFormat(t *testing.T) {
+	if _, err := Apply(logrus.New(), models.Logging{Format: "xml"}, ""); err == nil {
+		t.Error("Apply accepted an unknown format")
+	}
+}
@@ -0,0 +1,330 @@
+package membership
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// DefaultRetention is how many recent changes are kept in memory for the API
+const DefaultRetention = 10000
+
+// Kind is the kind of membership change
+type Kind string
+
+const (
+	KindAdded     Kind = "added"     // Validator started carrying the label
+	KindRemoved   Kind = "removed"   // Validator stopped carrying the label or is no longer watched
+	KindActivated Kind = "activated" // Validator entered the active set
+	KindExited    Kind = "exited"    // Validator left the active set
+	KindSlashed   Kind = "slashed"
+	KindWithdrawn Kind = "withdrawn" // Validator balance was fully withdrawn
+)
+
+// Change is one entry of the membership feed
+// Epoch is when the change took effect on chain (activation, exit and withdrawable epochs),
+// or when it was observed for changes the chain doesn't date
+type Change struct {
+	Seq            uint64                 `json:"seq"`
+	Time           time.Time              `json:"time"`
+	Epoch          models.Epoch           `json:"epoch"`
+	ObservedEpoch  models.Epoch           `json:"observed_epoch"`
+	Label          string                 `json:"label"`
+	ValidatorIndex models.ValidatorIndex  `json:"validator_index"`
+	Pubkey         string                 `json:"pubkey"`
+	Kind           Kind                   `json:"kind"`
+	Status         models.ValidatorStatus `json:"status"` // Status after the change
+	Reason         string                 `json:"reason"`
+}
+
+// Member is a watched validator and its labels as of an epoch
+type Member struct {
+	Validator models.Validator // Its index as published, which may be a pseudonym
+	Pubkey    string           // As published, which may be a pseudonym
+	Labels    []string
+}
+
+// memberState is what the feed last recorded about a validator
+type memberState struct {
+	status  models.ValidatorStatus
+	slashed bool
+	labels  map[string]bool
+}
+
+// Feed turns successive observations of the watched validators into an append-only
+// list of membership changes per label, optionally persisted as JSON lines
+type Feed struct {
+	mu     sync.RWMutex
+	state  map[models.ValidatorIndex]*memberState
+	pubkey map[models.ValidatorIndex]string
+	seq    uint64
+	recent []Change
+	keep   int
+
+	file *os.File
+}
+
+// NewFeed creates an in-memory feed keeping the last keep changes
+func NewFeed(keep int) *Feed {
+	return &Feed{
+		state:  make(map[models.ValidatorIndex]*memberState),
+		pubkey: make(map[models.ValidatorIndex]string),
+		keep:   keep,
+	}
+}
+
+// OpenFeed creates a feed persisted to a JSON lines file
+// Existing entries are replayed first, so a restart continues the sequence and only records
+// what changed while the watcher was down. A last line without its newline was torn by a crash
+// mid-write and is dropped; the next observation records its change again
+func OpenFeed(path string, keep int) (*Feed, error) {
+	f := NewFeed(keep)
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to open membership feed: %w", err)
+	}
+	complete := 0 // Length of the newline-terminated lines
+	for line := 1; ; line++ {
+		end := bytes.IndexByte(data[complete:], '\n')
+		if end < 0 {
+			break
+		}
+		entry := data[complete : complete+end]
+		complete += end + 1
+		if len(bytes.TrimSpace(entry)) == 0 {
+			continue
+		}
+		var change Change
+		if err := json.Unmarshal(entry, &change); err != nil {
+			return nil, fmt.Errorf("invalid membership feed entry at line %d: %w", line, err)
+		}
+		f.apply(change)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open membership feed: %w", err)
+	}
+	if complete < len(data) {
+		if err := file.Truncate(int64(complete)); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to drop torn membership feed entry: %w", err)
+		}
+	}
+	f.file = file
+	return f, nil
+}
+
+// Observe records the changes between the last observation and the watched validators
+// as of an epoch. members must be the full watched set: validators missing from it are removed
+func (f *Feed) Observe(epoch models.Epoch, members []Member, now time.Time) ([]Change, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var changes []Change
+	record := func(index models.ValidatorIndex, label string, kind Kind, effective models.Epoch, status models.ValidatorStatus, reason string) {
+		changes = append(changes, Change{
+			Time:           now,
+			Epoch:          effective,
+			ObservedEpoch:  epoch,
+			Label:          label,
+			ValidatorIndex: index,
+			Pubkey:         f.pubkey[index],
+			Kind:           kind,
+			Status:         status,
+			Reason:         reason,
+		})
+	}
+
+	seen := make(map[models.ValidatorIndex]bool, len(members))
+	for _, m := range members {
+		v := m.Validator
+		seen[v.Index] = true
+		f.pubkey[v.Index] = m.Pubkey
+
+		labels := make(map[string]bool, len(m.Labels))
+		for _, label := range m.Labels {
+			labels[label] = true
+		}
+
+		prev, known := f.state[v.Index]
+		if !known {
+			prev = &memberState{status: v.Status, slashed: v.Data.Slashed, labels: map[string]bool{}}
+		}
+
+		for _, label := range sortedLabels(labels) {
+			if !prev.labels[label] {
+				reason := "label added"
+				if !known {
+					reason = "validator watched"
+				}
+				record(v.Index, label, KindAdded, epoch, v.Status, reason)
+			}
+		}
+		for _, label := range sortedLabels(prev.labels) {
+			if !labels[label] {
+				record(v.Index, label, KindRemoved, epoch, v.Status, "label removed")
+			}
+		}
+
+		if known {
+			transition := fmt.Sprintf("%s -> %s", prev.status, v.Status)
+			for _, label := range sortedLabels(labels) {
+				if isPending(prev.status) && isActive(v.Status) {
+					record(v.Index, label, KindActivated, v.Data.ActivationEpoch, v.Status, transition)
+				}
+				if !prev.slashed && v.Data.Slashed {
+					record(v.Index, label, KindSlashed, epoch, v.Status, "slashed on chain")
+				}
+				if isActive(prev.status) && !isActive(v.Status) && !isPending(v.Status) {
+					record(v.Index, label, KindExited, v.Data.ExitEpoch, v.Status, transition)
+				}
+				if prev.status != models.StatusWithdrawalDone && v.Status == models.StatusWithdrawalDone {
+					record(v.Index, label, KindWithdrawn, v.Data.WithdrawableEpoch, v.Status, transition)
+				}
+			}
+		}
+
+		f.state[v.Index] = &memberState{status: v.Status, slashed: v.Data.Slashed, labels: labels}
+	}
+
+	var gone []models.ValidatorIndex
+	for index := range f.state {
+		if !seen[index] {
+			gone = append(gone, index)
+		}
+	}
+	sort.Slice(gone, func(i, j int) bool { return gone[i] < gone[j] })
+	for _, index := range gone {
+		prev := f.state[index]
+		for _, label := range sortedLabels(prev.labels) {
+			record(index, label, KindRemoved, epoch, prev.status, "validator no longer watched")
+		}
+		delete(f.state, index)
+	}
+
+	// A change takes its sequence number only once persisted, so a failed write never leaves a gap;
+	// the unwritten changes are lost until a restart replays the file and records them again
+	for i := range changes {
+		changes[i].Seq = f.seq + 1
+		if err := f.append(changes[i]); err != nil {
+			return changes[:i], fmt.Errorf("failed to append to membership feed: %w", err)
+		}
+		f.seq++
+		f.remember(changes[i])
+	}
+	return changes, nil
+}
+
+// append writes a change to the feed file, if any, truncating what a failed write left behind
+// so later entries don't follow a partial line
+func (f *Feed) append(change Change) error {
+	if f.file == nil {
+		return nil
+	}
+	line, err := json.Marshal(change)
+	if err != nil {
+		return err
+	}
+	info, err := f.file.Stat()
+	if err != nil {
+		return err
+	}
+	if _, err := f.file.Write(append(line, '\n')); err != nil {
+		f.file.Truncate(info.Size())
+		return err
+	}
+	return nil
+}
+
+// apply replays a persisted change into the feed state
+func (f *Feed) apply(change Change) {
+	state, ok := f.state[change.ValidatorIndex]
+	if !ok {
+		state = &memberState{labels: map[string]bool{}}
+		f.state[change.ValidatorIndex] = state
+	}
+	state.status = change.Status
+	switch change.Kind {
+	case KindAdded:
+		state.labels[change.Label] = true
+	case KindRemoved:
+		delete(state.labels, change.Label)
+		if len(state.labels) == 0 {
+			delete(f.state, change.ValidatorIndex)
+		}
+	case KindSlashed:
+		state.slashed = true
+	}
+	f.pubkey[change.ValidatorIndex] = change.Pubkey
+	if change.Seq > f.seq {
+		f.seq = change.Seq
+	}
+	f.remember(change)
+}
+
+// remember keeps a change for the API, dropping the oldest beyond the retention
+func (f *Feed) remember(change Change) {
+	f.recent = append(f.recent, change)
+	if f.keep > 0 && len(f.recent) > f.keep {
+		f.recent = f.recent[len(f.recent)-f.keep:]
+	}
+}
+
+// Since returns up to limit retained changes after seq, oldest first, optionally only of one label
+func (f *Feed) Since(seq uint64, label string, limit int) []Change {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	start := sort.Search(len(f.recent), func(i int) bool { return f.recent[i].Seq > seq })
+	changes := []Change{}
+	for _, change := range f.recent[start:] {
+		if label != "" && change.Label != label {
+			continue
+		}
+		if limit > 0 && len(changes) == limit {
+			break
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// Close closes the feed file, if any
+func (f *Feed) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+	return f.file.Close()
+}
+
+// isActive reports whether a status is in the active set
+func isActive(status models.ValidatorStatus) bool {
+	return strings.HasPrefix(string(status), "active_")
+}
+
+// isPending reports whether a status is waiting for activation
+func isPending(status models.ValidatorStatus) bool {
+	return strings.HasPrefix(string(status), "pending_")
+}
+
+// sortedLabels returns the labels of a set in order
+func sortedLabels(labels map[string]bool) []string {
+	sorted := make([]string, 0, len(labels))
+	for label := range labels {
+		sorted = append(sorted, label)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
@@ -0,0 +1,140 @@
+package membership
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+func member(index models.ValidatorIndex, status models.ValidatorStatus, slashed bool, labels ...string) Member {
+	v := models.Validator{Index: index, Status: status}
+	v.Data.Slashed = slashed
+	v.Data.ActivationEpoch = 10
+	v.Data.ExitEpoch = 20
+	v.Data.WithdrawableEpoch = 30
+	return Member{Validator: v, Pubkey: "0xabc", Labels: labels}
+}
+
+func kinds(changes []Change) []string {
+	var got []string
+	for _, c := range changes {
+		got = append(got, string(c.Kind)+" "+c.Label)
+	}
+	return got
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestFeedObserve(t *testing.T) {
+	feed := NewFeed(DefaultRetention)
+	now := time.Now()
+
+	changes, _ := feed.Observe(5, []Member{member(1, models.StatusPendingQueued, false, "operator:a")}, now)
+	if want := []string{"added operator:a"}; !equal(kinds(changes), want) {
+		t.Errorf("First observation = %v, want %v", kinds(changes), want)
+	}
+
+	changes, _ = feed.Observe(10, []Member{member(1, models.StatusActiveOngoing, false, "operator:b")}, now)
+	if want := []string{"added operator:b", "removed operator:a", "activated operator:b"}; !equal(kinds(changes), want) {
+		t.Errorf("Relabel and activation = %v, want %v", kinds(changes), want)
+	}
+	if changes[2].Epoch != 10 || changes[2].Reason != "pending_queued -> active_ongoing" {
+		t.Errorf("Unexpected activation: %+v", changes[2])
+	}
+
+	changes, _ = feed.Observe(21, []Member{member(1, models.StatusExitedSlashed, true, "operator:b")}, now)
+	if want := []string{"slashed operator:b", "exited operator:b"}; !equal(kinds(changes), want) {
+		t.Errorf("Slashing and exit = %v, want %v", kinds(changes), want)
+	}
+	if changes[1].Epoch != 20 {
+		t.Errorf("Exit dated %d, want the exit epoch 20", changes[1].Epoch)
+	}
+
+	changes, _ = feed.Observe(22, nil, now)
+	if want := []string{"removed operator:b"}; !equal(kinds(changes), want) {
+		t.Errorf("Unwatched = %v, want %v", kinds(changes), want)
+	}
+
+	if got := feed.Since(3, "operator:b", 2); len(got) != 2 || got[0].Seq != 4 || got[1].Kind != KindSlashed {
+		t.Errorf("Since(3) = %+v", got)
+	}
+}
+
+func TestOpenFeedResumes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "membership.jsonl")
+	now := time.Now()
+
+	feed, err := OpenFeed(path, DefaultRetention)
+	if err != nil {
+		t.Fatalf("OpenFeed() error = %v", err)
+	}
+	feed.Observe(5, []Member{member(1, models.StatusPendingQueued, false, "operator:a")}, now)
+	feed.Close()
+
+	feed, err = OpenFeed(path, DefaultRetention)
+	if err != nil {
+		t.Fatalf("OpenFeed() error = %v", err)
+	}
+	defer feed.Close()
+
+	changes, _ := feed.Observe(10, []Member{member(1, models.StatusActiveOngoing, false, "operator:a")}, now)
+	if len(changes) != 1 || changes[0].Kind != KindActivated || changes[0].Seq != 2 {
+		t.Errorf("Expected only the activation as seq 2 after a restart, got %+v", changes)
+	}
+	if got := feed.Since(0, "", 0); len(got) != 2 {
+		t.Errorf("Expected the replayed and new change, got %d", len(got))
+	}
+}
+
+func TestOpenFeedDropsTornLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "membership.jsonl")
+	now := time.Now()
+
+	feed, err := OpenFeed(path, DefaultRetention)
+	if err != nil {
+		t.Fatalf("OpenFeed() error = %v", err)
+	}
+	feed.Observe(5, []Member{member(1, models.StatusActiveOngoing, false, "operator:a")}, now)
+	feed.Close()
+
+	// A crash mid-write leaves a partial last line
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatalf("Failed to open feed: %v", err)
+	}
+	file.WriteString(`{"seq":2,"label":"operator:b","valid`)
+	file.Close()
+
+	feed, err = OpenFeed(path, DefaultRetention)
+	if err != nil {
+		t.Fatalf("Expected the torn line to be dropped, got %v", err)
+	}
+	changes, _ := feed.Observe(6, []Member{member(1, models.StatusActiveOngoing, false, "operator:a", "operator:b")}, now)
+	feed.Close()
+	if len(changes) != 1 || changes[0].Seq != 2 {
+		t.Fatalf("Expected the change recorded again as seq 2, got %+v", changes)
+	}
+
+	// The file is valid again
+	feed, err = OpenFeed(path, DefaultRetention)
+	if err != nil {
+		t.Fatalf("OpenFeed() error = %v", err)
+	}
+	defer feed.Close()
+	if got := feed.Since(0, "", 0); len(got) != 2 {
+		t.Errorf("Expected both persisted changes, got %d", len(got))
+	}
+}
@@ -0,0 +1,189 @@
+package metrics
+
+import (
+	"hash/maphash"
+	"math"
+	"runtime"
+	"sync"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/validator"
+)
+
+// Aggregator computes metrics by label like ComputeMetrics, reusing its buffers across calls
+// Slots where no validator changed return the previous result without recomputing it
+type Aggregator struct {
+	mu      sync.Mutex
+	classes LabelClasses
+	seed    maphash.Seed
+
+	workers     []map[string]*MetricsByLabel // Per-worker partial metrics, reset and reused every computation
+	fingerprint uint64
+	result      map[string]*MetricsByLabel // Last result, returned while the fingerprint is unchanged
+}
+
+// NewAggregator creates an aggregator for the selected label classes (nil selects all)
+func NewAggregator(classes LabelClasses) *Aggregator {
+	numWorkers := runtime.NumCPU()
+	if numWorkers < 1 {
+		numWorkers = 1
+	}
+
+	workers := make([]map[string]*MetricsByLabel, numWorkers)
+	for i := range workers {
+		workers[i] = make(map[string]*MetricsByLabel)
+	}
+
+	return &Aggregator{
+		classes: classes,
+		seed:    maphash.MakeSeed(),
+		workers: workers,
+	}
+}
+
+// Compute returns the metrics of the validators grouped by label
+// The result is shared with later calls that see the same validators, so callers must not modify it
+func (a *Aggregator) Compute(validators []*validator.WatchedValidator) map[string]*MetricsByLabel {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	fingerprint := a.fingerprintOf(validators)
+	if a.result != nil && fingerprint == a.fingerprint {
+		return a.result
+	}
+
+	// Split validators into chunks for parallel processing
+	chunkSize := (len(validators) + len(a.workers) - 1) / len(a.workers)
+
+	var wg sync.WaitGroup
+	for i, local := range a.workers {
+		start := i * chunkSize
+		end := start + chunkSize
+		if start > len(validators) {
+			start = len(validators)
+		}
+		if end > len(validators) {
+			end = len(validators)
+		}
+
+		wg.Add(1)
+		go func(local map[string]*MetricsByLabel, chunk []*validator.WatchedValidator) {
+			defer wg.Done()
+			a.aggregate(local, chunk)
+		}(local, validators[start:end])
+	}
+	wg.Wait()
+
+	// The result is published (API, Prometheus), so it gets fresh storage; only the partials are reused
+	result := make(map[string]*MetricsByLabel, len(a.result))
+	for _, local := range a.workers {
+		for label, metrics := range local {
+			fm, ok := result[label]
+			if !ok {
+				fm = newMetricsByLabel(label)
+				result[label] = fm
+			}
+			mergeMetrics(fm, metrics)
+		}
+	}
+	calculateRates(result)
+
+	a.fingerprint = fingerprint
+	a.result = result
+	return result
+}
+
+// aggregate computes one worker's partial metrics, dropping labels its chunk no longer has
+func (a *Aggregator) aggregate(local map[string]*MetricsByLabel, chunk []*validator.WatchedValidator) {
+	for _, metrics := range local {
+		resetMetrics(metrics)
+	}
+
+	for _, v := range chunk {
+		// Per-validator values are the same for every label
+		validatorType := getValidatorType(v.Data.WithdrawalCredentials)
+
+		for _, label := range v.Labels {
+			if !a.classes.Aggregates(label) {
+				continue
+			}
+
+			metrics, ok := local[label]
+			if !ok {
+				metrics = newMetricsByLabel(label)
+				local[label] = metrics
+			}
+			addValidator(metrics, v, validatorType)
+		}
+	}
+
+	for label, metrics := range local {
+		if metrics.ValidatorCount == 0 {
+			delete(local, label)
+		}
+	}
+}
+
+// fingerprintOf hashes everything Compute reads from the validators
+// Per-validator hashes are summed, so the order GetAll returns validators in doesn't matter
+func (a *Aggregator) fingerprintOf(validators []*validator.WatchedValidator) uint64 {
+	var h maphash.Hash
+	h.SetSeed(a.seed)
+
+	sum := uint64(len(validators))
+	for _, v := range validators {
+		h.Reset()
+		writeUint64(&h, uint64(v.Index))
+		h.WriteString(string(v.Status))
+		h.WriteString(v.Data.Pubkey)
+		h.WriteString(v.Data.WithdrawalCredentials)
+		writeUint64(&h, math.Float64bits(v.Weight))
+		if v.Data.Slashed {
+			h.WriteByte(1)
+		} else {
+			h.WriteByte(0)
+		}
+		for _, label := range v.Labels {
+			h.WriteString(label)
+			h.WriteByte(0)
+		}
+
+		for _, counter := range [...]uint64{
+			uint64(v.Balance),
+			uint64(v.Data.EffectiveBalance),
+			v.MissedAttestations,
+			v.SuboptimalSourceVotes,
+			v.SuboptimalTargetVotes,
+			v.SuboptimalHeadVotes,
+			uint64(v.IdealConsensusRewards),
+			uint64(v.ConsensusRewards),
+			v.ProposedBlocks,
+			v.ProposedBlocksFinalized,
+			v.MissedBlocks,
+			v.MissedBlocksFinalized,
+			v.FutureBlockProposals,
+			v.AttestationDuties,
+			v.AttestationDutiesSuccess,
+			v.ConsecutiveMissedAttest,
+			math.Float64bits(v.ExpectedAggregations),
+			v.CommitteeAggregatesIncluded,
+			v.CommitteeAggregatesMissed,
+			v.InclusionDelaySum,
+			v.InclusionDelayCount,
+			v.MaxInclusionDelay,
+		} {
+			writeUint64(&h, counter)
+		}
+
+		sum += h.Sum64()
+	}
+	return sum
+}
+
+// writeUint64 hashes a number without allocating
+func writeUint64(h *maphash.Hash, n uint64) {
+	var buf [8]byte
+	for i := range buf {
+		buf[i] = byte(n >> (8 * i))
+	}
+	h.Write(buf[:])
+}
@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"math"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// secondsPerYear is the year rewards are annualized over
+const secondsPerYear = 365.25 * 24 * 60 * 60
+
+// DefaultBaseRewardFactor is BASE_REWARD_FACTOR on Ethereum
+const DefaultBaseRewardFactor = 64
+
+// attestationWeight is the share of the base reward paid for timely source, target and head votes:
+// (TIMELY_SOURCE_WEIGHT + TIMELY_TARGET_WEIGHT + TIMELY_HEAD_WEIGHT) / WEIGHT_DENOMINATOR
+const attestationWeight = float64(14+26+14) / 64
+
+// activeStatuses are the statuses whose effective balance counts towards the total active balance
+var activeStatuses = []models.ValidatorStatus{models.StatusActiveOngoing, models.StatusActiveExiting, models.StatusActiveSlashed}
+
+// EpochsPerYear returns how many epochs of a duration fit in a year
+func EpochsPerYear(epoch time.Duration) float64 {
+	if epoch <= 0 {
+		return 0
+	}
+	return secondsPerYear / epoch.Seconds()
+}
+
+// EstimatedAPR annualizes one epoch's attestation rewards over the effective balance that earned them
+func EstimatedAPR(rewards models.SignedGwei, balance models.Gwei, epochsPerYear float64) float64 {
+	if balance == 0 {
+		return 0
+	}
+	return float64(rewards) * epochsPerYear / float64(balance)
+}
+
+// BaselineAPR returns the yearly attestation rewards of a validator attesting perfectly, from the
+// network's total active balance, on the same basis as EstimatedAPR: every epoch pays
+// BASE_REWARD_FACTOR / sqrt(total active balance) per Gwei of effective balance, of which attestations
+// earn the source, target and head weights; proposals and sync committees earn the rest
+func BaselineAPR(totalActiveBalance models.Gwei, baseRewardFactor uint64, epochsPerYear float64) float64 {
+	if totalActiveBalance == 0 {
+		return 0
+	}
+	return float64(baseRewardFactor) * attestationWeight * epochsPerYear / math.Sqrt(float64(totalActiveBalance))
+}
+
+// ActiveBalance returns the effective balance of a label's active validators
+func (m *MetricsByLabel) ActiveBalance(stakeUnit models.Gwei) models.Gwei {
+	var stake float64
+	for _, status := range activeStatuses {
+		stake += m.StatusStakes[status]
+	}
+	return models.Gwei(stake * float64(stakeUnit))
+}
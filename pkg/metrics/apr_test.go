@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+func TestEstimatedAPR(t *testing.T) {
+	epochsPerYear := EpochsPerYear(384 * time.Second)
+	if math.Abs(epochsPerYear-82181.25) > 0.01 {
+		t.Errorf("EpochsPerYear() = %f", epochsPerYear)
+	}
+
+	// 10,000 Gwei per epoch on 32 ETH is about 2.57% a year
+	if apr := EstimatedAPR(10_000, 32_000_000_000, epochsPerYear); math.Abs(apr-0.02568) > 0.0001 {
+		t.Errorf("EstimatedAPR() = %f", apr)
+	}
+	if apr := EstimatedAPR(-10_000, 32_000_000_000, epochsPerYear); apr >= 0 {
+		t.Errorf("Expected penalties to give a negative APR, got %f", apr)
+	}
+
+	// 34M ETH active: about 2.41% from attestations, 54/64 of the 2.85% consensus issuance
+	if apr := BaselineAPR(34_000_000_000_000_000, DefaultBaseRewardFactor, epochsPerYear); math.Abs(apr-0.02407) > 0.0001 {
+		t.Errorf("BaselineAPR() = %f", apr)
+	}
+}
+
+func TestActiveBalance(t *testing.T) {
+	m := NewNetworkMetrics()
+	m.StatusStakes[models.StatusActiveOngoing] = 2
+	m.StatusStakes[models.StatusActiveExiting] = 1
+	m.StatusStakes[models.StatusPendingQueued] = 5
+
+	if got := m.ActiveBalance(models.DefaultStakeUnit); got != 96_000_000_000 {
+		t.Errorf("ActiveBalance() = %d, expected only active stake", got)
+	}
+}
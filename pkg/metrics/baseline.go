@@ -0,0 +1,36 @@
+package metrics
+
+import "github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+
+// NetworkBaseline is the attestation performance of a random sample of active network validators in
+// one epoch, read from their attestation rewards
+type NetworkBaseline struct {
+	Epoch            models.Epoch
+	Validators       int // Sampled validators the rewards covered
+	Participating    int // Sampled validators with a timely source vote
+	SuboptimalSource int
+	SuboptimalTarget int
+	SuboptimalHead   int
+	IdealRewards     models.Gwei
+	ActualRewards    models.SignedGwei
+}
+
+// WithBaseline returns a copy of network-wide metrics with a sampled attestation performance: the
+// duty rate is the participation rate, the suboptimal vote and consensus rewards rates are the sample's
+// The counters stay untouched, so counter windows keep meaning cumulative network totals
+func (m *MetricsByLabel) WithBaseline(b *NetworkBaseline) *MetricsByLabel {
+	network := *m
+	if b == nil || b.Validators == 0 {
+		return &network
+	}
+
+	network.SampledDuties = uint64(b.Validators)
+	network.SuboptimalSourceVotes = uint64(b.SuboptimalSource)
+	network.SuboptimalTargetVotes = uint64(b.SuboptimalTarget)
+	network.SuboptimalHeadVotes = uint64(b.SuboptimalHead)
+	network.AttestationDutiesRate = float64(b.Participating) / float64(b.Validators)
+	if b.IdealRewards > 0 {
+		network.ConsensusRewardsRate = float64(b.ActualRewards) / float64(b.IdealRewards)
+	}
+	return &network
+}
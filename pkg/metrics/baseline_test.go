@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestNetworkBaseline(t *testing.T) {
+	network := NewNetworkMetrics()
+	network.ValidatorCount = 1000
+
+	sampled := network.WithBaseline(&NetworkBaseline{
+		Epoch:            10,
+		Validators:       200,
+		Participating:    190,
+		SuboptimalSource: 10,
+		SuboptimalTarget: 12,
+		SuboptimalHead:   30,
+		IdealRewards:     2000,
+		ActualRewards:    1900,
+	})
+	if network.SampledDuties != 0 || network.ConsensusRewardsRate != 0 {
+		t.Error("Expected the network metrics not to be modified")
+	}
+	if sampled.ValidatorCount != 1000 || sampled.AttestationDuties != 0 {
+		t.Errorf("Expected counts and counters to be kept, got %d validators and %d duties", sampled.ValidatorCount, sampled.AttestationDuties)
+	}
+	if sampled.AttestationDutiesRate != 0.95 || sampled.ConsensusRewardsRate != 0.95 {
+		t.Errorf("Expected 0.95 participation and rewards rates, got %v and %v", sampled.AttestationDutiesRate, sampled.ConsensusRewardsRate)
+	}
+
+	m := NewPrometheusMetrics(prometheus.NewRegistry())
+	m.UpdateMetrics(map[string]*MetricsByLabel{"scope:all-network": sampled}, 100, 3, "mainnet")
+	if rate := testutil.ToFloat64(m.SuboptimalHeadsRate.WithLabelValues("scope:all-network", "mainnet")); rate != 0.15 {
+		t.Errorf("Expected a 0.15 network suboptimal head rate, got %v", rate)
+	}
+	if rate := testutil.ToFloat64(m.DutiesRate.WithLabelValues("scope:all-network", "mainnet")); rate != 0.95 {
+		t.Errorf("Expected a 0.95 network duty rate, got %v", rate)
+	}
+
+	if unchanged := network.WithBaseline(nil); unchanged.SampledDuties != 0 {
+		t.Error("Expected no baseline to leave the network metrics as they are")
+	}
+}
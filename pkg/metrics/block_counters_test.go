@@ -0,0 +1,36 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestRestoreBlockCounters(t *testing.T) {
+	first := NewPrometheusMetrics(prometheus.NewRegistry())
+	first.UpdateMetrics(map[string]*MetricsByLabel{
+		"scope:watched": {Label: "scope:watched", ProposedBlocks: 2, MissedBlocks: 1},
+	}, 100, 3, "mainnet")
+
+	state := first.BlockCounterState("mainnet")
+
+	// After a restart the same epoch's counters are seen again and must not be added twice
+	restarted := NewPrometheusMetrics(prometheus.NewRegistry())
+	restarted.RestoreBlockCounters("mainnet", state)
+	restarted.UpdateMetrics(map[string]*MetricsByLabel{
+		"scope:watched": {Label: "scope:watched", ProposedBlocks: 3, MissedBlocks: 1},
+	}, 101, 3, "mainnet")
+
+	if value := testutil.ToFloat64(restarted.BlockProposalsHeadTotal.WithLabelValues("scope:watched", "mainnet")); value != 3 {
+		t.Errorf("Expected 3 proposed blocks, got %v", value)
+	}
+	if value := testutil.ToFloat64(restarted.MissedBlockProposalsHeadTotal.WithLabelValues("scope:watched", "mainnet")); value != 1 {
+		t.Errorf("Expected 1 missed block, got %v", value)
+	}
+
+	totals := restarted.BlockCounterState("mainnet")["scope:watched"].Totals
+	if totals.ProposedHead != 3 || totals.MissedHead != 1 {
+		t.Errorf("Expected totals 3/1, got %+v", totals)
+	}
+}
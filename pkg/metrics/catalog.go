@@ -0,0 +1,131 @@
+package metrics
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// Metric types of the catalog
+const (
+	TypeGauge     = "gauge"
+	TypeCounter   = "counter"
+	TypeHistogram = "histogram"
+)
+
+// Info describes an exported metric
+type Info struct {
+	Name   string
+	Help   string
+	Type   string
+	Labels []string // Variable labels, network included
+}
+
+// HasLabel reports whether the metric has a variable label
+func (i Info) HasLabel(name string) bool {
+	for _, label := range i.Labels {
+		if label == name {
+			return true
+		}
+	}
+	return false
+}
+
+// catalogRegisterer keeps the collectors registered with it instead of serving them
+type catalogRegisterer struct {
+	collectors []prometheus.Collector
+}
+
+func (r *catalogRegisterer) Register(c prometheus.Collector) error {
+	r.collectors = append(r.collectors, c)
+	return nil
+}
+
+func (r *catalogRegisterer) MustRegister(cs ...prometheus.Collector) {
+	r.collectors = append(r.collectors, cs...)
+}
+
+func (r *catalogRegisterer) Unregister(prometheus.Collector) bool {
+	return false
+}
+
+// maxCatalogLabels bounds the label values tried on a vector before giving up on it
+const maxCatalogLabels = 16
+
+// Catalog lists the watcher's metrics and those of the given collectors, sorted by name, as a
+// registry gathers them once every vector has a series; the Go runtime and process collectors are
+// left out, and the given collectors must export their series, e.g. for a client endpoint or a cache
+func Catalog(collectors ...prometheus.Collector) ([]Info, error) {
+	registerer := &catalogRegisterer{}
+	NewPrometheusMetrics(registerer)
+
+	registry := prometheus.NewRegistry()
+	for _, c := range registerer.collectors {
+		switch c.(type) {
+		case prometheus.Gauge, prometheus.Counter, prometheus.Histogram:
+		case *prometheus.GaugeVec, *prometheus.CounterVec, *prometheus.HistogramVec:
+		default:
+			continue
+		}
+		collectors = append(collectors, c)
+	}
+	for _, c := range collectors {
+		if err := addSeries(c); err != nil {
+			return nil, err
+		}
+		if err := registry.Register(c); err != nil {
+			return nil, err
+		}
+	}
+
+	families, err := registry.Gather()
+	if err != nil {
+		return nil, err
+	}
+	var infos []Info
+	for _, family := range families {
+		info := Info{Name: family.GetName(), Help: family.GetHelp()}
+		switch family.GetType() {
+		case dto.MetricType_GAUGE:
+			info.Type = TypeGauge
+		case dto.MetricType_COUNTER:
+			info.Type = TypeCounter
+		case dto.MetricType_HISTOGRAM:
+			info.Type = TypeHistogram
+		default:
+			continue
+		}
+		for _, pair := range family.GetMetric()[0].GetLabel() {
+			info.Labels = append(info.Labels, pair.GetName())
+		}
+		infos = append(infos, info)
+	}
+	// Gather sorts the families by name
+	return infos, nil
+}
+
+// addSeries gives a metric vector a series, trying one more placeholder label value until the vector
+// takes them, so it is gathered with its labels; other collectors are left as is
+func addSeries(c prometheus.Collector) error {
+	var with func(values ...string) error
+	switch v := c.(type) {
+	case *prometheus.GaugeVec:
+		with = func(values ...string) error { _, err := v.GetMetricWithLabelValues(values...); return err }
+	case *prometheus.CounterVec:
+		with = func(values ...string) error { _, err := v.GetMetricWithLabelValues(values...); return err }
+	case *prometheus.HistogramVec:
+		with = func(values ...string) error { _, err := v.GetMetricWithLabelValues(values...); return err }
+	default:
+		return nil
+	}
+
+	var values []string
+	for len(values) <= maxCatalogLabels {
+		if with(values...) == nil {
+			return nil
+		}
+		values = append(values, "catalog")
+	}
+	return fmt.Errorf("no label values accepted by a metric vector after %d", maxCatalogLabels)
+}
@@ -0,0 +1,36 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/cache"
+)
+
+func TestCatalog(t *testing.T) {
+	caches := cache.NewCollector(cache.New[int, int]("test", 1, 0))
+	catalog, err := Catalog(caches)
+	if err != nil {
+		t.Fatalf("Catalog() error = %v", err)
+	}
+
+	byName := make(map[string]Info, len(catalog))
+	for _, info := range catalog {
+		byName[info.Name] = info
+	}
+	missed, ok := byName["eth_missed_attestations"]
+	if !ok || missed.Type != TypeGauge || !missed.HasLabel("scope") || !missed.HasLabel("network") {
+		t.Errorf("Unexpected eth_missed_attestations: %+v", missed)
+	}
+	if missed.Help == "" {
+		t.Error("Expected the help of eth_missed_attestations")
+	}
+	if reorgs := byName["eth_reorg_events_total"]; reorgs.Type != TypeCounter {
+		t.Errorf("Expected eth_reorg_events_total to be a counter, got %+v", reorgs)
+	}
+	if evictions := byName["eth_cache_evictions_total"]; evictions.Type != TypeCounter || !evictions.HasLabel("cache") || !evictions.HasLabel("reason") {
+		t.Errorf("Expected the cache collector's metrics, got %+v", evictions)
+	}
+	if _, ok := byName["go_goroutines"]; ok {
+		t.Error("Expected the Go runtime metrics to be left out")
+	}
+}
@@ -1,9 +1,7 @@
 package metrics
 
 import (
-	"runtime"
 	"strings"
-	"sync"
 
 	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
 	"github.com/enriquemanuel/eth-validator-watcher/pkg/validator"
@@ -69,6 +67,22 @@ type MetricsByLabel struct {
 	AttestationDutiesSuccess uint64
 	AttestationDutiesRate    float64
 	AttestationDutiesStake   float64 // Stake-weighted duties
+	SampledDuties            uint64  // Network-wide only: sampled duties the suboptimal votes are out of (see NetworkBaseline)
+
+	// Aggregation duties
+	ExpectedAggregations        float64 // Expected aggregator selections (probability-weighted)
+	CommitteeAggregatesIncluded uint64  // Duties whose committee aggregate reached the chain
+	CommitteeAggregatesMissed   uint64  // Duties whose committee aggregate never reached the chain
+
+	// Attestation inclusion delay (slots from the attestation to its first inclusion, 1 is optimal)
+	InclusionDelaySum   uint64
+	InclusionDelayCount uint64
+	InclusionDelayAvg   float64
+	MaxInclusionDelay   uint64
+
+	// Sync committee participation (blocks signed or missed by the members)
+	SyncCommitteeSigned uint64
+	SyncCommitteeMissed uint64
 
 	// Status breakdown
 	StatusCounts map[models.ValidatorStatus]int
@@ -82,6 +96,12 @@ type MetricsByLabel struct {
 	SlashedCount int
 	SlashedStake float64
 
+	// Balances of the validators that still hold one (not yet withdrawn)
+	FundedCount      int
+	Balance          models.Gwei
+	EffectiveBalance models.Gwei
+	RewardedBalance  models.Gwei // Effective balance of the validators with rewards in the last processed epoch
+
 	// Consecutive missed attestations
 	MaxConsecutiveMissed       uint64  // Max consecutive missed
 	MaxConsecutiveMissedStake  float64 // Stake-weighted max consecutive missed
@@ -103,298 +123,310 @@ type ValidatorDetail struct {
 
 // ComputeMetrics computes metrics for all validators grouped by labels
 // Uses concurrent processing for performance with large validator sets
-func ComputeMetrics(validators []*validator.WatchedValidator, slot models.Slot) map[string]*MetricsByLabel {
-	numWorkers := runtime.NumCPU()
-	if numWorkers < 1 {
-		numWorkers = 1
+// Only labels of the selected classes are aggregated (nil selects all)
+func ComputeMetrics(validators []*validator.WatchedValidator, slot models.Slot, classes LabelClasses) map[string]*MetricsByLabel {
+	return NewAggregator(classes).Compute(validators)
+}
+
+// newMetricsByLabel creates empty metrics for a label
+func newMetricsByLabel(label string) *MetricsByLabel {
+	return &MetricsByLabel{
+		Label:               label,
+		StatusCounts:        make(map[models.ValidatorStatus]int),
+		StatusStakes:        make(map[models.ValidatorStatus]float64),
+		ValidatorTypeCounts: make(map[string]int),
+		ValidatorTypeStakes: make(map[string]float64),
 	}
+}
 
-	// Split validators into chunks for parallel processing
-	chunkSize := (len(validators) + numWorkers - 1) / numWorkers
+// reset empties the metrics for reuse, keeping the allocated maps and detail slices
+func resetMetrics(metrics *MetricsByLabel) {
+	statusCounts, statusStakes := metrics.StatusCounts, metrics.StatusStakes
+	typeCounts, typeStakes := metrics.ValidatorTypeCounts, metrics.ValidatorTypeStakes
+	missed, source, target, head, blocks := metrics.MissedAttestationDetails, metrics.SuboptimalSourceDetails,
+		metrics.SuboptimalTargetDetails, metrics.SuboptimalHeadDetails, metrics.MissedBlockDetails
+
+	clear(statusCounts)
+	clear(statusStakes)
+	clear(typeCounts)
+	clear(typeStakes)
+	*metrics = MetricsByLabel{
+		Label:                    metrics.Label,
+		StatusCounts:             statusCounts,
+		StatusStakes:             statusStakes,
+		ValidatorTypeCounts:      typeCounts,
+		ValidatorTypeStakes:      typeStakes,
+		MissedAttestationDetails: missed[:0],
+		SuboptimalSourceDetails:  source[:0],
+		SuboptimalTargetDetails:  target[:0],
+		SuboptimalHeadDetails:    head[:0],
+		MissedBlockDetails:       blocks[:0],
+	}
+}
 
-	type workerResult struct {
-		metrics map[string]*MetricsByLabel
+// add aggregates one validator into the label's metrics
+func addValidator(metrics *MetricsByLabel, v *validator.WatchedValidator, validatorType string) {
+	// Always count all validators for status breakdown
+	metrics.ValidatorCount++
+	metrics.StakeCount += v.Weight
+	metrics.StatusCounts[v.Status]++
+	metrics.StatusStakes[v.Status] += v.Weight
+
+	// Track validator type from withdrawal credentials
+	metrics.ValidatorTypeCounts[validatorType]++
+	metrics.ValidatorTypeStakes[validatorType] += v.Weight
+
+	// Track slashed validators
+	if v.Data.Slashed {
+		metrics.SlashedCount++
+		metrics.SlashedStake += v.Weight
 	}
+	metrics.addBalance(&v.Validator)
 
-	resultsChan := make(chan workerResult, numWorkers)
-	var wg sync.WaitGroup
+	// Track max consecutive missed attestations
+	if v.ConsecutiveMissedAttest > metrics.MaxConsecutiveMissed {
+		metrics.MaxConsecutiveMissed = v.ConsecutiveMissedAttest
+	}
+	consecStakeWeighted := float64(v.ConsecutiveMissedAttest) * v.Weight
+	if consecStakeWeighted > metrics.MaxConsecutiveMissedStake {
+		metrics.MaxConsecutiveMissedStake = consecStakeWeighted
+	}
 
-	// Process chunks in parallel
-	for i := 0; i < numWorkers; i++ {
-		start := i * chunkSize
-		if start >= len(validators) {
-			break
-		}
+	// Performance counters only grow while a validator owes duties (see duties.Liability), so they
+	// are aggregated whatever its status: dropping them at the exit would make label totals go backwards
+	metrics.MissedAttestations += v.MissedAttestations
+	metrics.MissedAttestationsStake += float64(v.MissedAttestations) * v.Weight
+	metrics.SuboptimalSourceVotes += v.SuboptimalSourceVotes
+	metrics.SuboptimalSourceVotesStake += float64(v.SuboptimalSourceVotes) * v.Weight
+	metrics.SuboptimalTargetVotes += v.SuboptimalTargetVotes
+	metrics.SuboptimalTargetVotesStake += float64(v.SuboptimalTargetVotes) * v.Weight
+	metrics.SuboptimalHeadVotes += v.SuboptimalHeadVotes
+	metrics.SuboptimalHeadVotesStake += float64(v.SuboptimalHeadVotes) * v.Weight
+	metrics.MissedBlocksFinalized += v.MissedBlocksFinalized
+	metrics.FutureBlockProposals += v.FutureBlockProposals
+	metrics.IdealConsensusRewards += v.IdealConsensusRewards
+	metrics.ConsensusRewards += v.ConsensusRewards
+	if v.IdealConsensusRewards > 0 {
+		metrics.RewardedBalance += v.Data.EffectiveBalance
+	}
+	metrics.AttestationDuties += v.AttestationDuties
+	metrics.AttestationDutiesSuccess += v.AttestationDutiesSuccess
+	metrics.AttestationDutiesStake += float64(v.AttestationDuties) * v.Weight
+	metrics.ExpectedAggregations += v.ExpectedAggregations
+	metrics.CommitteeAggregatesIncluded += v.CommitteeAggregatesIncluded
+	metrics.CommitteeAggregatesMissed += v.CommitteeAggregatesMissed
+	metrics.InclusionDelaySum += v.InclusionDelaySum
+	metrics.InclusionDelayCount += v.InclusionDelayCount
+	if v.MaxInclusionDelay > metrics.MaxInclusionDelay {
+		metrics.MaxInclusionDelay = v.MaxInclusionDelay
+	}
+	metrics.SyncCommitteeSigned += v.SyncCommitteeSigned
+	metrics.SyncCommitteeMissed += v.SyncCommitteeMissed
+
+	// Block proposals should be counted regardless of validator status
+	// A validator can propose a block even when exiting or in other states
+	metrics.ProposedBlocks += v.ProposedBlocks
+	metrics.ProposedBlocksFinalized += v.ProposedBlocksFinalized
+	metrics.MissedBlocks += v.MissedBlocks
+
+	// Collect details (limited to 5 per label)
+	if v.MissedAttestations > 0 && len(metrics.MissedAttestationDetails) < 5 {
+		metrics.MissedAttestationDetails = append(metrics.MissedAttestationDetails, ValidatorDetail{
+			Index:  v.Index,
+			Pubkey: v.Data.Pubkey,
+			Value:  v.MissedAttestations,
+		})
+	}
+	if v.SuboptimalSourceVotes > 0 && len(metrics.SuboptimalSourceDetails) < 5 {
+		metrics.SuboptimalSourceDetails = append(metrics.SuboptimalSourceDetails, ValidatorDetail{
+			Index:  v.Index,
+			Pubkey: v.Data.Pubkey,
+			Value:  v.SuboptimalSourceVotes,
+		})
+	}
+	if v.SuboptimalTargetVotes > 0 && len(metrics.SuboptimalTargetDetails) < 5 {
+		metrics.SuboptimalTargetDetails = append(metrics.SuboptimalTargetDetails, ValidatorDetail{
+			Index:  v.Index,
+			Pubkey: v.Data.Pubkey,
+			Value:  v.SuboptimalTargetVotes,
+		})
+	}
+	if v.SuboptimalHeadVotes > 0 && len(metrics.SuboptimalHeadDetails) < 5 {
+		metrics.SuboptimalHeadDetails = append(metrics.SuboptimalHeadDetails, ValidatorDetail{
+			Index:  v.Index,
+			Pubkey: v.Data.Pubkey,
+			Value:  v.SuboptimalHeadVotes,
+		})
+	}
+	if v.MissedBlocks > 0 && len(metrics.MissedBlockDetails) < 5 {
+		metrics.MissedBlockDetails = append(metrics.MissedBlockDetails, ValidatorDetail{
+			Index:  v.Index,
+			Pubkey: v.Data.Pubkey,
+			Value:  v.MissedBlocks,
+		})
+	}
+}
 
-		end := start + chunkSize
-		if end > len(validators) {
-			end = len(validators)
-		}
+// mergeMetrics adds a worker's partial metrics into the merged metrics of the same label
+func mergeMetrics(fm, metrics *MetricsByLabel) {
+	// Merge metrics
+	fm.ValidatorCount += metrics.ValidatorCount
+	fm.StakeCount += metrics.StakeCount
+	fm.MissedAttestations += metrics.MissedAttestations
+	fm.MissedAttestationsStake += metrics.MissedAttestationsStake
+	fm.SuboptimalSourceVotes += metrics.SuboptimalSourceVotes
+	fm.SuboptimalSourceVotesStake += metrics.SuboptimalSourceVotesStake
+	fm.SuboptimalTargetVotes += metrics.SuboptimalTargetVotes
+	fm.SuboptimalTargetVotesStake += metrics.SuboptimalTargetVotesStake
+	fm.SuboptimalHeadVotes += metrics.SuboptimalHeadVotes
+	fm.SuboptimalHeadVotesStake += metrics.SuboptimalHeadVotesStake
+	fm.ProposedBlocks += metrics.ProposedBlocks
+	fm.ProposedBlocksFinalized += metrics.ProposedBlocksFinalized
+	fm.MissedBlocks += metrics.MissedBlocks
+	fm.MissedBlocksFinalized += metrics.MissedBlocksFinalized
+	fm.FutureBlockProposals += metrics.FutureBlockProposals
+	fm.IdealConsensusRewards += metrics.IdealConsensusRewards
+	fm.ConsensusRewards += metrics.ConsensusRewards
+	fm.AttestationDuties += metrics.AttestationDuties
+	fm.AttestationDutiesSuccess += metrics.AttestationDutiesSuccess
+	fm.AttestationDutiesStake += metrics.AttestationDutiesStake
+	fm.ExpectedAggregations += metrics.ExpectedAggregations
+	fm.CommitteeAggregatesIncluded += metrics.CommitteeAggregatesIncluded
+	fm.CommitteeAggregatesMissed += metrics.CommitteeAggregatesMissed
+	fm.InclusionDelaySum += metrics.InclusionDelaySum
+	fm.InclusionDelayCount += metrics.InclusionDelayCount
+	if metrics.MaxInclusionDelay > fm.MaxInclusionDelay {
+		fm.MaxInclusionDelay = metrics.MaxInclusionDelay
+	}
+	fm.SyncCommitteeSigned += metrics.SyncCommitteeSigned
+	fm.SyncCommitteeMissed += metrics.SyncCommitteeMissed
+
+	// Merge slashing metrics
+	fm.SlashedCount += metrics.SlashedCount
+	fm.SlashedStake += metrics.SlashedStake
+
+	// Merge balances
+	fm.FundedCount += metrics.FundedCount
+	fm.Balance += metrics.Balance
+	fm.EffectiveBalance += metrics.EffectiveBalance
+	fm.RewardedBalance += metrics.RewardedBalance
+
+	// Merge consecutive missed attestations (take max)
+	if metrics.MaxConsecutiveMissed > fm.MaxConsecutiveMissed {
+		fm.MaxConsecutiveMissed = metrics.MaxConsecutiveMissed
+	}
+	if metrics.MaxConsecutiveMissedStake > fm.MaxConsecutiveMissedStake {
+		fm.MaxConsecutiveMissedStake = metrics.MaxConsecutiveMissedStake
+	}
 
-		wg.Add(1)
-		go func(chunk []*validator.WatchedValidator) {
-			defer wg.Done()
-
-			// Process chunk
-			localMetrics := make(map[string]*MetricsByLabel)
-
-			for _, v := range chunk {
-				for _, label := range v.Labels {
-					metrics, ok := localMetrics[label]
-					if !ok {
-						metrics = &MetricsByLabel{
-							Label:              label,
-							StatusCounts:       make(map[models.ValidatorStatus]int),
-							StatusStakes:       make(map[models.ValidatorStatus]float64),
-							ValidatorTypeCounts: make(map[string]int),
-							ValidatorTypeStakes: make(map[string]float64),
-						}
-						localMetrics[label] = metrics
-					}
-
-					// Check if validator is active (should be attesting)
-					isActive := v.Status == models.StatusActiveOngoing ||
-						v.Status == models.StatusActiveExiting ||
-						v.Status == models.StatusActiveSlashed
-
-					// Always count all validators for status breakdown
-					metrics.ValidatorCount++
-					metrics.StakeCount += v.Weight
-					metrics.StatusCounts[v.Status]++
-					metrics.StatusStakes[v.Status] += v.Weight
-
-					// Track validator type from withdrawal credentials
-					validatorType := getValidatorType(v.Data.WithdrawalCredentials)
-					metrics.ValidatorTypeCounts[validatorType]++
-					metrics.ValidatorTypeStakes[validatorType] += v.Weight
-
-					// Track slashed validators
-					if v.Data.Slashed {
-						metrics.SlashedCount++
-						metrics.SlashedStake += v.Weight
-					}
-
-					// Track max consecutive missed attestations
-					if v.ConsecutiveMissedAttest > metrics.MaxConsecutiveMissed {
-						metrics.MaxConsecutiveMissed = v.ConsecutiveMissedAttest
-					}
-					consecStakeWeighted := float64(v.ConsecutiveMissedAttest) * v.Weight
-					if consecStakeWeighted > metrics.MaxConsecutiveMissedStake {
-						metrics.MaxConsecutiveMissedStake = consecStakeWeighted
-					}
-
-					// Only aggregate performance metrics for ACTIVE validators
-					if isActive {
-						metrics.MissedAttestations += v.MissedAttestations
-						metrics.MissedAttestationsStake += float64(v.MissedAttestations) * v.Weight
-						metrics.SuboptimalSourceVotes += v.SuboptimalSourceVotes
-						metrics.SuboptimalSourceVotesStake += float64(v.SuboptimalSourceVotes) * v.Weight
-						metrics.SuboptimalTargetVotes += v.SuboptimalTargetVotes
-						metrics.SuboptimalTargetVotesStake += float64(v.SuboptimalTargetVotes) * v.Weight
-						metrics.SuboptimalHeadVotes += v.SuboptimalHeadVotes
-						metrics.SuboptimalHeadVotesStake += float64(v.SuboptimalHeadVotes) * v.Weight
-						metrics.MissedBlocksFinalized += v.MissedBlocksFinalized
-						metrics.FutureBlockProposals += v.FutureBlockProposals
-						metrics.IdealConsensusRewards += v.IdealConsensusRewards
-						metrics.ConsensusRewards += v.ConsensusRewards
-						metrics.AttestationDuties += v.AttestationDuties
-						metrics.AttestationDutiesSuccess += v.AttestationDutiesSuccess
-						metrics.AttestationDutiesStake += float64(v.AttestationDuties) * v.Weight
-					}
-
-					// Block proposals should be counted regardless of validator status
-					// A validator can propose a block even when exiting or in other states
-					metrics.ProposedBlocks += v.ProposedBlocks
-					metrics.ProposedBlocksFinalized += v.ProposedBlocksFinalized
-					metrics.MissedBlocks += v.MissedBlocks
-
-					// Collect details (limited to 5 per label)
-					if v.MissedAttestations > 0 && len(metrics.MissedAttestationDetails) < 5 {
-						metrics.MissedAttestationDetails = append(metrics.MissedAttestationDetails, ValidatorDetail{
-							Index:  v.Index,
-							Pubkey: v.Data.Pubkey,
-							Value:  v.MissedAttestations,
-						})
-					}
-					if v.SuboptimalSourceVotes > 0 && len(metrics.SuboptimalSourceDetails) < 5 {
-						metrics.SuboptimalSourceDetails = append(metrics.SuboptimalSourceDetails, ValidatorDetail{
-							Index:  v.Index,
-							Pubkey: v.Data.Pubkey,
-							Value:  v.SuboptimalSourceVotes,
-						})
-					}
-					if v.SuboptimalTargetVotes > 0 && len(metrics.SuboptimalTargetDetails) < 5 {
-						metrics.SuboptimalTargetDetails = append(metrics.SuboptimalTargetDetails, ValidatorDetail{
-							Index:  v.Index,
-							Pubkey: v.Data.Pubkey,
-							Value:  v.SuboptimalTargetVotes,
-						})
-					}
-					if v.SuboptimalHeadVotes > 0 && len(metrics.SuboptimalHeadDetails) < 5 {
-						metrics.SuboptimalHeadDetails = append(metrics.SuboptimalHeadDetails, ValidatorDetail{
-							Index:  v.Index,
-							Pubkey: v.Data.Pubkey,
-							Value:  v.SuboptimalHeadVotes,
-						})
-					}
-					if v.MissedBlocks > 0 && len(metrics.MissedBlockDetails) < 5 {
-						metrics.MissedBlockDetails = append(metrics.MissedBlockDetails, ValidatorDetail{
-							Index:  v.Index,
-							Pubkey: v.Data.Pubkey,
-							Value:  v.MissedBlocks,
-						})
-					}
-				}
-			}
-
-			resultsChan <- workerResult{metrics: localMetrics}
-		}(validators[start:end])
-	}
-
-	// Wait for all workers and close channel
-	go func() {
-		wg.Wait()
-		close(resultsChan)
-	}()
-
-	// Merge results from all workers
-	finalMetrics := make(map[string]*MetricsByLabel)
-
-	for result := range resultsChan {
-		for label, metrics := range result.metrics {
-			if _, ok := finalMetrics[label]; !ok {
-				finalMetrics[label] = &MetricsByLabel{
-					Label:              label,
-					StatusCounts:       make(map[models.ValidatorStatus]int),
-					StatusStakes:       make(map[models.ValidatorStatus]float64),
-					ValidatorTypeCounts: make(map[string]int),
-					ValidatorTypeStakes: make(map[string]float64),
-				}
-			}
-
-			fm := finalMetrics[label]
-
-			// Merge metrics
-			fm.ValidatorCount += metrics.ValidatorCount
-			fm.StakeCount += metrics.StakeCount
-			fm.MissedAttestations += metrics.MissedAttestations
-			fm.MissedAttestationsStake += metrics.MissedAttestationsStake
-			fm.SuboptimalSourceVotes += metrics.SuboptimalSourceVotes
-			fm.SuboptimalSourceVotesStake += metrics.SuboptimalSourceVotesStake
-			fm.SuboptimalTargetVotes += metrics.SuboptimalTargetVotes
-			fm.SuboptimalTargetVotesStake += metrics.SuboptimalTargetVotesStake
-			fm.SuboptimalHeadVotes += metrics.SuboptimalHeadVotes
-			fm.SuboptimalHeadVotesStake += metrics.SuboptimalHeadVotesStake
-			fm.ProposedBlocks += metrics.ProposedBlocks
-			fm.ProposedBlocksFinalized += metrics.ProposedBlocksFinalized
-			fm.MissedBlocks += metrics.MissedBlocks
-			fm.MissedBlocksFinalized += metrics.MissedBlocksFinalized
-			fm.FutureBlockProposals += metrics.FutureBlockProposals
-			fm.IdealConsensusRewards += metrics.IdealConsensusRewards
-			fm.ConsensusRewards += metrics.ConsensusRewards
-			fm.AttestationDuties += metrics.AttestationDuties
-			fm.AttestationDutiesSuccess += metrics.AttestationDutiesSuccess
-			fm.AttestationDutiesStake += metrics.AttestationDutiesStake
-
-			// Merge slashing metrics
-			fm.SlashedCount += metrics.SlashedCount
-			fm.SlashedStake += metrics.SlashedStake
-
-			// Merge consecutive missed attestations (take max)
-			if metrics.MaxConsecutiveMissed > fm.MaxConsecutiveMissed {
-				fm.MaxConsecutiveMissed = metrics.MaxConsecutiveMissed
-			}
-			if metrics.MaxConsecutiveMissedStake > fm.MaxConsecutiveMissedStake {
-				fm.MaxConsecutiveMissedStake = metrics.MaxConsecutiveMissedStake
-			}
-
-			// Merge status counts
-			for status, count := range metrics.StatusCounts {
-				fm.StatusCounts[status] += count
-			}
-			for status, stake := range metrics.StatusStakes {
-				fm.StatusStakes[status] += stake
-			}
-
-			// Merge validator type counts
-			for validatorType, count := range metrics.ValidatorTypeCounts {
-				fm.ValidatorTypeCounts[validatorType] += count
-			}
-			for validatorType, stake := range metrics.ValidatorTypeStakes {
-				fm.ValidatorTypeStakes[validatorType] += stake
-			}
-
-			// Merge details (keep first 5)
-			for _, detail := range metrics.MissedAttestationDetails {
-				if len(fm.MissedAttestationDetails) < 5 {
-					fm.MissedAttestationDetails = append(fm.MissedAttestationDetails, detail)
-				}
-			}
-			for _, detail := range metrics.SuboptimalSourceDetails {
-				if len(fm.SuboptimalSourceDetails) < 5 {
-					fm.SuboptimalSourceDetails = append(fm.SuboptimalSourceDetails, detail)
-				}
-			}
-			for _, detail := range metrics.SuboptimalTargetDetails {
-				if len(fm.SuboptimalTargetDetails) < 5 {
-					fm.SuboptimalTargetDetails = append(fm.SuboptimalTargetDetails, detail)
-				}
-			}
-			for _, detail := range metrics.SuboptimalHeadDetails {
-				if len(fm.SuboptimalHeadDetails) < 5 {
-					fm.SuboptimalHeadDetails = append(fm.SuboptimalHeadDetails, detail)
-				}
-			}
-			for _, detail := range metrics.MissedBlockDetails {
-				if len(fm.MissedBlockDetails) < 5 {
-					fm.MissedBlockDetails = append(fm.MissedBlockDetails, detail)
-				}
-			}
+	// Merge status counts
+	for status, count := range metrics.StatusCounts {
+		fm.StatusCounts[status] += count
+	}
+	for status, stake := range metrics.StatusStakes {
+		fm.StatusStakes[status] += stake
+	}
+
+	// Merge validator type counts
+	for validatorType, count := range metrics.ValidatorTypeCounts {
+		fm.ValidatorTypeCounts[validatorType] += count
+	}
+	for validatorType, stake := range metrics.ValidatorTypeStakes {
+		fm.ValidatorTypeStakes[validatorType] += stake
+	}
+
+	// Merge details (keep first 5)
+	for _, detail := range metrics.MissedAttestationDetails {
+		if len(fm.MissedAttestationDetails) < 5 {
+			fm.MissedAttestationDetails = append(fm.MissedAttestationDetails, detail)
+		}
+	}
+	for _, detail := range metrics.SuboptimalSourceDetails {
+		if len(fm.SuboptimalSourceDetails) < 5 {
+			fm.SuboptimalSourceDetails = append(fm.SuboptimalSourceDetails, detail)
+		}
+	}
+	for _, detail := range metrics.SuboptimalTargetDetails {
+		if len(fm.SuboptimalTargetDetails) < 5 {
+			fm.SuboptimalTargetDetails = append(fm.SuboptimalTargetDetails, detail)
+		}
+	}
+	for _, detail := range metrics.SuboptimalHeadDetails {
+		if len(fm.SuboptimalHeadDetails) < 5 {
+			fm.SuboptimalHeadDetails = append(fm.SuboptimalHeadDetails, detail)
+		}
+	}
+	for _, detail := range metrics.MissedBlockDetails {
+		if len(fm.MissedBlockDetails) < 5 {
+			fm.MissedBlockDetails = append(fm.MissedBlockDetails, detail)
 		}
 	}
+}
 
-	// Calculate rates
-	for _, metrics := range finalMetrics {
+// calculateRates derives the rates from the merged totals
+func calculateRates(result map[string]*MetricsByLabel) {
+	for _, metrics := range result {
 		if metrics.IdealConsensusRewards > 0 {
 			metrics.ConsensusRewardsRate = float64(metrics.ConsensusRewards) / float64(metrics.IdealConsensusRewards)
 		}
 		if metrics.AttestationDuties > 0 {
 			metrics.AttestationDutiesRate = float64(metrics.AttestationDutiesSuccess) / float64(metrics.AttestationDuties)
 		}
+		if metrics.InclusionDelayCount > 0 {
+			metrics.InclusionDelayAvg = float64(metrics.InclusionDelaySum) / float64(metrics.InclusionDelayCount)
+		}
 	}
-
-	return finalMetrics
 }
 
 // ComputeNetworkMetrics computes aggregate network-wide metrics from all validators
-func ComputeNetworkMetrics(allValidators []models.Validator) *MetricsByLabel {
-	metrics := &MetricsByLabel{
+// Stake is weighted in units of stakeUnit, the effective balance of a full validator
+func ComputeNetworkMetrics(allValidators []models.Validator, stakeUnit models.Gwei) *MetricsByLabel {
+	metrics := NewNetworkMetrics()
+	for i := range allValidators {
+		metrics.AddNetworkValidator(&allValidators[i], stakeUnit)
+	}
+	return metrics
+}
+
+// NewNetworkMetrics creates an empty network-wide aggregate for AddNetworkValidator
+func NewNetworkMetrics() *MetricsByLabel {
+	return &MetricsByLabel{
 		Label:              "scope:all-network",
 		StatusCounts:       make(map[models.ValidatorStatus]int),
 		StatusStakes:       make(map[models.ValidatorStatus]float64),
 		ValidatorTypeCounts: make(map[string]int),
 		ValidatorTypeStakes: make(map[string]float64),
 	}
+}
 
-	for _, v := range allValidators {
-		weight := float64(v.Data.EffectiveBalance) / 32_000_000_000.0
+// AddNetworkValidator adds a validator to a network-wide aggregate as it is streamed in
+func (m *MetricsByLabel) AddNetworkValidator(v *models.Validator, stakeUnit models.Gwei) {
+	weight := models.StakeWeight(v.Data.EffectiveBalance, stakeUnit)
 
-		metrics.ValidatorCount++
-		metrics.StakeCount += weight
-		metrics.StatusCounts[v.Status]++
-		metrics.StatusStakes[v.Status] += weight
+	m.ValidatorCount++
+	m.StakeCount += weight
+	m.StatusCounts[v.Status]++
+	m.StatusStakes[v.Status] += weight
 
-		// Track validator type
-		validatorType := getValidatorType(v.Data.WithdrawalCredentials)
-		metrics.ValidatorTypeCounts[validatorType]++
-		metrics.ValidatorTypeStakes[validatorType] += weight
+	// Track validator type
+	validatorType := getValidatorType(v.Data.WithdrawalCredentials)
+	m.ValidatorTypeCounts[validatorType]++
+	m.ValidatorTypeStakes[validatorType] += weight
 
-		// Track slashed validators
-		if v.Data.Slashed {
-			metrics.SlashedCount++
-			metrics.SlashedStake += weight
-		}
+	// Track slashed validators
+	if v.Data.Slashed {
+		m.SlashedCount++
+		m.SlashedStake += weight
 	}
+	m.addBalance(v)
+}
 
-	return metrics
+// addBalance adds a validator's balances; withdrawn validators are left out so they don't drag the averages down
+func (m *MetricsByLabel) addBalance(v *models.Validator) {
+	if v.Balance == 0 && v.Data.EffectiveBalance == 0 {
+		return
+	}
+	m.FundedCount++
+	m.Balance += v.Balance
+	m.EffectiveBalance += v.Data.EffectiveBalance
 }
@@ -1,6 +1,8 @@
 package metrics
 
 import (
+	"fmt"
+	"math"
 	"testing"
 
 	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
@@ -46,7 +48,7 @@ func TestComputeMetrics(t *testing.T) {
 		},
 	}
 
-	metricsByLabel := ComputeMetrics(validators, 1000)
+	metricsByLabel := ComputeMetrics(validators, 1000, nil)
 
 	// Check scope:watched metrics
 	watched, ok := metricsByLabel["scope:watched"]
@@ -127,7 +129,7 @@ func TestComputeMetricsStakeWeighting(t *testing.T) {
 		},
 	}
 
-	metricsByLabel := ComputeMetrics(validators, 1000)
+	metricsByLabel := ComputeMetrics(validators, 1000, nil)
 
 	watched := metricsByLabel["scope:watched"]
 
@@ -174,7 +176,7 @@ func TestComputeMetricsStatusCounts(t *testing.T) {
 		},
 	}
 
-	metricsByLabel := ComputeMetrics(validators, 1000)
+	metricsByLabel := ComputeMetrics(validators, 1000, nil)
 
 	watched := metricsByLabel["scope:watched"]
 
@@ -213,7 +215,7 @@ func TestComputeNetworkMetrics(t *testing.T) {
 		validators[i].Data.EffectiveBalance = 32000000000
 	}
 
-	metrics := ComputeNetworkMetrics(validators)
+	metrics := ComputeNetworkMetrics(validators, models.DefaultStakeUnit)
 
 	if metrics.ValidatorCount != 3 {
 		t.Errorf("Expected 3 validators, got %d", metrics.ValidatorCount)
@@ -232,6 +234,19 @@ func TestComputeNetworkMetrics(t *testing.T) {
 	}
 }
 
+func TestComputeNetworkMetricsStakeUnit(t *testing.T) {
+	// A devnet preset where a full validator has 1,000 ETH of effective balance; a compounding
+	// validator holds two of them
+	validators := []models.Validator{{Index: 1, Status: models.StatusActiveOngoing}, {Index: 2, Status: models.StatusActiveOngoing}}
+	validators[0].Data.EffectiveBalance = 1_000_000_000_000
+	validators[1].Data.EffectiveBalance = 2_000_000_000_000
+
+	metrics := ComputeNetworkMetrics(validators, 1_000_000_000_000)
+	if metrics.StakeCount != 3.0 || metrics.StatusStakes[models.StatusActiveOngoing] != 3.0 {
+		t.Errorf("Expected stake 3.0 in full validator units, got %f", metrics.StakeCount)
+	}
+}
+
 func TestComputeMetricsConcurrency(t *testing.T) {
 	// Create a large set of validators to test concurrent processing
 	validators := make([]*validator.WatchedValidator, 10000)
@@ -250,7 +265,7 @@ func TestComputeMetricsConcurrency(t *testing.T) {
 		}
 	}
 
-	metricsByLabel := ComputeMetrics(validators, 1000)
+	metricsByLabel := ComputeMetrics(validators, 1000, nil)
 
 	watched := metricsByLabel["scope:watched"]
 
@@ -283,6 +298,234 @@ func BenchmarkComputeMetrics(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		ComputeMetrics(validators, 1000)
+		ComputeMetrics(validators, 1000, nil)
+	}
+}
+
+func TestComputeMetricsLabelClasses(t *testing.T) {
+	validators := []*validator.WatchedValidator{
+		{
+			Validator: models.Validator{Index: 1, Status: models.StatusActiveOngoing},
+			Labels:    []string{"scope:watched", "operator:a", "key:0xaaa", "canary"},
+			Weight:    1.0,
+		},
+		{
+			Validator: models.Validator{Index: 2, Status: models.StatusActiveOngoing},
+			Labels:    []string{"scope:watched", "operator:a", "key:0xbbb"},
+			Weight:    1.0,
+		},
+	}
+
+	all := ComputeMetrics(validators, 1000, nil)
+	if len(all) != 5 {
+		t.Errorf("Expected every label to be aggregated, got %d labels", len(all))
+	}
+
+	// Scope labels are aggregated even when not selected
+	selected := ComputeMetrics(validators, 1000, NewLabelClasses([]string{"operator"}))
+	if len(selected) != 2 {
+		t.Fatalf("Expected scope:watched and operator:a only, got %d labels", len(selected))
+	}
+	if selected["operator:a"] == nil || selected["operator:a"].ValidatorCount != 2 {
+		t.Errorf("Expected operator:a with 2 validators, got %+v", selected["operator:a"])
+	}
+	if selected["scope:watched"] == nil || selected["scope:watched"].ValidatorCount != 2 {
+		t.Errorf("Expected scope:watched with 2 validators, got %+v", selected["scope:watched"])
+	}
+}
+
+func TestComputeMetricsHierarchicalLabels(t *testing.T) {
+	validators := []*validator.WatchedValidator{
+		{
+			Validator: models.Validator{Index: 1, Status: models.StatusActiveOngoing},
+			Labels:    append([]string{"scope:watched"}, validator.ExpandLabels([]string{"operator:a/cluster:eu/node:1"})...),
+			Weight:    1.0,
+		},
+		{
+			Validator: models.Validator{Index: 2, Status: models.StatusActiveOngoing},
+			Labels:    append([]string{"scope:watched"}, validator.ExpandLabels([]string{"operator:a/cluster:us/node:2"})...),
+			Weight:    1.0,
+		},
+	}
+
+	// Every level rolls up the validators below it
+	all := ComputeMetrics(validators, 1000, nil)
+	for label, count := range map[string]int{"operator:a": 2, "operator:a/cluster:eu": 1, "operator:a/cluster:us/node:2": 1} {
+		if all[label] == nil || all[label].ValidatorCount != count {
+			t.Errorf("Expected %s with %d validators, got %+v", label, count, all[label])
+		}
+	}
+
+	// A level is selected by the class of its last part
+	selected := ComputeMetrics(validators, 1000, NewLabelClasses([]string{"operator", "cluster"}))
+	if len(selected) != 4 || selected["operator:a/cluster:eu/node:1"] != nil {
+		t.Errorf("Expected the scope, operator and cluster levels only, got %d labels", len(selected))
+	}
+}
+
+func TestComputeMetricsInclusionDelay(t *testing.T) {
+	validators := []*validator.WatchedValidator{
+		{
+			Validator:           models.Validator{Index: 1, Status: models.StatusActiveOngoing},
+			Labels:              []string{"scope:watched"},
+			Weight:              1.0,
+			InclusionDelaySum:   3,
+			InclusionDelayCount: 3,
+			MaxInclusionDelay:   1,
+		},
+		{
+			Validator:           models.Validator{Index: 2, Status: models.StatusActiveOngoing},
+			Labels:              []string{"scope:watched"},
+			Weight:              1.0,
+			InclusionDelaySum:   6,
+			InclusionDelayCount: 3,
+			MaxInclusionDelay:   4,
+		},
+	}
+
+	watched := ComputeMetrics(validators, 1000, nil)["scope:watched"]
+	if watched.InclusionDelayAvg != 1.5 || watched.MaxInclusionDelay != 4 {
+		t.Errorf("Expected avg 1.5 and max 4, got %v and %d", watched.InclusionDelayAvg, watched.MaxInclusionDelay)
+	}
+
+	card := ComputeScorecard(watched, nil, DefaultScorecardWeights())
+	if d := card.Dimensions[DimensionInclusionDelay]; d == nil || math.Abs(*d-100/1.5) > 1e-9 {
+		t.Errorf("Expected inclusion delay score %.2f, got %v", 100/1.5, d)
+	}
+}
+
+// benchmarkLabeledFleet is a fleet with a per-key label and several descriptive labels per key
+func benchmarkLabeledFleet() []*validator.WatchedValidator {
+	validators := make([]*validator.WatchedValidator, 10000)
+	for i := range validators {
+		validators[i] = &validator.WatchedValidator{
+			Validator: models.Validator{
+				Index:  models.ValidatorIndex(i),
+				Status: models.StatusActiveOngoing,
+			},
+			Labels: []string{
+				"scope:all-network", "scope:watched",
+				fmt.Sprintf("operator:op%d", i%10),
+				fmt.Sprintf("key:%d", i),
+				fmt.Sprintf("name:validator-%d", i),
+				fmt.Sprintf("node:node%d", i%100),
+				fmt.Sprintf("region:r%d", i%4),
+			},
+			Weight:             1.0,
+			MissedAttestations: uint64(i % 5),
+		}
+	}
+	return validators
+}
+
+func BenchmarkComputeMetricsAllLabels(b *testing.B) {
+	validators := benchmarkLabeledFleet()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ComputeMetrics(validators, 1000, nil)
+	}
+}
+
+func BenchmarkComputeMetricsSelectedClasses(b *testing.B) {
+	validators := benchmarkLabeledFleet()
+	classes := NewLabelClasses([]string{"operator", "region"})
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ComputeMetrics(validators, 1000, classes)
+	}
+}
+
+func TestAggregatorReusesUnchangedResult(t *testing.T) {
+	validators := benchmarkLabeledFleet()[:1000]
+	aggregator := NewAggregator(nil)
+
+	first := aggregator.Compute(validators)
+	allocs := testing.AllocsPerRun(10, func() {
+		aggregator.Compute(validators)
+	})
+	if allocs != 0 {
+		t.Errorf("Expected no allocations while nothing changed, got %.0f", allocs)
+	}
+
+	// Order doesn't matter (GetAll iterates a map)
+	reversed := make([]*validator.WatchedValidator, len(validators))
+	for i, v := range validators {
+		reversed[len(validators)-1-i] = v
+	}
+	if second := aggregator.Compute(reversed); fmt.Sprintf("%p", second) != fmt.Sprintf("%p", first) {
+		t.Error("Expected the previous result for the same validators in another order")
+	}
+
+	// A changed counter is picked up, in a fresh result so the published one stays intact
+	validators[0].MissedAttestations += 10
+	changed := aggregator.Compute(validators)
+	if changed["scope:watched"].MissedAttestations != first["scope:watched"].MissedAttestations+10 {
+		t.Errorf("Expected the change to be aggregated, got %d (was %d)",
+			changed["scope:watched"].MissedAttestations, first["scope:watched"].MissedAttestations)
+	}
+
+	// Recomputing reuses the per-worker partials: far fewer allocations than a one-shot computation
+	oneShot := testing.AllocsPerRun(5, func() {
+		ComputeMetrics(validators, 1000, nil)
+	})
+	recompute := testing.AllocsPerRun(5, func() {
+		validators[0].MissedAttestations++
+		aggregator.Compute(validators)
+	})
+	if recompute >= oneShot/2 {
+		t.Errorf("Expected recomputing to allocate well below a one-shot computation (%.0f), got %.0f", oneShot, recompute)
+	}
+}
+
+func BenchmarkAggregatorUnchanged(b *testing.B) {
+	validators := benchmarkLabeledFleet()
+	aggregator := NewAggregator(nil)
+	aggregator.Compute(validators)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		aggregator.Compute(validators)
+	}
+}
+
+func BenchmarkAggregatorChanged(b *testing.B) {
+	validators := benchmarkLabeledFleet()
+	aggregator := NewAggregator(nil)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		validators[i%len(validators)].MissedAttestations++
+		aggregator.Compute(validators)
+	}
+}
+
+func TestComputeMetricsBalances(t *testing.T) {
+	newValidator := func(index models.ValidatorIndex, balance, effective models.Gwei) *validator.WatchedValidator {
+		v := &validator.WatchedValidator{Labels: []string{"scope:watched"}}
+		v.Index = index
+		v.Balance = balance
+		v.Data.EffectiveBalance = effective
+		return v
+	}
+	validators := []*validator.WatchedValidator{
+		newValidator(1, 32_010_000_000, 32_000_000_000),
+		newValidator(2, 31_500_000_000, 31_000_000_000), // Leaking
+		newValidator(3, 0, 0),                           // Withdrawn
+	}
+
+	aggregator := NewAggregator(nil)
+	watched := aggregator.Compute(validators)["scope:watched"]
+	if watched.FundedCount != 2 || watched.Balance != 63_510_000_000 || watched.EffectiveBalance != 63_000_000_000 {
+		t.Errorf("Unexpected balances: funded=%d balance=%d effective=%d", watched.FundedCount, watched.Balance, watched.EffectiveBalance)
+	}
+
+	// A balance change alone is picked up
+	validators[1].Balance -= 1_000_000
+	if got := aggregator.Compute(validators)["scope:watched"].Balance; got != 63_509_000_000 {
+		t.Errorf("Expected the balance change to be aggregated, got %d", got)
 	}
 }
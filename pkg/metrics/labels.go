@@ -0,0 +1,42 @@
+package metrics
+
+import "strings"
+
+// scopeClass is the class of the built-in scope:* labels, which are always aggregated
+const scopeClass = "scope"
+
+// LabelClass returns a label's class, the part before the first ':'
+// (operator for operator:acme). Labels without a ':' are their own class
+// A hierarchical label has the class of its last level (cluster for operator:acme/cluster:eu)
+func LabelClass(label string) string {
+	if i := strings.LastIndexByte(label, '/'); i >= 0 {
+		label = label[i+1:]
+	}
+	if i := strings.IndexByte(label, ':'); i >= 0 {
+		return label[:i]
+	}
+	return label
+}
+
+// LabelClasses selects the label classes ComputeMetrics aggregates
+// Labels of other classes stay on the validator for lookups (API, alerts, top offenders)
+// but get no metrics of their own. A nil LabelClasses aggregates every label
+type LabelClasses map[string]bool
+
+// NewLabelClasses returns the selection for the given classes, or nil (everything) if none are given
+func NewLabelClasses(classes []string) LabelClasses {
+	if len(classes) == 0 {
+		return nil
+	}
+
+	selected := LabelClasses{scopeClass: true}
+	for _, class := range classes {
+		selected[class] = true
+	}
+	return selected
+}
+
+// Aggregates reports whether metrics are computed for the label
+func (c LabelClasses) Aggregates(label string) bool {
+	return c == nil || c[LabelClass(label)]
+}
@@ -1,10 +1,18 @@
 package metrics
 
 import (
+	"math"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/queues"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/scheduler"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 )
 
 // PrometheusMetrics holds all Prometheus metric collectors
@@ -20,6 +28,16 @@ type PrometheusMetrics struct {
 	PendingConsolidationsCount *prometheus.GaugeVec
 	PendingWithdrawalsCount    *prometheus.GaugeVec
 
+	// Pending queue flow rates (per epoch)
+	PendingQueueInflow      *prometheus.GaugeVec
+	PendingQueueOutflow     *prometheus.GaugeVec
+	PendingQueueInflowGwei  *prometheus.GaugeVec
+	PendingQueueOutflowGwei *prometheus.GaugeVec
+	ValidatorQueuePosition  *prometheus.GaugeVec
+	ValidatorQueueETA       *prometheus.GaugeVec
+	ActivationQueueEpochs   *prometheus.GaugeVec
+	ExitQueueEpochs         *prometheus.GaugeVec
+
 	// Validator status metrics
 	ValidatorStatusCount       *prometheus.GaugeVec
 	ValidatorStatusScaledCount *prometheus.GaugeVec
@@ -49,6 +67,7 @@ type PrometheusMetrics struct {
 	IdealConsensusRewardsGwei  *prometheus.GaugeVec
 	ActualConsensusRewardsGwei *prometheus.GaugeVec
 	ConsensusRewardsRate       *prometheus.GaugeVec
+	EstimatedAPR               *prometheus.GaugeVec
 
 	// Duty metrics at slot level
 	MissedDutiesAtSlot       *prometheus.GaugeVec
@@ -64,9 +83,146 @@ type PrometheusMetrics struct {
 	MissedConsecutiveAttestations       *prometheus.GaugeVec
 	MissedConsecutiveAttestationsScaled *prometheus.GaugeVec
 
+	// Attestation inclusion delay
+	AttestationInclusionDelay    *prometheus.GaugeVec
+	AttestationInclusionDistance *prometheus.HistogramVec
+
+	// Balances by stat (sum, avg)
+	ValidatorBalance *prometheus.GaugeVec
+	EffectiveBalance *prometheus.GaugeVec
+
+	// Aggregation duty metrics
+	ExpectedAggregationDuties     *prometheus.GaugeVec
+	CommitteeAggregatesIncluded   *prometheus.GaugeVec
+	CommitteeAggregatesMissed     *prometheus.GaugeVec
+	CommitteeAggregateSuccessRate *prometheus.GaugeVec
+
+	// Data freshness
+	DataLastUpdated *prometheus.GaugeVec
+	DataStale       *prometheus.GaugeVec
+
+	// Watchlist audit
+	WatchlistChangesTotal *prometheus.CounterVec
+
+	// Warmup mode (1 while misses are not recorded)
+	Warmup *prometheus.GaugeVec
+
+	// Beacon node sync status (1 while duty processing is paused)
+	BeaconNodeSyncing *prometheus.GaugeVec
+
+	// Local slot minus the beacon node's head slot
+	ClockDriftSlots *prometheus.GaugeVec
+
+	// Network attestation baseline
+	NetworkSampleValidators *prometheus.GaugeVec
+
+	// Beacon node event stream
+	BeaconEventsTotal *prometheus.CounterVec
+	SlotTriggersTotal *prometheus.CounterVec
+
+	// Startup validator loading progress
+	StartupBatches          *prometheus.GaugeVec
+	StartupBatchesCompleted *prometheus.GaugeVec
+	StartupValidatorsLoaded *prometheus.GaugeVec
+
+	// Slot scheduler
+	SchedulerTaskDuration        *prometheus.GaugeVec
+	SchedulerTaskOutcomesTotal   *prometheus.CounterVec
+	SchedulerSlotBudgetRemaining *prometheus.GaugeVec
+	SlotProcessingDuration       *prometheus.HistogramVec
+
+	// Epoch processing pipeline
+	EpochProcessingDuration    *prometheus.GaugeVec
+	EpochProcessingBudgetRatio *prometheus.GaugeVec
+	EpochStageDuration         *prometheus.GaugeVec
+	EpochStageOutcomesTotal    *prometheus.CounterVec
+
+	// Canary validators
+	CanaryValidators  *prometheus.GaugeVec
+	CanaryMissesTotal *prometheus.CounterVec
+
+	// Slashings of watched validators included on chain
+	SlashingEventsTotal *prometheus.CounterVec
+
+	// Finalized block proposal reconciliation
+	ProposalsPendingFinality      *prometheus.GaugeVec
+	ProposalFinalityFlipsTotal    *prometheus.CounterVec
+	ProposalReorgCorrectionsTotal *prometheus.CounterVec
+	ReorgEventsTotal              *prometheus.CounterVec
+
+	// Rewards of watched block proposals
+	BlockCLRewardsGwei *prometheus.CounterVec
+	BlockELRewardsWei  *prometheus.CounterVec
+
+	// Withdrawals swept from watched validators by execution payloads
+	WithdrawalsGwei *prometheus.CounterVec
+
+	// Watched proposals paying to an unexpected fee recipient
+	WrongFeeRecipientTotal *prometheus.CounterVec
+
+	// Watched proposals by the clients their graffiti points to
+	BlocksByInferredClient *prometheus.CounterVec
+
+	// MEV-Boost relay registrations
+	RelayRegistered             *prometheus.GaugeVec
+	RelayUnregisteredValidators *prometheus.GaugeVec
+
+	// Third-party effectiveness ratings of the watched validators
+	ExternalEffectiveness   *prometheus.GaugeVec
+	ExternalRatedValidators *prometheus.GaugeVec
+
+	// Watched validators in the current and next sync committees
+	SyncCommitteeMember        *prometheus.GaugeVec
+	SyncCommitteePeriodEpoch   *prometheus.GaugeVec
+	SyncCommitteeMembers       *prometheus.GaugeVec
+	FutureSyncCommitteeMembers *prometheus.GaugeVec
+
+	// Watched keys that aren't active yet, per pre-activation stage
+	PreActivationValidators *prometheus.GaugeVec
+
+	// Optional work shed while the beacon node is overloaded
+	DegradationLevel *prometheus.GaugeVec
+	ShedWorkTotal    *prometheus.CounterVec
+
+	// Attestation duties evaluated against those expected (the watcher's own data completeness)
+	AttestationDutyCoverage      *prometheus.GaugeVec
+	AttestationDutiesUnevaluated *prometheus.CounterVec
+
+	// Watched validators by duty liability (pending, active, exiting, slashed, exited)
+	DutyLiability *prometheus.GaugeVec
+
+	// Beacon status changes of watched validators between validator loads
+	StatusTransitionsTotal *prometheus.CounterVec
+
+	// Withdrawal credentials type changes of watched validators between validator loads
+	CredentialsChangesTotal *prometheus.CounterVec
+
+	// Watcher self-health: how far processing trails the chain and when an epoch last went through
+	EpochsBehind              *prometheus.GaugeVec
+	LastEpochProcessedSeconds *prometheus.GaugeVec
+
+	// Counter reset policy: what the cumulative counters gained over trailing windows, and when they last reset
+	CounterWindow          *prometheus.GaugeVec
+	CountersResetTimestamp *prometheus.GaugeVec
+	windows                *CounterWindows // Nil without configured windows
+
 	// Counter state tracking (last seen values for incrementing)
-	counterState     map[string]counterValues
-	counterStateMu   sync.RWMutex
+	counterState   map[string]counterValues
+	blockTotals    map[string]BlockCounters // Block proposal counter totals by scope, for persistence
+	counterStateMu sync.RWMutex
+
+	// Staleness tracking (last successful update per data source)
+	lastUpdated map[DataSource]time.Time
+	paused      map[DataSource]bool // Sources stale while their processing is paused
+	staleAfter  time.Duration
+	startTime   time.Time
+	stalenessMu sync.RWMutex
+
+	stakeUnit atomic.Uint64 // Effective balance of a full validator in Gwei, 0 until set (32 ETH)
+
+	// Reward spec the APR is estimated from, 0 until set
+	epochYearBits atomic.Uint64 // Epochs per year as float64 bits
+	rewardFactor  atomic.Uint64 // BASE_REWARD_FACTOR
 }
 
 // counterValues tracks the last seen values for counters
@@ -77,8 +233,22 @@ type counterValues struct {
 	MissedBlocksFinalized   uint64
 }
 
+// BlockCounters are the totals of the block proposal counters of a scope
+type BlockCounters struct {
+	ProposedHead      uint64 `json:"proposed_head"`
+	MissedHead        uint64 `json:"missed_head"`
+	ProposedFinalized uint64 `json:"proposed_finalized"`
+	MissedFinalized   uint64 `json:"missed_finalized"`
+}
+
+// ScopeCounters is the persisted state of a scope's block proposal counters
+type ScopeCounters struct {
+	Totals BlockCounters `json:"totals"` // Counter values
+	Seen   BlockCounters `json:"seen"`   // Last aggregated values the counters were incremented from
+}
+
 // NewPrometheusMetrics creates and registers all Prometheus metrics
-func NewPrometheusMetrics(registry *prometheus.Registry) *PrometheusMetrics {
+func NewPrometheusMetrics(registry prometheus.Registerer) *PrometheusMetrics {
 	m := &PrometheusMetrics{
 		Slot: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 			Name: "eth_slot",
@@ -108,13 +278,45 @@ func NewPrometheusMetrics(registry *prometheus.Registry) *PrometheusMetrics {
 			Name: "eth_pending_withdrawals_count",
 			Help: "Number of pending withdrawals",
 		}, []string{"network"}),
+		PendingQueueInflow: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "eth_pending_queue_inflow_per_epoch",
+			Help: "Entries added to a pending queue per epoch since the previous epoch",
+		}, []string{"queue", "network"}),
+		PendingQueueOutflow: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "eth_pending_queue_outflow_per_epoch",
+			Help: "Entries processed out of a pending queue per epoch since the previous epoch",
+		}, []string{"queue", "network"}),
+		PendingQueueInflowGwei: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "eth_pending_queue_inflow_gwei_per_epoch",
+			Help: "Gwei added to a pending queue per epoch since the previous epoch",
+		}, []string{"queue", "network"}),
+		PendingQueueOutflowGwei: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "eth_pending_queue_outflow_gwei_per_epoch",
+			Help: "Gwei processed out of a pending queue per epoch since the previous epoch",
+		}, []string{"queue", "network"}),
+		ValidatorQueuePosition: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "eth_validator_queue_position",
+			Help: "Stake queued ahead of a watched validator in the activation or exit queue, in full validators",
+		}, []string{"validator_index", "label", "queue", "network"}),
+		ValidatorQueueETA: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "eth_validator_queue_eta_epochs",
+			Help: "Estimated epochs until a queued watched validator is activated, or withdrawable after its exit",
+		}, []string{"validator_index", "label", "queue", "network"}),
+		ActivationQueueEpochs: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "eth_activation_queue_epochs",
+			Help: "Estimated epochs until activation for a validator joining the activation queue now",
+		}, []string{"network"}),
+		ExitQueueEpochs: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "eth_exit_queue_epochs",
+			Help: "Epochs until an exit initiated now takes effect, from the exits already scheduled",
+		}, []string{"network"}),
 		ValidatorStatusCount: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 			Name: "eth_validator_status_count",
 			Help: "Number of validators by status",
 		}, []string{"scope", "status", "network"}),
 		ValidatorStatusScaledCount: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 			Name: "eth_validator_status_scaled_count",
-			Help: "Number of validators by status, scaled by stake (32 ETH units)",
+			Help: "Number of validators by status, scaled by stake (full validator units, 32 ETH on mainnet)",
 		}, []string{"scope", "status", "network"}),
 		ValidatorTypeCount: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 			Name: "eth_validator_type_count",
@@ -122,7 +324,7 @@ func NewPrometheusMetrics(registry *prometheus.Registry) *PrometheusMetrics {
 		}, []string{"scope", "type", "network"}),
 		ValidatorTypeScaledCount: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 			Name: "eth_validator_type_scaled_count",
-			Help: "Number of validators by withdrawal credentials type, scaled by stake (32 ETH units)",
+			Help: "Number of validators by withdrawal credentials type, scaled by stake (full validator units, 32 ETH on mainnet)",
 		}, []string{"scope", "type", "network"}),
 		SlashedValidators: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 			Name: "eth_slashed_validators",
@@ -130,11 +332,11 @@ func NewPrometheusMetrics(registry *prometheus.Registry) *PrometheusMetrics {
 		}, []string{"scope", "network"}),
 		MissedAttestations: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 			Name: "eth_missed_attestations",
-			Help: "Number of missed attestations in the current epoch",
+			Help: "Missed attestation duties, settled per epoch, since the counters last reset",
 		}, []string{"scope", "network"}),
 		MissedAttestationsScaled: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 			Name: "eth_missed_attestations_scaled",
-			Help: "Number of missed attestations in the current epoch, scaled by stake (32 ETH units)",
+			Help: "Missed attestation duties since the counters last reset, scaled by stake (full validator units, 32 ETH on mainnet)",
 		}, []string{"scope", "network"}),
 		SuboptimalSourcesRate: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 			Name: "eth_suboptimal_sources_rate",
@@ -180,21 +382,25 @@ func NewPrometheusMetrics(registry *prometheus.Registry) *PrometheusMetrics {
 			Name: "eth_consensus_rewards_rate",
 			Help: "Consensus rewards rate (actual/ideal, 0-1)",
 		}, []string{"scope", "network"}),
+		EstimatedAPR: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "eth_estimated_apr",
+			Help: "Last epoch's attestation rewards annualized over the effective balance that earned them (0.03 is 3%); scope:all-network is the baseline of perfect attestations",
+		}, []string{"scope", "network"}),
 		MissedDutiesAtSlot: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 			Name: "eth_missed_duties_at_slot",
-			Help: "Missed validator duties in last slot",
+			Help: "Missed attestation duties in the last evaluated slot (validators with a duty that slot only)",
 		}, []string{"scope", "network"}),
 		MissedDutiesAtSlotScaled: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 			Name: "eth_missed_duties_at_slot_scaled",
-			Help: "Stake-scaled missed validator duties in last slot",
+			Help: "Missed attestation duties in the last evaluated slot, scaled by the missing validators' stake (full validator units, 32 ETH on mainnet)",
 		}, []string{"scope", "network"}),
 		PerformedDutiesAtSlot: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 			Name: "eth_performed_duties_at_slot",
-			Help: "Performed validator duties in last slot",
+			Help: "Performed attestation duties in the last evaluated slot (validators with a duty that slot only)",
 		}, []string{"scope", "network"}),
 		PerformedDutiesAtSlotScaled: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 			Name: "eth_performed_duties_at_slot_scaled",
-			Help: "Stake-scaled performed validator duties in last slot",
+			Help: "Performed attestation duties in the last evaluated slot, scaled by the attesting validators' stake (full validator units, 32 ETH on mainnet)",
 		}, []string{"scope", "network"}),
 		DutiesRate: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 			Name: "eth_duties_rate",
@@ -210,9 +416,260 @@ func NewPrometheusMetrics(registry *prometheus.Registry) *PrometheusMetrics {
 		}, []string{"scope", "network"}),
 		MissedConsecutiveAttestationsScaled: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 			Name: "eth_missed_consecutive_attestations_scaled",
-			Help: "Maximum number of consecutive missed attestations, scaled by stake (32 ETH units)",
+			Help: "Maximum number of consecutive missed attestations, scaled by stake (full validator units, 32 ETH on mainnet)",
+		}, []string{"scope", "network"}),
+		AttestationInclusionDelay: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "eth_attestation_inclusion_delay",
+			Help: "Slots between attestations and the blocks that first included them in the current epoch, by stat (avg, max)",
+		}, []string{"scope", "stat", "network"}),
+		AttestationInclusionDistance: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "eth_attestation_inclusion_distance",
+			Help: "Slots between watched attestations and the blocks that first included them (1 is optimal)",
+			// Distances are whole slots, so every bucket up to the 32-slot inclusion window is exact
+			Buckets: []float64{1, 2, 3, 4, 5, 8, 16, 32},
+		}, []string{"scope", "network"}),
+		ValidatorBalance: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "eth_validator_balance_gwei",
+			Help: "Balance of the validators not yet withdrawn, by stat (sum, avg)",
+		}, []string{"scope", "stat", "network"}),
+		EffectiveBalance: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "eth_effective_balance_gwei",
+			Help: "Effective balance of the validators not yet withdrawn, by stat (sum, avg)",
+		}, []string{"scope", "stat", "network"}),
+		ExpectedAggregationDuties: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "eth_expected_aggregation_duties",
+			Help: "Expected number of aggregator selections in the current epoch, derived from committee sizes",
+		}, []string{"scope", "network"}),
+		CommitteeAggregatesIncluded: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "eth_committee_aggregates_included",
+			Help: "Attestation duties in the current epoch whose committee aggregate was included on chain",
 		}, []string{"scope", "network"}),
+		CommitteeAggregatesMissed: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "eth_committee_aggregates_missed",
+			Help: "Attestation duties in the current epoch whose committee aggregate was never included on chain",
+		}, []string{"scope", "network"}),
+		CommitteeAggregateSuccessRate: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "eth_committee_aggregate_success_rate",
+			Help: "Rate of attestation duties whose committee aggregate was included on chain (0-1)",
+		}, []string{"scope", "network"}),
+		DataLastUpdated: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "eth_data_last_updated_timestamp_seconds",
+			Help: "Unix timestamp of the last successful update per data source",
+		}, []string{"source", "network"}),
+		DataStale: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "eth_data_stale",
+			Help: "Whether a data source is stale and its derived series were removed (1 = stale)",
+		}, []string{"source", "network"}),
+		WatchlistChangesTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "eth_watchlist_changes_total",
+			Help: "Total watched key changes applied on reload, by change type (added, removed, relabeled)",
+		}, []string{"change", "network"}),
+		Warmup: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "eth_watcher_warmup",
+			Help: "Whether the watcher is warming up after start (1) and not yet recording duty misses",
+		}, []string{"network"}),
+		BeaconNodeSyncing: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "eth_beacon_node_syncing",
+			Help: "Whether the beacon node is syncing or optimistic (1) and duty and reward processing is paused",
+		}, []string{"network"}),
+		ClockDriftSlots: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "eth_clock_drift_slots",
+			Help: "Locally computed current slot minus the slot of the beacon node's head header, checked every minute",
+		}, []string{"network"}),
+		NetworkSampleValidators: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "eth_network_sample_validators",
+			Help: "Active network validators sampled for the scope:all-network attestation performance baseline",
+		}, []string{"network"}),
+		BeaconEventsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "eth_beacon_events_total",
+			Help: "Total beacon node events received, by topic",
+		}, []string{"topic", "network"}),
+		SlotTriggersTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "eth_slot_triggers_total",
+			Help: "Total slots processed in event-driven mode, by trigger (head event or clock timeout)",
+		}, []string{"trigger", "network"}),
+		StartupBatches: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "eth_startup_batches",
+			Help: "Number of validator lookup batches needed to load the watched validators at startup",
+		}, []string{"network"}),
+		StartupBatchesCompleted: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "eth_startup_batches_completed",
+			Help: "Number of startup validator lookup batches completed",
+		}, []string{"network"}),
+		StartupValidatorsLoaded: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "eth_startup_validators_loaded",
+			Help: "Number of watched validators loaded so far at startup",
+		}, []string{"network"}),
+		SchedulerTaskDuration: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "eth_scheduler_task_duration_seconds",
+			Help: "Duration of the last run of each scheduled per-slot task",
+		}, []string{"task", "priority", "network"}),
+		SchedulerTaskOutcomesTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "eth_scheduler_task_outcomes_total",
+			Help: "Scheduled task outcomes (completed, failed, deadline_exceeded, skipped, deferred)",
+		}, []string{"task", "outcome", "network"}),
+		SchedulerSlotBudgetRemaining: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "eth_scheduler_slot_budget_remaining_seconds",
+			Help: "Slot budget left after the last slot's tasks ran (negative when over budget)",
+		}, []string{"network"}),
+		SlotProcessingDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "eth_slot_processing_duration_seconds",
+			Help:    "Time each slot's scheduled tasks took, idle work left out",
+			Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2, 4, 6, 8, 12, 24},
+			// Also exported as a native histogram to scrapers that negotiate it
+			NativeHistogramBucketFactor:     1.1,
+			NativeHistogramMaxBucketNumber:  100,
+			NativeHistogramMinResetDuration: time.Hour,
+		}, []string{"network"}),
+		EpochProcessingDuration: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "eth_epoch_processing_duration_seconds",
+			Help: "Duration of the last epoch processing pipeline",
+		}, []string{"network"}),
+		EpochProcessingBudgetRatio: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "eth_epoch_processing_budget_ratio",
+			Help: "Duration of the last epoch processing pipeline over the slot duration (above 1 it overran its slot)",
+		}, []string{"network"}),
+		EpochStageDuration: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "eth_epoch_stage_duration_seconds",
+			Help: "Duration of the last run of each epoch processing stage",
+		}, []string{"stage", "network"}),
+		EpochStageOutcomesTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "eth_epoch_stage_outcomes_total",
+			Help: "Epoch processing stage outcomes (completed, failed, deadline_exceeded, skipped)",
+		}, []string{"stage", "outcome", "network"}),
+		CanaryValidators: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "eth_canary_validators",
+			Help: "Number of canary validators, by primary label",
+		}, []string{"label", "network"}),
+		CanaryMissesTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "eth_canary_misses_total",
+			Help: "Total missed duties of canary validators, by primary label and duty (attestation, block, liveness)",
+		}, []string{"label", "duty", "network"}),
+		SlashingEventsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "eth_slashing_events_total",
+			Help: "Total slashings of watched validators included in blocks, by kind (proposer, attester) and primary label",
+		}, []string{"kind", "label", "network"}),
+		ProposalsPendingFinality: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "eth_block_proposals_pending_finality",
+			Help: "Watched block proposals waiting for their slot to be finalized",
+		}, []string{"network"}),
+		ProposalFinalityFlipsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "eth_block_proposal_finality_flips_total",
+			Help: "Watched block proposals whose finalized outcome differs from the head outcome, by head and finalized outcome",
+		}, []string{"head", "finalized", "network"}),
+		ProposalReorgCorrectionsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "eth_block_proposal_reorg_corrections_total",
+			Help: "Watched block proposal outcomes corrected after a chain reorg, by outcome before and after (proposed, missed or unassigned)",
+		}, []string{"before", "after", "network"}),
+		ReorgEventsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "eth_reorg_events_total",
+			Help: "Chain reorgs seen by the watcher (node chain_reorg events, head events or block roots of processed slots), by depth in slots",
+		}, []string{"depth", "network"}),
+		WithdrawalsGwei: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "eth_withdrawals_total_gwei",
+			Help: "Withdrawals of watched validators swept by execution payloads in gwei, by kind (partial, full)",
+		}, []string{"scope", "kind", "network"}),
+		BlockCLRewardsGwei: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "eth_block_cl_rewards_gwei",
+			Help: "Consensus layer rewards of watched block proposals in gwei",
+		}, []string{"scope", "network"}),
+		BlockELRewardsWei: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "eth_block_el_rewards_wei",
+			Help: "Execution payload value paid to the fee recipient of watched block proposals in wei, for payloads delivered by a configured relay",
+		}, []string{"scope", "network"}),
+		WrongFeeRecipientTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "eth_wrong_fee_recipient_total",
+			Help: "Watched block proposals whose execution payload pays to a fee recipient other than the expected ones",
+		}, []string{"scope", "network"}),
+		BlocksByInferredClient: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "eth_blocks_by_inferred_client",
+			Help: "Watched block proposals by the consensus and execution clients inferred from their graffiti",
+		}, []string{"scope", "consensus", "execution", "network"}),
+		RelayRegistered: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "eth_relay_registered",
+			Help: "Active watched validators with a validator registration on the MEV-Boost relay",
+		}, []string{"relay", "network"}),
+		RelayUnregisteredValidators: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "eth_relay_unregistered_validators",
+			Help: "Active watched validators registered with none of the configured MEV-Boost relays",
+		}, []string{"network"}),
+		ExternalEffectiveness: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "eth_external_effectiveness",
+			Help: "Average effectiveness of the watched validators as rated by a third-party provider, in percent",
+		}, []string{"scope", "provider", "network"}),
+		ExternalRatedValidators: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "eth_external_rated_validators",
+			Help: "Watched validators with a third-party effectiveness rating",
+		}, []string{"scope", "provider", "network"}),
+		SyncCommitteeMember: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "eth_sync_committee_member",
+			Help: "Committee positions of a watched validator in the current sync committee",
+		}, []string{"validator_index", "label", "period", "network"}),
+		SyncCommitteePeriodEpoch: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "eth_sync_committee_period_epoch",
+			Help: "First and last epoch of the current sync committee period",
+		}, []string{"boundary", "network"}),
+		SyncCommitteeMembers: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "eth_sync_committee_members",
+			Help: "Watched validators in the current sync committee",
+		}, []string{"scope", "network"}),
+		FutureSyncCommitteeMembers: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "eth_future_sync_committee_members",
+			Help: "Watched validators in the next sync committee",
+		}, []string{"scope", "network"}),
+		PreActivationValidators: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "eth_pre_activation_validators",
+			Help: "Watched keys not active yet by stage (no_deposit, deposit_seen, deposit_pending, pending_initialized, pending_queued)",
+		}, []string{"scope", "stage", "network"}),
+		DegradationLevel: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "eth_degradation_level",
+			Help: "Optional work shed to spare an overloaded beacon node (0 normal, 1 reduced intervals, 2 duty tracking only)",
+		}, []string{"network"}),
+		ShedWorkTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "eth_shed_work_total",
+			Help: "Runs of optional work skipped because of degradation",
+		}, []string{"work", "network"}),
+		AttestationDutyCoverage: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "eth_attestation_duty_coverage_percent",
+			Help: "Attestation duties of the watched validators evaluated in the last complete epoch, in percent of one per active validator",
+		}, []string{"network"}),
+		AttestationDutiesUnevaluated: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "eth_attestation_duties_unevaluated_total",
+			Help: "Expected attestation duties of the watched validators the watcher never evaluated",
+		}, []string{"network"}),
+		DutyLiability: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "eth_duty_liability_validators",
+			Help: "Watched validators by duty liability in the last evaluated epoch (from activation and exit epochs)",
+		}, []string{"state", "network"}),
+		StatusTransitionsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "eth_validator_status_transitions_total",
+			Help: "Beacon status changes of watched validators between validator loads, by previous and new status",
+		}, []string{"scope", "from", "to", "network"}),
+		CredentialsChangesTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "eth_withdrawal_credentials_changes_total",
+			Help: "Withdrawal credentials type changes of watched validators between validator loads (0x00 to 0x01, 0x01 to 0x02)",
+		}, []string{"scope", "from", "to", "network"}),
+		EpochsBehind: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "eth_watcher_epochs_behind",
+			Help: "Epochs between the chain's current epoch and the epoch of the slot the watcher last processed",
+		}, []string{"network"}),
+		LastEpochProcessedSeconds: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "eth_watcher_last_epoch_processed_timestamp_seconds",
+			Help: "Unix timestamp of the last successful epoch processing",
+		}, []string{"network"}),
+		CounterWindow: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "eth_counter_window",
+			Help: "What a cumulative counter gained over the trailing window of epochs, whatever the reset policy",
+		}, []string{"scope", "counter", "window", "network"}),
+		CountersResetTimestamp: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "eth_counters_reset_timestamp_seconds",
+			Help: "Unix timestamp of the last reset of the cumulative per-validator counters",
+		}, []string{"network"}),
 		counterState: make(map[string]counterValues),
+		blockTotals:  make(map[string]BlockCounters),
+		lastUpdated:  make(map[DataSource]time.Time),
+		paused:       make(map[DataSource]bool),
+		startTime:    time.Now(),
 	}
 
 	// Register all metrics
@@ -223,6 +680,14 @@ func NewPrometheusMetrics(registry *prometheus.Registry) *PrometheusMetrics {
 	registry.MustRegister(m.PendingDepositsValue)
 	registry.MustRegister(m.PendingConsolidationsCount)
 	registry.MustRegister(m.PendingWithdrawalsCount)
+	registry.MustRegister(m.PendingQueueInflow)
+	registry.MustRegister(m.PendingQueueOutflow)
+	registry.MustRegister(m.PendingQueueInflowGwei)
+	registry.MustRegister(m.PendingQueueOutflowGwei)
+	registry.MustRegister(m.ValidatorQueuePosition)
+	registry.MustRegister(m.ValidatorQueueETA)
+	registry.MustRegister(m.ActivationQueueEpochs)
+	registry.MustRegister(m.ExitQueueEpochs)
 	registry.MustRegister(m.ValidatorStatusCount)
 	registry.MustRegister(m.ValidatorStatusScaledCount)
 	registry.MustRegister(m.ValidatorTypeCount)
@@ -241,6 +706,7 @@ func NewPrometheusMetrics(registry *prometheus.Registry) *PrometheusMetrics {
 	registry.MustRegister(m.IdealConsensusRewardsGwei)
 	registry.MustRegister(m.ActualConsensusRewardsGwei)
 	registry.MustRegister(m.ConsensusRewardsRate)
+	registry.MustRegister(m.EstimatedAPR)
 	registry.MustRegister(m.MissedDutiesAtSlot)
 	registry.MustRegister(m.MissedDutiesAtSlotScaled)
 	registry.MustRegister(m.PerformedDutiesAtSlot)
@@ -249,6 +715,70 @@ func NewPrometheusMetrics(registry *prometheus.Registry) *PrometheusMetrics {
 	registry.MustRegister(m.DutiesRateScaled)
 	registry.MustRegister(m.MissedConsecutiveAttestations)
 	registry.MustRegister(m.MissedConsecutiveAttestationsScaled)
+	registry.MustRegister(m.AttestationInclusionDelay)
+	registry.MustRegister(m.AttestationInclusionDistance)
+	registry.MustRegister(m.ValidatorBalance)
+	registry.MustRegister(m.EffectiveBalance)
+	registry.MustRegister(m.ExpectedAggregationDuties)
+	registry.MustRegister(m.CommitteeAggregatesIncluded)
+	registry.MustRegister(m.CommitteeAggregatesMissed)
+	registry.MustRegister(m.CommitteeAggregateSuccessRate)
+	registry.MustRegister(m.DataLastUpdated)
+	registry.MustRegister(m.DataStale)
+	registry.MustRegister(m.WatchlistChangesTotal)
+	registry.MustRegister(m.Warmup)
+	registry.MustRegister(m.BeaconNodeSyncing)
+	registry.MustRegister(m.ClockDriftSlots)
+	registry.MustRegister(m.NetworkSampleValidators)
+	registry.MustRegister(m.BeaconEventsTotal)
+	registry.MustRegister(m.SlotTriggersTotal)
+	registry.MustRegister(m.StartupBatches)
+	registry.MustRegister(m.StartupBatchesCompleted)
+	registry.MustRegister(m.StartupValidatorsLoaded)
+	registry.MustRegister(m.SchedulerTaskDuration)
+	registry.MustRegister(m.SchedulerTaskOutcomesTotal)
+	registry.MustRegister(m.SchedulerSlotBudgetRemaining)
+	registry.MustRegister(m.SlotProcessingDuration)
+	registry.MustRegister(m.EpochProcessingDuration)
+	registry.MustRegister(m.EpochProcessingBudgetRatio)
+	registry.MustRegister(m.EpochStageDuration)
+	registry.MustRegister(m.EpochStageOutcomesTotal)
+	registry.MustRegister(m.CanaryValidators)
+	registry.MustRegister(m.CanaryMissesTotal)
+	registry.MustRegister(m.SlashingEventsTotal)
+	registry.MustRegister(m.ProposalsPendingFinality)
+	registry.MustRegister(m.ProposalFinalityFlipsTotal)
+	registry.MustRegister(m.ProposalReorgCorrectionsTotal)
+	registry.MustRegister(m.ReorgEventsTotal)
+	registry.MustRegister(m.BlockCLRewardsGwei)
+	registry.MustRegister(m.BlockELRewardsWei)
+	registry.MustRegister(m.WithdrawalsGwei)
+	registry.MustRegister(m.WrongFeeRecipientTotal)
+	registry.MustRegister(m.BlocksByInferredClient)
+	registry.MustRegister(m.RelayRegistered)
+	registry.MustRegister(m.RelayUnregisteredValidators)
+	registry.MustRegister(m.ExternalEffectiveness)
+	registry.MustRegister(m.ExternalRatedValidators)
+	registry.MustRegister(m.SyncCommitteeMember)
+	registry.MustRegister(m.SyncCommitteePeriodEpoch)
+	registry.MustRegister(m.SyncCommitteeMembers)
+	registry.MustRegister(m.FutureSyncCommitteeMembers)
+	registry.MustRegister(m.PreActivationValidators)
+	registry.MustRegister(m.DegradationLevel)
+	registry.MustRegister(m.ShedWorkTotal)
+	registry.MustRegister(m.AttestationDutyCoverage)
+	registry.MustRegister(m.AttestationDutiesUnevaluated)
+	registry.MustRegister(m.DutyLiability)
+	registry.MustRegister(m.StatusTransitionsTotal)
+	registry.MustRegister(m.CredentialsChangesTotal)
+	registry.MustRegister(m.EpochsBehind)
+	registry.MustRegister(m.LastEpochProcessedSeconds)
+	registry.MustRegister(m.CounterWindow)
+	registry.MustRegister(m.CountersResetTimestamp)
+
+	// Goroutines, memory, GC and process stats of the watcher itself
+	registry.MustRegister(collectors.NewGoCollector())
+	registry.MustRegister(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
 
 	return m
 }
@@ -277,10 +807,14 @@ func (m *PrometheusMetrics) UpdateMetrics(metricsByLabel map[string]*MetricsByLa
 	m.SuboptimalHeadsRate.Reset()
 	m.FutureBlockProposals.Reset()
 	m.ConsensusRewardsRate.Reset()
+	m.EstimatedAPR.Reset()
 	m.DutiesRate.Reset()
 	m.DutiesRateScaled.Reset()
 	m.MissedConsecutiveAttestations.Reset()
 	m.MissedConsecutiveAttestationsScaled.Reset()
+	m.AttestationInclusionDelay.Reset()
+	m.ValidatorBalance.Reset()
+	m.EffectiveBalance.Reset()
 
 	// Update metrics for each scope
 	for label, metrics := range metricsByLabel {
@@ -291,9 +825,8 @@ func (m *PrometheusMetrics) UpdateMetrics(metricsByLabel map[string]*MetricsByLa
 			m.ValidatorStatusCount.WithLabelValues(scope, string(status), network).Set(float64(count))
 		}
 		for status, stake := range metrics.StatusStakes {
-			// Scaled count = stake / 32 (since each validator has 32 ETH effective balance)
-			scaledCount := stake / 32.0
-			m.ValidatorStatusScaledCount.WithLabelValues(scope, string(status), network).Set(scaledCount)
+			// Stakes are already weighted in full validator units
+			m.ValidatorStatusScaledCount.WithLabelValues(scope, string(status), network).Set(stake)
 		}
 
 		// Validator type metrics (0x00 BLS, 0x01 execution, 0x02 compounding)
@@ -301,22 +834,33 @@ func (m *PrometheusMetrics) UpdateMetrics(metricsByLabel map[string]*MetricsByLa
 			m.ValidatorTypeCount.WithLabelValues(scope, validatorType, network).Set(float64(count))
 		}
 		for validatorType, stake := range metrics.ValidatorTypeStakes {
-			scaledCount := stake / 32.0
-			m.ValidatorTypeScaledCount.WithLabelValues(scope, validatorType, network).Set(scaledCount)
+			m.ValidatorTypeScaledCount.WithLabelValues(scope, validatorType, network).Set(stake)
 		}
 
 		// Slashed validators
 		m.SlashedValidators.WithLabelValues(scope, network).Set(float64(metrics.SlashedCount))
 
+		// Balances
+		m.ValidatorBalance.WithLabelValues(scope, "sum", network).Set(float64(metrics.Balance))
+		m.EffectiveBalance.WithLabelValues(scope, "sum", network).Set(float64(metrics.EffectiveBalance))
+		if metrics.FundedCount > 0 {
+			m.ValidatorBalance.WithLabelValues(scope, "avg", network).Set(float64(metrics.Balance) / float64(metrics.FundedCount))
+			m.EffectiveBalance.WithLabelValues(scope, "avg", network).Set(float64(metrics.EffectiveBalance) / float64(metrics.FundedCount))
+		}
+
 		// Attestation metrics
 		m.MissedAttestations.WithLabelValues(scope, network).Set(float64(metrics.MissedAttestations))
-		m.MissedAttestationsScaled.WithLabelValues(scope, network).Set(metrics.MissedAttestationsStake / 32.0)
+		m.MissedAttestationsScaled.WithLabelValues(scope, network).Set(metrics.MissedAttestationsStake)
 
 		// Calculate suboptimal rates
-		if metrics.AttestationDuties > 0 {
-			sourceRate := float64(metrics.SuboptimalSourceVotes) / float64(metrics.AttestationDuties)
-			targetRate := float64(metrics.SuboptimalTargetVotes) / float64(metrics.AttestationDuties)
-			headRate := float64(metrics.SuboptimalHeadVotes) / float64(metrics.AttestationDuties)
+		duties := metrics.AttestationDuties
+		if duties == 0 {
+			duties = metrics.SampledDuties
+		}
+		if duties > 0 {
+			sourceRate := float64(metrics.SuboptimalSourceVotes) / float64(duties)
+			targetRate := float64(metrics.SuboptimalTargetVotes) / float64(duties)
+			headRate := float64(metrics.SuboptimalHeadVotes) / float64(duties)
 
 			m.SuboptimalSourcesRate.WithLabelValues(scope, network).Set(sourceRate)
 			m.SuboptimalTargetsRate.WithLabelValues(scope, network).Set(targetRate)
@@ -366,6 +910,12 @@ func (m *PrometheusMetrics) UpdateMetrics(metricsByLabel map[string]*MetricsByLa
 			ProposedBlocksFinalized: metrics.ProposedBlocksFinalized,
 			MissedBlocksFinalized:   metrics.MissedBlocksFinalized,
 		}
+		totals := m.blockTotals[scope]
+		totals.ProposedHead += proposedHeadDelta
+		totals.MissedHead += missedHeadDelta
+		totals.ProposedFinalized += proposedFinalizedDelta
+		totals.MissedFinalized += missedFinalizedDelta
+		m.blockTotals[scope] = totals
 		m.counterStateMu.Unlock()
 
 		// Increment counters by delta (note: label order is scope, network)
@@ -380,15 +930,17 @@ func (m *PrometheusMetrics) UpdateMetrics(metricsByLabel map[string]*MetricsByLa
 		m.ActualConsensusRewardsGwei.WithLabelValues(scope, network).Set(float64(metrics.ConsensusRewards))
 		m.ConsensusRewardsRate.WithLabelValues(scope, network).Set(metrics.ConsensusRewardsRate)
 
-		// Duty metrics at slot level (these track current epoch performance)
-		m.PerformedDutiesAtSlot.WithLabelValues(scope, network).Set(float64(metrics.AttestationDutiesSuccess))
-		m.MissedDutiesAtSlot.WithLabelValues(scope, network).Set(float64(metrics.AttestationDuties - metrics.AttestationDutiesSuccess))
-
-		// Scaled versions
-		successStake := float64(metrics.AttestationDutiesSuccess) * (metrics.StakeCount / float64(metrics.ValidatorCount))
-		missedStake := float64(metrics.AttestationDuties-metrics.AttestationDutiesSuccess) * (metrics.StakeCount / float64(metrics.ValidatorCount))
-		m.PerformedDutiesAtSlotScaled.WithLabelValues(scope, network).Set(successStake / 32.0)
-		m.MissedDutiesAtSlotScaled.WithLabelValues(scope, network).Set(missedStake / 32.0)
+		// Estimated APR, once the epoch duration is known; network rewards aren't fetched, so the
+		// network-wide scope gets the baseline of perfect attestations instead
+		if epochsPerYear := m.epochsPerYear(); epochsPerYear > 0 {
+			if scope == "scope:all-network" {
+				if active := metrics.ActiveBalance(m.StakeUnit()); active > 0 {
+					m.EstimatedAPR.WithLabelValues(scope, network).Set(BaselineAPR(active, m.baseRewardFactor(), epochsPerYear))
+				}
+			} else if metrics.RewardedBalance > 0 {
+				m.EstimatedAPR.WithLabelValues(scope, network).Set(EstimatedAPR(metrics.ConsensusRewards, metrics.RewardedBalance, epochsPerYear))
+			}
+		}
 
 		// Duty rate metrics
 		m.DutiesRate.WithLabelValues(scope, network).Set(metrics.AttestationDutiesRate)
@@ -400,7 +952,470 @@ func (m *PrometheusMetrics) UpdateMetrics(metricsByLabel map[string]*MetricsByLa
 
 		// Consecutive missed attestations
 		m.MissedConsecutiveAttestations.WithLabelValues(scope, network).Set(float64(metrics.MaxConsecutiveMissed))
-		m.MissedConsecutiveAttestationsScaled.WithLabelValues(scope, network).Set(metrics.MaxConsecutiveMissedStake / 32.0)
+		m.MissedConsecutiveAttestationsScaled.WithLabelValues(scope, network).Set(metrics.MaxConsecutiveMissedStake)
+
+		// Attestation inclusion delay
+		if metrics.InclusionDelayCount > 0 {
+			m.AttestationInclusionDelay.WithLabelValues(scope, "avg", network).Set(metrics.InclusionDelayAvg)
+			m.AttestationInclusionDelay.WithLabelValues(scope, "max", network).Set(float64(metrics.MaxInclusionDelay))
+		}
+
+		// Aggregation duty metrics
+		m.ExpectedAggregationDuties.WithLabelValues(scope, network).Set(metrics.ExpectedAggregations)
+		m.CommitteeAggregatesIncluded.WithLabelValues(scope, network).Set(float64(metrics.CommitteeAggregatesIncluded))
+		m.CommitteeAggregatesMissed.WithLabelValues(scope, network).Set(float64(metrics.CommitteeAggregatesMissed))
+		if total := metrics.CommitteeAggregatesIncluded + metrics.CommitteeAggregatesMissed; total > 0 {
+			m.CommitteeAggregateSuccessRate.WithLabelValues(scope, network).Set(float64(metrics.CommitteeAggregatesIncluded) / float64(total))
+		}
+	}
+
+	// Trailing windows of the cumulative counters
+	if m.windows != nil {
+		m.windows.Observe(epoch, metricsByLabel)
+		m.CounterWindow.Reset()
+		for label := range metricsByLabel {
+			for n, sums := range m.windows.Sums(label, epoch) {
+				window := strconv.Itoa(n)
+				for i, counter := range WindowCounters {
+					m.CounterWindow.WithLabelValues(label, counter, window, network).Set(float64(sums[i]))
+				}
+			}
+		}
+	}
+
+	// Remove series derived from data sources that stopped updating
+	m.applyStaleness(network, time.Now())
+}
+
+// SetCounterWindows exports what the cumulative counters gained over trailing windows of the given lengths in epochs
+func (m *PrometheusMetrics) SetCounterWindows(epochs []int) {
+	m.windows = NewCounterWindows(epochs)
+}
+
+// RecordCounterReset rebases the block proposal counters and windows on counters reset to zero
+func (m *PrometheusMetrics) RecordCounterReset(network string, now time.Time) {
+	m.counterStateMu.Lock()
+	prefix := network + ":"
+	for key := range m.counterState {
+		if strings.HasPrefix(key, prefix) {
+			m.counterState[key] = counterValues{}
+		}
+	}
+	m.counterStateMu.Unlock()
+
+	if m.windows != nil {
+		m.windows.Rebase()
+	}
+	m.CountersResetTimestamp.WithLabelValues(network).Set(float64(now.Unix()))
+}
+
+// RecordWatchlistChanges increments the watchlist change counters
+func (m *PrometheusMetrics) RecordWatchlistChanges(network string, added, removed, relabeled int) {
+	m.WatchlistChangesTotal.WithLabelValues("added", network).Add(float64(added))
+	m.WatchlistChangesTotal.WithLabelValues("removed", network).Add(float64(removed))
+	m.WatchlistChangesTotal.WithLabelValues("relabeled", network).Add(float64(relabeled))
+}
+
+// SetWarmup sets the warmup gauge
+func (m *PrometheusMetrics) SetWarmup(network string, warmup bool) {
+	value := 0.0
+	if warmup {
+		value = 1
+	}
+	m.Warmup.WithLabelValues(network).Set(value)
+}
+
+// SetBeaconNodeSyncing sets the beacon node syncing gauge
+func (m *PrometheusMetrics) SetBeaconNodeSyncing(network string, syncing bool) {
+	value := 0.0
+	if syncing {
+		value = 1
+	}
+	m.BeaconNodeSyncing.WithLabelValues(network).Set(value)
+}
+
+// SetNetworkSample sets how many network validators the attestation baseline sampled
+func (m *PrometheusMetrics) SetNetworkSample(network string, validators int) {
+	m.NetworkSampleValidators.WithLabelValues(network).Set(float64(validators))
+}
+
+// SetClockDrift sets the clock drift gauge
+func (m *PrometheusMetrics) SetClockDrift(network string, slots int64) {
+	m.ClockDriftSlots.WithLabelValues(network).Set(float64(slots))
+}
+
+// RecordBeaconEvent counts a beacon node event
+func (m *PrometheusMetrics) RecordBeaconEvent(network, topic string) {
+	m.BeaconEventsTotal.WithLabelValues(topic, network).Inc()
+}
+
+// RecordSlotTrigger counts what started an event-driven slot's processing
+func (m *PrometheusMetrics) RecordSlotTrigger(network, trigger string) {
+	m.SlotTriggersTotal.WithLabelValues(trigger, network).Inc()
+}
+
+// SetStartupProgress sets the startup loading progress gauges
+func (m *PrometheusMetrics) SetStartupProgress(network string, completed, total, loaded int) {
+	m.StartupBatches.WithLabelValues(network).Set(float64(total))
+	m.StartupBatchesCompleted.WithLabelValues(network).Set(float64(completed))
+	m.StartupValidatorsLoaded.WithLabelValues(network).Set(float64(loaded))
+}
+
+// RecordSchedule records the outcome of a slot's scheduled tasks
+// The slot's processing time is the sum of its tasks' runs, which are sequential; idle work isn't the slot's
+func (m *PrometheusMetrics) RecordSchedule(network string, report scheduler.Report) {
+	var processing time.Duration
+	for _, result := range report.Results {
+		m.SchedulerTaskOutcomesTotal.WithLabelValues(result.Name, string(result.Outcome), network).Inc()
+		if result.Outcome != scheduler.OutcomeSkipped && result.Outcome != scheduler.OutcomeDeferred {
+			m.SchedulerTaskDuration.WithLabelValues(result.Name, result.Priority.String(), network).Set(result.Duration.Seconds())
+			if result.Priority != scheduler.PriorityIdle {
+				processing += result.Duration
+			}
+		}
+	}
+	m.SchedulerSlotBudgetRemaining.WithLabelValues(network).Set(report.Remaining.Seconds())
+	m.SlotProcessingDuration.WithLabelValues(network).Observe(processing.Seconds())
+}
+
+// RecordEpochPipeline records the stages of an epoch's processing and its duration against the slot
+func (m *PrometheusMetrics) RecordEpochPipeline(network string, results []scheduler.TaskResult, duration, slot time.Duration) {
+	for _, result := range results {
+		m.EpochStageOutcomesTotal.WithLabelValues(result.Name, string(result.Outcome), network).Inc()
+		if result.Outcome != scheduler.OutcomeSkipped {
+			m.EpochStageDuration.WithLabelValues(result.Name, network).Set(result.Duration.Seconds())
+		}
+	}
+	m.EpochProcessingDuration.WithLabelValues(network).Set(duration.Seconds())
+	if slot > 0 {
+		m.EpochProcessingBudgetRatio.WithLabelValues(network).Set(duration.Seconds() / slot.Seconds())
+	}
+}
+
+// RecordInclusionDistance records the inclusion distance of a watched attestation in its scopes
+func (m *PrometheusMetrics) RecordInclusionDistance(network string, scopes []string, distance uint64) {
+	for _, scope := range scopes {
+		m.AttestationInclusionDistance.WithLabelValues(scope, network).Observe(float64(distance))
+	}
+}
+
+// SetStakeUnit sets the effective balance of a full validator, which slot duty stakes are scaled by
+func (m *PrometheusMetrics) SetStakeUnit(stakeUnit models.Gwei) {
+	m.stakeUnit.Store(uint64(stakeUnit))
+}
+
+// StakeUnit returns the effective balance of a full validator, 32 ETH until set
+func (m *PrometheusMetrics) StakeUnit() models.Gwei {
+	if unit := m.stakeUnit.Load(); unit > 0 {
+		return models.Gwei(unit)
+	}
+	return models.DefaultStakeUnit
+}
+
+// SetRewardSpec sets the epoch duration and BASE_REWARD_FACTOR the APR is estimated from
+// A zero factor keeps Ethereum's
+func (m *PrometheusMetrics) SetRewardSpec(epoch time.Duration, baseRewardFactor uint64) {
+	m.epochYearBits.Store(math.Float64bits(EpochsPerYear(epoch)))
+	m.rewardFactor.Store(baseRewardFactor)
+}
+
+func (m *PrometheusMetrics) epochsPerYear() float64 {
+	return math.Float64frombits(m.epochYearBits.Load())
+}
+
+func (m *PrometheusMetrics) baseRewardFactor() uint64 {
+	if factor := m.rewardFactor.Load(); factor > 0 {
+		return factor
+	}
+	return DefaultBaseRewardFactor
+}
+
+// SetSlotDuties sets the duty metrics of the last evaluated slot
+// Labels without validators holding a duty that slot are dropped rather than reported as zero
+func (m *PrometheusMetrics) SetSlotDuties(network string, duties SlotDutiesByLabel) {
+	m.PerformedDutiesAtSlot.Reset()
+	m.PerformedDutiesAtSlotScaled.Reset()
+	m.MissedDutiesAtSlot.Reset()
+	m.MissedDutiesAtSlotScaled.Reset()
+
+	unit := float64(m.StakeUnit()) / 1e9 // Slot duty stakes are in ETH
+	for scope, d := range duties {
+		m.PerformedDutiesAtSlot.WithLabelValues(scope, network).Set(float64(d.Performed))
+		m.MissedDutiesAtSlot.WithLabelValues(scope, network).Set(float64(d.Missed))
+		m.PerformedDutiesAtSlotScaled.WithLabelValues(scope, network).Set(d.PerformedStake / unit)
+		m.MissedDutiesAtSlotScaled.WithLabelValues(scope, network).Set(d.MissedStake / unit)
+	}
+}
+
+// SetCanaryCounts sets the number of canary validators per primary label
+func (m *PrometheusMetrics) SetCanaryCounts(network string, counts map[string]int) {
+	m.CanaryValidators.Reset()
+	for label, count := range counts {
+		m.CanaryValidators.WithLabelValues(label, network).Set(float64(count))
+	}
+}
+
+// RecordCanaryMiss counts a missed duty of a canary validator
+func (m *PrometheusMetrics) RecordCanaryMiss(network, label, duty string) {
+	m.CanaryMissesTotal.WithLabelValues(label, duty, network).Inc()
+}
+
+// RecordSlashing counts a slashing of a watched validator
+func (m *PrometheusMetrics) RecordSlashing(network, kind, label string) {
+	m.SlashingEventsTotal.WithLabelValues(kind, label, network).Inc()
+}
+
+// SetProposalsPendingFinality sets the number of proposals waiting for finality
+func (m *PrometheusMetrics) SetProposalsPendingFinality(network string, pending int) {
+	m.ProposalsPendingFinality.WithLabelValues(network).Set(float64(pending))
+}
+
+// RecordProposalFinalityFlip counts a proposal whose finalized outcome differs from the head one
+func (m *PrometheusMetrics) RecordProposalFinalityFlip(network, head, finalized string) {
+	m.ProposalFinalityFlipsTotal.WithLabelValues(head, finalized, network).Inc()
+}
+
+// RecordProposalReorgCorrection counts a proposal outcome corrected after a chain reorg
+func (m *PrometheusMetrics) RecordProposalReorgCorrection(network, before, after string) {
+	m.ProposalReorgCorrectionsTotal.WithLabelValues(before, after, network).Inc()
+}
+
+// RecordReorg counts a chain reorg by its depth in slots
+func (m *PrometheusMetrics) RecordReorg(network string, depth uint64) {
+	m.ReorgEventsTotal.WithLabelValues(strconv.FormatUint(depth, 10), network).Inc()
+}
+
+// SetDegradationLevel sets the current degradation level
+func (m *PrometheusMetrics) SetDegradationLevel(network string, level int) {
+	m.DegradationLevel.WithLabelValues(network).Set(float64(level))
+}
+
+// RecordShedWork counts a skipped run of optional work
+func (m *PrometheusMetrics) RecordShedWork(network, work string) {
+	m.ShedWorkTotal.WithLabelValues(work, network).Inc()
+}
+
+// RecordDutyCoverage sets the attestation duty coverage of a complete epoch and counts its unevaluated duties
+func (m *PrometheusMetrics) RecordDutyCoverage(network string, percent float64, missing int) {
+	m.AttestationDutyCoverage.WithLabelValues(network).Set(percent)
+	m.AttestationDutiesUnevaluated.WithLabelValues(network).Add(float64(missing))
+}
+
+// SetDutyLiability sets the number of watched validators in each duty liability state
+func (m *PrometheusMetrics) SetDutyLiability(network string, counts map[string]int) {
+	for state, count := range counts {
+		m.DutyLiability.WithLabelValues(state, network).Set(float64(count))
+	}
+}
+
+// RecordCredentialsChange counts a watched validator's withdrawal credentials type change in its scopes
+func (m *PrometheusMetrics) RecordCredentialsChange(network string, scopes []string, from, to string) {
+	for _, scope := range scopes {
+		m.CredentialsChangesTotal.WithLabelValues(scope, from, to, network).Inc()
+	}
+}
+
+// RecordStatusTransition counts a watched validator's status change in its scopes
+func (m *PrometheusMetrics) RecordStatusTransition(network string, scopes []string, from, to models.ValidatorStatus) {
+	for _, scope := range scopes {
+		m.StatusTransitionsTotal.WithLabelValues(scope, string(from), string(to), network).Inc()
+	}
+}
+
+// RecordProgress sets how many epochs the slot just processed trails the chain's current epoch
+func (m *PrometheusMetrics) RecordProgress(network string, processed, current models.Epoch) {
+	behind := 0.0
+	if current > processed {
+		behind = float64(current - processed)
+	}
+	m.EpochsBehind.WithLabelValues(network).Set(behind)
+}
+
+// MarkEpochProcessed records a successful epoch processing
+func (m *PrometheusMetrics) MarkEpochProcessed(network string, now time.Time) {
+	m.LastEpochProcessedSeconds.WithLabelValues(network).Set(float64(now.Unix()))
+}
+
+// BlockCounterState returns the block proposal counter state of every scope for persistence
+func (m *PrometheusMetrics) BlockCounterState(network string) map[string]ScopeCounters {
+	m.counterStateMu.RLock()
+	defer m.counterStateMu.RUnlock()
+
+	state := make(map[string]ScopeCounters, len(m.blockTotals))
+	for scope, totals := range m.blockTotals {
+		seen := m.counterState[network+":"+scope]
+		state[scope] = ScopeCounters{
+			Totals: totals,
+			Seen: BlockCounters{
+				ProposedHead:      seen.ProposedBlocks,
+				MissedHead:        seen.MissedBlocks,
+				ProposedFinalized: seen.ProposedBlocksFinalized,
+				MissedFinalized:   seen.MissedBlocksFinalized,
+			},
+		}
+	}
+	return state
+}
+
+// RestoreBlockCounters restores persisted block proposal counters, e.g. after a restart
+// Must be called before the first UpdateMetrics so restored values aren't counted twice
+func (m *PrometheusMetrics) RestoreBlockCounters(network string, state map[string]ScopeCounters) {
+	m.counterStateMu.Lock()
+	defer m.counterStateMu.Unlock()
+
+	for scope, counters := range state {
+		totals := counters.Totals
+		m.BlockProposalsHeadTotal.WithLabelValues(scope, network).Add(float64(totals.ProposedHead))
+		m.MissedBlockProposalsHeadTotal.WithLabelValues(scope, network).Add(float64(totals.MissedHead))
+		m.BlockProposalsFinalizedTotal.WithLabelValues(scope, network).Add(float64(totals.ProposedFinalized))
+		m.MissedBlockProposalsFinalizedTotal.WithLabelValues(scope, network).Add(float64(totals.MissedFinalized))
+		m.blockTotals[scope] = totals
+
+		m.counterState[network+":"+scope] = counterValues{
+			ProposedBlocks:          counters.Seen.ProposedHead,
+			MissedBlocks:            counters.Seen.MissedHead,
+			ProposedBlocksFinalized: counters.Seen.ProposedFinalized,
+			MissedBlocksFinalized:   counters.Seen.MissedFinalized,
+		}
+	}
+}
+
+// RecordBlockRewards adds a watched proposal's consensus layer rewards to its scopes
+func (m *PrometheusMetrics) RecordBlockRewards(network string, scopes []string, gwei uint64) {
+	for _, scope := range scopes {
+		m.BlockCLRewardsGwei.WithLabelValues(scope, network).Add(float64(gwei))
+	}
+}
+
+// RecordWithdrawal adds a watched validator's withdrawal to its scopes
+func (m *PrometheusMetrics) RecordWithdrawal(network string, scopes []string, kind string, gwei uint64) {
+	for _, scope := range scopes {
+		m.WithdrawalsGwei.WithLabelValues(scope, kind, network).Add(float64(gwei))
+	}
+}
+
+// RecordPayloadValue adds a watched proposal's execution payload value to its scopes
+func (m *PrometheusMetrics) RecordPayloadValue(network string, scopes []string, wei float64) {
+	for _, scope := range scopes {
+		m.BlockELRewardsWei.WithLabelValues(scope, network).Add(wei)
+	}
+}
+
+// SetRelayRegistrations sets the registered validators per relay and the number registered with none
+func (m *PrometheusMetrics) SetRelayRegistrations(network string, registered map[string]int, unregistered int) {
+	m.RelayRegistered.Reset()
+	for relay, count := range registered {
+		m.RelayRegistered.WithLabelValues(relay, network).Set(float64(count))
+	}
+	m.RelayUnregisteredValidators.WithLabelValues(network).Set(float64(unregistered))
+}
+
+// ExternalRating is the average third-party effectiveness of a scope's rated validators
+type ExternalRating struct {
+	Effectiveness float64
+	Validators    int
+}
+
+// SetExternalRatings replaces the third-party effectiveness ratings of a provider, by scope
+func (m *PrometheusMetrics) SetExternalRatings(network, provider string, byScope map[string]ExternalRating) {
+	m.ExternalEffectiveness.Reset()
+	m.ExternalRatedValidators.Reset()
+	for scope, rating := range byScope {
+		m.ExternalEffectiveness.WithLabelValues(scope, provider, network).Set(rating.Effectiveness)
+		m.ExternalRatedValidators.WithLabelValues(scope, provider, network).Set(float64(rating.Validators))
+	}
+}
+
+// RecordWrongFeeRecipient counts a watched proposal paying to an unexpected fee recipient in its scopes
+func (m *PrometheusMetrics) RecordWrongFeeRecipient(network string, scopes []string) {
+	for _, scope := range scopes {
+		m.WrongFeeRecipientTotal.WithLabelValues(scope, network).Inc()
+	}
+}
+
+// RecordInferredClient counts a watched proposal in its scopes under the clients inferred from its graffiti
+func (m *PrometheusMetrics) RecordInferredClient(network string, scopes []string, consensus, execution string) {
+	for _, scope := range scopes {
+		m.BlocksByInferredClient.WithLabelValues(scope, consensus, execution, network).Inc()
+	}
+}
+
+// SyncCommitteeMember is a watched validator in the current sync committee
+type SyncCommitteeMember struct {
+	Index     models.ValidatorIndex
+	Label     string
+	Positions int
+}
+
+// SetSyncCommittee replaces the watched members of the current sync committee and its period boundaries
+func (m *PrometheusMetrics) SetSyncCommittee(network string, period uint64, start, end models.Epoch, members []SyncCommitteeMember) {
+	m.SyncCommitteeMember.Reset()
+	for _, member := range members {
+		m.SyncCommitteeMember.WithLabelValues(strconv.FormatUint(uint64(member.Index), 10), member.Label, strconv.FormatUint(period, 10), network).Set(float64(member.Positions))
+	}
+	m.SyncCommitteePeriodEpoch.WithLabelValues("start", network).Set(float64(start))
+	m.SyncCommitteePeriodEpoch.WithLabelValues("end", network).Set(float64(end))
+}
+
+// SetSyncCommitteeMembers replaces the watched member counts per scope of the current and next
+// sync committees; next is nil while the next committee is unknown
+func (m *PrometheusMetrics) SetSyncCommitteeMembers(network string, current, next map[string]int) {
+	m.SyncCommitteeMembers.Reset()
+	for scope, count := range current {
+		m.SyncCommitteeMembers.WithLabelValues(scope, network).Set(float64(count))
+	}
+	m.FutureSyncCommitteeMembers.Reset()
+	for scope, count := range next {
+		m.FutureSyncCommitteeMembers.WithLabelValues(scope, network).Set(float64(count))
+	}
+}
+
+// SetPreActivation replaces the watched key counts per scope and pre-activation stage
+// Every stage of a scope is exported, so keys moving on leave a zero behind
+func (m *PrometheusMetrics) SetPreActivation(network string, counts map[string]map[string]int) {
+	m.PreActivationValidators.Reset()
+	for scope, stages := range counts {
+		for _, stage := range queues.Stages {
+			m.PreActivationValidators.WithLabelValues(scope, stage, network).Set(float64(stages[stage]))
+		}
+	}
+}
+
+// SetQueueFlows sets the pending queue flow rates
+func (m *PrometheusMetrics) SetQueueFlows(network string, flows []queues.Flow) {
+	for _, flow := range flows {
+		if !flow.HasRates {
+			continue
+		}
+		m.PendingQueueInflow.WithLabelValues(flow.Queue, network).Set(flow.InflowPerEpoch)
+		m.PendingQueueOutflow.WithLabelValues(flow.Queue, network).Set(flow.OutflowPerEpoch)
+		m.PendingQueueInflowGwei.WithLabelValues(flow.Queue, network).Set(flow.InflowGweiPerEpoch)
+		m.PendingQueueOutflowGwei.WithLabelValues(flow.Queue, network).Set(flow.OutflowGweiPerEpoch)
+	}
+}
+
+// QueuePosition is a watched validator's place in the activation or exit queue
+type QueuePosition struct {
+	Index     models.ValidatorIndex
+	Label     string
+	Queue     string
+	Ahead     float64 // Full validators of stake ahead
+	ETAEpochs uint64
+}
+
+// SetQueuePositions replaces the queue positions of the watched validators and, when the full
+// validator set was scanned, the network's activation and exit queue estimates
+func (m *PrometheusMetrics) SetQueuePositions(network string, positions []QueuePosition, estimates *queues.Network) {
+	m.ValidatorQueuePosition.Reset()
+	m.ValidatorQueueETA.Reset()
+	for _, p := range positions {
+		index := strconv.FormatUint(uint64(p.Index), 10)
+		m.ValidatorQueuePosition.WithLabelValues(index, p.Label, p.Queue, network).Set(p.Ahead)
+		m.ValidatorQueueETA.WithLabelValues(index, p.Label, p.Queue, network).Set(float64(p.ETAEpochs))
+	}
+	if estimates != nil {
+		m.ActivationQueueEpochs.WithLabelValues(network).Set(float64(estimates.ActivationEpochs))
+		m.ExitQueueEpochs.WithLabelValues(network).Set(float64(estimates.ExitEpochs))
 	}
 }
 
@@ -0,0 +1,12 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// ModeReplay is the mode label of every series exported by a replay run
+// Live runs carry no mode label, so dashboards exclude reprocessed history with mode!="replay"
+const ModeReplay = "replay"
+
+// ReplayRegisterer registers collectors with mode="replay" added to all of their series
+func ReplayRegisterer(registry prometheus.Registerer) prometheus.Registerer {
+	return prometheus.WrapRegistererWith(prometheus.Labels{"mode": ModeReplay}, registry)
+}
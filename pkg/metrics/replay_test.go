@@ -0,0 +1,24 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestReplayRegisterer(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	m := NewPrometheusMetrics(ReplayRegisterer(registry))
+	m.Slot.WithLabelValues("mainnet").Set(1344)
+
+	expected := `
+# HELP eth_slot Current Ethereum slot number
+# TYPE eth_slot gauge
+eth_slot{mode="replay",network="mainnet"} 1344
+`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(expected), "eth_slot"); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,133 @@
+package metrics
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Scorecard dimensions
+const (
+	DimensionDutySuccess       = "duty_success"
+	DimensionInclusionDelay    = "inclusion_delay"
+	DimensionProposalSuccess   = "proposal_success"
+	DimensionSyncParticipation = "sync_participation"
+	DimensionRewardRate        = "reward_rate"
+)
+
+// DefaultScorecardWeights returns the default weight of each scorecard dimension
+func DefaultScorecardWeights() map[string]float64 {
+	return map[string]float64{
+		DimensionDutySuccess:       0.35,
+		DimensionInclusionDelay:    0.15,
+		DimensionProposalSuccess:   0.2,
+		DimensionSyncParticipation: 0.1,
+		DimensionRewardRate:        0.2,
+	}
+}
+
+// ResolveScorecardWeights merges configured weights over the defaults
+func ResolveScorecardWeights(configured map[string]float64) (map[string]float64, error) {
+	weights := DefaultScorecardWeights()
+	for dimension, weight := range configured {
+		if _, ok := weights[dimension]; !ok {
+			return nil, fmt.Errorf("unknown scorecard dimension %q", dimension)
+		}
+		if weight < 0 {
+			return nil, fmt.Errorf("scorecard weight for %q must not be negative", dimension)
+		}
+		weights[dimension] = weight
+	}
+	return weights, nil
+}
+
+// Scorecard is a composite per-label score with each dimension normalized to 0-100
+// Dimensions without data are null and excluded from the composite score
+type Scorecard struct {
+	Label      string              `json:"label"`
+	Validators int                 `json:"validators"`
+	Score      *float64            `json:"score"`
+	Dimensions map[string]*float64 `json:"dimensions"`
+}
+
+// ComputeScorecard builds the scorecard for one label, comparing rewards against the network baseline if available
+func ComputeScorecard(m *MetricsByLabel, network *MetricsByLabel, weights map[string]float64) *Scorecard {
+	card := &Scorecard{
+		Label:      m.Label,
+		Validators: m.ValidatorCount,
+		Dimensions: map[string]*float64{
+			DimensionDutySuccess:       nil,
+			DimensionInclusionDelay:    nil,
+			DimensionProposalSuccess:   nil,
+			DimensionSyncParticipation: nil,
+			DimensionRewardRate:        nil,
+		},
+	}
+
+	if m.AttestationDuties > 0 {
+		card.Dimensions[DimensionDutySuccess] = score(m.AttestationDutiesRate * 100)
+	}
+
+	if m.InclusionDelayAvg > 0 {
+		// Inclusion in the next slot scores 100, a delay of two slots 50
+		card.Dimensions[DimensionInclusionDelay] = score(100 / m.InclusionDelayAvg)
+	}
+
+	if proposals := m.ProposedBlocks + m.MissedBlocks; proposals > 0 {
+		card.Dimensions[DimensionProposalSuccess] = score(float64(m.ProposedBlocks) * 100 / float64(proposals))
+	}
+
+	if blocks := m.SyncCommitteeSigned + m.SyncCommitteeMissed; blocks > 0 {
+		card.Dimensions[DimensionSyncParticipation] = score(float64(m.SyncCommitteeSigned) * 100 / float64(blocks))
+	}
+
+	if m.IdealConsensusRewards > 0 {
+		rate := m.ConsensusRewardsRate
+		if network != nil && network.ConsensusRewardsRate > 0 {
+			// Relative to the network: matching the network scores 100
+			rate = rate / network.ConsensusRewardsRate
+		}
+		card.Dimensions[DimensionRewardRate] = score(rate * 100)
+	}
+
+	// Weighted average over available dimensions (weights renormalized)
+	var total, weightSum float64
+	for dimension, value := range card.Dimensions {
+		if value == nil {
+			continue
+		}
+		total += *value * weights[dimension]
+		weightSum += weights[dimension]
+	}
+	if weightSum > 0 {
+		card.Score = score(total / weightSum)
+	}
+
+	return card
+}
+
+// ComputeScorecards builds scorecards for every label, sorted by label
+func ComputeScorecards(metricsByLabel map[string]*MetricsByLabel, weights map[string]float64) []*Scorecard {
+	network := metricsByLabel["scope:all-network"]
+
+	cards := make([]*Scorecard, 0, len(metricsByLabel))
+	for label, m := range metricsByLabel {
+		if label == "scope:all-network" {
+			continue
+		}
+		cards = append(cards, ComputeScorecard(m, network, weights))
+	}
+
+	sort.Slice(cards, func(i, j int) bool { return cards[i].Label < cards[j].Label })
+	return cards
+}
+
+// score clamps a value to 0-100 and returns a pointer for JSON nullability
+func score(value float64) *float64 {
+	if value < 0 {
+		value = 0
+	}
+	if value > 100 {
+		value = 100
+	}
+	return &value
+}
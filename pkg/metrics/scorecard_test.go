@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"math"
+	"testing"
+)
+
+func TestComputeScorecard(t *testing.T) {
+	m := &MetricsByLabel{
+		Label:                    "operator:a",
+		ValidatorCount:           10,
+		AttestationDuties:        100,
+		AttestationDutiesSuccess: 98,
+		AttestationDutiesRate:    0.98,
+		ProposedBlocks:           3,
+		MissedBlocks:             1,
+	}
+
+	weights := DefaultScorecardWeights()
+	card := ComputeScorecard(m, nil, weights)
+
+	if card.Dimensions[DimensionDutySuccess] == nil || *card.Dimensions[DimensionDutySuccess] != 98 {
+		t.Errorf("Expected duty success 98, got %v", card.Dimensions[DimensionDutySuccess])
+	}
+	if card.Dimensions[DimensionProposalSuccess] == nil || *card.Dimensions[DimensionProposalSuccess] != 75 {
+		t.Errorf("Expected proposal success 75, got %v", card.Dimensions[DimensionProposalSuccess])
+	}
+	if card.Dimensions[DimensionRewardRate] != nil {
+		t.Error("Expected reward rate to be null without rewards data")
+	}
+
+	// Only duty success and proposal success are available, weights are renormalized
+	expected := (98*weights[DimensionDutySuccess] + 75*weights[DimensionProposalSuccess]) /
+		(weights[DimensionDutySuccess] + weights[DimensionProposalSuccess])
+	if card.Score == nil || math.Abs(*card.Score-expected) > 1e-9 {
+		t.Errorf("Expected score %.4f, got %v", expected, card.Score)
+	}
+}
+
+func TestComputeScorecardRelativeToNetwork(t *testing.T) {
+	m := &MetricsByLabel{
+		Label:                 "operator:a",
+		IdealConsensusRewards: 1000,
+		ConsensusRewards:      900,
+		ConsensusRewardsRate:  0.9,
+	}
+	network := &MetricsByLabel{
+		Label:                "scope:all-network",
+		ConsensusRewardsRate: 0.95,
+	}
+
+	card := ComputeScorecard(m, network, DefaultScorecardWeights())
+
+	expected := 0.9 / 0.95 * 100
+	if value := card.Dimensions[DimensionRewardRate]; value == nil || math.Abs(*value-expected) > 1e-9 {
+		t.Errorf("Expected reward rate %.4f, got %v", expected, value)
+	}
+}
+
+func TestComputeScorecardSyncParticipation(t *testing.T) {
+	m := &MetricsByLabel{
+		Label:               "operator:a",
+		SyncCommitteeSigned: 30,
+		SyncCommitteeMissed: 2,
+	}
+
+	card := ComputeScorecard(m, nil, DefaultScorecardWeights())
+
+	if value := card.Dimensions[DimensionSyncParticipation]; value == nil || *value != 93.75 {
+		t.Errorf("Expected sync participation 93.75, got %v", value)
+	}
+	if card.Score == nil || *card.Score != 93.75 {
+		t.Errorf("Expected the only dimension as the score, got %v", card.Score)
+	}
+}
+
+func TestComputeScorecardNoData(t *testing.T) {
+	card := ComputeScorecard(&MetricsByLabel{Label: "operator:new"}, nil, DefaultScorecardWeights())
+	if card.Score != nil {
+		t.Errorf("Expected null score without data, got %v", *card.Score)
+	}
+}
+
+func TestResolveScorecardWeights(t *testing.T) {
+	weights, err := ResolveScorecardWeights(map[string]float64{DimensionDutySuccess: 1})
+	if err != nil {
+		t.Fatalf("ResolveScorecardWeights failed: %v", err)
+	}
+	if weights[DimensionDutySuccess] != 1 {
+		t.Errorf("Expected overridden weight 1, got %v", weights[DimensionDutySuccess])
+	}
+	if weights[DimensionRewardRate] != DefaultScorecardWeights()[DimensionRewardRate] {
+		t.Error("Expected unspecified weights to keep their defaults")
+	}
+
+	if _, err := ResolveScorecardWeights(map[string]float64{"uptime": 1}); err == nil {
+		t.Error("Expected error for unknown dimension")
+	}
+	if _, err := ResolveScorecardWeights(map[string]float64{DimensionDutySuccess: -1}); err == nil {
+		t.Error("Expected error for negative weight")
+	}
+}
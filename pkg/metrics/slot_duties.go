@@ -0,0 +1,39 @@
+package metrics
+
+import "github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+
+// SlotDuties tallies the outcomes of the validators that had a duty in one slot
+// Only validators with a duty that slot are counted, with their own stake
+type SlotDuties struct {
+	Performed      uint64
+	Missed         uint64
+	PerformedStake float64 // ETH of effective balance
+	MissedStake    float64
+}
+
+// Add records the outcome of one validator's duty
+func (d *SlotDuties) Add(performed bool, effectiveBalance models.Gwei) {
+	stake := float64(effectiveBalance) / 1e9
+	if performed {
+		d.Performed++
+		d.PerformedStake += stake
+	} else {
+		d.Missed++
+		d.MissedStake += stake
+	}
+}
+
+// SlotDutiesByLabel tallies a slot's duty outcomes per label
+type SlotDutiesByLabel map[string]*SlotDuties
+
+// Add records the outcome of one validator's duty under each of its labels
+func (s SlotDutiesByLabel) Add(labels []string, performed bool, effectiveBalance models.Gwei) {
+	for _, label := range labels {
+		duties, ok := s[label]
+		if !ok {
+			duties = &SlotDuties{}
+			s[label] = duties
+		}
+		duties.Add(performed, effectiveBalance)
+	}
+}
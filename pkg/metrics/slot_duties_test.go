@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestSlotDutiesByLabel(t *testing.T) {
+	duties := SlotDutiesByLabel{}
+	duties.Add([]string{"scope:watched", "operator:a"}, true, 32_000_000_000)
+	duties.Add([]string{"scope:watched", "operator:b"}, false, 2048_000_000_000)
+	duties.Add([]string{"scope:watched", "operator:a"}, true, 31_000_000_000)
+
+	watched := duties["scope:watched"]
+	if watched.Performed != 2 || watched.Missed != 1 {
+		t.Errorf("Expected 2 performed and 1 missed, got %+v", watched)
+	}
+	if watched.PerformedStake != 63 || watched.MissedStake != 2048 {
+		t.Errorf("Expected 63 ETH performed and 2048 ETH missed, got %+v", watched)
+	}
+	if a := duties["operator:a"]; a.Performed != 2 || a.Missed != 0 {
+		t.Errorf("Expected operator:a to have 2 performed duties, got %+v", a)
+	}
+}
+
+func TestSetSlotDuties(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	m := NewPrometheusMetrics(registry)
+
+	m.SetSlotDuties("mainnet", SlotDutiesByLabel{
+		"operator:a": {Performed: 3, PerformedStake: 96},
+		"operator:b": {Missed: 1, MissedStake: 2048},
+	})
+	// A later slot without duties for operator:b must not keep reporting its miss
+	m.SetSlotDuties("mainnet", SlotDutiesByLabel{
+		"operator:a": {Performed: 1, Missed: 1, PerformedStake: 32, MissedStake: 32},
+	})
+
+	expected := `
+# HELP eth_missed_duties_at_slot_scaled Missed attestation duties in the last evaluated slot, scaled by the missing validators' stake (full validator units, 32 ETH on mainnet)
+# TYPE eth_missed_duties_at_slot_scaled gauge
+eth_missed_duties_at_slot_scaled{network="mainnet",scope="operator:a"} 1
+# HELP eth_performed_duties_at_slot Performed attestation duties in the last evaluated slot (validators with a duty that slot only)
+# TYPE eth_performed_duties_at_slot gauge
+eth_performed_duties_at_slot{network="mainnet",scope="operator:a"} 1
+`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(expected),
+		"eth_performed_duties_at_slot", "eth_missed_duties_at_slot_scaled"); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,165 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DataSource identifies an upstream data feed that a group of metrics is derived from
+type DataSource string
+
+const (
+	SourceValidators   DataSource = "validators"
+	SourceAttestations DataSource = "attestations"
+	SourceLiveness     DataSource = "liveness"
+	SourceRewards      DataSource = "rewards"
+	SourcePrice        DataSource = "price"
+	SourceQueues       DataSource = "queues"
+)
+
+// allSources lists every tracked data source
+var allSources = []DataSource{SourceValidators, SourceAttestations, SourceLiveness, SourceRewards, SourcePrice, SourceQueues}
+
+// MarkUpdated records that data from a source was refreshed successfully
+func (m *PrometheusMetrics) MarkUpdated(source DataSource, network string) {
+	now := time.Now()
+
+	m.stalenessMu.Lock()
+	m.lastUpdated[source] = now
+	m.stalenessMu.Unlock()
+
+	m.DataLastUpdated.WithLabelValues(string(source), network).Set(float64(now.Unix()))
+}
+
+// SetStaleAfter sets how long a data source may go without updates before its series are deleted
+// A zero duration disables stale series deletion
+func (m *PrometheusMetrics) SetStaleAfter(d time.Duration) {
+	m.stalenessMu.Lock()
+	defer m.stalenessMu.Unlock()
+
+	m.staleAfter = d
+}
+
+// LastUpdated returns when a data source was last refreshed, false if it never was
+func (m *PrometheusMetrics) LastUpdated(source DataSource) (time.Time, bool) {
+	m.stalenessMu.RLock()
+	defer m.stalenessMu.RUnlock()
+
+	last, ok := m.lastUpdated[source]
+	return last, ok
+}
+
+// SetPaused marks data sources stale whatever their last update, while their processing is paused
+func (m *PrometheusMetrics) SetPaused(sources []DataSource, paused bool) {
+	m.stalenessMu.Lock()
+	defer m.stalenessMu.Unlock()
+
+	for _, source := range sources {
+		if paused {
+			m.paused[source] = true
+		} else {
+			delete(m.paused, source)
+		}
+	}
+}
+
+// IsStale returns true if a data source has not been updated within the staleness window or is paused
+func (m *PrometheusMetrics) IsStale(source DataSource, now time.Time) bool {
+	m.stalenessMu.RLock()
+	defer m.stalenessMu.RUnlock()
+
+	if m.paused[source] {
+		return true
+	}
+	if m.staleAfter <= 0 {
+		return false
+	}
+
+	last, ok := m.lastUpdated[source]
+	if !ok {
+		last = m.startTime
+	}
+	return now.Sub(last) > m.staleAfter
+}
+
+// sourceGauges returns the gauges whose values are derived from a data source
+func (m *PrometheusMetrics) sourceGauges(source DataSource) []*prometheus.GaugeVec {
+	switch source {
+	case SourceValidators:
+		return []*prometheus.GaugeVec{
+			m.ValidatorStatusCount,
+			m.ValidatorStatusScaledCount,
+			m.ValidatorTypeCount,
+			m.ValidatorTypeScaledCount,
+			m.SlashedValidators,
+			m.ValidatorBalance,
+			m.EffectiveBalance,
+		}
+	case SourceAttestations:
+		return []*prometheus.GaugeVec{
+			m.PerformedDutiesAtSlot,
+			m.PerformedDutiesAtSlotScaled,
+			m.MissedDutiesAtSlot,
+			m.MissedDutiesAtSlotScaled,
+			m.DutiesRate,
+			m.DutiesRateScaled,
+			m.MissedConsecutiveAttestations,
+			m.MissedConsecutiveAttestationsScaled,
+			m.AttestationInclusionDelay,
+			m.ExpectedAggregationDuties,
+			m.CommitteeAggregatesIncluded,
+			m.CommitteeAggregatesMissed,
+			m.CommitteeAggregateSuccessRate,
+		}
+	case SourceLiveness:
+		return []*prometheus.GaugeVec{
+			m.MissedAttestations,
+			m.MissedAttestationsScaled,
+		}
+	case SourceRewards:
+		return []*prometheus.GaugeVec{
+			m.IdealConsensusRewardsGwei,
+			m.ActualConsensusRewardsGwei,
+			m.ConsensusRewardsRate,
+			m.EstimatedAPR,
+			m.SuboptimalSourcesRate,
+			m.SuboptimalTargetsRate,
+			m.SuboptimalHeadsRate,
+		}
+	case SourcePrice:
+		return []*prometheus.GaugeVec{
+			m.CurrentPriceDollars,
+		}
+	case SourceQueues:
+		return []*prometheus.GaugeVec{
+			m.PendingDepositsCount,
+			m.PendingDepositsValue,
+			m.PendingConsolidationsCount,
+			m.PendingWithdrawalsCount,
+			m.PendingQueueInflow,
+			m.PendingQueueOutflow,
+			m.PendingQueueInflowGwei,
+			m.PendingQueueOutflowGwei,
+			m.ValidatorQueuePosition,
+			m.ValidatorQueueETA,
+			m.ActivationQueueEpochs,
+			m.ExitQueueEpochs,
+		}
+	}
+	return nil
+}
+
+// applyStaleness deletes series of stale data sources so dashboards show gaps instead of frozen values
+func (m *PrometheusMetrics) applyStaleness(network string, now time.Time) {
+	for _, source := range allSources {
+		if m.IsStale(source, now) {
+			for _, gauge := range m.sourceGauges(source) {
+				gauge.Reset()
+			}
+			m.DataStale.WithLabelValues(string(source), network).Set(1)
+		} else {
+			m.DataStale.WithLabelValues(string(source), network).Set(0)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestStaleSeriesDeleted(t *testing.T) {
+	m := NewPrometheusMetrics(prometheus.NewRegistry())
+	m.SetStaleAfter(time.Minute)
+
+	metricsByLabel := map[string]*MetricsByLabel{
+		"scope:watched": {
+			Label:                 "scope:watched",
+			ValidatorCount:        1,
+			StakeCount:            1,
+			IdealConsensusRewards: 100,
+			ConsensusRewards:      90,
+			ConsensusRewardsRate:  0.9,
+		},
+	}
+
+	m.MarkUpdated(SourceRewards, "mainnet")
+	m.UpdateMetrics(metricsByLabel, 100, 3, "mainnet")
+
+	if count := testutil.CollectAndCount(m.ConsensusRewardsRate); count != 1 {
+		t.Fatalf("Expected 1 consensus rewards rate series while fresh, got %d", count)
+	}
+	if value := testutil.ToFloat64(m.DataStale.WithLabelValues("rewards", "mainnet")); value != 0 {
+		t.Errorf("Expected rewards not to be stale, got %v", value)
+	}
+
+	// Simulate the last rewards update happening long ago
+	m.stalenessMu.Lock()
+	m.lastUpdated[SourceRewards] = time.Now().Add(-2 * time.Minute)
+	m.stalenessMu.Unlock()
+
+	m.UpdateMetrics(metricsByLabel, 101, 3, "mainnet")
+
+	if count := testutil.CollectAndCount(m.ConsensusRewardsRate); count != 0 {
+		t.Errorf("Expected stale consensus rewards rate series to be deleted, got %d", count)
+	}
+	if value := testutil.ToFloat64(m.DataStale.WithLabelValues("rewards", "mainnet")); value != 1 {
+		t.Errorf("Expected rewards to be marked stale, got %v", value)
+	}
+}
+
+func TestStalenessDisabled(t *testing.T) {
+	m := NewPrometheusMetrics(prometheus.NewRegistry())
+	m.SetStaleAfter(0)
+
+	if m.IsStale(SourceLiveness, time.Now().Add(24*time.Hour)) {
+		t.Error("Expected staleness to be disabled with zero window")
+	}
+}
+
+func TestNeverUpdatedSourceBecomesStale(t *testing.T) {
+	m := NewPrometheusMetrics(prometheus.NewRegistry())
+	m.SetStaleAfter(time.Minute)
+
+	if m.IsStale(SourceLiveness, time.Now()) {
+		t.Error("Expected source to be fresh right after startup")
+	}
+	if !m.IsStale(SourceLiveness, time.Now().Add(2*time.Minute)) {
+		t.Error("Expected never-updated source to become stale after the window")
+	}
+}
+
+func TestPausedSourceIsStale(t *testing.T) {
+	m := NewPrometheusMetrics(prometheus.NewRegistry())
+	m.SetStaleAfter(0)
+	m.MarkUpdated(SourceAttestations, "mainnet")
+
+	m.SetPaused([]DataSource{SourceAttestations}, true)
+	if !m.IsStale(SourceAttestations, time.Now()) {
+		t.Error("Expected paused source to be stale, even with staleness disabled")
+	}
+	if m.IsStale(SourceValidators, time.Now()) {
+		t.Error("Expected other sources not to be affected by a pause")
+	}
+
+	m.SetPaused([]DataSource{SourceAttestations}, false)
+	if m.IsStale(SourceAttestations, time.Now()) {
+		t.Error("Expected source to be fresh once resumed")
+	}
+}
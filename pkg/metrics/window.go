@@ -0,0 +1,128 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// MaxWindowEpochs is the longest trailing window kept (a week of epochs)
+const MaxWindowEpochs = 1575
+
+// WindowCounters are the cumulative counters exported over trailing windows, in export order
+var WindowCounters = []string{"attestation_duties", "attestation_duties_success", "missed_attestations", "proposed_blocks", "missed_blocks"}
+
+// windowValues holds one value per WindowCounters entry
+type windowValues [5]uint64
+
+// windowCounterValues reads the windowed counters of a label
+func windowCounterValues(m *MetricsByLabel) windowValues {
+	return windowValues{m.AttestationDuties, m.AttestationDutiesSuccess, m.MissedAttestations, m.ProposedBlocks, m.MissedBlocks}
+}
+
+// windowBucket is what a label's counters gained during one epoch
+type windowBucket struct {
+	epoch  models.Epoch
+	gained windowValues
+}
+
+// CounterWindows sums what each label's counters gained over trailing windows of epochs, so the
+// windowed series mean the same whatever the counters' reset policy
+type CounterWindows struct {
+	mu      sync.Mutex
+	epochs  []int                     // Window lengths
+	longest int                       // Buckets kept per label
+	last    map[string]windowValues   // Counters at the previous observation
+	buckets map[string][]windowBucket // Per label, oldest first
+}
+
+// NewCounterWindows creates windows of the given lengths in epochs, or nil if there are none
+func NewCounterWindows(epochs []int) *CounterWindows {
+	if len(epochs) == 0 {
+		return nil
+	}
+
+	w := &CounterWindows{
+		epochs:  epochs,
+		last:    make(map[string]windowValues),
+		buckets: make(map[string][]windowBucket),
+	}
+	for _, n := range epochs {
+		w.longest = max(w.longest, n)
+	}
+	return w
+}
+
+// Observe adds what every label's counters gained since the previous observation to the epoch's bucket
+// A counter that went down lost validators, which gains nothing; after a reset, Rebase counts from zero
+func (w *CounterWindows) Observe(epoch models.Epoch, metricsByLabel map[string]*MetricsByLabel) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for label, m := range metricsByLabel {
+		current := windowCounterValues(m)
+		prev, seen := w.last[label]
+		w.last[label] = current
+		if !seen {
+			// The first observation is the baseline
+			continue
+		}
+
+		var gained windowValues
+		for i := range current {
+			if current[i] > prev[i] {
+				gained[i] = current[i] - prev[i]
+			}
+		}
+
+		buckets := w.buckets[label]
+		if n := len(buckets); n > 0 && buckets[n-1].epoch == epoch {
+			for i := range gained {
+				buckets[n-1].gained[i] += gained[i]
+			}
+		} else {
+			buckets = append(buckets, windowBucket{epoch: epoch, gained: gained})
+		}
+		for len(buckets) > 0 && uint64(buckets[0].epoch)+uint64(w.longest) <= uint64(epoch) {
+			buckets = buckets[1:]
+		}
+		w.buckets[label] = buckets
+	}
+
+	for label := range w.last {
+		if _, ok := metricsByLabel[label]; !ok {
+			delete(w.last, label)
+			delete(w.buckets, label)
+		}
+	}
+}
+
+// Rebase records that the counters were reset, so the next observation counts from zero
+func (w *CounterWindows) Rebase() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for label := range w.last {
+		w.last[label] = windowValues{}
+	}
+}
+
+// Sums returns what a label's counters gained over each window ending at epoch, keyed by window length
+func (w *CounterWindows) Sums(label string, epoch models.Epoch) map[int]windowValues {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	sums := make(map[int]windowValues, len(w.epochs))
+	for _, n := range w.epochs {
+		var sum windowValues
+		for _, bucket := range w.buckets[label] {
+			if uint64(bucket.epoch)+uint64(n) > uint64(epoch) {
+				for i := range sum {
+					sum[i] += bucket.gained[i]
+				}
+			}
+		}
+		sums[n] = sum
+	}
+	return sums
+}
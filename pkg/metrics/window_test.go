@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCounterWindows(t *testing.T) {
+	w := NewCounterWindows([]int{2, 4})
+	observe := func(epoch, duties, missed uint64) {
+		w.Observe(models.Epoch(epoch), map[string]*MetricsByLabel{
+			"scope:watched": {AttestationDuties: duties, MissedAttestations: missed},
+		})
+	}
+
+	observe(10, 4, 0) // Baseline
+	observe(11, 8, 1)
+	observe(12, 12, 1)
+	observe(12, 13, 1) // Later slots of the same epoch add to its bucket
+	observe(13, 2, 0)  // Counters reset without Rebase: nothing is gained
+	w.Rebase()
+	observe(14, 3, 1) // After Rebase, gains count from zero
+
+	sums := w.Sums("scope:watched", 14)
+	if got := sums[2]; got[0] != 3 || got[2] != 1 {
+		t.Errorf("Expected 3 duties and 1 miss over 2 epochs, got %v", got)
+	}
+	if got := sums[4]; got[0] != 12 || got[2] != 2 {
+		t.Errorf("Expected 12 duties and 2 misses over 4 epochs, got %v", got)
+	}
+
+	// Buckets older than the longest window are dropped, labels that disappear are forgotten
+	observe(20, 3, 1)
+	if got := w.Sums("scope:watched", 20)[4]; got[0] != 0 {
+		t.Errorf("Expected an empty window, got %v", got)
+	}
+	w.Observe(21, map[string]*MetricsByLabel{})
+	if len(w.buckets) != 0 || len(w.last) != 0 {
+		t.Errorf("Expected the label to be forgotten, got %v", w.buckets)
+	}
+
+	if NewCounterWindows(nil) != nil {
+		t.Error("Expected no windows without window lengths")
+	}
+}
+
+func TestCounterWindowMetrics(t *testing.T) {
+	m := NewPrometheusMetrics(prometheus.NewRegistry())
+	m.SetCounterWindows([]int{225})
+
+	m.UpdateMetrics(map[string]*MetricsByLabel{"scope:watched": {ProposedBlocks: 1}}, 320, 10, "mainnet")
+	m.RecordCounterReset("mainnet", time.Unix(1700000000, 0))
+	m.UpdateMetrics(map[string]*MetricsByLabel{"scope:watched": {ProposedBlocks: 1}}, 352, 11, "mainnet")
+
+	if value := testutil.ToFloat64(m.CounterWindow.WithLabelValues("scope:watched", "proposed_blocks", "225", "mainnet")); value != 1 {
+		t.Errorf("Expected 1 proposal in the window, got %v", value)
+	}
+	// The reset rebased the block counters, so the proposal after it is counted
+	if value := testutil.ToFloat64(m.BlockProposalsHeadTotal.WithLabelValues("scope:watched", "mainnet")); value != 2 {
+		t.Errorf("Expected 2 proposed blocks, got %v", value)
+	}
+	if value := testutil.ToFloat64(m.CountersResetTimestamp.WithLabelValues("mainnet")); value != 1700000000 {
+		t.Errorf("Expected the reset timestamp, got %v", value)
+	}
+}
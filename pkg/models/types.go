@@ -66,11 +66,60 @@ type Genesis struct {
 	GenesisValidatorsRoot string `json:"genesis_validators_root"`
 }
 
+// SyncStatus is a beacon node's /eth/v1/node/syncing status
+type SyncStatus struct {
+	HeadSlot     Slot `json:"head_slot,string"`
+	SyncDistance Slot `json:"sync_distance,string"`
+	IsSyncing    bool `json:"is_syncing"`
+	IsOptimistic bool `json:"is_optimistic"` // Head not yet validated by the execution client
+	ELOffline    bool `json:"el_offline"`
+}
+
+// DefaultStakeUnit is the effective balance of a full mainnet validator (32 ETH)
+const DefaultStakeUnit Gwei = 32_000_000_000
+
 // Spec represents the beacon chain specification
 type Spec struct {
 	SecondsPerSlot               uint64 `json:"SECONDS_PER_SLOT,string"`
 	SlotsPerEpoch                uint64 `json:"SLOTS_PER_EPOCH,string"`
 	EpochsPerSyncCommitteePeriod uint64 `json:"EPOCHS_PER_SYNC_COMMITTEE_PERIOD,string"`
+	MaxEffectiveBalance          Gwei   `json:"MAX_EFFECTIVE_BALANCE,string"`
+	MinActivationBalance         Gwei   `json:"MIN_ACTIVATION_BALANCE,string"` // Electra, where MAX_EFFECTIVE_BALANCE is no longer a full validator
+	BaseRewardFactor             uint64 `json:"BASE_REWARD_FACTOR,string"`     // 64 on Ethereum, 25 on Gnosis Chain
+	DepositContractAddress       string `json:"DEPOSIT_CONTRACT_ADDRESS"`
+
+	// Churn the activation and exit queues drain by
+	MinPerEpochChurnLimit               uint64 `json:"MIN_PER_EPOCH_CHURN_LIMIT,string"`
+	ChurnLimitQuotient                  uint64 `json:"CHURN_LIMIT_QUOTIENT,string"`
+	MaxPerEpochActivationChurnLimit     uint64 `json:"MAX_PER_EPOCH_ACTIVATION_CHURN_LIMIT,string"`
+	MinPerEpochChurnLimitElectra        Gwei   `json:"MIN_PER_EPOCH_CHURN_LIMIT_ELECTRA,string"`
+	MaxPerEpochActivationExitChurnLimit Gwei   `json:"MAX_PER_EPOCH_ACTIVATION_EXIT_CHURN_LIMIT,string"`
+	MaxSeedLookahead                    uint64 `json:"MAX_SEED_LOOKAHEAD,string"`
+	StakeUnitOverride                   Gwei   `json:"-"` // Set from the config's spec overrides
+}
+
+// StakeUnit returns the effective balance of one full validator, which stake weights are expressed in:
+// MIN_ACTIVATION_BALANCE since Electra, MAX_EFFECTIVE_BALANCE before, 32 ETH if the node reports neither
+// Gnosis Chain reports 32 mGNO (1 GNO)
+func (s *Spec) StakeUnit() Gwei {
+	switch {
+	case s.StakeUnitOverride > 0:
+		return s.StakeUnitOverride
+	case s.MinActivationBalance > 0:
+		return s.MinActivationBalance
+	case s.MaxEffectiveBalance > 0:
+		return s.MaxEffectiveBalance
+	default:
+		return DefaultStakeUnit
+	}
+}
+
+// StakeWeight returns an effective balance in units of a full validator's
+func StakeWeight(effectiveBalance, stakeUnit Gwei) float64 {
+	if stakeUnit == 0 {
+		stakeUnit = DefaultStakeUnit
+	}
+	return float64(effectiveBalance) / float64(stakeUnit)
 }
 
 // BeaconHeader represents a beacon block header
@@ -87,6 +136,51 @@ type BeaconHeader struct {
 	} `json:"header"`
 }
 
+// Checkpoint represents an epoch boundary block
+type Checkpoint struct {
+	Epoch Epoch  `json:"epoch,string"`
+	Root  string `json:"root"`
+}
+
+// FinalityCheckpoints represents the justified and finalized checkpoints of a state
+type FinalityCheckpoints struct {
+	PreviousJustified Checkpoint `json:"previous_justified"`
+	CurrentJustified  Checkpoint `json:"current_justified"`
+	Finalized         Checkpoint `json:"finalized"`
+}
+
+// HeadEvent is the /eth/v1/events head topic payload
+type HeadEvent struct {
+	Slot            Slot   `json:"slot,string"`
+	Block           string `json:"block"`
+	State           string `json:"state"`
+	EpochTransition bool   `json:"epoch_transition"`
+}
+
+// BlockEvent is the /eth/v1/events block topic payload
+type BlockEvent struct {
+	Slot  Slot   `json:"slot,string"`
+	Block string `json:"block"`
+}
+
+// FinalizedCheckpointEvent is the /eth/v1/events finalized_checkpoint topic payload
+type FinalizedCheckpointEvent struct {
+	Block string `json:"block"`
+	State string `json:"state"`
+	Epoch Epoch  `json:"epoch,string"`
+}
+
+// ChainReorgEvent is the /eth/v1/events chain_reorg topic payload
+type ChainReorgEvent struct {
+	Slot         Slot   `json:"slot,string"`
+	Depth        uint64 `json:"depth,string"`
+	OldHeadBlock string `json:"old_head_block"`
+	NewHeadBlock string `json:"new_head_block"`
+	OldHeadState string `json:"old_head_state"`
+	NewHeadState string `json:"new_head_state"`
+	Epoch        Epoch  `json:"epoch,string"`
+}
+
 // Validator represents a beacon chain validator
 type Validator struct {
 	Index   ValidatorIndex  `json:"index,string"`
@@ -121,19 +215,91 @@ type ProposerDutiesResponse struct {
 	Data []ProposerDuty `json:"data"`
 }
 
+// AttesterDuty represents an attestation duty
+type AttesterDuty struct {
+	Pubkey                  string         `json:"pubkey"`
+	ValidatorIndex          ValidatorIndex `json:"validator_index,string"`
+	CommitteeIndex          uint64         `json:"committee_index,string"`
+	CommitteeLength         uint64         `json:"committee_length,string"`
+	CommitteesAtSlot        uint64         `json:"committees_at_slot,string"`
+	ValidatorCommitteeIndex uint64         `json:"validator_committee_index,string"`
+	Slot                    Slot           `json:"slot,string"`
+}
+
+// AttesterDutiesResponse represents the API response for attester duties
+type AttesterDutiesResponse struct {
+	Data []AttesterDuty `json:"data"`
+}
+
 // Block represents a beacon block
 type Block struct {
 	Message struct {
 		Slot          Slot   `json:"slot,string"`
 		ProposerIndex uint64 `json:"proposer_index,string"`
 		Body          struct {
-			ExecutionPayload *struct {
-				FeeRecipient string `json:"fee_recipient"`
+			ProposerSlashings []ProposerSlashing `json:"proposer_slashings"`
+			AttesterSlashings []AttesterSlashing `json:"attester_slashings"`
+			Graffiti          string             `json:"graffiti"`                 // 32 bytes, hex-encoded
+			SyncAggregate     *SyncAggregate     `json:"sync_aggregate,omitempty"` // Altair and later
+			ExecutionPayload  *struct {
+				FeeRecipient string       `json:"fee_recipient"`
+				BlockHash    string       `json:"block_hash"`
+				Withdrawals  []Withdrawal `json:"withdrawals,omitempty"` // Capella and later
 			} `json:"execution_payload,omitempty"`
 		} `json:"body"`
 	} `json:"message"`
 }
 
+// Withdrawal is a balance swept to the execution layer by a block's execution payload
+type Withdrawal struct {
+	Index          uint64         `json:"index,string"`
+	ValidatorIndex ValidatorIndex `json:"validator_index,string"`
+	Address        string         `json:"address"`
+	Amount         Gwei           `json:"amount,string"`
+}
+
+// SyncAggregate is a block's sync committee signature over its parent block root
+type SyncAggregate struct {
+	SyncCommitteeBits      string `json:"sync_committee_bits"` // SSZ bitvector, one bit per committee position
+	SyncCommitteeSignature string `json:"sync_committee_signature"`
+}
+
+// SyncCommittee is a sync committee as returned by the state sync_committees endpoint
+type SyncCommittee struct {
+	Validators []string `json:"validators"` // Member indices by committee position (a validator may hold several)
+}
+
+// SignedBeaconBlockHeader represents a signed block header, as carried by proposer slashings
+type SignedBeaconBlockHeader struct {
+	Message struct {
+		Slot          Slot           `json:"slot,string"`
+		ProposerIndex ValidatorIndex `json:"proposer_index,string"`
+		ParentRoot    string         `json:"parent_root"`
+		StateRoot     string         `json:"state_root"`
+		BodyRoot      string         `json:"body_root"`
+	} `json:"message"`
+	Signature string `json:"signature"`
+}
+
+// ProposerSlashing represents two conflicting block headers signed by the same proposer
+type ProposerSlashing struct {
+	SignedHeader1 SignedBeaconBlockHeader `json:"signed_header_1"`
+	SignedHeader2 SignedBeaconBlockHeader `json:"signed_header_2"`
+}
+
+// IndexedAttestation represents an attestation with its attesting validator indices
+type IndexedAttestation struct {
+	AttestingIndices []string        `json:"attesting_indices"`
+	Data             AttestationData `json:"data"`
+	Signature        string          `json:"signature"`
+}
+
+// AttesterSlashing represents two conflicting attestations; validators in both are slashed
+type AttesterSlashing struct {
+	Attestation1 IndexedAttestation `json:"attestation_1"`
+	Attestation2 IndexedAttestation `json:"attestation_2"`
+}
+
 // BlockResponse represents the API response for a block
 type BlockResponse struct {
 	Data Block `json:"data"`
@@ -214,10 +380,21 @@ type RewardsResponse struct {
 	} `json:"data"`
 }
 
+// BlockRewards represents the consensus layer rewards of a block's proposer
+type BlockRewards struct {
+	ProposerIndex     ValidatorIndex `json:"proposer_index,string"`
+	Total             Gwei           `json:"total,string"`
+	Attestations      Gwei           `json:"attestations,string"`
+	SyncAggregate     Gwei           `json:"sync_aggregate,string"`
+	ProposerSlashings Gwei           `json:"proposer_slashings,string"`
+	AttesterSlashings Gwei           `json:"attester_slashings,string"`
+}
+
 // PendingDeposit represents a pending deposit
 type PendingDeposit struct {
 	Pubkey string `json:"pubkey"`
 	Amount Gwei   `json:"amount,string"`
+	Slot   Slot   `json:"slot,string"`
 }
 
 // PendingDepositsResponse represents the API response for pending deposits
@@ -238,9 +415,10 @@ type PendingConsolidationsResponse struct {
 
 // PendingWithdrawal represents a pending withdrawal
 type PendingWithdrawal struct {
-	Index          uint64         `json:"index,string"`
-	ValidatorIndex ValidatorIndex `json:"validator_index,string"`
-	Amount         Gwei           `json:"amount,string"`
+	Index             uint64         `json:"index,string"`
+	ValidatorIndex    ValidatorIndex `json:"validator_index,string"`
+	Amount            Gwei           `json:"amount,string"`
+	WithdrawableEpoch Epoch          `json:"withdrawable_epoch,string"`
 }
 
 // PendingWithdrawalsResponse represents the API response for pending withdrawals
@@ -255,16 +433,476 @@ type StateIDRequest struct {
 
 // Config represents the watcher configuration
 type Config struct {
-	Network           string       `yaml:"network"`
-	BeaconURL         string       `yaml:"beacon_url"`
-	BeaconTimeout     Duration     `yaml:"beacon_timeout_sec"`
-	MetricsPort       int          `yaml:"metrics_port"`
-	WatchedKeys       []WatchedKey `yaml:"watched_keys"`
-	SlackToken        string       `yaml:"slack_token,omitempty"`
-	SlackChannel      string       `yaml:"slack_channel,omitempty"`
-	ReplayStartAtTS   *uint64      `yaml:"replay_start_at_ts,omitempty"`
-	ReplayEndAtTS     *uint64      `yaml:"replay_end_at_ts,omitempty"`
-	LoadAllValidators *bool        `yaml:"load_all_validators,omitempty"` // Default true - load full 2M+ validator set for network comparison
+	Path                     string             `yaml:"-"` // File the config was loaded from, re-read on reload
+	Network                  string             `yaml:"network"`
+	BeaconURL                string             `yaml:"beacon_url"`
+	BeaconURLs               []string           `yaml:"beacon_urls,omitempty"`  // Failover endpoints, used instead of beacon_url when set
+	BeaconNodes              []BeaconNode       `yaml:"beacon_nodes,omitempty"` // Failover endpoints with their own credentials, used instead of both
+	BeaconTimeout            Duration           `yaml:"beacon_timeout_sec"`
+	BeaconRateLimit          BeaconRateLimit    `yaml:"beacon_rate_limit,omitempty"`
+	BeaconAuth               BeaconAuth         `yaml:"beacon_auth,omitempty"`
+	BeaconRetry              BeaconRetry        `yaml:"beacon_retry,omitempty"`
+	MetricsPort              int                `yaml:"metrics_port"`
+	GRPCHealthPort           int                `yaml:"grpc_health_port,omitempty"` // gRPC health checking protocol (0 disables)
+	Health                   Health             `yaml:"health,omitempty"`
+	WatchedKeys              []WatchedKey       `yaml:"watched_keys"`
+	WatchedKeysURL           string             `yaml:"watched_keys_url,omitempty"`            // Remote key list (JSON or YAML), merged with watched_keys
+	WatchedKeysHeaders       map[string]string  `yaml:"watched_keys_headers,omitempty"`        // Request headers for watched_keys_url, values may use ${ENV_VARS}
+	WatchedKeysRefreshEpochs int                `yaml:"watched_keys_refresh_epochs,omitempty"` // How often the remote key list is refetched
+	Web3Signer               Web3Signer         `yaml:"web3signer,omitempty"`
+	SlackToken               string             `yaml:"slack_token,omitempty"`
+	SlackChannel             string             `yaml:"slack_channel,omitempty"`
+	Explorer                 Explorer           `yaml:"explorer,omitempty"`
+	Discord                  Discord            `yaml:"discord,omitempty"`
+	PagerDuty                PagerDuty          `yaml:"pagerduty,omitempty"`
+	Telegram                 Telegram           `yaml:"telegram,omitempty"`
+	CriticalAlerts           CriticalAlerts     `yaml:"critical_alerts,omitempty"`
+	ProposalLookahead        ProposalLookahead  `yaml:"proposal_lookahead,omitempty"`
+	Rules                    []AlertRule        `yaml:"rules,omitempty"`
+	ReplayStartAtTS          *uint64            `yaml:"replay_start_at_ts,omitempty"`
+	ReplayEndAtTS            *uint64            `yaml:"replay_end_at_ts,omitempty"`
+	LoadAllValidators        *bool              `yaml:"load_all_validators,omitempty"` // Default true - load full 2M+ validator set for network comparison
+	UseEvents                *bool              `yaml:"use_events,omitempty"`          // Default true - trigger slot processing from the beacon event stream
+	SlotLagSeconds           int                `yaml:"slot_lag_seconds"`              // Wait after a slot's end before processing it, for attestations to propagate
+	LivenessSlot             int                `yaml:"liveness_slot"`                 // Slot of the epoch when the previous epoch's liveness is checked
+	RewardsSlot              int                `yaml:"rewards_slot"`                  // Slot of the epoch when the rewards of two epochs ago are fetched
+	ConfigReloadSlot         int                `yaml:"config_reload_slot"`            // Slot of the epoch when the config file is re-read
+	HTTPServer               HTTPServer         `yaml:"http_server,omitempty"`
+	Log                      Logging            `yaml:"log,omitempty"`
+	Tracing                  Tracing            `yaml:"tracing,omitempty"`
+	LogSampling              LogSampling        `yaml:"log_sampling,omitempty"`
+	EventsFile               string             `yaml:"events_file,omitempty"`          // JSON lines file receiving full per-validator event detail
+	EventsFormat             string             `yaml:"events_format,omitempty"`        // json (default), cloudevents or protobuf
+	MembershipFile           string             `yaml:"membership_file,omitempty"`      // JSON lines file persisting the label membership feed
+	StaleDataAfter           Duration           `yaml:"stale_data_after_sec,omitempty"` // Delete series of data sources not updated for this long (0 disables)
+	IndexCacheFile           string             `yaml:"index_cache_file,omitempty"`     // Persisted pubkey -> index resolutions
+	StateFile                string             `yaml:"state_file,omitempty"`           // BoltDB file persisting counters across restarts
+	StateTrends              StateTrends        `yaml:"state_trends,omitempty"`
+	SnapshotFile             string             `yaml:"snapshot_file,omitempty"` // API data and metrics exported every epoch, served by --serve-snapshot
+	Spec                     SpecOverrides      `yaml:"spec,omitempty"`          // Overrides of the beacon node's spec, for custom presets
+	Scorecard                Scorecard          `yaml:"scorecard,omitempty"`
+	PriceRefresh             Duration           `yaml:"price_refresh_interval_sec,omitempty"` // Background ETH price refresh interval
+	EpochStageTimeout        Duration           `yaml:"epoch_stage_timeout_sec,omitempty"`    // Budget of each epoch processing stage (default: two slots)
+	NetworkSample            int                `yaml:"network_sample,omitempty"`             // Active network validators whose attestation rewards form the all-network baseline each epoch (0 disables)
+	DVT                      DVT                `yaml:"dvt,omitempty"`
+	HeatmapEpochs            int                `yaml:"heatmap_epochs,omitempty"`         // Epochs of per-validator outcomes served by /api/v1/heatmap
+	SigningHistoryEpochs     int                `yaml:"signing_history_epochs,omitempty"` // Epochs of on-chain signing history served by /api/v1/interchange
+	Startup                  Startup            `yaml:"startup,omitempty"`
+	OnchainRegistry          OnchainRegistry    `yaml:"onchain_registry,omitempty"`
+	DepositTracking          DepositTracking    `yaml:"deposit_tracking,omitempty"`
+	Privacy                  Privacy            `yaml:"privacy,omitempty"`
+	Report                   Report             `yaml:"report,omitempty"`
+	Silences                 []Silence          `yaml:"silences,omitempty"`
+	AggregateLabelClasses    []string           `yaml:"aggregate_label_classes,omitempty"` // Label classes (prefix before ':') with their own metrics, empty for all
+	MEVRelays                []MEVRelay         `yaml:"mev_relays,omitempty"`              // MEV-Boost relays checked for validator registrations every epoch
+	SharedCache              SharedCache        `yaml:"shared_cache,omitempty"`
+	FeeRecipients            FeeRecipients      `yaml:"fee_recipients,omitempty"`
+	GraffitiClients          []GraffitiClient   `yaml:"graffiti_clients,omitempty"` // Graffiti signatures of the operator's own nodes, tried before the built-in ones
+	Federation               Federation         `yaml:"federation,omitempty"`
+	Degradation              Degradation        `yaml:"degradation,omitempty"`
+	GrafanaAnnotations       GrafanaAnnotations `yaml:"grafana_annotations,omitempty"`
+	Influx                   Influx             `yaml:"influx,omitempty"`
+	Cohorts                  []Cohort           `yaml:"cohorts,omitempty"` // External validators benchmarked against the watched ones
+	Counters                 Counters           `yaml:"counters,omitempty"`
+	ExternalRatings          ExternalRatings    `yaml:"external_ratings,omitempty"`
+	Export                   Export             `yaml:"export,omitempty"`
+}
+
+// HTTPServer secures the server of /metrics, the health probes and the API
+type HTTPServer struct {
+	TLSCertFile string    `yaml:"tls_cert_file,omitempty"` // PEM certificate (chain), served over HTTPS when set
+	TLSKeyFile  string    `yaml:"tls_key_file,omitempty"`  // PEM private key of the certificate
+	BasicAuth   BasicAuth `yaml:"basic_auth,omitempty"`
+	BearerToken string    `yaml:"bearer_token,omitempty"` // Accepted as "Authorization: Bearer <token>", besides basic auth
+	PublicPaths []string  `yaml:"public_paths,omitempty"` // Paths served without credentials, e.g. probes
+}
+
+// BasicAuth is the username and password of HTTP basic authentication
+type BasicAuth struct {
+	Username string `yaml:"username,omitempty"`
+	Password string `yaml:"password,omitempty"`
+}
+
+// Logging configures the log format and an optional size-rotated log file
+type Logging struct {
+	Format     string `yaml:"format,omitempty"`      // text (default) or json, overridden by --log-format
+	File       string `yaml:"file,omitempty"`        // Written instead of stderr (disabled if empty)
+	MaxSizeMB  int    `yaml:"max_size_mb,omitempty"` // Size the file is rotated at (0 never rotates)
+	MaxBackups int    `yaml:"max_backups,omitempty"` // Rotated files kept
+}
+
+// Tracing configures exporting OpenTelemetry spans of the processing pipeline over OTLP
+type Tracing struct {
+	Endpoint    string            `yaml:"endpoint,omitempty"`     // Collector URL, e.g. http://otel-collector:4318 (disabled if empty)
+	Protocol    string            `yaml:"protocol,omitempty"`     // http (default) or grpc
+	Headers     map[string]string `yaml:"headers,omitempty"`      // Exporter headers, values may use ${ENV_VARS}
+	ServiceName string            `yaml:"service_name,omitempty"` // service.name of the spans (default eth-validator-watcher)
+	SampleRatio float64           `yaml:"sample_ratio,omitempty"` // Share of slots traced
+}
+
+// Counters configures when the cumulative per-validator counters reset and the trailing windows exported beside them
+type Counters struct {
+	Reset        string `yaml:"reset,omitempty"`         // never, epoch (default), day or reload
+	WindowEpochs []int  `yaml:"window_epochs,omitempty"` // Trailing windows exported as eth_counter_window, e.g. [225, 1575]
+}
+
+// Web3Signer configures importing the keys loaded in a Web3Signer instance as watched keys
+type Web3Signer struct {
+	URL           string            `yaml:"url,omitempty"`            // e.g. http://web3signer:9000 (disabled if empty)
+	Headers       map[string]string `yaml:"headers,omitempty"`        // Request headers, values may use ${ENV_VARS}
+	Labels        []string          `yaml:"labels,omitempty"`         // Given to every key of the signer, e.g. signer:web3signer-1
+	RefreshEpochs int               `yaml:"refresh_epochs,omitempty"` // How often the key list is refetched
+}
+
+// Cohort is a group of external validators (competitors, a network sample) tracked with the same
+// duty and reward pipeline under cohort:<name>, without alerting on them
+type Cohort struct {
+	Name       string           `yaml:"name"`
+	PublicKeys []string         `yaml:"public_keys,omitempty"`
+	Indices    []ValidatorIndex `yaml:"indices,omitempty"`
+}
+
+// GrafanaAnnotations configures publishing watcher events as Grafana annotations
+type GrafanaAnnotations struct {
+	URL           string   `yaml:"url,omitempty"`            // Grafana base URL, e.g. https://grafana.example.com (disabled if empty)
+	APIKey        string   `yaml:"api_key,omitempty"`        // Service account token with the annotations:write permission
+	DashboardUIDs []string `yaml:"dashboard_uids,omitempty"` // Dashboards annotated, empty for organization annotations queried by tag
+	Tags          []string `yaml:"tags,omitempty"`           // Added to every annotation, besides eth-validator-watcher and the event type
+	Events        []string `yaml:"events,omitempty"`         // Event types annotated (default: proposals, missed blocks, slashings, reorgs, fee recipients, watchlist changes, degradation)
+}
+
+// Health configures when the JSON health report of /health and /ready fails the probe
+type Health struct {
+	FailOn          string   `yaml:"fail_on,omitempty"`          // Lowest component status answered with 503: fail (default) or warn
+	LiveComponents  []string `yaml:"live_components,omitempty"`  // Components /health requires (default slot_age)
+	ReadyComponents []string `yaml:"ready_components,omitempty"` // Components /ready requires (default: those the served data depends on)
+}
+
+// Influx configures writing epoch-level measurements in the InfluxDB line protocol
+type Influx struct {
+	URL        string `yaml:"url,omitempty"`        // Write endpoint with its query, e.g. http://influxdb:8086/api/v2/write?org=acme&bucket=validators (disabled if empty)
+	Token      string `yaml:"token,omitempty"`      // Sent as "Authorization: Token <token>"
+	Validators *bool  `yaml:"validators,omitempty"` // Write a per-validator measurement besides the per-label one (default true)
+}
+
+// WritesValidators reports whether per-validator measurements are written
+func (i Influx) WritesValidators() bool {
+	if i.Validators == nil {
+		return true
+	}
+	return *i.Validators
+}
+
+// Export configures the per-validator epoch rows appended to daily CSV files for offline analysis
+type Export struct {
+	Dir           string `yaml:"dir,omitempty"`            // Directory of the daily files (disabled if empty)
+	Format        string `yaml:"format,omitempty"`         // csv, the only format (default)
+	RetentionDays int    `yaml:"retention_days,omitempty"` // Daily files kept (0 keeps every file)
+}
+
+// Degradation configures shedding optional work while the beacon node is overloaded
+type Degradation struct {
+	Enabled       *bool   `yaml:"enabled,omitempty"`        // Default true
+	MaxLatencyMs  int     `yaml:"max_latency_ms,omitempty"` // Average request latency over an epoch that counts as overloaded
+	MaxErrorRate  float64 `yaml:"max_error_rate,omitempty"` // Share of failed requests over an epoch that counts as overloaded
+	RecoverEpochs int     `yaml:"recover_epochs,omitempty"` // Healthy epochs before stepping back a level
+}
+
+// IsEnabled returns whether optional work is shed under load (default true)
+func (d Degradation) IsEnabled() bool {
+	if d.Enabled == nil {
+		return true
+	}
+	return *d.Enabled
+}
+
+// Federation configures the peer watchers whose label summaries are combined with this instance's
+// at /api/v1/federation/labels, for operators sharding their keys across several watchers
+type Federation struct {
+	Name    string           `yaml:"name,omitempty"`                 // This instance's shard name (default local)
+	Peers   []FederationPeer `yaml:"peers,omitempty"`                // Disabled if empty
+	Refresh Duration         `yaml:"refresh_interval_sec,omitempty"` // How often peers are polled
+}
+
+// FederationPeer is another watcher whose API is polled for label summaries
+type FederationPeer struct {
+	Name string `yaml:"name,omitempty"` // Shard name in the federated view (default: the URL host)
+	URL  string `yaml:"url"`            // Peer API base URL, e.g. http://watcher-2:8000
+}
+
+// FeeRecipients configures the fee recipient addresses watched proposers' blocks are expected to pay to
+// Addresses for any of a validator's labels replace the defaults; validators without either aren't checked
+type FeeRecipients struct {
+	Default []string            `yaml:"default,omitempty"`
+	ByLabel map[string][]string `yaml:"by_label,omitempty"`
+}
+
+// GraffitiClient maps graffiti matching a regular expression to the clients of the node that proposed
+type GraffitiClient struct {
+	Pattern   string `yaml:"pattern"`             // RE2 regular expression, e.g. ^myop-lh-
+	Consensus string `yaml:"consensus,omitempty"` // Consensus client, e.g. lighthouse
+	Execution string `yaml:"execution,omitempty"` // Execution client, e.g. nethermind
+}
+
+// SharedCache configures the Redis cache that watcher replicas share derived data through
+type SharedCache struct {
+	RedisURL            string `yaml:"redis_url,omitempty"`               // redis://[user:password@]host:port/db (disabled if empty)
+	KeyPrefix           string `yaml:"key_prefix,omitempty"`              // Namespace of the watcher's keys (default eth-validator-watcher)
+	MaxFullSetAgeEpochs int    `yaml:"max_full_set_age_epochs,omitempty"` // A replica reloads the full validator set itself at least this often
+}
+
+// Discord configures alert delivery to Discord webhooks
+type Discord struct {
+	WebhookURL       string            `yaml:"webhook_url,omitempty"`           // Receives every severity from min_severity up (disabled if empty)
+	MinSeverity      string            `yaml:"min_severity,omitempty"`          // info (default), warning or critical
+	SeverityWebhooks map[string]string `yaml:"severity_webhook_urls,omitempty"` // Per-severity webhooks used instead of webhook_url
+	ExplorerURL      string            `yaml:"explorer_url,omitempty"`          // Deprecated: use explorer.url
+}
+
+// Explorer configures the block explorer linked from alerts, API responses and reports
+type Explorer struct {
+	URL           string            `yaml:"url,omitempty"`            // Explorer of the watched network (default: beaconcha.in or gnosischa.in on public networks)
+	Networks      map[string]string `yaml:"networks,omitempty"`       // Explorer per network name, taking precedence over url (e.g. devnets sharing one config)
+	ValidatorPath string            `yaml:"validator_path,omitempty"` // Page paths with {id} for the index, slot, epoch or block root
+	SlotPath      string            `yaml:"slot_path,omitempty"`
+	EpochPath     string            `yaml:"epoch_path,omitempty"`
+	BlockPath     string            `yaml:"block_path,omitempty"`
+	Disabled      bool              `yaml:"disabled,omitempty"` // No links at all, even on public networks
+}
+
+// PagerDuty configures paging through the PagerDuty Events API v2
+type PagerDuty struct {
+	RoutingKey  string `yaml:"routing_key,omitempty"`  // Events API v2 integration key (disabled if empty)
+	MinSeverity string `yaml:"min_severity,omitempty"` // Lowest severity paged (default critical)
+}
+
+// Telegram configures alert delivery to a Telegram chat through a bot
+type Telegram struct {
+	BotToken    string `yaml:"bot_token,omitempty"`    // From @BotFather (disabled if empty)
+	ChatID      string `yaml:"chat_id,omitempty"`      // Chat, group or channel id (e.g. -1001234567890) or @channelname
+	MinSeverity string `yaml:"min_severity,omitempty"` // Lowest severity sent (default info)
+}
+
+// CriticalAlerts configures conditions raising critical alerts, besides slashings and canary misses
+type CriticalAlerts struct {
+	ConsecutiveMissedAttestations uint64  `yaml:"consecutive_missed_attestations,omitempty"` // A validator missed this many attestations in a row (0 disables)
+	LabelOfflinePercent           float64 `yaml:"label_offline_percent,omitempty"`           // More than this % of a label's validators weren't live in an epoch (0 disables)
+	VoluntaryExits                bool    `yaml:"voluntary_exits,omitempty"`                 // Page when a watched validator initiates an exit (a warning otherwise)
+}
+
+// ProposalLookahead warns ahead of watched proposals whose validator is missing attestations
+type ProposalLookahead struct {
+	Slots                int    `yaml:"slots,omitempty"`                  // How far ahead proposals are checked (0 disables)
+	MinConsecutiveMissed uint64 `yaml:"min_consecutive_missed,omitempty"` // Missed attestations in a row that put a proposal at risk
+}
+
+// AlertRule alerts when a label's metric crosses a threshold for a number of epochs
+type AlertRule struct {
+	Name       string   `yaml:"name"`
+	Metric     string   `yaml:"metric"`             // e.g. miss_rate, in percent of the epoch's duties
+	Comparison string   `yaml:"comparison"`         // >, >=, <, <=, == or !=
+	Threshold  float64  `yaml:"threshold"`          // Compared against the metric
+	Duration   int      `yaml:"duration,omitempty"` // Consecutive epochs the condition must hold (default 1)
+	Labels     []string `yaml:"labels,omitempty"`   // Labels evaluated, a trailing * matches a prefix (default every label but scope:all-network and cohort:*)
+	Channels   []string `yaml:"channels,omitempty"` // slack, discord, telegram and/or pagerduty (default every configured channel)
+	Severity   string   `yaml:"severity,omitempty"` // info, warning (default) or critical
+	Cooldown   Duration `yaml:"cooldown,omitempty"` // Minimum seconds between alerts of the rule for a label (default 3600)
+}
+
+// Report configures the periodic summary sent to the alert channels
+type Report struct {
+	Schedule string `yaml:"schedule,omitempty"` // Cron expression, e.g. "0 9 * * *" (disabled if empty)
+	TimeZone string `yaml:"timezone,omitempty"` // IANA time zone of the schedule (default UTC)
+}
+
+// Silence is a recurring maintenance window during which chat alerts are suppressed
+type Silence struct {
+	Name     string   `yaml:"name"`
+	Schedule string   `yaml:"schedule"`           // Cron expression of the window start
+	TimeZone string   `yaml:"timezone,omitempty"` // IANA time zone of the schedule (default UTC)
+	Duration Duration `yaml:"duration_sec"`       // How long the window stays open
+}
+
+// OnchainRegistry configures registry contracts that assign watched keys to operators
+type OnchainRegistry struct {
+	ExecutionURL string             `yaml:"execution_url,omitempty"`        // Execution layer JSON-RPC endpoint for eth_call
+	Refresh      Duration           `yaml:"refresh_interval_sec,omitempty"` // How often assignments are pulled
+	Contracts    []RegistryContract `yaml:"contracts,omitempty"`
+}
+
+// DepositTracking configures the deposit contract scan telling watched keys with a deposit on the
+// execution layer apart from those without any
+type DepositTracking struct {
+	ExecutionURL    string  `yaml:"execution_url,omitempty"`    // Execution layer JSON-RPC endpoint for eth_getLogs (disabled if empty)
+	ContractAddress string  `yaml:"contract_address,omitempty"` // Deposit contract (default: DEPOSIT_CONTRACT_ADDRESS of the beacon node's spec)
+	FromBlock       *uint64 `yaml:"from_block,omitempty"`       // First block scanned (default: the deployment of the network's deposit contract)
+}
+
+// Privacy configures pubkey and index anonymization in logs, events, the API and alerts
+type Privacy struct {
+	AnonymizePubkeys bool   `yaml:"anonymize_pubkeys,omitempty"`
+	Salt             string `yaml:"salt,omitempty"` // Secret keying the pseudonyms; keep it stable so pseudonyms survive restarts
+}
+
+// RegistryContract is a view function mapping a pubkey (passed as bytes) to a label value
+type RegistryContract struct {
+	Address  string `yaml:"address"`  // Contract address
+	Selector string `yaml:"selector"` // 4-byte function selector, e.g. of operatorOf(bytes)
+	Returns  string `yaml:"returns"`  // Return type: string, uint256, address or bytes32
+	Label    string `yaml:"label"`    // Label prefix, e.g. operator gives operator:<value>
+}
+
+// MEVRelay is a MEV-Boost relay whose data API is checked for watched validator registrations
+type MEVRelay struct {
+	Name string `yaml:"name,omitempty"` // Relay label in metrics and alerts (default: the URL host)
+	URL  string `yaml:"url"`            // Relay URL, as configured in MEV-Boost
+}
+
+// ExternalRatings configures the daily pull of third-party effectiveness ratings of the watched validators
+type ExternalRatings struct {
+	Provider string   `yaml:"provider,omitempty"`             // rated or beaconcha.in (disabled if empty)
+	URL      string   `yaml:"url,omitempty"`                  // API base URL (default: the provider's mainnet API)
+	APIKey   string   `yaml:"api_key,omitempty"`              // Rated bearer token or beaconcha.in API key
+	Network  string   `yaml:"network,omitempty"`              // Network name sent to Rated (default: the watcher's network)
+	Refresh  Duration `yaml:"refresh_interval_sec,omitempty"` // How often ratings are pulled
+}
+
+// BeaconAuth authenticates the requests to the beacon nodes, for hosted providers that require API keys
+type BeaconAuth struct {
+	Headers       map[string]string `yaml:"headers,omitempty"`         // Sent with every request, values may use ${ENV_VARS}
+	BearerToken   string            `yaml:"bearer_token,omitempty"`    // Sent as "Authorization: Bearer <token>", may use ${ENV_VARS}
+	BasicAuth     BasicAuth         `yaml:"basic_auth,omitempty"`      // Username and password may use ${ENV_VARS}
+	JWTSecretFile string            `yaml:"jwt_secret_file,omitempty"` // Hex HS256 secret; every request gets a fresh token with an iat claim
+}
+
+// BeaconNode is a failover endpoint with the credentials of its provider
+type BeaconNode struct {
+	URL  string     `yaml:"url"`
+	Auth BeaconAuth `yaml:"auth,omitempty"` // Sent to this endpoint only
+}
+
+// BeaconRetry sets the timeout and retries of beacon requests, by request category
+type BeaconRetry struct {
+	Default    BeaconRetryPolicy            `yaml:"default,omitempty"`    // Every category, unless overridden
+	Categories map[string]BeaconRetryPolicy `yaml:"categories,omitempty"` // validators, blocks, duties, rewards, state or default
+}
+
+// BeaconRetryPolicy is the timeout and retries of a request category; unset fields are inherited
+type BeaconRetryPolicy struct {
+	Timeout     Duration `yaml:"timeout_sec,omitempty"`  // Per attempt (default beacon_timeout_sec)
+	MaxAttempts int      `yaml:"max_attempts,omitempty"` // Rounds over the endpoints (default 3)
+	Backoff     string   `yaml:"backoff,omitempty"`      // linear (default) or exponential
+	DelayMs     int      `yaml:"delay_ms,omitempty"`     // Delay before the first retry (default 2000)
+	MaxDelayMs  int      `yaml:"max_delay_ms,omitempty"` // Cap of the delay
+	Jitter      *bool    `yaml:"jitter,omitempty"`       // Randomize delays between half and all of their value
+}
+
+// BeaconRateLimit paces the requests sent to the beacon nodes, for providers that throttle heavy clients
+// Zero values leave the rate or the concurrency unbounded
+type BeaconRateLimit struct {
+	RequestsPerSec float64 `yaml:"requests_per_sec,omitempty"`
+	Burst          int     `yaml:"burst,omitempty"`          // Requests sent at once after an idle period (default: one second of requests)
+	MaxConcurrent  int     `yaml:"max_concurrent,omitempty"` // Requests in flight at once
+}
+
+// Startup paces the batched validator lookups made when the watcher starts
+type Startup struct {
+	BatchSize            int `yaml:"batch_size,omitempty"`             // Validators per request
+	MaxConcurrentBatches int `yaml:"max_concurrent_batches,omitempty"` // Requests in flight at once
+	BatchDelayMs         int `yaml:"batch_delay_ms,omitempty"`         // Wait between starting consecutive requests
+}
+
+// StateTrends configures the per-label trend kept in the state file
+type StateTrends struct {
+	CompactAfterDays int `yaml:"compact_after_days,omitempty"` // Per-epoch points older than this are merged into daily points
+	RetentionDays    int `yaml:"retention_days,omitempty"`     // Daily points older than this are dropped (0 keeps them)
+}
+
+// SpecOverrides replaces spec constants the beacon node reports (or doesn't); zero keeps the node's value
+// With seconds_per_slot and slots_per_epoch set, the watcher runs against nodes that don't serve the spec
+type SpecOverrides struct {
+	SecondsPerSlot               uint64 `yaml:"seconds_per_slot,omitempty"`
+	SlotsPerEpoch                uint64 `yaml:"slots_per_epoch,omitempty"`
+	EpochsPerSyncCommitteePeriod uint64 `yaml:"epochs_per_sync_committee_period,omitempty"`
+	StakeUnitGwei                Gwei   `yaml:"stake_unit_gwei,omitempty"` // Effective balance of a full validator
+}
+
+// Complete reports whether the overrides are enough to run the clock without the node's spec
+func (o SpecOverrides) Complete() bool {
+	return o.SecondsPerSlot > 0 && o.SlotsPerEpoch > 0
+}
+
+// Apply overrides the spec's constants
+func (o SpecOverrides) Apply(spec *Spec) {
+	if o.SecondsPerSlot > 0 {
+		spec.SecondsPerSlot = o.SecondsPerSlot
+	}
+	if o.SlotsPerEpoch > 0 {
+		spec.SlotsPerEpoch = o.SlotsPerEpoch
+	}
+	if o.EpochsPerSyncCommitteePeriod > 0 {
+		spec.EpochsPerSyncCommitteePeriod = o.EpochsPerSyncCommitteePeriod
+	}
+	if o.StakeUnitGwei > 0 {
+		spec.StakeUnitOverride = o.StakeUnitGwei
+	}
+}
+
+// DVT configures distributed validator sources whose keys are added to the watched keys
+type DVT struct {
+	ObolLockFiles  []string `yaml:"obol_lock_files,omitempty"`  // Obol cluster-lock.json files
+	SSVOperatorIDs []uint64 `yaml:"ssv_operator_ids,omitempty"` // SSV operators whose validators are watched
+	SSVAPIURL      string   `yaml:"ssv_api_url,omitempty"`      // SSV API base URL (default https://api.ssv.network/api/v4)
+}
+
+// Scorecard configures the composite operator scorecard served by the API
+type Scorecard struct {
+	Weights map[string]float64 `yaml:"weights,omitempty"` // Per-dimension weights, unspecified dimensions keep their defaults
+}
+
+// LogSampling controls how per-validator details are aggregated in log lines
+type LogSampling struct {
+	MaxExamples int `yaml:"max_examples"` // Validators listed per log line, the rest are only counted
+}
+
+// BeaconEndpoints returns the beacon node URLs in configured order
+func (c *Config) BeaconEndpoints() []string {
+	nodes := c.BeaconNodeList()
+	urls := make([]string, len(nodes))
+	for i, node := range nodes {
+		urls[i] = node.URL
+	}
+	return urls
+}
+
+// BeaconNodeList returns the beacon nodes in configured order with their credentials; beacon_auth
+// applies to every URL of beacon_url and beacon_urls
+func (c *Config) BeaconNodeList() []BeaconNode {
+	if len(c.BeaconNodes) > 0 {
+		return c.BeaconNodes
+	}
+	urls := c.BeaconURLs
+	if len(urls) == 0 {
+		urls = []string{c.BeaconURL}
+	}
+	nodes := make([]BeaconNode, len(urls))
+	for i, url := range urls {
+		nodes[i] = BeaconNode{URL: url, Auth: c.BeaconAuth}
+	}
+	return nodes
+}
+
+// ShouldUseEvents returns whether slot processing follows beacon node head events (default true)
+func (c *Config) ShouldUseEvents() bool {
+	if c.UseEvents == nil {
+		return true
+	}
+	return *c.UseEvents
+}
+
+// IsReplay returns whether the watcher reprocesses history from replay_start_at_ts instead of following the chain
+func (c *Config) IsReplay() bool {
+	return c.ReplayStartAtTS != nil
 }
 
 // ShouldLoadAllValidators returns whether to load the full validator set (default true)
@@ -0,0 +1,208 @@
+package onchain
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// DepositEventTopic is the topic of the deposit contract's DepositEvent(bytes,bytes,bytes,bytes,bytes)
+const DepositEventTopic = "0x649bbc62d0e31342afea4e5cd82d4049e7e1ee912fc0889aa790803be39038c5"
+
+// depositLogChunk is the block range of one eth_getLogs request, within what providers accept
+const depositLogChunk = 10000
+
+// depositContractBlocks are the deployment blocks of the networks' deposit contracts; the others
+// were deployed in their genesis
+var depositContractBlocks = map[string]uint64{
+	"mainnet": 11052984,
+	"sepolia": 1273020,
+}
+
+// DepositContractBlock returns the block a network's deposit contract was deployed in, the first
+// one that can hold a deposit
+func DepositContractBlock(network string) uint64 {
+	return depositContractBlocks[network]
+}
+
+// Deposit is a deposit of a watched pubkey seen on the deposit contract
+type Deposit struct {
+	Pubkey      string      `json:"pubkey"`
+	Amount      models.Gwei `json:"amount"`
+	BlockNumber uint64      `json:"block_number"`
+}
+
+// DepositScan is the progress of a scanner, persisted so a restart doesn't read the contract's
+// history again
+type DepositScan struct {
+	Next     map[string]uint64  `json:"next"`     // First block not scanned yet, by pubkey
+	Deposits map[string]Deposit `json:"deposits"` // First deposit of each pubkey found
+}
+
+// DepositScanner follows the deposit contract's finalized logs and remembers the deposits of watched
+// pubkeys; each pubkey is looked for from the contract's deployment on, so a deposit made any time
+// before the pubkey was watched is found too
+type DepositScanner struct {
+	rpc      *RPCClient
+	contract string
+	from     uint64 // First block scanned for a new pubkey
+
+	mu       sync.RWMutex
+	next     map[string]uint64 // First block not scanned yet for each pubkey being looked for
+	deposits map[string]Deposit
+}
+
+// NewDepositScanner creates a scanner of a deposit contract whose scans start at block from
+func NewDepositScanner(rpc *RPCClient, contract string, from uint64) *DepositScanner {
+	return &DepositScanner{
+		rpc:      rpc,
+		contract: contract,
+		from:     from,
+		next:     make(map[string]uint64),
+		deposits: make(map[string]Deposit),
+	}
+}
+
+// Scan reads the finalized deposit logs the pubkeys weren't looked for in yet and records their
+// deposits, chunk by chunk, so a scan cut short resumes where it stopped; pubkeys no longer given
+// are forgotten
+func (s *DepositScanner) Scan(ctx context.Context, pubkeys []string) error {
+	finalized, err := s.rpc.FinalizedBlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	next := make(map[string]uint64, len(pubkeys))
+	for _, pubkey := range pubkeys {
+		pubkey = strings.ToLower(pubkey)
+		if _, found := s.deposits[pubkey]; found {
+			continue
+		}
+		if n, ok := s.next[pubkey]; ok {
+			next[pubkey] = n
+		} else {
+			next[pubkey] = s.from
+		}
+	}
+	s.next = next
+	s.mu.Unlock()
+
+	for {
+		// The chunk starts at the pubkeys scanned the least far, and counts for each pubkey it reaches
+		from, pending := uint64(0), false
+		for _, n := range next {
+			if n <= finalized && (!pending || n < from) {
+				from, pending = n, true
+			}
+		}
+		if !pending {
+			return nil
+		}
+		to := min(from+depositLogChunk-1, finalized)
+
+		logs, err := s.rpc.GetLogs(ctx, s.contract, DepositEventTopic, from, to)
+		if err != nil {
+			return fmt.Errorf("blocks %d-%d: %w", from, to, err)
+		}
+		found := make(map[string]Deposit)
+		for _, log := range logs {
+			data, err := hex.DecodeString(strings.TrimPrefix(log.Data, "0x"))
+			if err != nil {
+				return fmt.Errorf("invalid deposit log data: %w", err)
+			}
+			pubkey, amount, err := DecodeDepositEvent(data)
+			if err != nil {
+				return err
+			}
+			if _, wanted := next[pubkey]; !wanted {
+				continue
+			}
+			block, _ := parseQuantity(log.BlockNumber)
+			if d, ok := found[pubkey]; !ok || block < d.BlockNumber {
+				found[pubkey] = Deposit{Pubkey: pubkey, Amount: amount, BlockNumber: block}
+			}
+		}
+
+		s.mu.Lock()
+		for pubkey, d := range found {
+			s.deposits[pubkey] = d
+			delete(next, pubkey)
+		}
+		for pubkey, n := range next {
+			if n <= to {
+				next[pubkey] = to + 1
+			}
+		}
+		s.mu.Unlock()
+	}
+}
+
+// Deposit returns the first deposit seen for a pubkey
+func (s *DepositScanner) Deposit(pubkey string) (Deposit, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	d, ok := s.deposits[strings.ToLower(pubkey)]
+	return d, ok
+}
+
+// Progress returns what the scans covered so far
+func (s *DepositScanner) Progress() DepositScan {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return DepositScan{Next: maps.Clone(s.next), Deposits: maps.Clone(s.deposits)}
+}
+
+// Restore resumes from the progress of a previous run
+func (s *DepositScanner) Restore(scan DepositScan) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for pubkey, n := range scan.Next {
+		s.next[pubkey] = n
+	}
+	for pubkey, d := range scan.Deposits {
+		s.deposits[pubkey] = d
+	}
+}
+
+// DecodeDepositEvent returns the pubkey (0x-prefixed, lowercase) and the amount of a DepositEvent's data
+// The event's five bytes arguments are ABI-encoded; the amount is 8 bytes little-endian Gwei
+func DecodeDepositEvent(data []byte) (string, models.Gwei, error) {
+	pubkey, err := abiBytes(data, 0)
+	if err != nil || len(pubkey) != 48 {
+		return "", 0, fmt.Errorf("invalid deposit event pubkey")
+	}
+	amount, err := abiBytes(data, 2)
+	if err != nil || len(amount) != 8 {
+		return "", 0, fmt.Errorf("invalid deposit event amount")
+	}
+	return "0x" + hex.EncodeToString(pubkey), models.Gwei(binary.LittleEndian.Uint64(amount)), nil
+}
+
+// abiBytes returns the dynamic bytes argument at a position of ABI-encoded data
+func abiBytes(data []byte, arg int) ([]byte, error) {
+	head := arg * 32
+	if len(data) < head+32 {
+		return nil, fmt.Errorf("data too short")
+	}
+	offset := new(big.Int).SetBytes(data[head : head+32])
+	if !offset.IsUint64() || offset.Uint64() > uint64(len(data)-32) {
+		return nil, fmt.Errorf("offset out of range")
+	}
+	start := int(offset.Uint64())
+	length := new(big.Int).SetBytes(data[start : start+32])
+	if !length.IsUint64() || length.Uint64() > uint64(len(data)-start-32) {
+		return nil, fmt.Errorf("length out of range")
+	}
+	return data[start+32 : start+32+int(length.Uint64())], nil
+}
@@ -0,0 +1,135 @@
+package onchain
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// depositEvent ABI-encodes the data of a DepositEvent
+func depositEvent(pubkey []byte, amount uint64) string {
+	args := [][]byte{pubkey, make([]byte, 32), make([]byte, 8), make([]byte, 96), make([]byte, 8)}
+	binary.LittleEndian.PutUint64(args[2], amount)
+
+	data := make([]byte, 32*len(args))
+	for i, arg := range args {
+		binary.BigEndian.PutUint64(data[i*32+24:], uint64(len(data)))
+		word := make([]byte, 32)
+		binary.BigEndian.PutUint64(word[24:], uint64(len(arg)))
+		data = append(data, word...)
+		data = append(data, arg...)
+		data = append(data, make([]byte, (32-len(arg)%32)%32)...)
+	}
+	return "0x" + hex.EncodeToString(data)
+}
+
+func TestDecodeDepositEvent(t *testing.T) {
+	pubkey := make([]byte, 48)
+	pubkey[0] = 0xab
+	data, _ := hex.DecodeString(strings.TrimPrefix(depositEvent(pubkey, 32000000000), "0x"))
+
+	got, amount, err := DecodeDepositEvent(data)
+	if err != nil {
+		t.Fatalf("DecodeDepositEvent() error = %v", err)
+	}
+	if got != "0x"+hex.EncodeToString(pubkey) || amount != 32000000000 {
+		t.Errorf("DecodeDepositEvent() = %s, %d", got, amount)
+	}
+	if _, _, err := DecodeDepositEvent(data[:100]); err == nil {
+		t.Error("Expected truncated data to be rejected")
+	}
+}
+
+func TestDepositScanner(t *testing.T) {
+	watched := make([]byte, 48)
+	watched[0] = 0xaa
+	late := make([]byte, 48)
+	late[0] = 0xcc
+	other := make([]byte, 48)
+	other[0] = 0xbb
+
+	finalized := "0x4e20" // 20000
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var requests []rpcRequest
+		json.NewDecoder(r.Body).Decode(&requests)
+		var result interface{}
+		switch requests[0].Method {
+		case "eth_getBlockByNumber":
+			if requests[0].Params[0] != "finalized" {
+				t.Errorf("Expected the finalized block, got %v", requests[0].Params[0])
+			}
+			result = map[string]string{"number": finalized}
+		case "eth_getLogs":
+			filter := requests[0].Params[0].(map[string]interface{})
+			ranges = append(ranges, filter["fromBlock"].(string)+"-"+filter["toBlock"].(string))
+			if filter["fromBlock"] == "0x1388" {
+				result = []Log{
+					{Topics: []string{DepositEventTopic}, Data: depositEvent(other, 1), BlockNumber: "0x2711"},
+					{Topics: []string{DepositEventTopic}, Data: depositEvent(watched, 32000000000), BlockNumber: "0x2712"},
+					{Topics: []string{DepositEventTopic}, Data: depositEvent(late, 1000000000), BlockNumber: "0x2713"},
+				}
+			}
+		}
+		raw, _ := json.Marshal(result)
+		json.NewEncoder(w).Encode([]rpcResponse{{ID: requests[0].ID, Result: raw}})
+	}))
+	defer server.Close()
+
+	contract := "0x00000000219ab540356cBB839Cbe05303d7705Fa"
+	scanner := NewDepositScanner(NewRPCClient(server.URL, time.Second), contract, 5000)
+	watchedKey := "0x" + hex.EncodeToString(watched)
+	if err := scanner.Scan(context.Background(), []string{watchedKey}); err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+
+	// Blocks 5000 to the finalized 20000 in chunks of 10000, stopping once the deposit is found
+	if len(ranges) != 1 || ranges[0] != "0x1388-0x3a97" {
+		t.Errorf("Unexpected block ranges %v", ranges)
+	}
+	if d, ok := scanner.Deposit(strings.ToUpper(watchedKey[:4]) + watchedKey[4:]); !ok || d.Amount != 32000000000 || d.BlockNumber != 10002 {
+		t.Errorf("Expected the watched deposit, got %+v (%v)", d, ok)
+	}
+	if _, ok := scanner.Deposit("0x" + hex.EncodeToString(other)); ok {
+		t.Error("Expected the deposit of an unwatched pubkey to be ignored")
+	}
+
+	// A pubkey watched later is looked for from the first block, and found in the blocks scanned before
+	ranges = nil
+	lateKey := "0x" + hex.EncodeToString(late)
+	if err := scanner.Scan(context.Background(), []string{watchedKey, lateKey}); err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if d, ok := scanner.Deposit(lateKey); !ok || d.BlockNumber != 10003 || len(ranges) != 1 {
+		t.Errorf("Expected the late deposit from a backfill, got %+v (%v) after %v", d, ok, ranges)
+	}
+
+	// A pubkey without a deposit resumes from its progress, in a restarted scanner too
+	ranges = nil
+	missing := "0x" + strings.Repeat("dd", 48)
+	if err := scanner.Scan(context.Background(), []string{missing}); err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if len(ranges) != 2 || ranges[1] != "0x3a98-0x4e20" {
+		t.Errorf("Unexpected block ranges %v", ranges)
+	}
+	restarted := NewDepositScanner(NewRPCClient(server.URL, time.Second), contract, 5000)
+	restarted.Restore(scanner.Progress())
+	finalized = "0x4e2a" // 20010
+	ranges = nil
+	if err := restarted.Scan(context.Background(), []string{missing}); err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if len(ranges) != 1 || ranges[0] != "0x4e21-0x4e2a" {
+		t.Errorf("Expected only the newly finalized blocks scanned, got %v", ranges)
+	}
+	if _, ok := restarted.Deposit(watchedKey); !ok {
+		t.Error("Expected the deposits found before the restart restored")
+	}
+}
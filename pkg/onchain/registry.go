@@ -0,0 +1,164 @@
+package onchain
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// Supported return types of registry lookup functions
+const (
+	ReturnString  = "string"
+	ReturnUint256 = "uint256"
+	ReturnAddress = "address"
+	ReturnBytes32 = "bytes32"
+)
+
+// Registry resolves watched pubkeys to labels through on-chain registry contracts
+// Each contract exposes a view function taking the 48-byte pubkey as bytes and returning one value
+type Registry struct {
+	rpc       *RPCClient
+	contracts []models.RegistryContract
+}
+
+// NewRegistry creates a registry resolver
+func NewRegistry(rpc *RPCClient, contracts []models.RegistryContract) *Registry {
+	return &Registry{rpc: rpc, contracts: contracts}
+}
+
+// Labels looks up every pubkey in every contract and returns pubkey -> labels
+// Pubkeys a contract doesn't know (reverted call or zero value) get no label from it
+func (r *Registry) Labels(ctx context.Context, pubkeys []string) (map[string][]string, error) {
+	labels := make(map[string][]string)
+
+	for _, contract := range r.contracts {
+		selector, err := hex.DecodeString(strings.TrimPrefix(contract.Selector, "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("contract %s: invalid selector: %w", contract.Address, err)
+		}
+
+		calls := make([]Call, 0, len(pubkeys))
+		for _, pubkey := range pubkeys {
+			raw, err := hex.DecodeString(strings.TrimPrefix(pubkey, "0x"))
+			if err != nil {
+				return nil, fmt.Errorf("invalid pubkey %s: %w", pubkey, err)
+			}
+			calls = append(calls, Call{To: contract.Address, Data: EncodeBytesCall(selector, raw)})
+		}
+
+		results, err := r.rpc.CallBatch(ctx, calls)
+		if err != nil {
+			return nil, fmt.Errorf("contract %s: %w", contract.Address, err)
+		}
+
+		for i, result := range results {
+			value, ok := DecodeValue(result, contract.Returns)
+			if !ok {
+				continue
+			}
+			labels[pubkeys[i]] = append(labels[pubkeys[i]], contract.Label+":"+value)
+		}
+	}
+
+	return labels, nil
+}
+
+// Apply returns copies of keys with registry labels appended (configured labels stay first)
+func Apply(keys []models.WatchedKey, labels map[string][]string) []models.WatchedKey {
+	applied := make([]models.WatchedKey, len(keys))
+	for i, wk := range keys {
+		applied[i] = models.WatchedKey{PublicKey: wk.PublicKey, Labels: wk.Labels}
+
+		extra := labels[wk.PublicKey]
+		if len(extra) == 0 {
+			continue
+		}
+		merged := make([]string, 0, len(wk.Labels)+len(extra))
+		merged = append(merged, wk.Labels...)
+		for _, label := range extra {
+			if !contains(merged, label) {
+				merged = append(merged, label)
+			}
+		}
+		applied[i].Labels = merged
+	}
+	return applied
+}
+
+// EncodeBytesCall ABI-encodes a call to a function taking a single bytes argument
+func EncodeBytesCall(selector, arg []byte) []byte {
+	padded := (len(arg) + 31) / 32 * 32
+	data := make([]byte, 4+32+32+padded)
+	copy(data, selector)
+	binary.BigEndian.PutUint64(data[4+24:], 32) // Offset of the bytes argument
+	binary.BigEndian.PutUint64(data[4+32+24:], uint64(len(arg)))
+	copy(data[4+64:], arg)
+	return data
+}
+
+// DecodeValue decodes a single ABI-encoded return value as a label value
+// ok is false for empty results, zero values and invalid encodings
+func DecodeValue(data []byte, returns string) (string, bool) {
+	if len(data) < 32 {
+		return "", false
+	}
+	word := data[:32]
+
+	switch returns {
+	case ReturnString:
+		// Bounds are checked against what is left of data, as offset+length can overflow
+		offset := new(big.Int).SetBytes(word)
+		if !offset.IsUint64() || offset.Uint64() > uint64(len(data))-32 {
+			return "", false
+		}
+		start := offset.Uint64()
+		length := new(big.Int).SetBytes(data[start : start+32])
+		if !length.IsUint64() || length.Uint64() > uint64(len(data))-start-32 {
+			return "", false
+		}
+		value := string(data[start+32 : start+32+length.Uint64()])
+		if !utf8.ValidString(value) {
+			return "", false
+		}
+		value = strings.Join(strings.Fields(value), "-")
+		return value, value != ""
+	case ReturnUint256:
+		value := new(big.Int).SetBytes(word)
+		return value.String(), value.Sign() != 0
+	case ReturnAddress:
+		if isZero(word[12:]) {
+			return "", false
+		}
+		return "0x" + hex.EncodeToString(word[12:]), true
+	case ReturnBytes32:
+		if isZero(word) {
+			return "", false
+		}
+		return "0x" + hex.EncodeToString(word), true
+	}
+	return "", false
+}
+
+func isZero(b []byte) bool {
+	for _, v := range b {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package onchain
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// abiString ABI-encodes a single string return value
+func abiString(s string) string {
+	data := make([]byte, 64+(len(s)+31)/32*32)
+	data[31] = 32
+	data[63] = byte(len(s))
+	copy(data[64:], s)
+	return "0x" + hex.EncodeToString(data)
+}
+
+func TestEncodeBytesCall(t *testing.T) {
+	pubkey := make([]byte, 48)
+	pubkey[0] = 0xaa
+	data := EncodeBytesCall([]byte{0x12, 0x34, 0x56, 0x78}, pubkey)
+
+	if len(data) != 4+32+32+64 {
+		t.Fatalf("Expected 132 bytes, got %d", len(data))
+	}
+	if hex.EncodeToString(data[:4]) != "12345678" || data[35] != 32 || data[67] != 48 || data[68] != 0xaa {
+		t.Errorf("Unexpected encoding: %x", data)
+	}
+}
+
+func TestDecodeValue(t *testing.T) {
+	word := make([]byte, 32)
+	if _, ok := DecodeValue(word, ReturnUint256); ok {
+		t.Error("Expected zero uint256 to be treated as unassigned")
+	}
+
+	word[31] = 7
+	if value, ok := DecodeValue(word, ReturnUint256); !ok || value != "7" {
+		t.Errorf("Expected 7, got %q", value)
+	}
+	if value, ok := DecodeValue(word, ReturnAddress); !ok || value != "0x0000000000000000000000000000000000000007" {
+		t.Errorf("Unexpected address %q", value)
+	}
+
+	raw, _ := hex.DecodeString(strings.TrimPrefix(abiString("Staking Facilities"), "0x"))
+	if value, ok := DecodeValue(raw, ReturnString); !ok || value != "Staking-Facilities" {
+		t.Errorf("Expected sanitized string, got %q", value)
+	}
+	if _, ok := DecodeValue(nil, ReturnString); ok {
+		t.Error("Expected empty result to be rejected")
+	}
+
+	// An offset or length near 2^64 must not wrap around the bounds checks
+	huge := make([]byte, 64)
+	copy(huge[24:32], []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xf0})
+	if _, ok := DecodeValue(huge, ReturnString); ok {
+		t.Error("Expected an out of range offset to be rejected")
+	}
+	huge = make([]byte, 64)
+	huge[31] = 32
+	copy(huge[56:64], []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xf0})
+	if _, ok := DecodeValue(huge, ReturnString); ok {
+		t.Error("Expected an out of range length to be rejected")
+	}
+}
+
+func TestRegistryLabels(t *testing.T) {
+	known := "0x" + strings.Repeat("aa", 48)
+	unknown := "0x" + strings.Repeat("bb", 48)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var requests []rpcRequest
+		json.NewDecoder(r.Body).Decode(&requests)
+
+		responses := make([]map[string]interface{}, 0, len(requests))
+		for _, req := range requests {
+			call := req.Params[0].(map[string]interface{})
+			if strings.Contains(call["data"].(string), strings.Repeat("aa", 48)) {
+				responses = append(responses, map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": abiString("Kiln")})
+			} else {
+				responses = append(responses, map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "error": map[string]interface{}{"code": 3, "message": "execution reverted"}})
+			}
+		}
+		json.NewEncoder(w).Encode(responses)
+	}))
+	defer server.Close()
+
+	registry := NewRegistry(NewRPCClient(server.URL, 5*time.Second), []models.RegistryContract{{
+		Address:  "0x" + strings.Repeat("11", 20),
+		Selector: "0x12345678",
+		Returns:  ReturnString,
+		Label:    "operator",
+	}})
+
+	labels, err := registry.Labels(context.Background(), []string{known, unknown})
+	if err != nil {
+		t.Fatalf("Labels failed: %v", err)
+	}
+	if len(labels) != 1 || len(labels[known]) != 1 || labels[known][0] != "operator:Kiln" {
+		t.Errorf("Unexpected labels: %v", labels)
+	}
+
+	keys := Apply([]models.WatchedKey{
+		{PublicKey: known, Labels: []string{"region:eu"}},
+		{PublicKey: unknown},
+	}, labels)
+	if strings.Join(keys[0].Labels, ",") != "region:eu,operator:Kiln" || len(keys[1].Labels) != 0 {
+		t.Errorf("Unexpected applied labels: %+v", keys)
+	}
+}
@@ -0,0 +1,192 @@
+package onchain
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// rpcBatchSize is the number of eth_call requests sent per JSON-RPC batch
+const rpcBatchSize = 100
+
+// Call is a single eth_call against a contract
+type Call struct {
+	To   string
+	Data []byte
+}
+
+// RPCClient is a minimal execution layer JSON-RPC client for eth_call and log queries
+type RPCClient struct {
+	url    string
+	client *http.Client
+}
+
+// rpcRequest is a JSON-RPC request
+type rpcRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      int           `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+// rpcResponse is a JSON-RPC response
+type rpcResponse struct {
+	ID     int             `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// NewRPCClient creates a new JSON-RPC client
+func NewRPCClient(url string, timeout time.Duration) *RPCClient {
+	return &RPCClient{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// CallBatch runs eth_calls against the latest block in JSON-RPC batches
+// Results are returned in call order; a reverted call yields nil
+func (c *RPCClient) CallBatch(ctx context.Context, calls []Call) ([][]byte, error) {
+	results := make([][]byte, len(calls))
+
+	for start := 0; start < len(calls); start += rpcBatchSize {
+		end := start + rpcBatchSize
+		if end > len(calls) {
+			end = len(calls)
+		}
+
+		requests := make([]rpcRequest, 0, end-start)
+		for i := start; i < end; i++ {
+			requests = append(requests, rpcRequest{
+				JSONRPC: "2.0",
+				ID:      i,
+				Method:  "eth_call",
+				Params: []interface{}{
+					map[string]string{"to": calls[i].To, "data": "0x" + hex.EncodeToString(calls[i].Data)},
+					"latest",
+				},
+			})
+		}
+
+		responses, err := c.post(ctx, requests)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, resp := range responses {
+			if resp.ID < start || resp.ID >= end || resp.Error != nil {
+				continue
+			}
+			var result string
+			if err := json.Unmarshal(resp.Result, &result); err != nil {
+				return nil, fmt.Errorf("invalid eth_call result: %w", err)
+			}
+			data, err := hex.DecodeString(strings.TrimPrefix(result, "0x"))
+			if err != nil {
+				return nil, fmt.Errorf("invalid eth_call result: %w", err)
+			}
+			results[resp.ID] = data
+		}
+	}
+
+	return results, nil
+}
+
+// call sends a single JSON-RPC request and decodes its result
+func (c *RPCClient) call(ctx context.Context, method string, params []interface{}, result interface{}) error {
+	responses, err := c.post(ctx, []rpcRequest{{JSONRPC: "2.0", ID: 0, Method: method, Params: params}})
+	if err != nil {
+		return err
+	}
+	if len(responses) != 1 {
+		return fmt.Errorf("%s: expected 1 response, got %d", method, len(responses))
+	}
+	if responses[0].Error != nil {
+		return fmt.Errorf("%s: %s (code %d)", method, responses[0].Error.Message, responses[0].Error.Code)
+	}
+	if err := json.Unmarshal(responses[0].Result, result); err != nil {
+		return fmt.Errorf("%s: invalid result: %w", method, err)
+	}
+	return nil
+}
+
+// FinalizedBlockNumber returns the number of the latest finalized block, whose logs can't be reorged
+func (c *RPCClient) FinalizedBlockNumber(ctx context.Context) (uint64, error) {
+	var block struct {
+		Number string `json:"number"`
+	}
+	if err := c.call(ctx, "eth_getBlockByNumber", []interface{}{"finalized", false}, &block); err != nil {
+		return 0, err
+	}
+	return parseQuantity(block.Number)
+}
+
+// Log is an event log returned by eth_getLogs
+type Log struct {
+	Topics      []string `json:"topics"`
+	Data        string   `json:"data"`
+	BlockNumber string   `json:"blockNumber"`
+}
+
+// GetLogs returns the logs of a contract with the given first topic in a block range, bounds included
+func (c *RPCClient) GetLogs(ctx context.Context, address, topic string, from, to uint64) ([]Log, error) {
+	filter := map[string]interface{}{
+		"address":   address,
+		"topics":    []string{topic},
+		"fromBlock": fmt.Sprintf("0x%x", from),
+		"toBlock":   fmt.Sprintf("0x%x", to),
+	}
+	var logs []Log
+	if err := c.call(ctx, "eth_getLogs", []interface{}{filter}, &logs); err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+// parseQuantity decodes a JSON-RPC hex quantity
+func parseQuantity(s string) (uint64, error) {
+	n, err := strconv.ParseUint(strings.TrimPrefix(s, "0x"), 16, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid quantity %q: %w", s, err)
+	}
+	return n, nil
+}
+
+// post sends a JSON-RPC batch
+func (c *RPCClient) post(ctx context.Context, requests []rpcRequest) ([]rpcResponse, error) {
+	body, err := json.Marshal(requests)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var responses []rpcResponse
+	if err := json.NewDecoder(resp.Body).Decode(&responses); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return responses, nil
+}
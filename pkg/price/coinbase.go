@@ -1,6 +1,7 @@
 package price
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -57,7 +58,10 @@ func (f *Fetcher) GetCurrentETHPrice() float64 {
 	f.mu.RUnlock()
 
 	// Fetch new price
-	price := f.fetchPrice()
+	price, err := f.FetchETHPrice(context.Background())
+	if err != nil {
+		f.logger.WithError(err).Debug("Failed to fetch ETH price from Coinbase")
+	}
 
 	// Update cache
 	f.mu.Lock()
@@ -68,12 +72,11 @@ func (f *Fetcher) GetCurrentETHPrice() float64 {
 	return price
 }
 
-// fetchPrice makes the actual HTTP request to Coinbase
-func (f *Fetcher) fetchPrice() float64 {
-	req, err := http.NewRequest("GET", coinbaseURL, nil)
+// FetchETHPrice makes the actual HTTP request to Coinbase
+func (f *Fetcher) FetchETHPrice(ctx context.Context) (float64, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", coinbaseURL, nil)
 	if err != nil {
-		f.logger.WithError(err).Debug("Failed to create Coinbase request")
-		return 0.0
+		return 0.0, fmt.Errorf("failed to create Coinbase request: %w", err)
 	}
 
 	q := req.URL.Query()
@@ -82,36 +85,31 @@ func (f *Fetcher) fetchPrice() float64 {
 
 	resp, err := f.client.Do(req)
 	if err != nil {
-		f.logger.WithError(err).Debug("Failed to fetch ETH price from Coinbase")
-		return 0.0
+		return 0.0, fmt.Errorf("failed to fetch ETH price: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		f.logger.WithField("status", resp.StatusCode).Debug("Coinbase API returned non-200 status")
-		return 0.0
+		return 0.0, fmt.Errorf("coinbase API returned status %d", resp.StatusCode)
 	}
 
 	var trades []CoinbaseTrade
 	if err := json.NewDecoder(resp.Body).Decode(&trades); err != nil {
-		f.logger.WithError(err).Debug("Failed to decode Coinbase response")
-		return 0.0
+		return 0.0, fmt.Errorf("failed to decode Coinbase response: %w", err)
 	}
 
 	if len(trades) == 0 {
-		f.logger.Debug("Coinbase returned empty trades list")
-		return 0.0
+		return 0.0, fmt.Errorf("coinbase returned empty trades list")
 	}
 
 	// Parse price from string
 	var price float64
 	if _, err := parseFloat(trades[0].Price, &price); err != nil {
-		f.logger.WithError(err).Debug("Failed to parse price from Coinbase")
-		return 0.0
+		return 0.0, fmt.Errorf("failed to parse price from Coinbase: %w", err)
 	}
 
 	f.logger.WithField("price", price).Debug("Fetched ETH price from Coinbase")
-	return price
+	return price, nil
 }
 
 // parseFloat parses a float from a string
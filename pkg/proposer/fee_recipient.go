@@ -0,0 +1,77 @@
+package proposer
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// FeeRecipientPolicy resolves the fee recipients a watched proposer's blocks may pay to
+// Addresses configured for any of a validator's labels replace the defaults
+type FeeRecipientPolicy struct {
+	defaults map[string]bool
+	byLabel  map[string]map[string]bool
+}
+
+// NewFeeRecipientPolicy creates the policy for the configured addresses (compared case-insensitively)
+func NewFeeRecipientPolicy(cfg models.FeeRecipients) *FeeRecipientPolicy {
+	policy := &FeeRecipientPolicy{
+		defaults: addressSet(cfg.Default),
+		byLabel:  make(map[string]map[string]bool, len(cfg.ByLabel)),
+	}
+	for label, addresses := range cfg.ByLabel {
+		policy.byLabel[label] = addressSet(addresses)
+	}
+	return policy
+}
+
+// Expected returns the allowed fee recipients of a validator with the labels, sorted
+// It returns nil if none are configured for it, in which case its blocks aren't checked
+func (p *FeeRecipientPolicy) Expected(labels []string) []string {
+	allowed := make(map[string]bool)
+	for _, label := range labels {
+		for address := range p.byLabel[label] {
+			allowed[address] = true
+		}
+	}
+	if len(allowed) == 0 {
+		allowed = p.defaults
+	}
+	if len(allowed) == 0 {
+		return nil
+	}
+
+	expected := make([]string, 0, len(allowed))
+	for address := range allowed {
+		expected = append(expected, address)
+	}
+	sort.Strings(expected)
+	return expected
+}
+
+// Check reports whether a block paying to feeRecipient is allowed for a validator with the labels
+// checked is false if no fee recipient is configured for the validator
+func (p *FeeRecipientPolicy) Check(labels []string, feeRecipient string) (ok, checked bool) {
+	expected := p.Expected(labels)
+	if expected == nil {
+		return true, false
+	}
+
+	feeRecipient = strings.ToLower(feeRecipient)
+	for _, address := range expected {
+		if address == feeRecipient {
+			return true, true
+		}
+	}
+	return false, true
+}
+
+// addressSet lowercases addresses into a set
+func addressSet(addresses []string) map[string]bool {
+	set := make(map[string]bool, len(addresses))
+	for _, address := range addresses {
+		set[strings.ToLower(address)] = true
+	}
+	return set
+}
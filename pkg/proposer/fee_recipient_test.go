@@ -0,0 +1,43 @@
+package proposer
+
+import (
+	"testing"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+func TestFeeRecipientPolicy(t *testing.T) {
+	const (
+		vault = "0x388C818CA8B9251b393131C08a736A67ccB19297"
+		acme  = "0x1111111111111111111111111111111111111111"
+		other = "0x2222222222222222222222222222222222222222"
+	)
+	policy := NewFeeRecipientPolicy(models.FeeRecipients{
+		Default: []string{vault},
+		ByLabel: map[string][]string{"operator:acme": {acme}},
+	})
+
+	tests := []struct {
+		name         string
+		labels       []string
+		feeRecipient string
+		ok           bool
+	}{
+		{"default address", []string{"operator:other"}, "0x388c818ca8b9251b393131c08a736a67ccb19297", true},
+		{"unexpected address", []string{"operator:other"}, other, false},
+		{"label address", []string{"operator:acme"}, acme, true},
+		{"label replaces default", []string{"operator:acme"}, vault, false},
+	}
+
+	for _, tt := range tests {
+		ok, checked := policy.Check(tt.labels, tt.feeRecipient)
+		if ok != tt.ok || !checked {
+			t.Errorf("%s: Check() = %v, %v, want %v, true", tt.name, ok, checked, tt.ok)
+		}
+	}
+
+	unchecked := NewFeeRecipientPolicy(models.FeeRecipients{ByLabel: map[string][]string{"operator:acme": {acme}}})
+	if ok, checked := unchecked.Check([]string{"operator:other"}, other); !ok || checked {
+		t.Errorf("Check() without a configured address = %v, %v, want true, false", ok, checked)
+	}
+}
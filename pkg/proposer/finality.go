@@ -0,0 +1,79 @@
+package proposer
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// Proposal is a watched validator's block proposal duty as seen at the head
+type Proposal struct {
+	Slot           models.Slot
+	ValidatorIndex models.ValidatorIndex
+	HeadProposed   bool // Whether a block was found when the slot was processed
+}
+
+// FinalityTracker holds watched proposals until their slot is finalized
+// The finalized outcome may differ from the head one: a late block can be
+// picked up by the canonical chain, and a head block can be reorged out
+type FinalityTracker struct {
+	mu      sync.Mutex
+	pending map[models.Slot]Proposal
+}
+
+// NewFinalityTracker creates a new finality tracker
+func NewFinalityTracker() *FinalityTracker {
+	return &FinalityTracker{
+		pending: make(map[models.Slot]Proposal),
+	}
+}
+
+// Track records a proposal's head outcome, replacing an earlier outcome for the same slot
+func (t *FinalityTracker) Track(proposal Proposal) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.pending[proposal.Slot] = proposal
+}
+
+// Due removes and returns the proposals at or before the finalized slot, oldest first
+func (t *FinalityTracker) Due(finalizedSlot models.Slot) []Proposal {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var due []Proposal
+	for slot, proposal := range t.pending {
+		if slot <= finalizedSlot {
+			due = append(due, proposal)
+			delete(t.pending, slot)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].Slot < due[j].Slot })
+	return due
+}
+
+// Take removes and returns the proposals from one slot to another (inclusive), oldest first
+// Used to re-evaluate head outcomes a reorg may have changed
+func (t *FinalityTracker) Take(from, to models.Slot) []Proposal {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var taken []Proposal
+	for slot, proposal := range t.pending {
+		if slot >= from && slot <= to {
+			taken = append(taken, proposal)
+			delete(t.pending, slot)
+		}
+	}
+	sort.Slice(taken, func(i, j int) bool { return taken[i].Slot < taken[j].Slot })
+	return taken
+}
+
+// Pending returns the number of proposals waiting for finality
+func (t *FinalityTracker) Pending() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return len(t.pending)
+}
@@ -0,0 +1,41 @@
+package proposer
+
+import "testing"
+
+func TestFinalityTrackerDue(t *testing.T) {
+	tracker := NewFinalityTracker()
+	tracker.Track(Proposal{Slot: 70, ValidatorIndex: 3, HeadProposed: true})
+	tracker.Track(Proposal{Slot: 10, ValidatorIndex: 1})
+	tracker.Track(Proposal{Slot: 40, ValidatorIndex: 2})
+	// A later outcome for the same slot replaces the earlier one
+	tracker.Track(Proposal{Slot: 10, ValidatorIndex: 1, HeadProposed: true})
+
+	due := tracker.Due(64)
+	if len(due) != 2 || due[0].Slot != 10 || due[1].Slot != 40 {
+		t.Fatalf("Expected slots 10 and 40 to be due in order, got %+v", due)
+	}
+	if !due[0].HeadProposed {
+		t.Error("Expected the replaced outcome for slot 10")
+	}
+	if tracker.Pending() != 1 {
+		t.Errorf("Expected 1 proposal still pending, got %d", tracker.Pending())
+	}
+	if due := tracker.Due(64); len(due) != 0 {
+		t.Errorf("Expected due proposals to be returned once, got %+v", due)
+	}
+}
+
+func TestFinalityTrackerTake(t *testing.T) {
+	tracker := NewFinalityTracker()
+	tracker.Track(Proposal{Slot: 10, ValidatorIndex: 1})
+	tracker.Track(Proposal{Slot: 12, ValidatorIndex: 2, HeadProposed: true})
+	tracker.Track(Proposal{Slot: 11, ValidatorIndex: 3})
+
+	taken := tracker.Take(11, 12)
+	if len(taken) != 2 || taken[0].Slot != 11 || taken[1].Slot != 12 {
+		t.Fatalf("Expected slots 11 and 12 to be taken in order, got %+v", taken)
+	}
+	if tracker.Pending() != 1 {
+		t.Errorf("Expected 1 proposal still pending, got %d", tracker.Pending())
+	}
+}
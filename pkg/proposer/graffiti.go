@@ -0,0 +1,152 @@
+package proposer
+
+import (
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// UnknownClient is the inferred client of a graffiti that matches no signature
+const UnknownClient = "unknown"
+
+// Client is the consensus and execution client a block's graffiti points to
+type Client struct {
+	Consensus string
+	Execution string
+}
+
+// clientCodes are the two-letter client codes of the Engine API's ClientVersionV1, which clients
+// write into the graffiti as <EL code><EL commit><CL code><CL commit>, e.g. GE168dLH1c0e
+var (
+	executionCodes = map[string]string{
+		"BU": "besu",
+		"EJ": "ethereumjs",
+		"EG": "erigon",
+		"GE": "geth",
+		"NM": "nethermind",
+		"RH": "reth",
+		"TE": "trin",
+	}
+	consensusCodes = map[string]string{
+		"GR": "grandine",
+		"LH": "lighthouse",
+		"LS": "lodestar",
+		"NB": "nimbus",
+		"PM": "prysm",
+		"TK": "teku",
+	}
+)
+
+// clientVersion matches the client version codes at the start of a graffiti, commits optional
+var clientVersion = regexp.MustCompile(`^([A-Z]{2})[0-9a-fA-F]{0,4}([A-Z]{2})[0-9a-fA-F]{0,4}(\s|$)`)
+
+// clientNames matches client names in free-form graffiti, e.g. Lighthouse/v5.1.0 or "geth+teku"
+var clientNames = regexp.MustCompile(`(?i)\b(lighthouse|prysm|prysmatic|teku|nimbus|lodestar|grandine|geth|nethermind|besu|erigon|reth)\b`)
+
+// nameClients maps the names clientNames matches to the consensus or execution client
+var nameClients = map[string]Client{
+	"lighthouse": {Consensus: "lighthouse"},
+	"prysm":      {Consensus: "prysm"},
+	"prysmatic":  {Consensus: "prysm"},
+	"teku":       {Consensus: "teku"},
+	"nimbus":     {Consensus: "nimbus"},
+	"lodestar":   {Consensus: "lodestar"},
+	"grandine":   {Consensus: "grandine"},
+	"geth":       {Execution: "geth"},
+	"nethermind": {Execution: "nethermind"},
+	"besu":       {Execution: "besu"},
+	"erigon":     {Execution: "erigon"},
+	"reth":       {Execution: "reth"},
+}
+
+// graffitiPattern is a configured graffiti signature
+type graffitiPattern struct {
+	re     *regexp.Regexp
+	client Client
+}
+
+// GraffitiClassifier infers the clients that proposed a block from its graffiti
+type GraffitiClassifier struct {
+	patterns []graffitiPattern
+}
+
+// ValidateGraffitiClients checks the configured graffiti signatures
+func ValidateGraffitiClients(patterns []models.GraffitiClient) error {
+	_, err := NewGraffitiClassifier(patterns)
+	return err
+}
+
+// NewGraffitiClassifier creates a classifier trying the configured signatures, in order, before the
+// client version codes and client names
+func NewGraffitiClassifier(patterns []models.GraffitiClient) (*GraffitiClassifier, error) {
+	c := &GraffitiClassifier{patterns: make([]graffitiPattern, 0, len(patterns))}
+	for i, p := range patterns {
+		if p.Consensus == "" && p.Execution == "" {
+			return nil, fmt.Errorf("[%d]: consensus or execution is required", i)
+		}
+		re, err := regexp.Compile(p.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("[%d]: invalid pattern: %w", i, err)
+		}
+		c.patterns = append(c.patterns, graffitiPattern{re: re, client: Client{Consensus: p.Consensus, Execution: p.Execution}})
+	}
+	return c, nil
+}
+
+// Infer returns the clients a decoded graffiti points to, UnknownClient for the layers it doesn't name
+func (c *GraffitiClassifier) Infer(graffiti string) Client {
+	var client Client
+	for _, p := range c.patterns {
+		if p.re.MatchString(graffiti) {
+			client = p.client
+			break
+		}
+	}
+
+	if m := clientVersion.FindStringSubmatch(graffiti); m != nil {
+		execution, consensus := executionCodes[m[1]], consensusCodes[m[2]]
+		if execution != "" && consensus != "" {
+			client = fill(client, Client{Consensus: consensus, Execution: execution})
+		}
+	}
+	for _, name := range clientNames.FindAllString(graffiti, -1) {
+		client = fill(client, nameClients[strings.ToLower(name)])
+	}
+
+	if client.Consensus == "" {
+		client.Consensus = UnknownClient
+	}
+	if client.Execution == "" {
+		client.Execution = UnknownClient
+	}
+	return client
+}
+
+// fill sets the layers of a client that aren't known yet
+func fill(client, other Client) Client {
+	if client.Consensus == "" {
+		client.Consensus = other.Consensus
+	}
+	if client.Execution == "" {
+		client.Execution = other.Execution
+	}
+	return client
+}
+
+// DecodeGraffiti turns a block's 32-byte hex graffiti into text, dropping the zero padding
+// Graffiti that isn't valid UTF-8 is returned as is
+func DecodeGraffiti(raw string) string {
+	data, err := hex.DecodeString(strings.TrimPrefix(raw, "0x"))
+	if err != nil {
+		return raw
+	}
+	data = []byte(strings.TrimRight(string(data), "\x00"))
+	if !utf8.Valid(data) {
+		return raw
+	}
+	return string(data)
+}
@@ -0,0 +1,54 @@
+package proposer
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+func TestDecodeGraffiti(t *testing.T) {
+	raw := make([]byte, 32)
+	copy(raw, "Lighthouse/v5.1.3")
+	if got := DecodeGraffiti("0x" + hex.EncodeToString(raw)); got != "Lighthouse/v5.1.3" {
+		t.Errorf("DecodeGraffiti() = %q, want Lighthouse/v5.1.3", got)
+	}
+	if got := DecodeGraffiti("0xff00"); got != "0xff00" {
+		t.Errorf("DecodeGraffiti() = %q, want invalid UTF-8 returned as is", got)
+	}
+	if got := DecodeGraffiti("0x" + hex.EncodeToString(make([]byte, 32))); got != "" {
+		t.Errorf("DecodeGraffiti() = %q, want empty graffiti", got)
+	}
+}
+
+func TestGraffitiClassifier(t *testing.T) {
+	classifier, err := NewGraffitiClassifier([]models.GraffitiClient{{Pattern: "^acme-lh-", Consensus: "lighthouse"}})
+	if err != nil {
+		t.Fatalf("NewGraffitiClassifier() error = %v", err)
+	}
+
+	tests := []struct {
+		graffiti string
+		expected Client
+	}{
+		{"GE168dLH1c0e", Client{Consensus: "lighthouse", Execution: "geth"}},
+		{"NMTK my pool", Client{Consensus: "teku", Execution: "nethermind"}},
+		{"Lighthouse/v5.1.3-3058b96", Client{Consensus: "lighthouse", Execution: UnknownClient}},
+		{"prysm + besu", Client{Consensus: "prysm", Execution: "besu"}},
+		{"acme-lh-3 reth", Client{Consensus: "lighthouse", Execution: "reth"}},
+		{"ZZ12LH34", Client{Consensus: UnknownClient, Execution: UnknownClient}},
+		{"", Client{Consensus: UnknownClient, Execution: UnknownClient}},
+	}
+	for _, tt := range tests {
+		if got := classifier.Infer(tt.graffiti); got != tt.expected {
+			t.Errorf("Infer(%q) = %+v, want %+v", tt.graffiti, got, tt.expected)
+		}
+	}
+
+	if _, err := NewGraffitiClassifier([]models.GraffitiClient{{Pattern: "(", Consensus: "teku"}}); err == nil {
+		t.Error("Expected an invalid pattern to be rejected")
+	}
+	if _, err := NewGraffitiClassifier([]models.GraffitiClient{{Pattern: "x"}}); err == nil {
+		t.Error("Expected a pattern without client to be rejected")
+	}
+}
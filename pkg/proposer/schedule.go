@@ -3,6 +3,7 @@ package proposer
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/enriquemanuel/eth-validator-watcher/pkg/beacon"
@@ -28,25 +29,53 @@ func NewSchedule(client *beacon.Client, logger *logrus.Logger) *Schedule {
 	}
 }
 
+// DutyChange is a slot whose proposer changed when its epoch's duties were refetched
+type DutyChange struct {
+	Slot models.Slot
+	Old  models.ValidatorIndex
+	New  models.ValidatorIndex
+}
+
 // Update fetches and updates the proposer schedule for an epoch
 func (s *Schedule) Update(ctx context.Context, epoch models.Epoch) error {
 	duties, err := s.client.GetProposerDuties(ctx, epoch)
 	if err != nil {
 		return fmt.Errorf("failed to fetch proposer duties for epoch %d: %w", epoch, err)
 	}
+	s.apply(epoch, duties)
+	return nil
+}
 
+// Refresh refetches an epoch's proposer duties and returns the slots whose known proposer changed
+// Duties depend on the chain up to the previous epoch, so a reorg across it can reassign them; the
+// response cached for the epoch is revalidated rather than served again
+func (s *Schedule) Refresh(ctx context.Context, epoch models.Epoch) ([]DutyChange, error) {
+	duties, err := s.client.RefetchProposerDuties(ctx, epoch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch proposer duties for epoch %d: %w", epoch, err)
+	}
+	return s.apply(epoch, duties), nil
+}
+
+// apply records an epoch's duties and returns the slots whose known proposer changed
+func (s *Schedule) apply(epoch models.Epoch, duties []models.ProposerDuty) []DutyChange {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	var changes []DutyChange
 	for _, duty := range duties {
+		if old, ok := s.duties[duty.Slot]; ok && old != duty.ValidatorIndex {
+			changes = append(changes, DutyChange{Slot: duty.Slot, Old: old, New: duty.ValidatorIndex})
+		}
 		s.duties[duty.Slot] = duty.ValidatorIndex
 		if duty.Slot > s.maxSlot {
 			s.maxSlot = duty.Slot
 		}
 	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Slot < changes[j].Slot })
 
-	s.logger.Debugf("Updated proposer schedule for epoch %d: %d duties", epoch, len(duties))
-	return nil
+	s.logger.Debugf("Updated proposer schedule for epoch %d: %d duties, %d changed", epoch, len(duties), len(changes))
+	return changes
 }
 
 // GetProposer returns the validator index of the proposer for a slot
@@ -81,6 +110,21 @@ func (s *Schedule) GetDuties(validatorIndex models.ValidatorIndex) []models.Slot
 	return slots
 }
 
+// Upcoming returns the scheduled duties from a slot on, earliest first
+func (s *Schedule) Upcoming(from models.Slot) []models.ProposerDuty {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	duties := make([]models.ProposerDuty, 0)
+	for slot, proposer := range s.duties {
+		if slot >= from {
+			duties = append(duties, models.ProposerDuty{Slot: slot, ValidatorIndex: proposer})
+		}
+	}
+	sort.Slice(duties, func(i, j int) bool { return duties[i].Slot < duties[j].Slot })
+	return duties
+}
+
 // Cleanup removes old duties before the specified slot
 func (s *Schedule) Cleanup(beforeSlot models.Slot) {
 	s.mu.Lock()
@@ -0,0 +1,77 @@
+package proposer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/beacon"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+func TestScheduleRefresh(t *testing.T) {
+	proposerOf33 := 5
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"data":[{"pubkey":"0x01","validator_index":"4","slot":"32"},{"pubkey":"0x02","validator_index":"%d","slot":"33"}]}`, proposerOf33)
+	}))
+	defer server.Close()
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.PanicLevel)
+	schedule := NewSchedule(beacon.NewClient(server.URL, time.Second, logger), logger)
+
+	if err := schedule.Update(context.Background(), 1); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	proposerOf33 = 9
+	changes, err := schedule.Refresh(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if len(changes) != 1 || changes[0] != (DutyChange{Slot: 33, Old: 5, New: 9}) {
+		t.Errorf("Refresh() = %+v, want slot 33 moved from 5 to 9", changes)
+	}
+	if proposer, _ := schedule.GetProposer(33); proposer != 9 {
+		t.Errorf("GetProposer(33) = %d, want 9", proposer)
+	}
+}
+
+func TestScheduleRefreshAfterReorgOfCurrentEpoch(t *testing.T) {
+	proposerOf33 := 5
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"data":[{"pubkey":"0x01","validator_index":"4","slot":"32"},{"pubkey":"0x02","validator_index":"%d","slot":"33"}]}`, proposerOf33)
+	}))
+	defer server.Close()
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.PanicLevel)
+	client := beacon.NewClient(server.URL, time.Second, logger)
+	client.SetEpochClock(func() models.Epoch { return 1 })
+	schedule := NewSchedule(client, logger)
+
+	if err := schedule.Update(context.Background(), 1); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	// A reorg reassigns slot 33: an update during the epoch still serves the cached duties,
+	// a refresh refetches them
+	proposerOf33 = 9
+	if err := schedule.Update(context.Background(), 1); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if proposer, _ := schedule.GetProposer(33); proposer != 5 {
+		t.Errorf("GetProposer(33) after Update = %d, want the cached 5", proposer)
+	}
+	changes, err := schedule.Refresh(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if len(changes) != 1 || changes[0] != (DutyChange{Slot: 33, Old: 5, New: 9}) {
+		t.Errorf("Refresh() = %+v, want slot 33 moved from 5 to 9", changes)
+	}
+}
@@ -0,0 +1,36 @@
+package queues
+
+import "github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+
+// Pre-activation stages of a watched key, from no deposit to waiting in the activation queue
+const (
+	StageNoDeposit          = "no_deposit"          // Not in the validator set and no deposit found
+	StageDepositSeen        = "deposit_seen"        // Deposited on the execution layer, not processed by the beacon chain yet
+	StageDepositPending     = "deposit_pending"     // In the beacon state's pending deposits
+	StagePendingInitialized = "pending_initialized" // In the validator set, not yet eligible for activation
+	StagePendingQueued      = "pending_queued"      // Eligible, waiting in the activation queue
+)
+
+// Stages lists the pre-activation stages in order
+var Stages = []string{StageNoDeposit, StageDepositSeen, StageDepositPending, StagePendingInitialized, StagePendingQueued}
+
+// PreActivationStage returns a watched key's pre-activation stage, false once it is activated
+// Keys in the validator set are staged by their status; the others by where their deposit was found
+func PreActivationStage(status models.ValidatorStatus, inSet, depositPending, depositSeen bool) (string, bool) {
+	if inSet {
+		switch status {
+		case models.StatusPendingInitialized:
+			return StagePendingInitialized, true
+		case models.StatusPendingQueued:
+			return StagePendingQueued, true
+		}
+		return "", false
+	}
+	switch {
+	case depositPending:
+		return StageDepositPending, true
+	case depositSeen:
+		return StageDepositSeen, true
+	}
+	return StageNoDeposit, true
+}
@@ -0,0 +1,35 @@
+package queues
+
+import (
+	"testing"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+func TestPreActivationStage(t *testing.T) {
+	tests := []struct {
+		name           string
+		status         models.ValidatorStatus
+		inSet          bool
+		depositPending bool
+		depositSeen    bool
+		want           string
+		ok             bool
+	}{
+		{"no deposit", "", false, false, false, StageNoDeposit, true},
+		{"deposit seen", "", false, false, true, StageDepositSeen, true},
+		{"pending deposit wins", "", false, true, true, StageDepositPending, true},
+		{"pending initialized", models.StatusPendingInitialized, true, false, true, StagePendingInitialized, true},
+		{"pending queued", models.StatusPendingQueued, true, false, false, StagePendingQueued, true},
+		{"active", models.StatusActiveOngoing, true, false, false, "", false},
+		{"exited", models.StatusExitedUnslashed, true, false, false, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := PreActivationStage(tt.status, tt.inSet, tt.depositPending, tt.depositSeen)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("PreActivationStage() = %q, %v, want %q, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
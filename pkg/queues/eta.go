@@ -0,0 +1,300 @@
+package queues
+
+import (
+	"sort"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/duties"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// Queue names of a validator's position
+const (
+	QueueActivation = "activation"
+	QueueExit       = "exit"
+)
+
+// finalityDelay is the number of epochs until an activation eligibility epoch is finalized
+const finalityDelay = 2
+
+// effectiveBalanceIncrement is what the Electra balance churn is rounded down to
+const effectiveBalanceIncrement = models.Gwei(1_000_000_000)
+
+// ChurnSpec holds the spec constants the activation and exit churn derive from
+type ChurnSpec struct {
+	Electra                     bool        // Churn is a balance and deposits queue before activation
+	StakeUnit                   models.Gwei // Effective balance of a full validator
+	MinPerEpochChurnLimit       uint64
+	ChurnLimitQuotient          uint64
+	MaxActivationChurnLimit     uint64
+	MinChurnLimitElectra        models.Gwei
+	MaxActivationExitChurnLimit models.Gwei
+	MaxSeedLookahead            uint64
+}
+
+// ChurnSpecOf reads the churn constants of a spec, with mainnet values for those the node doesn't report
+func ChurnSpecOf(spec *models.Spec) ChurnSpec {
+	c := ChurnSpec{
+		Electra:                     spec.MinActivationBalance > 0,
+		StakeUnit:                   spec.StakeUnit(),
+		MinPerEpochChurnLimit:       spec.MinPerEpochChurnLimit,
+		ChurnLimitQuotient:          spec.ChurnLimitQuotient,
+		MaxActivationChurnLimit:     spec.MaxPerEpochActivationChurnLimit,
+		MinChurnLimitElectra:        spec.MinPerEpochChurnLimitElectra,
+		MaxActivationExitChurnLimit: spec.MaxPerEpochActivationExitChurnLimit,
+		MaxSeedLookahead:            spec.MaxSeedLookahead,
+	}
+	if c.MinPerEpochChurnLimit == 0 {
+		c.MinPerEpochChurnLimit = 4
+	}
+	if c.ChurnLimitQuotient == 0 {
+		c.ChurnLimitQuotient = 65536
+	}
+	if c.MaxActivationChurnLimit == 0 {
+		c.MaxActivationChurnLimit = 8
+	}
+	if c.MinChurnLimitElectra == 0 {
+		c.MinChurnLimitElectra = 128_000_000_000
+	}
+	if c.MaxActivationExitChurnLimit == 0 {
+		c.MaxActivationExitChurnLimit = 256_000_000_000
+	}
+	if c.MaxSeedLookahead == 0 {
+		c.MaxSeedLookahead = 4
+	}
+	return c
+}
+
+// activationDelay is the number of epochs between leaving the queue and activation: the
+// eligibility epoch is finalized, then the activation is scheduled past the seed lookahead
+func (c ChurnSpec) activationDelay() uint64 {
+	return finalityDelay + 1 + c.MaxSeedLookahead
+}
+
+// Position is a watched validator's place in the activation or exit queue
+type Position struct {
+	ValidatorIndex models.ValidatorIndex `json:"validator_index"`
+	Queue          string                `json:"queue"`
+	Ahead          models.Gwei           `json:"ahead_gwei"` // Stake queued before the validator's
+	ETAEpochs      uint64                `json:"eta_epochs"` // Epochs until activation, or until withdrawable for exits
+	Epoch          models.Epoch          `json:"eta_epoch"`  // Epoch of the ETA
+	Scheduled      bool                  `json:"scheduled"`  // The beacon state already set the epoch, it is not an estimate
+}
+
+// Network is how long the activation and exit queues take at an epoch
+type Network struct {
+	ActivationEpochs uint64 `json:"activation_epochs"` // For a validator joining the activation queue now
+	ExitEpochs       uint64 `json:"exit_epochs"`       // For an exit initiated now
+}
+
+// queued is a validator waiting in a queue
+type queued struct {
+	index   models.ValidatorIndex
+	epoch   models.Epoch // Eligibility epoch for activations, exit epoch for exits
+	balance models.Gwei
+}
+
+// before orders queue entries by epoch, then index
+func (q queued) before(other queued) bool {
+	if q.epoch != other.epoch {
+		return q.epoch < other.epoch
+	}
+	return q.index < other.index
+}
+
+// Estimator estimates queue positions at an epoch from one pass over the full validator set
+// Without the full set only the epochs the beacon state already scheduled are known
+type Estimator struct {
+	spec     ChurnSpec
+	epoch    models.Epoch
+	deposits []models.PendingDeposit
+
+	scanned       bool
+	sorted        bool
+	activeCount   uint64
+	activeBalance models.Gwei
+	lastExitEpoch models.Epoch
+	activations   []queued // Pre-Electra activation queue
+	exits         []queued // Exits still ahead
+}
+
+// NewEstimator creates an estimator at an epoch; deposits is the pending deposits queue (Electra)
+func NewEstimator(spec ChurnSpec, epoch models.Epoch, deposits []models.PendingDeposit) *Estimator {
+	return &Estimator{spec: spec, epoch: epoch, deposits: deposits}
+}
+
+// Scan adds a validator of the full set
+func (e *Estimator) Scan(v *models.Validator) {
+	e.scanned = true
+	e.sorted = false
+	d := &v.Data
+
+	if d.ActivationEpoch <= e.epoch && e.epoch < d.ExitEpoch {
+		e.activeCount++
+		e.activeBalance += d.EffectiveBalance
+	}
+	if d.ExitEpoch != duties.FarFutureEpoch {
+		e.lastExitEpoch = max(e.lastExitEpoch, d.ExitEpoch)
+		if d.ExitEpoch > e.epoch {
+			e.exits = append(e.exits, queued{index: v.Index, epoch: d.ExitEpoch, balance: d.EffectiveBalance})
+		}
+	}
+	if !e.spec.Electra && d.ActivationEligibilityEpoch != duties.FarFutureEpoch && d.ActivationEpoch == duties.FarFutureEpoch {
+		e.activations = append(e.activations, queued{index: v.Index, epoch: d.ActivationEligibilityEpoch, balance: d.EffectiveBalance})
+	}
+}
+
+// sort orders the queues once all validators are scanned
+func (e *Estimator) sort() {
+	if e.sorted {
+		return
+	}
+	sort.Slice(e.activations, func(i, j int) bool { return e.activations[i].before(e.activations[j]) })
+	sort.Slice(e.exits, func(i, j int) bool { return e.exits[i].before(e.exits[j]) })
+	e.sorted = true
+}
+
+// ActivationChurn returns the stake that can leave the activation queue per epoch
+func (e *Estimator) ActivationChurn() models.Gwei {
+	if e.spec.Electra {
+		return e.balanceChurn()
+	}
+	return models.Gwei(min(e.countChurn(), e.spec.MaxActivationChurnLimit)) * e.spec.StakeUnit
+}
+
+// countChurn is the pre-Electra churn limit in validators
+func (e *Estimator) countChurn() uint64 {
+	return max(e.spec.MinPerEpochChurnLimit, e.activeCount/e.spec.ChurnLimitQuotient)
+}
+
+// balanceChurn is the Electra activation and exit churn limit in Gwei
+func (e *Estimator) balanceChurn() models.Gwei {
+	churn := max(e.spec.MinChurnLimitElectra, e.activeBalance/models.Gwei(e.spec.ChurnLimitQuotient))
+	churn -= churn % effectiveBalanceIncrement
+	return min(churn, e.spec.MaxActivationExitChurnLimit)
+}
+
+// epochsFor returns the epochs needed for the churn to let through an amount of stake
+func epochsFor(amount, churn models.Gwei) uint64 {
+	if churn == 0 {
+		return 0
+	}
+	return uint64((amount + churn - 1) / churn)
+}
+
+// Network returns how long the queues take for a validator joining them now
+// Returns false if the full validator set wasn't scanned
+func (e *Estimator) Network() (Network, bool) {
+	if !e.scanned {
+		return Network{}, false
+	}
+	e.sort()
+
+	var queuedStake models.Gwei
+	if e.spec.Electra {
+		for _, d := range e.deposits {
+			queuedStake += d.Amount
+		}
+	} else {
+		for _, q := range e.activations {
+			queuedStake += q.balance
+		}
+	}
+
+	earliestExit := e.epoch + 1 + models.Epoch(e.spec.MaxSeedLookahead)
+	return Network{
+		ActivationEpochs: epochsFor(queuedStake, e.ActivationChurn()) + e.spec.activationDelay(),
+		ExitEpochs:       uint64(max(e.lastExitEpoch, earliestExit) - e.epoch),
+	}, true
+}
+
+// Positions returns the queue positions of the watched validators that are waiting for activation
+// or to become withdrawable, in the order given
+func (e *Estimator) Positions(watched []*models.Validator) []Position {
+	e.sort()
+
+	positions := make([]Position, 0)
+	for _, v := range watched {
+		d := &v.Data
+		switch {
+		case d.ExitEpoch != duties.FarFutureEpoch:
+			if d.WithdrawableEpoch <= e.epoch {
+				continue
+			}
+			positions = append(positions, Position{
+				ValidatorIndex: v.Index,
+				Queue:          QueueExit,
+				Ahead:          e.exitAhead(v),
+				ETAEpochs:      uint64(d.WithdrawableEpoch - e.epoch),
+				Epoch:          d.WithdrawableEpoch,
+				Scheduled:      true,
+			})
+		case d.ActivationEpoch != duties.FarFutureEpoch:
+			if d.ActivationEpoch <= e.epoch {
+				continue
+			}
+			positions = append(positions, Position{
+				ValidatorIndex: v.Index,
+				Queue:          QueueActivation,
+				ETAEpochs:      uint64(d.ActivationEpoch - e.epoch),
+				Epoch:          d.ActivationEpoch,
+				Scheduled:      true,
+			})
+		default:
+			if !e.scanned {
+				continue
+			}
+			ahead, eta := e.activationEstimate(v)
+			positions = append(positions, Position{
+				ValidatorIndex: v.Index,
+				Queue:          QueueActivation,
+				Ahead:          ahead,
+				ETAEpochs:      eta,
+				Epoch:          e.epoch + models.Epoch(eta),
+			})
+		}
+	}
+	return positions
+}
+
+// exitAhead returns the stake scheduled to exit before a validator
+func (e *Estimator) exitAhead(v *models.Validator) models.Gwei {
+	self := queued{index: v.Index, epoch: v.Data.ExitEpoch}
+	var ahead models.Gwei
+	for _, q := range e.exits {
+		if !q.before(self) {
+			break
+		}
+		ahead += q.balance
+	}
+	return ahead
+}
+
+// activationEstimate returns the stake ahead of a validator that awaits activation and its ETA in epochs
+// Since Electra the churn applies to deposits, so the queue is the pending deposits up to the
+// validator's first one; before, it is the validators eligible earlier
+func (e *Estimator) activationEstimate(v *models.Validator) (models.Gwei, uint64) {
+	var ahead, own models.Gwei
+	if e.spec.Electra {
+		for _, d := range e.deposits {
+			if d.Pubkey == v.Data.Pubkey {
+				own = d.Amount
+				break
+			}
+			ahead += d.Amount
+		}
+		if own == 0 {
+			// Nothing queued: the validator only waits for its eligibility to finalize
+			ahead = 0
+		}
+	} else {
+		self := queued{index: v.Index, epoch: v.Data.ActivationEligibilityEpoch}
+		own = v.Data.EffectiveBalance
+		for _, q := range e.activations {
+			if !q.before(self) {
+				break
+			}
+			ahead += q.balance
+		}
+	}
+	return ahead, epochsFor(ahead+own, e.ActivationChurn()) + e.spec.activationDelay()
+}
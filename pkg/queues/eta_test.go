@@ -0,0 +1,118 @@
+package queues
+
+import (
+	"testing"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/duties"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+const gwei32 = models.Gwei(32_000_000_000)
+
+func testValidator(index models.ValidatorIndex, eligibility, activation, exit, withdrawable models.Epoch) *models.Validator {
+	v := &models.Validator{Index: index}
+	v.Data.Pubkey = "0x" + string(rune('a'+index))
+	v.Data.EffectiveBalance = gwei32
+	v.Data.ActivationEligibilityEpoch = eligibility
+	v.Data.ActivationEpoch = activation
+	v.Data.ExitEpoch = exit
+	v.Data.WithdrawableEpoch = withdrawable
+	return v
+}
+
+func TestChurnSpecDefaults(t *testing.T) {
+	c := ChurnSpecOf(&models.Spec{})
+	if c.Electra || c.MinPerEpochChurnLimit != 4 || c.ChurnLimitQuotient != 65536 || c.MaxSeedLookahead != 4 {
+		t.Errorf("Unexpected defaults: %+v", c)
+	}
+	if c.activationDelay() != 7 {
+		t.Errorf("Expected an activation delay of 7 epochs, got %d", c.activationDelay())
+	}
+}
+
+func TestPositionsPreElectra(t *testing.T) {
+	far := duties.FarFutureEpoch
+	all := []*models.Validator{
+		testValidator(0, 0, 0, far, far),    // Active
+		testValidator(1, 0, 0, 120, 376),    // Exiting
+		testValidator(2, 0, 0, 110, 366),    // Exiting earlier
+		testValidator(3, 95, far, far, far), // Queued second
+		testValidator(4, 90, far, far, far), // Queued first
+		testValidator(5, 99, 105, far, far), // Activation scheduled
+		testValidator(6, 0, 0, 50, 90),      // Withdrawable already
+	}
+
+	e := NewEstimator(ChurnSpecOf(&models.Spec{}), 100, nil)
+	for _, v := range all {
+		e.Scan(v)
+	}
+
+	positions := e.Positions([]*models.Validator{all[0], all[1], all[3], all[5], all[6]})
+	if len(positions) != 3 {
+		t.Fatalf("Expected 3 positions, got %+v", positions)
+	}
+
+	exit := positions[0]
+	if exit.Queue != QueueExit || !exit.Scheduled || exit.ETAEpochs != 276 || exit.Ahead != gwei32 {
+		t.Errorf("Unexpected exit position: %+v", exit)
+	}
+
+	// Churn of 4 validators: validator 4 and 3 leave the queue within an epoch, then wait 7
+	queued := positions[1]
+	if queued.Queue != QueueActivation || queued.Scheduled || queued.Ahead != gwei32 || queued.ETAEpochs != 8 || queued.Epoch != 108 {
+		t.Errorf("Unexpected queued position: %+v", queued)
+	}
+
+	scheduled := positions[2]
+	if !scheduled.Scheduled || scheduled.ETAEpochs != 5 {
+		t.Errorf("Unexpected scheduled position: %+v", scheduled)
+	}
+
+	network, ok := e.Network()
+	if !ok {
+		t.Fatal("Expected network estimates after a scan")
+	}
+	if network.ExitEpochs != 20 || network.ActivationEpochs != 8 {
+		t.Errorf("Unexpected network estimates: %+v", network)
+	}
+}
+
+func TestPositionsElectra(t *testing.T) {
+	far := duties.FarFutureEpoch
+	spec := ChurnSpecOf(&models.Spec{MinActivationBalance: gwei32})
+	pending := testValidator(1, far, far, far, far)
+	deposits := []models.PendingDeposit{
+		{Pubkey: "0xother", Amount: 200_000_000_000},
+		{Pubkey: pending.Data.Pubkey, Amount: gwei32},
+	}
+
+	e := NewEstimator(spec, 100, deposits)
+	e.Scan(testValidator(0, 0, 0, far, far))
+	e.Scan(pending)
+
+	positions := e.Positions([]*models.Validator{pending})
+	if len(positions) != 1 {
+		t.Fatalf("Expected 1 position, got %+v", positions)
+	}
+	// 232 ETH through a 128 ETH churn takes 2 epochs, then 7 until activation
+	p := positions[0]
+	if p.Ahead != 200_000_000_000 || p.ETAEpochs != 9 {
+		t.Errorf("Unexpected position: %+v", p)
+	}
+}
+
+func TestPositionsWithoutScan(t *testing.T) {
+	far := duties.FarFutureEpoch
+	e := NewEstimator(ChurnSpecOf(&models.Spec{}), 100, nil)
+
+	positions := e.Positions([]*models.Validator{
+		testValidator(0, 90, far, far, far),
+		testValidator(1, 90, 104, far, far),
+	})
+	if len(positions) != 1 || positions[0].ValidatorIndex != 1 {
+		t.Errorf("Expected only the scheduled activation, got %+v", positions)
+	}
+	if _, ok := e.Network(); ok {
+		t.Error("Expected no network estimates without a scan")
+	}
+}
@@ -0,0 +1,118 @@
+package queues
+
+import (
+	"fmt"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// Queue names used in metrics
+const (
+	QueueDeposits       = "deposits"
+	QueueConsolidations = "consolidations"
+	QueueWithdrawals    = "withdrawals"
+)
+
+// Snapshot is the content of the beacon state's pending queues at an epoch
+type Snapshot struct {
+	Epoch          models.Epoch
+	Deposits       []models.PendingDeposit
+	Consolidations []models.PendingConsolidation
+	Withdrawals    []models.PendingWithdrawal
+
+	// Stale marks the queues that failed to fetch; they hold the previous snapshot's entries
+	Stale map[string]bool
+}
+
+// Flow describes one queue's size and how it changed since the previous snapshot
+type Flow struct {
+	Queue  string
+	Size   int
+	Amount models.Gwei // Total queued amount (0 for consolidations)
+
+	// Rates are normalized per epoch and only set when both snapshots fetched the queue
+	HasRates            bool
+	InflowPerEpoch      float64
+	OutflowPerEpoch     float64
+	InflowGweiPerEpoch  float64
+	OutflowGweiPerEpoch float64
+}
+
+// item is a queue entry reduced to an identity key and amount
+type item struct {
+	key    string
+	amount models.Gwei
+}
+
+// items returns a queue's entries
+func (s *Snapshot) items(queue string) []item {
+	var items []item
+	switch queue {
+	case QueueDeposits:
+		items = make([]item, len(s.Deposits))
+		for i, d := range s.Deposits {
+			items[i] = item{key: fmt.Sprintf("%s/%d/%d", d.Pubkey, d.Amount, d.Slot), amount: d.Amount}
+		}
+	case QueueConsolidations:
+		items = make([]item, len(s.Consolidations))
+		for i, c := range s.Consolidations {
+			items[i] = item{key: fmt.Sprintf("%d/%d", c.SourceIndex, c.TargetIndex)}
+		}
+	case QueueWithdrawals:
+		items = make([]item, len(s.Withdrawals))
+		for i, w := range s.Withdrawals {
+			items[i] = item{key: fmt.Sprintf("%d/%d/%d", w.ValidatorIndex, w.Amount, w.WithdrawableEpoch), amount: w.Amount}
+		}
+	}
+	return items
+}
+
+// Flows computes each queue's size and, if prev is set, its inflow and outflow since prev
+// Entries present in both snapshots stayed queued, the rest left (outflow) or joined (inflow);
+// a queue that is stale in either snapshot has no rates, as its entries aren't from that epoch
+func Flows(prev, cur *Snapshot) []Flow {
+	queueNames := []string{QueueDeposits, QueueConsolidations, QueueWithdrawals}
+	flows := make([]Flow, 0, len(queueNames))
+
+	for _, queue := range queueNames {
+		current := cur.items(queue)
+		flow := Flow{Queue: queue, Size: len(current)}
+		for _, it := range current {
+			flow.Amount += it.amount
+		}
+
+		if prev != nil && cur.Epoch > prev.Epoch && !prev.Stale[queue] && !cur.Stale[queue] {
+			// Multiset of previous entries (duplicates are possible, e.g. top-up deposits)
+			remaining := make(map[string]int)
+			var prevAmount models.Gwei
+			for _, it := range prev.items(queue) {
+				remaining[it.key]++
+				prevAmount += it.amount
+			}
+
+			var inflow int
+			var inflowGwei, stayedGwei models.Gwei
+			for _, it := range current {
+				if remaining[it.key] > 0 {
+					remaining[it.key]--
+					stayedGwei += it.amount
+					continue
+				}
+				inflow++
+				inflowGwei += it.amount
+			}
+			outflow := len(prev.items(queue)) - (len(current) - inflow)
+
+			epochs := float64(cur.Epoch - prev.Epoch)
+			flow.HasRates = true
+			flow.InflowPerEpoch = float64(inflow) / epochs
+			flow.OutflowPerEpoch = float64(outflow) / epochs
+			flow.InflowGweiPerEpoch = float64(inflowGwei) / epochs
+			flow.OutflowGweiPerEpoch = float64(prevAmount-stayedGwei) / epochs
+		}
+
+		flows = append(flows, flow)
+	}
+
+	return flows
+}
@@ -0,0 +1,103 @@
+package queues
+
+import (
+	"testing"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+func flowFor(flows []Flow, queue string) Flow {
+	for _, flow := range flows {
+		if flow.Queue == queue {
+			return flow
+		}
+	}
+	return Flow{}
+}
+
+func TestFlowsWithoutPrevious(t *testing.T) {
+	cur := &Snapshot{
+		Epoch:    10,
+		Deposits: []models.PendingDeposit{{Pubkey: "0xa", Amount: 32_000_000_000, Slot: 1}},
+	}
+
+	deposits := flowFor(Flows(nil, cur), QueueDeposits)
+	if deposits.Size != 1 || deposits.Amount != 32_000_000_000 {
+		t.Errorf("Unexpected deposits flow: %+v", deposits)
+	}
+	if deposits.HasRates {
+		t.Error("Expected no rates without a previous snapshot")
+	}
+}
+
+func TestFlowsBetweenEpochs(t *testing.T) {
+	prev := &Snapshot{
+		Epoch: 10,
+		Deposits: []models.PendingDeposit{
+			{Pubkey: "0xa", Amount: 32_000_000_000, Slot: 1},
+			{Pubkey: "0xb", Amount: 1_000_000_000, Slot: 2},
+			{Pubkey: "0xb", Amount: 1_000_000_000, Slot: 2}, // Duplicate entries are counted separately
+		},
+		Withdrawals: []models.PendingWithdrawal{
+			{ValidatorIndex: 5, Amount: 100, WithdrawableEpoch: 12},
+		},
+	}
+	cur := &Snapshot{
+		Epoch: 12,
+		Deposits: []models.PendingDeposit{
+			{Pubkey: "0xb", Amount: 1_000_000_000, Slot: 2},
+			{Pubkey: "0xc", Amount: 2_000_000_000, Slot: 70},
+			{Pubkey: "0xd", Amount: 2_000_000_000, Slot: 71},
+		},
+		Withdrawals: []models.PendingWithdrawal{
+			{ValidatorIndex: 5, Amount: 100, WithdrawableEpoch: 12},
+		},
+	}
+
+	flows := Flows(prev, cur)
+
+	// 2 deposits left the queue (0xa and one 0xb) and 2 joined, over 2 epochs
+	deposits := flowFor(flows, QueueDeposits)
+	if !deposits.HasRates {
+		t.Fatal("Expected rates with a previous snapshot")
+	}
+	if deposits.InflowPerEpoch != 1 || deposits.OutflowPerEpoch != 1 {
+		t.Errorf("Expected 1 in / 1 out per epoch, got %v / %v", deposits.InflowPerEpoch, deposits.OutflowPerEpoch)
+	}
+	if deposits.InflowGweiPerEpoch != 2_000_000_000 || deposits.OutflowGweiPerEpoch != 16_500_000_000 {
+		t.Errorf("Unexpected gwei rates: in %v, out %v", deposits.InflowGweiPerEpoch, deposits.OutflowGweiPerEpoch)
+	}
+
+	withdrawals := flowFor(flows, QueueWithdrawals)
+	if withdrawals.InflowPerEpoch != 0 || withdrawals.OutflowPerEpoch != 0 {
+		t.Errorf("Expected unchanged withdrawals queue, got %+v", withdrawals)
+	}
+}
+
+func TestFlowsStaleQueue(t *testing.T) {
+	deposits := []models.PendingDeposit{{Pubkey: "0xa", Amount: 32_000_000_000, Slot: 1}}
+	prev := &Snapshot{Epoch: 10, Deposits: deposits}
+	cur := &Snapshot{
+		Epoch:       11,
+		Deposits:    deposits,
+		Withdrawals: []models.PendingWithdrawal{{ValidatorIndex: 5, Amount: 100, WithdrawableEpoch: 12}},
+		Stale:       map[string]bool{QueueDeposits: true},
+	}
+
+	flows := Flows(prev, cur)
+
+	// The failed queue keeps its size but has no rates, the others still do
+	stale := flowFor(flows, QueueDeposits)
+	if stale.Size != 1 || stale.HasRates {
+		t.Errorf("Expected the stale deposits queue sized without rates, got %+v", stale)
+	}
+	if withdrawals := flowFor(flows, QueueWithdrawals); !withdrawals.HasRates || withdrawals.InflowPerEpoch != 1 {
+		t.Errorf("Expected withdrawal rates, got %+v", withdrawals)
+	}
+
+	// Nor does the next epoch compare against the stale entries
+	next := &Snapshot{Epoch: 12, Deposits: deposits}
+	if flow := flowFor(Flows(cur, next), QueueDeposits); flow.HasRates {
+		t.Errorf("Expected no rates against a stale snapshot, got %+v", flow)
+	}
+}
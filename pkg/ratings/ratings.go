@@ -0,0 +1,204 @@
+package ratings
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// Providers of effectiveness ratings
+const (
+	ProviderRated       = "rated"
+	ProviderBeaconchain = "beaconcha.in"
+)
+
+// defaultURLs are the providers' mainnet APIs, used when no URL is configured
+var defaultURLs = map[string]string{
+	ProviderRated:       "https://api.rated.network",
+	ProviderBeaconchain: "https://beaconcha.in",
+}
+
+// maxConcurrentRequests bounds the Rated lookups in flight, which are one per validator
+const maxConcurrentRequests = 4
+
+// beaconchainBatchSize is the most validators beaconcha.in accepts in one request
+const beaconchainBatchSize = 100
+
+// Effectiveness is the effectiveness rating of each validator that got one, in percent (100 is perfect)
+type Effectiveness map[models.ValidatorIndex]float64
+
+// Client pulls the effectiveness ratings of validators from a third-party provider
+type Client struct {
+	provider string
+	baseURL  string
+	apiKey   string
+	network  string
+	client   *http.Client
+}
+
+// IsProvider reports whether a provider is supported
+func IsProvider(provider string) bool {
+	_, ok := defaultURLs[provider]
+	return ok
+}
+
+// NewClient creates a client of the configured provider; network is the Rated network name
+func NewClient(cfg models.ExternalRatings, network string, timeout time.Duration) *Client {
+	baseURL := cfg.URL
+	if baseURL == "" {
+		baseURL = defaultURLs[cfg.Provider]
+	}
+	if cfg.Network != "" {
+		network = cfg.Network
+	}
+
+	return &Client{
+		provider: cfg.Provider,
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		apiKey:   cfg.APIKey,
+		network:  network,
+		client:   &http.Client{Timeout: timeout},
+	}
+}
+
+// Provider returns the provider the client rates validators with
+func (c *Client) Provider() string {
+	return c.provider
+}
+
+// Effectiveness looks up the validators' latest ratings
+// It only fails if no validator could be looked up at all
+func (c *Client) Effectiveness(ctx context.Context, indices []models.ValidatorIndex) (Effectiveness, error) {
+	if c.provider == ProviderBeaconchain {
+		return c.beaconchainEffectiveness(ctx, indices)
+	}
+	return c.ratedEffectiveness(ctx, indices)
+}
+
+// ratedResponse is the page of daily effectiveness of a validator, latest day first
+type ratedResponse struct {
+	Data []struct {
+		ValidatorEffectiveness float64 `json:"validatorEffectiveness"`
+	} `json:"data"`
+}
+
+// ratedEffectiveness looks up each validator's effectiveness of its latest rated day
+func (c *Client) ratedEffectiveness(ctx context.Context, indices []models.ValidatorIndex) (Effectiveness, error) {
+	result := make(Effectiveness, len(indices))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var lastErr error
+	sem := make(chan struct{}, maxConcurrentRequests)
+
+	for _, index := range indices {
+		wg.Add(1)
+		go func(index models.ValidatorIndex) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			var resp ratedResponse
+			path := fmt.Sprintf("/v0/eth/validators/%d/effectiveness?size=1&granularity=day", index)
+			err := c.get(ctx, path, &resp)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				lastErr = fmt.Errorf("validator %d: %w", index, err)
+				return
+			}
+			if len(resp.Data) > 0 {
+				result[index] = resp.Data[0].ValidatorEffectiveness
+			}
+		}(index)
+	}
+	wg.Wait()
+
+	if len(result) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+	return result, nil
+}
+
+// beaconchainResponse is the attestation efficiency of a batch of validators, where 1 is perfect
+// and higher is worse
+type beaconchainResponse struct {
+	Status string `json:"status"`
+	Data   []struct {
+		AttestationEfficiency float64 `json:"attestation_efficiency"`
+		ValidatorIndex        uint64  `json:"validatorindex"`
+	} `json:"data"`
+}
+
+// beaconchainEffectiveness looks up the validators in batches and turns their attestation
+// efficiency into effectiveness the way beaconcha.in displays it
+func (c *Client) beaconchainEffectiveness(ctx context.Context, indices []models.ValidatorIndex) (Effectiveness, error) {
+	result := make(Effectiveness, len(indices))
+	var lastErr error
+
+	for start := 0; start < len(indices); start += beaconchainBatchSize {
+		batch := indices[start:min(start+beaconchainBatchSize, len(indices))]
+		ids := make([]string, len(batch))
+		for i, index := range batch {
+			ids[i] = strconv.FormatUint(uint64(index), 10)
+		}
+
+		var resp beaconchainResponse
+		if err := c.get(ctx, "/api/v1/validator/"+strings.Join(ids, ",")+"/attestationefficiency", &resp); err != nil {
+			lastErr = err
+			continue
+		}
+		if resp.Status != "OK" {
+			lastErr = fmt.Errorf("status %q", resp.Status)
+			continue
+		}
+		for _, v := range resp.Data {
+			if v.AttestationEfficiency > 0 {
+				result[models.ValidatorIndex(v.ValidatorIndex)] = 100 / v.AttestationEfficiency
+			}
+		}
+	}
+
+	if len(result) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+	return result, nil
+}
+
+// get requests a path of the provider's API and decodes the JSON answer
+func (c *Client) get(ctx context.Context, path string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.apiKey != "" {
+		switch c.provider {
+		case ProviderBeaconchain:
+			req.Header.Set("apikey", c.apiKey)
+		default:
+			req.Header.Set("Authorization", "Bearer "+c.apiKey)
+		}
+	}
+	if c.provider == ProviderRated && c.network != "" {
+		req.Header.Set("X-Rated-Network", c.network)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,85 @@
+package ratings
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+func TestRatedEffectiveness(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" || r.Header.Get("X-Rated-Network") != "hoodi" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/v0/eth/validators/1/effectiveness":
+			fmt.Fprint(w, `{"data":[{"day":700,"validatorEffectiveness":97.5},{"day":699,"validatorEffectiveness":90}]}`)
+		case "/v0/eth/validators/2/effectiveness":
+			fmt.Fprint(w, `{"data":[]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(models.ExternalRatings{Provider: ProviderRated, URL: server.URL + "/", APIKey: "secret"}, "hoodi", time.Second)
+	ratings, err := client.Effectiveness(context.Background(), []models.ValidatorIndex{1, 2, 3})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(ratings) != 1 || ratings[1] != 97.5 {
+		t.Errorf("Expected only validator 1 rated 97.5 on its latest day, got %v", ratings)
+	}
+
+	// Nothing answered at all is an error
+	client = NewClient(models.ExternalRatings{Provider: ProviderRated, URL: server.URL, APIKey: "wrong"}, "hoodi", time.Second)
+	if _, err := client.Effectiveness(context.Background(), []models.ValidatorIndex{1}); err == nil {
+		t.Error("Expected an error when every lookup fails")
+	}
+}
+
+func TestBeaconchainEffectiveness(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("apikey") != "secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		ids, ok := strings.CutPrefix(r.URL.Path, "/api/v1/validator/")
+		if !ok || !strings.HasSuffix(ids, "/attestationefficiency") {
+			http.NotFound(w, r)
+			return
+		}
+		if strings.HasPrefix(ids, "0,") {
+			fmt.Fprint(w, `{"status":"OK","data":[{"attestation_efficiency":1,"validatorindex":0},{"attestation_efficiency":1.25,"validatorindex":1}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"status":"OK","data":[]}`)
+	}))
+	defer server.Close()
+
+	indices := make([]models.ValidatorIndex, beaconchainBatchSize+1)
+	for i := range indices {
+		indices[i] = models.ValidatorIndex(i)
+	}
+	client := NewClient(models.ExternalRatings{Provider: ProviderBeaconchain, URL: server.URL, APIKey: "secret"}, "mainnet", time.Second)
+	ratings, err := client.Effectiveness(context.Background(), indices)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 batched requests, got %d", requests)
+	}
+	if len(ratings) != 2 || ratings[0] != 100 || math.Abs(ratings[1]-80) > 1e-9 {
+		t.Errorf("Expected efficiency 1 and 1.25 as 100%% and 80%%, got %v", ratings)
+	}
+}
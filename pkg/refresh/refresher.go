@@ -0,0 +1,106 @@
+package refresh
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrSkipped is returned by a fetch that chose not to run; the previous value and error are kept
+var ErrSkipped = errors.New("refresh skipped")
+
+// Refresher periodically fetches a value in the background and caches the last good result
+// Readers never block on the fetch, so a slow upstream can't delay slot processing
+type Refresher[T any] struct {
+	name     string
+	interval time.Duration
+	fetch    func(ctx context.Context) (T, error)
+	logger   *logrus.Logger
+
+	mu      sync.RWMutex
+	value   T
+	updated time.Time
+	lastErr error
+}
+
+// New creates a refresher that calls fetch every interval once started
+func New[T any](name string, interval time.Duration, fetch func(ctx context.Context) (T, error), logger *logrus.Logger) *Refresher[T] {
+	return &Refresher[T]{
+		name:     name,
+		interval: interval,
+		fetch:    fetch,
+		logger:   logger,
+	}
+}
+
+// Start fetches every interval until ctx is cancelled, starting immediately
+// unless a value was already fetched with Refresh
+func (r *Refresher[T]) Start(ctx context.Context) {
+	_, _, fetched := r.Value()
+
+	go func() {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		if fetched {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+
+		for {
+			r.Refresh(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Refresh fetches once, keeping the previous value if the fetch fails
+// Each fetch is bounded by the refresh interval
+func (r *Refresher[T]) Refresh(ctx context.Context) {
+	fetchCtx, cancel := context.WithTimeout(ctx, r.interval)
+	defer cancel()
+
+	value, err := r.fetch(fetchCtx)
+	if errors.Is(err, ErrSkipped) {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastErr = err
+	if err != nil {
+		r.logger.WithError(err).WithField("refresher", r.name).Debug("Background refresh failed - keeping previous value")
+		return
+	}
+	r.value = value
+	r.updated = time.Now()
+}
+
+// Value returns the last successfully fetched value and when it was fetched
+// ok is false until the first successful fetch
+func (r *Refresher[T]) Value() (value T, updated time.Time, ok bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.value, r.updated, !r.updated.IsZero()
+}
+
+// Err returns the error of the most recent fetch, if any
+func (r *Refresher[T]) Err() error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.lastErr
+}
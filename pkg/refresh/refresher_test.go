@@ -0,0 +1,108 @@
+package refresh
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestRefresherKeepsLastGoodValue(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	calls := 0
+	r := New("price", time.Minute, func(ctx context.Context) (float64, error) {
+		calls++
+		if calls == 2 {
+			return 0, errors.New("upstream down")
+		}
+		return float64(calls) * 1000, nil
+	}, logger)
+
+	if _, _, ok := r.Value(); ok {
+		t.Fatal("Expected no value before the first fetch")
+	}
+
+	r.Refresh(context.Background())
+	if value, _, ok := r.Value(); !ok || value != 1000 {
+		t.Fatalf("Expected 1000, got %v (ok=%v)", value, ok)
+	}
+
+	r.Refresh(context.Background())
+	if value, _, _ := r.Value(); value != 1000 {
+		t.Errorf("Expected previous value to survive a failed fetch, got %v", value)
+	}
+	if r.Err() == nil {
+		t.Error("Expected the failed fetch to be reported")
+	}
+
+	r.Refresh(context.Background())
+	if value, _, _ := r.Value(); value != 3000 {
+		t.Errorf("Expected 3000, got %v", value)
+	}
+	if r.Err() != nil {
+		t.Errorf("Expected error to clear after a successful fetch, got %v", r.Err())
+	}
+}
+
+func TestRefresherStart(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	fetched := make(chan struct{}, 1)
+	r := New("queues", time.Hour, func(ctx context.Context) (int, error) {
+		select {
+		case fetched <- struct{}{}:
+		default:
+		}
+		return 42, nil
+	}, logger)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r.Start(ctx)
+
+	select {
+	case <-fetched:
+	case <-time.After(time.Second):
+		t.Fatal("Expected an immediate fetch on start")
+	}
+
+	// The value is stored right after fetch returns
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if value, _, ok := r.Value(); ok && value == 42 {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Error("Expected value 42 after start")
+}
+
+func TestRefresherSkipped(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	skip := false
+	r := New("price", time.Minute, func(ctx context.Context) (float64, error) {
+		if skip {
+			return 0, ErrSkipped
+		}
+		return 1000, nil
+	}, logger)
+
+	r.Refresh(context.Background())
+	_, updated, _ := r.Value()
+
+	skip = true
+	r.Refresh(context.Background())
+	if value, at, ok := r.Value(); !ok || value != 1000 || !at.Equal(updated) {
+		t.Errorf("Expected a skipped fetch to keep the value and its time, got %v at %v", value, at)
+	}
+	if r.Err() != nil {
+		t.Errorf("Expected a skipped fetch not to be reported as an error, got %v", r.Err())
+	}
+}
@@ -0,0 +1,297 @@
+package relay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// registrationPath is the relay data API endpoint returning a validator's latest registration
+const registrationPath = "/relay/v1/data/validator_registration"
+
+// payloadDeliveredPath is the relay data API endpoint listing the payloads delivered to proposers
+const payloadDeliveredPath = "/relay/v1/data/bidtraces/proposer_payload_delivered"
+
+// maxConcurrentRequests bounds the lookups in flight per relay
+const maxConcurrentRequests = 8
+
+// Registrations records which pubkeys each relay has a validator registration for (relay -> pubkey -> registered)
+// A relay only has entries for the pubkeys it answered for; failed lookups are unknown
+type Registrations map[string]map[string]bool
+
+// Registered returns the number of pubkeys registered with a relay
+func (r Registrations) Registered(relay string) int {
+	count := 0
+	for _, registered := range r[relay] {
+		if registered {
+			count++
+		}
+	}
+	return count
+}
+
+// Unregistered returns the pubkeys every relay answered for without a registration
+// A pubkey a relay couldn't be asked about is not reported
+func (r Registrations) Unregistered(pubkeys []string) []string {
+	if len(r) == 0 {
+		return nil
+	}
+
+	var missing []string
+	for _, pubkey := range pubkeys {
+		unregistered := true
+		for _, byPubkey := range r {
+			if registered, known := byPubkey[pubkey]; !known || registered {
+				unregistered = false
+				break
+			}
+		}
+		if unregistered {
+			missing = append(missing, pubkey)
+		}
+	}
+	return missing
+}
+
+// endpoint is a relay's data API base URL
+type endpoint struct {
+	name    string
+	baseURL string
+}
+
+// Client looks up validator registrations and delivered payloads on MEV-Boost relays
+type Client struct {
+	relays []endpoint
+	client *http.Client
+}
+
+// NewClient creates a client for the configured relays
+func NewClient(relays []models.MEVRelay, timeout time.Duration) *Client {
+	endpoints := make([]endpoint, len(relays))
+	for i, relay := range relays {
+		endpoints[i] = endpoint{name: Name(relay), baseURL: baseURL(relay.URL)}
+	}
+
+	return &Client{
+		relays: endpoints,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Name returns the relay's configured name, or its host if none is set
+func Name(relay models.MEVRelay) string {
+	if relay.Name != "" {
+		return relay.Name
+	}
+	if u, err := url.Parse(relay.URL); err == nil && u.Host != "" {
+		return u.Hostname()
+	}
+	return relay.URL
+}
+
+// baseURL strips the relay pubkey that MEV-Boost relay URLs carry as user info
+func baseURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return strings.TrimRight(raw, "/")
+	}
+	u.User = nil
+	return strings.TrimRight(u.String(), "/")
+}
+
+// Registrations looks up every pubkey on every relay
+// It only fails if no relay answered at all
+func (c *Client) Registrations(ctx context.Context, pubkeys []string) (Registrations, error) {
+	result := make(Registrations, len(c.relays))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var lastErr error
+	answered := 0
+
+	for _, relay := range c.relays {
+		wg.Add(1)
+		go func(relay endpoint) {
+			defer wg.Done()
+
+			registered, err := c.relayRegistrations(ctx, relay, pubkeys)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				lastErr = fmt.Errorf("relay %s: %w", relay.name, err)
+			}
+			if len(registered) > 0 {
+				answered++
+			}
+			result[relay.name] = registered
+		}(relay)
+	}
+	wg.Wait()
+
+	if answered == 0 && len(pubkeys) > 0 {
+		return nil, lastErr
+	}
+	return result, nil
+}
+
+// relayRegistrations looks up the pubkeys on one relay, returning the answers it got and the last lookup error
+func (c *Client) relayRegistrations(ctx context.Context, relay endpoint, pubkeys []string) (map[string]bool, error) {
+	registered := make(map[string]bool, len(pubkeys))
+	var mu sync.Mutex
+	var lastErr error
+
+	sem := make(chan struct{}, maxConcurrentRequests)
+	var wg sync.WaitGroup
+	for _, pubkey := range pubkeys {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return registered, ctx.Err()
+		}
+
+		wg.Add(1)
+		go func(pubkey string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			ok, err := c.isRegistered(ctx, relay, pubkey)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				lastErr = err
+				return
+			}
+			registered[pubkey] = ok
+		}(pubkey)
+	}
+	wg.Wait()
+
+	return registered, lastErr
+}
+
+// registrationResponse is the part of a signed validator registration that is checked
+type registrationResponse struct {
+	Message struct {
+		Pubkey string `json:"pubkey"`
+	} `json:"message"`
+}
+
+// isRegistered asks a relay for a pubkey's registration
+// Relays answer 400 or 404 for validators that never registered
+func (c *Client) isRegistered(ctx context.Context, relay endpoint, pubkey string) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, relay.baseURL+registrationPath+"?pubkey="+pubkey, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusBadRequest, http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var result registrationResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return false, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return strings.EqualFold(result.Message.Pubkey, pubkey), nil
+}
+
+// Payload is an execution payload a relay delivered to a proposer
+type Payload struct {
+	Relay        string
+	Value        *big.Int // Paid to the proposer's fee recipient, in wei
+	FeeRecipient string   // The proposer's fee recipient the builder paid; the block's own pays the builder
+}
+
+// bidTrace is the part of a delivered payload's bid trace that is used
+type bidTrace struct {
+	BlockHash            string `json:"block_hash"`
+	Value                string `json:"value"`
+	ProposerFeeRecipient string `json:"proposer_fee_recipient"`
+}
+
+// DeliveredPayload finds the relay that delivered the execution block with the hash at a slot
+// ok is false if no relay delivered it (a locally built block); err is only set if no relay
+// delivered it and at least one couldn't be asked
+func (c *Client) DeliveredPayload(ctx context.Context, slot models.Slot, blockHash string) (payload Payload, ok bool, err error) {
+	type answer struct {
+		payload Payload
+		ok      bool
+		err     error
+	}
+
+	answers := make(chan answer, len(c.relays))
+	for _, relay := range c.relays {
+		go func(relay endpoint) {
+			payload, ok, err := c.deliveredPayload(ctx, relay, slot, blockHash)
+			payload.Relay = relay.name
+			answers <- answer{payload: payload, ok: ok, err: err}
+		}(relay)
+	}
+
+	var lastErr error
+	for range c.relays {
+		a := <-answers
+		if a.ok {
+			return a.payload, true, nil
+		}
+		if a.err != nil {
+			lastErr = fmt.Errorf("relay %s: %w", a.payload.Relay, a.err)
+		}
+	}
+	return Payload{}, false, lastErr
+}
+
+// deliveredPayload asks one relay whether it delivered the block, for how much and to whom
+func (c *Client) deliveredPayload(ctx context.Context, relay endpoint, slot models.Slot, blockHash string) (Payload, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s%s?slot=%d", relay.baseURL, payloadDeliveredPath, slot), nil)
+	if err != nil {
+		return Payload{}, false, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return Payload{}, false, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Payload{}, false, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var traces []bidTrace
+	if err := json.NewDecoder(resp.Body).Decode(&traces); err != nil {
+		return Payload{}, false, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	for _, trace := range traces {
+		if !strings.EqualFold(trace.BlockHash, blockHash) {
+			continue
+		}
+		value, ok := new(big.Int).SetString(trace.Value, 10)
+		if !ok {
+			return Payload{}, false, fmt.Errorf("invalid payload value %q", trace.Value)
+		}
+		return Payload{Value: value, FeeRecipient: trace.ProposerFeeRecipient}, true, nil
+	}
+	return Payload{}, false, nil
+}
@@ -0,0 +1,116 @@
+package relay
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// fakeRelay serves registrations for the given pubkeys and 400 for everything else
+func fakeRelay(t *testing.T, registered ...string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != registrationPath {
+			http.NotFound(w, r)
+			return
+		}
+		pubkey := r.URL.Query().Get("pubkey")
+		for _, key := range registered {
+			if key == pubkey {
+				fmt.Fprintf(w, `{"message":{"fee_recipient":"0x00","gas_limit":"30000000","timestamp":"1","pubkey":"%s"},"signature":"0x00"}`, pubkey)
+				return
+			}
+		}
+		http.Error(w, `{"code":400,"message":"no registration found for validator"}`, http.StatusBadRequest)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRegistrations(t *testing.T) {
+	a, b, c := "0xaaa", "0xbbb", "0xccc"
+	flashbots := fakeRelay(t, a)
+	ultrasound := fakeRelay(t, a, b)
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer broken.Close()
+
+	// Relay URLs carry the relay pubkey as user info, which is not sent
+	withPubkey := strings.Replace(flashbots.URL, "http://", "http://0xrelaypubkey@", 1)
+	client := NewClient([]models.MEVRelay{
+		{Name: "flashbots", URL: withPubkey},
+		{Name: "ultrasound", URL: ultrasound.URL + "/"},
+	}, time.Second)
+
+	registrations, err := client.Registrations(context.Background(), []string{a, b, c})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if registrations.Registered("flashbots") != 1 || registrations.Registered("ultrasound") != 2 {
+		t.Errorf("Unexpected registrations: %v", registrations)
+	}
+	if missing := registrations.Unregistered([]string{a, b, c}); len(missing) != 1 || missing[0] != c {
+		t.Errorf("Expected only %s to be missing from all relays, got %v", c, missing)
+	}
+
+	// A relay that can't be asked leaves the pubkeys it would answer for unknown
+	client = NewClient([]models.MEVRelay{{Name: "flashbots", URL: flashbots.URL}, {URL: broken.URL}}, time.Second)
+	registrations, err = client.Registrations(context.Background(), []string{a, c})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if missing := registrations.Unregistered([]string{a, c}); len(missing) != 0 {
+		t.Errorf("Expected no validator reported missing while a relay is down, got %v", missing)
+	}
+
+	// Nothing answered at all: the lookup fails so the previous result is kept
+	client = NewClient([]models.MEVRelay{{URL: broken.URL}}, time.Second)
+	if _, err := client.Registrations(context.Background(), []string{a}); err == nil {
+		t.Error("Expected an error when no relay answered")
+	}
+}
+
+func TestName(t *testing.T) {
+	if name := Name(models.MEVRelay{URL: "https://0xabc@boost-relay.flashbots.net"}); name != "boost-relay.flashbots.net" {
+		t.Errorf("Expected the URL host as default name, got %q", name)
+	}
+	if name := Name(models.MEVRelay{Name: "flashbots", URL: "https://boost-relay.flashbots.net"}); name != "flashbots" {
+		t.Errorf("Expected the configured name, got %q", name)
+	}
+}
+
+func TestDeliveredPayload(t *testing.T) {
+	delivering := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != payloadDeliveredPath || r.URL.Query().Get("slot") != "100" {
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+		w.Write([]byte(`[{"slot":"100","block_hash":"0xAB","value":"123456789012345678901","proposer_fee_recipient":"0xfee"}]`))
+	}))
+	defer delivering.Close()
+	empty := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[]`))
+	}))
+	defer empty.Close()
+
+	client := NewClient([]models.MEVRelay{{Name: "empty", URL: empty.URL}, {Name: "builder", URL: delivering.URL}}, time.Second)
+
+	payload, ok, err := client.DeliveredPayload(context.Background(), 100, "0xab")
+	if err != nil || !ok {
+		t.Fatalf("Expected the payload to be found, got ok=%v err=%v", ok, err)
+	}
+	if payload.Relay != "builder" || payload.Value.String() != "123456789012345678901" || payload.FeeRecipient != "0xfee" {
+		t.Errorf("Unexpected payload %s %s %s", payload.Relay, payload.Value, payload.FeeRecipient)
+	}
+
+	// A locally built block isn't known to any relay
+	if _, ok, err := client.DeliveredPayload(context.Background(), 100, "0xcd"); ok || err != nil {
+		t.Errorf("Expected no payload and no error, got ok=%v err=%v", ok, err)
+	}
+}
@@ -0,0 +1,150 @@
+// Package reorg detects chain reorganizations from the block roots of processed slots
+package reorg
+
+import (
+	"sync"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// Window is the range of slots (inclusive) whose blocks a reorg may have changed
+type Window struct {
+	From models.Slot
+	To   models.Slot
+}
+
+// Depth returns the number of slots in the window
+func (w Window) Depth() uint64 {
+	return uint64(w.To-w.From) + 1
+}
+
+// overlaps reports whether two windows share a slot
+func (w Window) overlaps(other Window) bool {
+	return w.From <= other.To && other.From <= w.To
+}
+
+// Tracker keeps the block roots of recently processed slots and detects blocks that don't extend them
+type Tracker struct {
+	mu      sync.Mutex
+	keep    models.Slot // Slots of roots and windows kept behind the latest block
+	roots   map[models.Slot]string
+	slots   map[string]models.Slot
+	latest  models.Slot
+	hasRoot bool
+	windows []Window // Recently reported reorgs, so one reorg seen twice is reported once
+}
+
+// NewTracker creates a tracker keeping the roots of the last keep slots
+func NewTracker(keep uint64) *Tracker {
+	return &Tracker{
+		keep:  models.Slot(keep),
+		roots: make(map[models.Slot]string),
+		slots: make(map[string]models.Slot),
+	}
+}
+
+// SlotOf returns the slot of a recorded block root
+func (t *Tracker) SlotOf(root string) (models.Slot, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	slot, ok := t.slots[root]
+	return slot, ok
+}
+
+// Observe records a processed block and, if its parent replaced a recorded block or orphaned the
+// recorded blocks after it, returns the slots between the common ancestor and the block that the reorg
+// changed. A parent after the latest recorded block is one the tracker never saw (a skipped slot or a
+// late block), which isn't evidence of a reorg
+// parentSlot is the slot of the block's parent root
+func (t *Tracker) Observe(slot models.Slot, root string, parentSlot models.Slot, parentRoot string) (Window, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var window Window
+	reorged := false
+	if t.hasRoot && t.latest < slot && t.roots[t.latest] != parentRoot {
+		recorded, known := t.roots[parentSlot]
+		switch {
+		case known && recorded != parentRoot:
+			// The parent replaced the block recorded in its slot
+			window = Window{From: parentSlot, To: slot - 1}
+			reorged = true
+		case parentSlot < t.latest:
+			// Recorded blocks after the parent were orphaned
+			window = Window{From: parentSlot + 1, To: slot - 1}
+			reorged = true
+		}
+
+		for s := window.From; reorged && s <= window.To; s++ {
+			if old, ok := t.roots[s]; ok {
+				delete(t.slots, old)
+				delete(t.roots, s)
+			}
+		}
+	}
+
+	t.record(parentSlot, parentRoot)
+	t.record(slot, root)
+	t.prune()
+	return window, reorged
+}
+
+// Note remembers a reorg window and reports whether it is new
+// A window overlapping one noted recently is the same reorg seen from another source
+func (t *Tracker) Note(window Window) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for i, noted := range t.windows {
+		if noted.overlaps(window) {
+			if window.From < noted.From {
+				t.windows[i].From = window.From
+			}
+			if window.To > noted.To {
+				t.windows[i].To = window.To
+			}
+			return false
+		}
+	}
+	t.windows = append(t.windows, window)
+	return true
+}
+
+// record sets the root of a slot
+func (t *Tracker) record(slot models.Slot, root string) {
+	if root == "" {
+		return
+	}
+	if old, ok := t.roots[slot]; ok {
+		delete(t.slots, old)
+	}
+	t.roots[slot] = root
+	t.slots[root] = slot
+	if !t.hasRoot || slot > t.latest {
+		t.latest = slot
+		t.hasRoot = true
+	}
+}
+
+// prune drops the roots and windows older than keep slots behind the latest block
+func (t *Tracker) prune() {
+	if t.latest < t.keep {
+		return
+	}
+	oldest := t.latest - t.keep
+
+	for slot, root := range t.roots {
+		if slot < oldest {
+			delete(t.roots, slot)
+			delete(t.slots, root)
+		}
+	}
+	windows := t.windows[:0]
+	for _, window := range t.windows {
+		if window.To >= oldest {
+			windows = append(windows, window)
+		}
+	}
+	t.windows = windows
+}
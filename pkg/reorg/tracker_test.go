@@ -0,0 +1,52 @@
+package reorg
+
+import "testing"
+
+func TestTrackerObserve(t *testing.T) {
+	tracker := NewTracker(64)
+
+	if _, reorged := tracker.Observe(10, "0x10", 9, "0x09"); reorged {
+		t.Fatal("First block reported as reorg")
+	}
+	if _, reorged := tracker.Observe(11, "0x11", 10, "0x10"); reorged {
+		t.Fatal("Block extending the latest one reported as reorg")
+	}
+	// Slot 12 looked empty, 13 extends 11
+	if _, reorged := tracker.Observe(13, "0x13", 11, "0x11"); reorged {
+		t.Fatal("Block after an empty slot reported as reorg")
+	}
+
+	// 15 builds on 11: the block at 13 was orphaned
+	window, reorged := tracker.Observe(15, "0x15", 11, "0x11")
+	if !reorged || window != (Window{From: 12, To: 14}) || window.Depth() != 3 {
+		t.Fatalf("Observe() = %+v, %v, want slots 12-14 reorged", window, reorged)
+	}
+	if _, ok := tracker.SlotOf("0x13"); ok {
+		t.Error("Orphaned root still recorded")
+	}
+
+	// 17 builds on a block at 16 that was never recorded, e.g. a slot the watcher skipped
+	if _, reorged := tracker.Observe(17, "0x17", 16, "0x16"); reorged {
+		t.Fatal("Block whose parent was never recorded reported as reorg")
+	}
+
+	// 19 builds on another block at 17 than the recorded one
+	window, reorged = tracker.Observe(19, "0x19", 17, "0x17b")
+	if !reorged || window != (Window{From: 17, To: 18}) {
+		t.Fatalf("Observe() = %+v, %v, want slots 17-18 reorged", window, reorged)
+	}
+}
+
+func TestTrackerNote(t *testing.T) {
+	tracker := NewTracker(64)
+
+	if !tracker.Note(Window{From: 12, To: 14}) {
+		t.Error("First window not new")
+	}
+	if tracker.Note(Window{From: 13, To: 15}) {
+		t.Error("Overlapping window reported as a new reorg")
+	}
+	if !tracker.Note(Window{From: 20, To: 20}) {
+		t.Error("Separate window not new")
+	}
+}
@@ -0,0 +1,121 @@
+package rules
+
+import (
+	"sort"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/metrics"
+)
+
+// counters is the part of a label's metrics rules are evaluated on
+type counters struct {
+	validators      float64
+	slashed         float64
+	maxConsecutive  float64
+	duties          uint64
+	dutiesSuccess   uint64
+	livenessMisses  uint64
+	proposedBlocks  uint64
+	missedBlocks    uint64
+	idealRewards    uint64
+	rewards         int64
+	inclusionSum    uint64
+	inclusionCount  uint64
+	suboptimalHeads uint64
+}
+
+// newCounters copies the evaluated fields of a label's metrics
+func newCounters(m *metrics.MetricsByLabel) counters {
+	return counters{
+		validators:      float64(m.ValidatorCount),
+		slashed:         float64(m.SlashedCount),
+		maxConsecutive:  float64(m.MaxConsecutiveMissed),
+		duties:          m.AttestationDuties,
+		dutiesSuccess:   m.AttestationDutiesSuccess,
+		livenessMisses:  m.MissedAttestations,
+		proposedBlocks:  m.ProposedBlocks,
+		missedBlocks:    m.MissedBlocks,
+		idealRewards:    uint64(m.IdealConsensusRewards),
+		rewards:         int64(m.ConsensusRewards),
+		inclusionSum:    m.InclusionDelaySum,
+		inclusionCount:  m.InclusionDelayCount,
+		suboptimalHeads: m.SuboptimalHeadVotes,
+	}
+}
+
+// epochDelta is what a label's counters gained over an epoch, false if any went backwards
+// (validators left the label), which leaves nothing to compare
+// Rewards are those of a single epoch already, so they are kept as they are
+func epochDelta(cur, prev counters) (counters, bool) {
+	if cur.duties < prev.duties || cur.dutiesSuccess < prev.dutiesSuccess ||
+		cur.livenessMisses < prev.livenessMisses || cur.proposedBlocks < prev.proposedBlocks ||
+		cur.missedBlocks < prev.missedBlocks || cur.inclusionSum < prev.inclusionSum ||
+		cur.inclusionCount < prev.inclusionCount || cur.suboptimalHeads < prev.suboptimalHeads {
+		return counters{}, false
+	}
+
+	delta := cur
+	delta.duties -= prev.duties
+	delta.dutiesSuccess -= prev.dutiesSuccess
+	delta.livenessMisses -= prev.livenessMisses
+	delta.proposedBlocks -= prev.proposedBlocks
+	delta.missedBlocks -= prev.missedBlocks
+	delta.inclusionSum -= prev.inclusionSum
+	delta.inclusionCount -= prev.inclusionCount
+	delta.suboptimalHeads -= prev.suboptimalHeads
+	return delta, true
+}
+
+// metric computes a rule's value from a label's epoch, false when the epoch has no data for it
+// Gauges read the label's current state; counters and rates cover the settled epoch
+type metric func(epoch counters) (float64, bool)
+
+// percent returns part as a percentage of total, false without a total
+func percent(part, total float64) (float64, bool) {
+	if total <= 0 {
+		return 0, false
+	}
+	return part * 100 / total, true
+}
+
+// ruleMetrics are the metrics rules can compare, by name
+var ruleMetrics = map[string]metric{
+	"validators":             func(e counters) (float64, bool) { return e.validators, true },
+	"slashed":                func(e counters) (float64, bool) { return e.slashed, true },
+	"max_consecutive_missed": func(e counters) (float64, bool) { return e.maxConsecutive, true },
+	"missed_attestations": func(e counters) (float64, bool) {
+		return float64(e.duties - e.dutiesSuccess), e.duties > 0
+	},
+	"miss_rate": func(e counters) (float64, bool) {
+		return percent(float64(e.duties-e.dutiesSuccess), float64(e.duties))
+	},
+	"attestation_rate": func(e counters) (float64, bool) {
+		return percent(float64(e.dutiesSuccess), float64(e.duties))
+	},
+	"offline_rate": func(e counters) (float64, bool) {
+		return percent(float64(e.livenessMisses), e.validators)
+	},
+	"head_miss_rate": func(e counters) (float64, bool) {
+		return percent(float64(e.suboptimalHeads), float64(e.dutiesSuccess))
+	},
+	"missed_blocks":   func(e counters) (float64, bool) { return float64(e.missedBlocks), true },
+	"proposed_blocks": func(e counters) (float64, bool) { return float64(e.proposedBlocks), true },
+	"rewards_rate": func(e counters) (float64, bool) {
+		return percent(float64(e.rewards), float64(e.idealRewards))
+	},
+	"inclusion_delay_avg": func(e counters) (float64, bool) {
+		if e.inclusionCount == 0 {
+			return 0, false
+		}
+		return float64(e.inclusionSum) / float64(e.inclusionCount), true
+	},
+}
+
+// MetricNames returns the names of the metrics rules can compare, sorted
+func MetricNames() []string {
+	names := make([]string, 0, len(ruleMetrics))
+	for name := range ruleMetrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
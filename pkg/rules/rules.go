@@ -0,0 +1,235 @@
+package rules
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/alert"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/metrics"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// DefaultCooldown is the minimum time between two alerts of a rule for the same label
+const DefaultCooldown = time.Hour
+
+// networkLabel is only evaluated by rules naming it
+const networkLabel = "scope:all-network"
+
+// cohortPrefix starts the labels of comparison cohorts, also only evaluated by rules naming them
+const cohortPrefix = "cohort:"
+
+// comparisons are the supported comparison operators
+var comparisons = map[string]func(value, threshold float64) bool{
+	">":  func(v, t float64) bool { return v > t },
+	">=": func(v, t float64) bool { return v >= t },
+	"<":  func(v, t float64) bool { return v < t },
+	"<=": func(v, t float64) bool { return v <= t },
+	"==": func(v, t float64) bool { return v == t },
+	"!=": func(v, t float64) bool { return v != t },
+}
+
+// Rule alerts when a label's metric compares true against a threshold for Duration epochs in a row
+type Rule struct {
+	Name       string
+	Metric     string
+	Comparison string
+	Threshold  float64
+	Duration   int      // Consecutive epochs the condition must hold
+	Labels     []string // Labels evaluated; a trailing * matches a prefix
+	Channels   []string // Alert channels, every channel if empty
+	Severity   alert.Severity
+	Cooldown   time.Duration
+}
+
+// FromConfig validates a configured rule and fills in its defaults
+func FromConfig(cfg models.AlertRule) (Rule, error) {
+	rule := Rule{
+		Name:       cfg.Name,
+		Metric:     cfg.Metric,
+		Comparison: cfg.Comparison,
+		Threshold:  cfg.Threshold,
+		Duration:   cfg.Duration,
+		Labels:     cfg.Labels,
+		Channels:   cfg.Channels,
+		Severity:   alert.SeverityWarning,
+		Cooldown:   cfg.Cooldown.ToDuration(),
+	}
+
+	if rule.Name == "" {
+		return Rule{}, fmt.Errorf("rule name is required")
+	}
+	if _, ok := ruleMetrics[rule.Metric]; !ok {
+		return Rule{}, fmt.Errorf("rule %s: unknown metric %q (%s)", rule.Name, rule.Metric, strings.Join(MetricNames(), ", "))
+	}
+	if _, ok := comparisons[rule.Comparison]; !ok {
+		return Rule{}, fmt.Errorf("rule %s: unknown comparison %q (>, >=, <, <=, == or !=)", rule.Name, rule.Comparison)
+	}
+	if rule.Duration < 0 {
+		return Rule{}, fmt.Errorf("rule %s: duration must not be negative", rule.Name)
+	}
+	if rule.Duration == 0 {
+		rule.Duration = 1
+	}
+	if rule.Cooldown < 0 {
+		return Rule{}, fmt.Errorf("rule %s: cooldown must not be negative", rule.Name)
+	}
+	if rule.Cooldown == 0 {
+		rule.Cooldown = DefaultCooldown
+	}
+	if cfg.Severity != "" {
+		severity, err := alert.ParseSeverity(cfg.Severity)
+		if err != nil {
+			return Rule{}, fmt.Errorf("rule %s: %w", rule.Name, err)
+		}
+		rule.Severity = severity
+	}
+	for _, channel := range rule.Channels {
+		switch channel {
+		case "slack", "discord", "telegram", "pagerduty":
+		default:
+			return Rule{}, fmt.Errorf("rule %s: unknown channel %q (slack, discord, telegram or pagerduty)", rule.Name, channel)
+		}
+	}
+	return rule, nil
+}
+
+// matches reports whether the rule evaluates a label
+// Without labels, a rule evaluates every label but the network-wide scope
+func (r *Rule) matches(label string) bool {
+	if len(r.Labels) == 0 {
+		return label != networkLabel && !strings.HasPrefix(label, cohortPrefix)
+	}
+	for _, pattern := range r.Labels {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(label, prefix) {
+				return true
+			}
+		} else if label == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// Firing is a rule whose condition held long enough on a label
+type Firing struct {
+	Rule   Rule
+	Label  string
+	Value  float64 // Metric value in the last epoch
+	Epochs int     // Consecutive epochs the condition held
+}
+
+// state is a rule's progress on one label
+type state struct {
+	streak int
+	fired  time.Time
+}
+
+// Engine evaluates the rules once per epoch against the per-label metrics
+type Engine struct {
+	rules []Rule
+
+	mu        sync.Mutex
+	epoch     models.Epoch
+	evaluated bool
+	rebased   bool                // Whether the counters were zeroed since the previous evaluation
+	previous  map[string]counters // Label counters at the previous evaluation
+	states    map[string]*state   // By rule name and label
+}
+
+// NewEngine creates an engine for validated rules
+func NewEngine(rules []Rule) *Engine {
+	return &Engine{
+		rules:    rules,
+		previous: make(map[string]counters),
+		states:   make(map[string]*state),
+	}
+}
+
+// Rebase tells the engine the counters were zeroed, so the next evaluation takes them as gained
+// from zero instead of comparing them to the previous ones
+func (e *Engine) Rebase() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.rebased = true
+}
+
+// Evaluate compares the rules against what each label gained since the previous settled epoch and
+// returns those that fire, ordered by rule and label
+// It only evaluates once per epoch; the first call sets the baseline and never fires, unless the
+// counters were rebased before it
+func (e *Engine) Evaluate(epoch models.Epoch, byLabel map[string]*metrics.MetricsByLabel, now time.Time) []Firing {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.evaluated && epoch <= e.epoch {
+		return nil
+	}
+	baseline := !e.evaluated && !e.rebased
+	rebased := e.rebased
+	e.epoch, e.evaluated, e.rebased = epoch, true, false
+
+	current := make(map[string]counters, len(byLabel))
+	for label, m := range byLabel {
+		current[label] = newCounters(m)
+	}
+	previous := e.previous
+	e.previous = current
+	if baseline {
+		return nil
+	}
+
+	labels := make([]string, 0, len(current))
+	for label := range current {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	var firings []Firing
+	for _, rule := range e.rules {
+		for _, label := range labels {
+			if !rule.matches(label) {
+				continue
+			}
+			key := rule.Name + "\x00" + label
+			st := e.states[key]
+			if st == nil {
+				st = &state{}
+				e.states[key] = st
+			}
+
+			var prev counters
+			if !rebased {
+				var known bool
+				if prev, known = previous[label]; !known {
+					continue
+				}
+			}
+			delta, ok := epochDelta(current[label], prev)
+			if !ok {
+				continue
+			}
+			// Epochs without data neither extend nor break a streak
+			value, ok := ruleMetrics[rule.Metric](delta)
+			if !ok {
+				continue
+			}
+			if !comparisons[rule.Comparison](value, rule.Threshold) {
+				st.streak = 0
+				continue
+			}
+
+			st.streak++
+			if st.streak < rule.Duration || (!st.fired.IsZero() && now.Sub(st.fired) < rule.Cooldown) {
+				continue
+			}
+			st.fired = now
+			firings = append(firings, Firing{Rule: rule, Label: label, Value: value, Epochs: st.streak})
+		}
+	}
+	return firings
+}
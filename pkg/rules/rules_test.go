@@ -0,0 +1,115 @@
+package rules
+
+import (
+	"testing"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/metrics"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+func TestFromConfig(t *testing.T) {
+	rule, err := FromConfig(models.AlertRule{Name: "misses", Metric: "miss_rate", Comparison: ">", Threshold: 2})
+	if err != nil {
+		t.Fatalf("FromConfig() error = %v", err)
+	}
+	if rule.Duration != 1 || rule.Cooldown != DefaultCooldown || rule.Severity != "warning" {
+		t.Errorf("Defaults not applied: %+v", rule)
+	}
+
+	invalid := []models.AlertRule{
+		{Metric: "miss_rate", Comparison: ">"},
+		{Name: "a", Metric: "nope", Comparison: ">"},
+		{Name: "a", Metric: "miss_rate", Comparison: "=>"},
+		{Name: "a", Metric: "miss_rate", Comparison: ">", Severity: "page"},
+		{Name: "a", Metric: "miss_rate", Comparison: ">", Channels: []string{"email"}},
+	}
+	for _, cfg := range invalid {
+		if _, err := FromConfig(cfg); err == nil {
+			t.Errorf("FromConfig(%+v) accepted an invalid rule", cfg)
+		}
+	}
+}
+
+func TestEngineDurationAndCooldown(t *testing.T) {
+	rule, err := FromConfig(models.AlertRule{
+		Name: "misses", Metric: "miss_rate", Comparison: ">", Threshold: 2,
+		Duration: 2, Labels: []string{"operator:*"}, Cooldown: models.Duration(time.Hour),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine := NewEngine([]Rule{rule})
+
+	// Cumulative counters: operator:a misses 5 of 100 duties per epoch, operator:b none
+	var duties, success uint64
+	now := time.Unix(0, 0)
+	evaluate := func(epoch models.Epoch, missed uint64) []Firing {
+		duties += 100
+		success += 100 - missed
+		return engine.Evaluate(epoch, map[string]*metrics.MetricsByLabel{
+			"operator:a":        {Label: "operator:a", AttestationDuties: duties, AttestationDutiesSuccess: success},
+			"operator:b":        {Label: "operator:b", AttestationDuties: duties, AttestationDutiesSuccess: duties},
+			"scope:all-network": {Label: "scope:all-network", AttestationDuties: duties},
+		}, now)
+	}
+
+	if got := evaluate(9, 5); got != nil {
+		t.Errorf("Baseline fired: %+v", got)
+	}
+	if got := evaluate(10, 5); got != nil {
+		t.Errorf("Fired before the duration: %+v", got)
+	}
+	if got := evaluate(10, 5); got != nil {
+		t.Errorf("Evaluated an epoch twice: %+v", got)
+	}
+	got := evaluate(11, 5)
+	if len(got) != 1 || got[0].Label != "operator:a" || got[0].Value != 5 || got[0].Epochs != 2 {
+		t.Fatalf("Evaluate() = %+v, want operator:a at 5%% for 2 epochs", got)
+	}
+
+	now = now.Add(30 * time.Minute)
+	if got := evaluate(12, 5); got != nil {
+		t.Errorf("Fired during the cooldown: %+v", got)
+	}
+	now = now.Add(time.Hour)
+	if got := evaluate(13, 0); got != nil {
+		t.Errorf("Fired after recovering: %+v", got)
+	}
+	if got := evaluate(14, 5); got != nil {
+		t.Errorf("Fired before the streak was rebuilt: %+v", got)
+	}
+	if got := evaluate(15, 5); len(got) != 1 {
+		t.Errorf("Expected a firing after the cooldown, got %+v", got)
+	}
+}
+
+func TestEngineCountsEachEpoch(t *testing.T) {
+	rule, err := FromConfig(models.AlertRule{Name: "missed", Metric: "missed_blocks", Comparison: ">", Threshold: 0, Duration: 1, Cooldown: models.Duration(time.Minute)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine := NewEngine([]Rule{rule})
+	evaluate := func(epoch models.Epoch, missed uint64) []Firing {
+		return engine.Evaluate(epoch, map[string]*metrics.MetricsByLabel{
+			"operator:a": {Label: "operator:a", MissedBlocks: missed},
+		}, time.Unix(int64(epoch)*384, 0))
+	}
+
+	// Counters zeroed before the first evaluation count from zero, so it isn't a baseline
+	engine.Rebase()
+	if got := evaluate(10, 1); len(got) != 1 {
+		t.Errorf("Expected the missed block of the first epoch to fire, got %+v", got)
+	}
+	// A miss is counted in its epoch only, though the counters keep it until they reset
+	if got := evaluate(11, 1); got != nil {
+		t.Errorf("Fired again for the same miss: %+v", got)
+	}
+	if got := evaluate(12, 2); len(got) != 1 || got[0].Value != 1 {
+		t.Errorf("Expected a firing for the new miss, got %+v", got)
+	}
+	engine.Rebase()
+	if got := evaluate(13, 0); got != nil {
+		t.Errorf("Fired after the counters reset: %+v", got)
+	}
+}
@@ -0,0 +1,123 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/tracing"
+)
+
+// Stage is a unit of work in a pipeline, started as soon as the stages it depends on completed
+type Stage struct {
+	Name string
+	// After lists the stages that must complete first; the stage is skipped if one of them doesn't
+	After []string
+	// Budget caps the stage's runtime; 0 means it isn't bounded
+	Budget time.Duration
+	Run    func(ctx context.Context) error
+}
+
+// RunPipeline runs stages concurrently, each once its dependencies completed, and returns their
+// results in the order given
+// Unknown dependencies and cycles are rejected before any stage runs
+func RunPipeline(ctx context.Context, stages []Stage) ([]TaskResult, error) {
+	index := make(map[string]int, len(stages))
+	for i, stage := range stages {
+		if _, ok := index[stage.Name]; ok {
+			return nil, fmt.Errorf("duplicate stage %q", stage.Name)
+		}
+		index[stage.Name] = i
+	}
+	for _, stage := range stages {
+		for _, dep := range stage.After {
+			if _, ok := index[dep]; !ok {
+				return nil, fmt.Errorf("stage %q depends on unknown stage %q", stage.Name, dep)
+			}
+		}
+	}
+	if err := checkAcyclic(stages, index); err != nil {
+		return nil, err
+	}
+
+	results := make([]TaskResult, len(stages))
+	done := make([]chan struct{}, len(stages))
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+
+	var wg sync.WaitGroup
+	for i, stage := range stages {
+		wg.Add(1)
+		go func(i int, stage Stage) {
+			defer wg.Done()
+			defer close(done[i])
+
+			for _, dep := range stage.After {
+				j := index[dep]
+				<-done[j]
+				if results[j].Outcome != OutcomeCompleted {
+					results[i] = TaskResult{Name: stage.Name, Outcome: OutcomeSkipped, Err: fmt.Errorf("%s did not complete", dep)}
+					return
+				}
+			}
+			results[i] = runStage(ctx, stage)
+		}(i, stage)
+	}
+	wg.Wait()
+
+	return results, nil
+}
+
+// runStage executes a stage within its budget
+func runStage(ctx context.Context, stage Stage) TaskResult {
+	start := time.Now()
+
+	stageCtx, cancel := ctx, context.CancelFunc(func() {})
+	if stage.Budget > 0 {
+		stageCtx, cancel = context.WithTimeout(ctx, stage.Budget)
+	}
+	defer cancel()
+
+	stageCtx, span := tracing.Start(stageCtx, "stage "+stage.Name)
+	err := stage.Run(stageCtx)
+	tracing.End(span, err)
+
+	return TaskResult{Name: stage.Name, Outcome: outcomeOf(err), Duration: time.Since(start), Err: err}
+}
+
+// checkAcyclic rejects stages that depend on themselves, directly or not
+func checkAcyclic(stages []Stage, index map[string]int) error {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(stages))
+
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case visiting:
+			return fmt.Errorf("stage %q depends on itself", stages[i].Name)
+		case visited:
+			return nil
+		}
+		state[i] = visiting
+		for _, dep := range stages[i].After {
+			if err := visit(index[dep]); err != nil {
+				return err
+			}
+		}
+		state[i] = visited
+		return nil
+	}
+
+	for i := range stages {
+		if err := visit(i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
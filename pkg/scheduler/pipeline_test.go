@@ -0,0 +1,111 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRunPipelineOrdersByDependency(t *testing.T) {
+	var mu sync.Mutex
+	var order []string
+	stage := func(name string, after ...string) Stage {
+		return Stage{Name: name, After: after, Run: func(ctx context.Context) error {
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+			return nil
+		}}
+	}
+
+	results, err := RunPipeline(context.Background(), []Stage{
+		stage("future_proposals", "validators", "proposer_schedule"),
+		stage("validators"),
+		stage("proposer_schedule", "validators"),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []string{"validators", "proposer_schedule", "future_proposals"}
+	for i := range expected {
+		if order[i] != expected[i] {
+			t.Fatalf("Expected %v, got %v", expected, order)
+		}
+	}
+	if results[0].Name != "future_proposals" || results[0].Outcome != OutcomeCompleted {
+		t.Errorf("Expected results in the order given, got %+v", results[0])
+	}
+}
+
+func TestRunPipelineRunsIndependentStagesConcurrently(t *testing.T) {
+	// Each stage waits for the other to start, so they only finish if they run at the same time
+	started := make(chan struct{}, 2)
+	stage := func(name string) Stage {
+		return Stage{Name: name, Budget: time.Second, Run: func(ctx context.Context) error {
+			started <- struct{}{}
+			for len(started) < 2 {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(time.Millisecond):
+				}
+			}
+			return nil
+		}}
+	}
+
+	results, err := RunPipeline(context.Background(), []Stage{stage("a"), stage("b")})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, result := range results {
+		if result.Outcome != OutcomeCompleted {
+			t.Errorf("Expected %s to complete alongside the other stage, got %s", result.Name, result.Outcome)
+		}
+	}
+}
+
+func TestRunPipelineSkipsDependentsOfFailedStages(t *testing.T) {
+	ran := false
+	results, err := RunPipeline(context.Background(), []Stage{
+		{Name: "validators", Run: func(ctx context.Context) error { return errors.New("beacon node down") }},
+		{Name: "sync_committee", After: []string{"validators"}, Run: func(ctx context.Context) error {
+			ran = true
+			return nil
+		}},
+		{Name: "slow", Budget: 10 * time.Millisecond, Run: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if results[0].Outcome != OutcomeFailed {
+		t.Errorf("Expected validators to fail, got %s", results[0].Outcome)
+	}
+	if ran || results[1].Outcome != OutcomeSkipped {
+		t.Errorf("Expected sync_committee to be skipped, got %s (ran: %v)", results[1].Outcome, ran)
+	}
+	if results[2].Outcome != OutcomeDeadlineExceeded {
+		t.Errorf("Expected slow to exceed its budget, got %s", results[2].Outcome)
+	}
+}
+
+func TestRunPipelineRejectsInvalidDependencies(t *testing.T) {
+	noop := func(ctx context.Context) error { return nil }
+	tests := map[string][]Stage{
+		"unknown": {{Name: "a", After: []string{"b"}, Run: noop}},
+		"cycle":   {{Name: "a", After: []string{"b"}, Run: noop}, {Name: "b", After: []string{"a"}, Run: noop}},
+		"self":    {{Name: "a", After: []string{"a"}, Run: noop}},
+	}
+	for name, stages := range tests {
+		if _, err := RunPipeline(context.Background(), stages); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/tracing"
+	"github.com/sirupsen/logrus"
+)
+
+// Priority determines the order tasks run in and whether they may be skipped
+type Priority int
+
+const (
+	// PriorityCritical tasks always run (duty accounting)
+	PriorityCritical Priority = iota
+	// PriorityNormal tasks run after critical tasks if the slot budget allows
+	PriorityNormal
+	// PriorityIdle tasks only run in leftover time and are carried over to later slots otherwise
+	PriorityIdle
+)
+
+// String returns the priority name used in metrics and logs
+func (p Priority) String() string {
+	switch p {
+	case PriorityCritical:
+		return "critical"
+	case PriorityNormal:
+		return "normal"
+	default:
+		return "idle"
+	}
+}
+
+// DefaultIdleReserve is the minimum budget left in a slot before idle work is started
+const DefaultIdleReserve = 2 * time.Second
+
+// Task is a unit of per-slot work
+type Task struct {
+	Name     string
+	Priority Priority
+	// Budget caps the task's runtime; 0 means it may use the rest of the slot
+	Budget time.Duration
+	Run    func(ctx context.Context) error
+}
+
+// Outcome is what happened to a task in one slot
+type Outcome string
+
+const (
+	OutcomeCompleted        Outcome = "completed"
+	OutcomeFailed           Outcome = "failed"
+	OutcomeDeadlineExceeded Outcome = "deadline_exceeded"
+	OutcomeSkipped          Outcome = "skipped"
+	OutcomeDeferred         Outcome = "deferred"
+)
+
+// TaskResult records one task's run within a slot
+type TaskResult struct {
+	Name     string
+	Priority Priority
+	Outcome  Outcome
+	Duration time.Duration
+	Err      error
+}
+
+// Report summarizes a slot's scheduling
+type Report struct {
+	Budget    time.Duration // Time available when the slot started
+	Remaining time.Duration // Time left after all tasks ran
+	Results   []TaskResult
+}
+
+// Scheduler allocates the per-slot time budget across tasks
+type Scheduler struct {
+	mu          sync.Mutex
+	idle        []Task // Idle tasks waiting for spare budget (oldest first)
+	idleReserve time.Duration
+	logger      *logrus.Logger
+	now         func() time.Time
+}
+
+// New creates a new scheduler
+func New(idleReserve time.Duration, logger *logrus.Logger) *Scheduler {
+	return &Scheduler{
+		idleReserve: idleReserve,
+		logger:      logger,
+		now:         time.Now,
+	}
+}
+
+// Defer queues idle work to run when a slot has spare budget
+// A queued task with the same name is replaced, so repeated refreshes don't pile up
+func (s *Scheduler) Defer(task Task) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	task.Priority = PriorityIdle
+	for i, queued := range s.idle {
+		if queued.Name == task.Name {
+			s.idle[i] = task
+			return
+		}
+	}
+	s.idle = append(s.idle, task)
+}
+
+// Pending returns the number of queued idle tasks
+func (s *Scheduler) Pending() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.idle)
+}
+
+// RunSlot runs tasks by priority until deadline, then drains queued idle work with the leftover budget
+func (s *Scheduler) RunSlot(ctx context.Context, deadline time.Time, tasks []Task) Report {
+	report := Report{Budget: deadline.Sub(s.now())}
+
+	ordered := make([]Task, len(tasks))
+	copy(ordered, tasks)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].Priority < ordered[j].Priority })
+
+	for _, task := range ordered {
+		if task.Priority == PriorityIdle {
+			s.Defer(task)
+			continue
+		}
+
+		remaining := deadline.Sub(s.now())
+		if task.Priority != PriorityCritical && remaining <= 0 {
+			report.Results = append(report.Results, TaskResult{Name: task.Name, Priority: task.Priority, Outcome: OutcomeSkipped})
+			continue
+		}
+
+		report.Results = append(report.Results, s.run(ctx, task, deadline))
+	}
+
+	// Spend what's left of the slot on idle work, oldest first
+	for {
+		if deadline.Sub(s.now()) < s.idleReserve {
+			break
+		}
+
+		s.mu.Lock()
+		if len(s.idle) == 0 {
+			s.mu.Unlock()
+			break
+		}
+		task := s.idle[0]
+		s.idle = s.idle[1:]
+		s.mu.Unlock()
+
+		report.Results = append(report.Results, s.run(ctx, task, deadline))
+	}
+
+	s.mu.Lock()
+	for _, task := range s.idle {
+		report.Results = append(report.Results, TaskResult{Name: task.Name, Priority: PriorityIdle, Outcome: OutcomeDeferred})
+	}
+	s.mu.Unlock()
+
+	report.Remaining = deadline.Sub(s.now())
+	return report
+}
+
+// run executes a task with its deadline
+// Critical tasks are bounded only by their own budget, never by the slot deadline
+func (s *Scheduler) run(ctx context.Context, task Task, deadline time.Time) TaskResult {
+	start := s.now()
+
+	var taskCtx context.Context
+	var cancel context.CancelFunc
+	switch {
+	case task.Priority == PriorityCritical && task.Budget > 0:
+		taskCtx, cancel = context.WithTimeout(ctx, task.Budget)
+	case task.Priority == PriorityCritical:
+		// Critical work without its own budget is never cut short, and may start
+		// background work that outlives the slot, so it gets the parent context as is
+		taskCtx, cancel = ctx, func() {}
+	case task.Budget > 0 && start.Add(task.Budget).Before(deadline):
+		taskCtx, cancel = context.WithTimeout(ctx, task.Budget)
+	default:
+		taskCtx, cancel = context.WithDeadline(ctx, deadline)
+	}
+	defer cancel()
+
+	taskCtx, span := tracing.Start(taskCtx, "task "+task.Name)
+	err := task.Run(taskCtx)
+	tracing.End(span, err)
+	result := TaskResult{
+		Name:     task.Name,
+		Priority: task.Priority,
+		Duration: s.now().Sub(start),
+		Outcome:  outcomeOf(err),
+		Err:      err,
+	}
+
+	if result.Outcome != OutcomeCompleted {
+		s.logger.WithError(err).WithFields(logrus.Fields{
+			"task":     task.Name,
+			"priority": task.Priority.String(),
+			"duration": result.Duration,
+		}).Debug("Scheduled task did not complete")
+	}
+
+	return result
+}
+
+// outcomeOf classifies the error a task or stage returned
+func outcomeOf(err error) Outcome {
+	switch {
+	case err == nil:
+		return OutcomeCompleted
+	case errors.Is(err, context.DeadlineExceeded):
+		return OutcomeDeadlineExceeded
+	default:
+		return OutcomeFailed
+	}
+}
@@ -0,0 +1,115 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func newTestScheduler() *Scheduler {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	return New(time.Second, logger)
+}
+
+func TestRunSlotOrdersByPriority(t *testing.T) {
+	s := newTestScheduler()
+
+	var order []string
+	task := func(name string, priority Priority) Task {
+		return Task{Name: name, Priority: priority, Run: func(ctx context.Context) error {
+			order = append(order, name)
+			return nil
+		}}
+	}
+
+	report := s.RunSlot(context.Background(), time.Now().Add(time.Minute), []Task{
+		task("metrics", PriorityNormal),
+		task("price", PriorityIdle),
+		task("slot", PriorityCritical),
+	})
+
+	expected := []string{"slot", "metrics", "price"}
+	if len(order) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, order)
+	}
+	for i := range expected {
+		if order[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected, order)
+			break
+		}
+	}
+
+	for _, result := range report.Results {
+		if result.Outcome != OutcomeCompleted {
+			t.Errorf("Expected %s to complete, got %s", result.Name, result.Outcome)
+		}
+	}
+}
+
+func TestRunSlotDefersIdleWork(t *testing.T) {
+	s := newTestScheduler()
+
+	ran := 0
+	idle := Task{Name: "price", Priority: PriorityIdle, Run: func(ctx context.Context) error {
+		ran++
+		return nil
+	}}
+
+	// Less than the idle reserve is left: idle work waits for a later slot
+	report := s.RunSlot(context.Background(), time.Now().Add(500*time.Millisecond), []Task{idle, idle})
+	if ran != 0 {
+		t.Fatalf("Expected idle task to be deferred, ran %d times", ran)
+	}
+	if s.Pending() != 1 {
+		t.Errorf("Expected 1 queued idle task (deduplicated by name), got %d", s.Pending())
+	}
+	if len(report.Results) != 1 || report.Results[0].Outcome != OutcomeDeferred {
+		t.Errorf("Expected a single deferred result, got %+v", report.Results)
+	}
+
+	// A slot with spare time drains the queue
+	s.RunSlot(context.Background(), time.Now().Add(time.Minute), nil)
+	if ran != 1 || s.Pending() != 0 {
+		t.Errorf("Expected idle task to run once and leave the queue empty, ran %d, pending %d", ran, s.Pending())
+	}
+}
+
+func TestRunSlotDeadlines(t *testing.T) {
+	s := newTestScheduler()
+
+	normalRan := false
+	waitForDeadline := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	report := s.RunSlot(context.Background(), time.Now().Add(20*time.Millisecond), []Task{
+		{Name: "slow", Priority: PriorityNormal, Run: waitForDeadline},
+		{Name: "late", Priority: PriorityNormal, Run: func(ctx context.Context) error {
+			normalRan = true
+			return nil
+		}},
+		{Name: "critical", Priority: PriorityCritical, Budget: 10 * time.Millisecond, Run: waitForDeadline},
+	})
+
+	outcomes := make(map[string]Outcome)
+	for _, result := range report.Results {
+		outcomes[result.Name] = result.Outcome
+	}
+
+	if outcomes["critical"] != OutcomeDeadlineExceeded {
+		t.Errorf("Expected critical task to hit its budget, got %s", outcomes["critical"])
+	}
+	if outcomes["slow"] != OutcomeDeadlineExceeded {
+		t.Errorf("Expected slow task to hit the slot deadline, got %s", outcomes["slow"])
+	}
+	if normalRan || outcomes["late"] != OutcomeSkipped {
+		t.Errorf("Expected late task to be skipped once the slot is over, got %s", outcomes["late"])
+	}
+	if report.Remaining > 0 {
+		t.Errorf("Expected no remaining budget, got %v", report.Remaining)
+	}
+}
@@ -0,0 +1,120 @@
+package sharedcache
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/cache"
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultKeyPrefix namespaces the watcher's keys when none is configured
+const DefaultKeyPrefix = "eth-validator-watcher"
+
+// Cache is a Redis cache shared by the watcher replicas of one network
+// Values are stored as gzipped JSON under <prefix>:<network>:<key>
+// Callers treat every error as a miss and fall back to the beacon node
+type Cache struct {
+	client *redis.Client
+	prefix string
+
+	mu    sync.Mutex
+	stats cache.Stats
+}
+
+// New connects to the Redis server at url (redis://[user:password@]host:port/db)
+func New(ctx context.Context, url, keyPrefix, network string) (*Cache, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis url: %w", err)
+	}
+
+	client := redis.NewClient(opts)
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to connect to redis: %w", err)
+	}
+
+	if keyPrefix == "" {
+		keyPrefix = DefaultKeyPrefix
+	}
+	return &Cache{
+		client: client,
+		prefix: keyPrefix + ":" + network + ":",
+	}, nil
+}
+
+// Name returns the cache name used in metrics
+func (c *Cache) Name() string {
+	return "shared"
+}
+
+// Stats returns the hit and miss counters of Get
+func (c *Cache) Stats() cache.Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.stats
+}
+
+// Get decodes the value stored under key into value
+// ok is false if the key is missing or expired
+func (c *Cache) Get(ctx context.Context, key string, value any) (ok bool, err error) {
+	data, err := c.client.Get(ctx, c.prefix+key).Bytes()
+
+	c.mu.Lock()
+	if err == nil {
+		c.stats.Hits++
+	} else {
+		c.stats.Misses++
+	}
+	c.mu.Unlock()
+
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return false, fmt.Errorf("failed to decompress %s: %w", key, err)
+	}
+	defer zr.Close()
+	if err := json.NewDecoder(zr).Decode(value); err != nil {
+		return false, fmt.Errorf("failed to decode %s: %w", key, err)
+	}
+	return true, nil
+}
+
+// Set stores value under key for ttl
+func (c *Cache) Set(ctx context.Context, key string, value any, ttl time.Duration) error {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(zw).Encode(value); err != nil {
+		return fmt.Errorf("failed to encode %s: %w", key, err)
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to compress %s: %w", key, err)
+	}
+
+	return c.client.Set(ctx, c.prefix+key, buf.Bytes(), ttl).Err()
+}
+
+// Acquire claims key for ttl, returning true for the one replica that got it first
+// It is used to elect the replica that does an expensive load for everyone
+func (c *Cache) Acquire(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	return c.client.SetNX(ctx, c.prefix+"lock:"+key, time.Now().Unix(), ttl).Result()
+}
+
+// Close closes the connection pool
+func (c *Cache) Close() error {
+	return c.client.Close()
+}
@@ -0,0 +1,101 @@
+package sharedcache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func newTestCache(t *testing.T, network string) (*Cache, *miniredis.Miniredis) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	c, err := New(context.Background(), "redis://"+server.Addr(), "", network)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c, server
+}
+
+func TestCacheGetSet(t *testing.T) {
+	ctx := context.Background()
+	c, server := newTestCache(t, "mainnet")
+
+	var got []int
+	ok, err := c.Get(ctx, "committees:10", &got)
+	if err != nil || ok {
+		t.Fatalf("Get() on empty cache = %v, %v, want miss", ok, err)
+	}
+
+	if err := c.Set(ctx, "committees:10", []int{1, 2, 3}, time.Minute); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if !server.Exists("eth-validator-watcher:mainnet:committees:10") {
+		t.Error("Set() did not use the network key prefix")
+	}
+
+	ok, err = c.Get(ctx, "committees:10", &got)
+	if err != nil || !ok {
+		t.Fatalf("Get() = %v, %v, want hit", ok, err)
+	}
+	if len(got) != 3 || got[2] != 3 {
+		t.Errorf("Get() decoded %v, want [1 2 3]", got)
+	}
+
+	stats := c.Stats()
+	if stats.Hits != 1 || stats.Misses != 1 {
+		t.Errorf("Stats() = %+v, want 1 hit and 1 miss", stats)
+	}
+
+	server.FastForward(2 * time.Minute)
+	if ok, _ := c.Get(ctx, "committees:10", &got); ok {
+		t.Error("Get() returned an expired value")
+	}
+}
+
+func TestCacheAcquire(t *testing.T) {
+	ctx := context.Background()
+	c, server := newTestCache(t, "mainnet")
+
+	other, err := New(ctx, "redis://"+server.Addr(), "", "mainnet")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer other.Close()
+
+	first, err := c.Acquire(ctx, "all_validators:5", time.Minute)
+	if err != nil || !first {
+		t.Fatalf("first Acquire() = %v, %v, want true", first, err)
+	}
+	second, err := other.Acquire(ctx, "all_validators:5", time.Minute)
+	if err != nil || second {
+		t.Fatalf("second Acquire() = %v, %v, want false", second, err)
+	}
+
+	server.FastForward(2 * time.Minute)
+	if again, _ := other.Acquire(ctx, "all_validators:5", time.Minute); !again {
+		t.Error("Acquire() failed after the claim expired")
+	}
+}
+
+func TestCacheNetworksAreIsolated(t *testing.T) {
+	ctx := context.Background()
+	c, server := newTestCache(t, "mainnet")
+
+	holesky, err := New(ctx, "redis://"+server.Addr(), "", "holesky")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer holesky.Close()
+
+	if err := c.Set(ctx, "price", 3000.0, time.Minute); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	var price float64
+	if ok, _ := holesky.Get(ctx, "price", &price); ok {
+		t.Error("Get() returned another network's value")
+	}
+}
@@ -0,0 +1,118 @@
+// Package snapshot exports what the watcher serves to a file, for read-only replicas that serve it
+// without a beacon node or any other infrastructure access
+package snapshot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/api"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+// Version is the snapshot format version
+const Version = 1
+
+// Snapshot is the API data and Prometheus metrics of a watcher at one point in time
+type Snapshot struct {
+	Version   int          `json:"version"`
+	Network   string       `json:"network"`
+	CreatedAt time.Time    `json:"created_at"`
+	Slot      models.Slot  `json:"slot"`
+	Epoch     models.Epoch `json:"epoch"`
+	API       api.State    `json:"api"`
+	Metrics   string       `json:"metrics"` // Prometheus text exposition
+}
+
+// New captures the API state and the metrics of a registry
+func New(network string, slot models.Slot, epoch models.Epoch, state api.State, gatherer prometheus.Gatherer, now time.Time) (*Snapshot, error) {
+	families, err := gatherer.Gather()
+	if err != nil {
+		return nil, fmt.Errorf("failed to gather metrics: %w", err)
+	}
+
+	var text bytes.Buffer
+	for _, family := range families {
+		if _, err := expfmt.MetricFamilyToText(&text, family); err != nil {
+			return nil, fmt.Errorf("failed to encode metric %s: %w", family.GetName(), err)
+		}
+	}
+
+	return &Snapshot{
+		Version:   Version,
+		Network:   network,
+		CreatedAt: now.UTC(),
+		Slot:      slot,
+		Epoch:     epoch,
+		API:       state,
+		Metrics:   text.String(),
+	}, nil
+}
+
+// Write saves a snapshot, replacing the file atomically so readers never see a partial one
+func Write(path string, s *Snapshot) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return nil
+}
+
+// Read loads a snapshot
+func Read(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	var s Snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("invalid snapshot %s: %w", path, err)
+	}
+	if s.Version != Version {
+		return nil, fmt.Errorf("unsupported snapshot version %d (supported: %d)", s.Version, Version)
+	}
+	return &s, nil
+}
+
+// Gatherer serves the snapshot's metrics as they were exported
+func (s *Snapshot) Gatherer() (prometheus.Gatherer, error) {
+	var parser expfmt.TextParser
+	parsed, err := parser.TextToMetricFamilies(strings.NewReader(s.Metrics))
+	if err != nil {
+		return nil, fmt.Errorf("invalid snapshot metrics: %w", err)
+	}
+
+	families := make([]*dto.MetricFamily, 0, len(parsed))
+	for _, family := range parsed {
+		families = append(families, family)
+	}
+	return prometheus.GathererFunc(func() ([]*dto.MetricFamily, error) {
+		return families, nil
+	}), nil
+}
@@ -0,0 +1,73 @@
+package snapshot
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/api"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/metrics"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sirupsen/logrus"
+)
+
+func TestRoundTrip(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	source := api.NewServer(metrics.DefaultScorecardWeights(), logger)
+	source.UpdateMetrics(map[string]*metrics.MetricsByLabel{
+		"operator:a": {Label: "operator:a", ValidatorCount: 2, AttestationDuties: 10, AttestationDutiesSuccess: 9},
+	})
+	source.UpdateLiveness(41, map[models.ValidatorIndex]bool{7: true})
+
+	registry := prometheus.NewRegistry()
+	gauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "eth_slot", Help: "Current Ethereum slot number"}, []string{"network"})
+	registry.MustRegister(gauge)
+	gauge.WithLabelValues("mainnet").Set(1344)
+
+	s, err := New("mainnet", 1344, 42, source.State(), registry, time.Unix(0, 0))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	if err := Write(path, s); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	loaded, err := Read(path)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if loaded.Network != "mainnet" || loaded.Epoch != 42 || *loaded.API.LivenessEpoch != 41 {
+		t.Errorf("Unexpected snapshot: %+v", loaded)
+	}
+
+	replica := api.NewServer(nil, logger)
+	replica.Restore(loaded.API)
+	mux := http.NewServeMux()
+	replica.Register(mux)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/labels/operator:a/summary", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"attestation_duties":10`) {
+		t.Errorf("Expected the exported label summary, got %d %s", rec.Code, rec.Body.String())
+	}
+
+	gatherer, err := loaded.Gatherer()
+	if err != nil {
+		t.Fatalf("Gatherer() error = %v", err)
+	}
+	expected := `
+# HELP eth_slot Current Ethereum slot number
+# TYPE eth_slot gauge
+eth_slot{network="mainnet"} 1344
+`
+	if err := testutil.GatherAndCompare(gatherer, strings.NewReader(expected), "eth_slot"); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,251 @@
+package store
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/metrics"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/onchain"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/validator"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	metaBucket       = []byte("meta")
+	validatorsBucket = []byte("validators")
+	countersBucket   = []byte("block_counters")
+
+	epochKey              = []byte("epoch")
+	lastProcessedEpochKey = []byte("last_processed_epoch")
+	depositScanKey        = []byte("deposit_scan")
+)
+
+// openTimeout bounds the wait for the file lock held by another watcher instance
+const openTimeout = time.Second
+
+// ValidatorCounters are the persisted counters of one watched validator
+type ValidatorCounters struct {
+	MissedAttestations          uint64            `json:"missed_attestations"`
+	SuboptimalSourceVotes       uint64            `json:"suboptimal_source_votes"`
+	SuboptimalTargetVotes       uint64            `json:"suboptimal_target_votes"`
+	SuboptimalHeadVotes         uint64            `json:"suboptimal_head_votes"`
+	IdealConsensusRewards       models.Gwei       `json:"ideal_consensus_rewards"`
+	ConsensusRewards            models.SignedGwei `json:"consensus_rewards"`
+	ProposedBlocks              uint64            `json:"proposed_blocks"`
+	ProposedBlocksFinalized     uint64            `json:"proposed_blocks_finalized"`
+	MissedBlocks                uint64            `json:"missed_blocks"`
+	MissedBlocksFinalized       uint64            `json:"missed_blocks_finalized"`
+	AttestationDuties           uint64            `json:"attestation_duties"`
+	AttestationDutiesSuccess    uint64            `json:"attestation_duties_success"`
+	ConsecutiveMissedAttest     uint64            `json:"consecutive_missed_attest"`
+	ExpectedAggregations        float64           `json:"expected_aggregations"`
+	CommitteeAggregatesIncluded uint64            `json:"committee_aggregates_included"`
+	CommitteeAggregatesMissed   uint64            `json:"committee_aggregates_missed"`
+	InclusionDelaySum           uint64            `json:"inclusion_delay_sum"`
+	InclusionDelayCount         uint64            `json:"inclusion_delay_count"`
+	MaxInclusionDelay           uint64            `json:"max_inclusion_delay"`
+	SyncCommitteeSigned         uint64            `json:"sync_committee_signed"`
+	SyncCommitteeMissed         uint64            `json:"sync_committee_missed"`
+}
+
+// CountersOf copies the persisted counters of a watched validator
+func CountersOf(v *validator.WatchedValidator) ValidatorCounters {
+	return ValidatorCounters{
+		MissedAttestations:          v.MissedAttestations,
+		SuboptimalSourceVotes:       v.SuboptimalSourceVotes,
+		SuboptimalTargetVotes:       v.SuboptimalTargetVotes,
+		SuboptimalHeadVotes:         v.SuboptimalHeadVotes,
+		IdealConsensusRewards:       v.IdealConsensusRewards,
+		ConsensusRewards:            v.ConsensusRewards,
+		ProposedBlocks:              v.ProposedBlocks,
+		ProposedBlocksFinalized:     v.ProposedBlocksFinalized,
+		MissedBlocks:                v.MissedBlocks,
+		MissedBlocksFinalized:       v.MissedBlocksFinalized,
+		AttestationDuties:           v.AttestationDuties,
+		AttestationDutiesSuccess:    v.AttestationDutiesSuccess,
+		ConsecutiveMissedAttest:     v.ConsecutiveMissedAttest,
+		ExpectedAggregations:        v.ExpectedAggregations,
+		CommitteeAggregatesIncluded: v.CommitteeAggregatesIncluded,
+		CommitteeAggregatesMissed:   v.CommitteeAggregatesMissed,
+		InclusionDelaySum:           v.InclusionDelaySum,
+		InclusionDelayCount:         v.InclusionDelayCount,
+		MaxInclusionDelay:           v.MaxInclusionDelay,
+		SyncCommitteeSigned:         v.SyncCommitteeSigned,
+		SyncCommitteeMissed:         v.SyncCommitteeMissed,
+	}
+}
+
+// Apply restores the counters onto a watched validator
+func (c ValidatorCounters) Apply(v *validator.WatchedValidator) {
+	v.MissedAttestations = c.MissedAttestations
+	v.SuboptimalSourceVotes = c.SuboptimalSourceVotes
+	v.SuboptimalTargetVotes = c.SuboptimalTargetVotes
+	v.SuboptimalHeadVotes = c.SuboptimalHeadVotes
+	v.IdealConsensusRewards = c.IdealConsensusRewards
+	v.ConsensusRewards = c.ConsensusRewards
+	v.ProposedBlocks = c.ProposedBlocks
+	v.ProposedBlocksFinalized = c.ProposedBlocksFinalized
+	v.MissedBlocks = c.MissedBlocks
+	v.MissedBlocksFinalized = c.MissedBlocksFinalized
+	v.AttestationDuties = c.AttestationDuties
+	v.AttestationDutiesSuccess = c.AttestationDutiesSuccess
+	v.ConsecutiveMissedAttest = c.ConsecutiveMissedAttest
+	v.ExpectedAggregations = c.ExpectedAggregations
+	v.CommitteeAggregatesIncluded = c.CommitteeAggregatesIncluded
+	v.CommitteeAggregatesMissed = c.CommitteeAggregatesMissed
+	v.InclusionDelaySum = c.InclusionDelaySum
+	v.InclusionDelayCount = c.InclusionDelayCount
+	v.MaxInclusionDelay = c.MaxInclusionDelay
+	v.SyncCommitteeSigned = c.SyncCommitteeSigned
+	v.SyncCommitteeMissed = c.SyncCommitteeMissed
+}
+
+// State is the watcher state kept across restarts
+type State struct {
+	Epoch              models.Epoch                     // Settled epoch the validator counters were saved in
+	LastProcessedEpoch models.Epoch                     // Last epoch whose epoch processing completed
+	Validators         map[string]ValidatorCounters     // By pubkey, so they survive index cache loss
+	BlockCounters      map[string]metrics.ScopeCounters // Prometheus block proposal counters by scope
+	DepositScan        *onchain.DepositScan             // Deposit contract scan progress, nil without deposit tracking
+}
+
+// Store persists watcher state in a BoltDB file
+type Store struct {
+	db *bolt.DB
+}
+
+// Open opens (or creates) the state file
+func Open(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0644, &bolt.Options{Timeout: openTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open state store: %w", err)
+	}
+	return &Store{db: db}, nil
+}
+
+// Close closes the state file
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Save replaces the stored state in a single transaction
+func (s *Store) Save(state *State) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		meta, err := tx.CreateBucketIfNotExists(metaBucket)
+		if err != nil {
+			return err
+		}
+		if err := meta.Put(epochKey, encodeUint(uint64(state.Epoch))); err != nil {
+			return err
+		}
+		if err := meta.Put(lastProcessedEpochKey, encodeUint(uint64(state.LastProcessedEpoch))); err != nil {
+			return err
+		}
+		if state.DepositScan != nil {
+			data, err := json.Marshal(state.DepositScan)
+			if err != nil {
+				return err
+			}
+			if err := meta.Put(depositScanKey, data); err != nil {
+				return err
+			}
+		}
+
+		if err := putAll(tx, validatorsBucket, state.Validators); err != nil {
+			return err
+		}
+		return putAll(tx, countersBucket, state.BlockCounters)
+	})
+}
+
+// Load reads the stored state; ok is false if nothing was saved yet
+func (s *Store) Load() (state *State, ok bool, err error) {
+	state = &State{
+		Validators:    make(map[string]ValidatorCounters),
+		BlockCounters: make(map[string]metrics.ScopeCounters),
+	}
+
+	err = s.db.View(func(tx *bolt.Tx) error {
+		meta := tx.Bucket(metaBucket)
+		if meta == nil {
+			return nil
+		}
+		ok = true
+		state.Epoch = models.Epoch(decodeUint(meta.Get(epochKey)))
+		state.LastProcessedEpoch = models.Epoch(decodeUint(meta.Get(lastProcessedEpochKey)))
+		if data := meta.Get(depositScanKey); data != nil {
+			state.DepositScan = &onchain.DepositScan{}
+			if err := json.Unmarshal(data, state.DepositScan); err != nil {
+				return fmt.Errorf("invalid deposit scan: %w", err)
+			}
+		}
+
+		if err := getAll(tx, validatorsBucket, state.Validators); err != nil {
+			return err
+		}
+		return getAll(tx, countersBucket, state.BlockCounters)
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to load state store: %w", err)
+	}
+	return state, ok, nil
+}
+
+// putAll replaces a bucket's content with JSON-encoded values
+func putAll[T any](tx *bolt.Tx, name []byte, values map[string]T) error {
+	if tx.Bucket(name) != nil {
+		if err := tx.DeleteBucket(name); err != nil {
+			return err
+		}
+	}
+	bucket, err := tx.CreateBucket(name)
+	if err != nil {
+		return err
+	}
+
+	for key, value := range values {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		if err := bucket.Put([]byte(key), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getAll decodes every value of a bucket into values
+func getAll[T any](tx *bolt.Tx, name []byte, values map[string]T) error {
+	bucket := tx.Bucket(name)
+	if bucket == nil {
+		return nil
+	}
+
+	return bucket.ForEach(func(key, data []byte) error {
+		var value T
+		if err := json.Unmarshal(data, &value); err != nil {
+			return fmt.Errorf("invalid %s entry %s: %w", name, key, err)
+		}
+		values[string(key)] = value
+		return nil
+	})
+}
+
+// encodeUint encodes a number as 8 big-endian bytes
+func encodeUint(value uint64) []byte {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, value)
+	return buf
+}
+
+// decodeUint decodes encodeUint's output, returning 0 for a missing value
+func decodeUint(data []byte) uint64 {
+	if len(data) != 8 {
+		return 0
+	}
+	return binary.BigEndian.Uint64(data)
+}
@@ -0,0 +1,99 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/metrics"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/onchain"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/validator"
+)
+
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+	s, err := Open(filepath.Join(t.TempDir(), "state.db"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestLoadEmpty(t *testing.T) {
+	s := openTestStore(t)
+
+	state, ok, err := s.Load()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if ok {
+		t.Error("Expected ok=false before anything was saved")
+	}
+	if state.Validators == nil || state.BlockCounters == nil {
+		t.Error("Expected initialized maps on an empty load")
+	}
+}
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	s := openTestStore(t)
+
+	v := &validator.WatchedValidator{
+		MissedAttestations:      2,
+		ConsensusRewards:        -150,
+		ProposedBlocks:          1,
+		ConsecutiveMissedAttest: 2,
+		ExpectedAggregations:    0.25,
+	}
+	blocks := map[string]metrics.ScopeCounters{
+		"scope:watched": {
+			Totals: metrics.BlockCounters{ProposedHead: 7, MissedHead: 1},
+			Seen:   metrics.BlockCounters{ProposedHead: 1},
+		},
+	}
+
+	if err := s.Save(&State{
+		Epoch:              100,
+		LastProcessedEpoch: 99,
+		Validators:         map[string]ValidatorCounters{"0xaa": CountersOf(v), "0xbb": {MissedBlocks: 1}},
+		BlockCounters:      blocks,
+	}); err != nil {
+		t.Fatalf("Failed to save: %v", err)
+	}
+
+	// A second save replaces the first, dropping validators that are gone
+	if err := s.Save(&State{
+		Epoch:              101,
+		LastProcessedEpoch: 100,
+		Validators:         map[string]ValidatorCounters{"0xaa": CountersOf(v)},
+		BlockCounters:      blocks,
+		DepositScan: &onchain.DepositScan{
+			Next:     map[string]uint64{"0xcc": 20001},
+			Deposits: map[string]onchain.Deposit{"0xdd": {Pubkey: "0xdd", Amount: 32000000000, BlockNumber: 10002}},
+		},
+	}); err != nil {
+		t.Fatalf("Failed to save: %v", err)
+	}
+
+	state, ok, err := s.Load()
+	if err != nil || !ok {
+		t.Fatalf("Expected saved state, got ok=%v err=%v", ok, err)
+	}
+	if state.Epoch != 101 || state.LastProcessedEpoch != 100 {
+		t.Errorf("Expected epochs 101/100, got %d/%d", state.Epoch, state.LastProcessedEpoch)
+	}
+	if len(state.Validators) != 1 {
+		t.Fatalf("Expected 1 validator, got %d", len(state.Validators))
+	}
+
+	restored := &validator.WatchedValidator{}
+	state.Validators["0xaa"].Apply(restored)
+	if CountersOf(restored) != CountersOf(v) {
+		t.Errorf("Expected %+v, got %+v", CountersOf(v), CountersOf(restored))
+	}
+	if state.BlockCounters["scope:watched"] != blocks["scope:watched"] {
+		t.Errorf("Expected %+v, got %+v", blocks["scope:watched"], state.BlockCounters["scope:watched"])
+	}
+	if scan := state.DepositScan; scan == nil || scan.Next["0xcc"] != 20001 || scan.Deposits["0xdd"].BlockNumber != 10002 {
+		t.Errorf("Expected the deposit scan progress, got %+v", scan)
+	}
+}
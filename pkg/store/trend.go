@@ -0,0 +1,251 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/metrics"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	trendEpochsBucket = []byte("trend_epochs") // Label -> epoch -> TrendPoint
+	trendDaysBucket   = []byte("trend_days")   // Label -> UTC day -> TrendPoint
+)
+
+// Trend resolutions
+const (
+	ResolutionEpoch = "epoch"
+	ResolutionDay   = "day"
+)
+
+// TrendPoint is what a label gained over one epoch, or over the epochs of a UTC day once compacted
+type TrendPoint struct {
+	Start                    time.Time         `json:"start"` // Start of the epoch, or midnight UTC of the day
+	FirstEpoch               models.Epoch      `json:"first_epoch"`
+	LastEpoch                models.Epoch      `json:"last_epoch"`
+	Epochs                   int               `json:"epochs"`     // Epochs aggregated
+	Validators               int               `json:"validators"` // Most validators carrying the label in one epoch
+	AttestationDuties        uint64            `json:"attestation_duties"`
+	AttestationDutiesSuccess uint64            `json:"attestation_duties_success"`
+	MissedAttestations       uint64            `json:"missed_attestations"` // Settled attestation misses
+	ProposedBlocks           uint64            `json:"proposed_blocks"`
+	MissedBlocks             uint64            `json:"missed_blocks"`
+	IdealConsensusRewards    models.Gwei       `json:"ideal_consensus_rewards"`
+	ConsensusRewards         models.SignedGwei `json:"consensus_rewards"`
+}
+
+// EpochPoint copies a label's per-epoch counters of a settled epoch
+func EpochPoint(m *metrics.MetricsByLabel) TrendPoint {
+	return TrendPoint{
+		Epochs:                   1,
+		Validators:               m.ValidatorCount,
+		AttestationDuties:        m.AttestationDuties,
+		AttestationDutiesSuccess: m.AttestationDutiesSuccess,
+		MissedAttestations:       m.MissedAttestations,
+		ProposedBlocks:           m.ProposedBlocks,
+		MissedBlocks:             m.MissedBlocks,
+		IdealConsensusRewards:    m.IdealConsensusRewards,
+		ConsensusRewards:         m.ConsensusRewards,
+	}
+}
+
+// add folds another point into an aggregate
+func (p *TrendPoint) add(o TrendPoint) {
+	if p.Epochs == 0 || o.FirstEpoch < p.FirstEpoch {
+		p.FirstEpoch = o.FirstEpoch
+	}
+	if o.LastEpoch > p.LastEpoch {
+		p.LastEpoch = o.LastEpoch
+	}
+	p.Epochs += o.Epochs
+	p.Validators = max(p.Validators, o.Validators)
+	p.AttestationDuties += o.AttestationDuties
+	p.AttestationDutiesSuccess += o.AttestationDutiesSuccess
+	p.MissedAttestations += o.MissedAttestations
+	p.ProposedBlocks += o.ProposedBlocks
+	p.MissedBlocks += o.MissedBlocks
+	p.IdealConsensusRewards += o.IdealConsensusRewards
+	p.ConsensusRewards += o.ConsensusRewards
+}
+
+// AppendTrend stores the points of one epoch, by label
+func (s *Store) AppendTrend(epoch models.Epoch, start time.Time, points map[string]TrendPoint) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		root, err := tx.CreateBucketIfNotExists(trendEpochsBucket)
+		if err != nil {
+			return err
+		}
+
+		for label, point := range points {
+			point.Start = start.UTC()
+			point.FirstEpoch, point.LastEpoch = epoch, epoch
+			bucket, err := root.CreateBucketIfNotExists([]byte(label))
+			if err != nil {
+				return err
+			}
+			if err := putPoint(bucket, uint64(epoch), point); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// CompactTrend folds the per-epoch points of every UTC day that ended more than compactAfter ago
+// into one point per day, and drops daily points older than retention (0 keeps them)
+// Returns how many per-epoch points were compacted and daily points dropped
+func (s *Store) CompactTrend(now time.Time, compactAfter, retention time.Duration) (compacted, dropped int, err error) {
+	cutoff := startOfDay(now.Add(-compactAfter))
+
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		epochs := tx.Bucket(trendEpochsBucket)
+		if epochs != nil {
+			days, err := tx.CreateBucketIfNotExists(trendDaysBucket)
+			if err != nil {
+				return err
+			}
+
+			err = epochs.ForEachBucket(func(label []byte) error {
+				n, err := compactLabel(epochs.Bucket(label), days, label, cutoff)
+				compacted += n
+				return err
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		days := tx.Bucket(trendDaysBucket)
+		if days == nil || retention <= 0 {
+			return nil
+		}
+		oldest := uint64(startOfDay(now.Add(-retention)).Unix())
+		return days.ForEachBucket(func(label []byte) error {
+			n, err := deleteBefore(days.Bucket(label), oldest)
+			dropped += n
+			return err
+		})
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to compact trend: %w", err)
+	}
+	return compacted, dropped, nil
+}
+
+// compactLabel moves a label's per-epoch points from before cutoff into its daily points
+func compactLabel(epochs, days *bolt.Bucket, label []byte, cutoff time.Time) (int, error) {
+	aggregates := make(map[uint64]*TrendPoint)
+	var done [][]byte
+
+	cursor := epochs.Cursor()
+	for key, data := cursor.First(); key != nil; key, data = cursor.Next() {
+		var point TrendPoint
+		if err := json.Unmarshal(data, &point); err != nil {
+			return 0, fmt.Errorf("invalid trend point %s/%d: %w", label, decodeUint(key), err)
+		}
+		// Epochs are keyed in order, so every later point is newer
+		if !point.Start.Before(cutoff) {
+			break
+		}
+
+		day := startOfDay(point.Start)
+		aggregate, ok := aggregates[uint64(day.Unix())]
+		if !ok {
+			aggregate = &TrendPoint{Start: day}
+			aggregates[uint64(day.Unix())] = aggregate
+		}
+		aggregate.add(point)
+		done = append(done, key)
+	}
+	if len(done) == 0 {
+		return 0, nil
+	}
+
+	labelDays, err := days.CreateBucketIfNotExists(label)
+	if err != nil {
+		return 0, err
+	}
+	for day, aggregate := range aggregates {
+		// A day may already hold epochs compacted by an earlier run
+		if data := labelDays.Get(encodeUint(day)); data != nil {
+			var existing TrendPoint
+			if err := json.Unmarshal(data, &existing); err != nil {
+				return 0, fmt.Errorf("invalid trend day %s/%d: %w", label, day, err)
+			}
+			aggregate.add(existing)
+		}
+		if err := putPoint(labelDays, day, *aggregate); err != nil {
+			return 0, err
+		}
+	}
+	for _, key := range done {
+		if err := epochs.Delete(key); err != nil {
+			return 0, err
+		}
+	}
+	return len(done), nil
+}
+
+// deleteBefore deletes the keys of a bucket below a number
+func deleteBefore(bucket *bolt.Bucket, before uint64) (int, error) {
+	var keys [][]byte
+	cursor := bucket.Cursor()
+	for key, _ := cursor.First(); key != nil && decodeUint(key) < before; key, _ = cursor.Next() {
+		keys = append(keys, key)
+	}
+	for _, key := range keys {
+		if err := bucket.Delete(key); err != nil {
+			return 0, err
+		}
+	}
+	return len(keys), nil
+}
+
+// Trend returns a label's points at a resolution, oldest first
+func (s *Store) Trend(label, resolution string) ([]TrendPoint, error) {
+	name := trendEpochsBucket
+	if resolution == ResolutionDay {
+		name = trendDaysBucket
+	}
+
+	points := []TrendPoint{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		root := tx.Bucket(name)
+		if root == nil {
+			return nil
+		}
+		bucket := root.Bucket([]byte(label))
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(key, data []byte) error {
+			var point TrendPoint
+			if err := json.Unmarshal(data, &point); err != nil {
+				return fmt.Errorf("invalid trend point %s/%d: %w", label, decodeUint(key), err)
+			}
+			points = append(points, point)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trend: %w", err)
+	}
+	return points, nil
+}
+
+// putPoint stores a JSON-encoded point under a numeric key
+func putPoint(bucket *bolt.Bucket, key uint64, point TrendPoint) error {
+	data, err := json.Marshal(point)
+	if err != nil {
+		return err
+	}
+	return bucket.Put(encodeUint(key), data)
+}
+
+// startOfDay returns midnight UTC of a time's day
+func startOfDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
@@ -0,0 +1,78 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/metrics"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+func TestEpochPoint(t *testing.T) {
+	point := EpochPoint(&metrics.MetricsByLabel{ValidatorCount: 5, AttestationDuties: 5, AttestationDutiesSuccess: 4, ConsensusRewards: -50})
+	if point.Epochs != 1 || point.Validators != 5 || point.AttestationDuties != 5 || point.AttestationDutiesSuccess != 4 || point.ConsensusRewards != -50 {
+		t.Errorf("EpochPoint() = %+v", point)
+	}
+}
+
+func TestCompactTrend(t *testing.T) {
+	s := openTestStore(t)
+
+	// Three epochs on day 1 (one of them compacted by an earlier run), one on day 2, one today
+	day1 := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	now := day1.Add(10 * 24 * time.Hour)
+	appendPoint := func(epoch models.Epoch, start time.Time, duties uint64) {
+		t.Helper()
+		err := s.AppendTrend(epoch, start, map[string]TrendPoint{
+			"operator:a": {Epochs: 1, Validators: int(epoch), AttestationDuties: duties},
+		})
+		if err != nil {
+			t.Fatalf("AppendTrend() error = %v", err)
+		}
+	}
+
+	appendPoint(10, day1.Add(time.Hour), 4)
+	if _, _, err := s.CompactTrend(day1.Add(25*time.Hour), 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	appendPoint(11, day1.Add(2*time.Hour), 5)
+	appendPoint(12, day1.Add(23*time.Hour), 6)
+	appendPoint(13, day1.Add(30*time.Hour), 7)
+	appendPoint(14, now, 8)
+
+	compacted, dropped, err := s.CompactTrend(now, 7*24*time.Hour, 0)
+	if err != nil {
+		t.Fatalf("CompactTrend() error = %v", err)
+	}
+	if compacted != 3 || dropped != 0 {
+		t.Errorf("CompactTrend() = %d, %d, want 3 compacted", compacted, dropped)
+	}
+
+	days, err := s.Trend("operator:a", ResolutionDay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(days) != 2 {
+		t.Fatalf("Expected 2 days, got %+v", days)
+	}
+	first := days[0]
+	if !first.Start.Equal(day1) || first.Epochs != 3 || first.FirstEpoch != 10 || first.LastEpoch != 12 || first.AttestationDuties != 15 || first.Validators != 12 {
+		t.Errorf("Day 1 = %+v, want epochs 10-12 merged", first)
+	}
+
+	epochs, err := s.Trend("operator:a", ResolutionEpoch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(epochs) != 1 || epochs[0].FirstEpoch != 14 {
+		t.Errorf("Expected only epoch 14 left, got %+v", epochs)
+	}
+
+	// Retention drops whole days
+	if _, dropped, err := s.CompactTrend(now, 7*24*time.Hour, 9*24*time.Hour); err != nil || dropped != 1 {
+		t.Errorf("CompactTrend() dropped %d, %v, want day 1 dropped", dropped, err)
+	}
+	if days, _ := s.Trend("operator:a", ResolutionDay); len(days) != 1 || days[0].FirstEpoch != 13 {
+		t.Errorf("Expected day 2 kept, got %+v", days)
+	}
+}
@@ -0,0 +1,140 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// OTLP exporter protocols
+const (
+	ProtocolHTTP = "http"
+	ProtocolGRPC = "grpc"
+)
+
+// DefaultServiceName is the service.name of the exported spans
+const DefaultServiceName = "eth-validator-watcher"
+
+// defaultHTTPPath is where OTLP/HTTP collectors receive spans, used when the endpoint has no path
+const defaultHTTPPath = "/v1/traces"
+
+// instrumentation names the tracer of the watcher's spans
+const instrumentation = "github.com/enriquemanuel/eth-validator-watcher"
+
+// Span attributes of the pipeline
+const (
+	AttrSlot     = attribute.Key("eth.slot")
+	AttrEpoch    = attribute.Key("eth.epoch")
+	AttrRoute    = attribute.Key("beacon.route")
+	AttrEndpoint = attribute.Key("beacon.endpoint")
+)
+
+// Slot returns the slot attribute of a span
+func Slot(slot models.Slot) attribute.KeyValue {
+	return AttrSlot.Int64(int64(slot))
+}
+
+// Epoch returns the epoch attribute of a span
+func Epoch(epoch models.Epoch) attribute.KeyValue {
+	return AttrEpoch.Int64(int64(epoch))
+}
+
+// Start starts a span of the watcher's tracer; until Setup installs an exporter spans are no-ops
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentation).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End ends a span, marking it failed if err is set
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Validate checks a tracing config
+func Validate(cfg models.Tracing) error {
+	if cfg.Endpoint == "" {
+		return nil
+	}
+	switch cfg.Protocol {
+	case "", ProtocolHTTP, ProtocolGRPC:
+	default:
+		return fmt.Errorf("unknown protocol %q (http or grpc)", cfg.Protocol)
+	}
+	if !strings.HasPrefix(cfg.Endpoint, "http://") && !strings.HasPrefix(cfg.Endpoint, "https://") {
+		return fmt.Errorf("endpoint must be an http(s) URL")
+	}
+	if cfg.SampleRatio < 0 || cfg.SampleRatio > 1 {
+		return fmt.Errorf("sample_ratio must be in [0, 1]")
+	}
+	return nil
+}
+
+// Setup installs the global tracer provider exporting spans over OTLP
+// Returns a shutdown function flushing the spans still buffered; tracing stays disabled without an endpoint
+func Setup(ctx context.Context, cfg models.Tracing, version string) (func(context.Context) error, error) {
+	if cfg.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := newExporter(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+
+	serviceName := cfg.ServiceName
+	if serviceName == "" {
+		serviceName = DefaultServiceName
+	}
+	res := resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(serviceName),
+		semconv.ServiceVersion(version),
+	)
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// newExporter creates the OTLP exporter of the configured protocol
+func newExporter(ctx context.Context, cfg models.Tracing) (*otlptrace.Exporter, error) {
+	headers := make(map[string]string, len(cfg.Headers))
+	for name, value := range cfg.Headers {
+		headers[name] = os.ExpandEnv(value)
+	}
+
+	if cfg.Protocol == ProtocolGRPC {
+		return otlptracegrpc.New(ctx,
+			otlptracegrpc.WithEndpointURL(cfg.Endpoint),
+			otlptracegrpc.WithHeaders(headers),
+		)
+	}
+	opts := []otlptracehttp.Option{
+		otlptracehttp.WithEndpointURL(cfg.Endpoint),
+		otlptracehttp.WithHeaders(headers),
+	}
+	if u, err := url.Parse(cfg.Endpoint); err == nil && strings.Trim(u.Path, "/") == "" {
+		opts = append(opts, otlptracehttp.WithURLPath(defaultHTTPPath))
+	}
+	return otlptracehttp.New(ctx, opts...)
+}
@@ -0,0 +1,67 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestStartEndRecordsNestedSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(previous)
+
+	ctx, parent := Start(context.Background(), "processEpoch", Epoch(10))
+	_, child := Start(ctx, "beacon /eth/v1/beacon/blocks/{id}")
+	End(child, errors.New("HTTP 500"))
+	End(parent, nil)
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("ended spans = %d, want 2", len(spans))
+	}
+	childSpan, parentSpan := spans[0], spans[1]
+	if childSpan.Parent().SpanID() != parentSpan.SpanContext().SpanID() {
+		t.Error("beacon span is not a child of the epoch span")
+	}
+	if childSpan.Status().Code != codes.Error {
+		t.Errorf("failed span status = %v, want error", childSpan.Status().Code)
+	}
+	if parentSpan.Status().Code != codes.Unset {
+		t.Errorf("successful span status = %v, want unset", parentSpan.Status().Code)
+	}
+	attrs := parentSpan.Attributes()
+	if len(attrs) != 1 || attrs[0].Key != AttrEpoch || attrs[0].Value.AsInt64() != 10 {
+		t.Errorf("epoch span attributes = %v", attrs)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     models.Tracing
+		wantErr bool
+	}{
+		{"disabled", models.Tracing{Protocol: "udp"}, false},
+		{"http", models.Tracing{Endpoint: "http://otel-collector:4318", SampleRatio: 1}, false},
+		{"grpc", models.Tracing{Endpoint: "https://otel.example.com", Protocol: ProtocolGRPC, SampleRatio: 0.1}, false},
+		{"unknown protocol", models.Tracing{Endpoint: "http://otel-collector:4318", Protocol: "udp"}, true},
+		{"no scheme", models.Tracing{Endpoint: "otel-collector:4318"}, true},
+		{"ratio over one", models.Tracing{Endpoint: "http://otel-collector:4318", SampleRatio: 2}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Validate(tt.cfg); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
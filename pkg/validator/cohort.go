@@ -0,0 +1,79 @@
+package validator
+
+import (
+	"strings"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// CohortPrefix starts the label of every comparison cohort (cohort:<name>)
+// Keys whose labels are all cohort labels are tracked for comparison only: they go through the same
+// duty and reward pipeline but aren't part of scope:watched and never alert
+const CohortPrefix = "cohort:"
+
+// CohortLabel returns the label of a cohort
+func CohortLabel(name string) string {
+	return CohortPrefix + name
+}
+
+// IsCohortLabel reports whether a label names a comparison cohort
+func IsCohortLabel(label string) bool {
+	return strings.HasPrefix(label, CohortPrefix)
+}
+
+// isCohortOnly reports whether a key is only watched as a member of comparison cohorts
+func isCohortOnly(labels []string) bool {
+	if len(labels) == 0 {
+		return false
+	}
+	for _, label := range labels {
+		if !IsCohortLabel(label) {
+			return false
+		}
+	}
+	return true
+}
+
+// MergeCohorts adds cohort members to the watched keys, with their cohort labels
+// Members that are already watched stay watched and aren't added to the cohort, so a comparison
+// never includes the operator's own validators
+func MergeCohorts(keys, members []models.WatchedKey) []models.WatchedKey {
+	if len(members) == 0 {
+		return keys
+	}
+
+	merged := make([]models.WatchedKey, 0, len(keys)+len(members))
+	merged = append(merged, keys...)
+	positions := make(map[string]int, len(keys)+len(members))
+	for i, wk := range keys {
+		positions[strings.ToLower(wk.PublicKey)] = i
+	}
+
+	for _, member := range members {
+		pubkey := strings.ToLower(member.PublicKey)
+		i, ok := positions[pubkey]
+		switch {
+		case !ok:
+			positions[pubkey] = len(merged)
+			merged = append(merged, models.WatchedKey{PublicKey: pubkey, Labels: append([]string{}, member.Labels...)})
+		case isCohortOnly(merged[i].Labels):
+			// Member of several cohorts
+			for _, label := range member.Labels {
+				if !contains(merged[i].Labels, label) {
+					merged[i].Labels = append(append([]string{}, merged[i].Labels...), label)
+				}
+			}
+		}
+	}
+	return merged
+}
+
+// contains reports whether a label is in the list
+func contains(labels []string, label string) bool {
+	for _, l := range labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
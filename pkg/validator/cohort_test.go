@@ -0,0 +1,53 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+func TestMergeCohorts(t *testing.T) {
+	keys := []models.WatchedKey{{PublicKey: "0xaa", Labels: []string{"operator:a"}}}
+	members := []models.WatchedKey{
+		{PublicKey: "0xAA", Labels: []string{CohortLabel("lido")}},
+		{PublicKey: "0xbb", Labels: []string{CohortLabel("lido")}},
+		{PublicKey: "0xbb", Labels: []string{CohortLabel("sample")}},
+	}
+
+	merged := MergeCohorts(keys, members)
+	if len(merged) != 2 {
+		t.Fatalf("Expected 2 keys, got %+v", merged)
+	}
+	if len(merged[0].Labels) != 1 {
+		t.Errorf("Expected a watched key to stay out of cohorts, got %v", merged[0].Labels)
+	}
+	if merged[1].PublicKey != "0xbb" || len(merged[1].Labels) != 2 {
+		t.Errorf("Expected 0xbb in both cohorts, got %+v", merged[1])
+	}
+	if len(keys[0].Labels) != 1 {
+		t.Error("Expected the watched keys to be left untouched")
+	}
+}
+
+func TestCohortMembersStayOutOfWatchedScope(t *testing.T) {
+	validators := []models.Validator{{Index: 1}, {Index: 2}}
+	validators[0].Data.Pubkey = "0xaa"
+	validators[1].Data.Pubkey = "0xbb"
+
+	wv := NewWatchedValidators()
+	wv.Update(validators, []models.WatchedKey{
+		{PublicKey: "0xaa"},
+		{PublicKey: "0xbb", Labels: []string{CohortLabel("lido")}},
+	})
+
+	if watched := wv.GetByLabel("scope:watched"); len(watched) != 1 || watched[0].Index != 1 {
+		t.Errorf("Expected only validator 1 in scope:watched, got %d validators", len(watched))
+	}
+	member, _ := wv.Get(2)
+	if !member.Cohort || len(wv.GetByLabel("cohort:lido")) != 1 {
+		t.Errorf("Expected validator 2 to be a cohort member, got %+v", member.Labels)
+	}
+	if own, _ := wv.Get(1); own.Cohort {
+		t.Error("Expected validator 1 to be watched")
+	}
+}
@@ -0,0 +1,74 @@
+package validator
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// Withdrawal credential types, the first byte of the credentials
+const (
+	CredentialsBLS         = "0x00" // BLS withdrawal key, no withdrawals until changed
+	CredentialsExecution   = "0x01" // Execution layer withdrawal address
+	CredentialsCompounding = "0x02" // Compounding (EIP-7251), balances up to 2048 ETH
+)
+
+// CredentialsType returns the type byte of withdrawal credentials, 0x-prefixed
+func CredentialsType(credentials string) string {
+	cleaned := strings.TrimPrefix(strings.ToLower(credentials), "0x")
+	if len(cleaned) < 2 {
+		return CredentialsBLS
+	}
+	return "0x" + cleaned[:2]
+}
+
+// CredentialsChange is a watched validator's withdrawal credentials changing type between two validator
+// loads: a BLS to execution change (0x00 to 0x01) or a compounding upgrade (0x01 to 0x02)
+type CredentialsChange struct {
+	ValidatorIndex models.ValidatorIndex
+	From           string
+	To             string
+	Credentials    string // New withdrawal credentials
+}
+
+// CredentialsTracker remembers the last loaded withdrawal credentials type of every watched validator
+type CredentialsTracker struct {
+	mu    sync.Mutex
+	types map[models.ValidatorIndex]string
+}
+
+// NewCredentialsTracker creates an empty tracker
+func NewCredentialsTracker() *CredentialsTracker {
+	return &CredentialsTracker{types: make(map[models.ValidatorIndex]string)}
+}
+
+// Observe records the credentials types of the watched validators and returns their changes since the
+// previous observation, by validator index; validators seen for the first time have none, and
+// validators no longer watched are forgotten
+func (t *CredentialsTracker) Observe(validators []*WatchedValidator) []CredentialsChange {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var changes []CredentialsChange
+	types := make(map[models.ValidatorIndex]string, len(validators))
+	for _, v := range validators {
+		credentialsType := CredentialsType(v.Data.WithdrawalCredentials)
+		types[v.Index] = credentialsType
+		if prev, ok := t.types[v.Index]; ok && prev != credentialsType {
+			changes = append(changes, CredentialsChange{
+				ValidatorIndex: v.Index,
+				From:           prev,
+				To:             credentialsType,
+				Credentials:    v.Data.WithdrawalCredentials,
+			})
+		}
+	}
+	t.types = types
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].ValidatorIndex < changes[j].ValidatorIndex
+	})
+	return changes
+}
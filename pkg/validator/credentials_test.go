@@ -0,0 +1,57 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+func TestCredentialsType(t *testing.T) {
+	tests := map[string]string{
+		"0x00f50428677c60f997aadeab24aabf7fceaef491c96a52b463ae91f95611cf71": CredentialsBLS,
+		"0x010000000000000000000000c02aaa39b223fe8d0a0e5c4f27ead9083c756cc2": CredentialsExecution,
+		"0x020000000000000000000000C02AAA39B223FE8D0A0E5C4F27EAD9083C756CC2": CredentialsCompounding,
+		"": CredentialsBLS,
+	}
+	for credentials, want := range tests {
+		if got := CredentialsType(credentials); got != want {
+			t.Errorf("CredentialsType(%q) = %q, want %q", credentials, got, want)
+		}
+	}
+}
+
+func TestCredentialsTracker(t *testing.T) {
+	tracker := NewCredentialsTracker()
+	watched := func(credentials ...string) []*WatchedValidator {
+		validators := make([]*WatchedValidator, len(credentials))
+		for i, c := range credentials {
+			v := &WatchedValidator{Validator: models.Validator{Index: models.ValidatorIndex(i)}}
+			v.Data.WithdrawalCredentials = c
+			validators[i] = v
+		}
+		return validators
+	}
+	bls := "0x00f50428677c60f997aadeab24aabf7fceaef491c96a52b463ae91f95611cf71"
+	execution := "0x010000000000000000000000c02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"
+	compounding := "0x020000000000000000000000c02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"
+
+	if changes := tracker.Observe(watched(bls, execution)); len(changes) != 0 {
+		t.Errorf("Expected no changes on the first load, got %+v", changes)
+	}
+
+	changes := tracker.Observe(watched(execution, compounding, bls))
+	if len(changes) != 2 {
+		t.Fatalf("Expected 2 changes, got %+v", changes)
+	}
+	if changes[0].ValidatorIndex != 0 || changes[0].From != CredentialsBLS || changes[0].To != CredentialsExecution || changes[0].Credentials != execution {
+		t.Errorf("Expected a BLS to execution change of validator 0, got %+v", changes[0])
+	}
+	if changes[1].ValidatorIndex != 1 || changes[1].From != CredentialsExecution || changes[1].To != CredentialsCompounding {
+		t.Errorf("Expected a compounding upgrade of validator 1, got %+v", changes[1])
+	}
+
+	// An unchanged type is no change; validator 2 was new in the previous load and is gone now
+	if changes := tracker.Observe(watched(execution, compounding)); len(changes) != 0 {
+		t.Errorf("Expected no changes, got %+v", changes)
+	}
+}
@@ -0,0 +1,140 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// IndexCache caches pubkey → validator index resolutions
+// Indices never change once assigned, so resolutions are kept in memory and,
+// when a path is configured, persisted to disk for subsequent startups
+type IndexCache struct {
+	mu      sync.RWMutex
+	path    string
+	indices map[string]models.ValidatorIndex
+	dirty   bool
+}
+
+// NewIndexCache creates an index cache, persisted to path if non-empty
+func NewIndexCache(path string) *IndexCache {
+	return &IndexCache{
+		path:    path,
+		indices: make(map[string]models.ValidatorIndex),
+	}
+}
+
+// Load reads persisted resolutions from disk (a missing file is not an error)
+func (c *IndexCache) Load() error {
+	if c.path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read index cache: %w", err)
+	}
+
+	var indices map[string]models.ValidatorIndex
+	if err := json.Unmarshal(data, &indices); err != nil {
+		return fmt.Errorf("failed to parse index cache: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for pubkey, index := range indices {
+		c.indices[pubkey] = index
+	}
+	return nil
+}
+
+// Save persists resolutions to disk if anything changed since the last save
+func (c *IndexCache) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.path == "" || !c.dirty {
+		return nil
+	}
+
+	data, err := json.Marshal(c.indices)
+	if err != nil {
+		return fmt.Errorf("failed to marshal index cache: %w", err)
+	}
+
+	// Write to a temporary file first so a crash never leaves a truncated cache
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), ".index-cache-*")
+	if err != nil {
+		return fmt.Errorf("failed to create index cache file: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write index cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write index cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to replace index cache: %w", err)
+	}
+
+	c.dirty = false
+	return nil
+}
+
+// Get returns the cached index for a pubkey
+func (c *IndexCache) Get(pubkey string) (models.ValidatorIndex, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	index, ok := c.indices[pubkey]
+	return index, ok
+}
+
+// Set records the index for a pubkey
+func (c *IndexCache) Set(pubkey string, index models.ValidatorIndex) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if existing, ok := c.indices[pubkey]; ok && existing == index {
+		return
+	}
+	c.indices[pubkey] = index
+	c.dirty = true
+}
+
+// Resolve splits pubkeys into those with a cached index and those still unresolved
+func (c *IndexCache) Resolve(pubkeys []string) (map[string]models.ValidatorIndex, []string) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	resolved := make(map[string]models.ValidatorIndex, len(pubkeys))
+	unresolved := make([]string, 0)
+	for _, pubkey := range pubkeys {
+		if index, ok := c.indices[pubkey]; ok {
+			resolved[pubkey] = index
+		} else {
+			unresolved = append(unresolved, pubkey)
+		}
+	}
+	return resolved, unresolved
+}
+
+// Count returns the number of cached resolutions
+func (c *IndexCache) Count() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return len(c.indices)
+}
@@ -0,0 +1,57 @@
+package validator
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestIndexCacheResolve(t *testing.T) {
+	cache := NewIndexCache("")
+	cache.Set("0xaaa", 100)
+	cache.Set("0xbbb", 200)
+
+	resolved, unresolved := cache.Resolve([]string{"0xaaa", "0xbbb", "0xccc"})
+
+	if len(resolved) != 2 {
+		t.Errorf("Expected 2 resolved pubkeys, got %d", len(resolved))
+	}
+	if resolved["0xbbb"] != 200 {
+		t.Errorf("Expected index 200 for 0xbbb, got %d", resolved["0xbbb"])
+	}
+	if len(unresolved) != 1 || unresolved[0] != "0xccc" {
+		t.Errorf("Expected 0xccc to be unresolved, got %v", unresolved)
+	}
+}
+
+func TestIndexCachePersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "indices.json")
+
+	cache := NewIndexCache(path)
+	cache.Set("0xaaa", 100)
+	if err := cache.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	reloaded := NewIndexCache(path)
+	if err := reloaded.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	index, ok := reloaded.Get("0xaaa")
+	if !ok {
+		t.Fatal("Expected persisted pubkey to be found")
+	}
+	if index != 100 {
+		t.Errorf("Expected index 100, got %d", index)
+	}
+}
+
+func TestIndexCacheLoadMissingFile(t *testing.T) {
+	cache := NewIndexCache(filepath.Join(t.TempDir(), "missing.json"))
+	if err := cache.Load(); err != nil {
+		t.Errorf("Expected missing cache file to be ignored, got %v", err)
+	}
+	if cache.Count() != 0 {
+		t.Errorf("Expected empty cache, got %d entries", cache.Count())
+	}
+}
@@ -0,0 +1,43 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LabelSeparator separates the levels of a hierarchical label, e.g. operator:acme/cluster:eu/node:3
+const LabelSeparator = "/"
+
+// ExpandLabels adds the ancestors of hierarchical labels after the configured labels, so every
+// level gets metrics of its own while the configured labels keep their order and the first one
+// stays the key's primary label: operator:acme/cluster:eu also labels the key operator:acme
+// Labels given more than once, directly or as an ancestor, are kept once
+func ExpandLabels(labels []string) []string {
+	expanded := make([]string, 0, len(labels))
+	seen := make(map[string]bool, len(labels))
+	for _, label := range labels {
+		if !seen[label] {
+			seen[label] = true
+			expanded = append(expanded, label)
+		}
+	}
+	for _, label := range labels {
+		for i := 0; i < len(label); i++ {
+			if strings.HasPrefix(label[i:], LabelSeparator) && !seen[label[:i]] {
+				seen[label[:i]] = true
+				expanded = append(expanded, label[:i])
+			}
+		}
+	}
+	return expanded
+}
+
+// ValidateLabel checks that no level of a hierarchical label is empty
+func ValidateLabel(label string) error {
+	for _, level := range strings.Split(label, LabelSeparator) {
+		if level == "" {
+			return fmt.Errorf("label %q has an empty level", label)
+		}
+	}
+	return nil
+}
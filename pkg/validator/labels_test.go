@@ -0,0 +1,34 @@
+package validator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExpandLabels(t *testing.T) {
+	got := ExpandLabels([]string{"operator:acme/cluster:eu/node:3", "region:eu", "operator:acme/cluster:us", "operator:acme"})
+	expected := []string{
+		"operator:acme/cluster:eu/node:3",
+		"region:eu",
+		"operator:acme/cluster:us",
+		"operator:acme",
+		"operator:acme/cluster:eu",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("ExpandLabels() = %v, want %v", got, expected)
+	}
+}
+
+func TestValidateLabel(t *testing.T) {
+	for label, valid := range map[string]bool{
+		"operator:acme":             true,
+		"operator:acme/cluster:eu":  true,
+		"operator:acme/":            false,
+		"/cluster:eu":               false,
+		"operator:acme//cluster:eu": false,
+	} {
+		if err := ValidateLabel(label); (err == nil) != valid {
+			t.Errorf("ValidateLabel(%q) = %v", label, err)
+		}
+	}
+}
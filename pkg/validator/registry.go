@@ -2,16 +2,21 @@ package validator
 
 import (
 	"fmt"
+	"math/rand"
 	"sync"
 
 	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
 )
 
+// CanaryLabel marks a watched validator as a canary: any single missed duty pages
+const CanaryLabel = "canary"
+
 // WatchedValidator represents a validator being watched with its labels
 type WatchedValidator struct {
 	models.Validator
 	Labels                   []string
-	Weight                   float64 // effective_balance / 32 ETH
+	Weight                   float64 // effective_balance / stake unit (32 ETH on mainnet)
+	Cohort                   bool    // Only tracked as a member of comparison cohorts (see CohortPrefix)
 	MissedAttestations       uint64
 	SuboptimalSourceVotes    uint64
 	SuboptimalTargetVotes    uint64
@@ -26,6 +31,31 @@ type WatchedValidator struct {
 	AttestationDuties        uint64
 	AttestationDutiesSuccess uint64
 	ConsecutiveMissedAttest  uint64
+
+	// Aggregation: selection needs the validator's slot signature, so only the
+	// expected number of selections and the on-chain fate of its committee aggregate are known
+	ExpectedAggregations        float64
+	CommitteeAggregatesIncluded uint64
+	CommitteeAggregatesMissed   uint64
+
+	// Inclusion delay: slots between an attestation and the block that first included it
+	InclusionDelaySum   uint64
+	InclusionDelayCount uint64
+	MaxInclusionDelay   uint64
+
+	// Sync committee: blocks whose sync aggregate the member signed at all of its positions, or didn't
+	SyncCommitteeSigned uint64
+	SyncCommitteeMissed uint64
+}
+
+// IsCanary reports whether the validator is labelled as a canary
+func (wv *WatchedValidator) IsCanary() bool {
+	for _, label := range wv.Labels {
+		if label == CanaryLabel {
+			return true
+		}
+	}
+	return false
 }
 
 // AllValidators represents the full validator set (2M+)
@@ -45,20 +75,43 @@ func NewAllValidators() *AllValidators {
 
 // Update updates the full validator set
 func (av *AllValidators) Update(validators []models.Validator) {
-	av.mu.Lock()
-	defer av.mu.Unlock()
+	builder := NewAllValidatorsBuilder(len(validators))
+	for i := range validators {
+		builder.Add(validators[i])
+	}
+	av.Replace(builder)
+}
 
-	// Clear old data
-	av.validators = make(map[models.ValidatorIndex]*models.Validator, len(validators))
-	av.pubkeyMap = make(map[string]models.ValidatorIndex, len(validators))
+// AllValidatorsBuilder builds a new full validator set one validator at a time,
+// so a streamed load never needs the whole set as a slice
+type AllValidatorsBuilder struct {
+	validators map[models.ValidatorIndex]*models.Validator
+	pubkeyMap  map[string]models.ValidatorIndex
+}
 
-	for i := range validators {
-		v := &validators[i]
-		av.validators[v.Index] = v
-		av.pubkeyMap[v.Data.Pubkey] = v.Index
+// NewAllValidatorsBuilder creates a builder sized for about sizeHint validators
+func NewAllValidatorsBuilder(sizeHint int) *AllValidatorsBuilder {
+	return &AllValidatorsBuilder{
+		validators: make(map[models.ValidatorIndex]*models.Validator, sizeHint),
+		pubkeyMap:  make(map[string]models.ValidatorIndex, sizeHint),
 	}
 }
 
+// Add adds a validator to the set being built
+func (b *AllValidatorsBuilder) Add(v models.Validator) {
+	b.validators[v.Index] = &v
+	b.pubkeyMap[v.Data.Pubkey] = v.Index
+}
+
+// Replace swaps in a built set; the previous set is served until then
+func (av *AllValidators) Replace(b *AllValidatorsBuilder) {
+	av.mu.Lock()
+	defer av.mu.Unlock()
+
+	av.validators = b.validators
+	av.pubkeyMap = b.pubkeyMap
+}
+
 // Get retrieves a validator by index
 func (av *AllValidators) Get(index models.ValidatorIndex) (*models.Validator, bool) {
 	av.mu.RLock()
@@ -101,12 +154,49 @@ func (av *AllValidators) GetAll() []models.Validator {
 	return result
 }
 
+// Sample returns up to n distinct validators drawn at random among those keep accepts
+// Indices are drawn rather than the set scanned, so a sample costs about n lookups whatever the set's size
+func (av *AllValidators) Sample(n int, keep func(*models.Validator) bool) []models.Validator {
+	av.mu.RLock()
+	defer av.mu.RUnlock()
+
+	count := len(av.validators)
+	if count == 0 || n <= 0 {
+		return nil
+	}
+
+	drawn := make(map[models.ValidatorIndex]bool, n)
+	sample := make([]models.Validator, 0, n)
+	for attempts := 0; len(sample) < n && len(drawn) < count && attempts < 4*n; attempts++ {
+		index := models.ValidatorIndex(rand.Intn(count))
+		if drawn[index] {
+			continue
+		}
+		drawn[index] = true
+		if v, ok := av.validators[index]; ok && keep(v) {
+			sample = append(sample, *v)
+		}
+	}
+	return sample
+}
+
+// Each calls fn for every validator without copying the set; fn must not keep or modify the validator
+func (av *AllValidators) Each(fn func(*models.Validator)) {
+	av.mu.RLock()
+	defer av.mu.RUnlock()
+
+	for _, v := range av.validators {
+		fn(v)
+	}
+}
+
 // WatchedValidators represents the registry of watched validators
 type WatchedValidators struct {
 	mu         sync.RWMutex
 	validators map[models.ValidatorIndex]*WatchedValidator
 	pubkeyMap  map[string]models.ValidatorIndex
 	labels     map[string][]models.ValidatorIndex // label -> validator indices
+	stakeUnit  models.Gwei                        // Effective balance of a full validator
 }
 
 // NewWatchedValidators creates a new watched validators registry
@@ -115,14 +205,50 @@ func NewWatchedValidators() *WatchedValidators {
 		validators: make(map[models.ValidatorIndex]*WatchedValidator),
 		pubkeyMap:  make(map[string]models.ValidatorIndex),
 		labels:     make(map[string][]models.ValidatorIndex),
+		stakeUnit:  models.DefaultStakeUnit,
 	}
 }
 
+// SetStakeUnit sets the effective balance of a full validator, which weights are expressed in
+// It applies from the next Update or Reconcile
+func (wv *WatchedValidators) SetStakeUnit(stakeUnit models.Gwei) {
+	wv.mu.Lock()
+	defer wv.mu.Unlock()
+
+	wv.stakeUnit = stakeUnit
+}
+
 // Update updates the watched validators from API data
 func (wv *WatchedValidators) Update(validators []models.Validator, config []models.WatchedKey) error {
 	wv.mu.Lock()
 	defer wv.mu.Unlock()
 
+	wv.rebuild(validators, config)
+	return nil
+}
+
+// Reconcile applies a changed watch list like Update, but validators that stay watched
+// keep their accumulated counters (used on config reload)
+func (wv *WatchedValidators) Reconcile(validators []models.Validator, config []models.WatchedKey) {
+	wv.mu.Lock()
+	defer wv.mu.Unlock()
+
+	previous := wv.validators
+	wv.rebuild(validators, config)
+
+	for index, watched := range wv.validators {
+		prev, ok := previous[index]
+		if !ok || prev.Data.Pubkey != watched.Data.Pubkey {
+			continue
+		}
+		validatorData, labels, weight, cohort := watched.Validator, watched.Labels, watched.Weight, watched.Cohort
+		*watched = *prev
+		watched.Validator, watched.Labels, watched.Weight, watched.Cohort = validatorData, labels, weight, cohort
+	}
+}
+
+// rebuild replaces the registry content; the caller holds the lock
+func (wv *WatchedValidators) rebuild(validators []models.Validator, config []models.WatchedKey) {
 	// Build pubkey to config map
 	configMap := make(map[string]models.WatchedKey)
 	for _, wk := range config {
@@ -140,17 +266,23 @@ func (wv *WatchedValidators) Update(validators []models.Validator, config []mode
 			continue
 		}
 
-		// Calculate weight (effective balance / 32 ETH)
-		weight := float64(v.Data.EffectiveBalance) / 32_000_000_000.0
+		// Calculate weight (effective balance / stake unit)
+		weight := models.StakeWeight(v.Data.EffectiveBalance, wv.stakeUnit)
 
-		// Build labels (always include scope labels)
+		// Build labels (always include scope labels); cohort members aren't part of scope:watched
+		// Hierarchical labels bring their ancestors, so every level is aggregated
+		cohort := isCohortOnly(cfg.Labels)
 		labels := []string{"scope:all-network", "scope:watched"}
-		labels = append(labels, cfg.Labels...)
+		if cohort {
+			labels = labels[:1]
+		}
+		labels = append(labels, ExpandLabels(cfg.Labels)...)
 
 		watched := &WatchedValidator{
 			Validator: v,
 			Labels:    labels,
 			Weight:    weight,
+			Cohort:    cohort,
 		}
 
 		wv.validators[v.Index] = watched
@@ -166,8 +298,6 @@ func (wv *WatchedValidators) Update(validators []models.Validator, config []mode
 	for idx := range wv.validators {
 		wv.labels["scope:network"] = append(wv.labels["scope:network"], idx)
 	}
-
-	return nil
 }
 
 // Get retrieves a watched validator by index
@@ -257,25 +387,49 @@ func (wv *WatchedValidators) UpdateMetrics(index models.ValidatorIndex, fn func(
 	return nil
 }
 
+// SetFutureProposals sets how many scheduled proposals each validator has; validators not in
+// counts have none
+func (wv *WatchedValidators) SetFutureProposals(counts map[models.ValidatorIndex]uint64) {
+	wv.mu.Lock()
+	defer wv.mu.Unlock()
+
+	for index, v := range wv.validators {
+		v.FutureBlockProposals = counts[index]
+	}
+}
+
 // ResetMetrics resets all metrics for all validators
 func (wv *WatchedValidators) ResetMetrics() {
 	wv.mu.Lock()
 	defer wv.mu.Unlock()
 
 	for _, v := range wv.validators {
-		v.MissedAttestations = 0
-		v.SuboptimalSourceVotes = 0
-		v.SuboptimalTargetVotes = 0
-		v.SuboptimalHeadVotes = 0
-		v.IdealConsensusRewards = 0
-		v.ConsensusRewards = 0
-		v.ProposedBlocks = 0
-		v.ProposedBlocksFinalized = 0
-		v.MissedBlocks = 0
-		v.MissedBlocksFinalized = 0
-		v.FutureBlockProposals = 0
-		v.AttestationDuties = 0
-		v.AttestationDutiesSuccess = 0
-		v.ConsecutiveMissedAttest = 0
+		resetCounters(v)
 	}
 }
+
+// resetCounters zeroes a validator's counters
+func resetCounters(v *WatchedValidator) {
+	v.MissedAttestations = 0
+	v.SuboptimalSourceVotes = 0
+	v.SuboptimalTargetVotes = 0
+	v.SuboptimalHeadVotes = 0
+	v.IdealConsensusRewards = 0
+	v.ConsensusRewards = 0
+	v.ProposedBlocks = 0
+	v.ProposedBlocksFinalized = 0
+	v.MissedBlocks = 0
+	v.MissedBlocksFinalized = 0
+	v.FutureBlockProposals = 0
+	v.AttestationDuties = 0
+	v.AttestationDutiesSuccess = 0
+	v.ConsecutiveMissedAttest = 0
+	v.ExpectedAggregations = 0
+	v.CommitteeAggregatesIncluded = 0
+	v.CommitteeAggregatesMissed = 0
+	v.InclusionDelaySum = 0
+	v.InclusionDelayCount = 0
+	v.MaxInclusionDelay = 0
+	v.SyncCommitteeSigned = 0
+	v.SyncCommitteeMissed = 0
+}
@@ -1,6 +1,7 @@
 package validator
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
@@ -162,6 +163,29 @@ func TestWatchedValidatorsGetByLabel(t *testing.T) {
 	}
 }
 
+func TestWatchedValidatorIsCanary(t *testing.T) {
+	wv := NewWatchedValidators()
+
+	validators := []models.Validator{{Index: 100}, {Index: 200}}
+	validators[0].Data.Pubkey = "0xabc123"
+	validators[1].Data.Pubkey = "0xdef456"
+
+	wv.Update(validators, []models.WatchedKey{
+		{PublicKey: "0xabc123", Labels: []string{"operator:a", CanaryLabel}},
+		{PublicKey: "0xdef456", Labels: []string{"operator:a"}},
+	})
+
+	if v, _ := wv.Get(100); !v.IsCanary() {
+		t.Error("Expected validator 100 to be a canary")
+	}
+	if v, _ := wv.Get(200); v.IsCanary() {
+		t.Error("Expected validator 200 not to be a canary")
+	}
+	if canaries := wv.GetByLabel(CanaryLabel); len(canaries) != 1 {
+		t.Errorf("Expected 1 canary, got %d", len(canaries))
+	}
+}
+
 func TestWatchedValidatorsUpdateMetrics(t *testing.T) {
 	wv := NewWatchedValidators()
 
@@ -270,3 +294,103 @@ func TestAllValidatorsConcurrency(t *testing.T) {
 		<-done
 	}
 }
+
+func TestWatchedValidatorsReconcile(t *testing.T) {
+	wv := NewWatchedValidators()
+
+	validators := []models.Validator{{Index: 1}, {Index: 2}, {Index: 3}}
+	validators[0].Data.Pubkey = "0xaaa"
+	validators[1].Data.Pubkey = "0xbbb"
+	validators[2].Data.Pubkey = "0xccc"
+
+	if err := wv.Update(validators[:2], []models.WatchedKey{
+		{PublicKey: "0xaaa", Labels: []string{"operator:a"}},
+		{PublicKey: "0xbbb"},
+	}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	wv.UpdateMetrics(1, func(v *WatchedValidator) { v.MissedAttestations = 3 })
+	wv.UpdateMetrics(2, func(v *WatchedValidator) { v.MissedAttestations = 1 })
+
+	// 0xbbb removed, 0xccc added, 0xaaa relabeled
+	wv.Reconcile([]models.Validator{validators[0], validators[2]}, []models.WatchedKey{
+		{PublicKey: "0xaaa", Labels: []string{"operator:b"}},
+		{PublicKey: "0xccc"},
+	})
+
+	if wv.Count() != 2 {
+		t.Fatalf("Expected 2 validators, got %d", wv.Count())
+	}
+	if _, ok := wv.Get(2); ok {
+		t.Error("Expected removed validator to be dropped")
+	}
+
+	kept, _ := wv.Get(1)
+	if kept.MissedAttestations != 3 {
+		t.Errorf("Expected kept validator to keep its counters, got %d missed", kept.MissedAttestations)
+	}
+	if len(wv.GetByLabel("operator:b")) != 1 || len(wv.GetByLabel("operator:a")) != 0 {
+		t.Error("Expected kept validator to take its new labels")
+	}
+
+	added, _ := wv.Get(3)
+	if added.MissedAttestations != 0 {
+		t.Errorf("Expected added validator to start from zero, got %d missed", added.MissedAttestations)
+	}
+}
+
+func TestWatchedValidatorsSetFutureProposals(t *testing.T) {
+	wv := NewWatchedValidators()
+
+	validators := []models.Validator{{Index: 100}, {Index: 101}}
+	validators[0].Data.Pubkey = "0xabc123"
+	validators[1].Data.Pubkey = "0xdef456"
+	wv.Update(validators, []models.WatchedKey{{PublicKey: "0xabc123"}, {PublicKey: "0xdef456"}})
+
+	wv.SetFutureProposals(map[models.ValidatorIndex]uint64{100: 2, 101: 1})
+	wv.SetFutureProposals(map[models.ValidatorIndex]uint64{100: 1})
+
+	if v, _ := wv.Get(100); v.FutureBlockProposals != 1 {
+		t.Errorf("Expected 1 future proposal for validator 100, got %d", v.FutureBlockProposals)
+	}
+	if v, _ := wv.Get(101); v.FutureBlockProposals != 0 {
+		t.Errorf("Expected no future proposal left for validator 101, got %d", v.FutureBlockProposals)
+	}
+}
+
+func TestAllValidatorsSample(t *testing.T) {
+	av := NewAllValidators()
+	validators := make([]models.Validator, 100)
+	for i := range validators {
+		validators[i].Index = models.ValidatorIndex(i)
+		validators[i].Data.Pubkey = fmt.Sprintf("0x%02x", i)
+		validators[i].Status = models.StatusActiveOngoing
+		if i%2 == 1 {
+			validators[i].Status = models.StatusExitedUnslashed
+		}
+	}
+	av.Update(validators)
+
+	active := func(v *models.Validator) bool { return v.Status == models.StatusActiveOngoing }
+	sample := av.Sample(10, active)
+	if len(sample) == 0 || len(sample) > 10 {
+		t.Fatalf("Expected up to 10 sampled validators, got %d", len(sample))
+	}
+	seen := make(map[models.ValidatorIndex]bool)
+	for _, v := range sample {
+		if v.Status != models.StatusActiveOngoing {
+			t.Errorf("Expected only active validators, got %d (%s)", v.Index, v.Status)
+		}
+		if seen[v.Index] {
+			t.Errorf("Expected distinct validators, got %d twice", v.Index)
+		}
+		seen[v.Index] = true
+	}
+
+	if sample := av.Sample(1000, active); len(sample) > 50 {
+		t.Errorf("Expected at most the 50 active validators, got %d", len(sample))
+	}
+	if sample := NewAllValidators().Sample(10, active); len(sample) != 0 {
+		t.Errorf("Expected an empty sample of an empty set, got %d", len(sample))
+	}
+}
@@ -0,0 +1,39 @@
+package validator
+
+import "fmt"
+
+// ResetPolicy is when the cumulative per-validator counters go back to zero
+type ResetPolicy string
+
+const (
+	ResetNever  ResetPolicy = "never"  // Counters only grow, across restarts with a state file
+	ResetEpoch  ResetPolicy = "epoch"  // Counters cover the current epoch (default)
+	ResetDay    ResetPolicy = "day"    // Counters cover the current UTC day of chain time
+	ResetReload ResetPolicy = "reload" // Counters reset when a reload is requested or changes the watched keys
+)
+
+// ParseResetPolicy validates a configured reset policy; empty is the default
+func ParseResetPolicy(name string) (ResetPolicy, error) {
+	switch policy := ResetPolicy(name); policy {
+	case "":
+		return ResetEpoch, nil
+	case ResetNever, ResetEpoch, ResetDay, ResetReload:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown reset policy %q (never, epoch, day or reload)", name)
+	}
+}
+
+// ResetCounters zeroes the cumulative counters of every validator
+// Unlike ResetMetrics, runs of consecutive missed attestations and scheduled proposals carry on:
+// they are a state, not a count
+func (wv *WatchedValidators) ResetCounters() {
+	wv.mu.Lock()
+	defer wv.mu.Unlock()
+
+	for _, v := range wv.validators {
+		streak, future := v.ConsecutiveMissedAttest, v.FutureBlockProposals
+		resetCounters(v)
+		v.ConsecutiveMissedAttest, v.FutureBlockProposals = streak, future
+	}
+}
@@ -0,0 +1,47 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+func TestParseResetPolicy(t *testing.T) {
+	if policy, err := ParseResetPolicy(""); err != nil || policy != ResetEpoch {
+		t.Errorf("Expected the epoch policy by default, got %q (%v)", policy, err)
+	}
+	if policy, err := ParseResetPolicy("day"); err != nil || policy != ResetDay {
+		t.Errorf("Expected the day policy, got %q (%v)", policy, err)
+	}
+	if _, err := ParseResetPolicy("hourly"); err == nil {
+		t.Error("Expected an unknown policy to be rejected")
+	}
+}
+
+func TestWatchedValidatorsResetCounters(t *testing.T) {
+	wv := NewWatchedValidators()
+
+	validators := []models.Validator{{Index: 100, Status: models.StatusActiveOngoing}}
+	validators[0].Data.Pubkey = "0xabc123"
+	wv.Update(validators, []models.WatchedKey{{PublicKey: "0xabc123"}})
+
+	wv.UpdateMetrics(100, func(v *WatchedValidator) {
+		v.AttestationDuties = 4
+		v.MissedAttestations = 2
+		v.ConsecutiveMissedAttest = 2
+		v.FutureBlockProposals = 1
+	})
+
+	wv.ResetCounters()
+
+	v, _ := wv.Get(100)
+	if v.AttestationDuties != 0 || v.MissedAttestations != 0 {
+		t.Errorf("Expected the counters to be reset, got %d duties and %d misses", v.AttestationDuties, v.MissedAttestations)
+	}
+	if v.ConsecutiveMissedAttest != 2 {
+		t.Errorf("Expected the streak of 2 missed attestations to carry on, got %d", v.ConsecutiveMissedAttest)
+	}
+	if v.FutureBlockProposals != 1 {
+		t.Errorf("Expected the scheduled proposal to carry on, got %d", v.FutureBlockProposals)
+	}
+}
@@ -0,0 +1,56 @@
+package validator
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// StatusTransition is a watched validator's beacon status changing between two validator loads
+// Loads are an epoch apart, so a transition may skip statuses the validator went through in between
+type StatusTransition struct {
+	ValidatorIndex models.ValidatorIndex
+	From           models.ValidatorStatus
+	To             models.ValidatorStatus
+}
+
+// ExitInitiated reports whether the validator left active_ongoing without being slashed: someone
+// signed a voluntary exit or triggered one from the withdrawal address
+func (t StatusTransition) ExitInitiated() bool {
+	return t.From == models.StatusActiveOngoing && t.To != models.StatusActiveSlashed && t.To != models.StatusExitedSlashed
+}
+
+// StatusTracker remembers the last loaded status of every watched validator
+type StatusTracker struct {
+	mu       sync.Mutex
+	statuses map[models.ValidatorIndex]models.ValidatorStatus
+}
+
+// NewStatusTracker creates an empty tracker
+func NewStatusTracker() *StatusTracker {
+	return &StatusTracker{statuses: make(map[models.ValidatorIndex]models.ValidatorStatus)}
+}
+
+// Observe records the statuses of the watched validators and returns their transitions since the
+// previous observation, by validator index; validators seen for the first time have none, and
+// validators no longer watched are forgotten
+func (t *StatusTracker) Observe(validators []*WatchedValidator) []StatusTransition {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var transitions []StatusTransition
+	statuses := make(map[models.ValidatorIndex]models.ValidatorStatus, len(validators))
+	for _, v := range validators {
+		statuses[v.Index] = v.Status
+		if prev, ok := t.statuses[v.Index]; ok && prev != v.Status {
+			transitions = append(transitions, StatusTransition{ValidatorIndex: v.Index, From: prev, To: v.Status})
+		}
+	}
+	t.statuses = statuses
+
+	sort.Slice(transitions, func(i, j int) bool {
+		return transitions[i].ValidatorIndex < transitions[j].ValidatorIndex
+	})
+	return transitions
+}
@@ -0,0 +1,43 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+func TestStatusTracker(t *testing.T) {
+	tracker := NewStatusTracker()
+	watched := func(statuses ...models.ValidatorStatus) []*WatchedValidator {
+		validators := make([]*WatchedValidator, len(statuses))
+		for i, status := range statuses {
+			validators[i] = &WatchedValidator{Validator: models.Validator{Index: models.ValidatorIndex(i), Status: status}}
+		}
+		return validators
+	}
+
+	if transitions := tracker.Observe(watched(models.StatusActiveOngoing, models.StatusActiveOngoing)); len(transitions) != 0 {
+		t.Errorf("Expected no transitions on the first load, got %+v", transitions)
+	}
+
+	transitions := tracker.Observe(watched(models.StatusActiveExiting, models.StatusActiveSlashed, models.StatusActiveOngoing))
+	if len(transitions) != 2 {
+		t.Fatalf("Expected 2 transitions, got %+v", transitions)
+	}
+	if transitions[0].From != models.StatusActiveOngoing || transitions[0].To != models.StatusActiveExiting || !transitions[0].ExitInitiated() {
+		t.Errorf("Expected validator 0 to initiate an exit, got %+v", transitions[0])
+	}
+	if transitions[1].ValidatorIndex != 1 || transitions[1].ExitInitiated() {
+		t.Errorf("Expected validator 1 to be slashed rather than exit, got %+v", transitions[1])
+	}
+
+	// A skipped status still counts as the exit it implies
+	exited := StatusTransition{From: models.StatusActiveOngoing, To: models.StatusExitedUnslashed}
+	if !exited.ExitInitiated() {
+		t.Error("Expected active_ongoing to exited_unslashed to be an exit")
+	}
+	done := StatusTransition{From: models.StatusWithdrawalPossible, To: models.StatusWithdrawalDone}
+	if done.ExitInitiated() {
+		t.Error("Expected withdrawal_done not to be an exit initiation")
+	}
+}
@@ -0,0 +1,269 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/alert"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/cron"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/duties"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/events"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/explorer"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/validator"
+	"github.com/sirupsen/logrus"
+)
+
+// notifyTimeout bounds the delivery of a single alert
+const notifyTimeout = 10 * time.Second
+
+// newExplorer returns the explorer of the watched network: its explorer.networks entry, explorer.url,
+// the deprecated discord.explorer_url, then the public network's default; nil when disabled or unknown
+// A {network} in the URL is replaced by the network name, e.g. https://{network}.beaconcha.in
+func newExplorer(cfg *models.Config) *explorer.Explorer {
+	if cfg.Explorer.Disabled {
+		return nil
+	}
+
+	baseURL := cfg.Explorer.Networks[cfg.Network]
+	for _, fallback := range []string{cfg.Explorer.URL, cfg.Discord.ExplorerURL, explorer.DefaultURL(cfg.Network)} {
+		if baseURL == "" {
+			baseURL = fallback
+		}
+	}
+	baseURL = strings.ReplaceAll(baseURL, explorer.NetworkPlaceholder, cfg.Network)
+	return explorer.New(baseURL, explorer.Paths{
+		Validator: cfg.Explorer.ValidatorPath,
+		Slot:      cfg.Explorer.SlotPath,
+		Epoch:     cfg.Explorer.EpochPath,
+		Block:     cfg.Explorer.BlockPath,
+	})
+}
+
+// newNotifier builds the alert channels from the config
+// Alerts are always logged, and also sent to every configured chat and paging channel, with
+// explorer links to the validators, slots and epochs they name
+// Maintenance windows only silence the chat channels, so alerts stay in the log
+// Replay runs only log alerts: reprocessed history must not page anyone with years-old misses
+func newNotifier(cfg *models.Config, links *explorer.Explorer, logger *logrus.Logger) (alert.Notifier, []*alert.Tracked, error) {
+	notifier, tracked, err := newChannels(cfg, logger)
+	if err != nil || links == nil {
+		return notifier, tracked, err
+	}
+	return alert.NewLinker(notifier, links.FieldLinks), tracked, nil
+}
+
+// newChannels builds the log, chat and paging channels; the chat and paging channels are also
+// returned tracked, for the health report
+func newChannels(cfg *models.Config, logger *logrus.Logger) (alert.Notifier, []*alert.Tracked, error) {
+	if cfg.IsReplay() {
+		return alert.Multi{alert.NewLogNotifier(logger)}, nil, nil
+	}
+
+	var chat alert.Multi
+	if cfg.SlackToken != "" && cfg.SlackChannel != "" {
+		chat = append(chat, alert.NewSlackNotifier(cfg.SlackToken, cfg.SlackChannel, notifyTimeout))
+	}
+	if webhooks := discordWebhooks(cfg.Discord); len(webhooks) > 0 {
+		chat = append(chat, alert.NewDiscordNotifier(webhooks, notifyTimeout))
+	}
+	if tg := cfg.Telegram; tg.BotToken != "" {
+		minSeverity := alert.SeverityInfo
+		if tg.MinSeverity != "" {
+			minSeverity = alert.Severity(tg.MinSeverity)
+		}
+		chat = append(chat, alert.NewTelegramNotifier(tg.BotToken, tg.ChatID, minSeverity, notifyTimeout))
+	}
+	if pd := cfg.PagerDuty; pd.RoutingKey != "" {
+		minSeverity := alert.SeverityCritical
+		if pd.MinSeverity != "" {
+			minSeverity = alert.Severity(pd.MinSeverity)
+		}
+		source := fmt.Sprintf("eth-validator-watcher (%s)", cfg.Network)
+		chat = append(chat, alert.NewPagerDutyNotifier(pd.RoutingKey, source, minSeverity, notifyTimeout))
+	}
+
+	tracked := make([]*alert.Tracked, len(chat))
+	for i, notifier := range chat {
+		tracked[i] = alert.Track(notifier)
+		chat[i] = tracked[i]
+	}
+
+	notifiers := alert.Multi{alert.NewLogNotifier(logger)}
+	if len(chat) == 0 {
+		return notifiers, nil, nil
+	}
+	if len(cfg.Silences) == 0 {
+		return append(notifiers, chat...), tracked, nil
+	}
+
+	silences := make([]alert.Silence, 0, len(cfg.Silences))
+	for _, silence := range cfg.Silences {
+		schedule, err := cron.Parse(silence.Schedule, silence.TimeZone)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid silence %s: %w", silence.Name, err)
+		}
+		silences = append(silences, alert.Silence{
+			Name:   silence.Name,
+			Window: cron.Window{Schedule: schedule, Duration: silence.Duration.ToDuration()},
+		})
+	}
+	return append(notifiers, alert.NewSilencer(chat, silences, logger)), tracked, nil
+}
+
+// discordWebhooks routes each severity from min_severity up to its webhook
+func discordWebhooks(cfg models.Discord) map[alert.Severity]string {
+	min := alert.SeverityInfo
+	if cfg.MinSeverity != "" {
+		min = alert.Severity(cfg.MinSeverity)
+	}
+
+	webhooks := make(map[alert.Severity]string)
+	for _, severity := range []alert.Severity{alert.SeverityInfo, alert.SeverityWarning, alert.SeverityCritical} {
+		if !severity.AtLeast(min) {
+			continue
+		}
+		webhook := cfg.SeverityWebhooks[string(severity)]
+		if webhook == "" {
+			webhook = cfg.WebhookURL
+		}
+		if webhook != "" {
+			webhooks[severity] = webhook
+		}
+	}
+	return webhooks
+}
+
+// canaryDuties maps the miss events that page for canaries to their duty name
+var canaryDuties = map[events.Type]string{
+	events.TypeMissedAttestation: "attestation",
+	events.TypeMissedBlock:       "block",
+	events.TypeValidatorNotLive:  "liveness",
+}
+
+// canarySink pages on every missed duty of a canary validator
+// Canaries are spread across the infrastructure, so a single miss is an early
+// signal of a problem the fleet-wide aggregates don't show yet
+type canarySink struct {
+	watcher *ValidatorWatcher
+}
+
+// Write pages if the event is a canary's missed duty
+func (s *canarySink) Write(event events.Event) error {
+	duty, ok := canaryDuties[event.Type]
+	if !ok {
+		return nil
+	}
+
+	w := s.watcher
+	v, ok := w.watchedValidators.Get(event.ValidatorIndex)
+	if !ok || !v.IsCanary() {
+		return nil
+	}
+
+	w.prometheusMetrics.RecordCanaryMiss(w.config.Network, event.Label, duty)
+
+	fields := map[string]string{
+		"network":   w.config.Network,
+		"validator": fmt.Sprintf("%d", w.anonymizer.Index(event.ValidatorIndex)),
+		"pubkey":    w.logPubkey(v.Data.Pubkey),
+		"label":     event.Label,
+		"epoch":     fmt.Sprintf("%d", event.Epoch),
+	}
+	if event.Slot > 0 {
+		fields["slot"] = fmt.Sprintf("%d", event.Slot)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+	defer cancel()
+
+	return w.notifier.Notify(ctx, alert.Alert{
+		Severity: alert.SeverityCritical,
+		Title:    fmt.Sprintf("Canary validator missed %s duty", duty),
+		Text:     fmt.Sprintf("Canary %d (%s) missed a %s duty", w.anonymizer.Index(event.ValidatorIndex), event.Label, duty),
+		Fields:   fields,
+		Key:      fmt.Sprintf("canary:%d:%s", w.anonymizer.Index(event.ValidatorIndex), duty),
+	})
+}
+
+// Close has nothing to release
+func (s *canarySink) Close() error {
+	return nil
+}
+
+// canaryCounts returns the number of canary validators per primary label
+func canaryCounts(watched []*validator.WatchedValidator) map[string]int {
+	counts := make(map[string]int)
+	for _, v := range watched {
+		if v.IsCanary() {
+			counts[primaryLabel(v.Labels)]++
+		}
+	}
+	return counts
+}
+
+// processSlashings alerts on every watched validator slashed by an operation in the block
+// Slashings are alerted even during warmup: they don't depend on duty context
+func (w *ValidatorWatcher) processSlashings(block *models.Block, slot models.Slot) {
+	for _, slashing := range duties.ProcessSlashings(block) {
+		v, ok := w.watchedValidators.Get(slashing.ValidatorIndex)
+		if !ok {
+			continue
+		}
+
+		label := primaryLabel(v.Labels)
+		w.prometheusMetrics.RecordSlashing(w.config.Network, slashing.Kind, label)
+
+		w.events.Emit(events.Event{
+			Type:           events.TypeSlashing,
+			Slot:           slot,
+			Epoch:          w.clock.SlotToEpoch(slot),
+			ValidatorIndex: slashing.ValidatorIndex,
+			Pubkey:         v.Data.Pubkey,
+			Label:          label,
+			Data: map[string]interface{}{
+				"kind":           slashing.Kind,
+				"offence_slot":   slashing.OffenceSlot,
+				"conflict_roots": slashing.Roots,
+				"block_proposer": block.Message.ProposerIndex,
+			},
+		})
+		if v.Cohort {
+			continue // Comparison cohorts are counted, not alerted on
+		}
+
+		fields := map[string]string{
+			"network":         w.config.Network,
+			"validator":       fmt.Sprintf("%d", w.anonymizer.Index(slashing.ValidatorIndex)),
+			"pubkey":          w.logPubkey(v.Data.Pubkey),
+			"label":           label,
+			"kind":            slashing.Kind,
+			"included_slot":   fmt.Sprintf("%d", slot),
+			"included_by":     fmt.Sprintf("%d", block.Message.ProposerIndex),
+			"offence_slot":    fmt.Sprintf("%d", slashing.OffenceSlot),
+			"conflict_root_1": slashing.Roots[0],
+			"conflict_root_2": slashing.Roots[1],
+		}
+
+		// Delivered in the background so a slow channel never delays slot processing
+		go w.sendAlert(alert.Alert{
+			Severity: alert.SeverityCritical,
+			Title:    fmt.Sprintf("Watched validator %d slashed (%s)", w.anonymizer.Index(slashing.ValidatorIndex), slashing.Kind),
+			Text:     fmt.Sprintf("%s slashing included in block at slot %d for offence at slot %d", slashing.Kind, slot, slashing.OffenceSlot),
+			Fields:   fields,
+			Key:      fmt.Sprintf("slashing:%d", w.anonymizer.Index(slashing.ValidatorIndex)),
+		})
+	}
+}
+
+// sendAlert delivers an alert to the configured channels, logging delivery failures
+func (w *ValidatorWatcher) sendAlert(a alert.Alert) {
+	ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+	defer cancel()
+
+	if err := w.notifier.Notify(ctx, a); err != nil {
+		w.logger.WithError(err).WithField("alert", a.Title).Warn("Failed to deliver alert")
+	}
+}
@@ -0,0 +1,98 @@
+package watcher
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/duties"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/events"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/validator"
+	"github.com/sirupsen/logrus"
+)
+
+// settleAttestationDuties counts the one outcome of every watched validator's attestation duty in
+// an epoch: the duty, its success or miss, the run of consecutive misses and the heatmap all come
+// from the same result, so the attestation counters always add up
+func (w *ValidatorWatcher) settleAttestationDuties(epoch models.Epoch, liveness map[models.ValidatorIndex]bool) {
+	w.settleCounterPeriod(epoch)
+	results := w.attestationDuties.Settle(epoch, liveness)
+
+	// During warmup, observe only: duty outcomes are not recorded
+	if w.warmup {
+		return
+	}
+
+	missed := events.NewSampler(w.config.LogSampling.MaxExamples)
+	missedByLabel := make(map[string]int) // Track misses by primary label
+	lateOrUnseen := 0
+	for _, result := range results {
+		v, ok := w.watchedValidators.Get(result.ValidatorIndex)
+		if !ok {
+			continue
+		}
+		performed := result.Outcome.Performed()
+		if result.Outcome == duties.OutcomeLive {
+			lateOrUnseen++
+		}
+
+		var consecutive uint64
+		w.watchedValidators.UpdateMetrics(result.ValidatorIndex, func(wv *validator.WatchedValidator) {
+			wv.AttestationDuties++
+			if performed {
+				wv.AttestationDutiesSuccess++
+				wv.ConsecutiveMissedAttest = 0
+			} else {
+				wv.MissedAttestations++
+				wv.ConsecutiveMissedAttest++
+			}
+			consecutive = wv.ConsecutiveMissedAttest
+		})
+		w.heatmap.Record(result.ValidatorIndex, v.Labels, epoch, !performed)
+		if performed {
+			continue
+		}
+
+		// Full detail goes to the event stream, the log only gets a sample
+		label := primaryLabel(v.Labels)
+		missedByLabel[label]++
+		w.events.Emit(events.Event{
+			Type:           events.TypeMissedAttestation,
+			Slot:           result.Slot,
+			Epoch:          epoch,
+			ValidatorIndex: result.ValidatorIndex,
+			Pubkey:         v.Data.Pubkey,
+			Label:          label,
+			Data: map[string]interface{}{
+				"consecutive_missed": consecutive,
+			},
+		})
+		missed.Add(fmt.Sprintf("v%d (%s, consecutive: %d)", w.anonymizer.Index(result.ValidatorIndex), label, consecutive))
+		w.checkConsecutiveMissed(v, consecutive, result.Slot, epoch)
+	}
+
+	logFields := logrus.Fields{
+		"epoch":             epoch,
+		"duties":            len(results),
+		"live_not_in_block": lateOrUnseen,
+		"liveness_known":    liveness != nil,
+	}
+	missedCount := missed.Count()
+	if missedCount == 0 {
+		w.logger.WithFields(logFields).Debug("✅ Attestation duties settled, none missed")
+		return
+	}
+
+	logFields["missed_count"] = missedCount
+	logFields["miss_rate"] = fmt.Sprintf("%.2f%%", float64(missedCount)*100/float64(len(results)))
+	missed.AddFields(logFields, "examples")
+
+	// Show breakdown by label
+	labelBreakdown := make([]string, 0, len(missedByLabel))
+	for label, count := range missedByLabel {
+		labelBreakdown = append(labelBreakdown, fmt.Sprintf("%s:%d", label, count))
+	}
+	logFields["by_label"] = strings.Join(labelBreakdown, ", ")
+
+	w.logger.WithFields(logFields).Warn("⚠️  MISSED ATTESTATIONS")
+}
@@ -0,0 +1,81 @@
+package watcher
+
+import (
+	"context"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/duties"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/metrics"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// maxBaselineAge is how many epochs a network baseline is exported for: rewards settle two epochs
+// back, so the baseline is missing a third epoch only when its refresh failed
+const maxBaselineAge = 3
+
+// processNetworkBaseline fetches the attestation rewards of network_sample random active validators
+// for an epoch, the baseline the watched validators' participation, votes and rewards compare with
+// A fresh sample is drawn every epoch, so no validator skews the baseline for long
+func (w *ValidatorWatcher) processNetworkBaseline(ctx context.Context, epoch models.Epoch) error {
+	sample := w.allValidators.Sample(w.config.NetworkSample, func(v *models.Validator) bool {
+		return isActiveStatus(v.Status)
+	})
+	if len(sample) == 0 {
+		w.logger.Debug("No network validators loaded yet - skipping the network baseline")
+		return nil
+	}
+
+	balances := make(map[models.ValidatorIndex]models.Gwei, len(sample))
+	indices := make([]models.ValidatorIndex, 0, len(sample))
+	for _, v := range sample {
+		balances[v.Index] = v.Data.EffectiveBalance
+		indices = append(indices, v.Index)
+	}
+
+	rewards, err := w.beaconClient.GetRewards(ctx, epoch, indices)
+	if err != nil {
+		return err
+	}
+	rewardData, err := duties.ProcessRewards(rewards, balances)
+	if err != nil {
+		return err
+	}
+
+	baseline := &metrics.NetworkBaseline{Epoch: epoch, Validators: len(rewardData)}
+	for _, data := range rewardData {
+		if data.ActualSource > 0 {
+			baseline.Participating++
+		}
+		if data.SuboptimalSource {
+			baseline.SuboptimalSource++
+		}
+		if data.SuboptimalTarget {
+			baseline.SuboptimalTarget++
+		}
+		if data.SuboptimalHead {
+			baseline.SuboptimalHead++
+		}
+		baseline.IdealRewards += data.IdealTotal
+		baseline.ActualRewards += data.ActualTotal
+	}
+	w.networkBaseline.Store(baseline)
+	w.prometheusMetrics.SetNetworkSample(w.config.Network, baseline.Validators)
+
+	if baseline.Validators > 0 {
+		w.logger.WithFields(logrus.Fields{
+			"epoch":              epoch,
+			"sampled_validators": baseline.Validators,
+			"participation_rate": float64(baseline.Participating) / float64(baseline.Validators),
+		}).Debug("Updated network attestation baseline")
+	}
+	return nil
+}
+
+// withNetworkBaseline adds the last network baseline to the network-wide metrics, unless it's outdated
+func (w *ValidatorWatcher) withNetworkBaseline(network *metrics.MetricsByLabel, epoch models.Epoch) *metrics.MetricsByLabel {
+	baseline := w.networkBaseline.Load()
+	if baseline == nil || epoch > baseline.Epoch+maxBaselineAge {
+		return network
+	}
+	return network.WithBaseline(baseline)
+}
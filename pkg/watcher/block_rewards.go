@@ -0,0 +1,68 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/validator"
+	"github.com/sirupsen/logrus"
+)
+
+// payloadLookupTimeout bounds the relay lookup of a proposal's execution payload value
+const payloadLookupTimeout = 30 * time.Second
+
+// recordBlockRewards exports the rewards of a watched proposal and returns its consensus layer rewards
+// The execution payload value is looked up on the configured relays in the background, since only
+// relays know what a builder paid; locally built blocks have no known value. The lookup also tells
+// which fee recipient to verify: the bid's for a relayed block, the payload's for a local one
+func (w *ValidatorWatcher) recordBlockRewards(ctx context.Context, block *models.Block, v *validator.WatchedValidator) models.Gwei {
+	slot := block.Message.Slot
+	scopes := w.aggregatedScopes(v.Labels)
+
+	var clRewards models.Gwei
+	rewards, err := w.beaconClient.GetBlockRewards(ctx, fmt.Sprintf("%d", slot))
+	if err != nil {
+		w.logger.WithError(err).WithField("slot", slot).Warn("Failed to get block rewards")
+	} else {
+		clRewards = rewards.Total
+		w.prometheusMetrics.RecordBlockRewards(w.config.Network, scopes, uint64(rewards.Total))
+	}
+
+	payload := block.Message.Body.ExecutionPayload
+	if w.relayClient == nil || payload == nil || payload.BlockHash == "" {
+		return clRewards
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), payloadLookupTimeout)
+		defer cancel()
+
+		delivered, ok, err := w.relayClient.DeliveredPayload(ctx, slot, payload.BlockHash)
+		if err != nil {
+			w.logger.WithError(err).WithField("slot", slot).Warn("Failed to look up delivered payload on relays - fee recipient not verified")
+			return
+		}
+		if !ok {
+			w.logger.WithField("slot", slot).Debug("Block was not delivered by a configured relay - execution payload value unknown")
+			w.verifyFeeRecipient(slot, v, payload.FeeRecipient)
+			return
+		}
+		if delivered.FeeRecipient != "" {
+			w.verifyFeeRecipient(slot, v, delivered.FeeRecipient)
+		}
+
+		wei, _ := new(big.Float).SetInt(delivered.Value).Float64()
+		w.prometheusMetrics.RecordPayloadValue(w.config.Network, scopes, wei)
+		w.logger.WithFields(logrus.Fields{
+			"slot":            slot,
+			"validator_index": w.anonymizer.Index(v.Index),
+			"relay":           delivered.Relay,
+			"value_wei":       delivered.Value.String(),
+		}).Info("Execution payload delivered by relay")
+	}()
+
+	return clRewards
+}
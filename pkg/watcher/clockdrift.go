@@ -0,0 +1,70 @@
+package watcher
+
+import (
+	"context"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/refresh"
+	"github.com/sirupsen/logrus"
+)
+
+// clockDriftInterval is how often the local slot is compared with the beacon node's head
+const clockDriftInterval = time.Minute
+
+// maxClockDriftSlots is the drift logged as a warning: the head trails the local slot by one slot
+// until each block arrives, more means a skewed clock or consecutive missed blocks
+const maxClockDriftSlots = 1
+
+// clockAheadReadings is how many readings in a row the local clock must be ahead by more than
+// maxClockDriftSlots before it's reported: missed blocks put the head behind for a few slots, a
+// skewed clock for good; a head ahead of the local slot is always the local clock lagging
+const clockAheadReadings = 3
+
+// startClockDriftChecks compares the locally computed slot with the slot of the beacon node's head
+// header every minute: a local clock off by a slot shifts every duty deadline and the slots
+// processed, without any request failing
+func (w *ValidatorWatcher) startClockDriftChecks(ctx context.Context) {
+	if w.clock == nil || w.clock.IsReplayMode() {
+		return
+	}
+
+	w.clockDrift = refresh.New("clock_drift", clockDriftInterval, w.measureClockDrift, w.logger)
+	w.clockDrift.Start(ctx)
+}
+
+// measureClockDrift returns the local slot minus the head header's slot, positive when the local
+// clock is ahead; it's skipped while the beacon node syncs and its head is behind anyway
+func (w *ValidatorWatcher) measureClockDrift(ctx context.Context) (int64, error) {
+	if w.syncPaused.Load() {
+		return 0, refresh.ErrSkipped
+	}
+
+	header, err := w.beaconClient.GetHeader(ctx, "head")
+	if err != nil {
+		return 0, err
+	}
+	local := w.clock.CurrentSlot()
+	head := header.Header.Message.Slot
+	drift := int64(local) - int64(head)
+
+	w.prometheusMetrics.SetClockDrift(w.config.Network, drift)
+	if drift > maxClockDriftSlots {
+		w.clockAhead.Add(1)
+	} else {
+		w.clockAhead.Store(0)
+	}
+	if clockDrifting(drift, w.clockAhead.Load()) {
+		w.logger.WithFields(logrus.Fields{
+			"local_slot":  local,
+			"head_slot":   head,
+			"drift_slots": drift,
+		}).Warn("🕰️  Local clock drifts from the beacon node's head - check NTP")
+	}
+	return drift, nil
+}
+
+// clockDrifting reports whether a drift reading points at the local clock rather than at missed
+// blocks, given how many readings in a row the local clock was ahead
+func clockDrifting(drift, aheadReadings int64) bool {
+	return drift < 0 || (drift > maxClockDriftSlots && aheadReadings >= clockAheadReadings)
+}
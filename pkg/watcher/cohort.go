@@ -0,0 +1,50 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/batch"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/validator"
+	"github.com/sirupsen/logrus"
+)
+
+// loadCohortKeys resolves the comparison cohorts to keys labelled cohort:<name> and merges them into
+// the watched keys; members given by index are looked up on the beacon node
+func (w *ValidatorWatcher) loadCohortKeys(ctx context.Context) error {
+	if len(w.config.Cohorts) == 0 {
+		return nil
+	}
+
+	var keys []models.WatchedKey
+	for _, cohort := range w.config.Cohorts {
+		labels := []string{validator.CohortLabel(cohort.Name)}
+		for _, pubkey := range cohort.PublicKeys {
+			keys = append(keys, models.WatchedKey{PublicKey: pubkey, Labels: labels})
+		}
+
+		batchSize := w.config.Startup.BatchSize
+		for i := 0; i < batch.Count(len(cohort.Indices), batchSize); i++ {
+			start := i * batchSize
+			end := min(start+batchSize, len(cohort.Indices))
+			fetched, err := w.beaconClient.GetValidators(ctx, "head", cohort.Indices[start:end])
+			if err != nil {
+				return fmt.Errorf("cohort %s: %w", cohort.Name, err)
+			}
+			for _, v := range fetched {
+				keys = append(keys, models.WatchedKey{PublicKey: v.Data.Pubkey, Labels: labels})
+			}
+		}
+	}
+
+	w.cohortKeys = keys
+	before := len(w.config.WatchedKeys)
+	w.setWatchedKeys(w.mergedWatchedKeys())
+	w.logger.WithFields(logrus.Fields{
+		"cohorts":     len(w.config.Cohorts),
+		"cohort_keys": len(w.config.WatchedKeys) - before,
+	}).Info("Added comparison cohorts to watched keys")
+
+	return nil
+}
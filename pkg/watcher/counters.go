@@ -0,0 +1,88 @@
+package watcher
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/validator"
+	"github.com/sirupsen/logrus"
+)
+
+// counterPeriod names the period the per-validator counters of a settled epoch cover under the reset
+// policy: the counters reset when it changes, and are restored from the state file when it matches
+// Counters that never reset on their own cover a single period
+func (w *ValidatorWatcher) counterPeriod(epoch models.Epoch) string {
+	switch w.resetPolicy {
+	case validator.ResetEpoch:
+		return strconv.FormatUint(uint64(epoch), 10)
+	case validator.ResetDay:
+		return w.clock.SlotStartTime(w.clock.EpochToSlot(epoch)).UTC().Format(time.DateOnly)
+	default:
+		return ""
+	}
+}
+
+// settleCounterPeriod is called as an epoch's attestation duties settle, and resets the counters when
+// the epoch starts a new period: the reset is keyed to the settled epoch rather than the epoch start,
+// so the attestation counters always hold the last settled epoch instead of reading zero until it
+// settles. Rewards, which settle an epoch later, and blocks land in the period they arrive in
+func (w *ValidatorWatcher) settleCounterPeriod(epoch models.Epoch) {
+	if w.countersSettled && epoch > w.countersEpoch {
+		w.closeSettledEpoch(w.countersEpoch)
+	}
+	w.countersSettled = true
+	w.countersEpoch = epoch
+	if w.clock != nil {
+		w.countersSince = w.clock.CurrentSlot()
+	}
+	period := w.counterPeriod(epoch)
+	if period == w.countersPeriod {
+		return
+	}
+	w.countersPeriod = period
+	w.resetCounters(string(w.resetPolicy))
+}
+
+// closeSettledEpoch hands the counters of the last settled epoch to what consumes per-epoch values,
+// once they are complete (its rewards arrived an epoch after its attestations) and before they may reset
+// The first period after a start isn't closed, since the watcher didn't see all of it
+func (w *ValidatorWatcher) closeSettledEpoch(epoch models.Epoch) {
+	watched := w.watchedValidators.GetAll()
+	metricsByLabel := w.labelMetrics(watched, epoch)
+	w.evaluateRules(epoch, metricsByLabel)
+	w.recordTrend(epoch, metricsByLabel)
+	w.writeInflux(epoch, metricsByLabel, watched)
+	w.writeExport(epoch, watched)
+}
+
+// lastSettledEpoch returns the epoch whose attestation duties were last settled as of a slot:
+// the previous epoch from its liveness_slot on, the one before until then
+func (w *ValidatorWatcher) lastSettledEpoch(slot models.Slot) models.Epoch {
+	epoch := w.clock.SlotToEpoch(slot)
+	back := models.Epoch(2)
+	if uint64(slot-w.clock.EpochToSlot(epoch)) >= w.taskSlots.liveness {
+		back = 1
+	}
+	if epoch < back {
+		return 0
+	}
+	return epoch - back
+}
+
+// resetCounters zeroes the per-validator counters and rebases the metrics derived from them
+func (w *ValidatorWatcher) resetCounters(reason string) {
+	w.watchedValidators.ResetCounters()
+	w.prometheusMetrics.RecordCounterReset(w.config.Network, time.Now())
+	if w.exporter != nil {
+		w.exporter.Rebase()
+	}
+	if w.alertRules != nil {
+		w.alertRules.Rebase()
+	}
+
+	w.logger.WithFields(logrus.Fields{
+		"policy": w.resetPolicy,
+		"reason": reason,
+	}).Debug("Reset per-validator counters")
+}
@@ -0,0 +1,39 @@
+package watcher
+
+import (
+	"fmt"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/duties"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// auditCoverage exports the attestation duty coverage of every epoch completed before an epoch
+// Missing duties point at the watcher dropping data, not at the validators
+func (w *ValidatorWatcher) auditCoverage(epoch models.Epoch) {
+	for _, coverage := range w.coverage.Complete(epoch, w.activeWatchedAt) {
+		w.prometheusMetrics.RecordDutyCoverage(w.config.Network, coverage.Percent(), coverage.Missing())
+		if coverage.Missing() == 0 {
+			continue
+		}
+
+		w.logger.WithFields(logrus.Fields{
+			"epoch":     coverage.Epoch,
+			"expected":  coverage.Expected,
+			"evaluated": coverage.Evaluated,
+			"slots":     coverage.Slots,
+			"coverage":  fmt.Sprintf("%.1f%%", coverage.Percent()),
+		}).Warn("Attestation duties not evaluated - the watcher missed data for this epoch")
+	}
+}
+
+// activeWatchedAt counts the watched validators active in an epoch, each owing one attestation duty
+func (w *ValidatorWatcher) activeWatchedAt(epoch models.Epoch) int {
+	active := 0
+	for _, v := range w.watchedValidators.GetAll() {
+		if duties.LiabilityAt(&v.Validator, epoch).Attests() {
+			active++
+		}
+	}
+	return active
+}
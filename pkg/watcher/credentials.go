@@ -0,0 +1,67 @@
+package watcher
+
+import (
+	"fmt"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/alert"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/events"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// observeCredentials reports the withdrawal credentials type changes of watched validators since the
+// previous epoch: a BLS to execution change sets where the balance is withdrawn to and a compounding
+// upgrade stops the partial withdrawals, so each one is counted, emitted as an event and alerted on
+func (w *ValidatorWatcher) observeCredentials(epoch models.Epoch) {
+	for _, change := range w.credentials.Observe(w.watchedValidators.GetAll()) {
+		v, ok := w.watchedValidators.Get(change.ValidatorIndex)
+		if !ok {
+			continue
+		}
+		label := primaryLabel(v.Labels)
+		credentials := w.anonymizer.Credentials(change.Credentials)
+		w.prometheusMetrics.RecordCredentialsChange(w.config.Network, w.aggregatedScopes(v.Labels), change.From, change.To)
+
+		w.events.Emit(events.Event{
+			Type:           events.TypeCredentialsChanged,
+			Epoch:          epoch,
+			ValidatorIndex: v.Index,
+			Pubkey:         v.Data.Pubkey,
+			Label:          label,
+			Data: map[string]interface{}{
+				"from":                   change.From,
+				"to":                     change.To,
+				"withdrawal_credentials": credentials,
+			},
+		})
+
+		w.logger.WithFields(logrus.Fields{
+			"validator_index":        w.anonymizer.Index(v.Index),
+			"pubkey":                 w.logPubkey(v.Data.Pubkey),
+			"label":                  label,
+			"epoch":                  epoch,
+			"from":                   change.From,
+			"to":                     change.To,
+			"withdrawal_credentials": credentials,
+		}).Warn("🔑 Withdrawal credentials changed")
+		if v.Cohort {
+			continue
+		}
+
+		go w.sendAlert(alert.Alert{
+			Severity: alert.SeverityWarning,
+			Title:    fmt.Sprintf("Watched validator %d changed its withdrawal credentials", w.anonymizer.Index(v.Index)),
+			Text: fmt.Sprintf("Validator %d (%s) went from %s to %s withdrawal credentials in epoch %d; they are now %s",
+				w.anonymizer.Index(v.Index), label, change.From, change.To, epoch, credentials),
+			Fields: map[string]string{
+				"network":   w.config.Network,
+				"validator": fmt.Sprintf("%d", w.anonymizer.Index(v.Index)),
+				"pubkey":    w.logPubkey(v.Data.Pubkey),
+				"label":     label,
+				"from":      change.From,
+				"to":        change.To,
+			},
+			Key: fmt.Sprintf("credentials:%d", w.anonymizer.Index(v.Index)),
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package watcher
+
+import (
+	"fmt"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/degrade"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/grafana"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// minDegradationRequests is the fewest requests in an epoch that say anything about the node's load
+const minDegradationRequests = 10
+
+// observeBeaconLoad updates the degradation level from the beacon requests made since the previous epoch
+func (w *ValidatorWatcher) observeBeaconLoad(epoch models.Epoch) {
+	if w.degradation == nil {
+		return
+	}
+
+	var sample degrade.Sample
+	for _, ep := range w.beaconClient.Endpoints() {
+		sample.Requests += ep.Requests
+		sample.Failures += ep.Failures
+		sample.Latency += ep.LatencyTotal
+	}
+
+	level, changed, measured := w.degradation.Observe(sample)
+	w.prometheusMetrics.SetDegradationLevel(w.config.Network, int(level))
+	if !changed {
+		return
+	}
+
+	entry := w.logger.WithFields(logrus.Fields{
+		"epoch":    epoch,
+		"level":    level.String(),
+		"measured": measured,
+	})
+	if level == degrade.LevelNormal {
+		entry.Info("Beacon node recovered - optional work restored")
+	} else {
+		entry.Warn("Beacon node overloaded - shedding optional work")
+	}
+
+	if w.annotations.Enabled(grafana.TypeDegradation) {
+		w.annotations.Annotate(grafana.Annotation{
+			Text: fmt.Sprintf("Degradation level %s at epoch %d", level, epoch),
+			Tags: []string{grafana.TypeDegradation, level.String()},
+		})
+	}
+}
+
+// optionalWorkDue reports whether epoch-level optional work runs under the current degradation level,
+// counting it as shed otherwise
+func (w *ValidatorWatcher) optionalWorkDue(work string, epoch models.Epoch) bool {
+	if w.degradation.Due(uint64(epoch)) {
+		return true
+	}
+	w.prometheusMetrics.RecordShedWork(w.config.Network, work)
+	w.logger.WithFields(logrus.Fields{
+		"work":  work,
+		"level": w.degradation.Level().String(),
+	}).Debug("Shedding optional work")
+	return false
+}
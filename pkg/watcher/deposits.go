@@ -0,0 +1,150 @@
+package watcher
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/onchain"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/queues"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/refresh"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/validator"
+	"github.com/sirupsen/logrus"
+)
+
+// initDepositTracking creates the deposit contract scanner, following the configured contract or
+// the one the beacon node's spec names from its deployment block
+func (w *ValidatorWatcher) initDepositTracking(spec *models.Spec) {
+	tracking := w.config.DepositTracking
+	if tracking.ExecutionURL == "" {
+		return
+	}
+	contract, from := tracking.ContractAddress, uint64(0)
+	if contract == "" {
+		contract, from = spec.DepositContractAddress, onchain.DepositContractBlock(w.config.Network)
+	}
+	if tracking.FromBlock != nil {
+		from = *tracking.FromBlock
+	}
+	if contract == "" {
+		w.logger.Warn("Deposit tracking disabled: no contract_address and the beacon node's spec has no DEPOSIT_CONTRACT_ADDRESS")
+		return
+	}
+	rpc := onchain.NewRPCClient(tracking.ExecutionURL, w.config.BeaconTimeout.ToDuration())
+	w.depositScanner = onchain.NewDepositScanner(rpc, contract, from)
+	w.logger.WithFields(logrus.Fields{"contract": contract, "from_block": from}).Info("Tracking deposits of watched keys")
+}
+
+// startDepositScans scans the deposit contract for the watched keys missing from the validator set
+// every epoch, in the background: the first scan of a key reads the contract's whole history, which
+// takes many requests, and a scan cut short by the epoch's end resumes where it stopped
+func (w *ValidatorWatcher) startDepositScans(ctx context.Context) {
+	if w.depositScanner == nil || w.clock == nil {
+		return
+	}
+
+	epoch := time.Duration(w.clock.SlotsPerEpoch()*w.clock.SecondsPerSlot()) * time.Second
+	scans := refresh.New("deposit_scan", epoch, func(ctx context.Context) (struct{}, error) {
+		var missing []string
+		for _, key := range w.watchedKeys() {
+			if _, ok := w.watchedValidators.GetByPubkey(key.PublicKey); !ok {
+				missing = append(missing, key.PublicKey)
+			}
+		}
+		if len(missing) == 0 {
+			return struct{}{}, refresh.ErrSkipped
+		}
+		// A scan that ran out of time isn't a failure, the next one continues it
+		if err := w.depositScanner.Scan(ctx, missing); err != nil {
+			if ctx.Err() == nil {
+				w.logger.WithError(err).Warn("Failed to scan deposit contract logs")
+			}
+			return struct{}{}, err
+		}
+		return struct{}{}, nil
+	}, w.logger)
+	scans.Start(ctx)
+}
+
+// updateDepositTracking stages the watched keys that aren't active yet, from the beacon state's
+// pending deposits and the deposits the background scans found so far, and counts them per label
+func (w *ValidatorWatcher) updateDepositTracking(epoch models.Epoch, deposits []models.PendingDeposit) {
+	w.depositsMu.Lock()
+	defer w.depositsMu.Unlock()
+
+	keys := w.watchedKeys()
+	pending := make(map[string]bool, len(deposits))
+	for _, d := range deposits {
+		pending[strings.ToLower(d.Pubkey)] = true
+	}
+
+	stages := make(map[string]string)
+	counts := make(map[string]map[string]int)
+	for _, key := range keys {
+		pubkey := strings.ToLower(key.PublicKey)
+		v, inSet := w.watchedValidators.GetByPubkey(key.PublicKey)
+
+		var status models.ValidatorStatus
+		var labels []string
+		if inSet {
+			status, labels = v.Status, v.Labels
+		} else {
+			if cohortOnly(key.Labels) {
+				continue
+			}
+			labels = append([]string{"scope:watched"}, validator.ExpandLabels(key.Labels)...)
+		}
+
+		_, seen := w.depositSeen(pubkey)
+		stage, ok := queues.PreActivationStage(status, inSet, pending[pubkey], seen)
+		if !ok {
+			continue
+		}
+		stages[pubkey] = stage
+		for _, scope := range w.aggregatedScopes(labels) {
+			if counts[scope] == nil {
+				counts[scope] = make(map[string]int)
+			}
+			counts[scope][stage]++
+		}
+
+		if previous, ok := w.depositStages[pubkey]; !ok || previous != stage {
+			fields := logrus.Fields{"epoch": epoch, "pubkey": w.logPubkey(pubkey), "label": primaryLabel(labels), "stage": stage}
+			if deposit, ok := w.depositSeen(pubkey); ok {
+				fields["deposit_block"] = deposit.BlockNumber
+				fields["deposit_amount"] = deposit.Amount
+			}
+			switch stage {
+			case queues.StageNoDeposit:
+				w.logger.WithFields(fields).Info("🕳️ No deposit found for watched key")
+			default:
+				w.logger.WithFields(fields).Info("📥 Deposit seen, awaiting activation")
+			}
+		}
+	}
+	w.depositStages = stages
+
+	w.prometheusMetrics.SetPreActivation(w.config.Network, counts)
+}
+
+// depositSeen returns the deposit contract's first deposit of a pubkey, if deposits are tracked
+func (w *ValidatorWatcher) depositSeen(pubkey string) (onchain.Deposit, bool) {
+	if w.depositScanner == nil {
+		return onchain.Deposit{}, false
+	}
+	return w.depositScanner.Deposit(pubkey)
+}
+
+// cohortOnly reports whether a key's labels are all comparison cohort labels
+func cohortOnly(labels []string) bool {
+	if len(labels) == 0 {
+		return false
+	}
+	for _, label := range labels {
+		if !validator.IsCohortLabel(label) {
+			return false
+		}
+	}
+	return true
+}
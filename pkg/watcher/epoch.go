@@ -0,0 +1,96 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/metrics"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/scheduler"
+)
+
+// stageValidators is the epoch stage refreshing the watched validators, which epoch processing needs
+const stageValidators = "validators"
+
+// epochStages returns an epoch's refreshes as pipeline stages: the watched validators, attester duties
+// and proposer duties are fetched concurrently, and what reads the watched validators waits for them
+// Each stage is bounded by epoch_stage_timeout_sec, two slots by default
+func (w *ValidatorWatcher) epochStages(epoch models.Epoch, stateID string, watchedIndices []models.ValidatorIndex) []scheduler.Stage {
+	budget := w.config.EpochStageTimeout.ToDuration()
+	if budget == 0 {
+		budget = 2 * time.Duration(w.clock.SecondsPerSlot()) * time.Second
+	}
+
+	return []scheduler.Stage{
+		{Name: stageValidators, Budget: budget, Run: func(ctx context.Context) error {
+			return w.refreshWatchedValidators(ctx, epoch, stateID, watchedIndices)
+		}},
+		// Track the committees of this epoch's duties until their aggregates land on chain
+		{Name: "attester_duties", Budget: budget, Run: func(ctx context.Context) error {
+			if len(watchedIndices) == 0 {
+				return nil
+			}
+			attesterDuties, err := w.beaconClient.GetAttesterDuties(ctx, epoch, watchedIndices)
+			if err != nil {
+				w.logger.WithError(err).Warn("Failed to get attester duties")
+				return err
+			}
+			w.aggregation.AddDuties(attesterDuties)
+			return nil
+		}},
+		// Proposer schedule for the current and next epoch; a failed epoch keeps its previous duties
+		{Name: "proposer_schedule", Budget: budget, Run: func(ctx context.Context) error {
+			if err := w.proposerSchedule.Update(ctx, epoch); err != nil {
+				w.logger.WithError(err).Warn("Failed to update proposer schedule for current epoch")
+			}
+			if err := w.proposerSchedule.Update(ctx, epoch+1); err != nil {
+				w.logger.WithError(err).Warn("Failed to update proposer schedule for next epoch")
+			}
+			return nil
+		}},
+		// Watched members of the current sync committee
+		{Name: "sync_committee", After: []string{stageValidators}, Budget: budget, Run: func(ctx context.Context) error {
+			w.updateSyncCommittee(ctx, epoch, stateID)
+			return nil
+		}},
+		{Name: "future_proposals", After: []string{stageValidators, "proposer_schedule"}, Run: func(ctx context.Context) error {
+			w.updateFutureProposals(epoch)
+			return nil
+		}},
+		// Relay registrations looked up in the background during the previous epoch
+		{Name: "relay_registrations", After: []string{stageValidators}, Run: func(ctx context.Context) error {
+			w.updateRelayRegistrations()
+			return nil
+		}},
+		// Third-party ratings pulled in the background once a day
+		{Name: "external_ratings", After: []string{stageValidators}, Run: func(ctx context.Context) error {
+			w.updateExternalRatings()
+			return nil
+		}},
+	}
+}
+
+// refreshWatchedValidators reloads the watched validators at the pinned state and observes what changed
+func (w *ValidatorWatcher) refreshWatchedValidators(ctx context.Context, epoch models.Epoch, stateID string, watchedIndices []models.ValidatorIndex) error {
+	if len(watchedIndices) == 0 {
+		return nil
+	}
+
+	watchedVals, err := w.beaconClient.GetValidators(ctx, stateID, watchedIndices)
+	if err != nil {
+		return fmt.Errorf("failed to get watched validators: %w", err)
+	}
+	// Counters carry over; the reset policy decides when they start again from zero
+	w.watchedValidators.Reconcile(watchedVals, w.watchedKeys())
+	w.observeStatuses(epoch)
+	w.observeCredentials(epoch)
+	w.prometheusMetrics.MarkUpdated(metrics.SourceValidators, w.config.Network)
+	w.logger.WithField("count", w.watchedValidators.Count()).Info("Updated watched validators")
+	w.recordMembership(epoch)
+	w.heatmap.Retain(func(index models.ValidatorIndex) bool {
+		_, ok := w.watchedValidators.Get(index)
+		return ok
+	})
+	return nil
+}
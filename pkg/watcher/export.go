@@ -0,0 +1,68 @@
+package watcher
+
+import (
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/export"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/validator"
+)
+
+// writeExport appends a row per watched validator with what it was counted for since the last rows,
+// once an epoch settled and before the counters may reset: the epoch's attestation duties, the
+// last fetched rewards (see taskSlots.rewardsEpoch) and the blocks processed since the previous rows
+func (w *ValidatorWatcher) writeExport(epoch models.Epoch, watched []*validator.WatchedValidator) {
+	if w.exporter == nil || epoch == 0 {
+		return
+	}
+	blocksTo := w.countersSince
+	if w.clock != nil {
+		blocksTo = w.clock.CurrentSlot()
+	}
+	if blocksTo > w.countersSince {
+		blocksTo--
+	}
+
+	rewardsEpoch := w.taskSlots.rewardsEpoch(epoch)
+	at := time.Now()
+	if w.clock != nil {
+		at = w.clock.SlotStartTime(w.clock.EpochToSlot(epoch))
+	}
+
+	rows := make([]export.Row, 0, len(watched))
+	for _, v := range watched {
+		if v.Cohort {
+			continue
+		}
+		rows = append(rows, export.Row{
+			Epoch:                 epoch,
+			RewardsEpoch:          rewardsEpoch,
+			BlocksFrom:            w.countersSince,
+			BlocksTo:              blocksTo,
+			Index:                 w.anonymizer.Index(v.Index),
+			Pubkey:                w.anonymizer.Pubkey(v.Data.Pubkey),
+			Label:                 primaryLabel(v.Labels),
+			Status:                v.Status,
+			Balance:               v.Balance,
+			EffectiveBalance:      v.Data.EffectiveBalance,
+			IdealConsensusRewards: v.IdealConsensusRewards,
+			ConsensusRewards:      v.ConsensusRewards,
+			Counters: export.Counters{
+				AttestationDuties:        v.AttestationDuties,
+				AttestationDutiesSuccess: v.AttestationDutiesSuccess,
+				MissedAttestations:       v.MissedAttestations,
+				SuboptimalSourceVotes:    v.SuboptimalSourceVotes,
+				SuboptimalTargetVotes:    v.SuboptimalTargetVotes,
+				SuboptimalHeadVotes:      v.SuboptimalHeadVotes,
+				InclusionDelaySum:        v.InclusionDelaySum,
+				InclusionDelayCount:      v.InclusionDelayCount,
+				ProposedBlocks:           v.ProposedBlocks,
+				MissedBlocks:             v.MissedBlocks,
+			},
+		})
+	}
+
+	if err := w.exporter.Write(at, rows); err != nil {
+		w.logger.WithError(err).WithField("epoch", epoch).Warn("Failed to export validator rows")
+	}
+}
@@ -0,0 +1,77 @@
+package watcher
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/alert"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/events"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/validator"
+	"github.com/sirupsen/logrus"
+)
+
+// checkFeeRecipient checks a watched proposal's execution payload fee recipient when no relays are
+// configured; with relays, a builder's block pays the builder and the proposer's fee recipient is only
+// known from the relay's bid trace, so recordBlockRewards checks it once the relays answered
+func (w *ValidatorWatcher) checkFeeRecipient(block *models.Block, slot models.Slot, v *validator.WatchedValidator) {
+	payload := block.Message.Body.ExecutionPayload
+	if payload == nil || (w.relayClient != nil && payload.BlockHash != "") {
+		return
+	}
+	w.verifyFeeRecipient(slot, v, payload.FeeRecipient)
+}
+
+// verifyFeeRecipient compares the address a watched proposal paid against the expected ones
+// and alerts when the block pays to an unexpected address
+func (w *ValidatorWatcher) verifyFeeRecipient(slot models.Slot, v *validator.WatchedValidator, feeRecipient string) {
+	if v.Cohort {
+		return
+	}
+
+	ok, checked := w.feeRecipients.Check(v.Labels, feeRecipient)
+	if !checked || ok {
+		return
+	}
+
+	expected := w.feeRecipients.Expected(v.Labels)
+	label := primaryLabel(v.Labels)
+	w.prometheusMetrics.RecordWrongFeeRecipient(w.config.Network, w.aggregatedScopes(v.Labels))
+
+	w.events.Emit(events.Event{
+		Type:           events.TypeWrongFeeRecipient,
+		Slot:           slot,
+		Epoch:          w.clock.SlotToEpoch(slot),
+		ValidatorIndex: v.Index,
+		Pubkey:         v.Data.Pubkey,
+		Label:          label,
+		Data: map[string]interface{}{
+			"fee_recipient": feeRecipient,
+			"expected":      expected,
+		},
+	})
+
+	w.logger.WithFields(logrus.Fields{
+		"slot":            slot,
+		"validator_index": w.anonymizer.Index(v.Index),
+		"pubkey":          w.logPubkey(v.Data.Pubkey),
+		"label":           label,
+		"fee_recipient":   feeRecipient,
+		"expected":        strings.Join(expected, ","),
+	}).Warn("⚠️ WRONG FEE RECIPIENT")
+
+	go w.sendAlert(alert.Alert{
+		Severity: alert.SeverityWarning,
+		Title:    fmt.Sprintf("Watched validator %d proposed a block paying to an unexpected fee recipient", w.anonymizer.Index(v.Index)),
+		Text:     fmt.Sprintf("Block at slot %d pays to %s, expected %s", slot, feeRecipient, strings.Join(expected, " or ")),
+		Fields: map[string]string{
+			"network":       w.config.Network,
+			"validator":     fmt.Sprintf("%d", w.anonymizer.Index(v.Index)),
+			"pubkey":        w.logPubkey(v.Data.Pubkey),
+			"label":         label,
+			"slot":          fmt.Sprintf("%d", slot),
+			"fee_recipient": feeRecipient,
+			"expected":      strings.Join(expected, ","),
+		},
+	})
+}
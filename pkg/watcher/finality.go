@@ -0,0 +1,83 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/beacon"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/proposer"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/validator"
+	"github.com/sirupsen/logrus"
+)
+
+// proposalOutcome names a proposal outcome in logs and metrics
+func proposalOutcome(proposed bool) string {
+	if proposed {
+		return "proposed"
+	}
+	return "missed"
+}
+
+// reconcileFinalized settles the watched proposals whose slot is now finalized
+// The canonical block at a finalized slot is final, so its proposer decides the outcome
+// regardless of what was seen at the head
+func (w *ValidatorWatcher) reconcileFinalized(ctx context.Context) error {
+	checkpoints, err := w.beaconClient.GetFinalityCheckpoints(ctx, "head")
+	if err != nil {
+		return err
+	}
+	finalizedSlot := w.clock.EpochToSlot(checkpoints.Finalized.Epoch)
+
+	due := w.finality.Due(finalizedSlot)
+	for i, proposal := range due {
+		proposed, err := w.canonicalProposal(ctx, proposal)
+		if err != nil {
+			// Keep the rest for the next round rather than guessing their outcome
+			for _, p := range due[i:] {
+				w.finality.Track(p)
+			}
+			w.prometheusMetrics.SetProposalsPendingFinality(w.config.Network, w.finality.Pending())
+			return err
+		}
+
+		w.watchedValidators.UpdateMetrics(proposal.ValidatorIndex, func(wv *validator.WatchedValidator) {
+			if proposed {
+				wv.ProposedBlocksFinalized++
+			} else {
+				wv.MissedBlocksFinalized++
+			}
+		})
+
+		if proposed != proposal.HeadProposed {
+			w.prometheusMetrics.RecordProposalFinalityFlip(w.config.Network, proposalOutcome(proposal.HeadProposed), proposalOutcome(proposed))
+			w.logger.WithFields(logrus.Fields{
+				"slot":            proposal.Slot,
+				"validator_index": w.anonymizer.Index(proposal.ValidatorIndex),
+				"head":            proposalOutcome(proposal.HeadProposed),
+				"finalized":       proposalOutcome(proposed),
+			}).Warn("Finalized block proposal outcome differs from the head")
+		}
+	}
+
+	w.prometheusMetrics.SetProposalsPendingFinality(w.config.Network, w.finality.Pending())
+	if len(due) > 0 {
+		w.logger.WithFields(logrus.Fields{
+			"finalized_epoch": checkpoints.Finalized.Epoch,
+			"reconciled":      len(due),
+		}).Debug("Reconciled finalized block proposals")
+	}
+	return nil
+}
+
+// canonicalProposal reports whether the validator's block is the canonical block of the slot
+func (w *ValidatorWatcher) canonicalProposal(ctx context.Context, proposal proposer.Proposal) (bool, error) {
+	header, err := w.beaconClient.GetHeader(ctx, fmt.Sprintf("%d", proposal.Slot))
+	if beacon.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return models.ValidatorIndex(header.Header.Message.ProposerIndex) == proposal.ValidatorIndex, nil
+}
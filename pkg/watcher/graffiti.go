@@ -0,0 +1,15 @@
+package watcher
+
+import (
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/proposer"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/validator"
+)
+
+// recordGraffiti decodes a watched proposal's graffiti and counts the block under the clients it points to
+func (w *ValidatorWatcher) recordGraffiti(block *models.Block, v *validator.WatchedValidator) (string, proposer.Client) {
+	graffiti := proposer.DecodeGraffiti(block.Message.Body.Graffiti)
+	client := w.graffiti.Infer(graffiti)
+	w.prometheusMetrics.RecordInferredClient(w.config.Network, w.aggregatedScopes(v.Labels), client.Consensus, client.Execution)
+	return graffiti, client
+}
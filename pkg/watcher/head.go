@@ -0,0 +1,177 @@
+package watcher
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/beacon"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/events"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/reorg"
+	"github.com/sirupsen/logrus"
+)
+
+// Slot work triggers
+const (
+	triggerHead    = "head"    // The node imported a block at or after the slot
+	triggerTimeout = "timeout" // No head event arrived before the slot's end time
+)
+
+// headTracker follows the chain head from beacon node head events
+type headTracker struct {
+	mu      sync.Mutex
+	slot    models.Slot
+	root    string
+	seen    bool
+	changed chan struct{} // Closed and replaced whenever the head advances
+}
+
+func newHeadTracker() *headTracker {
+	return &headTracker{changed: make(chan struct{})}
+}
+
+// advance records a new head and wakes up waiters
+// A different head block at or before the current head slot is a reorg of the slots up to it
+func (h *headTracker) advance(slot models.Slot, root string) (reorg.Window, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.seen && slot <= h.slot {
+		if root == h.root {
+			return reorg.Window{}, false
+		}
+		window := reorg.Window{From: slot, To: h.slot}
+		h.root = root
+		return window, true
+	}
+	h.slot = slot
+	h.root = root
+	h.seen = true
+	close(h.changed)
+	h.changed = make(chan struct{})
+	return reorg.Window{}, false
+}
+
+// waitFor blocks until the head reaches slot or deadline passes
+// Returns true if the head reached the slot
+func (h *headTracker) waitFor(ctx context.Context, slot models.Slot, deadline time.Time) (bool, error) {
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+
+	for {
+		h.mu.Lock()
+		reached := h.seen && h.slot >= slot
+		changed := h.changed
+		h.mu.Unlock()
+
+		if reached {
+			return true, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-timer.C:
+			return false, nil
+		case <-changed:
+		}
+	}
+}
+
+// startEvents subscribes to the beacon node event stream
+// Returns false when slot processing has to stay on the local clock
+func (w *ValidatorWatcher) startEvents(ctx context.Context) bool {
+	if !w.config.ShouldUseEvents() || w.clock.IsReplayMode() {
+		return false
+	}
+
+	stream, err := w.beaconClient.SubscribeEvents(ctx, []string{
+		beacon.TopicHead,
+		beacon.TopicBlock,
+		beacon.TopicFinalizedCheckpoint,
+		beacon.TopicChainReorg,
+	})
+	if err != nil {
+		w.logger.WithError(err).Warn("Beacon event stream unavailable - processing slots on the local clock")
+		return false
+	}
+
+	w.head = newHeadTracker()
+	go func() {
+		for event := range stream {
+			w.handleBeaconEvent(event)
+		}
+	}()
+	return true
+}
+
+// handleBeaconEvent reacts to one beacon node event
+func (w *ValidatorWatcher) handleBeaconEvent(event beacon.Event) {
+	w.prometheusMetrics.RecordBeaconEvent(w.config.Network, event.Topic)
+
+	switch event.Topic {
+	case beacon.TopicHead:
+		var head models.HeadEvent
+		if err := event.Decode(&head); err != nil {
+			w.logger.WithError(err).Debug("Failed to decode head event")
+			return
+		}
+		if window, rewound := w.head.advance(head.Slot, head.Block); rewound && w.noteReorg(window) {
+			w.logger.WithFields(logrus.Fields{
+				"slot":       head.Slot,
+				"head_block": head.Block,
+				"depth":      window.Depth(),
+			}).Warn("🔀 Chain reorg detected from head event")
+		}
+
+	case beacon.TopicFinalizedCheckpoint:
+		var finalized models.FinalizedCheckpointEvent
+		if err := event.Decode(&finalized); err != nil {
+			w.logger.WithError(err).Debug("Failed to decode finalized checkpoint event")
+			return
+		}
+		w.logger.WithField("epoch", finalized.Epoch).Debug("Finalized checkpoint")
+
+	case beacon.TopicChainReorg:
+		var chainReorg models.ChainReorgEvent
+		if err := event.Decode(&chainReorg); err != nil {
+			w.logger.WithError(err).Debug("Failed to decode chain reorg event")
+			return
+		}
+		w.events.Emit(events.Event{
+			Type:  events.TypeChainReorg,
+			Slot:  chainReorg.Slot,
+			Epoch: chainReorg.Epoch,
+			Data: map[string]interface{}{
+				"depth":          chainReorg.Depth,
+				"old_head_block": chainReorg.OldHeadBlock,
+				"new_head_block": chainReorg.NewHeadBlock,
+			},
+		})
+		w.logger.WithFields(logrus.Fields{
+			"slot":           chainReorg.Slot,
+			"depth":          chainReorg.Depth,
+			"old_head_block": chainReorg.OldHeadBlock,
+			"new_head_block": chainReorg.NewHeadBlock,
+		}).Warn("🔀 Chain reorg")
+		w.noteReorg(eventWindow(chainReorg))
+	}
+}
+
+// waitForSlot waits until slot's block can be looked up: the node imported a block at or
+// after it, or the slot ended (with the usual attestation lag) without one
+func (w *ValidatorWatcher) waitForSlot(ctx context.Context, slot models.Slot) error {
+	reached, err := w.head.waitFor(ctx, slot, w.clock.SlotEndTime(slot))
+	if err != nil {
+		return err
+	}
+
+	trigger := triggerHead
+	if !reached {
+		trigger = triggerTimeout
+		w.logger.WithField("slot", slot).Debug("No head event for slot - processing on the clock")
+	}
+	w.prometheusMetrics.RecordSlotTrigger(w.config.Network, trigger)
+	return nil
+}
@@ -0,0 +1,69 @@
+package watcher
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/reorg"
+)
+
+func TestHeadTrackerAdvance(t *testing.T) {
+	type head struct {
+		slot models.Slot
+		root string
+	}
+	tests := []struct {
+		name     string
+		heads    []head
+		reorged  bool
+		window   reorg.Window
+		headSlot models.Slot
+	}{
+		{"next slot", []head{{100, "0xa"}, {101, "0xb"}}, false, reorg.Window{}, 101},
+		{"skipped slots", []head{{100, "0xa"}, {104, "0xb"}}, false, reorg.Window{}, 104},
+		{"same head again", []head{{100, "0xa"}, {100, "0xa"}}, false, reorg.Window{}, 100},
+		{"other block at the head slot", []head{{100, "0xa"}, {100, "0xb"}}, true, reorg.Window{From: 100, To: 100}, 100},
+		{"earlier block after skipped slots", []head{{100, "0xa"}, {104, "0xb"}, {102, "0xc"}}, true, reorg.Window{From: 102, To: 104}, 104},
+		{"out of order, earlier slot", []head{{101, "0xb"}, {100, "0xa"}}, true, reorg.Window{From: 100, To: 101}, 101},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHeadTracker()
+			var window reorg.Window
+			var reorged bool
+			for _, next := range tt.heads {
+				window, reorged = h.advance(next.slot, next.root)
+			}
+			if reorged != tt.reorged || window != tt.window {
+				t.Errorf("advance() = %+v, %v, want %+v, %v", window, reorged, tt.window, tt.reorged)
+			}
+			// A reorg replaces the head block but never moves the head back
+			if h.slot != tt.headSlot || h.root != tt.heads[len(tt.heads)-1].root {
+				t.Errorf("Head = %d %s, want %d %s", h.slot, h.root, tt.headSlot, tt.heads[len(tt.heads)-1].root)
+			}
+		})
+	}
+}
+
+func TestHeadTrackerWaitFor(t *testing.T) {
+	h := newHeadTracker()
+	ctx := context.Background()
+
+	if reached, _ := h.waitFor(ctx, 10, time.Now().Add(10*time.Millisecond)); reached {
+		t.Fatal("Reached a slot before any head")
+	}
+
+	go h.advance(12, "0xc") // Skipping slots 10 and 11 still reaches them
+	if reached, err := h.waitFor(ctx, 10, time.Now().Add(time.Second)); !reached || err != nil {
+		t.Fatalf("waitFor() = %v, %v, want the head past the slot", reached, err)
+	}
+
+	// A reorg to an earlier slot doesn't wake up waiters for later ones
+	h.advance(11, "0xb")
+	if reached, _ := h.waitFor(ctx, 13, time.Now().Add(10*time.Millisecond)); reached {
+		t.Error("Reached slot 13 with the head at 12")
+	}
+}
@@ -0,0 +1,305 @@
+package watcher
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/health"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/metrics"
+)
+
+// slotLoopStallEpochs is how long the slot loop may go without finishing a slot before it counts as stuck
+const slotLoopStallEpochs = 2
+
+// healthChecks returns the subsystem checks behind /livez, /readyz, /startupz and the gRPC health service
+func (w *ValidatorWatcher) healthChecks() []health.Check {
+	return []health.Check{
+		{
+			Name:   "initialized",
+			Probes: []health.Probe{health.ProbeStartup, health.ProbeReady},
+			Run: func() error {
+				if !w.ready {
+					return errors.New("initialization in progress")
+				}
+				return nil
+			},
+		},
+		{
+			Name:   "slot_loop",
+			Probes: []health.Probe{health.ProbeLive, health.ProbeReady},
+			Run:    w.checkSlotLoop,
+		},
+		{
+			Name:   "beacon",
+			Probes: []health.Probe{health.ProbeReady},
+			Run: func() error {
+				for _, status := range w.beaconClient.Endpoints() {
+					if status.Healthy {
+						return nil
+					}
+				}
+				return errors.New("no healthy beacon endpoint")
+			},
+		},
+		{
+			Name:   "validator_data",
+			Probes: []health.Probe{health.ProbeReady},
+			Run: func() error {
+				if w.prometheusMetrics.IsStale(metrics.SourceValidators, time.Now()) {
+					return errors.New("validator data is stale")
+				}
+				return nil
+			},
+		},
+	}
+}
+
+// checkSlotLoop fails when the slot loop hasn't finished a slot for a while
+// Before the first slot (still initializing, or snapshot mode without a clock) there is nothing to check
+func (w *ValidatorWatcher) checkSlotLoop() error {
+	last := w.lastSlotAt.Load()
+	if last == 0 || w.clock == nil {
+		return nil
+	}
+
+	stallAfter := time.Duration(slotLoopStallEpochs*w.clock.SlotsPerEpoch()*w.clock.SecondsPerSlot()) * time.Second
+	if since := time.Since(time.Unix(0, last)); since > stallAfter {
+		return fmt.Errorf("no slot processed for %s", since.Round(time.Second))
+	}
+	return nil
+}
+
+// Components of the JSON health report
+const (
+	componentInitialized       = "initialized"
+	componentBeacon            = "beacon"
+	componentBeaconSync        = "beacon_sync"
+	componentClock             = "clock"
+	componentSlotAge           = "slot_age"
+	componentValidatorRegistry = "validator_registry"
+	componentAlerting          = "alerting"
+)
+
+// defaultLiveComponents are the components /health requires unless configured: a stuck slot loop
+// needs a restart, a syncing beacon node doesn't
+var defaultLiveComponents = []string{componentSlotAge}
+
+// defaultReadyComponents are the components /ready requires unless configured: those the served
+// data depends on, so a failing alert channel or a drifting clock doesn't take a replica out
+var defaultReadyComponents = []string{componentInitialized, componentBeacon, componentBeaconSync, componentSlotAge, componentValidatorRegistry}
+
+// healthComponents returns the components of the JSON report served at /health and /ready
+func (w *ValidatorWatcher) healthComponents() []health.Component {
+	return []health.Component{
+		{Name: componentInitialized, Check: w.checkInitialized},
+		{Name: componentBeacon, Check: w.checkBeaconEndpoints},
+		{Name: componentBeaconSync, Check: w.checkBeaconSync},
+		{Name: componentClock, Check: w.checkClock},
+		{Name: componentSlotAge, Check: w.checkSlotAge},
+		{Name: componentValidatorRegistry, Check: w.checkValidatorRegistry},
+		{Name: componentAlerting, Check: w.checkAlerting},
+	}
+}
+
+// healthCriteria returns the failure criteria of /health and /ready, rejecting unknown components
+func (w *ValidatorWatcher) healthCriteria() (live, ready health.Criteria, err error) {
+	known := make(map[string]bool)
+	for _, name := range w.health.ComponentNames() {
+		known[name] = true
+	}
+	cfg := w.config.Health
+	for _, name := range append(append([]string{}, cfg.LiveComponents...), cfg.ReadyComponents...) {
+		if !known[name] {
+			return live, ready, fmt.Errorf("unknown health component %q", name)
+		}
+	}
+
+	failOn := health.Status(cfg.FailOn)
+	live = health.Criteria{FailOn: failOn, Components: cfg.LiveComponents}
+	if len(live.Components) == 0 {
+		live.Components = defaultLiveComponents
+	}
+	ready = health.Criteria{FailOn: failOn, Components: cfg.ReadyComponents}
+	if len(ready.Components) == 0 {
+		ready.Components = defaultReadyComponents
+	}
+	return live, ready, nil
+}
+
+func (w *ValidatorWatcher) checkInitialized() health.ComponentResult {
+	if !w.ready {
+		return health.ComponentResult{Status: health.StatusFail, Message: "initialization in progress"}
+	}
+	return health.ComponentResult{Status: health.StatusPass}
+}
+
+// checkBeaconEndpoints fails without a healthy beacon endpoint and warns while some are cooling down
+func (w *ValidatorWatcher) checkBeaconEndpoints() health.ComponentResult {
+	endpoints := w.beaconClient.Endpoints()
+	healthy := 0
+	details := map[string]interface{}{"endpoints": len(endpoints)}
+	for _, status := range endpoints {
+		if status.Healthy {
+			healthy++
+		}
+		if status.Active {
+			details["active"] = status.Name
+			details["latency_seconds"] = status.Latency.Seconds()
+		}
+	}
+	details["healthy"] = healthy
+
+	switch {
+	case healthy == 0:
+		return health.ComponentResult{Status: health.StatusFail, Message: "no healthy beacon endpoint", Details: details}
+	case healthy < len(endpoints):
+		return health.ComponentResult{Status: health.StatusWarn, Message: fmt.Sprintf("%d of %d beacon endpoints unhealthy", len(endpoints)-healthy, len(endpoints)), Details: details}
+	}
+	return health.ComponentResult{Status: health.StatusPass, Details: details}
+}
+
+// checkBeaconSync fails while the beacon node syncs and warns while its head is optimistic or its
+// execution client is offline
+func (w *ValidatorWatcher) checkBeaconSync() health.ComponentResult {
+	status, err := w.nodeSyncStatus()
+	if err != nil {
+		// Beacon errors carry endpoint paths and response bodies, which the report doesn't expose
+		w.logger.WithError(err).Debug("Health check failed to get the beacon node's sync status")
+		return health.ComponentResult{Status: health.StatusWarn, Message: "beacon node unreachable"}
+	}
+
+	details := map[string]interface{}{
+		"head_slot":     status.HeadSlot,
+		"sync_distance": status.SyncDistance,
+		"is_syncing":    status.IsSyncing,
+		"is_optimistic": status.IsOptimistic,
+		"el_offline":    status.ELOffline,
+	}
+	switch {
+	case status.IsSyncing:
+		return health.ComponentResult{Status: health.StatusFail, Message: fmt.Sprintf("beacon node syncing, %d slots behind", status.SyncDistance), Details: details}
+	case status.ELOffline:
+		return health.ComponentResult{Status: health.StatusWarn, Message: "beacon node's execution client offline", Details: details}
+	case status.IsOptimistic:
+		return health.ComponentResult{Status: health.StatusWarn, Message: "beacon node head is optimistic", Details: details}
+	}
+	return health.ComponentResult{Status: health.StatusPass, Details: details}
+}
+
+// checkClock warns when the local slot lags the beacon node's head or stays more than a slot ahead
+// of it (see startClockDriftChecks), and fails when it drifts an epoch
+func (w *ValidatorWatcher) checkClock() health.ComponentResult {
+	if w.clock == nil {
+		return health.ComponentResult{Status: health.StatusWarn, Message: "no beacon clock (genesis or spec unavailable)"}
+	}
+	if w.clockDrift == nil {
+		return health.ComponentResult{Status: health.StatusPass, Message: "replay mode"}
+	}
+	if w.syncPaused.Load() {
+		return health.ComponentResult{Status: health.StatusPass, Message: "beacon node syncing, head not comparable"}
+	}
+	drift, measured, ok := w.clockDrift.Value()
+	if !ok {
+		if err := w.clockDrift.Err(); err != nil {
+			w.logger.WithError(err).Debug("Health check found no clock drift reading")
+			return health.ComponentResult{Status: health.StatusWarn, Message: "beacon head unavailable"}
+		}
+		return health.ComponentResult{Status: health.StatusPass, Message: "not measured yet"}
+	}
+
+	details := map[string]interface{}{"drift_slots": drift, "measured_at": measured.UTC().Format(time.RFC3339)}
+	off := drift
+	if off < 0 {
+		off = -off
+	}
+	switch {
+	case off > int64(w.clock.SlotsPerEpoch()):
+		return health.ComponentResult{Status: health.StatusFail, Message: fmt.Sprintf("local clock is %d slots off the beacon head", drift), Details: details}
+	case clockDrifting(drift, w.clockAhead.Load()):
+		return health.ComponentResult{Status: health.StatusWarn, Message: fmt.Sprintf("local clock is %d slots off the beacon head", drift), Details: details}
+	}
+	return health.ComponentResult{Status: health.StatusPass, Details: details}
+}
+
+// checkSlotAge warns when the last slot finished more than two slots ago and fails when the slot
+// loop is stuck (see checkSlotLoop)
+func (w *ValidatorWatcher) checkSlotAge() health.ComponentResult {
+	last := w.lastSlotAt.Load()
+	if last == 0 || w.clock == nil {
+		return health.ComponentResult{Status: health.StatusPass, Message: "no slot processed yet"}
+	}
+
+	age := time.Since(time.Unix(0, last))
+	slot := time.Duration(w.clock.SecondsPerSlot()) * time.Second
+	details := map[string]interface{}{
+		"last_slot_at": time.Unix(0, last).UTC().Format(time.RFC3339),
+		"age_seconds":  age.Seconds(),
+	}
+	switch {
+	case w.checkSlotLoop() != nil:
+		return health.ComponentResult{Status: health.StatusFail, Message: fmt.Sprintf("no slot processed for %s", age.Round(time.Second)), Details: details}
+	case age > 2*slot:
+		return health.ComponentResult{Status: health.StatusWarn, Message: fmt.Sprintf("last slot finished %s ago", age.Round(time.Second)), Details: details}
+	}
+	return health.ComponentResult{Status: health.StatusPass, Details: details}
+}
+
+// checkValidatorRegistry warns when the watched validators weren't reloaded for two epochs and fails
+// once their data is stale (stale_data_after_sec)
+func (w *ValidatorWatcher) checkValidatorRegistry() health.ComponentResult {
+	details := map[string]interface{}{"validators": w.watchedValidators.Count()}
+	if w.prometheusMetrics.IsStale(metrics.SourceValidators, time.Now()) {
+		return health.ComponentResult{Status: health.StatusFail, Message: "validator data is stale", Details: details}
+	}
+	updated, ok := w.prometheusMetrics.LastUpdated(metrics.SourceValidators)
+	if !ok {
+		return health.ComponentResult{Status: health.StatusPass, Message: "validators not loaded yet", Details: details}
+	}
+
+	age := time.Since(updated)
+	details["updated_at"] = updated.UTC().Format(time.RFC3339)
+	details["age_seconds"] = age.Seconds()
+	if w.clock != nil {
+		epoch := time.Duration(w.clock.SlotsPerEpoch()*w.clock.SecondsPerSlot()) * time.Second
+		if age > 2*epoch {
+			return health.ComponentResult{Status: health.StatusWarn, Message: fmt.Sprintf("validators last reloaded %s ago", age.Round(time.Second)), Details: details}
+		}
+	}
+	return health.ComponentResult{Status: health.StatusPass, Details: details}
+}
+
+// checkAlerting warns when the last delivery to an alert channel failed and fails when the last
+// deliveries to every channel did; alerts are logged either way
+// Delivery errors are only logged, since the report is served to anyone who can reach the port
+func (w *ValidatorWatcher) checkAlerting() health.ComponentResult {
+	if len(w.alertChannels) == 0 {
+		return health.ComponentResult{Status: health.StatusPass, Message: "no alert channel configured, alerts are only logged"}
+	}
+
+	failing := 0
+	channels := make(map[string]interface{}, len(w.alertChannels))
+	for _, tracked := range w.alertChannels {
+		status := tracked.Status()
+		channel := map[string]interface{}{"consecutive_failures": status.ConsecutiveFailures}
+		if !status.LastSuccess.IsZero() {
+			channel["last_success"] = status.LastSuccess.UTC().Format(time.RFC3339)
+		}
+		if !status.LastFailure.IsZero() {
+			channel["last_failure"] = status.LastFailure.UTC().Format(time.RFC3339)
+		}
+		if status.ConsecutiveFailures > 0 {
+			failing++
+		}
+		channels[status.Name] = channel
+	}
+	details := map[string]interface{}{"channels": channels}
+
+	switch {
+	case failing == len(w.alertChannels):
+		return health.ComponentResult{Status: health.StatusFail, Message: "the last delivery to every alert channel failed", Details: details}
+	case failing > 0:
+		return health.ComponentResult{Status: health.StatusWarn, Message: fmt.Sprintf("the last delivery to %d of %d alert channels failed", failing, len(w.alertChannels)), Details: details}
+	}
+	return health.ComponentResult{Status: health.StatusPass, Details: details}
+}
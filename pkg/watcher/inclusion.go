@@ -0,0 +1,94 @@
+package watcher
+
+import (
+	"context"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/duties"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/validator"
+	"github.com/sirupsen/logrus"
+)
+
+// recordInclusionDelays records how late the block at slot included watched validators' attestations
+// Attestations of the previous slot reuse its already resolved attesters; older ones in the block
+// (included late, or aggregated again) are resolved against their own slot's committees
+func (w *ValidatorWatcher) recordInclusionDelays(ctx context.Context, slot models.Slot, attestations []models.Attestation,
+	previousSlot models.Slot, attested map[models.ValidatorIndex]bool) {
+	bySlot := make(map[models.Slot][]models.Attestation)
+	for _, att := range attestations {
+		if att.Data.Slot < previousSlot && slot-att.Data.Slot <= duties.InclusionWindow {
+			bySlot[att.Data.Slot] = append(bySlot[att.Data.Slot], att)
+		}
+	}
+
+	var inclusions []duties.Inclusion
+	inclusions = append(inclusions, w.inclusions.Observe(previousSlot, slot, w.watchedAttesters(attested))...)
+	for attestationSlot, atts := range bySlot {
+		attestationSlot := attestationSlot
+		committees, err := w.beaconClient.GetCommittees(ctx, "head", nil, &attestationSlot)
+		if err != nil {
+			w.logger.WithError(err).WithField("attesting_slot", attestationSlot).Debug("Failed to get committees for inclusion delay")
+			continue
+		}
+		lateAttested, err := w.committeeResolver.ProcessAttestations(atts, committees)
+		if err != nil {
+			w.logger.WithError(err).WithField("attesting_slot", attestationSlot).Debug("Failed to resolve attesters for inclusion delay")
+			continue
+		}
+		inclusions = append(inclusions, w.inclusions.Observe(attestationSlot, slot, w.watchedAttesters(lateAttested))...)
+	}
+
+	if slot > duties.InclusionWindow {
+		w.inclusions.Prune(slot - duties.InclusionWindow)
+	}
+
+	// A late inclusion performs the duty the next block missed
+	for _, inclusion := range inclusions {
+		w.attestationDuties.Include(w.clock.SlotToEpoch(inclusion.AttestationSlot), inclusion.ValidatorIndex)
+	}
+
+	// During warmup, observe only: delays are not recorded
+	if w.warmup {
+		return
+	}
+
+	var late int
+	for _, inclusion := range inclusions {
+		delay := inclusion.Delay()
+		if delay > 1 {
+			late++
+		}
+		var labels []string
+		w.watchedValidators.UpdateMetrics(inclusion.ValidatorIndex, func(wv *validator.WatchedValidator) {
+			wv.InclusionDelaySum += delay
+			wv.InclusionDelayCount++
+			if delay > wv.MaxInclusionDelay {
+				wv.MaxInclusionDelay = delay
+			}
+			labels = wv.Labels
+		})
+		w.prometheusMetrics.RecordInclusionDistance(w.config.Network, w.aggregatedScopes(labels), delay)
+	}
+
+	if late > 0 {
+		w.logger.WithFields(logrus.Fields{
+			"slot":     slot,
+			"included": len(inclusions),
+			"late":     late,
+		}).Debug("Watched attestations included late")
+	}
+}
+
+// watchedAttesters returns the watched validators among the attesters
+func (w *ValidatorWatcher) watchedAttesters(attested map[models.ValidatorIndex]bool) []models.ValidatorIndex {
+	var watched []models.ValidatorIndex
+	for index, included := range attested {
+		if !included {
+			continue
+		}
+		if _, ok := w.watchedValidators.Get(index); ok {
+			watched = append(watched, index)
+		}
+	}
+	return watched
+}
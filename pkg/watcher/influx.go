@@ -0,0 +1,92 @@
+package watcher
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/influx"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/metrics"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/validator"
+)
+
+// Measurements written in the line protocol
+const (
+	influxLabelMeasurement     = "eth_label"
+	influxValidatorMeasurement = "eth_validator"
+)
+
+// writeInflux writes the label metrics and, unless disabled, every watched validator's counters of a
+// settled epoch, timestamped at the epoch's start; the counters are that epoch's attestations and the
+// last fetched rewards (rewards_epoch, see taskSlots.rewardsEpoch), captured before they may reset
+func (w *ValidatorWatcher) writeInflux(epoch models.Epoch, metricsByLabel map[string]*metrics.MetricsByLabel, watched []*validator.WatchedValidator) {
+	if w.influx == nil {
+		return
+	}
+
+	at := time.Now()
+	if w.clock != nil {
+		at = w.clock.SlotStartTime(w.clock.EpochToSlot(epoch))
+	}
+	network := w.config.Network
+	rewardsEpoch := uint64(w.taskSlots.rewardsEpoch(epoch))
+
+	points := make([]influx.Point, 0, len(metricsByLabel)+len(watched))
+	for label, m := range metricsByLabel {
+		points = append(points, influx.Point{
+			Measurement: influxLabelMeasurement,
+			Tags:        map[string]string{"network": network, "label": label},
+			Fields: map[string]interface{}{
+				"epoch":                      uint64(epoch),
+				"rewards_epoch":              rewardsEpoch,
+				"validators":                 m.ValidatorCount,
+				"stake":                      m.StakeCount,
+				"missed_attestations":        m.MissedAttestations,
+				"attestation_duties":         m.AttestationDuties,
+				"attestation_duties_success": m.AttestationDutiesSuccess,
+				"attestation_duties_rate":    m.AttestationDutiesRate,
+				"ideal_consensus_rewards":    uint64(m.IdealConsensusRewards),
+				"consensus_rewards":          int64(m.ConsensusRewards),
+				"consensus_rewards_rate":     m.ConsensusRewardsRate,
+				"proposed_blocks":            m.ProposedBlocks,
+				"missed_blocks":              m.MissedBlocks,
+				"inclusion_delay_avg":        m.InclusionDelayAvg,
+			},
+			Time: at,
+		})
+	}
+
+	if w.config.Influx.WritesValidators() {
+		for _, v := range watched {
+			points = append(points, influx.Point{
+				Measurement: influxValidatorMeasurement,
+				Tags: map[string]string{
+					"network":         network,
+					"validator_index": fmt.Sprintf("%d", w.anonymizer.Index(v.Index)),
+					"label":           primaryLabel(v.Labels),
+				},
+				Fields: map[string]interface{}{
+					"epoch":                      uint64(epoch),
+					"rewards_epoch":              rewardsEpoch,
+					"pubkey":                     w.anonymizer.Pubkey(v.Data.Pubkey),
+					"status":                     string(v.Status),
+					"balance_gwei":               uint64(v.Balance),
+					"effective_balance_gwei":     uint64(v.Data.EffectiveBalance),
+					"missed_attestations":        v.MissedAttestations,
+					"consecutive_missed":         v.ConsecutiveMissedAttest,
+					"attestation_duties":         v.AttestationDuties,
+					"attestation_duties_success": v.AttestationDutiesSuccess,
+					"ideal_consensus_rewards":    uint64(v.IdealConsensusRewards),
+					"consensus_rewards":          int64(v.ConsensusRewards),
+					"proposed_blocks":            v.ProposedBlocks,
+					"missed_blocks":              v.MissedBlocks,
+					"inclusion_delay_sum":        v.InclusionDelaySum,
+					"inclusion_delay_count":      v.InclusionDelayCount,
+				},
+				Time: at,
+			})
+		}
+	}
+
+	w.influx.Write(points)
+}
@@ -0,0 +1,57 @@
+package watcher
+
+import (
+	"sort"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/duties"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// observeLiability moves every watched validator through its duty liability states for an epoch,
+// exports the count per state and returns the validators owing an attestation in the epoch, sorted
+func (w *ValidatorWatcher) observeLiability(epoch models.Epoch) []models.ValidatorIndex {
+	counts := make(map[string]int, len(duties.Liabilities))
+	for _, state := range duties.Liabilities {
+		counts[string(state)] = 0
+	}
+
+	watched := w.watchedValidators.GetAll()
+	attesters := make([]models.ValidatorIndex, 0, len(watched))
+	for _, v := range watched {
+		state, transition := w.liability.Observe(&v.Validator, epoch)
+		counts[string(state)]++
+		if state.Attests() {
+			attesters = append(attesters, v.Index)
+		}
+		if transition != nil {
+			w.logger.WithFields(logrus.Fields{
+				"validator":  w.anonymizer.Index(v.Index),
+				"pubkey":     w.logPubkey(v.Data.Pubkey),
+				"label":      primaryLabel(v.Labels),
+				"epoch":      epoch,
+				"from":       transition.From,
+				"to":         transition.To,
+				"exit_epoch": v.Data.ExitEpoch,
+			}).Info("Validator duty liability changed")
+		}
+	}
+
+	w.liability.Retain(func(index models.ValidatorIndex) bool {
+		_, ok := w.watchedValidators.Get(index)
+		return ok
+	})
+	w.prometheusMetrics.SetDutyLiability(w.config.Network, counts)
+
+	sort.Slice(attesters, func(i, j int) bool { return attesters[i] < attesters[j] })
+	return attesters
+}
+
+// owesAttestations reports whether a watched validator owes attestations, by its last observed
+// liability or, before the first observation, by its status
+func (w *ValidatorWatcher) owesAttestations(index models.ValidatorIndex, status models.ValidatorStatus) bool {
+	if state, ok := w.liability.Of(index); ok {
+		return state.Attests()
+	}
+	return status == models.StatusActiveOngoing || status == models.StatusActiveExiting || status == models.StatusActiveSlashed
+}
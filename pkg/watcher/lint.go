@@ -0,0 +1,110 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/alert"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/clock"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/lint"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// Lint resolves the watched keys from every source and reports the configurations correlated with slashing risk
+// It only reads the offline keys' on-chain records, so it runs without loading the validator set
+func (w *ValidatorWatcher) Lint(ctx context.Context) ([]lint.Finding, error) {
+	if err := w.loadRemoteKeys(ctx); err != nil {
+		return nil, fmt.Errorf("failed to load watched keys from URL: %w", err)
+	}
+	if err := w.loadSignerKeys(ctx); err != nil {
+		return nil, fmt.Errorf("failed to load watched keys from Web3Signer: %w", err)
+	}
+	if err := w.loadDVTKeys(ctx); err != nil {
+		return nil, fmt.Errorf("failed to load distributed validator keys: %w", err)
+	}
+
+	var validators []models.Validator
+	if offline := lint.Offline(w.config.WatchedKeys); len(offline) > 0 {
+		pubkeys := make([]string, len(offline))
+		for i, wk := range offline {
+			pubkeys[i] = wk.PublicKey
+		}
+		var err error
+		validators, err = w.beaconClient.GetValidatorsByPubkeys(ctx, "head", pubkeys)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get offline validators: %w", err)
+		}
+
+		if w.clock == nil {
+			if genesis, err := w.beaconClient.GetGenesis(ctx); err == nil {
+				if spec, err := w.beaconClient.GetSpec(ctx); err == nil {
+					w.clock = clock.NewBeaconClock(genesis, spec, w.logger)
+				}
+			}
+		}
+	}
+
+	return w.lintFindings(ctx, validators), nil
+}
+
+// warnLintFindings logs the critical slashing-risk findings of the watched set at startup
+func (w *ValidatorWatcher) warnLintFindings(ctx context.Context) {
+	watched := w.watchedValidators.GetAll()
+	validators := make([]models.Validator, len(watched))
+	for i, v := range watched {
+		validators[i] = v.Validator
+	}
+
+	findings := w.lintFindings(ctx, validators)
+	critical := 0
+	for _, f := range findings {
+		if f.Severity != alert.SeverityCritical {
+			continue
+		}
+		critical++
+		w.logger.WithFields(logrus.Fields{
+			"check":  f.Check,
+			"pubkey": w.logPubkey(f.Pubkey),
+		}).Warn("⚠️ SLASHING RISK: " + f.Message)
+	}
+
+	if len(findings) > critical {
+		w.logger.WithField("findings", len(findings)-critical).Info("Less severe slashing-risk findings in the watched set - run with -lint for details")
+	}
+}
+
+// lintFindings checks the watched key sources and compares the offline keys against their on-chain records
+// validators are the on-chain records of (at least) the offline keys; liveness needs the clock
+func (w *ValidatorWatcher) lintFindings(ctx context.Context, validators []models.Validator) []lint.Finding {
+	findings := lint.CheckKeys([]lint.Source{
+		{Name: "watched_keys", Keys: w.configuredKeys},
+		{Name: "watched_keys_url", Keys: w.remoteKeys},
+		{Name: "web3signer", Keys: w.signerKeys},
+		{Name: "dvt", Keys: w.dvtKeys},
+	})
+
+	offline := lint.Offline(w.config.WatchedKeys)
+	if len(offline) == 0 {
+		return findings
+	}
+
+	var live map[models.ValidatorIndex]bool
+	if w.clock != nil && w.clock.CurrentEpoch() > 0 {
+		if indices := lint.Indices(offline, validators); len(indices) > 0 {
+			epoch := w.clock.CurrentEpoch() - 1
+			liveness, err := w.beaconClient.GetValidatorsLiveness(ctx, epoch, indices)
+			if err != nil {
+				w.logger.WithError(err).Warn("Failed to get liveness of offline validators - only checking their status")
+			}
+			live = make(map[models.ValidatorIndex]bool, len(liveness))
+			for _, l := range liveness {
+				live[l.Index] = l.IsLive
+			}
+		}
+	}
+
+	findings = append(findings, lint.CheckOffline(offline, validators, live)...)
+	lint.Sort(findings)
+	return findings
+}
@@ -0,0 +1,69 @@
+package watcher
+
+import (
+	"fmt"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/alert"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// checkProposalLookahead warns once per proposal when a watched validator proposes within
+// proposal_lookahead.slots and missed its latest attestations, while there is still time to fix its node
+func (w *ValidatorWatcher) checkProposalLookahead(slot models.Slot) {
+	cfg := w.config.ProposalLookahead
+	if cfg.Slots <= 0 || w.proposerSchedule == nil || w.warmup {
+		return
+	}
+
+	for s := range w.proposalsWarned {
+		if s <= slot {
+			delete(w.proposalsWarned, s)
+		}
+	}
+	if w.proposalsWarned == nil {
+		w.proposalsWarned = make(map[models.Slot]bool)
+	}
+
+	horizon := slot + models.Slot(cfg.Slots)
+	for _, duty := range w.proposerSchedule.Upcoming(slot + 1) {
+		if duty.Slot > horizon {
+			break
+		}
+		v, ok := w.watchedValidators.Get(duty.ValidatorIndex)
+		if !ok || v.Cohort || w.proposalsWarned[duty.Slot] || v.ConsecutiveMissedAttest < cfg.MinConsecutiveMissed {
+			continue
+		}
+		w.proposalsWarned[duty.Slot] = true
+
+		label := primaryLabel(v.Labels)
+		slotsAway := duty.Slot - slot
+		eta := w.clock.SlotStartTime(duty.Slot).UTC()
+
+		w.logger.WithFields(logrus.Fields{
+			"slot":               duty.Slot,
+			"slots_away":         slotsAway,
+			"validator_index":    w.anonymizer.Index(v.Index),
+			"pubkey":             w.logPubkey(v.Data.Pubkey),
+			"label":              label,
+			"consecutive_missed": v.ConsecutiveMissedAttest,
+		}).Warn("⚠️ UPCOMING PROPOSAL AT RISK")
+
+		go w.sendAlert(alert.Alert{
+			Severity: alert.SeverityWarning,
+			Title:    fmt.Sprintf("Watched validator %d proposes in %d slots but is missing attestations", w.anonymizer.Index(v.Index), slotsAway),
+			Text: fmt.Sprintf("Validator %d (%s) proposes at slot %d (%s) and missed its last %d attestations - check its node before the block is lost",
+				w.anonymizer.Index(v.Index), label, duty.Slot, eta.Format("15:04:05 MST"), v.ConsecutiveMissedAttest),
+			Fields: map[string]string{
+				"network":            w.config.Network,
+				"validator":          fmt.Sprintf("%d", w.anonymizer.Index(v.Index)),
+				"pubkey":             w.logPubkey(v.Data.Pubkey),
+				"label":              label,
+				"slot":               fmt.Sprintf("%d", duty.Slot),
+				"slots_away":         fmt.Sprintf("%d", slotsAway),
+				"consecutive_missed": fmt.Sprintf("%d", v.ConsecutiveMissedAttest),
+			},
+			Key: fmt.Sprintf("proposal_at_risk:%d", duty.Slot),
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package watcher
+
+import (
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/membership"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// recordMembership appends what changed in the watched validators' label membership and
+// lifecycle since the last observation to the membership feed
+func (w *ValidatorWatcher) recordMembership(epoch models.Epoch) {
+	watched := w.watchedValidators.GetAll()
+	members := make([]membership.Member, 0, len(watched))
+	for _, v := range watched {
+		labels := make([]string, 0, len(v.Labels))
+		for _, label := range v.Labels {
+			// Every watched validator carries scope:watched; the network scope says nothing about membership
+			if label != "scope:all-network" {
+				labels = append(labels, label)
+			}
+		}
+		validator := v.Validator
+		validator.Index = w.anonymizer.Index(v.Index)
+		members = append(members, membership.Member{
+			Validator: validator,
+			Pubkey:    w.anonymizer.Pubkey(v.Data.Pubkey),
+			Labels:    labels,
+		})
+	}
+
+	changes, err := w.membership.Observe(epoch, members, time.Now())
+	if err != nil {
+		w.logger.WithError(err).Warn("Failed to persist membership changes")
+	}
+	if len(changes) > 0 {
+		w.logger.WithFields(logrus.Fields{
+			"epoch":   epoch,
+			"changes": len(changes),
+		}).Info("Recorded membership changes")
+	}
+}
@@ -0,0 +1,95 @@
+package watcher
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/alert"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/validator"
+)
+
+// checkConsecutiveMissed raises a critical alert when a validator's run of missed attestations
+// reaches critical_alerts.consecutive_missed_attestations
+// It fires once per run: the count only passes the threshold again after an attestation resets it
+func (w *ValidatorWatcher) checkConsecutiveMissed(v *validator.WatchedValidator, consecutive uint64, slot models.Slot, epoch models.Epoch) {
+	threshold := w.config.CriticalAlerts.ConsecutiveMissedAttestations
+	if threshold == 0 || consecutive != threshold || v.Cohort {
+		return
+	}
+
+	label := primaryLabel(v.Labels)
+	go w.sendAlert(alert.Alert{
+		Severity: alert.SeverityCritical,
+		Title:    fmt.Sprintf("Watched validator %d missed %d attestations in a row", w.anonymizer.Index(v.Index), consecutive),
+		Text:     fmt.Sprintf("Validator %d (%s) missed its last %d attestations, the latest in epoch %d", w.anonymizer.Index(v.Index), label, consecutive, epoch),
+		Fields: map[string]string{
+			"network":   w.config.Network,
+			"validator": fmt.Sprintf("%d", w.anonymizer.Index(v.Index)),
+			"pubkey":    w.logPubkey(v.Data.Pubkey),
+			"label":     label,
+			"slot":      fmt.Sprintf("%d", slot),
+			"epoch":     fmt.Sprintf("%d", epoch),
+		},
+		Key: fmt.Sprintf("consecutive_missed:%d", w.anonymizer.Index(v.Index)),
+	})
+}
+
+// checkLabelOffline raises a critical alert for every aggregated label with more than
+// critical_alerts.label_offline_percent of its validators not live in an epoch
+// A label alerts once while above the threshold and again only after it recovered
+func (w *ValidatorWatcher) checkLabelOffline(epoch models.Epoch, live map[models.ValidatorIndex]bool) {
+	threshold := w.config.CriticalAlerts.LabelOfflinePercent
+	if threshold == 0 {
+		return
+	}
+
+	total := make(map[string]int)
+	offline := make(map[string]int)
+	for index, isLive := range live {
+		v, ok := w.watchedValidators.Get(index)
+		if !ok || v.Cohort {
+			continue
+		}
+		for _, label := range w.aggregatedScopes(v.Labels) {
+			total[label]++
+			if !isLive {
+				offline[label]++
+			}
+		}
+	}
+
+	labels := make([]string, 0, len(offline))
+	for label := range offline {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	alerted := make(map[string]bool)
+	for _, label := range labels {
+		percent := float64(offline[label]) * 100 / float64(total[label])
+		if percent <= threshold {
+			continue
+		}
+		alerted[label] = true
+		if w.labelsOffline[label] {
+			continue
+		}
+
+		go w.sendAlert(alert.Alert{
+			Severity: alert.SeverityCritical,
+			Title:    fmt.Sprintf("%.1f%% of %s validators offline", percent, label),
+			Text:     fmt.Sprintf("%d of %d validators labelled %s were not live in epoch %d", offline[label], total[label], label, epoch),
+			Fields: map[string]string{
+				"network":   w.config.Network,
+				"label":     label,
+				"epoch":     fmt.Sprintf("%d", epoch),
+				"offline":   fmt.Sprintf("%d", offline[label]),
+				"total":     fmt.Sprintf("%d", total[label]),
+				"threshold": fmt.Sprintf("%.1f%%", threshold),
+			},
+			Key: fmt.Sprintf("label_offline:%s", label),
+		})
+	}
+	w.labelsOffline = alerted
+}
@@ -0,0 +1,60 @@
+package watcher
+
+import (
+	"sort"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/api"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/metrics"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/queues"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/validator"
+	"github.com/sirupsen/logrus"
+)
+
+// updateQueuePositions estimates where the watched validators stand in the activation and exit
+// queues from the epoch's pending deposits and, when it is loaded, the full validator set
+// Without the full set only the activation and withdrawable epochs already scheduled are exported
+func (w *ValidatorWatcher) updateQueuePositions(epoch models.Epoch, deposits []models.PendingDeposit) {
+	estimator := queues.NewEstimator(w.churn, epoch, deposits)
+	if w.config.ShouldLoadAllValidators() && w.allValidators.Count() > 0 {
+		w.allValidators.Each(estimator.Scan)
+	}
+
+	watched := w.watchedValidators.GetAll()
+	sort.Slice(watched, func(i, j int) bool { return watched[i].Index < watched[j].Index })
+	byIndex := make(map[models.ValidatorIndex]*validator.WatchedValidator, len(watched))
+	candidates := make([]*models.Validator, len(watched))
+	for i, v := range watched {
+		byIndex[v.Index] = v
+		candidates[i] = &v.Validator
+	}
+
+	var estimates *queues.Network
+	if network, ok := estimator.Network(); ok {
+		estimates = &network
+	}
+
+	positions := estimator.Positions(candidates)
+	metricPositions := make([]metrics.QueuePosition, len(positions))
+	apiPositions := make([]api.QueuePosition, len(positions))
+	for i, p := range positions {
+		v := byIndex[p.ValidatorIndex]
+		metricPositions[i] = metrics.QueuePosition{
+			Index:     w.anonymizer.Index(p.ValidatorIndex),
+			Label:     primaryLabel(v.Labels),
+			Queue:     p.Queue,
+			Ahead:     float64(p.Ahead) / float64(w.stakeUnit),
+			ETAEpochs: p.ETAEpochs,
+		}
+		apiPositions[i] = api.QueuePosition{Position: p, Labels: v.Labels}
+	}
+	w.prometheusMetrics.SetQueuePositions(w.config.Network, metricPositions, estimates)
+	w.apiServer.UpdateQueues(api.Queues{Epoch: epoch, Network: estimates, Validators: apiPositions})
+
+	fields := logrus.Fields{"epoch": epoch, "queued_watched": len(positions)}
+	if estimates != nil {
+		fields["activation_queue_epochs"] = estimates.ActivationEpochs
+		fields["exit_queue_epochs"] = estimates.ExitEpochs
+	}
+	w.logger.WithFields(fields).Debug("Updated queue positions")
+}
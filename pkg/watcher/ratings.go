@@ -0,0 +1,70 @@
+package watcher
+
+import (
+	"context"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/metrics"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/ratings"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/refresh"
+)
+
+// startRatingChecks pulls the third-party ratings of the active watched validators once per refresh
+// interval; it checks every epoch so the first pull waits only for the watched validators to load
+// and a failed pull is retried an epoch later rather than a day later
+func (w *ValidatorWatcher) startRatingChecks(ctx context.Context) {
+	if w.ratingsClient == nil || w.clock == nil {
+		return
+	}
+
+	interval := w.config.ExternalRatings.Refresh.ToDuration()
+	epoch := time.Duration(w.clock.SlotsPerEpoch()*w.clock.SecondsPerSlot()) * time.Second
+	w.externalRatings = refresh.New("external_ratings", epoch, func(ctx context.Context) (ratings.Effectiveness, error) {
+		if _, updated, ok := w.externalRatings.Value(); ok && time.Since(updated) < interval {
+			return nil, refresh.ErrSkipped
+		}
+		var indices []models.ValidatorIndex
+		for _, v := range w.watchedValidators.GetAll() {
+			if isActiveStatus(v.Status) && !v.Cohort {
+				indices = append(indices, v.Index)
+			}
+		}
+		if len(indices) == 0 {
+			return nil, refresh.ErrSkipped
+		}
+		return w.ratingsClient.Effectiveness(ctx, indices)
+	}, w.logger)
+	w.externalRatings.Start(ctx)
+}
+
+// updateExternalRatings exports the last pulled ratings averaged over each scope's rated validators
+func (w *ValidatorWatcher) updateExternalRatings() {
+	if w.externalRatings == nil {
+		return
+	}
+	effectiveness, _, ok := w.externalRatings.Value()
+	if !ok {
+		return
+	}
+
+	byScope := make(map[string]metrics.ExternalRating)
+	for _, v := range w.watchedValidators.GetAll() {
+		rating, rated := effectiveness[v.Index]
+		if !rated || v.Cohort {
+			continue
+		}
+		for _, scope := range w.aggregatedScopes(v.Labels) {
+			r := byScope[scope]
+			r.Effectiveness += rating
+			r.Validators++
+			byScope[scope] = r
+		}
+	}
+	for scope, r := range byScope {
+		r.Effectiveness /= float64(r.Validators)
+		byScope[scope] = r
+	}
+
+	w.prometheusMetrics.SetExternalRatings(w.config.Network, w.ratingsClient.Provider(), byScope)
+}
@@ -0,0 +1,105 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/alert"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/refresh"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/relay"
+	"github.com/sirupsen/logrus"
+)
+
+// maxRelayAlertExamples caps the pubkeys listed in a relay registration alert
+const maxRelayAlertExamples = 5
+
+// startRelayChecks looks up the active watched validators on the configured relays once per epoch
+func (w *ValidatorWatcher) startRelayChecks(ctx context.Context) {
+	if w.relayClient == nil || w.clock == nil {
+		return
+	}
+
+	epoch := time.Duration(w.clock.SlotsPerEpoch()*w.clock.SecondsPerSlot()) * time.Second
+	w.relayRegistrations = refresh.New("mev_relays", epoch, func(ctx context.Context) (relay.Registrations, error) {
+		return w.relayClient.Registrations(ctx, w.activeWatchedPubkeys())
+	}, w.logger)
+	w.relayRegistrations.Start(ctx)
+}
+
+// activeWatchedPubkeys returns the pubkeys of the watched validators that are expected to propose
+func (w *ValidatorWatcher) activeWatchedPubkeys() []string {
+	var pubkeys []string
+	for _, v := range w.watchedValidators.GetAll() {
+		if isActiveStatus(v.Status) && !v.Cohort {
+			pubkeys = append(pubkeys, v.Data.Pubkey)
+		}
+	}
+	return pubkeys
+}
+
+// updateRelayRegistrations exports the last relay lookup and alerts on validators that
+// became unregistered with every relay
+func (w *ValidatorWatcher) updateRelayRegistrations() {
+	if w.relayRegistrations == nil {
+		return
+	}
+	registrations, _, ok := w.relayRegistrations.Value()
+	if !ok {
+		return
+	}
+
+	registered := make(map[string]int, len(w.config.MEVRelays))
+	for _, r := range w.config.MEVRelays {
+		name := relay.Name(r)
+		registered[name] = registrations.Registered(name)
+	}
+	unregistered := registrations.Unregistered(w.activeWatchedPubkeys())
+	w.prometheusMetrics.SetRelayRegistrations(w.config.Network, registered, len(unregistered))
+
+	// Only newly unregistered validators are alerted, once
+	missing := make(map[string]bool, len(unregistered))
+	var newlyMissing []string
+	for _, pubkey := range unregistered {
+		missing[pubkey] = true
+		if !w.relayMissing[pubkey] {
+			newlyMissing = append(newlyMissing, pubkey)
+		}
+	}
+	w.relayMissing = missing
+
+	if len(newlyMissing) == 0 {
+		return
+	}
+
+	examples := make([]string, 0, maxRelayAlertExamples)
+	for _, pubkey := range newlyMissing {
+		if len(examples) == maxRelayAlertExamples {
+			break
+		}
+		examples = append(examples, w.logPubkey(pubkey))
+	}
+
+	w.logger.WithFields(logrus.Fields{
+		"validators": len(newlyMissing),
+		"examples":   examples,
+	}).Warn("Watched validators are not registered with any MEV-Boost relay")
+
+	go w.sendAlert(alert.Alert{
+		Severity: alert.SeverityWarning,
+		Title:    "Validators missing from all MEV-Boost relays",
+		Text:     fmt.Sprintf("%d active watched validators have no registration on any of the %d configured relays", len(newlyMissing), len(w.config.MEVRelays)),
+		Fields: map[string]string{
+			"network":    w.config.Network,
+			"validators": fmt.Sprintf("%d", len(newlyMissing)),
+			"examples":   strings.Join(examples, ", "),
+		},
+	})
+}
+
+// isActiveStatus reports whether a validator with the status is expected to register with relays
+func isActiveStatus(status models.ValidatorStatus) bool {
+	return strings.HasPrefix(string(status), "active_")
+}
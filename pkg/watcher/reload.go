@@ -0,0 +1,207 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/batch"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/config"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/dvt"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/validator"
+	"github.com/sirupsen/logrus"
+)
+
+// RequestReload asks for the config to be reloaded at the next slot (e.g. on SIGHUP)
+// The reload runs in the slot loop, so it never races with duty processing
+func (w *ValidatorWatcher) RequestReload() {
+	select {
+	case w.reloadRequests <- struct{}{}:
+	default:
+		// A reload is already pending
+	}
+}
+
+// reloadPending reports whether a reload was requested, without clearing the request: a reload task
+// the scheduler skips leaves it pending for the next slot
+func (w *ValidatorWatcher) reloadPending() bool {
+	return len(w.reloadRequests) > 0
+}
+
+// reloadRequested reports and clears a pending reload request, as the reload runs
+func (w *ValidatorWatcher) reloadRequested() bool {
+	select {
+	case <-w.reloadRequests:
+		return true
+	default:
+		return false
+	}
+}
+
+// reloadConfig re-reads the config file and applies watched_keys changes without a restart
+// Other settings need a restart; an invalid file keeps the current config
+// A requested reload (SIGHUP) resets the counters under the reload policy even if nothing changed
+func (w *ValidatorWatcher) reloadConfig(ctx context.Context, requested bool) error {
+	if w.config.Path == "" {
+		w.logger.Debug("Config reload skipped - config was not loaded from a file")
+		return nil
+	}
+
+	cfg, err := config.LoadConfig(w.config.Path)
+	if err != nil {
+		return err
+	}
+
+	// Keys from the URL and DVT clusters aren't in the file, so they are merged in again
+	w.configuredKeys = cfg.WatchedKeys
+	diff := w.applyWatchedKeys("config_reload", w.mergedWatchedKeys())
+	if diff.IsEmpty() {
+		if requested && w.resetPolicy == validator.ResetReload {
+			w.resetCounters("reload_requested")
+		}
+		return nil
+	}
+
+	return w.reconcileWatchedValidators(ctx)
+}
+
+// mergedWatchedKeys combines the configured, remote, Web3Signer and DVT keys, then the comparison cohorts
+// Keys present in several sources collect the labels of all of them
+func (w *ValidatorWatcher) mergedWatchedKeys() []models.WatchedKey {
+	merged := dvt.Merge(dvt.Merge(dvt.Merge(w.configuredKeys, w.remoteKeys), w.signerKeys), w.dvtKeys)
+	return validator.MergeCohorts(merged, w.cohortKeys)
+}
+
+// loadRemoteKeys adds the keys of watched_keys_url to the watched keys at startup
+func (w *ValidatorWatcher) loadRemoteKeys(ctx context.Context) error {
+	if w.config.WatchedKeysURL == "" {
+		return nil
+	}
+
+	keys, err := config.FetchWatchedKeys(ctx, w.keysClient, w.config.WatchedKeysURL, w.config.WatchedKeysHeaders)
+	if err != nil {
+		return err
+	}
+
+	w.remoteKeys = keys
+	w.setWatchedKeys(w.mergedWatchedKeys())
+	w.logger.WithFields(logrus.Fields{
+		"remote_keys": len(keys),
+		"total":       len(w.config.WatchedKeys),
+	}).Info("Added watched keys from URL")
+
+	return nil
+}
+
+// refreshRemoteKeys refetches watched_keys_url and applies the changes without a restart
+// A failed fetch keeps the previous list, so an outage of the key service never drops validators
+func (w *ValidatorWatcher) refreshRemoteKeys(ctx context.Context) error {
+	keys, err := config.FetchWatchedKeys(ctx, w.keysClient, w.config.WatchedKeysURL, w.config.WatchedKeysHeaders)
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 && len(w.remoteKeys) > 0 {
+		return fmt.Errorf("refusing to replace %d keys with an empty list", len(w.remoteKeys))
+	}
+
+	w.remoteKeys = keys
+	diff := w.applyWatchedKeys("watched_keys_url", w.mergedWatchedKeys())
+	if diff.IsEmpty() {
+		return nil
+	}
+
+	return w.reconcileWatchedValidators(ctx)
+}
+
+// loadSignerKeys adds the keys loaded in the Web3Signer instance to the watched keys at startup
+func (w *ValidatorWatcher) loadSignerKeys(ctx context.Context) error {
+	signer := w.config.Web3Signer
+	if signer.URL == "" {
+		return nil
+	}
+
+	keys, err := config.FetchWeb3SignerKeys(ctx, w.keysClient, signer.URL, signer.Headers, signer.Labels)
+	if err != nil {
+		return err
+	}
+
+	w.signerKeys = keys
+	w.setWatchedKeys(w.mergedWatchedKeys())
+	w.logger.WithFields(logrus.Fields{
+		"signer_keys": len(keys),
+		"total":       len(w.config.WatchedKeys),
+	}).Info("Added watched keys from Web3Signer")
+
+	return nil
+}
+
+// refreshSignerKeys relists the Web3Signer keys and applies the changes without a restart
+// A failed listing keeps the previous keys, so a signer restart never drops validators
+func (w *ValidatorWatcher) refreshSignerKeys(ctx context.Context) error {
+	signer := w.config.Web3Signer
+	keys, err := config.FetchWeb3SignerKeys(ctx, w.keysClient, signer.URL, signer.Headers, signer.Labels)
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 && len(w.signerKeys) > 0 {
+		return fmt.Errorf("refusing to replace %d keys with an empty list", len(w.signerKeys))
+	}
+
+	w.signerKeys = keys
+	diff := w.applyWatchedKeys("web3signer", w.mergedWatchedKeys())
+	if diff.IsEmpty() {
+		return nil
+	}
+
+	return w.reconcileWatchedValidators(ctx)
+}
+
+// reconcileWatchedValidators applies the current watched keys to the registry right away
+// Validators that stay watched keep their counters; only added keys are fetched
+func (w *ValidatorWatcher) reconcileWatchedValidators(ctx context.Context) error {
+	vals := make([]models.Validator, 0, len(w.config.WatchedKeys))
+	unresolved := make([]string, 0)
+	for _, wk := range w.config.WatchedKeys {
+		if v, ok := w.watchedValidators.GetByPubkey(wk.PublicKey); ok {
+			vals = append(vals, v.Validator)
+		} else if v, ok := w.allValidators.GetByPubkey(wk.PublicKey); ok {
+			vals = append(vals, *v)
+			w.indexCache.Set(wk.PublicKey, v.Index)
+		} else {
+			unresolved = append(unresolved, wk.PublicKey)
+		}
+	}
+
+	batchSize := w.config.Startup.BatchSize
+	for i := 0; i < batch.Count(len(unresolved), batchSize); i++ {
+		start := i * batchSize
+		end := min(start+batchSize, len(unresolved))
+		fetched, err := w.beaconClient.GetValidatorsByPubkeys(ctx, "head", unresolved[start:end])
+		if err != nil {
+			return fmt.Errorf("failed to get added watched validators: %w", err)
+		}
+		for _, v := range fetched {
+			w.indexCache.Set(v.Data.Pubkey, v.Index)
+		}
+		vals = append(vals, fetched...)
+	}
+	if err := w.indexCache.Save(); err != nil {
+		w.logger.WithError(err).Warn("Failed to persist index cache")
+	}
+
+	w.watchedValidators.Reconcile(vals, w.watchedKeys())
+	if w.resetPolicy == validator.ResetReload {
+		w.resetCounters("watched_keys_changed")
+	}
+	w.recordMembership(w.clock.CurrentEpoch())
+	w.heatmap.Retain(func(index models.ValidatorIndex) bool {
+		_, ok := w.watchedValidators.Get(index)
+		return ok
+	})
+
+	w.logger.WithFields(logrus.Fields{
+		"watched":   w.watchedValidators.Count(),
+		"not_found": len(w.config.WatchedKeys) - len(vals),
+	}).Info("Applied watched keys without restart")
+	return nil
+}
@@ -0,0 +1,125 @@
+package watcher
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/clock"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/events"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/metrics"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sirupsen/logrus"
+)
+
+// memorySink collects events in memory for tests
+type memorySink struct {
+	mu     sync.Mutex
+	events []events.Event
+}
+
+func (m *memorySink) Write(event events.Event) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = append(m.events, event)
+	return nil
+}
+
+func (m *memorySink) Close() error { return nil }
+
+func TestApplyWatchedKeysReportsDiff(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	stream := events.NewStream(10, logger)
+	sink := &memorySink{}
+	stream.AddSink(sink)
+
+	w := &ValidatorWatcher{
+		config: &models.Config{
+			Network: "mainnet",
+			WatchedKeys: []models.WatchedKey{
+				{PublicKey: "0xaaa", Labels: []string{"operator:a"}},
+				{PublicKey: "0xbbb", Labels: []string{"operator:a"}},
+			},
+		},
+		prometheusMetrics: metrics.NewPrometheusMetrics(prometheus.NewRegistry()),
+		events:            stream,
+		logger:            logger,
+	}
+
+	// A reload replaces the configured keys and applies them merged with the other sources
+	w.configuredKeys = []models.WatchedKey{
+		{PublicKey: "0xbbb", Labels: []string{"operator:b"}},
+		{PublicKey: "0xccc", Labels: []string{"operator:a"}},
+	}
+	diff := w.applyWatchedKeys("config_reload", w.mergedWatchedKeys())
+
+	if len(diff.Added) != 1 || len(diff.Removed) != 1 || len(diff.Relabeled) != 1 {
+		t.Fatalf("Expected one added, removed and relabeled key, got %+v", diff)
+	}
+	if len(w.config.WatchedKeys) != 2 {
+		t.Errorf("Expected the watched keys replaced, got %v", w.config.WatchedKeys)
+	}
+	for _, change := range []string{"added", "removed", "relabeled"} {
+		if got := testutil.ToFloat64(w.prometheusMetrics.WatchlistChangesTotal.WithLabelValues(change, "mainnet")); got != 1 {
+			t.Errorf("Expected 1 %s change counted, got %v", change, got)
+		}
+	}
+
+	// Applying the same keys again changes nothing
+	if diff := w.applyWatchedKeys("config_reload", w.mergedWatchedKeys()); !diff.IsEmpty() {
+		t.Errorf("Expected no changes on an identical reload, got %+v", diff)
+	}
+
+	if err := stream.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if len(sink.events) != 1 || sink.events[0].Type != events.TypeWatchlistChanged {
+		t.Fatalf("Expected a single watchlist_changed event, got %v", sink.events)
+	}
+	if sink.events[0].Data["source"] != "config_reload" {
+		t.Errorf("Expected the event to name its source, got %v", sink.events[0].Data["source"])
+	}
+}
+
+func TestRequestedReloadSurvivesSkippedTask(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	cfg := &models.Config{Network: "mainnet", ConfigReloadSlot: 15}
+	spec := &models.Spec{SecondsPerSlot: 12, SlotsPerEpoch: 32}
+	w := &ValidatorWatcher{
+		config:         cfg,
+		clock:          clock.NewBeaconClock(&models.Genesis{}, spec, logger),
+		taskSlots:      newTaskSlots(cfg, 32, logger),
+		reloadRequests: make(chan struct{}, 1),
+		logger:         logger,
+	}
+	reloadTask := func(slot models.Slot) func(context.Context) error {
+		for _, task := range w.slotTasks(slot, w.clock.SlotToEpoch(slot)) {
+			if task.Name == "reload_config" {
+				return task.Run
+			}
+		}
+		return nil
+	}
+
+	w.RequestReload()
+	if reloadTask(130) == nil {
+		t.Fatal("Expected a requested reload to be scheduled at the next slot")
+	}
+	// The scheduler skipped it over budget: the request stays pending until the task runs
+	run := reloadTask(131)
+	if run == nil {
+		t.Fatal("Expected a skipped reload to be scheduled again")
+	}
+	if err := run(context.Background()); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if reloadTask(132) != nil {
+		t.Error("Expected the request cleared once the reload ran")
+	}
+}
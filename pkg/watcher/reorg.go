@@ -0,0 +1,268 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/events"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/proposer"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/reorg"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/validator"
+	"github.com/sirupsen/logrus"
+)
+
+// maxReorgSlots bounds how far back a reorg's proposals are re-evaluated
+// Older duties are cleaned up from the schedule anyway
+const maxReorgSlots = 64
+
+// outcomeUnassigned is the outcome of a slot that isn't (or no longer is) a watched validator's duty
+const outcomeUnassigned = "unassigned"
+
+// reorgWindow collects the slots changed by reorgs until the slot loop handles them
+// Events arrive on the event stream goroutine; counters are only touched in the slot loop
+type reorgWindow struct {
+	mu       sync.Mutex
+	pending  bool
+	from, to models.Slot
+}
+
+// add widens the window with the slots a reorg replaced
+func (r *reorgWindow) add(window reorg.Window) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.pending || window.From < r.from {
+		r.from = window.From
+	}
+	if !r.pending || window.To > r.to {
+		r.to = window.To
+	}
+	r.pending = true
+}
+
+// eventWindow returns the slots a node chain_reorg event replaced (after the common ancestor up to the new head)
+func eventWindow(event models.ChainReorgEvent) reorg.Window {
+	depth := event.Depth
+	if depth > maxReorgSlots {
+		depth = maxReorgSlots
+	}
+	if depth == 0 {
+		depth = 1
+	}
+	from := models.Slot(0)
+	if uint64(event.Slot)+1 > depth {
+		from = event.Slot + 1 - models.Slot(depth)
+	}
+	return reorg.Window{From: from, To: event.Slot}
+}
+
+// noteReorg queues a reorg's slots for re-evaluation and counts it, once however many sources report it
+func (w *ValidatorWatcher) noteReorg(window reorg.Window) bool {
+	w.reorgs.add(window)
+	if !w.blockRoots.Note(window) {
+		return false
+	}
+	w.prometheusMetrics.RecordReorg(w.config.Network, window.Depth())
+	return true
+}
+
+// trackBlockRoot records the root of a processed slot's block and reports a reorg when the block
+// doesn't build on the last processed one: an orphaned block, or a late one the head had missed
+func (w *ValidatorWatcher) trackBlockRoot(ctx context.Context, slot models.Slot) {
+	header, err := w.beaconClient.GetHeader(ctx, fmt.Sprintf("%d", slot))
+	if err != nil {
+		w.logger.WithError(err).Debug("Failed to get block header for reorg detection")
+		return
+	}
+	parentRoot := header.Header.Message.ParentRoot
+
+	parentSlot, ok := w.blockRoots.SlotOf(parentRoot)
+	if !ok {
+		parent, err := w.beaconClient.GetHeader(ctx, parentRoot)
+		if err != nil {
+			w.logger.WithError(err).Debug("Failed to get parent block header for reorg detection")
+			return
+		}
+		parentSlot = parent.Header.Message.Slot
+	}
+
+	window, reorged := w.blockRoots.Observe(slot, header.Root, parentSlot, parentRoot)
+	if !reorged || !w.noteReorg(window) {
+		return
+	}
+
+	w.events.Emit(events.Event{
+		Type:  events.TypeChainReorg,
+		Slot:  slot,
+		Epoch: w.clock.SlotToEpoch(slot),
+		Data: map[string]interface{}{
+			"depth":       window.Depth(),
+			"from_slot":   window.From,
+			"to_slot":     window.To,
+			"parent_root": parentRoot,
+			"source":      "block_roots",
+		},
+	})
+	w.logger.WithFields(logrus.Fields{
+		"slot":        slot,
+		"from_slot":   window.From,
+		"to_slot":     window.To,
+		"depth":       window.Depth(),
+		"parent_root": parentRoot,
+	}).Warn("🔀 Chain reorg detected from block roots")
+}
+
+// take returns and clears the pending window; a failed re-evaluation adds it back
+func (r *reorgWindow) take() (from, to models.Slot, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.pending {
+		return 0, 0, false
+	}
+	r.pending = false
+	return r.from, r.to, true
+}
+
+// slotOutcome is a reorged slot's watched proposal as the canonical chain now has it
+type slotOutcome struct {
+	proposer models.ValidatorIndex
+	watched  bool // Whether the slot is a watched validator's duty
+	proposed bool
+}
+
+// reevaluateReorg refetches the proposer duties of the epochs a reorg may have changed and corrects
+// the head outcome of the already processed slots in the window
+func (w *ValidatorWatcher) reevaluateReorg(ctx context.Context, from, to, current models.Slot) error {
+	if w.proposerSchedule == nil {
+		return nil
+	}
+
+	for epoch := w.clock.SlotToEpoch(from); epoch <= w.clock.SlotToEpoch(current)+1; epoch++ {
+		changes, err := w.proposerSchedule.Refresh(ctx, epoch)
+		if err != nil {
+			return err
+		}
+		for _, change := range changes {
+			_, oldWatched := w.watchedValidators.Get(change.Old)
+			_, newWatched := w.watchedValidators.Get(change.New)
+			if !oldWatched && !newWatched {
+				continue
+			}
+			w.logger.WithFields(logrus.Fields{
+				"slot":         change.Slot,
+				"old_proposer": w.anonymizer.Index(change.Old),
+				"new_proposer": w.anonymizer.Index(change.New),
+			}).Warn("Proposer duty changed after reorg")
+		}
+	}
+
+	// Only slots already processed had a head outcome recorded
+	if w.warmup || current == 0 || from >= current {
+		return nil
+	}
+	if to >= current {
+		to = current - 1
+	}
+
+	tracked := make(map[models.Slot]proposer.Proposal)
+	taken := w.finality.Take(from, to)
+	for _, p := range taken {
+		tracked[p.Slot] = p
+	}
+
+	// Look every outcome up before changing any counter, so a failure leaves them as they were
+	outcomes := make(map[models.Slot]slotOutcome)
+	for slot := from; slot <= to; slot++ {
+		proposerIndex, scheduled := w.proposerSchedule.GetProposer(slot)
+		if !scheduled {
+			continue
+		}
+		outcome := slotOutcome{proposer: proposerIndex}
+		if _, ok := w.watchedValidators.Get(proposerIndex); ok {
+			proposed, err := w.canonicalProposal(ctx, proposer.Proposal{Slot: slot, ValidatorIndex: proposerIndex})
+			if err != nil {
+				for _, p := range taken {
+					w.finality.Track(p)
+				}
+				return err
+			}
+			outcome.watched = true
+			outcome.proposed = proposed
+		}
+		outcomes[slot] = outcome
+	}
+
+	corrected := 0
+	for slot := from; slot <= to; slot++ {
+		p, wasTracked := tracked[slot]
+		outcome, known := outcomes[slot]
+		if !known {
+			// Duty no longer in the schedule: keep the recorded outcome
+			if wasTracked {
+				w.finality.Track(p)
+			}
+			continue
+		}
+
+		if outcome.watched {
+			w.finality.Track(proposer.Proposal{Slot: slot, ValidatorIndex: outcome.proposer, HeadProposed: outcome.proposed})
+		}
+		if wasTracked && outcome.watched && p.ValidatorIndex == outcome.proposer && p.HeadProposed == outcome.proposed {
+			continue
+		}
+		if !wasTracked && !outcome.watched {
+			continue
+		}
+
+		// Move the head outcome from what was seen when the slot was processed to the canonical one
+		before, after := outcomeUnassigned, outcomeUnassigned
+		if wasTracked {
+			before = proposalOutcome(p.HeadProposed)
+			w.countProposal(p.ValidatorIndex, p.HeadProposed, false)
+		}
+		if outcome.watched {
+			after = proposalOutcome(outcome.proposed)
+			w.countProposal(outcome.proposer, outcome.proposed, true)
+		}
+
+		corrected++
+		w.prometheusMetrics.RecordProposalReorgCorrection(w.config.Network, before, after)
+		fields := logrus.Fields{
+			"slot":     slot,
+			"proposer": w.anonymizer.Index(outcome.proposer),
+			"before":   before,
+			"after":    after,
+		}
+		if wasTracked && p.ValidatorIndex != outcome.proposer {
+			fields["previous_proposer"] = w.anonymizer.Index(p.ValidatorIndex)
+		}
+		w.logger.WithFields(fields).Warn("Block proposal outcome corrected after reorg")
+	}
+
+	w.prometheusMetrics.SetProposalsPendingFinality(w.config.Network, w.finality.Pending())
+	w.logger.WithFields(logrus.Fields{
+		"from_slot": from,
+		"to_slot":   to,
+		"corrected": corrected,
+	}).Info("Re-evaluated block proposals after reorg")
+	return nil
+}
+
+// countProposal adds (or removes) a head proposal outcome to a watched validator's counters
+func (w *ValidatorWatcher) countProposal(index models.ValidatorIndex, proposed, add bool) {
+	w.watchedValidators.UpdateMetrics(index, func(wv *validator.WatchedValidator) {
+		counter := &wv.MissedBlocks
+		if proposed {
+			counter = &wv.ProposedBlocks
+		}
+		switch {
+		case add:
+			*counter++
+		case *counter > 0:
+			*counter--
+		}
+	})
+}
@@ -0,0 +1,76 @@
+package watcher
+
+import (
+	"testing"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/reorg"
+)
+
+func TestEventWindow(t *testing.T) {
+	tests := []struct {
+		name     string
+		slot     models.Slot
+		depth    uint64
+		expected reorg.Window
+	}{
+		{"single slot", 100, 1, reorg.Window{From: 100, To: 100}},
+		{"depth without slots", 100, 0, reorg.Window{From: 100, To: 100}},
+		{"several slots", 100, 3, reorg.Window{From: 98, To: 100}},
+		{"at the bound", 100, maxReorgSlots, reorg.Window{From: 100 - maxReorgSlots + 1, To: 100}},
+		{"past the bound", 100, maxReorgSlots + 10, reorg.Window{From: 100 - maxReorgSlots + 1, To: 100}},
+		{"back to genesis", 2, 3, reorg.Window{From: 0, To: 2}},
+		{"deeper than the chain", 2, 5, reorg.Window{From: 0, To: 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			window := eventWindow(models.ChainReorgEvent{Slot: tt.slot, Depth: tt.depth})
+			if window != tt.expected {
+				t.Errorf("eventWindow() = %+v, want %+v", window, tt.expected)
+			}
+			if window.Depth() > maxReorgSlots {
+				t.Errorf("Window of %d slots exceeds the bound", window.Depth())
+			}
+		})
+	}
+}
+
+func TestReorgWindow(t *testing.T) {
+	tests := []struct {
+		name     string
+		added    []reorg.Window
+		expected reorg.Window
+	}{
+		{"single reorg", []reorg.Window{{From: 10, To: 12}}, reorg.Window{From: 10, To: 12}},
+		{"nested", []reorg.Window{{From: 10, To: 15}, {From: 11, To: 12}}, reorg.Window{From: 10, To: 15}},
+		{"overlapping", []reorg.Window{{From: 10, To: 12}, {From: 12, To: 14}}, reorg.Window{From: 10, To: 14}},
+		{"apart, earlier last", []reorg.Window{{From: 20, To: 21}, {From: 10, To: 10}}, reorg.Window{From: 10, To: 21}},
+		{"adjacent", []reorg.Window{{From: 10, To: 11}, {From: 12, To: 13}}, reorg.Window{From: 10, To: 13}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r reorgWindow
+			if _, _, ok := r.take(); ok {
+				t.Fatal("Empty window taken")
+			}
+			for _, window := range tt.added {
+				r.add(window)
+			}
+
+			from, to, ok := r.take()
+			if !ok || from != tt.expected.From || to != tt.expected.To {
+				t.Fatalf("take() = %d, %d, %v, want %+v", from, to, ok, tt.expected)
+			}
+			// Taking clears the window, so earlier slots don't widen the next one
+			if _, _, ok := r.take(); ok {
+				t.Error("Window taken twice")
+			}
+			r.add(reorg.Window{From: 30, To: 30})
+			if from, to, _ := r.take(); from != 30 || to != 30 {
+				t.Errorf("take() after a new reorg = %d, %d, want 30, 30", from, to)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/alert"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/metrics"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/scheduler"
+)
+
+// reportTask returns the report task when a scheduled report is due
+// The first due time is computed on the first call, so no report is sent right at startup
+func (w *ValidatorWatcher) reportTask(slot models.Slot, now time.Time) (scheduler.Task, bool) {
+	if w.reportSchedule == nil {
+		return scheduler.Task{}, false
+	}
+	if w.nextReport.IsZero() {
+		w.nextReport = w.reportSchedule.Next(now)
+		w.reportBaseline = w.watchedBlockTotals()
+		return scheduler.Task{}, false
+	}
+	if now.Before(w.nextReport) {
+		return scheduler.Task{}, false
+	}
+
+	w.nextReport = w.reportSchedule.Next(now)
+	return scheduler.Task{Name: "report", Priority: scheduler.PriorityIdle, Run: func(ctx context.Context) error {
+		w.sendAlert(w.buildReport(slot))
+		return nil
+	}}, true
+}
+
+// watchedBlockTotals returns the block proposal counters of all watched validators since startup
+func (w *ValidatorWatcher) watchedBlockTotals() metrics.BlockCounters {
+	return w.prometheusMetrics.BlockCounterState(w.config.Network)["scope:watched"].Totals
+}
+
+// buildReport summarizes the watched validators: the current epoch's attestation
+// performance and the block proposals since the previous report
+func (w *ValidatorWatcher) buildReport(slot models.Slot) alert.Alert {
+	totals := w.watchedBlockTotals()
+	proposed := totals.ProposedHead - w.reportBaseline.ProposedHead
+	missed := totals.MissedHead - w.reportBaseline.MissedHead
+	w.reportBaseline = totals
+
+	watched := &metrics.MetricsByLabel{}
+	if m, ok := w.aggregator.Compute(w.watchedValidators.GetAll())["scope:watched"]; ok {
+		watched = m
+	}
+
+	return alert.Alert{
+		Severity: alert.SeverityInfo,
+		Title:    "Validator report",
+		Text: fmt.Sprintf("%d validators, %.2f%% attestation success this epoch, %d blocks proposed and %d missed since the last report",
+			watched.ValidatorCount, watched.AttestationDutiesRate*100, proposed, missed),
+		Fields: map[string]string{
+			"network":                w.config.Network,
+			"epoch":                  fmt.Sprintf("%d", w.clock.SlotToEpoch(slot)),
+			"validators":             fmt.Sprintf("%d", watched.ValidatorCount),
+			"attestation_rate":       fmt.Sprintf("%.4f", watched.AttestationDutiesRate),
+			"consensus_rewards_rate": fmt.Sprintf("%.4f", watched.ConsensusRewardsRate),
+			"missed_attestations":    fmt.Sprintf("%d", watched.MissedAttestations),
+			"proposed_blocks":        fmt.Sprintf("%d", proposed),
+			"missed_blocks":          fmt.Sprintf("%d", missed),
+		},
+	}
+}
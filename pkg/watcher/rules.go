@@ -0,0 +1,60 @@
+package watcher
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/alert"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/metrics"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/rules"
+)
+
+// newRulesEngine builds the engine of the configured alert rules, nil if there are none
+func newRulesEngine(cfg []models.AlertRule) (*rules.Engine, error) {
+	if len(cfg) == 0 {
+		return nil, nil
+	}
+
+	parsed := make([]rules.Rule, 0, len(cfg))
+	for _, c := range cfg {
+		rule, err := rules.FromConfig(c)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, rule)
+	}
+	return rules.NewEngine(parsed), nil
+}
+
+// evaluateRules alerts on every rule whose condition held long enough on a label, from the counters
+// of a settled epoch
+func (w *ValidatorWatcher) evaluateRules(epoch models.Epoch, metricsByLabel map[string]*metrics.MetricsByLabel) {
+	if w.alertRules == nil || w.warmup {
+		return
+	}
+
+	for _, firing := range w.alertRules.Evaluate(epoch, metricsByLabel, time.Now()) {
+		rule := firing.Rule
+		value := strconv.FormatFloat(firing.Value, 'f', -1, 64)
+		threshold := strconv.FormatFloat(rule.Threshold, 'f', -1, 64)
+
+		go w.sendAlert(alert.Alert{
+			Severity: rule.Severity,
+			Title:    fmt.Sprintf("Rule %s firing on %s", rule.Name, firing.Label),
+			Text:     fmt.Sprintf("%s is %s (%s %s) for %d epochs", rule.Metric, value, rule.Comparison, threshold, firing.Epochs),
+			Fields: map[string]string{
+				"network":   w.config.Network,
+				"rule":      rule.Name,
+				"label":     firing.Label,
+				"metric":    rule.Metric,
+				"value":     value,
+				"threshold": rule.Comparison + " " + threshold,
+				"epoch":     fmt.Sprintf("%d", epoch),
+			},
+			Key:      fmt.Sprintf("rule:%s:%s", rule.Name, firing.Label),
+			Channels: rule.Channels,
+		})
+	}
+}
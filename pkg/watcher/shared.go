@@ -0,0 +1,148 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/metrics"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/validator"
+)
+
+// sharedNetwork is the network-wide aggregate another replica computed from its full validator set load
+type sharedNetwork struct {
+	epoch   models.Epoch
+	metrics *metrics.MetricsByLabel
+}
+
+// refreshAllValidators reloads the full validator set for an epoch
+// With a shared cache, one replica per epoch loads it and shares the network-wide aggregate;
+// the others keep their previous set (for lookups) until it is older than max_full_set_age_epochs
+func (w *ValidatorWatcher) refreshAllValidators(ctx context.Context, epoch models.Epoch, stateID string) {
+	if w.shared != nil && !w.claimFullSetLoad(ctx, epoch) {
+		w.awaitSharedNetworkMetrics(ctx, epoch)
+		return
+	}
+
+	network, err := w.loadFullSet(ctx, stateID)
+	if err != nil {
+		w.logger.WithError(err).Warn("Failed to load all validators (background)")
+		return
+	}
+	w.fullSetEpoch.Store(uint64(epoch))
+	w.logger.WithField("count", w.allValidators.Count()).Debug("✅ Updated all validators cache (background)")
+
+	if w.shared != nil {
+		ttl := 2 * w.epochDuration()
+		if err := w.shared.Set(ctx, networkMetricsKey(epoch), network, ttl); err != nil {
+			w.logger.WithError(err).Debug("Failed to share network metrics")
+		}
+	}
+}
+
+// loadFullSet streams the full validator set into a new registry and its network-wide aggregate
+// The previous set is served until the new one is complete; a failed load keeps it
+func (w *ValidatorWatcher) loadFullSet(ctx context.Context, stateID string) (*metrics.MetricsByLabel, error) {
+	builder := validator.NewAllValidatorsBuilder(w.allValidators.Count())
+	network := metrics.NewNetworkMetrics()
+	if _, err := w.beaconClient.StreamAllValidators(ctx, stateID, func(v models.Validator) {
+		builder.Add(v)
+		network.AddNetworkValidator(&v, w.stakeUnit)
+	}); err != nil {
+		return nil, err
+	}
+
+	w.allValidators.Replace(builder)
+	w.fullSetNetwork.Store(network)
+	return network, nil
+}
+
+// claimFullSetLoad reports whether this replica loads the full validator set this epoch
+// A replica whose own set is too old always loads it, and a failing cache never blocks a load
+func (w *ValidatorWatcher) claimFullSetLoad(ctx context.Context, epoch models.Epoch) bool {
+	if uint64(epoch) >= w.fullSetEpoch.Load()+uint64(w.config.SharedCache.MaxFullSetAgeEpochs) {
+		return true
+	}
+
+	claimed, err := w.shared.Acquire(ctx, fmt.Sprintf("all_validators:%d", epoch), w.epochDuration())
+	if err != nil {
+		w.logger.WithError(err).Debug("Failed to claim the full validator set load - loading it locally")
+		return true
+	}
+	return claimed
+}
+
+// awaitSharedNetworkMetrics polls once per slot, for up to an epoch, for the aggregate the
+// claiming replica shares
+func (w *ValidatorWatcher) awaitSharedNetworkMetrics(ctx context.Context, epoch models.Epoch) {
+	slot := time.Duration(w.clock.SecondsPerSlot()) * time.Second
+	ticker := time.NewTicker(slot)
+	defer ticker.Stop()
+
+	for i := uint64(0); i < w.clock.SlotsPerEpoch(); i++ {
+		var network metrics.MetricsByLabel
+		ok, err := w.shared.Get(ctx, networkMetricsKey(epoch), &network)
+		if err != nil {
+			w.logger.WithError(err).Debug("Failed to read shared network metrics")
+		}
+		if ok {
+			w.sharedNetwork.Store(&sharedNetwork{epoch: epoch, metrics: &network})
+			w.logger.WithField("epoch", epoch).Debug("✅ Using network metrics shared by another replica")
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+	w.logger.WithField("epoch", epoch).Debug("No replica shared network metrics this epoch")
+}
+
+// networkMetrics returns the network-wide aggregate of the freshest full validator set,
+// whether this replica loaded it or another one shared it
+func (w *ValidatorWatcher) networkMetrics() *metrics.MetricsByLabel {
+	if shared := w.sharedNetwork.Load(); shared != nil && uint64(shared.epoch) > w.fullSetEpoch.Load() {
+		return shared.metrics
+	}
+	if network := w.fullSetNetwork.Load(); network != nil {
+		return network
+	}
+	return metrics.NewNetworkMetrics()
+}
+
+// sharedPrice returns the ETH price another replica fetched within the price refresh interval
+func (w *ValidatorWatcher) sharedPrice(ctx context.Context) (float64, bool) {
+	if w.shared == nil {
+		return 0, false
+	}
+
+	var ethPrice float64
+	ok, err := w.shared.Get(ctx, "price", &ethPrice)
+	if err != nil {
+		w.logger.WithError(err).Debug("Failed to read the shared ETH price")
+	}
+	return ethPrice, ok
+}
+
+// sharePrice makes a fetched ETH price available to the other replicas
+func (w *ValidatorWatcher) sharePrice(ctx context.Context, ethPrice float64) {
+	if w.shared == nil {
+		return
+	}
+	if err := w.shared.Set(ctx, "price", ethPrice, w.config.PriceRefresh.ToDuration()); err != nil {
+		w.logger.WithError(err).Debug("Failed to share the ETH price")
+	}
+}
+
+// epochDuration returns the wall-clock length of an epoch
+func (w *ValidatorWatcher) epochDuration() time.Duration {
+	return time.Duration(w.clock.SlotsPerEpoch()*w.clock.SecondsPerSlot()) * time.Second
+}
+
+// networkMetricsKey is the shared cache key of an epoch's network-wide aggregate
+func networkMetricsKey(epoch models.Epoch) string {
+	return fmt.Sprintf("network_metrics:%d", epoch)
+}
@@ -0,0 +1,37 @@
+package watcher
+
+import (
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+)
+
+// recordAttestationHistory records the source/target votes of watched validators' included attestations
+// for the EIP-3076 export. attested comes from all attestations; it's only recomputed per vote when the
+// attestations of the slot don't all carry the same source and target
+func (w *ValidatorWatcher) recordAttestationHistory(attestations []models.Attestation, committees []models.Committee, attested map[models.ValidatorIndex]bool) {
+	groups := make(map[[2]models.Epoch][]models.Attestation)
+	for _, att := range attestations {
+		vote := [2]models.Epoch{att.Data.Source.Epoch, att.Data.Target.Epoch}
+		groups[vote] = append(groups[vote], att)
+	}
+
+	for vote, group := range groups {
+		voters := attested
+		if len(groups) > 1 {
+			var err error
+			voters, err = w.committeeResolver.ProcessAttestations(group, committees)
+			if err != nil {
+				w.logger.WithError(err).Debug("Failed to resolve attesters for signing history")
+				continue
+			}
+		}
+
+		for index, included := range voters {
+			if !included {
+				continue
+			}
+			if v, ok := w.watchedValidators.Get(index); ok {
+				w.signingHistory.RecordAttestation(v.Data.Pubkey, vote[0], vote[1])
+			}
+		}
+	}
+}
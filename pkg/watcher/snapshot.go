@@ -0,0 +1,22 @@
+package watcher
+
+import (
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/snapshot"
+)
+
+// exportSnapshot writes what the API and /metrics serve to snapshot_file, for read-only replicas
+func (w *ValidatorWatcher) exportSnapshot(slot models.Slot) error {
+	var epoch models.Epoch
+	if w.clock != nil {
+		epoch = w.clock.SlotToEpoch(slot)
+	}
+
+	s, err := snapshot.New(w.config.Network, slot, epoch, w.apiServer.State(), w.registry, time.Now())
+	if err != nil {
+		return err
+	}
+	return snapshot.Write(w.config.SnapshotFile, s)
+}
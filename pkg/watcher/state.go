@@ -0,0 +1,67 @@
+package watcher
+
+import (
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/store"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/validator"
+	"github.com/sirupsen/logrus"
+)
+
+// restoreState reloads the state saved by a previous run
+// Block proposal counters always continue from their saved totals; per-validator counters
+// are restored when they were saved in the period they cover under the reset policy:
+// the same epoch or UTC day, or always when they don't reset on their own
+func (w *ValidatorWatcher) restoreState() error {
+	state, ok, err := w.store.Load()
+	if err != nil || !ok {
+		return err
+	}
+
+	w.prometheusMetrics.RestoreBlockCounters(w.config.Network, state.BlockCounters)
+	w.lastProcessedEpoch = state.LastProcessedEpoch
+	if w.depositScanner != nil && state.DepositScan != nil {
+		w.depositScanner.Restore(*state.DepositScan)
+	}
+
+	restored := 0
+	if w.clock != nil && w.counterPeriod(state.Epoch) == w.countersPeriod {
+		for pubkey, counters := range state.Validators {
+			v, ok := w.watchedValidators.GetByPubkey(pubkey)
+			if !ok {
+				continue
+			}
+			w.watchedValidators.UpdateMetrics(v.Index, func(wv *validator.WatchedValidator) {
+				counters.Apply(wv)
+			})
+			restored++
+		}
+	}
+
+	w.logger.WithFields(logrus.Fields{
+		"saved_epoch":          state.Epoch,
+		"last_processed_epoch": state.LastProcessedEpoch,
+		"block_counter_scopes": len(state.BlockCounters),
+		"validators_restored":  restored,
+	}).Info("Restored state from previous run")
+	return nil
+}
+
+// saveState persists the counters so a restart doesn't reset them
+func (w *ValidatorWatcher) saveState() error {
+	state := &store.State{
+		LastProcessedEpoch: w.lastProcessedEpoch,
+		Validators:         make(map[string]store.ValidatorCounters),
+		BlockCounters:      w.prometheusMetrics.BlockCounterState(w.config.Network),
+	}
+	if w.clock != nil {
+		state.Epoch = w.countersEpoch
+	}
+	if w.depositScanner != nil {
+		scan := w.depositScanner.Progress()
+		state.DepositScan = &scan
+	}
+	for _, v := range w.watchedValidators.GetAll() {
+		state.Validators[v.Data.Pubkey] = store.CountersOf(v)
+	}
+
+	return w.store.Save(state)
+}
@@ -0,0 +1,81 @@
+package watcher
+
+import (
+	"fmt"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/alert"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/events"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/validator"
+	"github.com/sirupsen/logrus"
+)
+
+// observeStatuses reports the beacon status changes of watched validators since the previous epoch
+// Every change is counted and emitted as an event; an exit initiation also alerts, since an
+// unplanned one means someone holds the validator's key or withdrawal address
+func (w *ValidatorWatcher) observeStatuses(epoch models.Epoch) {
+	for _, transition := range w.statuses.Observe(w.watchedValidators.GetAll()) {
+		v, ok := w.watchedValidators.Get(transition.ValidatorIndex)
+		if !ok {
+			continue
+		}
+		label := primaryLabel(v.Labels)
+		w.prometheusMetrics.RecordStatusTransition(w.config.Network, w.aggregatedScopes(v.Labels), transition.From, transition.To)
+
+		w.events.Emit(events.Event{
+			Type:           events.TypeStatusChanged,
+			Epoch:          epoch,
+			ValidatorIndex: v.Index,
+			Pubkey:         v.Data.Pubkey,
+			Label:          label,
+			Data: map[string]interface{}{
+				"from":               string(transition.From),
+				"to":                 string(transition.To),
+				"exit_epoch":         v.Data.ExitEpoch,
+				"withdrawable_epoch": v.Data.WithdrawableEpoch,
+			},
+		})
+
+		logger := w.logger.WithFields(logrus.Fields{
+			"validator_index": w.anonymizer.Index(v.Index),
+			"pubkey":          w.logPubkey(v.Data.Pubkey),
+			"label":           label,
+			"epoch":           epoch,
+			"from":            transition.From,
+			"to":              transition.To,
+		})
+		if !transition.ExitInitiated() {
+			logger.Info("Validator status changed")
+			continue
+		}
+		logger.WithField("exit_epoch", v.Data.ExitEpoch).Warn("🚪 VALIDATOR EXIT INITIATED")
+		if !v.Cohort {
+			w.alertExit(v, transition, epoch)
+		}
+	}
+}
+
+// alertExit alerts on a watched validator initiating an exit, critical with critical_alerts.voluntary_exits
+func (w *ValidatorWatcher) alertExit(v *validator.WatchedValidator, transition validator.StatusTransition, epoch models.Epoch) {
+	severity := alert.SeverityWarning
+	if w.config.CriticalAlerts.VoluntaryExits {
+		severity = alert.SeverityCritical
+	}
+
+	label := primaryLabel(v.Labels)
+	go w.sendAlert(alert.Alert{
+		Severity: severity,
+		Title:    fmt.Sprintf("Watched validator %d initiated an exit", w.anonymizer.Index(v.Index)),
+		Text: fmt.Sprintf("Validator %d (%s) went from %s to %s in epoch %d; it exits at epoch %d and becomes withdrawable at epoch %d",
+			w.anonymizer.Index(v.Index), label, transition.From, transition.To, epoch, v.Data.ExitEpoch, v.Data.WithdrawableEpoch),
+		Fields: map[string]string{
+			"network":    w.config.Network,
+			"validator":  fmt.Sprintf("%d", w.anonymizer.Index(v.Index)),
+			"pubkey":     w.logPubkey(v.Data.Pubkey),
+			"label":      label,
+			"status":     string(transition.To),
+			"exit_epoch": fmt.Sprintf("%d", v.Data.ExitEpoch),
+		},
+		Key: fmt.Sprintf("exit:%d", w.anonymizer.Index(v.Index)),
+	})
+}
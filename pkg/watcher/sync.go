@@ -0,0 +1,108 @@
+package watcher
+
+import (
+	"context"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/metrics"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/scheduler"
+	"github.com/sirupsen/logrus"
+)
+
+// syncStatusTimeout bounds a sync status request, within a slot's budget and probe timeouts
+const syncStatusTimeout = 2 * time.Second
+
+// syncPausedSources are the data sources marked stale while the beacon node syncs
+var syncPausedSources = []metrics.DataSource{metrics.SourceAttestations, metrics.SourceLiveness, metrics.SourceRewards}
+
+// syncPausedTasks are the slot tasks skipped while the beacon node syncs: they read duties, blocks and
+// rewards from a head that is behind, and would record every duty since it as missed
+var syncPausedTasks = map[string]bool{
+	"epoch":              true,
+	"reorg":              true,
+	"slot":               true,
+	"proposal_lookahead": true,
+	"liveness":           true,
+	"rewards":            true,
+	"network_baseline":   true,
+	"finality":           true,
+}
+
+// checkNodeSync refreshes the beacon node's sync status at the start of a slot and reports whether
+// duty and reward processing is paused, because the node is syncing or its head is optimistic
+// A failed request keeps the previous state. Processing resumes with a warmup, so the epochs the
+// pause cut short don't record misses
+func (w *ValidatorWatcher) checkNodeSync(ctx context.Context, slot models.Slot) bool {
+	if w.clock.IsReplayMode() {
+		return false
+	}
+
+	status, err := w.fetchNodeSyncStatus(ctx)
+	if err != nil {
+		w.logger.WithError(err).Debug("Failed to get beacon node sync status")
+		return w.syncPaused.Load()
+	}
+
+	paused := status.IsSyncing || status.IsOptimistic
+	if !w.syncPaused.CompareAndSwap(!paused, paused) {
+		return paused
+	}
+	w.prometheusMetrics.SetBeaconNodeSyncing(w.config.Network, paused)
+	w.prometheusMetrics.SetPaused(syncPausedSources, paused)
+
+	if paused {
+		w.logger.WithFields(logrus.Fields{
+			"slot":          slot,
+			"head_slot":     status.HeadSlot,
+			"sync_distance": status.SyncDistance,
+			"is_optimistic": status.IsOptimistic,
+		}).Warn("⏸️  Beacon node is syncing - pausing duty and reward processing")
+	} else {
+		w.logger.WithField("slot", slot).Info("▶️  Beacon node is synced - resuming duty and reward processing")
+		w.startWarmup(slot)
+	}
+	return paused
+}
+
+// withoutSyncPausedTasks drops the tasks that wait for the beacon node to sync
+func withoutSyncPausedTasks(tasks []scheduler.Task) []scheduler.Task {
+	kept := tasks[:0]
+	for _, task := range tasks {
+		if !syncPausedTasks[task.Name] {
+			kept = append(kept, task)
+		}
+	}
+	return kept
+}
+
+// nodeSyncStatus returns the beacon node's sync status, fetched at most once per slot so probes
+// don't add load to the node
+func (w *ValidatorWatcher) nodeSyncStatus() (*models.SyncStatus, error) {
+	maxAge := 12 * time.Second
+	if w.clock != nil {
+		maxAge = time.Duration(w.clock.SecondsPerSlot()) * time.Second
+	}
+
+	w.syncStatusMu.Lock()
+	if !w.syncStatusAt.IsZero() && time.Since(w.syncStatusAt) < maxAge {
+		defer w.syncStatusMu.Unlock()
+		return w.syncStatus, w.syncStatusErr
+	}
+	w.syncStatusMu.Unlock()
+
+	return w.fetchNodeSyncStatus(context.Background())
+}
+
+// fetchNodeSyncStatus requests the beacon node's sync status and caches it for nodeSyncStatus
+func (w *ValidatorWatcher) fetchNodeSyncStatus(ctx context.Context) (*models.SyncStatus, error) {
+	ctx, cancel := context.WithTimeout(ctx, syncStatusTimeout)
+	defer cancel()
+	status, err := w.beaconClient.GetNodeSyncing(ctx)
+
+	w.syncStatusMu.Lock()
+	defer w.syncStatusMu.Unlock()
+
+	w.syncStatus, w.syncStatusErr, w.syncStatusAt = status, err, time.Now()
+	return status, err
+}
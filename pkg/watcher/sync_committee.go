@@ -0,0 +1,142 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/api"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/duties"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/metrics"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/validator"
+	"github.com/sirupsen/logrus"
+)
+
+// updateSyncCommittee finds the watched validators in the sync committee of an epoch's period
+// and exports them with the period boundaries, and counts the watched members of the current and
+// next committees per label
+func (w *ValidatorWatcher) updateSyncCommittee(ctx context.Context, epoch models.Epoch, stateID string) {
+	if w.epochsPerSyncPeriod == 0 {
+		return
+	}
+
+	membership, err := w.syncCommitteeMembership(ctx, stateID, epoch)
+	if err != nil {
+		w.logger.WithError(err).Warn("Failed to get sync committee")
+		return
+	}
+
+	previous := w.syncCommittee
+	w.syncCommittee = membership
+
+	metricMembers := make([]metrics.SyncCommitteeMember, 0, len(membership.Positions))
+	apiMembers := make([]api.SyncCommitteeMember, 0, len(membership.Positions))
+	indices := make([]models.ValidatorIndex, 0, len(membership.Positions))
+	for index, positions := range membership.Positions {
+		v, ok := w.watchedValidators.Get(index)
+		if !ok {
+			continue
+		}
+		metricMembers = append(metricMembers, metrics.SyncCommitteeMember{Index: w.anonymizer.Index(index), Label: primaryLabel(v.Labels), Positions: len(positions)})
+		apiMembers = append(apiMembers, api.SyncCommitteeMember{ValidatorIndex: index, Pubkey: v.Data.Pubkey, Labels: v.Labels, Positions: positions})
+		indices = append(indices, w.anonymizer.Index(index))
+	}
+	w.prometheusMetrics.SetSyncCommittee(w.config.Network, membership.Period, membership.StartEpoch, membership.EndEpoch, metricMembers)
+	w.apiServer.UpdateSyncCommittee(api.SyncCommittee{
+		Period:     membership.Period,
+		StartEpoch: membership.StartEpoch,
+		EndEpoch:   membership.EndEpoch,
+		Members:    apiMembers,
+	})
+
+	if len(indices) > 0 && (previous == nil || previous.Period != membership.Period) {
+		w.logger.WithFields(logrus.Fields{
+			"period":      membership.Period,
+			"start_epoch": membership.StartEpoch,
+			"end_epoch":   membership.EndEpoch,
+			"validators":  indices,
+		}).Info("🔄 Watched validators in the current sync committee")
+	}
+
+	w.updateNextSyncCommittee(ctx, membership, stateID)
+}
+
+// updateNextSyncCommittee exports the watched member counts of the current committee and of the
+// next one, which the state knows for the whole current period
+func (w *ValidatorWatcher) updateNextSyncCommittee(ctx context.Context, current *duties.SyncCommitteeMembership, stateID string) {
+	labels := w.aggregatedScopes(w.watchedValidators.GetLabels())
+	labelsOf := func(index models.ValidatorIndex) []string {
+		if v, ok := w.watchedValidators.Get(index); ok {
+			return v.Labels
+		}
+		return nil
+	}
+
+	next, err := w.syncCommitteeMembership(ctx, stateID, current.EndEpoch+1)
+	if err != nil {
+		w.logger.WithError(err).Debug("Failed to get next sync committee")
+		w.prometheusMetrics.SetSyncCommitteeMembers(w.config.Network, current.CountByLabel(labels, labelsOf), nil)
+		return
+	}
+
+	previous := w.nextSyncCommittee
+	w.nextSyncCommittee = next
+	w.prometheusMetrics.SetSyncCommitteeMembers(w.config.Network, current.CountByLabel(labels, labelsOf), next.CountByLabel(labels, labelsOf))
+
+	if len(next.Positions) > 0 && (previous == nil || previous.Period != next.Period) {
+		indices := make([]models.ValidatorIndex, 0, len(next.Positions))
+		for index := range next.Positions {
+			indices = append(indices, w.anonymizer.Index(index))
+		}
+		sort.Slice(indices, func(i, j int) bool { return indices[i] < indices[j] })
+		w.logger.WithFields(logrus.Fields{
+			"period":      next.Period,
+			"start_epoch": next.StartEpoch,
+			"end_epoch":   next.EndEpoch,
+			"validators":  indices,
+		}).Info("⏭️  Watched validators in the next sync committee")
+	}
+}
+
+// syncCommitteeMembership fetches the sync committee of an epoch's period and finds the watched members
+func (w *ValidatorWatcher) syncCommitteeMembership(ctx context.Context, stateID string, epoch models.Epoch) (*duties.SyncCommitteeMembership, error) {
+	committee, err := w.beaconClient.GetSyncCommittee(ctx, stateID, epoch)
+	if err != nil {
+		return nil, err
+	}
+	membership, err := duties.NewSyncCommitteeMembership(epoch, w.epochsPerSyncPeriod, committee, func(index models.ValidatorIndex) bool {
+		_, ok := w.watchedValidators.Get(index)
+		return ok
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve sync committee: %w", err)
+	}
+	return membership, nil
+}
+
+// recordSyncParticipation records whether the watched sync committee members signed in a block's sync aggregate
+func (w *ValidatorWatcher) recordSyncParticipation(block *models.Block, slot models.Slot) {
+	aggregate := block.Message.Body.SyncAggregate
+	membership := w.syncCommittee
+	if aggregate == nil || membership == nil || len(membership.Positions) == 0 || !membership.Covers(w.clock.SlotToEpoch(slot)) {
+		return
+	}
+
+	signed, err := membership.Participation(aggregate.SyncCommitteeBits)
+	if err != nil {
+		w.logger.WithError(err).WithField("slot", slot).Debug("Failed to decode sync aggregate")
+		return
+	}
+	w.apiServer.AddSyncParticipation(slot, signed)
+
+	for index, ok := range signed {
+		w.watchedValidators.UpdateMetrics(index, func(wv *validator.WatchedValidator) {
+			if ok {
+				wv.SyncCommitteeSigned++
+			} else {
+				wv.SyncCommitteeMissed++
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package watcher
+
+import (
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// taskSlots are the positions in the epoch of the once-per-epoch checks: the configured
+// liveness_slot, rewards_slot and config_reload_slot, and the fixed slots of the other tasks
+type taskSlots struct {
+	liveness  uint64
+	rewards   uint64
+	reload    uint64
+	finality  uint64 // Reconciliation of finalized proposals
+	keyLists  uint64 // Refetch of the remote and Web3Signer key lists
+	saveState uint64
+	snapshot  uint64
+}
+
+// rewardsEpoch returns the epoch of the rewards the counters hold when an epoch's attestation duties
+// are handed on, as the next epoch's settle at liveness_slot: rewards of two epochs back are fetched
+// at rewards_slot, so an earlier rewards_slot already fetched the settled epoch's, a later one only
+// the epoch before it
+func (s taskSlots) rewardsEpoch(settled models.Epoch) models.Epoch {
+	if s.rewards < s.liveness || settled == 0 {
+		return settled
+	}
+	return settled - 1
+}
+
+// newTaskSlots reads the configured positions; one past the end of a shorter epoch (e.g. Gnosis
+// Chain's 16 slots) wraps around, so the check still runs every epoch, and so do the fixed ones
+func newTaskSlots(cfg *models.Config, slotsPerEpoch uint64, logger *logrus.Logger) taskSlots {
+	position := func(option string, slot int) uint64 {
+		if uint64(slot) < slotsPerEpoch {
+			return uint64(slot)
+		}
+		wrapped := uint64(slot) % slotsPerEpoch
+		logger.WithFields(logrus.Fields{
+			"option":          option,
+			"slot":            slot,
+			"slots_per_epoch": slotsPerEpoch,
+			"using":           wrapped,
+		}).Warn("Configured slot is past the end of the epoch - wrapping around")
+		return wrapped
+	}
+
+	return taskSlots{
+		liveness:  position("liveness_slot", cfg.LivenessSlot),
+		rewards:   position("rewards_slot", cfg.RewardsSlot),
+		reload:    position("config_reload_slot", cfg.ConfigReloadSlot),
+		finality:  18 % slotsPerEpoch,
+		keyLists:  14 % slotsPerEpoch,
+		saveState: 24 % slotsPerEpoch,
+		snapshot:  26 % slotsPerEpoch,
+	}
+}
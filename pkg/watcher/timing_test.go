@@ -0,0 +1,80 @@
+package watcher
+
+import (
+	"testing"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/clock"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/store"
+	"github.com/sirupsen/logrus"
+)
+
+func TestPerEpochTasksRunInShortEpochs(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	cfg := &models.Config{
+		Network:                  "gnosis",
+		LivenessSlot:             16,
+		RewardsSlot:              17,
+		ConfigReloadSlot:         15,
+		WatchedKeysURL:           "http://localhost/keys",
+		WatchedKeysRefreshEpochs: 1,
+		SnapshotFile:             "snapshot.json",
+	}
+	cfg.Web3Signer.URL = "http://localhost:9000"
+	cfg.Web3Signer.RefreshEpochs = 1
+
+	for _, slotsPerEpoch := range []uint64{16, 32} {
+		spec := &models.Spec{SecondsPerSlot: 5, SlotsPerEpoch: slotsPerEpoch}
+		w := &ValidatorWatcher{
+			config:         cfg,
+			clock:          clock.NewBeaconClock(&models.Genesis{}, spec, logger),
+			taskSlots:      newTaskSlots(cfg, slotsPerEpoch, logger),
+			store:          &store.Store{},
+			reloadRequests: make(chan struct{}, 1),
+			logger:         logger,
+		}
+
+		// Every per-epoch task is scheduled exactly once over an epoch
+		counts := make(map[string]int)
+		epoch := models.Epoch(4)
+		first := models.Slot(uint64(epoch) * slotsPerEpoch)
+		for slot := first; slot < first+models.Slot(slotsPerEpoch); slot++ {
+			for _, task := range w.slotTasks(slot, epoch) {
+				counts[task.Name]++
+			}
+		}
+		for _, name := range []string{"epoch", "liveness", "rewards", "finality", "watched_keys_url", "web3signer", "reload_config", "save_state", "export_snapshot"} {
+			if counts[name] != 1 {
+				t.Errorf("%d slots per epoch: task %s scheduled %d times, want once", slotsPerEpoch, name, counts[name])
+			}
+		}
+	}
+}
+
+func TestRewardsEpoch(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	tests := []struct {
+		name      string
+		liveness  int
+		rewards   int
+		settled   models.Epoch
+		rewardsOf models.Epoch
+	}{
+		{"rewards after liveness", 16, 17, 100, 99},
+		{"rewards before liveness", 20, 8, 100, 100},
+		{"same slot", 16, 16, 100, 99},
+		{"genesis", 16, 17, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slots := newTaskSlots(&models.Config{LivenessSlot: tt.liveness, RewardsSlot: tt.rewards}, 32, logger)
+			if got := slots.rewardsEpoch(tt.settled); got != tt.rewardsOf {
+				t.Errorf("rewardsEpoch(%d) = %d, want %d", tt.settled, got, tt.rewardsOf)
+			}
+		})
+	}
+}
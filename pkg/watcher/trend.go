@@ -0,0 +1,57 @@
+package watcher
+
+import (
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/metrics"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/store"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/validator"
+	"github.com/sirupsen/logrus"
+)
+
+// trendCompactInterval is how often per-epoch trend points are checked for compaction
+const trendCompactInterval = time.Hour
+
+// recordTrend appends every label's counters of a settled epoch to the state file's trend and compacts
+// old points into daily ones
+// Points are only per-epoch values under the epoch reset policy, so other policies keep no trend
+func (w *ValidatorWatcher) recordTrend(epoch models.Epoch, metricsByLabel map[string]*metrics.MetricsByLabel) {
+	if w.store == nil || w.clock == nil || w.resetPolicy != validator.ResetEpoch {
+		return
+	}
+
+	points := make(map[string]store.TrendPoint, len(metricsByLabel))
+	for label, m := range metricsByLabel {
+		if label != "scope:all-network" {
+			points[label] = store.EpochPoint(m)
+		}
+	}
+
+	start := w.clock.SlotStartTime(w.clock.EpochToSlot(epoch))
+	if err := w.store.AppendTrend(epoch, start, points); err != nil {
+		w.logger.WithError(err).Warn("Failed to record trend")
+		return
+	}
+
+	if time.Since(w.trendCompacted) < trendCompactInterval {
+		return
+	}
+	w.trendCompacted = time.Now()
+
+	// Ages are measured in chain time, so replays compact the same way
+	day := 24 * time.Hour
+	compacted, dropped, err := w.store.CompactTrend(w.clock.SlotStartTime(w.clock.EpochToSlot(epoch)),
+		time.Duration(w.config.StateTrends.CompactAfterDays)*day,
+		time.Duration(w.config.StateTrends.RetentionDays)*day)
+	if err != nil {
+		w.logger.WithError(err).Warn("Failed to compact trend")
+		return
+	}
+	if compacted > 0 || dropped > 0 {
+		w.logger.WithFields(logrus.Fields{
+			"epoch_points_compacted": compacted,
+			"day_points_dropped":     dropped,
+		}).Info("Compacted trend in state file")
+	}
+}
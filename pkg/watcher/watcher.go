@@ -5,14 +5,48 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
-
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/alert"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/anonymize"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/api"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/batch"
 	"github.com/enriquemanuel/eth-validator-watcher/pkg/beacon"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/cache"
 	"github.com/enriquemanuel/eth-validator-watcher/pkg/clock"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/config"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/cron"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/degrade"
 	"github.com/enriquemanuel/eth-validator-watcher/pkg/duties"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/dvt"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/events"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/explorer"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/export"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/federation"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/grafana"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/health"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/heatmap"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/httpserver"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/influx"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/interchange"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/membership"
 	"github.com/enriquemanuel/eth-validator-watcher/pkg/metrics"
 	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/onchain"
 	"github.com/enriquemanuel/eth-validator-watcher/pkg/price"
 	"github.com/enriquemanuel/eth-validator-watcher/pkg/proposer"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/queues"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/ratings"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/refresh"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/relay"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/reorg"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/rules"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/scheduler"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/sharedcache"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/store"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/tracing"
 	"github.com/enriquemanuel/eth-validator-watcher/pkg/validator"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -21,60 +55,428 @@ import (
 
 // ValidatorWatcher is the main orchestrator for validator monitoring
 type ValidatorWatcher struct {
-	config             *models.Config
-	beaconClient       *beacon.Client
-	clock              *clock.BeaconClock
-	proposerSchedule   *proposer.Schedule
-	allValidators      *validator.AllValidators
-	watchedValidators  *validator.WatchedValidators
-	prometheusMetrics  *metrics.PrometheusMetrics
-	priceFetcher       *price.Fetcher
-	registry           *prometheus.Registry
-	logger             *logrus.Logger
-	lastProcessedEpoch models.Epoch
-	ready              bool // Tracks if watcher has successfully initialized
+	config              *models.Config
+	keysMu              sync.RWMutex // Guards config.WatchedKeys, read by the registry label fetch outside the slot loop
+	beaconClient        *beacon.Client
+	clock               *clock.BeaconClock
+	proposerSchedule    *proposer.Schedule
+	finality            *proposer.FinalityTracker
+	reorgs              reorgWindow    // Slots changed by reorgs since the last slot
+	blockRoots          *reorg.Tracker // Block roots of processed slots, for reorg detection
+	feeRecipients       *proposer.FeeRecipientPolicy
+	graffiti            *proposer.GraffitiClassifier
+	allValidators       *validator.AllValidators
+	watchedValidators   *validator.WatchedValidators
+	indexCache          *validator.IndexCache
+	store               *store.Store                           // Persisted state for restart continuity, nil if not configured
+	trendCompacted      time.Time                              // Last trend compaction
+	shared              *sharedcache.Cache                     // Cache shared with other replicas, nil if not configured
+	sharedNetwork       atomic.Pointer[sharedNetwork]          // Network-wide aggregate shared by another replica
+	fullSetEpoch        atomic.Uint64                          // Epoch this replica last loaded the full validator set
+	fullSetNetwork      atomic.Pointer[metrics.MetricsByLabel] // Network-wide aggregate of that load
+	aggregation         *duties.AggregationTracker
+	inclusions          *duties.InclusionTracker
+	coverage            *duties.CoverageTracker       // Attestation duties evaluated per epoch, against those expected
+	liability           *duties.LiabilityTracker      // Duty liability of each watched validator
+	attestationDuties   *duties.DutyTracker           // Attestation duties seen in blocks, settled with liveness
+	statuses            *validator.StatusTracker      // Beacon status of each watched validator at the last load
+	credentials         *validator.CredentialsTracker // Withdrawal credentials type of each watched validator at the last load
+	heatmap             *heatmap.Tracker
+	scheduler           *scheduler.Scheduler
+	committeeResolver   *duties.CommitteeResolver
+	prometheusMetrics   *metrics.PrometheusMetrics
+	labelClasses        metrics.LabelClasses // Label classes aggregated into metrics, nil for all
+	aggregator          *metrics.Aggregator  // Per-label metrics of the watched validators, reused across slots
+	priceFetcher        *price.Fetcher
+	priceRefresher      *refresh.Refresher[float64]
+	onchainRegistry     *onchain.Registry
+	registryLabels      *refresh.Refresher[map[string][]string] // Pubkey -> labels from registry contracts, nil if not configured
+	degradation         *degrade.Controller                     // Sheds optional work while the beacon node is overloaded, nil if disabled
+	alertRules          *rules.Engine                           // Configured alert rules, nil if there are none
+	relayClient         *relay.Client                           // MEV-Boost relay data API, nil if no relays are configured
+	relayRegistrations  *refresh.Refresher[relay.Registrations] // MEV-Boost relay lookups, nil if no relays are configured
+	clockDrift          *refresh.Refresher[int64]               // Local slot minus the head header's slot, nil without a live clock
+	clockAhead          atomic.Int64                            // Consecutive drift readings with the local clock over maxClockDriftSlots ahead
+	relayMissing        map[string]bool                         // Pubkeys already alerted as missing from every relay
+	labelsOffline       map[string]bool                         // Labels already alerted as offline above critical_alerts.label_offline_percent
+	proposalsWarned     map[models.Slot]bool                    // Upcoming proposals already warned about by proposal_lookahead
+	federation          *federation.Client                      // Peer watchers' label summaries, nil if no peers are configured
+	configuredKeys      []models.WatchedKey                     // watched_keys from the config file
+	remoteKeys          []models.WatchedKey                     // Keys from watched_keys_url
+	keysClient          *http.Client                            // Fetches watched_keys_url
+	dvtKeys             []models.WatchedKey                     // Keys resolved from DVT clusters, merged again on reload
+	signerKeys          []models.WatchedKey                     // Keys loaded in the Web3Signer instance, merged again on reload
+	cohortKeys          []models.WatchedKey                     // Comparison cohort members, labelled cohort:<name>
+	resetPolicy         validator.ResetPolicy                   // When the per-validator counters reset
+	countersPeriod      string                                  // Period the per-validator counters cover (see counterPeriod)
+	countersEpoch       models.Epoch                            // Epoch whose attestation duties the counters last settled
+	countersSettled     bool                                    // Whether countersEpoch was settled since the start
+	countersSince       models.Slot                             // Slot countersEpoch settled in
+	reloadRequests      chan struct{}                           // Reloads requested outside the config_reload_slot schedule (SIGHUP)
+	queuesMu            sync.Mutex
+	queueSnapshot       *queues.Snapshot
+	queueFlows          []queues.Flow
+	registry            *prometheus.Registry
+	apiServer           *api.Server
+	membership          *membership.Feed // Label membership changes, also persisted to membership_file if set
+	events              *events.Stream
+	annotations         *grafana.Publisher // Grafana annotations, also an event stream sink; nil if disabled
+	influx              *influx.Writer     // Epoch-level line protocol measurements, nil if disabled
+	exporter            *export.Exporter   // Per-validator epoch rows in daily CSV files, nil if disabled
+	notifier            alert.Notifier
+	alertChannels       []*alert.Tracked      // Chat and paging channels, for the health report
+	reportSchedule      *cron.Schedule        // When summary reports are sent, nil if disabled
+	nextReport          time.Time             // Next report due time, zero until the first slot
+	reportBaseline      metrics.BlockCounters // Watched block counters at the previous report
+	anonymizer          *anonymize.Anonymizer // nil unless pubkeys are anonymized
+	logger              *logrus.Logger
+	lastProcessedEpoch  models.Epoch
+	head                *headTracker     // Chain head from beacon events, nil when slots follow the local clock
+	warmup              bool             // Observing only until a full epoch of context is available
+	warmupEndEpoch      models.Epoch     // First epoch processed with full context
+	ready               bool             // Tracks if watcher has successfully initialized
+	lastSlotAt          atomic.Int64     // Unix nanoseconds when the slot loop last finished a slot
+	health              *health.Registry // Subsystem checks behind the health endpoints
+	liveCriteria        health.Criteria  // Components /health fails on
+	readyCriteria       health.Criteria  // Components /ready fails on
+	signingHistory      *interchange.History
+	epochsPerSyncPeriod uint64                          // Sync committee period length from the spec, 0 if unknown
+	stakeUnit           models.Gwei                     // Effective balance of a full validator, from the spec
+	churn               queues.ChurnSpec                // Activation and exit churn constants, from the spec
+	taskSlots           taskSlots                       // Slots of the epoch the per-epoch checks run at
+	syncCommittee       *duties.SyncCommitteeMembership // Watched members of the current sync committee, nil until known
+	nextSyncCommittee   *duties.SyncCommitteeMembership // Watched members of the next sync committee, nil until known
+	depositsMu          sync.Mutex
+	depositScanner      *onchain.DepositScanner // Deposit contract logs of the watched keys, nil unless deposit_tracking is set
+	depositStages       map[string]string       // Pre-activation stage of each watched key not active yet, by pubkey
+	syncPaused          atomic.Bool             // Duty and reward processing paused while the beacon node syncs
+	syncStatusMu        sync.Mutex
+	syncStatus          *models.SyncStatus // Beacon node sync status, cached for a slot
+	syncStatusErr       error
+	syncStatusAt        time.Time
+	networkBaseline     atomic.Pointer[metrics.NetworkBaseline] // Sampled network attestation performance, nil until network_sample runs
+	ratingsClient       *ratings.Client                         // Third-party effectiveness ratings, nil if no provider is configured
+	externalRatings     *refresh.Refresher[ratings.Effectiveness]
 }
 
 // NewValidatorWatcher creates a new validator watcher
 func NewValidatorWatcher(cfg *models.Config, logger *logrus.Logger) (*ValidatorWatcher, error) {
 	// Create beacon client
-	beaconClient := beacon.NewClient(cfg.BeaconURL, cfg.BeaconTimeout.ToDuration(), logger)
+	beaconClient := beacon.NewFailoverClient(cfg.BeaconEndpoints(), cfg.BeaconTimeout.ToDuration(), logger)
+	beaconClient.SetRateLimit(cfg.BeaconRateLimit)
+	beaconClient.SetRetryPolicies(cfg.BeaconRetry)
+	for i, node := range cfg.BeaconNodeList() {
+		if err := beaconClient.SetEndpointAuth(i, node.Auth); err != nil {
+			if len(cfg.BeaconNodes) > 0 {
+				return nil, fmt.Errorf("beacon_nodes[%d].auth: %w", i, err)
+			}
+			return nil, fmt.Errorf("beacon_auth: %w", err)
+		}
+	}
 
 	// Initialize registries
 	allValidators := validator.NewAllValidators()
 	watchedValidators := validator.NewWatchedValidators()
 
+	// Load persisted pubkey -> index resolutions (indices never change)
+	indexCache := validator.NewIndexCache(cfg.IndexCacheFile)
+	if err := indexCache.Load(); err != nil {
+		logger.WithError(err).Warn("Failed to load index cache - pubkeys will be resolved from the beacon node")
+	}
+
 	// Create Prometheus registry and metrics
+	// Replay runs label their series mode="replay" so reprocessed history stays off live dashboards
 	registry := prometheus.NewRegistry()
-	prometheusMetrics := metrics.NewPrometheusMetrics(registry)
+	var registerer prometheus.Registerer = registry
+	if cfg.IsReplay() {
+		registerer = metrics.ReplayRegisterer(registry)
+	}
+	prometheusMetrics := metrics.NewPrometheusMetrics(registerer)
+	prometheusMetrics.SetStaleAfter(cfg.StaleDataAfter.ToDuration())
+	prometheusMetrics.SetCounterWindows(cfg.Counters.WindowEpochs)
+
+	resetPolicy, err := validator.ParseResetPolicy(cfg.Counters.Reset)
+	if err != nil {
+		return nil, fmt.Errorf("invalid counters config: %w", err)
+	}
+
+	// Export hit/miss/eviction counters of the shared caches
+	committeeResolver := duties.NewCommitteeResolver(duties.DefaultCommitteeCacheSize)
+	cacheCollector := cache.NewCollector(append(beaconClient.Caches(), committeeResolver.Cache())...)
+	registerer.MustRegister(cacheCollector)
+	registerer.MustRegister(beaconClient.Collectors()...)
 
 	// Create price fetcher
 	priceFetcher := price.NewFetcher(logger)
 
+	// Create JSON API server (served alongside /metrics)
+	scorecardWeights, err := metrics.ResolveScorecardWeights(cfg.Scorecard.Weights)
+	if err != nil {
+		return nil, fmt.Errorf("invalid scorecard weights: %w", err)
+	}
+	apiServer := api.NewServer(scorecardWeights, logger)
+
+	graffiti, err := proposer.NewGraffitiClassifier(cfg.GraffitiClients)
+	if err != nil {
+		return nil, fmt.Errorf("invalid graffiti_clients: %w", err)
+	}
+	heatmapTracker := heatmap.New(cfg.HeatmapEpochs)
+	apiServer.SetHeatmap(heatmapTracker)
+
+	// Create event stream for full per-validator detail (logs only carry samples)
+	eventStream := events.NewStream(events.DefaultBufferSize, logger)
+	if cfg.EventsFile != "" {
+		encoder, err := events.NewEncoder(cfg.EventsFormat, "/eth-validator-watcher/"+cfg.Network)
+		if err != nil {
+			return nil, err
+		}
+		fileSink, err := events.NewFileSink(cfg.EventsFile, encoder)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create events file sink: %w", err)
+		}
+		eventStream.AddSink(fileSink)
+	}
+
+	// Grafana annotations of proposals, misses and the watcher's own state changes
+	var annotations *grafana.Publisher
+	if g := cfg.GrafanaAnnotations; g.URL != "" && !cfg.IsReplay() {
+		annotations = grafana.NewPublisher(g.URL, g.APIKey, g.DashboardUIDs, append([]string{cfg.Network}, g.Tags...), g.Events, cfg.BeaconTimeout.ToDuration(), logger)
+		eventStream.AddSink(annotations)
+	}
+
+	// Long-term per-validator history, written to InfluxDB rather than as Prometheus series
+	var influxWriter *influx.Writer
+	if i := cfg.Influx; i.URL != "" {
+		influxWriter = influx.NewWriter(i.URL, i.Token, cfg.BeaconTimeout.ToDuration(), logger)
+	}
+
+	// Per-validator epoch rows for offline analysis
+	var exporter *export.Exporter
+	if e := cfg.Export; e.Dir != "" {
+		exporter, err = export.New(e.Dir, cfg.Network, e.RetentionDays)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Membership feed, resumed from its file so restarts only record what changed meanwhile
+	membershipFeed := membership.NewFeed(membership.DefaultRetention)
+	if cfg.MembershipFile != "" {
+		membershipFeed, err = membership.OpenFeed(cfg.MembershipFile, membership.DefaultRetention)
+		if err != nil {
+			return nil, err
+		}
+	}
+	apiServer.SetMembership(membershipFeed)
+
+	// Privacy mode: pubkeys and validator indices leave the watcher only as keyed pseudonyms
+	var anonymizer *anonymize.Anonymizer
+	if cfg.Privacy.AnonymizePubkeys {
+		anonymizer = anonymize.New(cfg.Privacy.Salt)
+		eventStream.SetPubkeyMapper(anonymizer.Pubkey)
+		eventStream.SetIndexMapper(anonymizer.Index)
+		apiServer.SetPubkeyMapper(anonymizer.Pubkey)
+		apiServer.SetIndexMapper(anonymizer.Index)
+	}
+
+	// Explorer pages linked from alerts, warnings, reports and API responses
+	// Privacy mode links none, as every page names the validators it is about
+	var links *explorer.Explorer
+	if !anonymizer.Enabled() {
+		links = newExplorer(cfg)
+	}
+	apiServer.SetExplorer(links)
+	if links != nil {
+		logger.AddHook(explorer.NewLogHook(links))
+	}
+
+	notifier, alertChannels, err := newNotifier(cfg, links, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	// Scheduled summary report, if configured
+	var reportSchedule *cron.Schedule
+	if cfg.Report.Schedule != "" {
+		reportSchedule, err = cron.Parse(cfg.Report.Schedule, cfg.Report.TimeZone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid report schedule: %w", err)
+		}
+	}
+
+	labelClasses := metrics.NewLabelClasses(cfg.AggregateLabelClasses)
+
+	// Optional state file so counters survive restarts
+	var stateStore *store.Store
+	if cfg.StateFile != "" {
+		stateStore, err = store.Open(cfg.StateFile)
+		if err != nil {
+			return nil, err
+		}
+		apiServer.SetTrend(stateStore)
+	}
+
+	// Optional cache shared with other replicas; without it every replica asks the beacon node
+	var sharedCache *sharedcache.Cache
+	if cfg.SharedCache.RedisURL != "" {
+		connectCtx, cancel := context.WithTimeout(context.Background(), cfg.BeaconTimeout.ToDuration())
+		sharedCache, err = sharedcache.New(connectCtx, cfg.SharedCache.RedisURL, cfg.SharedCache.KeyPrefix, cfg.Network)
+		cancel()
+		if err != nil {
+			logger.WithError(err).Warn("Failed to connect to the shared cache - continuing without it")
+			sharedCache = nil
+		} else {
+			beaconClient.SetSharedCache(sharedCache)
+			cacheCollector.Add(sharedCache)
+		}
+	}
+
 	watcher := &ValidatorWatcher{
 		config:            cfg,
 		beaconClient:      beaconClient,
 		allValidators:     allValidators,
 		watchedValidators: watchedValidators,
+		indexCache:        indexCache,
+		store:             stateStore,
+		shared:            sharedCache,
+		reloadRequests:    make(chan struct{}, 1),
+		resetPolicy:       resetPolicy,
+		stakeUnit:         models.DefaultStakeUnit,
+		churn:             queues.ChurnSpecOf(&models.Spec{}),
+		configuredKeys:    cfg.WatchedKeys,
+		keysClient:        &http.Client{Timeout: cfg.BeaconTimeout.ToDuration()},
+		aggregation:       duties.NewAggregationTracker(),
+		inclusions:        duties.NewInclusionTracker(),
+		coverage:          duties.NewCoverageTracker(),
+		liability:         duties.NewLiabilityTracker(),
+		attestationDuties: duties.NewDutyTracker(),
+		statuses:          validator.NewStatusTracker(),
+		credentials:       validator.NewCredentialsTracker(),
+		finality:          proposer.NewFinalityTracker(),
+		blockRoots:        reorg.NewTracker(maxReorgSlots),
+		feeRecipients:     proposer.NewFeeRecipientPolicy(cfg.FeeRecipients),
+		graffiti:          graffiti,
+		heatmap:           heatmapTracker,
+		scheduler:         scheduler.New(scheduler.DefaultIdleReserve, logger),
+		committeeResolver: committeeResolver,
 		prometheusMetrics: prometheusMetrics,
 		priceFetcher:      priceFetcher,
 		registry:          registry,
+		apiServer:         apiServer,
+		membership:        membershipFeed,
+		events:            eventStream,
+		annotations:       annotations,
+		influx:            influxWriter,
+		exporter:          exporter,
+		notifier:          notifier,
+		alertChannels:     alertChannels,
+		reportSchedule:    reportSchedule,
+		health:            health.New(),
+		signingHistory:    interchange.NewHistory(),
+		anonymizer:        anonymizer,
+		labelClasses:      labelClasses,
+		aggregator:        metrics.NewAggregator(labelClasses),
 		logger:            logger,
 	}
+	eventStream.AddInternalSink(&canarySink{watcher: watcher})
+	for _, check := range watcher.healthChecks() {
+		watcher.health.Add(check)
+	}
+	for _, component := range watcher.healthComponents() {
+		watcher.health.AddComponent(component)
+	}
+	watcher.liveCriteria, watcher.readyCriteria, err = watcher.healthCriteria()
+	if err != nil {
+		return nil, fmt.Errorf("health: %w", err)
+	}
+
+	// External data refreshed in the background so slot processing never waits on it
+	watcher.priceRefresher = refresh.New("price", cfg.PriceRefresh.ToDuration(), watcher.fetchPrice, logger)
+	if registryCfg := cfg.OnchainRegistry; registryCfg.ExecutionURL != "" {
+		rpc := onchain.NewRPCClient(registryCfg.ExecutionURL, cfg.BeaconTimeout.ToDuration())
+		watcher.onchainRegistry = onchain.NewRegistry(rpc, registryCfg.Contracts)
+		watcher.registryLabels = refresh.New("onchain_registry", registryCfg.Refresh.ToDuration(), watcher.fetchRegistryLabels, logger)
+	}
+	if len(cfg.MEVRelays) > 0 {
+		watcher.relayClient = relay.NewClient(cfg.MEVRelays, cfg.BeaconTimeout.ToDuration())
+	}
+	if cfg.ExternalRatings.Provider != "" {
+		watcher.ratingsClient = ratings.NewClient(cfg.ExternalRatings, cfg.Network, cfg.BeaconTimeout.ToDuration())
+	}
+	if d := cfg.Degradation; d.IsEnabled() {
+		watcher.degradation = degrade.NewController(degrade.Thresholds{
+			MaxLatency:     time.Duration(d.MaxLatencyMs) * time.Millisecond,
+			MaxErrorRate:   d.MaxErrorRate,
+			RecoverSamples: d.RecoverEpochs,
+			MinRequests:    minDegradationRequests,
+		})
+	}
+	alertRules, err := newRulesEngine(cfg.Rules)
+	if err != nil {
+		return nil, err
+	}
+	watcher.alertRules = alertRules
+	if fed := cfg.Federation; len(fed.Peers) > 0 {
+		watcher.federation = federation.NewClient(fed.Peers, fed.Refresh.ToDuration(), cfg.BeaconTimeout.ToDuration(), logger)
+		name := fed.Name
+		if name == "" {
+			name = federation.DefaultName
+		}
+		apiServer.EnableFederation(name)
+	}
 
 	return watcher, nil
 }
 
 // Run starts the validator watcher main loop
 func (w *ValidatorWatcher) Run(ctx context.Context) error {
+	defer w.events.Close()
+	defer w.membership.Close()
+	if w.influx != nil {
+		defer w.influx.Close()
+	}
+	if w.shared != nil {
+		defer w.shared.Close()
+	}
+	if w.store != nil {
+		defer w.store.Close()
+		defer func() {
+			if err := w.saveState(); err != nil {
+				w.logger.WithError(err).Warn("Failed to save state on shutdown")
+			}
+		}()
+	}
+
 	// Initialize beacon clock
 	if err := w.initialize(ctx); err != nil {
 		return fmt.Errorf("failed to initialize: %w", err)
 	}
 
+	// Start background refresher for external data
+	w.priceRefresher.Start(ctx)
+	if w.registryLabels != nil {
+		w.registryLabels.Start(ctx)
+	}
+	w.startRelayChecks(ctx)
+	w.startRatingChecks(ctx)
+	w.startDepositScans(ctx)
+	w.startClockDriftChecks(ctx)
+	if w.federation != nil {
+		w.federation.Start(ctx)
+	}
+
 	// Start Prometheus HTTP server
 	go w.startMetricsServer()
 
+	// Standard gRPC health checking protocol for gateways and probes
+	if w.config.GRPCHealthPort > 0 {
+		go func() {
+			if err := health.ServeGRPC(ctx, fmt.Sprintf(":%d", w.config.GRPCHealthPort), w.health, w.logger); err != nil {
+				w.logger.WithError(err).Error("gRPC health server failed")
+			}
+		}()
+	}
+
 	// Main monitoring loop
 	return w.mainLoop(ctx)
 }
@@ -83,6 +485,16 @@ func (w *ValidatorWatcher) Run(ctx context.Context) error {
 func (w *ValidatorWatcher) initialize(ctx context.Context) error {
 	w.logger.Info("Initializing validator watcher...")
 
+	// Identify the beacon node so quirk handling and metrics can name the client
+	if node, err := w.beaconClient.DetectNode(ctx); err != nil {
+		w.logger.WithError(err).Warn("Failed to detect beacon node client - continuing without client detection")
+	} else {
+		w.logger.WithFields(logrus.Fields{
+			"client":  node.Client,
+			"version": node.Version,
+		}).Info("Detected beacon node client")
+	}
+
 	// Fetch genesis and spec (optional - some public RPC endpoints may not support these)
 	genesis, err := w.beaconClient.GetGenesis(ctx)
 	if err != nil {
@@ -97,17 +509,39 @@ func (w *ValidatorWatcher) initialize(ctx context.Context) error {
 	var spec *models.Spec
 	if genesis != nil {
 		spec, err = w.beaconClient.GetSpec(ctx)
-		if err != nil {
+		switch {
+		case err != nil && w.config.Spec.Complete():
+			w.logger.WithError(err).Warn("Failed to get spec - using the configured spec overrides")
+			spec = &models.Spec{}
+		case err != nil:
 			w.logger.WithError(err).Warn("Failed to get spec - clock-based monitoring will be disabled")
 			genesis = nil // Also disable clock if we can't get spec
 		}
 	}
+	if spec != nil {
+		w.config.Spec.Apply(spec)
+		w.stakeUnit = spec.StakeUnit()
+		w.churn = queues.ChurnSpecOf(spec)
+		w.watchedValidators.SetStakeUnit(w.stakeUnit)
+		w.prometheusMetrics.SetStakeUnit(w.stakeUnit)
+		w.prometheusMetrics.SetRewardSpec(time.Duration(spec.SecondsPerSlot*spec.SlotsPerEpoch)*time.Second, spec.BaseRewardFactor)
+	}
 
 	// Initialize clock only if we have genesis and spec
 	if genesis != nil && spec != nil {
 		w.clock = clock.NewBeaconClock(genesis, spec, w.logger)
-		if w.config.ReplayStartAtTS != nil {
+		w.clock.SetSlotLag(uint64(w.config.SlotLagSeconds))
+		w.taskSlots = newTaskSlots(w.config, spec.SlotsPerEpoch, w.logger)
+		w.apiServer.SetInterchange(w.signingHistory, genesis.GenesisValidatorsRoot)
+		w.beaconClient.SetSlotsPerEpoch(spec.SlotsPerEpoch)
+		w.beaconClient.SetEpochClock(w.clock.CurrentEpoch)
+		w.epochsPerSyncPeriod = spec.EpochsPerSyncCommitteePeriod
+		w.initDepositTracking(spec)
+		w.countersEpoch = w.lastSettledEpoch(w.clock.CurrentSlot())
+		w.countersPeriod = w.counterPeriod(w.countersEpoch)
+		if w.config.IsReplay() {
 			w.clock.EnableReplayMode(w.config.ReplayStartAtTS, w.config.ReplayEndAtTS)
+			w.logger.WithField("mode", metrics.ModeReplay).Info("Replay mode - alerts are only logged and metrics are labeled mode=replay")
 		}
 
 		// Initialize proposer schedule
@@ -117,6 +551,7 @@ func (w *ValidatorWatcher) initialize(ctx context.Context) error {
 			"genesis_time":     genesis.GenesisTime,
 			"seconds_per_slot": spec.SecondsPerSlot,
 			"slots_per_epoch":  spec.SlotsPerEpoch,
+			"stake_unit_gwei":  w.stakeUnit,
 			"current_slot":     w.clock.CurrentSlot(),
 			"current_epoch":    w.clock.CurrentEpoch(),
 		}).Info("Initialized beacon clock")
@@ -124,11 +559,49 @@ func (w *ValidatorWatcher) initialize(ctx context.Context) error {
 		w.logger.Info("Clock not initialized - running in snapshot mode")
 	}
 
+	// Add keys from the remote key list
+	if err := w.loadRemoteKeys(ctx); err != nil {
+		return fmt.Errorf("failed to load watched keys from URL: %w", err)
+	}
+
+	// Add keys the remote signer holds
+	if err := w.loadSignerKeys(ctx); err != nil {
+		return fmt.Errorf("failed to load watched keys from Web3Signer: %w", err)
+	}
+
+	// Add keys derived from distributed validator clusters
+	if err := w.loadDVTKeys(ctx); err != nil {
+		return fmt.Errorf("failed to load distributed validator keys: %w", err)
+	}
+
+	// Add external validators benchmarked against the watched ones
+	if err := w.loadCohortKeys(ctx); err != nil {
+		return fmt.Errorf("failed to load comparison cohorts: %w", err)
+	}
+
+	// Label keys from on-chain registries before the first validator load
+	if w.registryLabels != nil {
+		w.registryLabels.Refresh(ctx)
+		if err := w.registryLabels.Err(); err != nil {
+			w.logger.WithError(err).Warn("Failed to load on-chain registry labels - retrying in the background")
+		}
+	}
+
 	// Load validators immediately (this works without clock)
 	if err := w.loadAllValidators(ctx); err != nil {
 		return fmt.Errorf("failed to load validators: %w", err)
 	}
 
+	w.prometheusMetrics.MarkUpdated(metrics.SourceValidators, w.config.Network)
+	w.warnLintFindings(ctx)
+
+	// Continue counters from the previous run
+	if w.store != nil {
+		if err := w.restoreState(); err != nil {
+			w.logger.WithError(err).Warn("Failed to restore state - counters start from zero")
+		}
+	}
+
 	// Mark watcher as ready after successful initialization
 	w.ready = true
 	w.logger.Info("✅ Validator watcher ready - health checks will now pass")
@@ -136,6 +609,71 @@ func (w *ValidatorWatcher) initialize(ctx context.Context) error {
 	return nil
 }
 
+// loadDVTKeys merges keys from Obol cluster locks and SSV operators into the watched keys
+func (w *ValidatorWatcher) loadDVTKeys(ctx context.Context) error {
+	dvtCfg := w.config.DVT
+	if len(dvtCfg.ObolLockFiles) == 0 && len(dvtCfg.SSVOperatorIDs) == 0 {
+		return nil
+	}
+
+	keys, err := dvt.Resolve(ctx, dvtCfg, w.config.Network, w.logger)
+	if err != nil {
+		return err
+	}
+
+	w.dvtKeys = keys
+	before := len(w.config.WatchedKeys)
+	w.setWatchedKeys(w.mergedWatchedKeys())
+	w.logger.WithFields(logrus.Fields{
+		"dvt_keys": len(keys),
+		"new_keys": len(w.config.WatchedKeys) - before,
+		"total":    len(w.config.WatchedKeys),
+	}).Info("Added distributed validator keys to watched keys")
+
+	return nil
+}
+
+// setWatchedKeys replaces the watched keys; only the slot loop replaces them, so it reads them unlocked
+func (w *ValidatorWatcher) setWatchedKeys(keys []models.WatchedKey) {
+	w.keysMu.Lock()
+	defer w.keysMu.Unlock()
+
+	w.config.WatchedKeys = keys
+}
+
+// watchedKeys returns the configured watched keys with on-chain registry labels applied
+func (w *ValidatorWatcher) watchedKeys() []models.WatchedKey {
+	if w.registryLabels == nil {
+		return w.config.WatchedKeys
+	}
+	labels, _, ok := w.registryLabels.Value()
+	if !ok {
+		return w.config.WatchedKeys
+	}
+	return onchain.Apply(w.config.WatchedKeys, labels)
+}
+
+// fetchRegistryLabels pulls the key -> operator assignments of the watched keys from the registry contracts
+func (w *ValidatorWatcher) fetchRegistryLabels(ctx context.Context) (map[string][]string, error) {
+	w.keysMu.RLock()
+	pubkeys := make([]string, len(w.config.WatchedKeys))
+	for i, wk := range w.config.WatchedKeys {
+		pubkeys[i] = wk.PublicKey
+	}
+	w.keysMu.RUnlock()
+
+	labels, err := w.onchainRegistry.Labels(ctx, pubkeys)
+	if err != nil {
+		return nil, err
+	}
+
+	w.logger.WithFields(logrus.Fields{
+		"watched":  len(pubkeys),
+		"labelled": len(labels),
+	}).Info("Loaded on-chain registry labels")
+	return labels, nil
+}
+
 // loadAllValidators loads all validators from the beacon node
 func (w *ValidatorWatcher) loadAllValidators(ctx context.Context) error {
 	// Check if we should load all validators (default true)
@@ -148,14 +686,15 @@ func (w *ValidatorWatcher) loadAllValidators(ctx context.Context) error {
 	w.logger.Info("Loading all validators from beacon node (this may take 30-60 seconds for 2M+ validators)...")
 	w.logger.Info("This enables network-wide performance comparison (like Kiln's original behavior)")
 
-	allVals, err := w.beaconClient.GetAllValidators(ctx, "head")
-	if err != nil {
+	if _, err := w.loadFullSet(ctx, "head"); err != nil {
 		w.logger.WithError(err).Error("Failed to load all validators")
 		w.logger.Warn("Network comparison will be unavailable - continuing with watched validators only")
 		return w.loadWatchedValidatorsOnly(ctx)
 	}
 
-	w.allValidators.Update(allVals)
+	if w.clock != nil {
+		w.fullSetEpoch.Store(uint64(w.clock.CurrentEpoch()))
+	}
 	w.logger.WithField("count", w.allValidators.Count()).Info("✅ Successfully loaded all validators")
 
 	// Load watched validators
@@ -164,54 +703,39 @@ func (w *ValidatorWatcher) loadAllValidators(ctx context.Context) error {
 
 		var allWatchedVals []models.Validator
 
-		if allVals != nil {
+		if w.allValidators.Count() > 0 {
 			// Use all validators to find indices (fast - no API call needed!)
 			w.logger.Info("Using cached validator set to build watched validators (no API calls needed)")
 			watchedIndices := make([]models.ValidatorIndex, 0)
 			for _, wk := range w.config.WatchedKeys {
 				if v, ok := w.allValidators.GetByPubkey(wk.PublicKey); ok {
 					watchedIndices = append(watchedIndices, v.Index)
+					w.indexCache.Set(wk.PublicKey, v.Index)
 					// We already have the validator data, just extract it
 					if fullVal, ok := w.allValidators.Get(v.Index); ok {
 						allWatchedVals = append(allWatchedVals, *fullVal)
 					}
 				} else {
-					w.logger.WithField("pubkey", wk.PublicKey[:10]+"...").Warn("Watched validator not found in all validators set")
+					w.logger.WithField("pubkey", w.logPubkey(wk.PublicKey)).Warn("Watched validator not found in all validators set")
 				}
 			}
 			w.logger.WithField("found", len(allWatchedVals)).Info("Extracted watched validators from cached set")
+			if err := w.indexCache.Save(); err != nil {
+				w.logger.WithError(err).Warn("Failed to persist index cache")
+			}
 		} else {
-			// Can't use all validators, fetch by public keys in batches
-			w.logger.Info("Fetching watched validators by public keys in batches (since all validators unavailable)...")
-			batchSize := 100
-			for i := 0; i < len(w.config.WatchedKeys); i += batchSize {
-				end := i + batchSize
-				if end > len(w.config.WatchedKeys) {
-					end = len(w.config.WatchedKeys)
-				}
-
-				pubkeys := make([]string, end-i)
-				for j, wk := range w.config.WatchedKeys[i:end] {
-					pubkeys[j] = wk.PublicKey
-				}
-
-				w.logger.WithFields(logrus.Fields{
-					"batch": i/batchSize + 1,
-					"total": (len(w.config.WatchedKeys) + batchSize - 1) / batchSize,
-					"size":  len(pubkeys),
-				}).Debug("Fetching batch...")
-
-				batchVals, err := w.beaconClient.GetValidatorsByPubkeys(ctx, "head", pubkeys)
-				if err != nil {
-					return fmt.Errorf("failed to get watched validators batch %d: %w", i/batchSize+1, err)
-				}
-				allWatchedVals = append(allWatchedVals, batchVals...)
+			// Can't use all validators, fetch from the beacon node in batches
+			w.logger.Info("Fetching watched validators in batches (since all validators unavailable)...")
+			var err error
+			allWatchedVals, err = w.fetchWatchedValidators(ctx)
+			if err != nil {
+				return err
 			}
 			w.logger.WithField("total", len(allWatchedVals)).Info("Fetched all watched validators in batches")
 		}
 
 		if len(allWatchedVals) > 0 {
-			if err := w.watchedValidators.Update(allWatchedVals, w.config.WatchedKeys); err != nil {
+			if err := w.watchedValidators.Update(allWatchedVals, w.watchedKeys()); err != nil {
 				return fmt.Errorf("failed to update watched validators: %w", err)
 			}
 			w.logger.WithField("count", w.watchedValidators.Count()).Info("Successfully loaded watched validators")
@@ -230,38 +754,15 @@ func (w *ValidatorWatcher) loadWatchedValidatorsOnly(ctx context.Context) error
 		return nil
 	}
 
-	w.logger.WithField("count", len(w.config.WatchedKeys)).Info("Loading watched validators by public keys...")
-
-	// Fetch by public keys in batches
-	batchSize := 100
-	var allWatchedVals []models.Validator
-
-	for i := 0; i < len(w.config.WatchedKeys); i += batchSize {
-		end := i + batchSize
-		if end > len(w.config.WatchedKeys) {
-			end = len(w.config.WatchedKeys)
-		}
-
-		pubkeys := make([]string, end-i)
-		for j, wk := range w.config.WatchedKeys[i:end] {
-			pubkeys[j] = wk.PublicKey
-		}
-
-		w.logger.WithFields(logrus.Fields{
-			"batch": i/batchSize + 1,
-			"total": (len(w.config.WatchedKeys) + batchSize - 1) / batchSize,
-			"size":  len(pubkeys),
-		}).Debug("Fetching batch...")
+	w.logger.WithField("count", len(w.config.WatchedKeys)).Info("Loading watched validators...")
 
-		batchVals, err := w.beaconClient.GetValidatorsByPubkeys(ctx, "head", pubkeys)
-		if err != nil {
-			return fmt.Errorf("failed to get watched validators batch %d: %w", i/batchSize+1, err)
-		}
-		allWatchedVals = append(allWatchedVals, batchVals...)
+	allWatchedVals, err := w.fetchWatchedValidators(ctx)
+	if err != nil {
+		return err
 	}
 
 	if len(allWatchedVals) > 0 {
-		if err := w.watchedValidators.Update(allWatchedVals, w.config.WatchedKeys); err != nil {
+		if err := w.watchedValidators.Update(allWatchedVals, w.watchedKeys()); err != nil {
 			return fmt.Errorf("failed to update watched validators: %w", err)
 		}
 		w.logger.WithField("count", w.watchedValidators.Count()).Info("✅ Successfully loaded watched validators")
@@ -272,6 +773,91 @@ func (w *ValidatorWatcher) loadWatchedValidatorsOnly(ctx context.Context) error
 	return nil
 }
 
+// fetchWatchedValidators fetches the watched validators from the beacon node in batches
+// Pubkeys with a cached index are fetched by index, the rest by pubkey (and then cached)
+func (w *ValidatorWatcher) fetchWatchedValidators(ctx context.Context) ([]models.Validator, error) {
+	pubkeys := make([]string, len(w.config.WatchedKeys))
+	for i, wk := range w.config.WatchedKeys {
+		pubkeys[i] = wk.PublicKey
+	}
+
+	resolved, unresolved := w.indexCache.Resolve(pubkeys)
+	w.logger.WithFields(logrus.Fields{
+		"cached":     len(resolved),
+		"unresolved": len(unresolved),
+	}).Info("Resolved watched pubkeys from index cache")
+
+	batchSize := w.config.Startup.BatchSize
+
+	// Index-based lookups for already resolved pubkeys, pubkey-based lookups for the rest
+	indices := make([]models.ValidatorIndex, 0, len(resolved))
+	for _, index := range resolved {
+		indices = append(indices, index)
+	}
+	indexBatches := batch.Count(len(indices), batchSize)
+	totalBatches := indexBatches + batch.Count(len(unresolved), batchSize)
+
+	results := make([][]models.Validator, totalBatches)
+	var loaded atomic.Int64
+	fetch := func(ctx context.Context, i int) error {
+		var batchVals []models.Validator
+		var err error
+		if i < indexBatches {
+			start := i * batchSize
+			end := min(start+batchSize, len(indices))
+			batchVals, err = w.beaconClient.GetValidators(ctx, "head", indices[start:end])
+			if err != nil {
+				return fmt.Errorf("failed to get watched validators by index batch %d: %w", i+1, err)
+			}
+		} else {
+			start := (i - indexBatches) * batchSize
+			end := min(start+batchSize, len(unresolved))
+			batchVals, err = w.beaconClient.GetValidatorsByPubkeys(ctx, "head", unresolved[start:end])
+			if err != nil {
+				return fmt.Errorf("failed to get watched validators batch %d: %w", i-indexBatches+1, err)
+			}
+			for _, v := range batchVals {
+				w.indexCache.Set(v.Data.Pubkey, v.Index)
+			}
+		}
+		results[i] = batchVals
+		loaded.Add(int64(len(batchVals)))
+		return nil
+	}
+
+	// Log roughly every 10% so big fleets show steady progress
+	logEvery := max(1, totalBatches/10)
+	progress := func(done int) {
+		w.prometheusMetrics.SetStartupProgress(w.config.Network, done, totalBatches, int(loaded.Load()))
+		if done%logEvery == 0 || done == totalBatches {
+			w.logger.WithFields(logrus.Fields{
+				"batches":    fmt.Sprintf("%d/%d", done, totalBatches),
+				"validators": loaded.Load(),
+			}).Info("Loading watched validators...")
+		}
+	}
+
+	w.prometheusMetrics.SetStartupProgress(w.config.Network, 0, totalBatches, 0)
+	pacer := batch.Pacer{
+		Concurrency: w.config.Startup.MaxConcurrentBatches,
+		Delay:       time.Duration(w.config.Startup.BatchDelayMs) * time.Millisecond,
+	}
+	if err := pacer.Run(ctx, totalBatches, fetch, progress); err != nil {
+		return nil, err
+	}
+
+	allWatchedVals := make([]models.Validator, 0, loaded.Load())
+	for _, batchVals := range results {
+		allWatchedVals = append(allWatchedVals, batchVals...)
+	}
+
+	if err := w.indexCache.Save(); err != nil {
+		w.logger.WithError(err).Warn("Failed to persist index cache")
+	}
+
+	return allWatchedVals, nil
+}
+
 // mainLoop runs the main monitoring loop
 func (w *ValidatorWatcher) mainLoop(ctx context.Context) error {
 	// If no clock, we're in snapshot mode - just load data and exit
@@ -280,11 +866,10 @@ func (w *ValidatorWatcher) mainLoop(ctx context.Context) error {
 		w.logger.Info("Validator data loaded successfully")
 
 		// Update metrics once
-		allVals := w.allValidators.GetAll()
 		watchedVals := w.watchedValidators.GetAll()
 
 		w.logger.WithFields(logrus.Fields{
-			"all_validators":     len(allVals),
+			"all_validators":     w.allValidators.Count(),
 			"watched_validators": len(watchedVals),
 		}).Info("Snapshot complete")
 
@@ -297,6 +882,12 @@ func (w *ValidatorWatcher) mainLoop(ctx context.Context) error {
 	}
 
 	w.logger.Info("Starting main monitoring loop...")
+	w.startWarmup(w.clock.CurrentSlot())
+
+	// Follow the node's head when it has an event stream, so a slot is only processed once
+	// its block could have been imported; otherwise process slots on the local clock
+	eventDriven := w.startEvents(ctx)
+	currentSlot := w.clock.CurrentSlot()
 
 	for {
 		select {
@@ -312,8 +903,21 @@ func (w *ValidatorWatcher) mainLoop(ctx context.Context) error {
 			return nil
 		}
 
-		// Get current slot
-		currentSlot := w.clock.CurrentSlot()
+		if eventDriven {
+			// Slots are processed in order; after a long stall, resume at the current slot
+			if now := w.clock.CurrentSlot(); now > currentSlot+models.Slot(w.clock.SlotsPerEpoch()) {
+				w.logger.WithFields(logrus.Fields{
+					"from_slot": currentSlot,
+					"to_slot":   now,
+				}).Warn("Fell more than an epoch behind - skipping to the current slot")
+				currentSlot = now
+			}
+			if err := w.waitForSlot(ctx, currentSlot); err != nil {
+				return err
+			}
+		} else {
+			currentSlot = w.clock.CurrentSlot()
+		}
 		currentEpoch := w.clock.SlotToEpoch(currentSlot)
 
 		// Log slot info every 10 slots or if it's the first slot of an epoch
@@ -326,119 +930,317 @@ func (w *ValidatorWatcher) mainLoop(ctx context.Context) error {
 			}).Info("📊 Slot checkpoint")
 		}
 
-		// Process epoch if it's the first slot
-		if w.clock.IsFirstSlotOfEpoch(currentSlot) {
-			if err := w.processEpoch(ctx, currentEpoch); err != nil {
+		// Run this slot's work within the slot budget (critical duty accounting first), traced as one span
+		slotCtx, span := tracing.Start(ctx, "mainLoop", tracing.Slot(currentSlot), tracing.Epoch(currentEpoch))
+		paused := w.checkNodeSync(slotCtx, currentSlot)
+		tasks := w.slotTasks(currentSlot, currentEpoch)
+		if paused {
+			tasks = withoutSyncPausedTasks(tasks)
+		}
+		report := w.scheduler.RunSlot(slotCtx, w.slotDeadline(currentSlot), tasks)
+		span.End()
+		w.prometheusMetrics.RecordSchedule(w.config.Network, report)
+		w.lastSlotAt.Store(time.Now().UnixNano())
+		if !w.clock.IsReplayMode() {
+			w.prometheusMetrics.RecordProgress(w.config.Network, currentEpoch, w.clock.SlotToEpoch(w.clock.TimeToSlot(uint64(time.Now().Unix()))))
+		}
+		if report.Remaining < 0 {
+			w.logger.WithFields(logrus.Fields{
+				"slot":        currentSlot,
+				"over_budget": -report.Remaining,
+			}).Warn("Slot work exceeded its time budget")
+		}
+
+		// Wait for next slot
+		if !eventDriven {
+			if _, err := w.clock.WaitUntilNextSlot(ctx); err != nil {
+				return err
+			}
+		}
+
+		// Cleanup old data
+		w.cleanup(currentSlot)
+		currentSlot++
+	}
+}
+
+// slotTasks returns the work to schedule for a slot
+func (w *ValidatorWatcher) slotTasks(slot models.Slot, epoch models.Epoch) []scheduler.Task {
+	tasks := make([]scheduler.Task, 0, 6)
+
+	// Process epoch if it's the first slot
+	if w.clock.IsFirstSlotOfEpoch(slot) {
+		tasks = append(tasks, scheduler.Task{Name: "epoch", Priority: scheduler.PriorityCritical, Run: func(ctx context.Context) error {
+			if err := w.processEpoch(ctx, epoch); err != nil {
 				w.logger.WithError(err).Error("Failed to process epoch")
+				return err
 			}
+			w.prometheusMetrics.MarkEpochProcessed(w.config.Network, time.Now())
+			return nil
+		}})
+	}
+
+	// Refetch proposer duties and re-evaluate proposals after a reorg, before this slot uses them
+	if from, to, ok := w.reorgs.take(); ok {
+		tasks = append(tasks, scheduler.Task{Name: "reorg", Priority: scheduler.PriorityCritical, Run: func(ctx context.Context) error {
+			if err := w.reevaluateReorg(ctx, from, to, slot); err != nil {
+				// Put the slots back so the next slot retries them, merged with any newer reorg
+				w.reorgs.add(reorg.Window{From: from, To: to})
+				w.logger.WithError(err).Warn("Failed to re-evaluate block proposals after reorg")
+				return err
+			}
+			return nil
+		}})
+	}
+
+	// Process current slot
+	tasks = append(tasks, scheduler.Task{Name: "slot", Priority: scheduler.PriorityCritical, Run: func(ctx context.Context) error {
+		if err := w.processSlot(ctx, slot); err != nil {
+			w.logger.WithError(err).Error("Failed to process slot")
+			return err
 		}
+		return nil
+	}})
+
+	// Warn about upcoming proposals of validators that are missing attestations
+	if w.config.ProposalLookahead.Slots > 0 {
+		tasks = append(tasks, scheduler.Task{Name: "proposal_lookahead", Priority: scheduler.PriorityNormal, Run: func(ctx context.Context) error {
+			w.checkProposalLookahead(slot)
+			return nil
+		}})
+	}
 
-		// Process slot-specific tasks
-		if w.clock.IsSlotInEpoch(currentSlot, 16) {
-			// Process liveness at slot 16
-			if err := w.processLiveness(ctx, currentEpoch-1); err != nil {
+	// Process the previous epoch's liveness at liveness_slot (16)
+	if w.clock.IsSlotInEpoch(slot, w.taskSlots.liveness) {
+		tasks = append(tasks, scheduler.Task{Name: "liveness", Priority: scheduler.PriorityCritical, Run: func(ctx context.Context) error {
+			if err := w.processLiveness(ctx, epoch-1); err != nil {
 				w.logger.WithError(err).Error("Failed to process liveness")
+				return err
 			}
-		}
+			return nil
+		}})
+	}
 
-		if w.clock.IsSlotInEpoch(currentSlot, 17) {
-			// Process rewards at slot 17 (for epoch - 2)
-			if currentEpoch >= 2 {
-				if err := w.processRewards(ctx, currentEpoch-2); err != nil {
-					w.logger.WithError(err).Error("Failed to process rewards")
-				}
+	// Process rewards at rewards_slot (17) (for epoch - 2)
+	if w.clock.IsSlotInEpoch(slot, w.taskSlots.rewards) && epoch >= 2 {
+		tasks = append(tasks, scheduler.Task{Name: "rewards", Priority: scheduler.PriorityCritical, Run: func(ctx context.Context) error {
+			if err := w.processRewards(ctx, epoch-2); err != nil {
+				w.logger.WithError(err).Error("Failed to process rewards")
+				return err
 			}
-		}
+			return nil
+		}})
+	}
+
+	// Sample the network's attestation rewards for the same epoch, as the all-network baseline
+	if w.config.NetworkSample > 0 && w.clock.IsSlotInEpoch(slot, w.taskSlots.rewards) && epoch >= 2 && w.optionalWorkDue("network_baseline", epoch) {
+		tasks = append(tasks, scheduler.Task{Name: "network_baseline", Priority: scheduler.PriorityNormal, Run: func(ctx context.Context) error {
+			if err := w.processNetworkBaseline(ctx, epoch-2); err != nil {
+				w.logger.WithError(err).Warn("Failed to sample the network attestation baseline")
+				return err
+			}
+			return nil
+		}})
+	}
+
+	// Settle proposals of finalized slots at slot 18 (pending ones wait for the next epoch)
+	if w.clock.IsSlotInEpoch(slot, w.taskSlots.finality) {
+		tasks = append(tasks, scheduler.Task{Name: "finality", Priority: scheduler.PriorityNormal, Run: func(ctx context.Context) error {
+			if err := w.reconcileFinalized(ctx); err != nil {
+				w.logger.WithError(err).Warn("Failed to reconcile finalized block proposals")
+				return err
+			}
+			return nil
+		}})
+	}
+
+	// Refetch the remote key list at slot 14 every watched_keys_refresh_epochs epochs
+	if w.config.WatchedKeysURL != "" && w.clock.IsSlotInEpoch(slot, w.taskSlots.keyLists) && uint64(epoch)%uint64(w.config.WatchedKeysRefreshEpochs) == 0 {
+		tasks = append(tasks, scheduler.Task{Name: "watched_keys_url", Priority: scheduler.PriorityNormal, Run: func(ctx context.Context) error {
+			if err := w.refreshRemoteKeys(ctx); err != nil {
+				w.logger.WithError(err).Warn("Failed to refresh watched keys from URL - keeping the previous list")
+				return err
+			}
+			return nil
+		}})
+	}
+
+	// Relist the Web3Signer keys at slot 14 every web3signer.refresh_epochs epochs
+	if w.config.Web3Signer.URL != "" && w.clock.IsSlotInEpoch(slot, w.taskSlots.keyLists) && uint64(epoch)%uint64(w.config.Web3Signer.RefreshEpochs) == 0 {
+		tasks = append(tasks, scheduler.Task{Name: "web3signer", Priority: scheduler.PriorityNormal, Run: func(ctx context.Context) error {
+			if err := w.refreshSignerKeys(ctx); err != nil {
+				w.logger.WithError(err).Warn("Failed to refresh watched keys from Web3Signer - keeping the previous list")
+				return err
+			}
+			return nil
+		}})
+	}
 
-		if w.clock.IsSlotInEpoch(currentSlot, 15) {
-			// Reload config at slot 15
-			if err := w.reloadConfig(); err != nil {
+	// Reload config at config_reload_slot (15), or at the next slot when requested
+	if w.reloadPending() || w.clock.IsSlotInEpoch(slot, w.taskSlots.reload) {
+		tasks = append(tasks, scheduler.Task{Name: "reload_config", Priority: scheduler.PriorityNormal, Run: func(ctx context.Context) error {
+			if err := w.reloadConfig(ctx, w.reloadRequested()); err != nil {
 				w.logger.WithError(err).Error("Failed to reload config")
+				return err
 			}
-		}
+			return nil
+		}})
+	}
 
-		// Process current slot
-		if err := w.processSlot(ctx, currentSlot); err != nil {
-			w.logger.WithError(err).Error("Failed to process slot")
-		}
+	// Update metrics
+	tasks = append(tasks, scheduler.Task{Name: "metrics", Priority: scheduler.PriorityNormal, Run: func(ctx context.Context) error {
+		w.updateMetrics(slot, epoch)
+		return nil
+	}})
 
-		// Update metrics
-		w.updateMetrics(currentSlot, currentEpoch)
+	// Summary report on its schedule, with spare slot time
+	if task, ok := w.reportTask(slot, time.Now()); ok {
+		tasks = append(tasks, task)
+	}
 
-		// Wait for next slot
-		if _, err := w.clock.WaitUntilNextSlot(ctx); err != nil {
-			return err
-		}
+	// Persist state once per epoch with spare slot time
+	if w.store != nil && w.clock.IsSlotInEpoch(slot, w.taskSlots.saveState) {
+		tasks = append(tasks, scheduler.Task{Name: "save_state", Priority: scheduler.PriorityIdle, Run: func(ctx context.Context) error {
+			if err := w.saveState(); err != nil {
+				w.logger.WithError(err).Warn("Failed to save state")
+				return err
+			}
+			return nil
+		}})
+	}
 
-		// Cleanup old data
-		w.cleanup(currentSlot)
+	// Export the served data for read-only replicas once per epoch
+	if w.config.SnapshotFile != "" && w.clock.IsSlotInEpoch(slot, w.taskSlots.snapshot) {
+		tasks = append(tasks, scheduler.Task{Name: "export_snapshot", Priority: scheduler.PriorityIdle, Run: func(ctx context.Context) error {
+			if err := w.exportSnapshot(slot); err != nil {
+				w.logger.WithError(err).Warn("Failed to export snapshot")
+				return err
+			}
+			return nil
+		}})
+	}
+
+	return tasks
+}
+
+// slotDeadline returns when work for a slot must be done (the next slot's processing time)
+func (w *ValidatorWatcher) slotDeadline(slot models.Slot) time.Time {
+	if w.clock.IsReplayMode() || w.head != nil {
+		// Replay doesn't wait between slots and event-driven slots start when their block
+		// arrives, so give each slot a full slot duration from now
+		return time.Now().Add(time.Duration(w.clock.SecondsPerSlot()) * time.Second)
 	}
+	return w.clock.SlotEndTime(slot)
 }
 
 // processEpoch processes epoch-specific tasks
-func (w *ValidatorWatcher) processEpoch(ctx context.Context, epoch models.Epoch) error {
+func (w *ValidatorWatcher) processEpoch(ctx context.Context, epoch models.Epoch) (err error) {
+	ctx, span := tracing.Start(ctx, "processEpoch", tracing.Epoch(epoch))
+	defer func() { tracing.End(span, err) }()
+
 	w.logger.WithField("epoch", epoch).Info("Processing epoch")
 
+	// Shed optional work first if the previous epoch's requests show an overloaded node
+	w.observeBeaconLoad(epoch)
+
+	// Pin the head state so every state query of this cycle sees the same chain view
+	stateID := w.pinState(ctx)
+
 	// Load ALL validators (full 2M+ set) in background - non-blocking
 	// This is used for network-wide comparison metrics
-	if w.config.ShouldLoadAllValidators() {
-		go func() {
-			allVals, err := w.beaconClient.GetAllValidators(ctx, "head")
-			if err != nil {
-				w.logger.WithError(err).Warn("Failed to load all validators (background)")
-				return
-			}
-			w.allValidators.Update(allVals)
-			w.logger.WithField("count", w.allValidators.Count()).Debug("✅ Updated all validators cache (background)")
-		}()
+	if w.config.ShouldLoadAllValidators() && w.optionalWorkDue("full_validator_set", epoch) {
+		go w.refreshAllValidators(ctx, epoch, stateID)
 	}
 
-	// Load watched validators
+	// Resolve the watched validators' indices
 	watchedIndices := make([]models.ValidatorIndex, 0)
 	for _, wk := range w.config.WatchedKeys {
-		if v, ok := w.allValidators.GetByPubkey(wk.PublicKey); ok {
+		if index, ok := w.indexCache.Get(wk.PublicKey); ok {
+			watchedIndices = append(watchedIndices, index)
+		} else if v, ok := w.allValidators.GetByPubkey(wk.PublicKey); ok {
 			watchedIndices = append(watchedIndices, v.Index)
+			w.indexCache.Set(wk.PublicKey, v.Index)
 		} else {
-			w.logger.WithField("pubkey", wk.PublicKey).Warn("Watched validator not found")
+			w.logger.WithField("pubkey", w.logPubkey(wk.PublicKey)).Warn("Watched validator not found")
 		}
 	}
+	if err := w.indexCache.Save(); err != nil {
+		w.logger.WithError(err).Warn("Failed to persist index cache")
+	}
 
-	if len(watchedIndices) > 0 {
-		watchedVals, err := w.beaconClient.GetValidators(ctx, "head", watchedIndices)
-		if err != nil {
-			return fmt.Errorf("failed to get watched validators: %w", err)
-		}
-		if err := w.watchedValidators.Update(watchedVals, w.config.WatchedKeys); err != nil {
-			return fmt.Errorf("failed to update watched validators: %w", err)
+	// Refresh the watched validators, duties and schedules as a pipeline, so independent requests
+	// overlap instead of adding up within the slot
+	start := time.Now()
+	results, err := scheduler.RunPipeline(ctx, w.epochStages(epoch, stateID, watchedIndices))
+	if err != nil {
+		return fmt.Errorf("invalid epoch pipeline: %w", err)
+	}
+	w.prometheusMetrics.RecordEpochPipeline(w.config.Network, results, time.Since(start), time.Duration(w.clock.SecondsPerSlot())*time.Second)
+	for _, result := range results {
+		if result.Name == stageValidators && result.Outcome != scheduler.OutcomeCompleted {
+			return result.Err
 		}
-		w.logger.WithField("count", w.watchedValidators.Count()).Info("Updated watched validators")
 	}
 
-	// Update proposer schedule for current and next epoch
-	if err := w.proposerSchedule.Update(ctx, epoch); err != nil {
-		w.logger.WithError(err).Warn("Failed to update proposer schedule for current epoch")
+	// Pending deposits, consolidations and withdrawals (once per epoch, off the slot's critical path)
+	if w.optionalWorkDue("pending_queues", epoch) {
+		go w.refreshPendingQueues(ctx, epoch, stateID)
 	}
-	if err := w.proposerSchedule.Update(ctx, epoch+1); err != nil {
-		w.logger.WithError(err).Warn("Failed to update proposer schedule for next epoch")
+
+	// Keep signing_history_epochs of history for the EIP-3076 export
+	if retention := models.Epoch(w.config.SigningHistoryEpochs); epoch > retention {
+		w.signingHistory.Prune(epoch-retention, w.clock.SlotsPerEpoch())
 	}
 
-	// Fetch pending deposits, consolidations, withdrawals
-	if _, err := w.beaconClient.GetPendingDeposits(ctx, "head"); err != nil {
-		w.logger.WithError(err).Debug("Failed to get pending deposits")
+	w.lastProcessedEpoch = epoch
+
+	if w.warmup && epoch >= w.warmupEndEpoch {
+		w.warmup = false
+		w.prometheusMetrics.SetWarmup(w.config.Network, false)
+		w.logger.WithField("epoch", epoch).Info("✅ Warmup complete - recording duty misses")
 	}
-	if _, err := w.beaconClient.GetPendingConsolidations(ctx, "head"); err != nil {
-		w.logger.WithError(err).Debug("Failed to get pending consolidations")
+
+	return nil
+}
+
+// pinState resolves the current head to its state root
+// Falls back to "head" (unpinned) if the header can't be fetched
+func (w *ValidatorWatcher) pinState(ctx context.Context) string {
+	header, err := w.beaconClient.GetHeader(ctx, "head")
+	if err != nil || header.Header.Message.StateRoot == "" {
+		w.logger.WithError(err).Warn("Failed to pin head state - epoch queries will use unpinned head")
+		return "head"
 	}
-	if _, err := w.beaconClient.GetPendingWithdrawals(ctx, "head"); err != nil {
-		w.logger.WithError(err).Debug("Failed to get pending withdrawals")
+
+	w.logger.WithFields(logrus.Fields{
+		"slot":       header.Header.Message.Slot,
+		"state_root": header.Header.Message.StateRoot,
+	}).Debug("Pinned state for epoch processing")
+	return header.Header.Message.StateRoot
+}
+
+// startWarmup enters warmup mode when monitoring starts
+// Starting mid-epoch means duties and committees for the epoch were never fully observed,
+// so misses are only recorded from the first fully processed epoch on
+func (w *ValidatorWatcher) startWarmup(startSlot models.Slot) {
+	w.warmupEndEpoch = w.clock.SlotToEpoch(startSlot)
+	if !w.clock.IsFirstSlotOfEpoch(startSlot) {
+		w.warmupEndEpoch++
 	}
+	w.warmup = true
+	w.prometheusMetrics.SetWarmup(w.config.Network, true)
 
-	w.lastProcessedEpoch = epoch
-	return nil
+	w.logger.WithFields(logrus.Fields{
+		"start_slot":       startSlot,
+		"warmup_end_epoch": w.warmupEndEpoch,
+	}).Info("🌡️  Warmup: observing without recording misses until a full epoch is processed")
 }
 
 // processSlot processes slot-specific tasks
 func (w *ValidatorWatcher) processSlot(ctx context.Context, slot models.Slot) error {
+	ctx, span := tracing.Start(ctx, "processSlot", tracing.Slot(slot))
+	defer span.End()
+
 	// Process block
 	if err := w.processBlock(ctx, slot); err != nil {
 		w.logger.WithError(err).Debug("Failed to process block (may not exist)")
@@ -459,24 +1261,35 @@ func (w *ValidatorWatcher) processBlock(ctx context.Context, slot models.Slot) e
 		// Block may not exist (missed)
 		if proposerIndex, ok := w.proposerSchedule.GetProposer(slot); ok {
 			if v, ok := w.watchedValidators.Get(proposerIndex); ok {
+				if w.warmup {
+					w.logger.WithFields(logrus.Fields{
+						"slot":            slot,
+						"validator_index": w.anonymizer.Index(proposerIndex),
+					}).Debug("Warmup: not recording missed block")
+					return err
+				}
+
 				w.watchedValidators.UpdateMetrics(proposerIndex, func(wv *validator.WatchedValidator) {
 					wv.MissedBlocks++
 				})
+				w.finality.Track(proposer.Proposal{Slot: slot, ValidatorIndex: proposerIndex})
 
-				// Get primary label (non-scope label)
-				primaryLabel := "unknown"
-				for _, label := range v.Labels {
-					if !strings.HasPrefix(label, "scope:") && !strings.HasPrefix(label, "key:") {
-						primaryLabel = label
-						break
-					}
-				}
+				label := primaryLabel(v.Labels)
+
+				w.events.Emit(events.Event{
+					Type:           events.TypeMissedBlock,
+					Slot:           slot,
+					Epoch:          w.clock.SlotToEpoch(slot),
+					ValidatorIndex: proposerIndex,
+					Pubkey:         v.Data.Pubkey,
+					Label:          label,
+				})
 
 				w.logger.WithFields(logrus.Fields{
 					"slot":            slot,
-					"validator_index": proposerIndex,
-					"pubkey":          v.Data.Pubkey[:14] + "...",
-					"label":           primaryLabel,
+					"validator_index": w.anonymizer.Index(proposerIndex),
+					"pubkey":          w.logPubkey(v.Data.Pubkey),
+					"label":           label,
 					"total_missed":    v.MissedBlocks + 1,
 				}).Warn("❌ MISSED BLOCK")
 			}
@@ -484,21 +1297,24 @@ func (w *ValidatorWatcher) processBlock(ctx context.Context, slot models.Slot) e
 		return err
 	}
 
+	w.trackBlockRoot(ctx, slot)
+	w.processSlashings(block, slot)
+	w.recordSyncParticipation(block, slot)
+	w.recordWithdrawals(block, slot)
+
 	// Block was proposed
 	proposerIndex := models.ValidatorIndex(block.Message.ProposerIndex)
 	if v, ok := w.watchedValidators.Get(proposerIndex); ok {
 		w.watchedValidators.UpdateMetrics(proposerIndex, func(wv *validator.WatchedValidator) {
 			wv.ProposedBlocks++
 		})
+		w.signingHistory.RecordBlock(v.Data.Pubkey, slot)
+		w.finality.Track(proposer.Proposal{Slot: slot, ValidatorIndex: proposerIndex, HeadProposed: true})
+		clRewards := w.recordBlockRewards(ctx, block, v)
+		w.checkFeeRecipient(block, slot, v)
+		graffiti, client := w.recordGraffiti(block, v)
 
-		// Get primary label
-		primaryLabel := "unknown"
-		for _, label := range v.Labels {
-			if !strings.HasPrefix(label, "scope:") && !strings.HasPrefix(label, "key:") {
-				primaryLabel = label
-				break
-			}
-		}
+		label := primaryLabel(v.Labels)
 
 		// Get fee recipient if available
 		feeRecipient := "unknown"
@@ -506,12 +1322,28 @@ func (w *ValidatorWatcher) processBlock(ctx context.Context, slot models.Slot) e
 			feeRecipient = block.Message.Body.ExecutionPayload.FeeRecipient[:10] + "..."
 		}
 
+		w.events.Emit(events.Event{
+			Type:           events.TypeBlockProposed,
+			Slot:           slot,
+			Epoch:          w.clock.SlotToEpoch(slot),
+			ValidatorIndex: proposerIndex,
+			Pubkey:         v.Data.Pubkey,
+			Label:          label,
+			Data: map[string]interface{}{
+				"graffiti":         graffiti,
+				"consensus_client": client.Consensus,
+				"execution_client": client.Execution,
+			},
+		})
+
 		w.logger.WithFields(logrus.Fields{
 			"slot":            slot,
-			"validator_index": proposerIndex,
-			"pubkey":          v.Data.Pubkey[:14] + "...",
-			"label":           primaryLabel,
+			"validator_index": w.anonymizer.Index(proposerIndex),
+			"pubkey":          w.logPubkey(v.Data.Pubkey),
+			"label":           label,
 			"fee_recipient":   feeRecipient,
+			"graffiti":        graffiti,
+			"cl_rewards_gwei": clRewards,
 			"total_proposed":  v.ProposedBlocks + 1,
 		}).Info("✅ BLOCK PROPOSED")
 	}
@@ -539,6 +1371,12 @@ func (w *ValidatorWatcher) processAttestations(ctx context.Context, slot models.
 		return err
 	}
 
+	// Every attestation in the block may carry aggregates for older committees
+	if err := w.aggregation.ObserveBlock(attestations); err != nil {
+		w.logger.WithError(err).Debug("Failed to observe committee aggregates")
+	}
+	w.processAggregationOutcomes(slot)
+
 	// Get committees for the PREVIOUS slot (where validators had duties)
 	committees, err := w.beaconClient.GetCommittees(ctx, "head", nil, &previousSlot)
 	if err != nil {
@@ -556,26 +1394,41 @@ func (w *ValidatorWatcher) processAttestations(ctx context.Context, slot models.
 	// Build set of validators with duties in the PREVIOUS slot
 	validatorsWithDuties := make(map[models.ValidatorIndex]bool)
 	for _, committee := range committees {
-		for _, validatorStr := range committee.Validators {
-			var validatorIdx models.ValidatorIndex
-			fmt.Sscanf(validatorStr, "%d", &validatorIdx)
+		for _, validatorIdx := range w.committeeResolver.Members(committee) {
 			validatorsWithDuties[validatorIdx] = true
 		}
 	}
 
 	// Process attestations (for previous slot)
-	attested, err := duties.ProcessAttestations(filteredAttestations, committees)
+	attested, err := w.committeeResolver.ProcessAttestations(filteredAttestations, committees)
 	if err != nil {
 		return err
 	}
+	w.recordAttestationHistory(filteredAttestations, committees, attested)
+	w.recordInclusionDelays(ctx, slot, attestations, previousSlot, attested)
+
+	// During warmup, observe only: duty outcomes are not recorded
+	if w.warmup {
+		w.logger.WithFields(logrus.Fields{
+			"attesting_slot": previousSlot,
+			"attested":       len(attested),
+		}).Debug("Warmup: not recording attestation duties")
+		return nil
+	}
 
-	// Update attestation duty metrics - ONLY for validators with duties this slot
-	missedCount := 0
+	// Record the duties of watched validators; their outcome is counted once the epoch settles on liveness
+	attestingEpoch := w.clock.SlotToEpoch(previousSlot)
+	w.auditCoverage(attestingEpoch)
 	dutiesCount := 0
-	var missedDetails []string
-	missedByLabel := make(map[string]int) // Track misses by primary label
+	notIncluded := events.NewSampler(w.config.LogSampling.MaxExamples)
+	slotDuties := metrics.SlotDutiesByLabel{}
 
 	for validatorIdx := range validatorsWithDuties {
+		// Network-wide outcome, with the stake of the validators that had a duty
+		if nv, ok := w.allValidators.Get(validatorIdx); ok {
+			slotDuties.Add(networkScope, attested[validatorIdx], nv.Data.EffectiveBalance)
+		}
+
 		// Only process if this is one of our watched validators
 		v, ok := w.watchedValidators.Get(validatorIdx)
 		if !ok {
@@ -583,72 +1436,33 @@ func (w *ValidatorWatcher) processAttestations(ctx context.Context, slot models.
 		}
 
 		dutiesCount++
-
+		slotDuties.Add(w.aggregatedScopes(v.Labels), attested[validatorIdx], v.Data.EffectiveBalance)
+		w.attestationDuties.Assign(attestingEpoch, validatorIdx, previousSlot)
 		if attested[validatorIdx] {
-			// Successfully attested
-			w.watchedValidators.UpdateMetrics(validatorIdx, func(wv *validator.WatchedValidator) {
-				wv.AttestationDutiesSuccess++
-				wv.AttestationDuties++
-				wv.ConsecutiveMissedAttest = 0
-			})
+			w.attestationDuties.Include(attestingEpoch, validatorIdx)
 		} else {
-			// Missed attestation
-			missedCount++
-
-			// Get primary label
-			primaryLabel := "unknown"
-			for _, label := range v.Labels {
-				if !strings.HasPrefix(label, "scope:") && !strings.HasPrefix(label, "key:") {
-					primaryLabel = label
-					break
-				}
-			}
-			missedByLabel[primaryLabel]++
-
-			w.watchedValidators.UpdateMetrics(validatorIdx, func(wv *validator.WatchedValidator) {
-				wv.ConsecutiveMissedAttest++
-				wv.AttestationDuties++
-			})
-
-			// Log first 5 missed attestations with details
-			if len(missedDetails) < 5 {
-				missedDetails = append(missedDetails, fmt.Sprintf("v%d (%s, consecutive: %d)",
-					validatorIdx, primaryLabel, v.ConsecutiveMissedAttest+1))
-			}
+			notIncluded.Add(fmt.Sprintf("v%d (%s)", w.anonymizer.Index(validatorIdx), primaryLabel(v.Labels)))
 		}
 	}
 
-	// Log attestation summary if there were any misses
-	if missedCount > 0 {
-		logFields := logrus.Fields{
-			"current_slot":   slot,
-			"attesting_slot": previousSlot,
-			"missed_count":   missedCount,
-			"duties_count":   dutiesCount,
-			"miss_rate":      fmt.Sprintf("%.2f%%", float64(missedCount)*100/float64(dutiesCount)),
-		}
-
-		if len(missedDetails) > 0 {
-			logFields["examples"] = strings.Join(missedDetails, "; ")
-		}
+	w.prometheusMetrics.SetSlotDuties(w.config.Network, slotDuties)
+	w.prometheusMetrics.MarkUpdated(metrics.SourceAttestations, w.config.Network)
+	w.coverage.Record(attestingEpoch, w.clock.IsFirstSlotOfEpoch(previousSlot), dutiesCount)
 
-		if missedCount > 5 {
-			logFields["more"] = fmt.Sprintf("+%d more", missedCount-5)
-		}
-
-		// Show breakdown by label
-		if len(missedByLabel) > 0 {
-			labelBreakdown := make([]string, 0)
-			for label, count := range missedByLabel {
-				labelBreakdown = append(labelBreakdown, fmt.Sprintf("%s:%d", label, count))
-			}
-			logFields["by_label"] = strings.Join(labelBreakdown, ", ")
+	// Attestations missing from the next block may still be included late: misses are only
+	// counted when the epoch settles
+	if notIncludedCount := notIncluded.Count(); notIncludedCount > 0 {
+		logFields := logrus.Fields{
+			"current_slot":       slot,
+			"attesting_slot":     previousSlot,
+			"not_included_count": notIncludedCount,
+			"duties_count":       dutiesCount,
 		}
-
-		w.logger.WithFields(logFields).Warn("⚠️  MISSED ATTESTATIONS")
+		notIncluded.AddFields(logFields, "examples")
+		w.logger.WithFields(logFields).Debug("Attestations not included in the next block")
 	} else if dutiesCount > 0 {
 		// All attestations successful - log occasionally
-		if dutiesCount > 100 || slot%32 == 0 { // Log if many duties or once per epoch
+		if dutiesCount > 100 || w.clock.IsFirstSlotOfEpoch(slot) { // Log if many duties or once per epoch
 			w.logger.WithFields(logrus.Fields{
 				"current_slot":   slot,
 				"attesting_slot": previousSlot,
@@ -660,51 +1474,88 @@ func (w *ValidatorWatcher) processAttestations(ctx context.Context, slot models.
 	return nil
 }
 
-// processLiveness processes validator liveness data
-func (w *ValidatorWatcher) processLiveness(ctx context.Context, epoch models.Epoch) error {
-	indices := make([]models.ValidatorIndex, 0)
-	for _, v := range w.watchedValidators.GetAll() {
-		indices = append(indices, v.Index)
+// processAggregationOutcomes records committees whose aggregate inclusion window has closed
+func (w *ValidatorWatcher) processAggregationOutcomes(slot models.Slot) {
+	window := models.Slot(w.clock.SlotsPerEpoch())
+	if slot <= window {
+		return
 	}
 
+	missed := events.NewSampler(w.config.LogSampling.MaxExamples)
+	for _, outcome := range w.aggregation.Expire(slot - window) {
+		w.watchedValidators.UpdateMetrics(outcome.ValidatorIndex, func(wv *validator.WatchedValidator) {
+			wv.ExpectedAggregations += outcome.SelectionProbability
+			if outcome.AggregateIncluded {
+				wv.CommitteeAggregatesIncluded++
+			} else {
+				wv.CommitteeAggregatesMissed++
+			}
+		})
+		if !outcome.AggregateIncluded {
+			missed.Add(fmt.Sprintf("v%d (slot %d, committee %d)",
+				w.anonymizer.Index(outcome.ValidatorIndex), outcome.Slot, outcome.CommitteeIndex))
+		}
+	}
+
+	if missed.Count() > 0 {
+		logFields := logrus.Fields{
+			"current_slot": slot,
+			"missed_count": missed.Count(),
+		}
+		missed.AddFields(logFields, "examples")
+		w.logger.WithFields(logFields).Warn("⚠️  Committee aggregates not included on chain")
+	}
+}
+
+// processLiveness processes validator liveness data and settles the epoch's attestation duties
+// If liveness can't be fetched, the duties settle on block inclusion alone
+func (w *ValidatorWatcher) processLiveness(ctx context.Context, epoch models.Epoch) error {
+	// Only validators owing an attestation in the epoch can miss one: pending and exited ones
+	// would otherwise count as offline
+	indices := w.observeLiability(epoch)
 	if len(indices) == 0 {
+		w.settleAttestationDuties(epoch, nil)
 		return nil
 	}
 
 	liveness, err := w.beaconClient.GetValidatorsLiveness(ctx, epoch, indices)
 	if err != nil {
+		w.settleAttestationDuties(epoch, nil)
 		return err
 	}
 
 	livenessMap := duties.ProcessLiveness(liveness)
+	w.prometheusMetrics.MarkUpdated(metrics.SourceLiveness, w.config.Network)
+	w.apiServer.UpdateLiveness(epoch, livenessMap)
+	w.settleAttestationDuties(epoch, livenessMap)
+
+	if w.warmup {
+		w.logger.WithField("epoch", epoch).Debug("Warmup: not recording liveness misses")
+		return nil
+	}
 
-	notLiveCount := 0
-	var notLiveDetails []string
+	w.checkLabelOffline(epoch, livenessMap)
+
+	notLive := events.NewSampler(w.config.LogSampling.MaxExamples)
 
 	for idx, isLive := range livenessMap {
 		if !isLive {
-			notLiveCount++
-			w.watchedValidators.UpdateMetrics(idx, func(wv *validator.WatchedValidator) {
-				wv.MissedAttestations++
-			})
-
-			// Collect details for first 5 non-live validators
-			if notLiveCount <= 5 {
-				if v, ok := w.watchedValidators.Get(idx); ok {
-					primaryLabel := "unknown"
-					for _, label := range v.Labels {
-						if !strings.HasPrefix(label, "scope:") && !strings.HasPrefix(label, "key:") {
-							primaryLabel = label
-							break
-						}
-					}
-					notLiveDetails = append(notLiveDetails, fmt.Sprintf("%d (%s)", idx, primaryLabel))
-				}
+			if v, ok := w.watchedValidators.Get(idx); ok {
+				label := primaryLabel(v.Labels)
+				w.events.Emit(events.Event{
+					Type:           events.TypeValidatorNotLive,
+					Epoch:          epoch,
+					ValidatorIndex: idx,
+					Pubkey:         v.Data.Pubkey,
+					Label:          label,
+				})
+				notLive.Add(fmt.Sprintf("%d (%s)", w.anonymizer.Index(idx), label))
 			}
 		}
 	}
 
 	// Log liveness summary
+	notLiveCount := notLive.Count()
 	liveCount := len(livenessMap) - notLiveCount
 	logFields := logrus.Fields{
 		"epoch":      epoch,
@@ -714,11 +1565,8 @@ func (w *ValidatorWatcher) processLiveness(ctx context.Context, epoch models.Epo
 		"percentage": fmt.Sprintf("%.1f%%", float64(liveCount)*100/float64(len(livenessMap))),
 	}
 
-	if notLiveCount > 0 && len(notLiveDetails) > 0 {
-		logFields["not_live_validators"] = strings.Join(notLiveDetails, "; ")
-		if notLiveCount > 5 {
-			logFields["more"] = fmt.Sprintf("+%d more", notLiveCount-5)
-		}
+	if notLiveCount > 0 {
+		notLive.AddFields(logFields, "not_live_validators")
 		w.logger.WithFields(logFields).Warn("🔴 Liveness check: some validators not live")
 	} else {
 		w.logger.WithFields(logFields).Info("🟢 Liveness check: all validators live")
@@ -754,6 +1602,7 @@ func (w *ValidatorWatcher) processRewards(ctx context.Context, epoch models.Epoc
 	if err != nil {
 		return err
 	}
+	w.prometheusMetrics.MarkUpdated(metrics.SourceRewards, w.config.Network)
 
 	// Track statistics
 	suboptimalSourceCount := 0
@@ -825,30 +1674,158 @@ func (w *ValidatorWatcher) processRewards(ctx context.Context, epoch models.Epoc
 	return nil
 }
 
-// reloadConfig reloads the configuration
-func (w *ValidatorWatcher) reloadConfig() error {
-	// Re-read config file if path is available
-	// For now, just log
-	w.logger.Debug("Config reload requested (not implemented yet)")
-	return nil
+// applyWatchedKeys replaces the watched key list and reports what changed
+// The watched validator registry picks up the new list on the next epoch refresh,
+// or right away through reconcileWatchedValidators
+func (w *ValidatorWatcher) applyWatchedKeys(source string, keys []models.WatchedKey) *config.WatchlistDiff {
+	diff := config.DiffWatchedKeys(w.config.WatchedKeys, keys)
+	w.setWatchedKeys(keys)
+
+	if diff.IsEmpty() {
+		w.logger.WithField("source", source).Debug("Watched keys unchanged")
+		return diff
+	}
+
+	w.prometheusMetrics.RecordWatchlistChanges(w.config.Network, len(diff.Added), len(diff.Removed), len(diff.Relabeled))
+
+	published := diff
+	if w.anonymizer.Enabled() {
+		published = diff.MapPubkeys(w.anonymizer.Pubkey)
+	}
+	w.events.Emit(events.Event{
+		Type: events.TypeWatchlistChanged,
+		Data: map[string]interface{}{
+			"source":    source,
+			"added":     published.Added,
+			"removed":   published.Removed,
+			"relabeled": published.Relabeled,
+		},
+	})
+
+	added := events.NewSampler(w.config.LogSampling.MaxExamples)
+	for _, wk := range diff.Added {
+		added.Add(w.logPubkey(wk.PublicKey))
+	}
+	removed := events.NewSampler(w.config.LogSampling.MaxExamples)
+	for _, wk := range diff.Removed {
+		removed.Add(w.logPubkey(wk.PublicKey))
+	}
+
+	logFields := logrus.Fields{
+		"source":    source,
+		"added":     len(diff.Added),
+		"removed":   len(diff.Removed),
+		"relabeled": len(diff.Relabeled),
+		"total":     len(keys),
+	}
+	if len(added.Examples()) > 0 {
+		logFields["added_keys"] = strings.Join(added.Examples(), ", ")
+	}
+	if len(removed.Examples()) > 0 {
+		logFields["removed_keys"] = strings.Join(removed.Examples(), ", ")
+	}
+	w.logger.WithFields(logFields).Info("📝 Watchlist changed")
+
+	return diff
+}
+
+// truncatePubkey shortens a pubkey for readability in logs
+func truncatePubkey(pubkey string) string {
+	if len(pubkey) <= 14 {
+		return pubkey
+	}
+	return pubkey[:14] + "..."
+}
+
+// logPubkey returns how a pubkey appears in logs and alerts: truncated, or its pseudonym in privacy mode
+func (w *ValidatorWatcher) logPubkey(pubkey string) string {
+	if w.anonymizer.Enabled() {
+		return w.anonymizer.Pubkey(pubkey)
+	}
+	return truncatePubkey(pubkey)
+}
+
+// upcomingProposals returns the scheduled proposals of watched validators after a slot
+func (w *ValidatorWatcher) upcomingProposals(slot models.Slot) []api.ProposalDuty {
+	if w.proposerSchedule == nil {
+		return nil
+	}
+
+	var proposals []api.ProposalDuty
+	for _, duty := range w.proposerSchedule.Upcoming(slot + 1) {
+		if v, ok := w.watchedValidators.Get(duty.ValidatorIndex); ok {
+			proposals = append(proposals, api.ProposalDuty{
+				Slot:           duty.Slot,
+				ValidatorIndex: duty.ValidatorIndex,
+				Pubkey:         v.Data.Pubkey,
+				Labels:         v.Labels,
+			})
+		}
+	}
+	return proposals
+}
+
+// updateFutureProposals counts the scheduled proposals of each watched validator in the current and
+// next epoch, from the epoch's first slot on
+func (w *ValidatorWatcher) updateFutureProposals(epoch models.Epoch) {
+	from := w.clock.EpochToSlot(epoch)
+	until := w.clock.EpochToSlot(epoch + 2)
+
+	counts := make(map[models.ValidatorIndex]uint64)
+	var slots []models.Slot
+	for _, duty := range w.proposerSchedule.Upcoming(from) {
+		if duty.Slot >= until {
+			break
+		}
+		if _, ok := w.watchedValidators.Get(duty.ValidatorIndex); ok {
+			counts[duty.ValidatorIndex]++
+			slots = append(slots, duty.Slot)
+		}
+	}
+	w.watchedValidators.SetFutureProposals(counts)
+
+	if len(slots) > 0 {
+		w.logger.WithFields(logrus.Fields{
+			"epoch":     epoch,
+			"proposals": len(slots),
+			"slots":     slots,
+		}).Info("📅 Upcoming block proposals")
+	}
+}
+
+// labelMetrics computes the metrics of the watched validators by label, with the network-wide scope
+func (w *ValidatorWatcher) labelMetrics(watched []*validator.WatchedValidator, epoch models.Epoch) map[string]*metrics.MetricsByLabel {
+	byLabel := w.aggregator.Compute(watched)
+
+	// Add network-wide metrics (to a copy: the aggregator's result is reused while nothing changes)
+	metricsByLabel := make(map[string]*metrics.MetricsByLabel, len(byLabel)+1)
+	for label, m := range byLabel {
+		metricsByLabel[label] = m
+	}
+	metricsByLabel["scope:all-network"] = w.withNetworkBaseline(w.networkMetrics(), epoch)
+	return metricsByLabel
 }
 
 // updateMetrics updates Prometheus metrics
 func (w *ValidatorWatcher) updateMetrics(slot models.Slot, epoch models.Epoch) {
 	// Compute metrics from watched validators
 	watchedVals := w.watchedValidators.GetAll()
-	metricsByLabel := metrics.ComputeMetrics(watchedVals, slot)
+	metricsByLabel := w.labelMetrics(watchedVals, epoch)
 
-	// Add network-wide metrics
-	allVals := w.allValidators.GetAll()
-	networkMetrics := metrics.ComputeNetworkMetrics(allVals)
-	metricsByLabel["scope:all-network"] = networkMetrics
+	// Network-level metrics from the background refreshers (before staleness is applied)
+	w.updateNetworkMetrics()
 
 	// Update Prometheus
 	w.prometheusMetrics.UpdateMetrics(metricsByLabel, slot, epoch, w.config.Network)
-
-	// Fetch and update network-level metrics
-	w.updateNetworkMetrics()
+	w.prometheusMetrics.SetCanaryCounts(w.config.Network, canaryCounts(watchedVals))
+
+	// Publish snapshot to the API
+	w.apiServer.UpdateMetrics(metricsByLabel)
+	w.apiServer.UpdateValidators(watchedVals)
+	w.apiServer.UpdateProposals(w.upcomingProposals(slot))
+	if w.federation != nil {
+		w.apiServer.UpdateFederation(w.federation.Shards())
+	}
 
 	// Log summary
 	if watchedMetrics, ok := metricsByLabel["scope:watched"]; ok {
@@ -857,6 +1834,7 @@ func (w *ValidatorWatcher) updateMetrics(slot models.Slot, epoch models.Epoch) {
 			"missed_attestations": watchedMetrics.MissedAttestations,
 			"proposed_blocks":     watchedMetrics.ProposedBlocks,
 			"missed_blocks":       watchedMetrics.MissedBlocks,
+			"future_proposals":    watchedMetrics.FutureBlockProposals,
 			"consensus_rate":      watchedMetrics.ConsensusRewardsRate,
 		}).Info("Metrics updated")
 	}
@@ -922,6 +1900,43 @@ func (w *ValidatorWatcher) updateMetrics(slot models.Slot, epoch models.Epoch) {
 	}
 }
 
+// primaryLabel returns the first label that is not a scope or key label
+func primaryLabel(labels []string) string {
+	for _, label := range labels {
+		if !strings.HasPrefix(label, "scope:") && !strings.HasPrefix(label, "key:") && label != validator.CanaryLabel {
+			return label
+		}
+	}
+	return "unknown"
+}
+
+// networkScope is the label of network-wide metrics
+var networkScope = []string{"scope:all-network"}
+
+// watchedScopes returns a watched validator's labels without the network-wide scope,
+// which is computed over every validator rather than the watched ones
+func watchedScopes(labels []string) []string {
+	scopes := make([]string, 0, len(labels))
+	for _, label := range labels {
+		if label != networkScope[0] {
+			scopes = append(scopes, label)
+		}
+	}
+	return scopes
+}
+
+// aggregatedScopes returns a watched validator's scopes whose label class is aggregated into metrics
+func (w *ValidatorWatcher) aggregatedScopes(labels []string) []string {
+	scopes := watchedScopes(labels)
+	selected := scopes[:0]
+	for _, scope := range scopes {
+		if w.labelClasses.Aggregates(scope) {
+			selected = append(selected, scope)
+		}
+	}
+	return selected
+}
+
 // getTopOffendingValidators returns the top N validators with most issues for a given label
 func (w *ValidatorWatcher) getTopOffendingValidators(label string, limit int) string {
 	type validatorIssue struct {
@@ -948,10 +1963,7 @@ func (w *ValidatorWatcher) getTopOffendingValidators(label string, limit int) st
 		}
 
 		// Skip validators that are not expected to be attesting
-		// Only include active validators (active_ongoing, active_exiting, active_slashed)
-		if v.Status != models.StatusActiveOngoing &&
-			v.Status != models.StatusActiveExiting &&
-			v.Status != models.StatusActiveSlashed {
+		if !w.owesAttestations(v.Index, v.Status) {
 			continue
 		}
 
@@ -965,7 +1977,7 @@ func (w *ValidatorWatcher) getTopOffendingValidators(label string, limit int) st
 		if v.MissedAttestations > 0 || performance < 90.0 {
 			issues = append(issues, validatorIssue{
 				index:              v.Index,
-				pubkey:             v.Data.Pubkey[:14] + "...", // Truncate for readability
+				pubkey:             w.logPubkey(v.Data.Pubkey),
 				status:             v.Status,
 				missedAttestations: v.MissedAttestations,
 				performance:        performance,
@@ -1020,83 +2032,140 @@ func (w *ValidatorWatcher) startMetricsServer() {
 
 	mux := http.NewServeMux()
 	mux.Handle("/metrics", promhttp.HandlerFor(w.registry, promhttp.HandlerOpts{}))
+	w.apiServer.Register(mux)
 
-	// Health check - always returns 200 OK if server is running
-	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("OK"))
-	})
+	// JSON health reports - 503 when a component they fail on reaches health.fail_on
+	w.health.RegisterReport(mux, "/health", w.liveCriteria)
+	w.health.RegisterReport(mux, "/ready", w.readyCriteria)
 
-	// Readiness check - returns 200 OK only after successful initialization
-	mux.HandleFunc("/ready", func(rw http.ResponseWriter, r *http.Request) {
-		if w.ready {
-			rw.WriteHeader(http.StatusOK)
-			rw.Write([]byte("READY"))
-		} else {
-			rw.WriteHeader(http.StatusServiceUnavailable)
-			rw.Write([]byte("NOT READY"))
-		}
-	})
+	// Kubernetes-style probes with per-subsystem checks
+	w.health.Register(mux)
 
+	if auth := w.config.HTTPServer.BasicAuth; auth.Username != "" && auth.Password == "" {
+		w.logger.Error("http_server.basic_auth has a username but no password - basic auth requests are rejected")
+	}
 	server := &http.Server{
 		Addr:    addr,
-		Handler: mux,
+		Handler: httpserver.Wrap(mux, w.config.HTTPServer),
 	}
 
-	if err := server.ListenAndServe(); err != nil {
+	if err := httpserver.ListenAndServe(server, w.config.HTTPServer); err != nil {
 		w.logger.WithError(err).Error("Metrics server failed")
 	}
 }
 
-// updateNetworkMetrics fetches and updates network-level metrics (price, pending operations)
-func (w *ValidatorWatcher) updateNetworkMetrics() {
-	ctx := context.Background()
-	network := w.config.Network
+// fetchPrice fetches the ETH price for the background price refresher
+func (w *ValidatorWatcher) fetchPrice(ctx context.Context) (float64, error) {
+	if _, updated, ok := w.priceRefresher.Value(); ok && !w.degradation.DueAfter(time.Since(updated), w.config.PriceRefresh.ToDuration()) {
+		w.prometheusMetrics.RecordShedWork(w.config.Network, "price")
+		return 0, refresh.ErrSkipped
+	}
+
+	if ethPrice, ok := w.sharedPrice(ctx); ok {
+		w.prometheusMetrics.MarkUpdated(metrics.SourcePrice, w.config.Network)
+		return ethPrice, nil
+	}
+
+	ethPrice, err := w.priceFetcher.FetchETHPrice(ctx)
+	if err != nil {
+		return 0, err
+	}
+	w.sharePrice(ctx, ethPrice)
+	w.prometheusMetrics.MarkUpdated(metrics.SourcePrice, w.config.Network)
+	return ethPrice, nil
+}
 
-	// Fetch ETH price from Coinbase
-	ethPrice := w.priceFetcher.GetCurrentETHPrice()
+// refreshPendingQueues fetches the pending queues once per epoch and computes flows against the previous epoch
+// Beacon nodes that don't support an endpoint report it as empty
+func (w *ValidatorWatcher) refreshPendingQueues(ctx context.Context, epoch models.Epoch, stateID string) {
+	snapshot := &queues.Snapshot{Epoch: epoch, Stale: make(map[string]bool)}
+
+	// A queue that fails to fetch keeps the previous entries, so it neither empties nor shows
+	// every entry leaving and joining again
+	w.queuesMu.Lock()
+	previous := w.queueSnapshot
+	w.queuesMu.Unlock()
+	if previous == nil {
+		previous = &queues.Snapshot{}
+	}
 
 	// Fetch pending deposits
-	var pendingDepositsCount, pendingDepositsValue float64
-	if deposits, err := w.beaconClient.GetPendingDeposits(ctx, "head"); err == nil {
-		pendingDepositsCount = float64(len(deposits))
-		for _, deposit := range deposits {
-			pendingDepositsValue += float64(deposit.Amount)
-		}
+	if deposits, err := w.beaconClient.GetPendingDeposits(ctx, stateID); err == nil {
+		snapshot.Deposits = deposits
 	} else {
 		w.logger.WithError(err).Debug("Failed to fetch pending deposits")
+		snapshot.Deposits = previous.Deposits
+		snapshot.Stale[queues.QueueDeposits] = true
 	}
 
 	// Fetch pending consolidations
-	var pendingConsolidationsCount float64
-	if consolidations, err := w.beaconClient.GetPendingConsolidations(ctx, "head"); err == nil {
-		pendingConsolidationsCount = float64(len(consolidations))
+	if consolidations, err := w.beaconClient.GetPendingConsolidations(ctx, stateID); err == nil {
+		snapshot.Consolidations = consolidations
 	} else {
 		w.logger.WithError(err).Debug("Failed to fetch pending consolidations")
+		snapshot.Consolidations = previous.Consolidations
+		snapshot.Stale[queues.QueueConsolidations] = true
 	}
 
 	// Fetch pending withdrawals
-	var pendingWithdrawalsCount float64
-	if withdrawals, err := w.beaconClient.GetPendingWithdrawals(ctx, "head"); err == nil {
-		pendingWithdrawalsCount = float64(len(withdrawals))
+	if withdrawals, err := w.beaconClient.GetPendingWithdrawals(ctx, stateID); err == nil {
+		snapshot.Withdrawals = withdrawals
 	} else {
 		w.logger.WithError(err).Debug("Failed to fetch pending withdrawals")
+		snapshot.Withdrawals = previous.Withdrawals
+		snapshot.Stale[queues.QueueWithdrawals] = true
+	}
+
+	if ctx.Err() != nil {
+		return
+	}
+
+	w.queuesMu.Lock()
+	flows := queues.Flows(w.queueSnapshot, snapshot)
+	w.queueSnapshot = snapshot
+	w.queueFlows = flows
+	w.queuesMu.Unlock()
+
+	w.updateQueuePositions(epoch, snapshot.Deposits)
+	w.updateDepositTracking(epoch, snapshot.Deposits)
+
+	w.prometheusMetrics.MarkUpdated(metrics.SourceQueues, w.config.Network)
+
+	fields := logrus.Fields{"epoch": epoch}
+	for _, flow := range flows {
+		fields[flow.Queue] = flow.Size
+		if flow.HasRates {
+			fields[flow.Queue+"_in"] = flow.InflowPerEpoch
+			fields[flow.Queue+"_out"] = flow.OutflowPerEpoch
+		}
+	}
+	w.logger.WithFields(fields).Debug("Updated pending queues")
+}
+
+// updateNetworkMetrics updates network-level metrics (price, pending operations) from the background refreshers
+// Never blocks on external APIs
+func (w *ValidatorWatcher) updateNetworkMetrics() {
+	network := w.config.Network
+
+	ethPrice, _, _ := w.priceRefresher.Value()
+
+	w.queuesMu.Lock()
+	flows := w.queueFlows
+	w.queuesMu.Unlock()
+
+	sizes := make(map[string]queues.Flow, len(flows))
+	for _, flow := range flows {
+		sizes[flow.Queue] = flow
 	}
 
 	// Set network metrics
 	w.prometheusMetrics.SetNetworkMetrics(
 		network,
 		ethPrice,
-		pendingDepositsCount,
-		pendingDepositsValue,
-		pendingConsolidationsCount,
-		pendingWithdrawalsCount,
+		float64(sizes[queues.QueueDeposits].Size),
+		float64(sizes[queues.QueueDeposits].Amount),
+		float64(sizes[queues.QueueConsolidations].Size),
+		float64(sizes[queues.QueueWithdrawals].Size),
 	)
-
-	w.logger.WithFields(logrus.Fields{
-		"eth_price":             ethPrice,
-		"pending_deposits":      pendingDepositsCount,
-		"pending_consolidations": pendingConsolidationsCount,
-		"pending_withdrawals":   pendingWithdrawalsCount,
-	}).Debug("Updated network metrics")
+	w.prometheusMetrics.SetQueueFlows(network, flows)
 }
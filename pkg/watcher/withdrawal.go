@@ -0,0 +1,39 @@
+package watcher
+
+import (
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/duties"
+	"github.com/enriquemanuel/eth-validator-watcher/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// recordWithdrawals adds the withdrawals a block's execution payload swept from watched validators
+// A full withdrawal completes an exit, so it is logged
+func (w *ValidatorWatcher) recordWithdrawals(block *models.Block, slot models.Slot) {
+	payload := block.Message.Body.ExecutionPayload
+	if payload == nil {
+		return
+	}
+
+	epoch := w.clock.SlotToEpoch(slot)
+	for _, withdrawal := range payload.Withdrawals {
+		v, ok := w.watchedValidators.Get(withdrawal.ValidatorIndex)
+		if !ok {
+			continue
+		}
+
+		kind := duties.WithdrawalKind(&v.Validator, epoch)
+		w.prometheusMetrics.RecordWithdrawal(w.config.Network, w.aggregatedScopes(v.Labels), kind, uint64(withdrawal.Amount))
+		if kind != duties.WithdrawalFull || v.Cohort {
+			continue
+		}
+
+		w.logger.WithFields(logrus.Fields{
+			"slot":            slot,
+			"validator_index": w.anonymizer.Index(withdrawal.ValidatorIndex),
+			"pubkey":          w.logPubkey(v.Data.Pubkey),
+			"label":           primaryLabel(v.Labels),
+			"amount_gwei":     withdrawal.Amount,
+			"address":         withdrawal.Address,
+		}).Info("💸 FULL WITHDRAWAL - exit complete")
+	}
+}
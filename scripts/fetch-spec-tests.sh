@@ -0,0 +1,16 @@
+#!/bin/sh
+# Fetches the SSZ bitfield vectors of the Ethereum consensus spec tests into
+# pkg/duties/testdata/spec-tests, where the duties tests pick them up
+set -eu
+
+VERSION="${SPEC_TESTS_VERSION:-v1.5.0}"
+DEST="${1:-pkg/duties/testdata/spec-tests}"
+URL="https://github.com/ethereum/consensus-spec-tests/releases/download/${VERSION}/general.tar.gz"
+
+mkdir -p "$DEST"
+echo "Fetching consensus spec tests ${VERSION}..."
+curl -fsSL "$URL" | tar -xz -C "$DEST" \
+	tests/general/phase0/ssz_generic/bitvector \
+	tests/general/phase0/ssz_generic/bitlist
+echo "$VERSION" > "$DEST/VERSION"
+echo "Spec tests in $DEST"